@@ -0,0 +1,148 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// ops for audit log.
+const (
+	OpCreateAuditEvent = "CreateAuditEvent"
+	OpFindAuditEvents  = "FindAuditEvents"
+)
+
+// AuditAction is the kind of change recorded by an audit event.
+type AuditAction string
+
+const (
+	// AuditCreate is recorded when a resource is created.
+	AuditCreate AuditAction = "create"
+	// AuditUpdate is recorded when a resource is changed.
+	AuditUpdate AuditAction = "update"
+	// AuditDelete is recorded when a resource is deleted.
+	AuditDelete AuditAction = "delete"
+)
+
+// Valid returns an error if a is not a known audit action.
+func (a AuditAction) Valid() error {
+	switch a {
+	case AuditCreate, AuditUpdate, AuditDelete:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  "unknown audit action " + string(a),
+	}
+}
+
+// DefaultAuditRetention is how long audit events are kept by default.
+const DefaultAuditRetention = 30 * 24 * time.Hour
+
+// AuditEvent records a change made to a resource through the API.
+type AuditEvent struct {
+	ID     ID          `json:"id"`
+	Time   time.Time   `json:"time"`
+	Action AuditAction `json:"action"`
+
+	// ResourceType and ResourceID identify the changed resource, and OrgID its organization.
+	// ResourceID is unset if the change is not to a single resource, or its ID is not known.
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID,omitempty"`
+	OrgID        ID           `json:"orgID,omitempty"`
+
+	// UserID is the user who made the change, and AuthorizerKind and AuthorizerID
+	// the authorization or session through which the change was made.
+	// They are unset for changes made by unauthenticated requests, such as the initial setup.
+	UserID         ID     `json:"userID,omitempty"`
+	AuthorizerKind string `json:"authorizerKind,omitempty"`
+	AuthorizerID   ID     `json:"authorizerID,omitempty"`
+
+	// SourceIP is the address of the client which made the request, and Request its method and path.
+	SourceIP string `json:"sourceIP,omitempty"`
+	Request  string `json:"request,omitempty"`
+
+	// Before and After are the JSON representations of the resource before and after the change.
+	// Only After is set for creations and only Before for deletions.
+	// For updates, they only have the top level fields which changed.
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditFilter represents a set of filters that restrict the returned audit events.
+type AuditFilter struct {
+	Action       *AuditAction
+	ResourceType *ResourceType
+	ResourceID   *ID
+	OrgID        *ID
+	UserID       *ID
+
+	// Since and Until restrict the events to those recorded in [Since, Until).
+	Since *time.Time
+	Until *time.Time
+}
+
+// Matches returns true if e passes the filter.
+func (f AuditFilter) Matches(e *AuditEvent) bool {
+	switch {
+	case f.Action != nil && e.Action != *f.Action:
+		return false
+	case f.ResourceType != nil && e.ResourceType != *f.ResourceType:
+		return false
+	case f.ResourceID != nil && e.ResourceID != *f.ResourceID:
+		return false
+	case f.OrgID != nil && e.OrgID != *f.OrgID:
+		return false
+	case f.UserID != nil && e.UserID != *f.UserID:
+		return false
+	case f.Since != nil && e.Time.Before(*f.Since):
+		return false
+	case f.Until != nil && !e.Time.Before(*f.Until):
+		return false
+	}
+	return true
+}
+
+// QueryParams implements PagingFilter.
+func (f AuditFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.Action != nil {
+		qp["action"] = []string{string(*f.Action)}
+	}
+
+	if f.ResourceType != nil {
+		qp["resourceType"] = []string{string(*f.ResourceType)}
+	}
+
+	if f.ResourceID != nil {
+		qp["resourceID"] = []string{f.ResourceID.String()}
+	}
+
+	if f.OrgID != nil {
+		qp["orgID"] = []string{f.OrgID.String()}
+	}
+
+	if f.UserID != nil {
+		qp["userID"] = []string{f.UserID.String()}
+	}
+
+	if f.Since != nil {
+		qp["since"] = []string{f.Since.Format(time.RFC3339Nano)}
+	}
+
+	if f.Until != nil {
+		qp["until"] = []string{f.Until.Format(time.RFC3339Nano)}
+	}
+
+	return qp
+}
+
+// AuditLogService records and retrieves audit events.
+type AuditLogService interface {
+	// CreateAuditEvent records an audit event, and sets its ID, and its time if not set.
+	CreateAuditEvent(ctx context.Context, e *AuditEvent) error
+
+	// FindAuditEvents returns the audit events that match filter, in the order they were recorded,
+	// or the reverse order if opt is descending.
+	FindAuditEvents(ctx context.Context, filter AuditFilter, opt ...FindOptions) ([]*AuditEvent, int, error)
+}
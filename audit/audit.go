@@ -0,0 +1,228 @@
+// Package audit records the changes made to resources through the API in an audit log.
+//
+// The HTTP API puts each request in the context with NewContext,
+// and the services changing resources on behalf of the request call Record.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// Source describes the request on whose behalf changes are made.
+type Source struct {
+	// IP is the address of the client which made the request.
+	IP string
+	// Request is the method and path of the request, such as "POST /api/v2/buckets".
+	Request string
+}
+
+// recorder records the changes made on behalf of a request.
+type recorder struct {
+	log    influxdb.AuditLogService
+	logger *zap.Logger
+	source Source
+
+	mu       sync.Mutex
+	recorded bool
+}
+
+type contextKey struct{}
+
+// NewContext returns a context in which the changes recorded with Record are recorded to log as made on behalf of src.
+func NewContext(ctx context.Context, log influxdb.AuditLogService, logger *zap.Logger, src Source) context.Context {
+	return context.WithValue(ctx, contextKey{}, &recorder{
+		log:    log,
+		logger: logger,
+		source: src,
+	})
+}
+
+func recorderFromContext(ctx context.Context) *recorder {
+	r, _ := ctx.Value(contextKey{}).(*recorder)
+	return r
+}
+
+// Enabled returns true if the changes made in ctx are recorded.
+// Services use it to only look up the state of a resource before a change if the change is recorded.
+func Enabled(ctx context.Context) bool {
+	return recorderFromContext(ctx) != nil
+}
+
+// Recorded returns true if a change was recorded in ctx.
+func Recorded(ctx context.Context) bool {
+	r := recorderFromContext(ctx)
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recorded
+}
+
+// Record records a change to the resource of type rt with the given ID, in the organization orgID.
+// before and after are the resource before and after the change; before is nil for creations and after for deletions.
+// Record does nothing if ctx is not the context of an audited request.
+// Failing to record the change does not undo it, so failures are logged instead of returned.
+//
+// Only the top level redactedFields are left out of the recorded resource.
+// Resources holding secrets in other fields must be recorded with RecordRedacted.
+func Record(ctx context.Context, action influxdb.AuditAction, rt influxdb.ResourceType, id, orgID influxdb.ID, before, after interface{}) {
+	RecordRedacted(ctx, action, rt, id, orgID, before, after, nil)
+}
+
+// Redactor returns the representation of a resource to record, without the secrets it holds.
+type Redactor func(resource interface{}) interface{}
+
+// RecordRedacted records a change like Record, but records the representations of before and after returned by redact.
+func RecordRedacted(ctx context.Context, action influxdb.AuditAction, rt influxdb.ResourceType, id, orgID influxdb.ID, before, after interface{}, redact Redactor) {
+	r := recorderFromContext(ctx)
+	if r == nil {
+		return
+	}
+
+	if redact != nil {
+		if before != nil {
+			before = redact(before)
+		}
+		if after != nil {
+			after = redact(after)
+		}
+	}
+
+	e := r.newEvent(ctx, action, rt, id, orgID)
+	var err error
+	if e.Before, e.After, err = Diff(before, after); err != nil {
+		r.logger.Info("failed to encode audited change", zap.String("resourceType", string(rt)), zap.Error(err))
+	}
+	r.record(ctx, e)
+}
+
+// RecordRequest records a change to the resource of type rt with the given ID, unless a change was already recorded in ctx.
+// It is used for requests which change resources without calling Record, without knowing what changed.
+func RecordRequest(ctx context.Context, action influxdb.AuditAction, rt influxdb.ResourceType, id influxdb.ID) {
+	r := recorderFromContext(ctx)
+	if r == nil || Recorded(ctx) {
+		return
+	}
+
+	r.record(ctx, r.newEvent(ctx, action, rt, id, 0))
+}
+
+func (r *recorder) newEvent(ctx context.Context, action influxdb.AuditAction, rt influxdb.ResourceType, id, orgID influxdb.ID) *influxdb.AuditEvent {
+	e := &influxdb.AuditEvent{
+		Action:       action,
+		ResourceType: rt,
+		ResourceID:   id,
+		OrgID:        orgID,
+		SourceIP:     r.source.IP,
+		Request:      r.source.Request,
+	}
+
+	if a, err := icontext.GetAuthorizer(ctx); err == nil {
+		e.UserID = a.GetUserID()
+		e.AuthorizerKind = a.Kind()
+		e.AuthorizerID = a.Identifier()
+	}
+	return e
+}
+
+func (r *recorder) record(ctx context.Context, e *influxdb.AuditEvent) {
+	r.mu.Lock()
+	r.recorded = true
+	r.mu.Unlock()
+
+	if err := r.log.CreateAuditEvent(ctx, e); err != nil {
+		r.logger.Error("failed to record audit event",
+			zap.String("action", string(e.Action)),
+			zap.String("resourceType", string(e.ResourceType)),
+			zap.String("resourceID", e.ResourceID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// redactedFields are the top level fields of resources which are never recorded, as they hold secrets.
+var redactedFields = []string{"token", "previousToken", "password"}
+
+// Diff returns the JSON representations of before and after, without the fields which hold secrets.
+// If both are JSON objects, only the top level fields which differ are kept.
+// A nil value is returned as nil.
+func Diff(before, after interface{}) (json.RawMessage, json.RawMessage, error) {
+	b, err := encodeFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := encodeFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if b.fields != nil && a.fields != nil {
+		for k, v := range b.fields {
+			if w, ok := a.fields[k]; ok && bytes.Equal(v, w) {
+				delete(b.fields, k)
+				delete(a.fields, k)
+			}
+		}
+	}
+
+	bj, err := b.encode()
+	if err != nil {
+		return nil, nil, err
+	}
+	aj, err := a.encode()
+	if err != nil {
+		return nil, nil, err
+	}
+	return bj, aj, nil
+}
+
+// encodedValue is the JSON representation of a value, with its top level fields split out if it is an object.
+type encodedValue struct {
+	raw    json.RawMessage
+	fields map[string]json.RawMessage
+}
+
+func encodeFields(v interface{}) (*encodedValue, error) {
+	if v == nil {
+		return &encodedValue{}, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if string(raw) == "null" {
+		return &encodedValue{}, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object.
+		return &encodedValue{raw: raw}, nil
+	}
+	for _, k := range redactedFields {
+		delete(fields, k)
+	}
+	return &encodedValue{fields: fields}, nil
+}
+
+func (v *encodedValue) encode() (json.RawMessage, error) {
+	if v.fields == nil {
+		return v.raw, nil
+	}
+
+	b, err := json.Marshal(v.fields)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
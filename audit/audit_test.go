@@ -0,0 +1,139 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestDiff(t *testing.T) {
+	type resource struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Token       string `json:"token,omitempty"`
+	}
+
+	tests := []struct {
+		name       string
+		before     interface{}
+		after      interface{}
+		wantBefore string
+		wantAfter  string
+	}{
+		{
+			name:      "creation",
+			after:     &resource{Name: "a", Description: "d"},
+			wantAfter: `{"description":"d","name":"a"}`,
+		},
+		{
+			name:       "deletion",
+			before:     &resource{Name: "a"},
+			wantBefore: `{"name":"a"}`,
+		},
+		{
+			name:       "update keeps the changed fields",
+			before:     &resource{Name: "a", Description: "d"},
+			after:      &resource{Name: "a", Description: "e"},
+			wantBefore: `{"description":"d"}`,
+			wantAfter:  `{"description":"e"}`,
+		},
+		{
+			name:      "secrets are redacted",
+			before:    (*resource)(nil),
+			after:     &resource{Name: "a", Token: "secret"},
+			wantAfter: `{"name":"a"}`,
+		},
+		{
+			name:       "values which are not objects are kept whole",
+			before:     []string{"k1"},
+			after:      []string{"k1", "k2"},
+			wantBefore: `["k1"]`,
+			wantAfter:  `["k1","k2"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after, err := audit.Diff(tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(before); got != tt.wantBefore {
+				t.Errorf("unexpected before; got %s, want %s", got, tt.wantBefore)
+			}
+			if got := string(after); got != tt.wantAfter {
+				t.Errorf("unexpected after; got %s, want %s", got, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var events []*influxdb.AuditEvent
+	log := mock.NewAuditLogService()
+	log.CreateAuditEventFn = func(ctx context.Context, e *influxdb.AuditEvent) error {
+		events = append(events, e)
+		return nil
+	}
+
+	a := &influxdb.Authorization{ID: 1, UserID: 2}
+	ctx := icontext.SetAuthorizer(context.Background(), a)
+
+	// Nothing is recorded outside of an audited request.
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.BucketsResourceType, 3, 4, nil, map[string]string{"name": "b"})
+	if len(events) != 0 {
+		t.Fatalf("expected no events outside of an audited request, got %d", len(events))
+	}
+	if audit.Enabled(ctx) {
+		t.Fatal("expected recording to be disabled outside of an audited request")
+	}
+
+	ctx = audit.NewContext(ctx, log, zap.NewNop(), audit.Source{IP: "10.0.0.1", Request: "POST /api/v2/buckets"})
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.BucketsResourceType, 3, 4, nil, map[string]string{"name": "b"})
+	// The change was already recorded, so the request itself is not.
+	audit.RecordRequest(ctx, influxdb.AuditCreate, influxdb.BucketsResourceType, 3)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+	if e.Action != influxdb.AuditCreate || e.ResourceType != influxdb.BucketsResourceType || e.ResourceID != 3 || e.OrgID != 4 {
+		t.Errorf("unexpected resource in event %+v", e)
+	}
+	if e.UserID != 2 || e.AuthorizerKind != influxdb.AuthorizationKind || e.AuthorizerID != 1 {
+		t.Errorf("unexpected authorizer in event %+v", e)
+	}
+	if e.SourceIP != "10.0.0.1" || e.Request != "POST /api/v2/buckets" {
+		t.Errorf("unexpected source in event %+v", e)
+	}
+	if got, want := string(e.After), `{"name":"b"}`; got != want {
+		t.Errorf("unexpected after; got %s, want %s", got, want)
+	}
+}
+
+func TestRecordRequest(t *testing.T) {
+	var events []*influxdb.AuditEvent
+	log := mock.NewAuditLogService()
+	log.CreateAuditEventFn = func(ctx context.Context, e *influxdb.AuditEvent) error {
+		events = append(events, e)
+		return nil
+	}
+
+	ctx := audit.NewContext(context.Background(), log, zap.NewNop(), audit.Source{IP: "10.0.0.1", Request: "POST /api/v2/setup"})
+	audit.RecordRequest(ctx, influxdb.AuditCreate, influxdb.ResourceType("setup"), 0)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if e := events[0]; e.ResourceType != "setup" || e.UserID.Valid() || e.Before != nil || e.After != nil {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if !audit.Recorded(ctx) {
+		t.Error("expected the request to be recorded")
+	}
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// Measurement is the measurement of the points mirroring audit events.
+const Measurement = "audit"
+
+// PointsWriter writes points to the storage engine.
+type PointsWriter interface {
+	WritePoints(ctx context.Context, points []models.Point) error
+}
+
+// MirrorService is an AuditLogService which also writes the audit events it records as points to a bucket,
+// so that they can be queried and alerted on like any other data.
+//
+// The events of every organization are written to the same bucket, so anyone who can read it can read the
+// events of every organization. Its organization should only have the operators of the instance as members.
+type MirrorService struct {
+	influxdb.AuditLogService
+
+	Logger        *zap.Logger
+	BucketService influxdb.BucketService
+	PointsWriter  PointsWriter
+
+	// BucketID is the bucket the points are written to.
+	BucketID influxdb.ID
+}
+
+var _ influxdb.AuditLogService = (*MirrorService)(nil)
+
+// NewMirrorService returns a MirrorService which records audit events to s and writes them as points to the bucket with ID bucketID.
+func NewMirrorService(s influxdb.AuditLogService, bs influxdb.BucketService, pw PointsWriter, bucketID influxdb.ID) *MirrorService {
+	return &MirrorService{
+		AuditLogService: s,
+		Logger:          zap.NewNop(),
+		BucketService:   bs,
+		PointsWriter:    pw,
+		BucketID:        bucketID,
+	}
+}
+
+// CreateAuditEvent records e, and then writes it as a point.
+// The event is recorded even if writing the point fails, which is only logged.
+func (s *MirrorService) CreateAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	if err := s.AuditLogService.CreateAuditEvent(ctx, e); err != nil {
+		return err
+	}
+
+	if err := s.writePoint(ctx, e); err != nil {
+		s.Logger.Info("failed to mirror audit event", zap.String("bucketID", s.BucketID.String()), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *MirrorService) writePoint(ctx context.Context, e *influxdb.AuditEvent) error {
+	b, err := s.BucketService.FindBucketByID(ctx, s.BucketID)
+	if err != nil {
+		return err
+	}
+
+	p, err := NewPoint(e)
+	if err != nil {
+		return err
+	}
+
+	exploded, err := tsdb.ExplodePoints(b.OrganizationID, b.ID, []models.Point{p})
+	if err != nil {
+		return err
+	}
+	return s.PointsWriter.WritePoints(ctx, exploded)
+}
+
+// NewPoint returns the point mirroring e.
+// Its tags are the action and the resource type, which have few distinct values; everything else is a field.
+func NewPoint(e *influxdb.AuditEvent) (models.Point, error) {
+	tags := models.NewTags(map[string]string{
+		"action":       string(e.Action),
+		"resourceType": string(e.ResourceType),
+	})
+
+	fields := models.Fields{
+		"id": e.ID.String(),
+	}
+	for k, id := range map[string]influxdb.ID{
+		"resourceID":   e.ResourceID,
+		"orgID":        e.OrgID,
+		"userID":       e.UserID,
+		"authorizerID": e.AuthorizerID,
+	} {
+		if id.Valid() {
+			fields[k] = id.String()
+		}
+	}
+	for k, v := range map[string]string{
+		"authorizerKind": e.AuthorizerKind,
+		"sourceIP":       e.SourceIP,
+		"request":        e.Request,
+		"before":         string(e.Before),
+		"after":          string(e.After),
+	} {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+
+	return models.NewPoint(Measurement, tags, fields, e.Time)
+}
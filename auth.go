@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
@@ -14,8 +15,19 @@ var (
 		Msg:  "unable to create token",
 		Code: EInvalid,
 	}
+
+	// ErrAuthorizationExpired is returned when the token of an expired authorization is used.
+	ErrAuthorizationExpired = &Error{
+		Msg:  "authorization has expired",
+		Code: EUnauthorized,
+	}
 )
 
+// LastUsedResolution is how often the time an authorization was last used at is updated.
+// Authenticating a request with a token only records the time if the recorded one is older than that,
+// to avoid a write for every request.
+const LastUsedResolution = time.Minute
+
 // Authorization is an authorization. 🎉
 type Authorization struct {
 	ID          ID           `json:"id"`
@@ -25,6 +37,23 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+
+	// CreatedAt is the time the authorization was created, and CreatedBy the user who created it.
+	// Both are unset for authorizations created before they were recorded.
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy ID        `json:"createdBy,omitempty"`
+
+	// ExpiresAt is the time from which the authorization's token is no longer accepted, if set.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// LastUsedAt is the time the authorization's token was last used to authenticate a request, if it was used.
+	// It may be behind by up to LastUsedResolution.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+
+	// PreviousToken is the token the authorization had before it was last rotated,
+	// which is still accepted until PreviousTokenExpiresAt.
+	PreviousToken          string     `json:"previousToken,omitempty"`
+	PreviousTokenExpiresAt *time.Time `json:"previousTokenExpiresAt,omitempty"`
 }
 
 // Valid ensures that the authorization is valid.
@@ -61,6 +90,43 @@ func (a *Authorization) IsActive() bool {
 	return a.Status == Active
 }
 
+// IsExpired returns true if the authorization has an expiry time which is not after now.
+func (a *Authorization) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// AcceptsToken returns true if t is the authorization's token,
+// or its previous token and the grace period after the last rotation did not end before now.
+func (a *Authorization) AcceptsToken(t string, now time.Time) bool {
+	if t == a.Token {
+		return true
+	}
+	return t == a.PreviousToken && a.PreviousTokenExpiresAt != nil && now.Before(*a.PreviousTokenExpiresAt)
+}
+
+// UnusedSince returns true if the authorization's token was not used since t.
+// An authorization which was never used counts as used when it was created.
+func (a *Authorization) UnusedSince(t time.Time) bool {
+	last := a.CreatedAt
+	if a.LastUsedAt != nil {
+		last = *a.LastUsedAt
+	}
+	return last.Before(t)
+}
+
+// Rotate replaces the authorization's token with token.
+// The current token is still accepted until gracePeriod after now; if gracePeriod is 0, it is rejected right away.
+func (a *Authorization) Rotate(token string, now time.Time, gracePeriod time.Duration) {
+	a.PreviousToken = ""
+	a.PreviousTokenExpiresAt = nil
+	if gracePeriod > 0 {
+		end := now.Add(gracePeriod)
+		a.PreviousToken = a.Token
+		a.PreviousTokenExpiresAt = &end
+	}
+	a.Token = token
+}
+
 // GetUserID returns the user id.
 func (a *Authorization) GetUserID() ID {
 	return a.UserID
@@ -80,6 +146,8 @@ const (
 	OpCreateAuthorization      = "CreateAuthorization"
 	OpSetAuthorizationStatus   = "SetAuthorizationStatus"
 	OpDeleteAuthorization      = "DeleteAuthorization"
+	OpRotateAuthorization      = "RotateAuthorization"
+	OpSetAuthorizationLastUsed = "SetAuthorizationLastUsed"
 )
 
 // AuthorizationService represents a service for managing authorization data.
@@ -103,6 +171,13 @@ type AuthorizationService interface {
 
 	// Removes a authorization by token.
 	DeleteAuthorization(ctx context.Context, id ID) error
+
+	// RotateAuthorization replaces the token of an authorization with a new one, and returns the updated authorization.
+	// The current token is still accepted for gracePeriod, so that its users can switch to the new one.
+	RotateAuthorization(ctx context.Context, id ID, gracePeriod time.Duration) (*Authorization, error)
+
+	// SetAuthorizationLastUsed records the time the authorization's token was last used at.
+	SetAuthorizationLastUsed(ctx context.Context, id ID, t time.Time) error
 }
 
 // AuthorizationFilter represents a set of filter that restrict the returned results.
@@ -112,4 +187,7 @@ type AuthorizationFilter struct {
 
 	UserID *ID
 	User   *string
+
+	// UnusedSince restricts the results to the authorizations whose token was not used since the time, if set.
+	UnusedSince *time.Time
 }
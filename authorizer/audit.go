@@ -0,0 +1,62 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AuditLogService = (*AuditLogService)(nil)
+
+// AuditLogService wraps a influxdb.AuditLogService and authorizes actions
+// against it appropriately. The audit events of an organization may be read
+// by whoever may read the organization, and the whole audit log, which also
+// has the events of resources outside of organizations, by whoever may read
+// every organization.
+type AuditLogService struct {
+	s influxdb.AuditLogService
+}
+
+// NewAuditLogService constructs an instance of an authorizing audit log service.
+func NewAuditLogService(s influxdb.AuditLogService) *AuditLogService {
+	return &AuditLogService{
+		s: s,
+	}
+}
+
+func authorizeReadAllOrgs(ctx context.Context) error {
+	p, err := influxdb.NewGlobalPermission(influxdb.ReadAction, influxdb.OrgsResourceType)
+	if err != nil {
+		return err
+	}
+
+	return IsAllowed(ctx, *p)
+}
+
+// CreateAuditEvent checks to see if the authorizer on context has write access to the global orgs resource.
+func (s *AuditLogService) CreateAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	p, err := influxdb.NewGlobalPermission(influxdb.WriteAction, influxdb.OrgsResourceType)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateAuditEvent(ctx, e)
+}
+
+// FindAuditEvents checks to see if the authorizer on context has read access to the organization of the filter,
+// or to every organization if the filter has none.
+func (s *AuditLogService) FindAuditEvents(ctx context.Context, filter influxdb.AuditFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	if filter.OrgID != nil {
+		if err := authorizeReadOrg(ctx, *filter.OrgID); err != nil {
+			return nil, 0, err
+		}
+	} else if err := authorizeReadAllOrgs(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return s.s.FindAuditEvents(ctx, filter, opt...)
+}
@@ -0,0 +1,160 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestAuditLogService_FindAuditEvents(t *testing.T) {
+	type args struct {
+		permissions []influxdb.Permission
+		filter      influxdb.AuditFilter
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to read the events of an organization",
+			args: args{
+				permissions: []influxdb.Permission{
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+				filter: influxdb.AuditFilter{OrgID: influxdbtesting.IDPtr(10)},
+			},
+		},
+		{
+			name: "unauthorized to read the events of another organization",
+			args: args{
+				permissions: []influxdb.Permission{
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+				filter: influxdb.AuditFilter{OrgID: influxdbtesting.IDPtr(11)},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs/000000000000000b is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+		{
+			name: "authorized to read all events",
+			args: args{
+				permissions: []influxdb.Permission{
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unauthorized to read all events with access to a single organization",
+			args: args{
+				permissions: []influxdb.Permission{
+					{
+						Action: "read",
+						Resource: influxdb.Resource{
+							Type: influxdb.OrgsResourceType,
+							ID:   influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "read:orgs is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewAuditLogService()
+			s := authorizer.NewAuditLogService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
+
+			_, _, err := s.FindAuditEvents(ctx, tt.args.filter)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+func TestAuditLogService_CreateAuditEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name: "authorized to write all orgs",
+			permissions: []influxdb.Permission{
+				{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+					},
+				},
+			},
+		},
+		{
+			name: "unauthorized to write a single org",
+			permissions: []influxdb.Permission{
+				{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type: influxdb.OrgsResourceType,
+						ID:   influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewAuditLogService()
+			s := authorizer.NewAuditLogService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := s.CreateAuditEvent(ctx, &influxdb.AuditEvent{Action: influxdb.AuditCreate})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -3,8 +3,10 @@ package authorizer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.AuthorizationService = (*AuthorizationService)(nil)
@@ -125,7 +127,12 @@ func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *influ
 		return err
 	}
 
-	return s.s.CreateAuthorization(ctx, a)
+	if err := s.s.CreateAuthorization(ctx, a); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.AuthorizationsResourceType, a.ID, a.OrgID, nil, a)
+	return nil
 }
 
 // VerifyPermission ensures that an authorization is allowed all of the appropriate permissions.
@@ -154,7 +161,14 @@ func (s *AuthorizationService) SetAuthorizationStatus(ctx context.Context, id in
 		return err
 	}
 
-	return s.s.SetAuthorizationStatus(ctx, id, st)
+	if err := s.s.SetAuthorizationStatus(ctx, id, st); err != nil {
+		return err
+	}
+
+	updated := *a
+	updated.Status = st
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.AuthorizationsResourceType, id, a.OrgID, a, &updated)
+	return nil
 }
 
 // DeleteAuthorization checks to see if the authorizer on context has write access to the authorization provided.
@@ -168,5 +182,44 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id influ
 		return err
 	}
 
-	return s.s.DeleteAuthorization(ctx, id)
+	if err := s.s.DeleteAuthorization(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.AuthorizationsResourceType, id, a.OrgID, a, nil)
+	return nil
+}
+
+// RotateAuthorization checks to see if the authorizer on context has write access to the authorization provided.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	a, err := s.s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteAuthorization(ctx, a.UserID); err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.s.RotateAuthorization(ctx, id, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.AuthorizationsResourceType, id, a.OrgID, a, rotated)
+	return rotated, nil
+}
+
+// SetAuthorizationLastUsed checks to see if the authorizer on context has write access to the authorization provided.
+func (s *AuthorizationService) SetAuthorizationLastUsed(ctx context.Context, id influxdb.ID, t time.Time) error {
+	a, err := s.s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteAuthorization(ctx, a.UserID); err != nil {
+		return err
+	}
+
+	return s.s.SetAuthorizationLastUsed(ctx, id, t)
 }
@@ -0,0 +1,58 @@
+package authorizer
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.BackupService = (*BackupService)(nil)
+
+// BackupService wraps a influxdb.BackupService and authorizes actions
+// against it appropriately.
+type BackupService struct {
+	s influxdb.BackupService
+}
+
+// NewBackupService constructs an instance of an authorizing backup service.
+func NewBackupService(s influxdb.BackupService) *BackupService {
+	return &BackupService{
+		s: s,
+	}
+}
+
+// authorizeBackup checks to see if the authorizer on context can read every
+// resource of every organization, as a backup contains all of them.
+func authorizeBackup(ctx context.Context) error {
+	for _, r := range influxdb.AllResourceTypes {
+		p, err := influxdb.NewGlobalPermission(influxdb.ReadAction, r)
+		if err != nil {
+			return err
+		}
+
+		if err := IsAllowed(ctx, *p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Backup checks to see if the authorizer on context has read access to all resources.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) error {
+	if err := authorizeBackup(ctx); err != nil {
+		return err
+	}
+
+	return s.s.Backup(ctx, w)
+}
+
+// IncrementalBackup checks to see if the authorizer on context has read access to all resources.
+func (s *BackupService) IncrementalBackup(ctx context.Context, since *influxdb.BackupManifest, w io.Writer) error {
+	if err := authorizeBackup(ctx); err != nil {
+		return err
+	}
+
+	return s.s.IncrementalBackup(ctx, since, w)
+}
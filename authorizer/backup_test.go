@@ -0,0 +1,59 @@
+package authorizer_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBackupService_Backup(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name:        "authorized to read and write all resources",
+			permissions: influxdb.OperPermissions(),
+		},
+		{
+			name: "authorized to read all resources of an organization",
+			permissions: []influxdb.Permission{
+				{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.AuthorizationsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "read:authorizations is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewBackupService()
+			m.BackupFn = func(ctx context.Context, w io.Writer) error {
+				return nil
+			}
+			s := authorizer.NewBackupService(m)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.permissions})
+
+			err := s.Backup(ctx, ioutil.Discard)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.BucketService = (*BucketService)(nil)
@@ -118,7 +119,12 @@ func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) er
 		return err
 	}
 
-	return s.s.CreateBucket(ctx, b)
+	if err := s.s.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.BucketsResourceType, b.ID, b.OrganizationID, nil, b)
+	return nil
 }
 
 // UpdateBucket checks to see if the authorizer on context has write access to the bucket provided.
@@ -132,7 +138,13 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 		return nil, err
 	}
 
-	return s.s.UpdateBucket(ctx, id, upd)
+	updated, err := s.s.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.BucketsResourceType, id, b.OrganizationID, b, updated)
+	return updated, nil
 }
 
 // DeleteBucket checks to see if the authorizer on context has write access to the bucket provided.
@@ -146,5 +158,10 @@ func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error
 		return err
 	}
 
-	return s.s.DeleteBucket(ctx, id)
+	if err := s.s.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.BucketsResourceType, id, b.OrganizationID, b, nil)
+	return nil
 }
@@ -0,0 +1,114 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+)
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService wraps a influxdb.BucketSchemaService and authorizes actions
+// against it appropriately. Reading a schema requires read access to its bucket,
+// and modifying a schema requires write access to its bucket.
+type BucketSchemaService struct {
+	s          influxdb.BucketSchemaService
+	orgService OrganizationService
+}
+
+// NewBucketSchemaService constructs an instance of an authorizing bucket schema service.
+func NewBucketSchemaService(orgSvc OrganizationService, s influxdb.BucketSchemaService) *BucketSchemaService {
+	return &BucketSchemaService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// authorizeBucket authorizes action a on the bucket, and returns the ID of its organization.
+func (s *BucketSchemaService) authorizeBucket(ctx context.Context, a influxdb.Action, bucketID influxdb.ID) (influxdb.ID, error) {
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return 0, err
+	}
+
+	if a == influxdb.ReadAction {
+		return orgID, authorizeReadBucket(ctx, orgID, bucketID)
+	}
+	return orgID, authorizeWriteBucket(ctx, orgID, bucketID)
+}
+
+// FindMeasurementSchema checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketSchemaService) FindMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string) (*influxdb.MeasurementSchema, error) {
+	if _, err := s.authorizeBucket(ctx, influxdb.ReadAction, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindMeasurementSchema(ctx, bucketID, name)
+}
+
+// FindMeasurementSchemas checks to see if the authorizer on context has read access to the bucket.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.MeasurementSchema, error) {
+	if _, err := s.authorizeBucket(ctx, influxdb.ReadAction, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindMeasurementSchemas(ctx, bucketID)
+}
+
+// CreateMeasurementSchema checks to see if the authorizer on context has write access to the bucket.
+// Changes to schemas are recorded as updates of their bucket.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	orgID, err := s.authorizeBucket(ctx, influxdb.WriteAction, m.BucketID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.s.CreateMeasurementSchema(ctx, m); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.BucketsResourceType, m.BucketID, orgID, nil, m)
+	return nil
+}
+
+// UpdateMeasurementSchema checks to see if the authorizer on context has write access to the bucket.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	orgID, err := s.authorizeBucket(ctx, influxdb.WriteAction, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var before *influxdb.MeasurementSchema
+	if audit.Enabled(ctx) {
+		before, _ = s.s.FindMeasurementSchema(ctx, bucketID, name)
+	}
+
+	m, err := s.s.UpdateMeasurementSchema(ctx, bucketID, name, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.BucketsResourceType, bucketID, orgID, before, m)
+	return m, nil
+}
+
+// DeleteMeasurementSchema checks to see if the authorizer on context has write access to the bucket.
+func (s *BucketSchemaService) DeleteMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string) error {
+	orgID, err := s.authorizeBucket(ctx, influxdb.WriteAction, bucketID)
+	if err != nil {
+		return err
+	}
+
+	var before *influxdb.MeasurementSchema
+	if audit.Enabled(ctx) {
+		before, _ = s.s.FindMeasurementSchema(ctx, bucketID, name)
+	}
+
+	if err := s.s.DeleteMeasurementSchema(ctx, bucketID, name); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.BucketsResourceType, bucketID, orgID, before, nil)
+	return nil
+}
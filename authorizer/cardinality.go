@@ -0,0 +1,38 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.CardinalityService = (*CardinalityService)(nil)
+
+// CardinalityService wraps a influxdb.CardinalityService and authorizes actions
+// against it appropriately.
+type CardinalityService struct {
+	s          influxdb.CardinalityService
+	orgService OrganizationService
+}
+
+// NewCardinalityService constructs an instance of an authorizing cardinality service.
+func NewCardinalityService(orgSvc OrganizationService, s influxdb.CardinalityService) *CardinalityService {
+	return &CardinalityService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// FindBucketCardinality checks to see if the authorizer on context has read access to the bucket.
+func (s *CardinalityService) FindBucketCardinality(ctx context.Context, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindBucketCardinality(ctx, bucketID, opts)
+}
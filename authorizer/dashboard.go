@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.DashboardService = (*DashboardService)(nil)
@@ -104,7 +105,12 @@ func (s *DashboardService) CreateDashboard(ctx context.Context, b *influxdb.Dash
 		return err
 	}
 
-	return s.s.CreateDashboard(ctx, b)
+	if err := s.s.CreateDashboard(ctx, b); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.DashboardsResourceType, b.ID, b.OrganizationID, nil, b)
+	return nil
 }
 
 // UpdateDashboard checks to see if the authorizer on context has write access to the dashboard provided.
@@ -118,7 +124,13 @@ func (s *DashboardService) UpdateDashboard(ctx context.Context, id influxdb.ID,
 		return nil, err
 	}
 
-	return s.s.UpdateDashboard(ctx, id, upd)
+	updated, err := s.s.UpdateDashboard(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.DashboardsResourceType, id, b.OrganizationID, b, updated)
+	return updated, nil
 }
 
 // DeleteDashboard checks to see if the authorizer on context has write access to the dashboard provided.
@@ -132,7 +144,12 @@ func (s *DashboardService) DeleteDashboard(ctx context.Context, id influxdb.ID)
 		return err
 	}
 
-	return s.s.DeleteDashboard(ctx, id)
+	if err := s.s.DeleteDashboard(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.DashboardsResourceType, id, b.OrganizationID, b, nil)
+	return nil
 }
 
 func (s *DashboardService) AddDashboardCell(ctx context.Context, id influxdb.ID, c *influxdb.Cell, opts influxdb.AddDashboardCellOptions) error {
@@ -145,7 +162,12 @@ func (s *DashboardService) AddDashboardCell(ctx context.Context, id influxdb.ID,
 		return err
 	}
 
-	return s.s.AddDashboardCell(ctx, id, c, opts)
+	if err := s.s.AddDashboardCell(ctx, id, c, opts); err != nil {
+		return err
+	}
+
+	s.recordCellsChange(ctx, b)
+	return nil
 }
 
 func (s *DashboardService) RemoveDashboardCell(ctx context.Context, dashboardID influxdb.ID, cellID influxdb.ID) error {
@@ -158,7 +180,12 @@ func (s *DashboardService) RemoveDashboardCell(ctx context.Context, dashboardID
 		return err
 	}
 
-	return s.s.RemoveDashboardCell(ctx, dashboardID, cellID)
+	if err := s.s.RemoveDashboardCell(ctx, dashboardID, cellID); err != nil {
+		return err
+	}
+
+	s.recordCellsChange(ctx, b)
+	return nil
 }
 
 func (s *DashboardService) UpdateDashboardCell(ctx context.Context, dashboardID influxdb.ID, cellID influxdb.ID, upd influxdb.CellUpdate) (*influxdb.Cell, error) {
@@ -171,7 +198,13 @@ func (s *DashboardService) UpdateDashboardCell(ctx context.Context, dashboardID
 		return nil, err
 	}
 
-	return s.s.UpdateDashboardCell(ctx, dashboardID, cellID, upd)
+	cell, err := s.s.UpdateDashboardCell(ctx, dashboardID, cellID, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordCellsChange(ctx, b)
+	return cell, nil
 }
 
 func (s *DashboardService) GetDashboardCellView(ctx context.Context, dashboardID influxdb.ID, cellID influxdb.ID) (*influxdb.View, error) {
@@ -197,7 +230,18 @@ func (s *DashboardService) UpdateDashboardCellView(ctx context.Context, dashboar
 		return nil, err
 	}
 
-	return s.s.UpdateDashboardCellView(ctx, dashboardID, cellID, upd)
+	var before *influxdb.View
+	if audit.Enabled(ctx) {
+		before, _ = s.s.GetDashboardCellView(ctx, dashboardID, cellID)
+	}
+
+	v, err := s.s.UpdateDashboardCellView(ctx, dashboardID, cellID, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.DashboardsResourceType, dashboardID, b.OrganizationID, before, v)
+	return v, nil
 }
 
 func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id influxdb.ID, c []*influxdb.Cell) error {
@@ -210,5 +254,23 @@ func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id influxd
 		return err
 	}
 
-	return s.s.ReplaceDashboardCells(ctx, id, c)
+	if err := s.s.ReplaceDashboardCells(ctx, id, c); err != nil {
+		return err
+	}
+
+	s.recordCellsChange(ctx, b)
+	return nil
+}
+
+// recordCellsChange records a change to the cells of dashboard before as an update of the dashboard.
+func (s *DashboardService) recordCellsChange(ctx context.Context, before *influxdb.Dashboard) {
+	if !audit.Enabled(ctx) {
+		return
+	}
+
+	after, err := s.s.FindDashboardByID(ctx, before.ID)
+	if err != nil {
+		after = nil
+	}
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.DashboardsResourceType, before.ID, before.OrganizationID, before, after)
 }
@@ -0,0 +1,39 @@
+package authorizer
+
+import (
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ExportService = (*ExportService)(nil)
+
+// ExportService wraps a influxdb.ExportService and authorizes actions
+// against it appropriately.
+type ExportService struct {
+	s          influxdb.ExportService
+	orgService OrganizationService
+}
+
+// NewExportService constructs an instance of an authorizing export service.
+func NewExportService(orgSvc OrganizationService, s influxdb.ExportService) *ExportService {
+	return &ExportService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// ExportBucket checks to see if the authorizer on context has read access to the bucket.
+func (s *ExportService) ExportBucket(ctx context.Context, filter influxdb.ExportFilter, w io.Writer) error {
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, influxdb.BucketsResourceType, filter.BucketID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeReadBucket(ctx, orgID, filter.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.ExportBucket(ctx, filter, w)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.LabelService = (*LabelService)(nil)
@@ -170,7 +171,12 @@ func (s *LabelService) CreateLabel(ctx context.Context, l *influxdb.Label) error
 		return err
 	}
 
-	return s.s.CreateLabel(ctx, l)
+	if err := s.s.CreateLabel(ctx, l); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.LabelsResourceType, l.ID, 0, nil, l)
+	return nil
 }
 
 // CreateLabelMapping checks to see if the authorizer on context has write access to the label and the resource contained by the label mapping in creation.
@@ -183,12 +189,18 @@ func (s *LabelService) CreateLabelMapping(ctx context.Context, m *influxdb.Label
 		return err
 	}
 
-	return s.s.CreateLabelMapping(ctx, m)
+	if err := s.s.CreateLabelMapping(ctx, m); err != nil {
+		return err
+	}
+
+	// Labeling a resource is recorded as an update of the resource.
+	audit.Record(ctx, influxdb.AuditUpdate, m.ResourceType, m.ResourceID, 0, nil, m)
+	return nil
 }
 
 // UpdateLabel checks to see if the authorizer on context has write access to the label provided.
 func (s *LabelService) UpdateLabel(ctx context.Context, id influxdb.ID, upd influxdb.LabelUpdate) (*influxdb.Label, error) {
-	_, err := s.s.FindLabelByID(ctx, id)
+	l, err := s.s.FindLabelByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -197,12 +209,18 @@ func (s *LabelService) UpdateLabel(ctx context.Context, id influxdb.ID, upd infl
 		return nil, err
 	}
 
-	return s.s.UpdateLabel(ctx, id, upd)
+	updated, err := s.s.UpdateLabel(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.LabelsResourceType, id, 0, l, updated)
+	return updated, nil
 }
 
 // DeleteLabel checks to see if the authorizer on context has write access to the label provided.
 func (s *LabelService) DeleteLabel(ctx context.Context, id influxdb.ID) error {
-	_, err := s.s.FindLabelByID(ctx, id)
+	l, err := s.s.FindLabelByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -211,7 +229,12 @@ func (s *LabelService) DeleteLabel(ctx context.Context, id influxdb.ID) error {
 		return err
 	}
 
-	return s.s.DeleteLabel(ctx, id)
+	if err := s.s.DeleteLabel(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.LabelsResourceType, id, 0, l, nil)
+	return nil
 }
 
 // DeleteLabelMapping checks to see if the authorizer on context has write access to the label and the resource of the label mapping to delete.
@@ -229,5 +252,10 @@ func (s *LabelService) DeleteLabelMapping(ctx context.Context, m *influxdb.Label
 		return err
 	}
 
-	return s.s.DeleteLabelMapping(ctx, m)
+	if err := s.s.DeleteLabelMapping(ctx, m); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, m.ResourceType, m.ResourceID, 0, m, nil)
+	return nil
 }
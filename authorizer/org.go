@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.OrganizationService = (*OrgService)(nil)
@@ -121,7 +122,12 @@ func (s *OrgService) CreateOrganization(ctx context.Context, o *influxdb.Organiz
 		return err
 	}
 
-	return s.s.CreateOrganization(ctx, o)
+	if err := s.s.CreateOrganization(ctx, o); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.OrgsResourceType, o.ID, o.ID, nil, o)
+	return nil
 }
 
 // UpdateOrganization checks to see if the authorizer on context has write access to the organization provided.
@@ -130,7 +136,18 @@ func (s *OrgService) UpdateOrganization(ctx context.Context, id influxdb.ID, upd
 		return nil, err
 	}
 
-	return s.s.UpdateOrganization(ctx, id, upd)
+	var before *influxdb.Organization
+	if audit.Enabled(ctx) {
+		before, _ = s.s.FindOrganizationByID(ctx, id)
+	}
+
+	o, err := s.s.UpdateOrganization(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.OrgsResourceType, id, id, before, o)
+	return o, nil
 }
 
 // DeleteOrganization checks to see if the authorizer on context has write access to the organization provided.
@@ -139,5 +156,15 @@ func (s *OrgService) DeleteOrganization(ctx context.Context, id influxdb.ID) err
 		return err
 	}
 
-	return s.s.DeleteOrganization(ctx, id)
+	var before *influxdb.Organization
+	if audit.Enabled(ctx) {
+		before, _ = s.s.FindOrganizationByID(ctx, id)
+	}
+
+	if err := s.s.DeleteOrganization(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.OrgsResourceType, id, id, before, nil)
+	return nil
 }
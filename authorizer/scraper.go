@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.ScraperTargetStoreService = (*ScraperTargetStoreService)(nil)
@@ -106,7 +107,12 @@ func (s *ScraperTargetStoreService) AddTarget(ctx context.Context, st *influxdb.
 		return err
 	}
 
-	return s.s.AddTarget(ctx, st, userID)
+	if err := s.s.AddTarget(ctx, st, userID); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.ScraperResourceType, st.ID, st.OrgID, nil, st)
+	return nil
 }
 
 // UpdateTarget checks to see if the authorizer on context has write access to the scraper target provided.
@@ -120,7 +126,13 @@ func (s *ScraperTargetStoreService) UpdateTarget(ctx context.Context, upd *influ
 		return nil, err
 	}
 
-	return s.s.UpdateTarget(ctx, upd, userID)
+	updated, err := s.s.UpdateTarget(ctx, upd, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.ScraperResourceType, upd.ID, st.OrgID, st, updated)
+	return updated, nil
 }
 
 // RemoveTarget checks to see if the authorizer on context has write access to the scraper target provided.
@@ -134,5 +146,10 @@ func (s *ScraperTargetStoreService) RemoveTarget(ctx context.Context, id influxd
 		return err
 	}
 
-	return s.s.RemoveTarget(ctx, id)
+	if err := s.s.RemoveTarget(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.ScraperResourceType, id, st.OrgID, st, nil)
+	return nil
 }
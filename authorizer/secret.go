@@ -2,8 +2,10 @@ package authorizer
 
 import (
 	"context"
+	"sort"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.SecretService = (*SecretService)(nil)
@@ -90,6 +92,7 @@ func (s *SecretService) PutSecret(ctx context.Context, orgID influxdb.ID, key st
 		return err
 	}
 
+	recordSecretsChange(ctx, influxdb.AuditUpdate, orgID, key)
 	return nil
 }
 
@@ -110,6 +113,7 @@ func (s *SecretService) PutSecrets(ctx context.Context, orgID influxdb.ID, m map
 		return err
 	}
 
+	recordSecretsChange(ctx, influxdb.AuditUpdate, orgID, secretKeys(m)...)
 	return nil
 }
 
@@ -124,6 +128,7 @@ func (s *SecretService) PatchSecrets(ctx context.Context, orgID influxdb.ID, m m
 		return err
 	}
 
+	recordSecretsChange(ctx, influxdb.AuditUpdate, orgID, secretKeys(m)...)
 	return nil
 }
 
@@ -138,5 +143,26 @@ func (s *SecretService) DeleteSecret(ctx context.Context, orgID influxdb.ID, key
 		return err
 	}
 
+	recordSecretsChange(ctx, influxdb.AuditDelete, orgID, keys...)
 	return nil
 }
+
+// recordSecretsChange records a change to the secrets of an organization.
+// Only the keys of the secrets are recorded, never their values.
+func recordSecretsChange(ctx context.Context, action influxdb.AuditAction, orgID influxdb.ID, keys ...string) {
+	change := map[string][]string{"keys": keys}
+	if action == influxdb.AuditDelete {
+		audit.Record(ctx, action, influxdb.SecretsResourceType, 0, orgID, change, nil)
+		return
+	}
+	audit.Record(ctx, action, influxdb.SecretsResourceType, 0, orgID, nil, change)
+}
+
+func secretKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 	"github.com/influxdata/influxdb/bolt"
 )
 
@@ -125,7 +126,12 @@ func (s *SourceService) CreateSource(ctx context.Context, src *influxdb.Source)
 		return err
 	}
 
-	return s.s.CreateSource(ctx, src)
+	if err := s.s.CreateSource(ctx, src); err != nil {
+		return err
+	}
+
+	audit.RecordRedacted(ctx, influxdb.AuditCreate, influxdb.SourcesResourceType, src.ID, src.OrganizationID, nil, src, redactSource)
+	return nil
 }
 
 // UpdateSource checks to see if the authorizer on context has write access to the source provided.
@@ -139,7 +145,13 @@ func (s *SourceService) UpdateSource(ctx context.Context, id influxdb.ID, upd in
 		return nil, err
 	}
 
-	return s.s.UpdateSource(ctx, id, upd)
+	updated, err := s.s.UpdateSource(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.RecordRedacted(ctx, influxdb.AuditUpdate, influxdb.SourcesResourceType, id, src.OrganizationID, src, updated, redactSource)
+	return updated, nil
 }
 
 // DeleteSource checks to see if the authorizer on context has write access to the source provided.
@@ -153,5 +165,24 @@ func (s *SourceService) DeleteSource(ctx context.Context, id influxdb.ID) error
 		return err
 	}
 
-	return s.s.DeleteSource(ctx, id)
+	if err := s.s.DeleteSource(ctx, id); err != nil {
+		return err
+	}
+
+	audit.RecordRedacted(ctx, influxdb.AuditDelete, influxdb.SourcesResourceType, id, m.OrganizationID, m, nil, redactSource)
+	return nil
+}
+
+// redactSource returns a copy of a source to record in the audit log, without the credentials it connects with.
+func redactSource(v interface{}) interface{} {
+	src, ok := v.(*influxdb.Source)
+	if !ok {
+		return v
+	}
+
+	redacted := *src
+	redacted.Token = ""
+	redacted.Password = ""
+	redacted.SharedSecret = ""
+	return &redacted
 }
@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 	"github.com/influxdata/influxdb/authorizer"
 	influxdbcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/mock"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap"
 )
 
 var sourceCmpOptions = cmp.Options{
@@ -625,3 +628,62 @@ func TestSourceService_CreateSource(t *testing.T) {
 		})
 	}
 }
+
+func TestSourceService_UpdateSource_AuditRedactsCredentials(t *testing.T) {
+	m := &mock.SourceService{
+		FindSourceByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Source, error) {
+			return &influxdb.Source{
+				ID:             1,
+				OrganizationID: 10,
+				Name:           "a",
+				SourceFields:   influxdb.SourceFields{Token: "old-token"},
+				V1SourceFields: influxdb.V1SourceFields{Password: "old-password", SharedSecret: "old-secret"},
+			}, nil
+		},
+		UpdateSourceFn: func(ctx context.Context, id influxdb.ID, upd influxdb.SourceUpdate) (*influxdb.Source, error) {
+			return &influxdb.Source{
+				ID:             1,
+				OrganizationID: 10,
+				Name:           "b",
+				SourceFields:   influxdb.SourceFields{Token: "new-token"},
+				V1SourceFields: influxdb.V1SourceFields{Password: "new-password", SharedSecret: "new-secret"},
+			}, nil
+		},
+	}
+	s := authorizer.NewSourceService(m)
+
+	var events []*influxdb.AuditEvent
+	log := mock.NewAuditLogService()
+	log.CreateAuditEventFn = func(ctx context.Context, e *influxdb.AuditEvent) error {
+		events = append(events, e)
+		return nil
+	}
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type:  influxdb.SourcesResourceType,
+				OrgID: influxdbtesting.IDPtr(10),
+			},
+		},
+	}})
+	ctx = audit.NewContext(ctx, log, zap.NewNop(), audit.Source{Request: "PATCH /api/v2/sources/0000000000000001"})
+
+	if _, err := s.UpdateSource(ctx, 1, influxdb.SourceUpdate{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	before, after := string(events[0].Before), string(events[0].After)
+	if before != `{"name":"a"}` || after != `{"name":"b"}` {
+		t.Errorf("expected only the name to be recorded, got %s and %s", before, after)
+	}
+	for _, secret := range []string{"token", "password", "secret"} {
+		if strings.Contains(before, secret) || strings.Contains(after, secret) {
+			t.Errorf("expected %s not to be recorded, got %s and %s", secret, before, after)
+		}
+	}
+}
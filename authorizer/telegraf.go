@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.TelegrafConfigStore = (*TelegrafConfigService)(nil)
@@ -106,7 +107,12 @@ func (s *TelegrafConfigService) CreateTelegrafConfig(ctx context.Context, tc *in
 		return err
 	}
 
-	return s.s.CreateTelegrafConfig(ctx, tc, userID)
+	if err := s.s.CreateTelegrafConfig(ctx, tc, userID); err != nil {
+		return err
+	}
+
+	audit.RecordRedacted(ctx, influxdb.AuditCreate, influxdb.TelegrafsResourceType, tc.ID, tc.OrganizationID, nil, tc, redactTelegrafConfig)
+	return nil
 }
 
 // UpdateTelegrafConfig checks to see if the authorizer on context has write access to the telegraf config provided.
@@ -120,7 +126,13 @@ func (s *TelegrafConfigService) UpdateTelegrafConfig(ctx context.Context, id inf
 		return nil, err
 	}
 
-	return s.s.UpdateTelegrafConfig(ctx, id, upd, userID)
+	updated, err := s.s.UpdateTelegrafConfig(ctx, id, upd, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.RecordRedacted(ctx, influxdb.AuditUpdate, influxdb.TelegrafsResourceType, id, tc.OrganizationID, tc, updated, redactTelegrafConfig)
+	return updated, nil
 }
 
 // DeleteTelegrafConfig checks to see if the authorizer on context has write access to the telegraf config provided.
@@ -134,5 +146,52 @@ func (s *TelegrafConfigService) DeleteTelegrafConfig(ctx context.Context, id inf
 		return err
 	}
 
-	return s.s.DeleteTelegrafConfig(ctx, id)
+	if err := s.s.DeleteTelegrafConfig(ctx, id); err != nil {
+		return err
+	}
+
+	audit.RecordRedacted(ctx, influxdb.AuditDelete, influxdb.TelegrafsResourceType, id, tc.OrganizationID, tc, nil, redactTelegrafConfig)
+	return nil
+}
+
+// auditedTelegrafConfig is the representation of a telegraf config recorded in the audit log.
+// Only the names of its plugins are recorded, as their configs hold credentials, such as the token of the influxdb_v2 output.
+type auditedTelegrafConfig struct {
+	ID             influxdb.ID                  `json:"id"`
+	OrganizationID influxdb.ID                  `json:"organizationID,omitempty"`
+	Name           string                       `json:"name"`
+	Description    string                       `json:"description"`
+	Agent          influxdb.TelegrafAgentConfig `json:"agent"`
+	Plugins        []auditedTelegrafPlugin      `json:"plugins"`
+}
+
+type auditedTelegrafPlugin struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Comment string `json:"comment"`
+}
+
+// redactTelegrafConfig returns the representation of a telegraf config to record in the audit log.
+func redactTelegrafConfig(v interface{}) interface{} {
+	tc, ok := v.(*influxdb.TelegrafConfig)
+	if !ok {
+		return v
+	}
+
+	redacted := &auditedTelegrafConfig{
+		ID:             tc.ID,
+		OrganizationID: tc.OrganizationID,
+		Name:           tc.Name,
+		Description:    tc.Description,
+		Agent:          tc.Agent,
+		Plugins:        make([]auditedTelegrafPlugin, 0, len(tc.Plugins)),
+	}
+	for _, p := range tc.Plugins {
+		plugin := auditedTelegrafPlugin{Comment: p.Comment}
+		if p.Config != nil {
+			plugin.Name, plugin.Type = p.Config.PluginName(), string(p.Config.Type())
+		}
+		redacted.Plugins = append(redacted.Plugins, plugin)
+	}
+	return redacted
 }
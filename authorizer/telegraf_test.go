@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 	"github.com/influxdata/influxdb/authorizer"
 	influxdbcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap"
 )
 
 var telegrafCmpOptions = cmp.Options{
@@ -540,3 +545,68 @@ func TestTelegrafConfigStore_CreateTelegrafConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestTelegrafConfigStore_CreateTelegrafConfig_AuditRedactsPluginConfigs(t *testing.T) {
+	m := &mock.TelegrafConfigStore{
+		CreateTelegrafConfigF: func(ctx context.Context, tc *influxdb.TelegrafConfig, userID influxdb.ID) error {
+			tc.ID = 1
+			return nil
+		},
+	}
+	s := authorizer.NewTelegrafConfigService(m, mock.NewUserResourceMappingService())
+
+	var events []*influxdb.AuditEvent
+	log := mock.NewAuditLogService()
+	log.CreateAuditEventFn = func(ctx context.Context, e *influxdb.AuditEvent) error {
+		events = append(events, e)
+		return nil
+	}
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type:  influxdb.TelegrafsResourceType,
+				OrgID: influxdbtesting.IDPtr(10),
+			},
+		},
+	}})
+	ctx = audit.NewContext(ctx, log, zap.NewNop(), audit.Source{Request: "POST /api/v2/telegrafs"})
+
+	tc := &influxdb.TelegrafConfig{
+		OrganizationID: 10,
+		Name:           "my config",
+		Agent:          influxdb.TelegrafAgentConfig{Interval: 10000},
+		Plugins: []influxdb.TelegrafPlugin{
+			{
+				Comment: "my cpu stats",
+				Config:  &inputs.CPUStats{},
+			},
+			{
+				Comment: "my influx output",
+				Config: &outputs.InfluxDBV2{
+					URLs:         []string{"http://127.0.0.1:9999"},
+					Token:        "no_more_secrets",
+					Organization: "my_org",
+					Bucket:       "my_bucket",
+				},
+			},
+		},
+	}
+	if err := s.CreateTelegrafConfig(ctx, tc, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	after := string(events[0].After)
+	if strings.Contains(after, "no_more_secrets") {
+		t.Errorf("expected the token of the output not to be recorded, got %s", after)
+	}
+	want := `{"agent":{"collectionInterval":10000},"description":"","id":"0000000000000001","name":"my config","organizationID":"000000000000000a",` +
+		`"plugins":[{"name":"cpu","type":"input","comment":"my cpu stats"},{"name":"influxdb_v2","type":"output","comment":"my influx output"}]}`
+	if after != want {
+		t.Errorf("unexpected after; got %s, want %s", after, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 type OrganizationService interface {
@@ -85,7 +86,13 @@ func (s *URMService) CreateUserResourceMapping(ctx context.Context, m *influxdb.
 		return err
 	}
 
-	return s.s.CreateUserResourceMapping(ctx, m)
+	if err := s.s.CreateUserResourceMapping(ctx, m); err != nil {
+		return err
+	}
+
+	// Adding a member or owner to a resource is recorded as an update of the resource.
+	audit.Record(ctx, influxdb.AuditUpdate, m.ResourceType, m.ResourceID, orgID, nil, m)
+	return nil
 }
 
 func (s *URMService) DeleteUserResourceMapping(ctx context.Context, resourceID influxdb.ID, userID influxdb.ID) error {
@@ -108,6 +115,8 @@ func (s *URMService) DeleteUserResourceMapping(ctx context.Context, resourceID i
 		if err := s.s.DeleteUserResourceMapping(ctx, urm.ResourceID, urm.UserID); err != nil {
 			return err
 		}
+
+		audit.Record(ctx, influxdb.AuditUpdate, urm.ResourceType, urm.ResourceID, orgID, urm, nil)
 	}
 
 	return nil
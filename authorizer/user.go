@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.UserService = (*UserService)(nil)
@@ -121,7 +122,12 @@ func (s *UserService) CreateUser(ctx context.Context, o *influxdb.User) error {
 		return err
 	}
 
-	return s.s.CreateUser(ctx, o)
+	if err := s.s.CreateUser(ctx, o); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.UsersResourceType, o.ID, 0, nil, o)
+	return nil
 }
 
 // UpdateUser checks to see if the authorizer on context has write access to the user provided.
@@ -130,7 +136,18 @@ func (s *UserService) UpdateUser(ctx context.Context, id influxdb.ID, upd influx
 		return nil, err
 	}
 
-	return s.s.UpdateUser(ctx, id, upd)
+	var before *influxdb.User
+	if audit.Enabled(ctx) {
+		before, _ = s.s.FindUserByID(ctx, id)
+	}
+
+	u, err := s.s.UpdateUser(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.UsersResourceType, id, 0, before, u)
+	return u, nil
 }
 
 // DeleteUser checks to see if the authorizer on context has write access to the user provided.
@@ -139,5 +156,15 @@ func (s *UserService) DeleteUser(ctx context.Context, id influxdb.ID) error {
 		return err
 	}
 
-	return s.s.DeleteUser(ctx, id)
+	var before *influxdb.User
+	if audit.Enabled(ctx) {
+		before, _ = s.s.FindUserByID(ctx, id)
+	}
+
+	if err := s.s.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.UsersResourceType, id, 0, before, nil)
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 )
 
 var _ influxdb.VariableService = (*VariableService)(nil)
@@ -103,7 +104,12 @@ func (s *VariableService) CreateVariable(ctx context.Context, m *influxdb.Variab
 		return err
 	}
 
-	return s.s.CreateVariable(ctx, m)
+	if err := s.s.CreateVariable(ctx, m); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditCreate, influxdb.VariablesResourceType, m.ID, m.OrganizationID, nil, m)
+	return nil
 }
 
 // UpdateVariable checks to see if the authorizer on context has write access to the variable provided.
@@ -117,21 +123,32 @@ func (s *VariableService) UpdateVariable(ctx context.Context, id influxdb.ID, up
 		return nil, err
 	}
 
-	return s.s.UpdateVariable(ctx, id, upd)
+	updated, err := s.s.UpdateVariable(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.VariablesResourceType, id, m.OrganizationID, m, updated)
+	return updated, nil
 }
 
 // ReplaceVariable checks to see if the authorizer on context has write access to the variable provided.
 func (s *VariableService) ReplaceVariable(ctx context.Context, m *influxdb.Variable) error {
-	m, err := s.FindVariableByID(ctx, m.ID)
+	existing, err := s.FindVariableByID(ctx, m.ID)
 	if err != nil {
 		return err
 	}
 
-	if err := authorizeWriteVariable(ctx, m.OrganizationID, m.ID); err != nil {
+	if err := authorizeWriteVariable(ctx, existing.OrganizationID, m.ID); err != nil {
 		return err
 	}
 
-	return s.s.ReplaceVariable(ctx, m)
+	if err := s.s.ReplaceVariable(ctx, m); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditUpdate, influxdb.VariablesResourceType, m.ID, existing.OrganizationID, existing, m)
+	return nil
 }
 
 // DeleteVariable checks to see if the authorizer on context has write access to the variable provided.
@@ -145,5 +162,10 @@ func (s *VariableService) DeleteVariable(ctx context.Context, id influxdb.ID) er
 		return err
 	}
 
-	return s.s.DeleteVariable(ctx, id)
+	if err := s.s.DeleteVariable(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, influxdb.AuditDelete, influxdb.VariablesResourceType, id, m.OrganizationID, m, nil)
+	return nil
 }
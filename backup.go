@@ -0,0 +1,82 @@
+package influxdb
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Names of the files in a backup archive.
+const (
+	// BackupManifestFile is the name of the BackupManifest of a backup, which
+	// is the first file of the archive.
+	BackupManifestFile = "manifest.json"
+	// BackupKVFile is the name of the snapshot of the metadata store.
+	BackupKVFile = "influxd.bolt"
+	// BackupTSMDir is the directory of the TSM and tombstone files of the
+	// storage engine.
+	BackupTSMDir = "tsm"
+	// BackupWALDir is the directory of the WAL segments of the storage engine.
+	BackupWALDir = "wal"
+)
+
+// ops for backups.
+const (
+	OpBackup = "Backup"
+)
+
+// BackupManifest describes the contents of a backup archive.
+type BackupManifest struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Since is the creation time of the backup an incremental backup is based
+	// on. It is nil for full backups.
+	Since *time.Time `json:"since,omitempty"`
+
+	// KV is the name of the snapshot of the metadata store.
+	KV string `json:"kv"`
+
+	// Files are the names of the TSM, tombstone and WAL segment files in the
+	// archive.
+	Files []string `json:"files"`
+
+	// Engine is the state of the storage engine at the time of the backup.
+	Engine BackupEngineState `json:"engine"`
+}
+
+// Incremental returns true if the backup only contains the files of the
+// storage engine created since another backup.
+func (m *BackupManifest) Incremental() bool {
+	return m.Since != nil
+}
+
+// BackupEngineState is the set of TSM, tombstone and closed WAL segment files
+// of a storage engine at the time of a backup. Restoring a backup recreates
+// exactly these files.
+type BackupEngineState struct {
+	// Generation is the highest generation of the TSM files.
+	Generation int `json:"generation"`
+	// WALSegment is the highest ID of the WAL segments.
+	WALSegment int `json:"walSegment"`
+
+	TSMFiles    []BackupFile `json:"tsmFiles"`
+	WALSegments []BackupFile `json:"walSegments"`
+}
+
+// BackupFile is a file of a storage engine.
+type BackupFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// BackupService creates backups of a running server.
+type BackupService interface {
+	// Backup writes a consistent snapshot of the metadata store and of the
+	// storage engine to w as a tar archive.
+	Backup(ctx context.Context, w io.Writer) error
+
+	// IncrementalBackup writes a consistent snapshot of the metadata store
+	// and the files of the storage engine created since the backup described
+	// by since to w as a tar archive.
+	IncrementalBackup(ctx context.Context, since *BackupManifest, w io.Writer) error
+}
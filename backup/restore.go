@@ -0,0 +1,491 @@
+package backup
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx_inspect/buildtsi"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"github.com/influxdata/influxql"
+	"go.uber.org/zap"
+)
+
+// ReadManifest reads the manifest of the backup archive read from r.
+func ReadManifest(r io.Reader) (*influxdb.BackupManifest, error) {
+	return readManifest(tar.NewReader(r))
+}
+
+func readManifest(tr *tar.Reader) (*influxdb.BackupManifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("reading backup manifest: %v", err)
+	}
+	if hdr.Name != influxdb.BackupManifestFile {
+		return nil, fmt.Errorf("invalid backup: first file is %q, expected %q", hdr.Name, influxdb.BackupManifestFile)
+	}
+	var m influxdb.BackupManifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decoding backup manifest: %v", err)
+	}
+	return &m, nil
+}
+
+// Extract extracts the backup archive read from r into dir, and returns the
+// manifest of the backup. Only the files listed in the manifest are extracted.
+func Extract(r io.Reader, dir string) (*influxdb.BackupManifest, error) {
+	tr := tar.NewReader(r)
+
+	m, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make(map[string]bool, len(m.Files)+1)
+	expected[m.KV] = true
+	for _, f := range m.Files {
+		expected[f] = true
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !expected[hdr.Name] || !validName(hdr.Name) {
+			return nil, fmt.Errorf("invalid backup: unexpected file %q", hdr.Name)
+		}
+		delete(expected, hdr.Name)
+
+		if err := extractFile(tr, filepath.Join(dir, filepath.FromSlash(hdr.Name))); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(expected) > 0 {
+		return nil, fmt.Errorf("invalid backup: %d files are missing", len(expected))
+	}
+	return m, nil
+}
+
+// validName returns true if name is a relative path within the archive.
+func validName(name string) bool {
+	return name != "" && !path.IsAbs(name) && path.Clean(name) == name && !strings.HasPrefix(name, "../")
+}
+
+func extractFile(r io.Reader, p string) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RestoreKV moves the snapshot of the metadata store of the backup extracted
+// in dir to boltPath. It fails if a file exists at boltPath.
+func RestoreKV(dir string, m *influxdb.BackupManifest, boltPath string) error {
+	if _, err := os.Stat(boltPath); err == nil {
+		return fmt.Errorf("bolt file %q already exists", boltPath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(boltPath), 0700); err != nil {
+		return err
+	}
+	return moveFile(filepath.Join(dir, filepath.FromSlash(m.KV)), boltPath)
+}
+
+// ApplyIncremental applies the incremental backup inc extracted in incDir to
+// the backup m extracted in dir, which inc must be based on. It returns the
+// manifest of the resulting backup in dir, which has the metadata store and
+// all of the files of the storage engine at the time of the incremental
+// backup.
+func ApplyIncremental(dir string, m *influxdb.BackupManifest, incDir string, inc *influxdb.BackupManifest) (*influxdb.BackupManifest, error) {
+	if !inc.Incremental() || !inc.Since.Equal(m.CreatedAt) {
+		return nil, fmt.Errorf("backup of %s is not an incremental backup of the backup of %s", inc.CreatedAt, m.CreatedAt)
+	}
+
+	// The metadata store is replaced, as it is always fully backed up.
+	if err := os.Remove(filepath.Join(dir, filepath.FromSlash(m.KV))); err != nil {
+		return nil, err
+	}
+	if err := moveFile(filepath.Join(incDir, filepath.FromSlash(inc.KV)), filepath.Join(dir, filepath.FromSlash(inc.KV))); err != nil {
+		return nil, err
+	}
+
+	for _, f := range inc.Files {
+		dst := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return nil, err
+		}
+		if err := moveFile(filepath.Join(incDir, filepath.FromSlash(f)), dst); err != nil {
+			return nil, err
+		}
+	}
+
+	// Remove the files which were compacted, or written to TSM files, since
+	// the previous backup.
+	files := engineFiles(&inc.Engine)
+	keep := make(map[string]bool, len(files))
+	for _, f := range files {
+		keep[f] = true
+	}
+	for _, f := range m.Files {
+		if keep[f] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(f))); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+			return nil, fmt.Errorf("invalid incremental backup: file %q is in neither backup", f)
+		}
+	}
+
+	applied := *inc
+	applied.Since = m.Since
+	applied.Files = files
+	return &applied, nil
+}
+
+// engineFiles returns the names in a backup archive of the files of state.
+func engineFiles(state *influxdb.BackupEngineState) []string {
+	files := make([]string, 0, len(state.TSMFiles)+len(state.WALSegments))
+	for _, f := range state.TSMFiles {
+		files = append(files, path.Join(influxdb.BackupTSMDir, f.Name))
+	}
+	for _, f := range state.WALSegments {
+		files = append(files, path.Join(influxdb.BackupWALDir, f.Name))
+	}
+	return files
+}
+
+// RestoreEngine moves the TSM, tombstone and WAL segment files of the backup
+// extracted in dir to the storage engine at enginePath, and rebuilds the
+// series file and the index of the engine. The engine must not be running,
+// and must not have any data. Incremental backups must be applied to the
+// backup they are based on first.
+func RestoreEngine(dir string, m *influxdb.BackupManifest, enginePath string, log *zap.Logger) error {
+	if m.Incremental() {
+		return fmt.Errorf("cannot restore an incremental backup without the backup it is based on")
+	}
+
+	config := storage.NewConfig()
+	dataPath, walPath := config.GetEnginePath(enginePath), config.GetWALPath(enginePath)
+	for _, p := range []string{dataPath, walPath, config.GetSeriesFilePath(enginePath), config.GetIndexPath(enginePath)} {
+		if fis, err := ioutil.ReadDir(p); err == nil && len(fis) > 0 {
+			return fmt.Errorf("engine directory %q is not empty", p)
+		} else if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	dirs := map[string]string{
+		influxdb.BackupTSMDir: dataPath,
+		influxdb.BackupWALDir: walPath,
+	}
+	for _, p := range dirs {
+		if err := os.MkdirAll(p, 0777); err != nil {
+			return err
+		}
+	}
+	for _, f := range m.Files {
+		dst, ok := dirs[path.Dir(f)]
+		if !ok {
+			return fmt.Errorf("invalid backup: unexpected file %q", f)
+		}
+		if err := moveFile(filepath.Join(dir, filepath.FromSlash(f)), filepath.Join(dst, path.Base(f))); err != nil {
+			return err
+		}
+	}
+
+	sfile := tsdb.NewSeriesFile(config.GetSeriesFilePath(enginePath))
+	sfile.Logger = log
+	if err := sfile.Open(context.Background()); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	const batchSize = 10000
+	return buildtsi.IndexShard(sfile, config.GetIndexPath(enginePath), dataPath, walPath,
+		tsi1.DefaultMaxIndexLogFileSize, tsm1.DefaultCacheMaxMemorySize, batchSize, log, false)
+}
+
+// moveFile renames src to dst, copying it if they are on different devices.
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// ReadBucketPoints reads the points of the bucket with ID bucketID from the
+// TSM and WAL segment files of the backup extracted in dir, and calls fn with
+// batches of at most batchSize points. The points are in their original
+// measurements, and have a single field. The deletes of the bucket in the WAL
+// segments are applied, in order, to the points of the TSM files and to the
+// points written to the WAL segments before them.
+func ReadBucketPoints(ctx context.Context, dir string, m *influxdb.BackupManifest, bucketID influxdb.ID, batchSize int, fn func([]models.Point) error) error {
+	if m.Incremental() {
+		return fmt.Errorf("cannot read an incremental backup without the backup it is based on")
+	}
+
+	batch := make([]models.Point, 0, batchSize)
+	add := func(p models.Point) error {
+		batch = append(batch, p)
+		if len(batch) < batchSize {
+			return nil
+		}
+		err := fn(batch)
+		batch = batch[:0]
+		return err
+	}
+
+	var tsmPaths, walPaths []string
+	for _, f := range m.Files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		switch filepath.Ext(f) {
+		case "." + tsm1.TSMFileExtension:
+			tsmPaths = append(tsmPaths, p)
+		case "." + wal.WALFileExtension:
+			walPaths = append(walPaths, p)
+		}
+	}
+
+	deletes, err := readWALDeletes(ctx, walPaths, bucketID)
+	if err != nil {
+		return err
+	}
+
+	// The data of the TSM files was written before any of the WAL entries.
+	for _, p := range tsmPaths {
+		if err := readTSMPoints(ctx, p, bucketID, deletes.after(-1), add); err != nil {
+			return err
+		}
+	}
+
+	seq := 0
+	if err := wal.NewWALReader(walPaths).Read(func(entry wal.WALEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		defer func() { seq++ }()
+
+		we, ok := entry.(*wal.WriteWALEntry)
+		if !ok {
+			return nil
+		}
+		deleted := deletes.after(seq)
+		for key, values := range we.Values {
+			if err := bucketPoints([]byte(key), bucketID, values, deleted, add); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// walDelete is a delete of the data of a bucket read from the WAL segments.
+type walDelete struct {
+	seq      int // position of the entry in the WAL segments
+	min, max int64
+	pred     influxql.Expr // nil for a delete of every series
+}
+
+// walDeletes are the deletes of a bucket, in the order of the WAL segments.
+type walDeletes []walDelete
+
+// readWALDeletes returns the deletes of the bucket with ID bucketID in the WAL segments.
+func readWALDeletes(ctx context.Context, walPaths []string, bucketID influxdb.ID) (walDeletes, error) {
+	var deletes walDeletes
+	seq := 0
+	err := wal.NewWALReader(walPaths).Read(func(entry wal.WALEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		defer func() { seq++ }()
+
+		switch en := entry.(type) {
+		case *wal.DeleteBucketRangeWALEntry:
+			if en.BucketID == bucketID {
+				deletes = append(deletes, walDelete{seq: seq, min: en.Min, max: en.Max})
+			}
+		case *wal.DeleteBucketRangePredicateWALEntry:
+			if en.BucketID != bucketID {
+				return nil
+			}
+			pred, err := influxql.ParseExpr(string(en.Predicate))
+			if err != nil {
+				return err
+			}
+			deletes = append(deletes, walDelete{seq: seq, min: en.Min, max: en.Max, pred: pred})
+		}
+		return nil
+	})
+	return deletes, err
+}
+
+// after returns a function reporting whether the value at time t of the series
+// with the given tags was deleted by a delete after the WAL entry at seq.
+func (ds walDeletes) after(seq int) func(tags models.Tags, t int64) bool {
+	return func(tags models.Tags, t int64) bool {
+		for _, d := range ds {
+			if d.seq <= seq || t < d.min || t > d.max {
+				continue
+			}
+			if d.pred == nil {
+				return true
+			}
+			// As in the storage engine, series without a tag of the predicate
+			// have an empty value for it.
+			eval := influxql.ValuerEval{Valuer: tagValuer(tags)}
+			if eval.Eval(d.pred) == true {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// tagValuer is an influxql.Valuer of the tags of a series, including its
+// measurement and field tags.
+type tagValuer models.Tags
+
+func (v tagValuer) Value(key string) (interface{}, bool) {
+	return string(models.Tags(v).Get([]byte(key))), true
+}
+
+func readTSMPoints(ctx context.Context, p string, bucketID influxdb.ID, deleted func(models.Tags, int64) bool, fn func(models.Point) error) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	defer r.Close()
+
+	itr := r.Iterator(nil)
+	for itr.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		key := itr.Key()
+		if !inBucket(key, bucketID) {
+			continue
+		}
+		values, err := r.ReadAll(key)
+		if err != nil {
+			return err
+		}
+		if err := bucketPoints(key, bucketID, values, deleted, fn); err != nil {
+			return err
+		}
+	}
+	return itr.Err()
+}
+
+// inBucket returns true if the series of the composite key is in the bucket
+// with ID bucketID.
+func inBucket(key []byte, bucketID influxdb.ID) bool {
+	seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, _ := models.ParseKeyBytes(seriesKey)
+	if len(name) != influxdb.IDLength {
+		return false
+	}
+	var encoded [influxdb.IDLength]byte
+	copy(encoded[:], name)
+	_, id := tsdb.DecodeName(encoded)
+	return id == bucketID
+}
+
+// bucketPoints calls fn with a point for each of the values of the composite
+// key which were not deleted, if its series is in the bucket with ID bucketID.
+func bucketPoints(key []byte, bucketID influxdb.ID, values []tsm1.Value, deleted func(models.Tags, int64) bool, fn func(models.Point) error) error {
+	if !inBucket(key, bucketID) {
+		return nil
+	}
+
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	_, tags := models.ParseKeyBytes(seriesKey)
+	measurement := tags.Get(tsdb.MeasurementTagKeyBytes)
+	userTags := make(models.Tags, 0, len(tags))
+	for _, t := range tags {
+		if string(t.Key) == string(tsdb.MeasurementTagKeyBytes) || string(t.Key) == string(tsdb.FieldKeyTagKeyBytes) {
+			continue
+		}
+		userTags = append(userTags, t.Clone())
+	}
+
+	for _, v := range values {
+		if deleted(tags, v.UnixNano()) {
+			continue
+		}
+		pt, err := models.NewPoint(string(measurement), userTags, models.Fields{string(field): v.Value()}, time.Unix(0, v.UnixNano()))
+		if err != nil {
+			return err
+		}
+		if err := fn(pt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
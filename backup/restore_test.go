@@ -0,0 +1,159 @@
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/backup"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// bucketValues returns the values of the lines of line protocol written to a bucket, by composite key.
+func bucketValues(t *testing.T, orgID, bucketID influxdb.ID, lines string) map[string][]tsm1.Value {
+	t.Helper()
+
+	points, err := models.ParsePointsString(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points, err = tsdb.ExplodePoints(orgID, bucketID, points); err != nil {
+		t.Fatal(err)
+	}
+	values, err := tsm1.PointsToValues(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+// writeTSMFile writes values to a new TSM file at path.
+func writeTSMFile(t *testing.T, path string, values map[string][]tsm1.Value) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := w.Write([]byte(k), values[k]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadBucketPoints_Deletes(t *testing.T) {
+	ctx := context.Background()
+	orgID, bucketID, otherBucketID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	dir, err := ioutil.TempDir("", "backup-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The snapshot of the engine, before the writes and deletes in the WAL.
+	tsmPath := filepath.Join(dir, tsmA)
+	writeTSMFile(t, tsmPath, bucketValues(t, orgID, bucketID, "cpu,host=a v=1 1\ncpu,host=b v=1 1\nmem,host=a v=1 1"))
+
+	walDir := filepath.Join(dir, "wal")
+	l := wal.NewWAL(walDir)
+	if err := l.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, write := range []func() error{
+		func() error {
+			_, err := l.WriteMulti(bucketValues(t, orgID, bucketID, "cpu,host=a v=2 2\ncpu,host=b v=2 2"))
+			return err
+		},
+		func() error {
+			_, err := l.DeleteBucketRangePredicate(orgID, bucketID, 0, 10, []byte(`_m = 'cpu' AND host = 'a'`))
+			return err
+		},
+		func() error {
+			_, err := l.DeleteBucketRange(orgID, bucketID, 0, 1)
+			return err
+		},
+		func() error {
+			_, err := l.DeleteBucketRange(orgID, otherBucketID, 0, 10)
+			return err
+		},
+		func() error {
+			_, err := l.WriteMulti(bucketValues(t, orgID, bucketID, "cpu,host=a v=3 3"))
+			return err
+		},
+	} {
+		if err := write(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	segments, err := wal.SegmentFileNames(walDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := EngineBackuper{}
+	for _, p := range append([]string{tsmPath}, segments...) {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		engine[filepath.Base(p)] = string(b)
+	}
+
+	var buf bytes.Buffer
+	if err := backup.NewService(KVSnapshotter("bolt data"), engine).Backup(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	restoreDir, m := extract(t, &buf)
+	defer os.RemoveAll(restoreDir)
+
+	var got []string
+	if err := backup.ReadBucketPoints(ctx, restoreDir, m, bucketID, 1, func(points []models.Point) error {
+		for _, p := range points {
+			got = append(got, p.String())
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+
+	// The points deleted after they were written are not restored, but the
+	// point written after the deletes is.
+	exp := []string{
+		"cpu,host=a v=3 3",
+		"cpu,host=b v=2 2",
+	}
+	if !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected restored points: %v", cmp.Diff(got, exp))
+	}
+}
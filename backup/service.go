@@ -0,0 +1,308 @@
+// Package backup creates backup archives of a running server, and restores
+// them.
+//
+// A backup archive is a tar archive. Its first file is an
+// influxdb.BackupManifest, followed by a snapshot of the metadata store and by
+// the TSM, tombstone and WAL segment files of the storage engine. The series
+// file and the index of the storage engine are not part of a backup, as they
+// are rebuilt from the TSM and WAL segment files when a backup is restored.
+//
+// An incremental backup only contains the files of the storage engine created
+// since the backup it is based on. TSM files are immutable and named by their
+// generation, and WAL segments are numbered, so they are new if their
+// generation or number is higher than the highest in the previous backup, or
+// if the previous backup does not have a file with the same name and size.
+// Tombstone files grow when data is deleted, and are in a backup whenever
+// their size changed.
+package backup
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/wal"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+)
+
+// A KVSnapshotter takes consistent snapshots of a metadata store.
+type KVSnapshotter interface {
+	// Snapshot calls fn with a consistent snapshot of the store and its size
+	// in bytes. The snapshot is only valid during the call.
+	Snapshot(ctx context.Context, fn func(size int64, snapshot io.WriterTo) error) error
+}
+
+// An EngineBackuper creates backups of a storage engine.
+type EngineBackuper interface {
+	// CreateBackup creates hard links to the TSM and tombstone files and to
+	// the closed WAL segments of the engine in a new directory, which must be
+	// removed by the caller. If snapshotCache is true, the cache of the engine
+	// is written to a TSM file first.
+	CreateBackup(ctx context.Context, snapshotCache bool) (string, error)
+}
+
+// Service implements influxdb.BackupService for a metadata store and a
+// storage engine.
+type Service struct {
+	kv     KVSnapshotter
+	engine EngineBackuper
+
+	now func() time.Time
+}
+
+var _ influxdb.BackupService = (*Service)(nil)
+
+// NewService returns a new Service which backs up kv and engine. If kv is
+// nil, the metadata store does not support backups and Backup fails.
+func NewService(kv KVSnapshotter, engine EngineBackuper) *Service {
+	return &Service{
+		kv:     kv,
+		engine: engine,
+		now:    time.Now,
+	}
+}
+
+// Backup writes a consistent snapshot of the metadata store and of the storage
+// engine to w as a tar archive.
+//
+// The cache of the storage engine is written to a TSM file, and the TSM files
+// are snapshotted first. The metadata store is snapshotted in a read
+// transaction, which is only held while the snapshot is written.
+func (s *Service) Backup(ctx context.Context, w io.Writer) error {
+	return s.backup(ctx, nil, w)
+}
+
+// IncrementalBackup writes a consistent snapshot of the metadata store and the
+// files of the storage engine created since the backup described by since to
+// w as a tar archive.
+//
+// The cache of the storage engine is not written to a TSM file, so that only
+// the WAL segments written since the previous backup are in the archive.
+func (s *Service) IncrementalBackup(ctx context.Context, since *influxdb.BackupManifest, w io.Writer) error {
+	if since == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpBackup,
+			Msg:  "an incremental backup requires the manifest of a previous backup",
+		}
+	}
+	return s.backup(ctx, since, w)
+}
+
+func (s *Service) backup(ctx context.Context, since *influxdb.BackupManifest, w io.Writer) error {
+	if s.kv == nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Op:   influxdb.OpBackup,
+			Msg:  "backups are only supported with the bolt store",
+		}
+	}
+
+	dir, err := s.engine.CreateBackup(ctx, since == nil)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpBackup,
+			Err:  err,
+		}
+	}
+	defer os.RemoveAll(dir)
+
+	state, err := readEngineState(dir)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpBackup,
+			Err:  err,
+		}
+	}
+
+	manifest := influxdb.BackupManifest{
+		CreatedAt: s.now().UTC(),
+		KV:        influxdb.BackupKVFile,
+		Engine:    *state,
+	}
+	if since != nil {
+		t := since.CreatedAt
+		manifest.Since = &t
+	}
+
+	// paths maps the names of the files in the archive to their paths.
+	paths := make(map[string]string)
+	for _, f := range state.TSMFiles {
+		if since == nil || newTSMFile(f, &since.Engine) {
+			name := path.Join(influxdb.BackupTSMDir, f.Name)
+			manifest.Files = append(manifest.Files, name)
+			paths[name] = filepath.Join(dir, f.Name)
+		}
+	}
+	for _, f := range state.WALSegments {
+		if since == nil || newWALSegment(f, &since.Engine) {
+			name := path.Join(influxdb.BackupWALDir, f.Name)
+			manifest.Files = append(manifest.Files, name)
+			paths[name] = filepath.Join(dir, f.Name)
+		}
+	}
+
+	tw := tar.NewWriter(w)
+	if err := s.kv.Snapshot(ctx, func(size int64, snapshot io.WriterTo) error {
+		if err := writeManifest(tw, manifest); err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    manifest.KV,
+			Mode:    0600,
+			Size:    size,
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		_, err := snapshot.WriteTo(tw)
+		return err
+	}); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpBackup,
+			Err: err,
+		}
+	}
+
+	for _, name := range manifest.Files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writeFile(tw, name, paths[name]); err != nil {
+			return &influxdb.Error{
+				Op:  influxdb.OpBackup,
+				Err: err,
+			}
+		}
+	}
+	return tw.Close()
+}
+
+// readEngineState returns the state of the storage engine files in dir.
+func readEngineState(dir string) (*influxdb.BackupEngineState, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &influxdb.BackupEngineState{}
+	for _, fi := range fis {
+		f := influxdb.BackupFile{Name: fi.Name(), Size: fi.Size()}
+		if isWALSegment(f.Name) {
+			id, err := walSegmentID(f.Name)
+			if err != nil {
+				return nil, err
+			}
+			if id > state.WALSegment {
+				state.WALSegment = id
+			}
+			state.WALSegments = append(state.WALSegments, f)
+			continue
+		}
+
+		gen, _, err := tsm1.DefaultParseFileName(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if gen > state.Generation {
+			state.Generation = gen
+		}
+		state.TSMFiles = append(state.TSMFiles, f)
+	}
+	return state, nil
+}
+
+// newTSMFile returns true if the TSM or tombstone file f is not part of the
+// engine state prev.
+func newTSMFile(f influxdb.BackupFile, prev *influxdb.BackupEngineState) bool {
+	if gen, _, err := tsm1.DefaultParseFileName(f.Name); err != nil || gen > prev.Generation {
+		return true
+	}
+	return !containsFile(prev.TSMFiles, f)
+}
+
+// newWALSegment returns true if the WAL segment f is not part of the engine
+// state prev. WAL segment IDs restart when the WAL is empty on startup, so a
+// segment with a known ID is new if its size differs.
+func newWALSegment(f influxdb.BackupFile, prev *influxdb.BackupEngineState) bool {
+	if id, err := walSegmentID(f.Name); err != nil || id > prev.WALSegment {
+		return true
+	}
+	return !containsFile(prev.WALSegments, f)
+}
+
+func containsFile(files []influxdb.BackupFile, f influxdb.BackupFile) bool {
+	for _, ff := range files {
+		if ff == f {
+			return true
+		}
+	}
+	return false
+}
+
+func isWALSegment(name string) bool {
+	return filepath.Ext(name) == "."+wal.WALFileExtension
+}
+
+// walSegmentID returns the ID of the WAL segment with the provided name.
+func walSegmentID(name string) (int, error) {
+	id := strings.TrimSuffix(strings.TrimPrefix(name, wal.WALFilePrefix), "."+wal.WALFileExtension)
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("WAL segment %s is named incorrectly", name)
+	}
+	return n, nil
+}
+
+func writeManifest(tw *tar.Writer, m influxdb.BackupManifest) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    influxdb.BackupManifestFile,
+		Mode:    0600,
+		Size:    int64(len(b)),
+		ModTime: m.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	_, err = tw.Write(b)
+	return err
+}
+
+// writeFile writes the file at path to tw with the provided name.
+func writeFile(tw *tar.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
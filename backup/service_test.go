@@ -0,0 +1,208 @@
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/backup"
+)
+
+// KVSnapshotter is a backup.KVSnapshotter with a fixed snapshot.
+type KVSnapshotter []byte
+
+func (s KVSnapshotter) Snapshot(ctx context.Context, fn func(int64, io.WriterTo) error) error {
+	return fn(int64(len(s)), bytes.NewReader(s))
+}
+
+// EngineBackuper is a backup.EngineBackuper with fixed files.
+type EngineBackuper map[string]string
+
+func (e EngineBackuper) CreateBackup(ctx context.Context, snapshotCache bool) (string, error) {
+	dir, err := ioutil.TempDir("", "backup-test-")
+	if err != nil {
+		return "", err
+	}
+	for name, data := range e {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// extract extracts the backup in buf to a new directory.
+func extract(t *testing.T, buf *bytes.Buffer) (string, *influxdb.BackupManifest) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "backup-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := backup.Extract(buf, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir, m
+}
+
+// readFiles returns the contents of the files of m extracted in dir.
+func readFiles(t *testing.T, dir string, m *influxdb.BackupManifest) map[string]string {
+	t.Helper()
+	got := map[string]string{}
+	for _, name := range append([]string{m.KV}, m.Files...) {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[name] = string(b)
+	}
+	return got
+}
+
+const (
+	tsmA       = "000000000000001-000000001.tsm"
+	tombstoneA = "000000000000001-000000001.tombstone"
+	tsmB       = "000000000000001-000000002.tsm"
+	tsmC       = "000000000000002-000000001.tsm"
+	wal1       = "_00001.wal"
+	wal2       = "_00002.wal"
+)
+
+func TestService_Backup(t *testing.T) {
+	engine := EngineBackuper{
+		tsmA:       "tsm data",
+		tombstoneA: "tombstone data",
+		wal1:       "wal data",
+	}
+	s := backup.NewService(KVSnapshotter("bolt data"), engine)
+
+	var buf bytes.Buffer
+	if err := s.Backup(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, m := extract(t, &buf)
+	defer os.RemoveAll(dir)
+
+	if m.Incremental() {
+		t.Fatal("full backup is incremental")
+	}
+	if got, exp := m.Engine.Generation, 1; got != exp {
+		t.Fatalf("got generation %d, exp %d", got, exp)
+	}
+	if got, exp := m.Engine.WALSegment, 1; got != exp {
+		t.Fatalf("got WAL segment %d, exp %d", got, exp)
+	}
+
+	exp := map[string]string{
+		influxdb.BackupKVFile: "bolt data",
+		"tsm/" + tsmA:         "tsm data",
+		"tsm/" + tombstoneA:   "tombstone data",
+		"wal/" + wal1:         "wal data",
+	}
+	if got := readFiles(t, dir, m); !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected extracted files: %v", cmp.Diff(got, exp))
+	}
+}
+
+func TestService_IncrementalBackup(t *testing.T) {
+	ctx := context.Background()
+	engine := EngineBackuper{
+		tsmA:       "a",
+		tombstoneA: "t",
+		wal1:       "w1",
+	}
+	var buf bytes.Buffer
+	if err := backup.NewService(KVSnapshotter("bolt 1"), engine).Backup(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	dir, m := extract(t, &buf)
+	defer os.RemoveAll(dir)
+
+	// A TSM file was compacted, a new one was written from the WAL, and data
+	// was deleted.
+	engine = EngineBackuper{
+		tsmA:       "a",
+		tombstoneA: "tt",
+		tsmB:       "b",
+		tsmC:       "c",
+		wal2:       "w2",
+	}
+	buf.Reset()
+	if err := backup.NewService(KVSnapshotter("bolt 2"), engine).IncrementalBackup(ctx, m, &buf); err != nil {
+		t.Fatal(err)
+	}
+	incDir, inc := extract(t, &buf)
+	defer os.RemoveAll(incDir)
+
+	if !inc.Incremental() || !inc.Since.Equal(m.CreatedAt) {
+		t.Fatalf("got since %v, exp %v", inc.Since, m.CreatedAt)
+	}
+	exp := []string{"tsm/" + tombstoneA, "tsm/" + tsmB, "tsm/" + tsmC, "wal/" + wal2}
+	if !cmp.Equal(inc.Files, exp) {
+		t.Fatalf("unexpected files in incremental backup: %v", cmp.Diff(inc.Files, exp))
+	}
+
+	applied, err := backup.ApplyIncremental(dir, m, incDir, inc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied.Incremental() {
+		t.Fatal("applied incremental backup is incremental")
+	}
+
+	expFiles := map[string]string{
+		influxdb.BackupKVFile: "bolt 2",
+		"tsm/" + tsmA:         "a",
+		"tsm/" + tombstoneA:   "tt",
+		"tsm/" + tsmB:         "b",
+		"tsm/" + tsmC:         "c",
+		"wal/" + wal2:         "w2",
+	}
+	if got := readFiles(t, dir, applied); !cmp.Equal(got, expFiles) {
+		t.Fatalf("unexpected restored files: %v", cmp.Diff(got, expFiles))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wal", wal1)); !os.IsNotExist(err) {
+		t.Fatalf("WAL segment written to a TSM file was not removed: %v", err)
+	}
+
+	// The incremental backup is not based on itself.
+	if _, err := backup.ApplyIncremental(dir, inc, incDir, inc); err == nil {
+		t.Fatal("expected error applying incremental backup to the wrong backup")
+	}
+}
+
+func TestService_Backup_NoKV(t *testing.T) {
+	s := backup.NewService(nil, EngineBackuper{})
+	err := s.Backup(context.Background(), ioutil.Discard)
+	if got, exp := influxdb.ErrorCode(err), influxdb.EUnavailable; got != exp {
+		t.Fatalf("got error code %q, exp %q", got, exp)
+	}
+}
+
+func TestExtract_Truncated(t *testing.T) {
+	var buf bytes.Buffer
+	s := backup.NewService(KVSnapshotter("bolt data"), EngineBackuper{tsmA: "tsm data"})
+	if err := s.Backup(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Truncating the archive after the manifest loses the listed files.
+	b := buf.Bytes()[:1024]
+	dir, err := ioutil.TempDir("", "backup-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := backup.Extract(bytes.NewReader(b), dir); err == nil {
+		t.Fatal("expected error extracting truncated backup")
+	}
+}
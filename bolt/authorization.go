@@ -3,6 +3,7 @@ package bolt
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/coreos/bbolt"
 	platform "github.com/influxdata/influxdb"
@@ -103,10 +104,32 @@ func (c *Client) findAuthorizationByToken(ctx context.Context, tx *bolt.Tx, n st
 			Err:  err,
 		}
 	}
-	return c.findAuthorizationByID(ctx, tx, id)
+
+	auth, pe := c.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+
+	// The index still has the previous token of a rotated authorization after its grace period ended.
+	if !auth.AcceptsToken(n, time.Now()) {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+	return auth, nil
 }
 
 func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platform.Authorization) bool {
+	if filter.UnusedSince != nil {
+		t := *filter.UnusedSince
+		filter.UnusedSince = nil
+		fn := filterAuthorizationsFn(filter)
+		return func(a *platform.Authorization) bool {
+			return a.UnusedSince(t) && fn(a)
+		}
+	}
+
 	if filter.ID != nil {
 		return func(a *platform.Authorization) bool {
 			return a.ID == *filter.ID
@@ -238,6 +261,10 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 		}
 
 		a.ID = c.IDGenerator.ID()
+		a.CreatedAt = time.Now()
+		a.LastUsedAt = nil
+		a.PreviousToken = ""
+		a.PreviousTokenExpiresAt = nil
 
 		pe := c.putAuthorization(ctx, tx, a)
 		if pe != nil {
@@ -299,6 +326,15 @@ func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.
 		}
 	}
 
+	if a.PreviousToken != "" {
+		if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(a.PreviousToken), encodedID); err != nil {
+			return &platform.Error{
+				Code: platform.EInternal,
+				Err:  err,
+			}
+		}
+	}
+
 	if err := tx.Bucket(authorizationBucket).Put(encodedID, v); err != nil {
 		return &platform.Error{
 			Err: err,
@@ -362,10 +398,8 @@ func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	if pe != nil {
 		return pe
 	}
-	if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(a.Token)); err != nil {
-		return &platform.Error{
-			Err: err,
-		}
+	if pe := deleteAuthorizationIndexKeys(tx, a); pe != nil {
+		return pe
 	}
 	encodedID, err := id.Encode()
 	if err != nil {
@@ -424,3 +458,80 @@ func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	}
 	return nil
 }
+
+// RotateAuthorization replaces the token of an authorization with a new one, and returns the updated authorization.
+// The current token is still accepted for gracePeriod.
+func (c *Client) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	op := getOp(platform.OpRotateAuthorization)
+	var a *platform.Authorization
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		var pe *platform.Error
+		a, pe = c.findAuthorizationByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		token, err := c.TokenGenerator.Token()
+		if err != nil {
+			return &platform.Error{
+				Err: err,
+				Op:  op,
+			}
+		}
+		if unique := c.uniqueAuthorizationToken(ctx, tx, &platform.Authorization{Token: token}); !unique {
+			return platform.ErrUnableToCreateToken
+		}
+
+		// putAuthorization indexes the tokens which are still accepted after the rotation.
+		if pe := deleteAuthorizationIndexKeys(tx, a); pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		a.Rotate(token, time.Now(), gracePeriod)
+		if pe := c.putAuthorization(ctx, tx, a); pe != nil {
+			pe.Op = op
+			return pe
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetAuthorizationLastUsed records the time the authorization's token was last used at.
+func (c *Client) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) error {
+	op := getOp(platform.OpSetAuthorizationLastUsed)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		a, pe := c.findAuthorizationByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		a.LastUsedAt = &t
+		if pe := c.putAuthorization(ctx, tx, a); pe != nil {
+			pe.Op = op
+			return pe
+		}
+		return nil
+	})
+}
+
+// deleteAuthorizationIndexKeys removes the current and previous tokens of a from the token index.
+func deleteAuthorizationIndexKeys(tx *bolt.Tx, a *platform.Authorization) *platform.Error {
+	for _, t := range []string{a.Token, a.PreviousToken} {
+		if t == "" {
+			continue
+		}
+		if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(t)); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package bolt
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -106,6 +107,14 @@ func (s *KVStore) View(fn func(tx kv.Tx) error) error {
 	})
 }
 
+// Snapshot calls fn with a consistent snapshot of the store, taken in a read
+// transaction, and its size in bytes. The snapshot is only valid during the call.
+func (s *KVStore) Snapshot(ctx context.Context, fn func(size int64, snapshot io.WriterTo) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Size(), tx)
+	})
+}
+
 // Update opens up an update transaction against the store.
 func (s *KVStore) Update(fn func(tx kv.Tx) error) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
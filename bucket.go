@@ -26,6 +26,22 @@ type Bucket struct {
 	Name                string        `json:"name"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	SchemaType          SchemaType    `json:"schemaType,omitempty"`
+
+	DownsampleRules []DownsampleRule `json:"downsampleRules,omitempty"`
+}
+
+// RawRetentionPeriod returns how long the raw data of the bucket is kept: the
+// shortest of the retention period and the source retention of the downsample
+// rules. It returns InfiniteRetention if the raw data is kept forever.
+func (b *Bucket) RawRetentionPeriod() time.Duration {
+	rp := b.RetentionPeriod
+	for _, r := range b.DownsampleRules {
+		if r.SourceRetention > 0 && (rp == InfiniteRetention || r.SourceRetention < rp) {
+			rp = r.SourceRetention
+		}
+	}
+	return rp
 }
 
 // ops for buckets error and buckets op logs.
@@ -66,6 +82,14 @@ type BucketService interface {
 type BucketUpdate struct {
 	Name            *string        `json:"name,omitempty"`
 	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+
+	// DownsampleRules replaces the downsample rules of the bucket if set.
+	DownsampleRules *[]DownsampleRule `json:"downsampleRules,omitempty"`
+
+	// SchemaType changes how the schema of the data written to the bucket is
+	// determined if set. The data already written is not validated against
+	// its measurement schemas when it becomes explicit.
+	SchemaType *SchemaType `json:"schemaType,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
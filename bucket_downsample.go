@@ -0,0 +1,120 @@
+package influxdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// DownsampleFunction is the aggregate function used by a DownsampleRule to
+// roll up the data of each window.
+type DownsampleFunction string
+
+// Known functions of a DownsampleRule.
+const (
+	DownsampleFunctionMean   DownsampleFunction = "mean"
+	DownsampleFunctionMedian DownsampleFunction = "median"
+	DownsampleFunctionMin    DownsampleFunction = "min"
+	DownsampleFunctionMax    DownsampleFunction = "max"
+	DownsampleFunctionSum    DownsampleFunction = "sum"
+	DownsampleFunctionCount  DownsampleFunction = "count"
+	DownsampleFunctionFirst  DownsampleFunction = "first"
+	DownsampleFunctionLast   DownsampleFunction = "last"
+)
+
+// Valid returns an error if the function is not known.
+func (f DownsampleFunction) Valid() error {
+	switch f {
+	case DownsampleFunctionMean, DownsampleFunctionMedian,
+		DownsampleFunctionMin, DownsampleFunctionMax,
+		DownsampleFunctionSum, DownsampleFunctionCount,
+		DownsampleFunctionFirst, DownsampleFunctionLast:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid downsample function %q; valid functions are mean, median, min, max, sum, count, first and last", f),
+		}
+	}
+}
+
+// DownsampleRule rolls up the data of a bucket into another bucket.
+//
+// Each rule is materialized as a task owned by the bucket, which aggregates
+// every window of the bucket's data with the rule's function and writes the
+// result to the destination bucket. The task is created, updated and deleted
+// along with the rule.
+type DownsampleRule struct {
+	// SourceRetention is how long the raw data of the bucket is kept once it
+	// has been rolled up. Zero means the raw data is kept for the retention
+	// period of the bucket.
+	SourceRetention time.Duration `json:"sourceRetention,omitempty"`
+
+	// Every is the width of the aggregate windows.
+	Every    time.Duration      `json:"every"`
+	Function DownsampleFunction `json:"function"`
+
+	DestinationBucketID ID `json:"destinationBucketID"`
+
+	// TaskID is the ID of the task which materializes the rule. It is set by
+	// the server.
+	TaskID ID `json:"taskID,omitempty"`
+}
+
+// Valid returns an error if the rule of the bucket with ID bucketID is invalid.
+func (r DownsampleRule) Valid(bucketID ID) error {
+	switch {
+	case r.Every < time.Second:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample window must be greater than or equal to one second",
+		}
+	case r.SourceRetention < 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample source retention must not be negative",
+		}
+	case r.SourceRetention > 0 && r.SourceRetention < r.Every:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample source retention must be greater than or equal to the window",
+		}
+	case !r.DestinationBucketID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample destination bucket ID is invalid",
+		}
+	case r.DestinationBucketID == bucketID:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "downsample destination bucket must be different from the source bucket",
+		}
+	}
+	return r.Function.Valid()
+}
+
+// SameRollup returns true if r and other roll up data the same way, that is
+// they only differ by their source retention or task.
+func (r DownsampleRule) SameRollup(other DownsampleRule) bool {
+	return r.Every == other.Every &&
+		r.Function == other.Function &&
+		r.DestinationBucketID == other.DestinationBucketID
+}
+
+// ValidDownsampleRules returns an error if any of the rules of the bucket with
+// ID bucketID is invalid, or if two of them roll up data the same way.
+func ValidDownsampleRules(bucketID ID, rules []DownsampleRule) error {
+	for i, r := range rules {
+		if err := r.Valid(bucketID); err != nil {
+			return err
+		}
+		for _, other := range rules[:i] {
+			if r.SameRollup(other) {
+				return &Error{
+					Code: EConflict,
+					Msg:  fmt.Sprintf("duplicate downsample rule: %s of %s windows to bucket %s", r.Function, r.Every, r.DestinationBucketID),
+				}
+			}
+		}
+	}
+	return nil
+}
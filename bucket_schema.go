@@ -0,0 +1,212 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// SchemaType defines how the schema of the data written to a bucket is determined.
+type SchemaType string
+
+const (
+	// SchemaTypeImplicit indicates that the schema of a bucket is determined
+	// by the data written to it. This is the default.
+	SchemaTypeImplicit SchemaType = "implicit"
+	// SchemaTypeExplicit indicates that every measurement written to a bucket
+	// must be described by a MeasurementSchema.
+	SchemaTypeExplicit SchemaType = "explicit"
+)
+
+// Valid returns an error if the schema type is not known. The empty schema type
+// is valid and is equivalent to SchemaTypeImplicit.
+func (t SchemaType) Valid() error {
+	switch t {
+	case "", SchemaTypeImplicit, SchemaTypeExplicit:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid schema type %q; valid schema types are %q and %q", t, SchemaTypeImplicit, SchemaTypeExplicit),
+		}
+	}
+}
+
+// SchemaFieldType is the data type of a field in a MeasurementSchema.
+type SchemaFieldType string
+
+// Known field types of a MeasurementSchema.
+const (
+	SchemaFieldTypeFloat    SchemaFieldType = "float"
+	SchemaFieldTypeInteger  SchemaFieldType = "integer"
+	SchemaFieldTypeUnsigned SchemaFieldType = "unsigned"
+	SchemaFieldTypeString   SchemaFieldType = "string"
+	SchemaFieldTypeBoolean  SchemaFieldType = "boolean"
+)
+
+// Valid returns an error if the field type is not known.
+func (t SchemaFieldType) Valid() error {
+	switch t {
+	case SchemaFieldTypeFloat, SchemaFieldTypeInteger, SchemaFieldTypeUnsigned, SchemaFieldTypeString, SchemaFieldTypeBoolean:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid field type %q; valid field types are float, integer, unsigned, string and boolean", t),
+		}
+	}
+}
+
+// ErrMeasurementSchemaNotFound is the error message for a missing measurement schema.
+const ErrMeasurementSchemaNotFound = "measurement schema not found"
+
+// ops for measurement schema errors.
+var (
+	OpFindMeasurementSchema   = "FindMeasurementSchema"
+	OpFindMeasurementSchemas  = "FindMeasurementSchemas"
+	OpCreateMeasurementSchema = "CreateMeasurementSchema"
+	OpUpdateMeasurementSchema = "UpdateMeasurementSchema"
+	OpDeleteMeasurementSchema = "DeleteMeasurementSchema"
+)
+
+// MeasurementSchemaField is a field of a MeasurementSchema.
+type MeasurementSchemaField struct {
+	Name string          `json:"name"`
+	Type SchemaFieldType `json:"type"`
+}
+
+// MeasurementSchema describes the tags and fields of a measurement written
+// to a bucket with an explicit schema.
+type MeasurementSchema struct {
+	BucketID ID     `json:"bucketID,omitempty"`
+	Name     string `json:"name"`
+	// Tags are the tag keys which every point of the measurement must have.
+	// Points may have other tags.
+	Tags []string `json:"tags"`
+	// Fields are the only fields points of the measurement may have.
+	Fields []MeasurementSchemaField `json:"fields"`
+}
+
+// Field returns the field with the given name, or nil if the schema has no
+// such field.
+func (m *MeasurementSchema) Field(name string) *MeasurementSchemaField {
+	for i := range m.Fields {
+		if m.Fields[i].Name == name {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the schema is not valid.
+func (m *MeasurementSchema) Validate() error {
+	if m.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "measurement schema requires a name",
+		}
+	}
+
+	if len(m.Fields) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("measurement schema %q requires at least one field", m.Name),
+		}
+	}
+
+	names := make(map[string]bool, len(m.Tags)+len(m.Fields))
+	for _, t := range m.Tags {
+		if t == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement schema %q has a tag without a name", m.Name),
+			}
+		}
+		if names[t] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement schema %q has duplicate tag %q", m.Name, t),
+			}
+		}
+		names[t] = true
+	}
+
+	for _, f := range m.Fields {
+		if f.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement schema %q has a field without a name", m.Name),
+			}
+		}
+		if names[f.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("measurement schema %q has duplicate tag or field %q", m.Name, f.Name),
+			}
+		}
+		names[f.Name] = true
+
+		if err := f.Type.Valid(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MeasurementSchemaUpdate represents updates to a measurement schema.
+//
+// Fields which already exist in the schema can neither be removed nor change
+// type, as data of that type may already have been written to the bucket.
+type MeasurementSchemaUpdate struct {
+	Tags   []string                 `json:"tags,omitempty"`
+	Fields []MeasurementSchemaField `json:"fields,omitempty"`
+}
+
+// Apply applies the update to m, returning an error if the update would remove
+// or change the type of an existing field.
+func (u MeasurementSchemaUpdate) Apply(m *MeasurementSchema) error {
+	if u.Fields != nil {
+		upd := &MeasurementSchema{Fields: u.Fields}
+		for _, f := range m.Fields {
+			uf := upd.Field(f.Name)
+			if uf == nil {
+				return &Error{
+					Code: EConflict,
+					Msg:  fmt.Sprintf("field %q cannot be removed from measurement schema %q", f.Name, m.Name),
+				}
+			}
+			if uf.Type != f.Type {
+				return &Error{
+					Code: EConflict,
+					Msg:  fmt.Sprintf("field %q of measurement schema %q cannot change type from %s to %s", f.Name, m.Name, f.Type, uf.Type),
+				}
+			}
+		}
+		m.Fields = u.Fields
+	}
+
+	if u.Tags != nil {
+		m.Tags = u.Tags
+	}
+
+	return m.Validate()
+}
+
+// BucketSchemaService represents a service for managing the measurement schemas
+// of buckets with an explicit schema.
+type BucketSchemaService interface {
+	// FindMeasurementSchema returns the schema of a measurement in a bucket.
+	FindMeasurementSchema(ctx context.Context, bucketID ID, name string) (*MeasurementSchema, error)
+
+	// FindMeasurementSchemas returns the schemas of every measurement in a bucket.
+	FindMeasurementSchemas(ctx context.Context, bucketID ID) ([]*MeasurementSchema, error)
+
+	// CreateMeasurementSchema creates the schema of a measurement in a bucket.
+	CreateMeasurementSchema(ctx context.Context, m *MeasurementSchema) error
+
+	// UpdateMeasurementSchema updates the schema of a measurement in a bucket.
+	UpdateMeasurementSchema(ctx context.Context, bucketID ID, name string, upd MeasurementSchemaUpdate) (*MeasurementSchema, error)
+
+	// DeleteMeasurementSchema removes the schema of a measurement from a bucket.
+	DeleteMeasurementSchema(ctx context.Context, bucketID ID, name string) error
+}
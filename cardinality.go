@@ -0,0 +1,63 @@
+package influxdb
+
+import "context"
+
+// DefaultCardinalityTopN is the default number of tag values with the most
+// series reported for each tag key.
+const DefaultCardinalityTopN = 10
+
+// ops for cardinality.
+const (
+	OpFindBucketCardinality = "FindBucketCardinality"
+)
+
+// CardinalityOptions determine how the cardinality of a bucket is computed.
+type CardinalityOptions struct {
+	// TopN is the number of tag values with the most series reported for each
+	// tag key.
+	TopN int
+
+	// Estimate bounds the memory used to compute the cardinality of a bucket by
+	// estimating the number of values of each tag key with a HyperLogLog sketch,
+	// and approximating the tag values with the most series.
+	Estimate bool
+}
+
+// BucketCardinality describes the series cardinality of a bucket.
+type BucketCardinality struct {
+	BucketID  ID   `json:"bucketID"`
+	Estimated bool `json:"estimated"`
+
+	// Series is the number of series in the bucket. A series is identified by
+	// its measurement, tag set and field.
+	Series       int64                    `json:"series"`
+	Measurements []MeasurementCardinality `json:"measurements"`
+}
+
+// MeasurementCardinality describes the series cardinality of a measurement.
+type MeasurementCardinality struct {
+	Name    string              `json:"name"`
+	Series  int64               `json:"series"`
+	TagKeys []TagKeyCardinality `json:"tagKeys"`
+}
+
+// TagKeyCardinality describes the values of a tag key of a measurement. The
+// field of a series is reported as the _field tag key.
+type TagKeyCardinality struct {
+	Key       string                `json:"key"`
+	Values    int64                 `json:"values"`
+	TopValues []TagValueCardinality `json:"topValues"`
+}
+
+// TagValueCardinality is the number of series with a tag value.
+type TagValueCardinality struct {
+	Value  string `json:"value"`
+	Series int64  `json:"series"`
+}
+
+// CardinalityService reports the series cardinality of buckets.
+type CardinalityService interface {
+	// FindBucketCardinality returns the series cardinality of a bucket broken
+	// down by measurement, tag key and tag value.
+	FindBucketCardinality(ctx context.Context, bucketID ID, opts CardinalityOptions) (*BucketCardinality, error)
+}
@@ -37,6 +37,7 @@ type Generic struct {
 	TokenURL       string
 	APIURL         string // APIURL returns OpenID Userinfo
 	APIKey         string // APIKey is the JSON key to lookup email address in APIURL response
+	GroupsKey      string // Optional JSON key to lookup the list of groups in APIURL response or id_token claims
 	Logger         chronograf.Logger
 }
 
@@ -134,6 +135,10 @@ func (g *Generic) Group(provider *http.Client) (string, error) {
 		return "", err
 	}
 
+	if g.GroupsKey != "" {
+		return groupsFromValue(res[g.GroupsKey])
+	}
+
 	email := ""
 	value := res[g.APIKey]
 	if e, ok := value.(string); ok {
@@ -214,8 +219,13 @@ func (g *Generic) PrincipalIDFromClaims(claims gojwt.MapClaims) (string, error)
 	return "", fmt.Errorf("no claim for %s", g.APIKey)
 }
 
-// GroupFromClaims verifies an optional id_token, extracts the email address of the user and splits off the domain part
+// GroupFromClaims verifies an optional id_token, extracts the email address of the user and splits off the domain part.
+// If GroupsKey is set, the groups claim is returned instead.
 func (g *Generic) GroupFromClaims(claims gojwt.MapClaims) (string, error) {
+	if g.GroupsKey != "" {
+		return groupsFromValue(claims[g.GroupsKey])
+	}
+
 	if id, ok := claims[g.APIKey].(string); ok {
 		email := strings.Split(id, "@")
 		if len(email) != 2 {
@@ -228,3 +238,25 @@ func (g *Generic) GroupFromClaims(claims gojwt.MapClaims) (string, error) {
 
 	return "", fmt.Errorf("no claim for %s", g.APIKey)
 }
+
+// groupsFromValue returns the comma delimited list of groups of a groups claim,
+// which is either a list of group names or a single group name.
+func groupsFromValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []interface{}:
+		groups := make([]string, 0, len(v))
+		for _, g := range v {
+			s, ok := g.(string)
+			if !ok {
+				return "", fmt.Errorf("group %v is not a string", g)
+			}
+			groups = append(groups, s)
+		}
+		return strings.Join(groups, ","), nil
+	}
+	return "", fmt.Errorf("groups claim %v is not a list of strings", v)
+}
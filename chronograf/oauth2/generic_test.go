@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	gojwt "github.com/dgrijalva/jwt-go"
 	"github.com/influxdata/influxdb/chronograf"
 	"github.com/influxdata/influxdb/chronograf/oauth2"
 )
@@ -56,6 +57,78 @@ func TestGenericGroup_withNotEmail(t *testing.T) {
 	}
 }
 
+func TestGenericGroup_withGroupsKey(t *testing.T) {
+	t.Parallel()
+
+	response := struct {
+		Email  string   `json:"email"`
+		Groups []string `json:"groups"`
+	}{
+		"martymcfly@pinheads.rok",
+		[]string{"admins", "devs"},
+	}
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		enc := json.NewEncoder(rw)
+
+		rw.WriteHeader(http.StatusOK)
+		_ = enc.Encode(response)
+	}))
+	defer mockAPI.Close()
+
+	logger := &chronograf.NoopLogger{}
+	prov := oauth2.Generic{
+		Logger:    logger,
+		APIURL:    mockAPI.URL,
+		APIKey:    "email",
+		GroupsKey: "groups",
+	}
+	tt, err := oauth2.NewTestTripper(logger, mockAPI, http.DefaultTransport)
+	if err != nil {
+		t.Fatal("Error initializing TestTripper: err:", err)
+	}
+
+	tc := &http.Client{
+		Transport: tt,
+	}
+
+	got, err := prov.Group(tc)
+	if err != nil {
+		t.Fatal("Unexpected error while retrieiving Group: err:", err)
+	}
+
+	want := "admins,devs"
+	if got != want {
+		t.Fatal("Retrieved group was not as expected. Want:", want, "Got:", got)
+	}
+}
+
+func TestGenericGroupFromClaims_withGroupsKey(t *testing.T) {
+	t.Parallel()
+
+	prov := oauth2.Generic{
+		Logger:    &chronograf.NoopLogger{},
+		APIKey:    "email",
+		GroupsKey: "groups",
+	}
+
+	got, err := prov.GroupFromClaims(gojwt.MapClaims{
+		"email":  "martymcfly@pinheads.rok",
+		"groups": []interface{}{"admins", "devs"},
+	})
+	if err != nil {
+		t.Fatal("Unexpected error while retrieiving Group: err:", err)
+	}
+
+	want := "admins,devs"
+	if got != want {
+		t.Fatal("Retrieved group was not as expected. Want:", want, "Got:", got)
+	}
+}
+
 func TestGenericGroup_withEmail(t *testing.T) {
 	t.Parallel()
 
@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"os"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/spf13/cobra"
 )
 
@@ -46,6 +48,8 @@ type AuthorizationCreateFlags struct {
 
 	writeDashboardsPermission bool
 	readDashboardsPermission  bool
+
+	expiresIn time.Duration
 }
 
 var authorizationCreateFlags AuthorizationCreateFlags
@@ -83,6 +87,8 @@ func init() {
 	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.writeDashboardsPermission, "write-dashboards", "", false, "Grants the permission to create dashboards")
 	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.readDashboardsPermission, "read-dashboards", "", false, "Grants the permission to read dashboards")
 
+	authorizationCreateCmd.Flags().DurationVarP(&authorizationCreateFlags.expiresIn, "expires-in", "", 0, "The duration after which the token expires; it never expires if unset")
+
 	authorizationCmd.AddCommand(authorizationCreateCmd)
 }
 
@@ -227,6 +233,10 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		Permissions: permissions,
 		OrgID:       o.ID,
 	}
+	if authorizationCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authorizationCreateFlags.expiresIn)
+		authorization.ExpiresAt = &expiresAt
+	}
 
 	s, err := newAuthorizationService(flags)
 	if err != nil {
@@ -266,9 +276,10 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 
 // AuthorizationFindFlags are command line args used when finding a authorization
 type AuthorizationFindFlags struct {
-	user   string
-	userID string
-	id     string
+	user      string
+	userID    string
+	id        string
+	unusedFor time.Duration
 }
 
 var authorizationFindFlags AuthorizationFindFlags
@@ -283,6 +294,7 @@ func init() {
 	authorizationFindCmd.Flags().StringVarP(&authorizationFindFlags.user, "user", "u", "", "The user")
 	authorizationFindCmd.Flags().StringVarP(&authorizationFindFlags.userID, "user-id", "", "", "The user ID")
 	authorizationFindCmd.Flags().StringVarP(&authorizationFindFlags.id, "id", "i", "", "The authorization ID")
+	authorizationFindCmd.Flags().DurationVarP(&authorizationFindFlags.unusedFor, "unused-for", "", 0, "Only find stale authorizations, whose token was not used for the duration")
 
 	authorizationCmd.AddCommand(authorizationFindCmd)
 }
@@ -293,13 +305,17 @@ func newAuthorizationService(f Flags) (platform.AuthorizationService, error) {
 		if err != nil {
 			return nil, err
 		}
-		c := bolt.NewClient()
-		c.Path = boltFile
-		if err := c.Open(context.Background()); err != nil {
+		// Tokens are stored hashed by the kv service, which the bolt client can't read.
+		store := bolt.NewKVStore(boltFile)
+		if err := store.Open(context.Background()); err != nil {
 			return nil, err
 		}
 
-		return c, nil
+		svc := kv.NewService(store)
+		if err := svc.Initialize(context.Background()); err != nil {
+			return nil, err
+		}
+		return svc, nil
 	}
 	return &http.AuthorizationService{
 		Addr:  flags.host,
@@ -331,6 +347,10 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 		}
 		filter.UserID = uID
 	}
+	if authorizationFindFlags.unusedFor > 0 {
+		since := time.Now().Add(-authorizationFindFlags.unusedFor)
+		filter.UnusedSince = &since
+	}
 
 	authorizations, _, err := s.FindAuthorizations(context.Background(), filter)
 	if err != nil {
@@ -344,6 +364,8 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"User",
 		"UserID",
+		"LastUsedAt",
+		"ExpiresAt",
 		"Permissions",
 	)
 
@@ -358,6 +380,8 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 			"Token":       a.Token,
 			"Status":      a.Status,
 			"UserID":      a.UserID.String(),
+			"LastUsedAt":  formatOptionalTime(a.LastUsedAt),
+			"ExpiresAt":   formatOptionalTime(a.ExpiresAt),
 			"Permissions": permissions,
 		})
 	}
@@ -367,6 +391,14 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// formatOptionalTime formats t for output, or returns an empty string if it is unset.
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 // AuthorizationDeleteFlags are command line args used when deleting a authorization
 type AuthorizationDeleteFlags struct {
 	id string
@@ -573,3 +605,63 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// AuthorizationRotateFlags are command line args used when rotating the token of an authorization
+type AuthorizationRotateFlags struct {
+	id          string
+	gracePeriod time.Duration
+}
+
+var authorizationRotateFlags AuthorizationRotateFlags
+
+func init() {
+	authorizationRotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the token of an authorization with a new one",
+		RunE:  wrapCheckSetup(authorizationRotateF),
+	}
+
+	authorizationRotateCmd.Flags().StringVarP(&authorizationRotateFlags.id, "id", "i", "", "The authorization ID (required)")
+	authorizationRotateCmd.MarkFlagRequired("id")
+	authorizationRotateCmd.Flags().DurationVarP(&authorizationRotateFlags.gracePeriod, "grace-period", "", 0, "How long the current token is still accepted; it is rejected right away if unset")
+
+	authorizationCmd.AddCommand(authorizationRotateCmd)
+}
+
+func authorizationRotateF(cmd *cobra.Command, args []string) error {
+	s, err := newAuthorizationService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(authorizationRotateFlags.id); err != nil {
+		return err
+	}
+
+	a, err := s.RotateAuthorization(context.Background(), id, authorizationRotateFlags.gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Token",
+		"Status",
+		"UserID",
+		"PreviousTokenExpiresAt",
+	)
+
+	w.Write(map[string]interface{}{
+		"ID":                     a.ID.String(),
+		"Token":                  a.Token,
+		"Status":                 a.Status,
+		"UserID":                 a.UserID.String(),
+		"PreviousTokenExpiresAt": formatOptionalTime(a.PreviousTokenExpiresAt),
+	})
+
+	w.Flush()
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/backup"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/signals"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Backup the data and metadata of a running server to a file",
+	Long: `Backup the data and metadata of a running server to a tar archive.
+The archive can be restored with the restore command.
+
+With --incremental-from, only the data written since a previous full or
+incremental backup is in the archive. It is restored together with the
+chain of backups it is based on.`,
+	RunE: wrapCheckSetup(backupF),
+}
+
+var backupFlags struct {
+	output          string
+	incrementalFrom string
+}
+
+func init() {
+	backupCmd.Flags().StringVarP(&backupFlags.output, "output", "o", "", "The path of the backup file (required)")
+	backupCmd.MarkFlagRequired("output")
+	backupCmd.Flags().StringVar(&backupFlags.incrementalFrom, "incremental-from", "", "The path of a previous backup file to create an incremental backup from")
+
+	influxCmd.AddCommand(backupCmd)
+}
+
+func newBackupService(f Flags) (platform.BackupService, error) {
+	if flags.local {
+		return nil, fmt.Errorf("backup is not supported with local storage")
+	}
+	return &http.BackupService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+func backupF(cmd *cobra.Command, args []string) error {
+	s, err := newBackupService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize backup service client: %v", err)
+	}
+
+	var since *platform.BackupManifest
+	if backupFlags.incrementalFrom != "" {
+		if since, err = readBackupManifest(backupFlags.incrementalFrom); err != nil {
+			return fmt.Errorf("failed to read previous backup: %v", err)
+		}
+	}
+
+	f, err := os.OpenFile(backupFlags.output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %v", err)
+	}
+
+	ctx := signals.WithStandardSignals(context.Background())
+	if since != nil {
+		err = s.IncrementalBackup(ctx, since, f)
+	} else {
+		err = s.Backup(ctx, f)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(backupFlags.output)
+		return fmt.Errorf("failed to backup: %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write backup file: %v", err)
+	}
+
+	fmt.Printf("backup written to %s\n", backupFlags.output)
+	return nil
+}
+
+func readBackupManifest(path string) (*platform.BackupManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return backup.ReadManifest(f)
+}
@@ -27,10 +27,11 @@ func bucketF(cmd *cobra.Command, args []string) {
 
 // BucketCreateFlags define the Create Command
 type BucketCreateFlags struct {
-	name      string
-	org       string
-	orgID     string
-	retention time.Duration
+	name       string
+	org        string
+	orgID      string
+	retention  time.Duration
+	schemaType string
 }
 
 var bucketCreateFlags BucketCreateFlags
@@ -46,6 +47,7 @@ func init() {
 	bucketCreateCmd.Flags().DurationVarP(&bucketCreateFlags.retention, "retention", "r", 0, "Duration in nanoseconds data will live in bucket")
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.org, "org", "o", "", "Name of the organization that owns the bucket")
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the bucket")
+	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.schemaType, "schema-type", "", "", "The schema type of the bucket (implicit or explicit)")
 	bucketCreateCmd.MarkFlagRequired("name")
 
 	bucketCmd.AddCommand(bucketCreateCmd)
@@ -71,6 +73,14 @@ func newBucketService(f Flags) (platform.BucketService, error) {
 	}, nil
 }
 
+// schemaType returns the schema type of b, which is implicit if it is not set.
+func schemaType(b *platform.Bucket) platform.SchemaType {
+	if b.SchemaType == "" {
+		return platform.SchemaTypeImplicit
+	}
+	return b.SchemaType
+}
+
 func bucketCreateF(cmd *cobra.Command, args []string) error {
 	if (bucketCreateFlags.org == "" && bucketCreateFlags.orgID == "") ||
 		(bucketCreateFlags.org != "" && bucketCreateFlags.orgID != "") {
@@ -85,6 +95,7 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 	b := &platform.Bucket{
 		Name:            bucketCreateFlags.name,
 		RetentionPeriod: bucketCreateFlags.retention,
+		SchemaType:      platform.SchemaType(bucketCreateFlags.schemaType),
 	}
 
 	if bucketCreateFlags.org != "" {
@@ -110,6 +121,7 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 		"Retention",
 		"Organization",
 		"OrganizationID",
+		"SchemaType",
 	)
 	w.Write(map[string]interface{}{
 		"ID":             b.ID.String(),
@@ -117,6 +129,7 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 		"Retention":      b.RetentionPeriod,
 		"Organization":   b.Organization,
 		"OrganizationID": b.OrganizationID.String(),
+		"SchemaType":     schemaType(b),
 	})
 	w.Flush()
 
@@ -195,6 +208,7 @@ func bucketFindF(cmd *cobra.Command, args []string) error {
 		"Retention",
 		"Organization",
 		"OrganizationID",
+		"SchemaType",
 	)
 	for _, b := range buckets {
 		w.Write(map[string]interface{}{
@@ -203,6 +217,7 @@ func bucketFindF(cmd *cobra.Command, args []string) error {
 			"Retention":      b.RetentionPeriod,
 			"Organization":   b.Organization,
 			"OrganizationID": b.OrganizationID.String(),
+			"SchemaType":     schemaType(b),
 		})
 	}
 	w.Flush()
@@ -212,9 +227,10 @@ func bucketFindF(cmd *cobra.Command, args []string) error {
 
 // BucketUpdateFlags define the Update Command
 type BucketUpdateFlags struct {
-	id        string
-	name      string
-	retention time.Duration
+	id         string
+	name       string
+	retention  time.Duration
+	schemaType string
 }
 
 var bucketUpdateFlags BucketUpdateFlags
@@ -229,6 +245,7 @@ func init() {
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.id, "id", "i", "", "The bucket ID (required)")
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.name, "name", "n", "", "New bucket name")
 	bucketUpdateCmd.Flags().DurationVarP(&bucketUpdateFlags.retention, "retention", "r", 0, "New duration data will live in bucket")
+	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.schemaType, "schema-type", "", "", "New schema type of the bucket (implicit or explicit)")
 	bucketUpdateCmd.MarkFlagRequired("id")
 
 	bucketCmd.AddCommand(bucketUpdateCmd)
@@ -252,6 +269,10 @@ func bucketUpdateF(cmd *cobra.Command, args []string) error {
 	if bucketUpdateFlags.retention != 0 {
 		update.RetentionPeriod = &bucketUpdateFlags.retention
 	}
+	if bucketUpdateFlags.schemaType != "" {
+		schemaType := platform.SchemaType(bucketUpdateFlags.schemaType)
+		update.SchemaType = &schemaType
+	}
 
 	b, err := s.UpdateBucket(context.Background(), id, update)
 	if err != nil {
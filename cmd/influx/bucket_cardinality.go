@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// BucketCardinalityFlags define the Cardinality Command
+type BucketCardinalityFlags struct {
+	id       string
+	topN     int
+	estimate bool
+}
+
+var bucketCardinalityFlags BucketCardinalityFlags
+
+func init() {
+	bucketCardinalityCmd := &cobra.Command{
+		Use:   "cardinality",
+		Short: "Show the series cardinality of a bucket",
+		RunE:  wrapCheckSetup(bucketCardinalityF),
+	}
+
+	bucketCardinalityCmd.Flags().StringVarP(&bucketCardinalityFlags.id, "id", "i", "", "The bucket ID (required)")
+	bucketCardinalityCmd.Flags().IntVarP(&bucketCardinalityFlags.topN, "top-n", "", platform.DefaultCardinalityTopN, "The number of tag values with the most series to show for each tag key")
+	bucketCardinalityCmd.Flags().BoolVarP(&bucketCardinalityFlags.estimate, "estimate", "", false, "Estimate the cardinality to bound the memory used by the server")
+	bucketCardinalityCmd.MarkFlagRequired("id")
+
+	bucketCmd.AddCommand(bucketCardinalityCmd)
+}
+
+func newCardinalityService(f Flags) (platform.CardinalityService, error) {
+	if flags.local {
+		return nil, fmt.Errorf("bucket cardinality is not supported with local storage")
+	}
+	return &http.CardinalityService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+func bucketCardinalityF(cmd *cobra.Command, args []string) error {
+	s, err := newCardinalityService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize cardinality service client: %v", err)
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(bucketCardinalityFlags.id); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketCardinalityFlags.id, err)
+	}
+
+	if bucketCardinalityFlags.topN < 1 {
+		return fmt.Errorf("top-n must be a positive integer")
+	}
+
+	opts := platform.CardinalityOptions{
+		TopN:     bucketCardinalityFlags.topN,
+		Estimate: bucketCardinalityFlags.estimate,
+	}
+	bc, err := s.FindBucketCardinality(context.Background(), id, opts)
+	if err != nil {
+		return fmt.Errorf("failed to find cardinality of bucket %q: %v", id, err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Measurement",
+		"Series",
+		"TagKey",
+		"Values",
+		"TopValues",
+	)
+	for _, m := range bc.Measurements {
+		for _, k := range m.TagKeys {
+			top := make([]string, 0, len(k.TopValues))
+			for _, v := range k.TopValues {
+				top = append(top, fmt.Sprintf("%s=%d", v.Value, v.Series))
+			}
+			w.Write(map[string]interface{}{
+				"Measurement": m.Name,
+				"Series":      m.Series,
+				"TagKey":      k.Key,
+				"Values":      k.Values,
+				"TopValues":   strings.Join(top, ","),
+			})
+		}
+	}
+	w.Flush()
+
+	if bc.Estimated {
+		fmt.Fprintf(os.Stdout, "bucket %s has about %d series (estimated)\n", bc.BucketID, bc.Series)
+	} else {
+		fmt.Fprintf(os.Stdout, "bucket %s has %d series\n", bc.BucketID, bc.Series)
+	}
+	return nil
+}
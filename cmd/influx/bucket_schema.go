@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+// Bucket Schema Command
+var bucketSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Bucket measurement schema management commands",
+	Run:   bucketF,
+}
+
+func init() {
+	bucketCmd.AddCommand(bucketSchemaCmd)
+}
+
+func newBucketSchemaService(f Flags) (platform.BucketSchemaService, error) {
+	if flags.local {
+		return nil, fmt.Errorf("bucket schemas are not supported with local storage")
+	}
+	return &http.BucketSchemaService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+// parseSchemaFields parses fields of the form name=type.
+func parseSchemaFields(fields []string) ([]platform.MeasurementSchemaField, error) {
+	fs := make([]platform.MeasurementSchemaField, 0, len(fields))
+	for _, f := range fields {
+		parts := strings.SplitN(f, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid field %q; fields must be of the form name=type", f)
+		}
+		fs = append(fs, platform.MeasurementSchemaField{
+			Name: parts[0],
+			Type: platform.SchemaFieldType(parts[1]),
+		})
+	}
+	return fs, nil
+}
+
+func writeMeasurementSchemas(ms ...*platform.MeasurementSchema) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"BucketID",
+		"Measurement",
+		"Tags",
+		"Fields",
+	)
+	for _, m := range ms {
+		fields := make([]string, 0, len(m.Fields))
+		for _, f := range m.Fields {
+			fields = append(fields, f.Name+"="+string(f.Type))
+		}
+		w.Write(map[string]interface{}{
+			"BucketID":    m.BucketID.String(),
+			"Measurement": m.Name,
+			"Tags":        strings.Join(m.Tags, ","),
+			"Fields":      strings.Join(fields, ","),
+		})
+	}
+	w.Flush()
+}
+
+// BucketSchemaCreateFlags define the Create Command
+type BucketSchemaCreateFlags struct {
+	bucketID string
+	name     string
+	tags     []string
+	fields   []string
+}
+
+var bucketSchemaCreateFlags BucketSchemaCreateFlags
+
+func init() {
+	bucketSchemaCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create the schema of a measurement",
+		RunE:  wrapCheckSetup(bucketSchemaCreateF),
+	}
+
+	bucketSchemaCreateCmd.Flags().StringVarP(&bucketSchemaCreateFlags.bucketID, "bucket-id", "", "", "The bucket ID (required)")
+	bucketSchemaCreateCmd.Flags().StringVarP(&bucketSchemaCreateFlags.name, "name", "n", "", "The measurement name (required)")
+	bucketSchemaCreateCmd.Flags().StringArrayVarP(&bucketSchemaCreateFlags.tags, "tag", "", []string{}, "A tag key every point must have")
+	bucketSchemaCreateCmd.Flags().StringArrayVarP(&bucketSchemaCreateFlags.fields, "field", "", []string{}, "A field as name=type, where type is float, integer, unsigned, string or boolean")
+	bucketSchemaCreateCmd.MarkFlagRequired("bucket-id")
+	bucketSchemaCreateCmd.MarkFlagRequired("name")
+
+	bucketSchemaCmd.AddCommand(bucketSchemaCreateCmd)
+}
+
+func bucketSchemaCreateF(cmd *cobra.Command, args []string) error {
+	s, err := newBucketSchemaService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize bucket schema service client: %v", err)
+	}
+
+	var bucketID platform.ID
+	if err := bucketID.DecodeFromString(bucketSchemaCreateFlags.bucketID); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketSchemaCreateFlags.bucketID, err)
+	}
+
+	fields, err := parseSchemaFields(bucketSchemaCreateFlags.fields)
+	if err != nil {
+		return err
+	}
+
+	m := &platform.MeasurementSchema{
+		BucketID: bucketID,
+		Name:     bucketSchemaCreateFlags.name,
+		Tags:     bucketSchemaCreateFlags.tags,
+		Fields:   fields,
+	}
+
+	if err := s.CreateMeasurementSchema(context.Background(), m); err != nil {
+		return fmt.Errorf("failed to create measurement schema: %v", err)
+	}
+
+	writeMeasurementSchemas(m)
+	return nil
+}
+
+// BucketSchemaFindFlags define the Find Command
+type BucketSchemaFindFlags struct {
+	bucketID string
+	name     string
+}
+
+var bucketSchemaFindFlags BucketSchemaFindFlags
+
+func init() {
+	bucketSchemaFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find measurement schemas",
+		RunE:  wrapCheckSetup(bucketSchemaFindF),
+	}
+
+	bucketSchemaFindCmd.Flags().StringVarP(&bucketSchemaFindFlags.bucketID, "bucket-id", "", "", "The bucket ID (required)")
+	bucketSchemaFindCmd.Flags().StringVarP(&bucketSchemaFindFlags.name, "name", "n", "", "The measurement name")
+	bucketSchemaFindCmd.MarkFlagRequired("bucket-id")
+
+	bucketSchemaCmd.AddCommand(bucketSchemaFindCmd)
+}
+
+func bucketSchemaFindF(cmd *cobra.Command, args []string) error {
+	s, err := newBucketSchemaService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize bucket schema service client: %v", err)
+	}
+
+	var bucketID platform.ID
+	if err := bucketID.DecodeFromString(bucketSchemaFindFlags.bucketID); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketSchemaFindFlags.bucketID, err)
+	}
+
+	ctx := context.Background()
+	if bucketSchemaFindFlags.name != "" {
+		m, err := s.FindMeasurementSchema(ctx, bucketID, bucketSchemaFindFlags.name)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve measurement schema: %v", err)
+		}
+		writeMeasurementSchemas(m)
+		return nil
+	}
+
+	ms, err := s.FindMeasurementSchemas(ctx, bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve measurement schemas: %v", err)
+	}
+	writeMeasurementSchemas(ms...)
+	return nil
+}
+
+// BucketSchemaUpdateFlags define the Update Command
+type BucketSchemaUpdateFlags struct {
+	bucketID string
+	name     string
+	tags     []string
+	fields   []string
+}
+
+var bucketSchemaUpdateFlags BucketSchemaUpdateFlags
+
+func init() {
+	bucketSchemaUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the schema of a measurement",
+		Long:  "Update the schema of a measurement. Fields replace the existing fields; existing fields cannot be removed or change type.",
+		RunE:  wrapCheckSetup(bucketSchemaUpdateF),
+	}
+
+	bucketSchemaUpdateCmd.Flags().StringVarP(&bucketSchemaUpdateFlags.bucketID, "bucket-id", "", "", "The bucket ID (required)")
+	bucketSchemaUpdateCmd.Flags().StringVarP(&bucketSchemaUpdateFlags.name, "name", "n", "", "The measurement name (required)")
+	bucketSchemaUpdateCmd.Flags().StringArrayVarP(&bucketSchemaUpdateFlags.tags, "tag", "", []string{}, "A tag key every point must have")
+	bucketSchemaUpdateCmd.Flags().StringArrayVarP(&bucketSchemaUpdateFlags.fields, "field", "", []string{}, "A field as name=type, where type is float, integer, unsigned, string or boolean")
+	bucketSchemaUpdateCmd.MarkFlagRequired("bucket-id")
+	bucketSchemaUpdateCmd.MarkFlagRequired("name")
+
+	bucketSchemaCmd.AddCommand(bucketSchemaUpdateCmd)
+}
+
+func bucketSchemaUpdateF(cmd *cobra.Command, args []string) error {
+	s, err := newBucketSchemaService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize bucket schema service client: %v", err)
+	}
+
+	var bucketID platform.ID
+	if err := bucketID.DecodeFromString(bucketSchemaUpdateFlags.bucketID); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketSchemaUpdateFlags.bucketID, err)
+	}
+
+	upd := platform.MeasurementSchemaUpdate{}
+	if cmd.Flags().Changed("tag") {
+		upd.Tags = bucketSchemaUpdateFlags.tags
+	}
+	if cmd.Flags().Changed("field") {
+		if upd.Fields, err = parseSchemaFields(bucketSchemaUpdateFlags.fields); err != nil {
+			return err
+		}
+	}
+
+	m, err := s.UpdateMeasurementSchema(context.Background(), bucketID, bucketSchemaUpdateFlags.name, upd)
+	if err != nil {
+		return fmt.Errorf("failed to update measurement schema: %v", err)
+	}
+
+	writeMeasurementSchemas(m)
+	return nil
+}
+
+// BucketSchemaDeleteFlags define the Delete Command
+type BucketSchemaDeleteFlags struct {
+	bucketID string
+	name     string
+}
+
+var bucketSchemaDeleteFlags BucketSchemaDeleteFlags
+
+func init() {
+	bucketSchemaDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete the schema of a measurement",
+		RunE:  wrapCheckSetup(bucketSchemaDeleteF),
+	}
+
+	bucketSchemaDeleteCmd.Flags().StringVarP(&bucketSchemaDeleteFlags.bucketID, "bucket-id", "", "", "The bucket ID (required)")
+	bucketSchemaDeleteCmd.Flags().StringVarP(&bucketSchemaDeleteFlags.name, "name", "n", "", "The measurement name (required)")
+	bucketSchemaDeleteCmd.MarkFlagRequired("bucket-id")
+	bucketSchemaDeleteCmd.MarkFlagRequired("name")
+
+	bucketSchemaCmd.AddCommand(bucketSchemaDeleteCmd)
+}
+
+func bucketSchemaDeleteF(cmd *cobra.Command, args []string) error {
+	s, err := newBucketSchemaService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize bucket schema service client: %v", err)
+	}
+
+	var bucketID platform.ID
+	if err := bucketID.DecodeFromString(bucketSchemaDeleteFlags.bucketID); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketSchemaDeleteFlags.bucketID, err)
+	}
+
+	ctx := context.Background()
+	m, err := s.FindMeasurementSchema(ctx, bucketID, bucketSchemaDeleteFlags.name)
+	if err != nil {
+		return fmt.Errorf("failed to find measurement schema %q: %v", bucketSchemaDeleteFlags.name, err)
+	}
+
+	if err := s.DeleteMeasurementSchema(ctx, bucketID, bucketSchemaDeleteFlags.name); err != nil {
+		return fmt.Errorf("failed to delete measurement schema %q: %v", bucketSchemaDeleteFlags.name, err)
+	}
+
+	writeMeasurementSchemas(m)
+	return nil
+}
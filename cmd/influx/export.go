@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/signals"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the data of a bucket as line protocol or annotated CSV",
+	Long: `Export the data of a bucket as line protocol or as Flux annotated CSV.
+The data can be written to a bucket with the import command.
+
+The series of the bucket are exported in order. With --resume, an export
+which was interrupted is resumed after the last complete line of the output
+file.`,
+	RunE: wrapCheckSetup(exportF),
+}
+
+var exportFlags struct {
+	bucketID string
+	format   string
+	start    string
+	stop     string
+	output   string
+	resume   bool
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFlags.bucketID, "bucket-id", "i", "", "The ID of the bucket to export (required)")
+	exportCmd.MarkFlagRequired("bucket-id")
+	exportCmd.Flags().StringVarP(&exportFlags.format, "format", "f", string(platform.ExportFormatLineProtocol), "The format of the export, lp for line protocol or csv for annotated CSV")
+	exportCmd.Flags().StringVar(&exportFlags.start, "start", "", "The RFC3339 time of the earliest points to export")
+	exportCmd.Flags().StringVar(&exportFlags.stop, "stop", "", "The RFC3339 time before which points are exported")
+	exportCmd.Flags().StringVarP(&exportFlags.output, "output", "o", "", "The path of the export file; the export is written to stdout by default")
+	exportCmd.Flags().BoolVar(&exportFlags.resume, "resume", false, "Resume an interrupted export to the output file")
+
+	influxCmd.AddCommand(exportCmd)
+}
+
+func newExportService(f Flags) (platform.ExportService, error) {
+	if flags.local {
+		return nil, fmt.Errorf("export is not supported with local storage")
+	}
+	return &http.ExportService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}, nil
+}
+
+func exportF(cmd *cobra.Command, args []string) error {
+	s, err := newExportService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize export service client: %v", err)
+	}
+
+	id, err := platform.IDFromString(exportFlags.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket-id: %v", err)
+	}
+
+	filter := platform.ExportFilter{
+		BucketID: *id,
+		Format:   platform.ExportFormat(exportFlags.format),
+	}
+	if err := filter.Format.Valid(); err != nil {
+		return err
+	}
+	if exportFlags.start != "" {
+		if filter.Start, err = time.Parse(time.RFC3339Nano, exportFlags.start); err != nil {
+			return fmt.Errorf("failed to parse start: %v", err)
+		}
+	}
+	if exportFlags.stop != "" {
+		if filter.Stop, err = time.Parse(time.RFC3339Nano, exportFlags.stop); err != nil {
+			return fmt.Errorf("failed to parse stop: %v", err)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if exportFlags.output != "" {
+		flag := os.O_CREATE | os.O_EXCL | os.O_WRONLY
+		if exportFlags.resume {
+			flag = os.O_CREATE | os.O_RDWR
+		}
+		f, err := os.OpenFile(exportFlags.output, flag, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %v", err)
+		}
+		defer f.Close()
+
+		if exportFlags.resume {
+			if filter.After, err = resumeExport(f, filter.Format); err != nil {
+				return fmt.Errorf("failed to resume export: %v", err)
+			}
+		}
+		w = f
+	} else if exportFlags.resume {
+		return fmt.Errorf("an export can only be resumed to an output file")
+	}
+
+	ctx := signals.WithStandardSignals(context.Background())
+	if err := s.ExportBucket(ctx, filter, w); err != nil {
+		return fmt.Errorf("failed to export: %v", err)
+	}
+	return nil
+}
+
+// resumeExport truncates the export file f after its last complete line, and
+// returns the position of the last point and field of the export, which is nil
+// if the export is empty. The offset of f is set to its end.
+func resumeExport(f *os.File, format platform.ExportFormat) (*platform.ExportPosition, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := lastNewline(f, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(end + 1); err != nil {
+		return nil, err
+	}
+	if end < 0 {
+		return nil, nil
+	}
+
+	// The last line of line protocol is the last point of the export. The rows
+	// of annotated CSV depend on the header of their table, so the file is
+	// converted to line protocol to find the last point.
+	var line []byte
+	switch format {
+	case platform.ExportFormatLineProtocol:
+		start, err := lastNewline(f, end)
+		if err != nil {
+			return nil, err
+		}
+		line = make([]byte, end-start-1)
+		if _, err := f.ReadAt(line, start+1); err != nil {
+			return nil, err
+		}
+	case platform.ExportFormatCSV:
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(storage.NewAnnotatedCSVReader(f))
+		if err != nil {
+			return nil, err
+		}
+		b = bytes.TrimSuffix(b, []byte("\n"))
+		line = b[bytes.LastIndexByte(b, '\n')+1:]
+	}
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, nil
+	}
+
+	points, err := models.ParsePoints(line)
+	if err != nil {
+		return nil, fmt.Errorf("invalid last line %q: %v", line, err)
+	}
+	p := points[0]
+	fields := p.FieldIterator()
+	if !fields.Next() {
+		return nil, fmt.Errorf("invalid last line %q", line)
+	}
+	return &platform.ExportPosition{
+		Key:   string(p.Key()),
+		Field: string(fields.FieldKey()),
+		Time:  p.Time(),
+	}, nil
+}
+
+// lastNewline returns the offset of the last newline in f before offset end,
+// or -1 if there is none.
+func lastNewline(f *os.File, end int64) (int64, error) {
+	buf := make([]byte, 64*1024)
+	for end > 0 {
+		n := int64(len(buf))
+		if n > end {
+			n = end
+		}
+		if _, err := f.ReadAt(buf[:n], end-n); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(buf[:n], '\n'); i >= 0 {
+			return end - n + int64(i), nil
+		}
+		end -= n
+	}
+	return -1, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/signals"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/write"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [flags] <path or - for stdin>",
+	Short: "Import the data of an export into a bucket",
+	Long: `Import line protocol or Flux annotated CSV, such as the data of a
+bucket written by the export command, into a bucket.
+
+The data is parsed in the same way as a write. Annotated CSV must have the
+_measurement, _field, _value and _time columns; other columns which are not
+part of a Flux result are imported as tags.`,
+	Args: cobra.ExactArgs(1),
+	RunE: wrapCheckSetup(importF),
+}
+
+var importFlags struct {
+	OrgID     string
+	Org       string
+	BucketID  string
+	Bucket    string
+	Format    string
+	Precision string
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFlags.OrgID, "org-id", "", "The ID of the organization that owns the bucket")
+	importCmd.Flags().StringVarP(&importFlags.Org, "org", "o", "", "The name of the organization that owns the bucket")
+	importCmd.Flags().StringVar(&importFlags.BucketID, "bucket-id", "", "The ID of destination bucket")
+	importCmd.Flags().StringVarP(&importFlags.Bucket, "bucket", "b", "", "The name of destination bucket")
+	importCmd.Flags().StringVarP(&importFlags.Format, "format", "f", string(platform.ExportFormatLineProtocol), "The format of the data, lp for line protocol or csv for annotated CSV")
+	importCmd.Flags().StringVarP(&importFlags.Precision, "precision", "p", "ns", "Precision of the timestamps of line protocol")
+
+	influxCmd.AddCommand(importCmd)
+}
+
+func importF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if importFlags.Org != "" && importFlags.OrgID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of org or org-id")
+	}
+
+	if importFlags.Bucket != "" && importFlags.BucketID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	format := platform.ExportFormat(importFlags.Format)
+	if err := format.Valid(); err != nil {
+		cmd.Usage()
+		return err
+	}
+
+	if !models.ValidPrecision(importFlags.Precision) {
+		cmd.Usage()
+		return fmt.Errorf("invalid precision")
+	}
+
+	bucket, err := findWriteBucket(ctx, importFlags.Org, importFlags.OrgID, importFlags.Bucket, importFlags.BucketID)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open %q: %v", args[0], err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var s platform.WriteService = &http.WriteService{
+		Addr:      flags.host,
+		Token:     flags.token,
+		Precision: importFlags.Precision,
+		Format:    format,
+	}
+	// Line protocol is imported in batches of lines. Annotated CSV is streamed
+	// in a single request, as its rows depend on the annotations of their table.
+	if format == platform.ExportFormatLineProtocol {
+		s = &write.Batcher{Service: s}
+	}
+
+	ctx = signals.WithStandardSignals(ctx)
+	if err := s.Write(ctx, bucket.OrganizationID, bucket.ID, r); err != nil && err != context.Canceled {
+		return writeError(err)
+	}
+
+	return nil
+}
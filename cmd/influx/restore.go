@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/backup"
+	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/internal/fs"
+	"github.com/influxdata/influxdb/kit/signals"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/models"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a backup created with the backup command",
+	Long: `Restore a backup created with the backup command.
+
+With --full, the data and metadata of the backup are restored to the
+bolt and engine paths of a server which is not running, and which has no
+data. The index of the engine is rebuilt from the restored data.
+
+With --bucket-id, the data of a single bucket of the backup is written to
+a new bucket of a running server.
+
+Incremental backups are restored by restoring the full backup they are
+based on with --input, and each incremental backup of the chain, oldest
+first, with --incremental.`,
+	RunE: restoreF,
+}
+
+var restoreFlags struct {
+	input        string
+	incrementals []string
+
+	full       bool
+	boltPath   string
+	enginePath string
+
+	bucketID  string
+	newBucket string
+	orgID     string
+}
+
+func init() {
+	restoreCmd.Flags().StringVarP(&restoreFlags.input, "input", "i", "", "The path of the backup file (required)")
+	restoreCmd.MarkFlagRequired("input")
+	restoreCmd.Flags().StringSliceVar(&restoreFlags.incrementals, "incremental", nil, "The path of an incremental backup file to apply, oldest first; may be repeated")
+
+	dir, _ := fs.InfluxDir()
+	restoreCmd.Flags().BoolVar(&restoreFlags.full, "full", false, "Restore the data and metadata of the backup to a stopped server")
+	restoreCmd.Flags().StringVar(&restoreFlags.boltPath, "bolt-path", filepath.Join(dir, "influxd.bolt"), "The path of the boltdb database of the server, with --full")
+	restoreCmd.Flags().StringVar(&restoreFlags.enginePath, "engine-path", filepath.Join(dir, "engine"), "The path of the engine files of the server, with --full")
+
+	restoreCmd.Flags().StringVar(&restoreFlags.bucketID, "bucket-id", "", "The ID of the bucket of the backup to restore")
+	restoreCmd.Flags().StringVar(&restoreFlags.newBucket, "new-bucket", "", "The name of the bucket to restore to, with --bucket-id")
+	restoreCmd.Flags().StringVar(&restoreFlags.orgID, "org-id", "", "The ID of the organization of the new bucket, with --bucket-id; defaults to the organization of the backed up bucket")
+
+	influxCmd.AddCommand(restoreCmd)
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	if restoreFlags.full == (restoreFlags.bucketID != "") {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of full or bucket-id")
+	}
+
+	f, err := os.Open(restoreFlags.input)
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %v", err)
+	}
+	defer f.Close()
+
+	dir, err := ioutil.TempDir("", "influx-restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	m, err := backup.Extract(f, dir)
+	if err != nil {
+		return fmt.Errorf("failed to extract backup: %v", err)
+	}
+	if m.Incremental() {
+		return fmt.Errorf("%s is an incremental backup; please specify the full backup it is based on as input", restoreFlags.input)
+	}
+
+	for _, p := range restoreFlags.incrementals {
+		if m, err = applyIncrementalBackup(dir, m, p); err != nil {
+			return fmt.Errorf("failed to apply incremental backup %s: %v", p, err)
+		}
+	}
+
+	if restoreFlags.full {
+		return restoreFull(dir, m)
+	}
+	return wrapCheckSetup(func(cmd *cobra.Command, args []string) error {
+		return restoreBucket(dir, m)
+	})(cmd, args)
+}
+
+// applyIncrementalBackup applies the incremental backup at path to the backup
+// m extracted in dir.
+func applyIncrementalBackup(dir string, m *platform.BackupManifest, path string) (*platform.BackupManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	incDir, err := ioutil.TempDir("", "influx-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(incDir)
+
+	inc, err := backup.Extract(f, incDir)
+	if err != nil {
+		return nil, err
+	}
+	return backup.ApplyIncremental(dir, m, incDir, inc)
+}
+
+func restoreFull(dir string, m *platform.BackupManifest) error {
+	if err := backup.RestoreKV(dir, m, restoreFlags.boltPath); err != nil {
+		return fmt.Errorf("failed to restore metadata: %v", err)
+	}
+
+	log, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+	if err := backup.RestoreEngine(dir, m, restoreFlags.enginePath, log); err != nil {
+		return fmt.Errorf("failed to restore engine: %v", err)
+	}
+
+	fmt.Printf("restored backup of %s to %s and %s\n", m.CreatedAt, restoreFlags.boltPath, restoreFlags.enginePath)
+	return nil
+}
+
+// restoreBucketBatchSize is the number of points written in each request when
+// restoring a bucket.
+const restoreBucketBatchSize = 5000
+
+func restoreBucket(dir string, m *platform.BackupManifest) error {
+	if flags.local {
+		return fmt.Errorf("restoring a bucket is not supported with local storage")
+	}
+	if restoreFlags.newBucket == "" {
+		return fmt.Errorf("please specify the name of the new bucket")
+	}
+
+	ctx := signals.WithStandardSignals(context.Background())
+
+	srcID, err := platform.IDFromString(restoreFlags.bucketID)
+	if err != nil {
+		return fmt.Errorf("failed to decode bucket-id: %v", err)
+	}
+
+	src, err := findBackupBucket(ctx, filepath.Join(dir, filepath.FromSlash(m.KV)), *srcID)
+	if err != nil {
+		return fmt.Errorf("failed to find bucket %q in backup: %v", srcID, err)
+	}
+
+	b := &platform.Bucket{
+		OrganizationID:  src.OrganizationID,
+		Name:            restoreFlags.newBucket,
+		RetentionPeriod: src.RetentionPeriod,
+	}
+	if restoreFlags.orgID != "" {
+		orgID, err := platform.IDFromString(restoreFlags.orgID)
+		if err != nil {
+			return fmt.Errorf("failed to decode org-id: %v", err)
+		}
+		b.OrganizationID = *orgID
+	}
+
+	bs := &http.BucketService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	if err := bs.CreateBucket(ctx, b); err != nil {
+		return fmt.Errorf("failed to create bucket: %v", err)
+	}
+
+	ws := &http.WriteService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	var n int
+	var buf bytes.Buffer
+	if err := backup.ReadBucketPoints(ctx, dir, m, *srcID, restoreBucketBatchSize, func(points []models.Point) error {
+		buf.Reset()
+		for _, p := range points {
+			buf.WriteString(p.String())
+			buf.WriteByte('\n')
+		}
+		if err := ws.Write(ctx, b.OrganizationID, b.ID, &buf); err != nil {
+			return err
+		}
+		n += len(points)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to restore data of bucket %q after %d points: %v", srcID, n, err)
+	}
+
+	fmt.Printf("restored %d points of bucket %s to bucket %s (%s)\n", n, srcID, b.Name, b.ID)
+	return nil
+}
+
+// findBackupBucket finds the bucket with ID id in the metadata store of a
+// backup at path.
+func findBackupBucket(ctx context.Context, path string, id platform.ID) (*platform.Bucket, error) {
+	store := bolt.NewKVStore(path)
+	if err := store.Open(ctx); err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	return kv.NewService(store).FindBucketByID(ctx, id)
+}
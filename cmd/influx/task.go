@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
@@ -55,8 +58,12 @@ func init() {
 
 // TaskCreateFlags define the Create Command
 type TaskCreateFlags struct {
-	org   string
-	orgID string
+	org     string
+	orgID   string
+	dryRun  bool
+	execute bool
+	runs    int
+	now     string
 }
 
 var taskCreateFlags TaskCreateFlags
@@ -71,6 +78,10 @@ func init() {
 
 	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.org, "org", "", "", "organization name")
 	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.orgID, "org-id", "", "", "id of the organization that owns the task")
+	taskCreateCmd.Flags().BoolVarP(&taskCreateFlags.dryRun, "dry-run", "", false, "validate the task and show its next runs without creating it")
+	taskCreateCmd.Flags().BoolVarP(&taskCreateFlags.execute, "execute", "", false, "with --dry-run, run the task once without writing the output of to()")
+	taskCreateCmd.Flags().IntVarP(&taskCreateFlags.runs, "runs", "", platform.TaskValidationDefaultRuns, "with --dry-run, the number of next runs to show")
+	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.now, "now", "", "", "with --execute, the RFC3339 time the run is scheduled for (default the current time)")
 	taskCreateCmd.MarkFlagRequired("flux")
 
 	taskCmd.AddCommand(taskCreateCmd)
@@ -103,6 +114,10 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		tc.OrganizationID = *oid
 	}
 
+	if taskCreateFlags.dryRun {
+		return taskValidate(s, tc)
+	}
+
 	t, err := s.CreateTask(context.Background(), tc)
 	if err != nil {
 		return err
@@ -134,13 +149,103 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// taskValidate validates the task tc would create, and prints the validation result.
+func taskValidate(s *http.TaskService, tc platform.TaskCreate) error {
+	if taskCreateFlags.runs < 1 || taskCreateFlags.runs > platform.TaskValidationMaxRuns {
+		return fmt.Errorf("runs must be between 1 and %d", platform.TaskValidationMaxRuns)
+	}
+
+	res, err := s.ValidateTask(context.Background(), platform.TaskValidation{
+		Flux:           tc.Flux,
+		OrganizationID: tc.OrganizationID,
+		Organization:   tc.Organization,
+		Runs:           taskCreateFlags.runs,
+		Execute:        taskCreateFlags.execute,
+		Now:            taskCreateFlags.now,
+	})
+	if err != nil {
+		return err
+	}
+
+	dependsOn := make([]string, len(res.DependsOn))
+	for i, id := range res.DependsOn {
+		dependsOn[i] = id.String()
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Name",
+		"Every",
+		"Cron",
+		"Offset",
+		"Concurrency",
+		"Retry",
+		"DependsOn",
+	)
+	w.Write(map[string]interface{}{
+		"Name":        res.Name,
+		"Every":       res.Every,
+		"Cron":        res.Cron,
+		"Offset":      res.Offset,
+		"Concurrency": res.Concurrency,
+		"Retry":       res.Retry,
+		"DependsOn":   strings.Join(dependsOn, ","),
+	})
+	w.Flush()
+
+	fmt.Println()
+	w = internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders("Permission")
+	for _, p := range res.Permissions {
+		w.Write(map[string]interface{}{
+			"Permission": p.String(),
+		})
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders("NextRun")
+	for _, r := range res.NextRuns {
+		w.Write(map[string]interface{}{
+			"NextRun": r,
+		})
+	}
+	w.Flush()
+
+	if ex := res.Execution; ex != nil {
+		fmt.Println()
+		w = internal.NewTabWriter(os.Stdout)
+		w.WriteHeaders(
+			"Result",
+			"Tables",
+			"Rows",
+		)
+		for _, r := range ex.Results {
+			w.Write(map[string]interface{}{
+				"Result": r.Name,
+				"Tables": r.Tables,
+				"Rows":   r.Rows,
+			})
+		}
+		w.Flush()
+
+		if ex.Error != "" {
+			return fmt.Errorf("run scheduled for %s failed: %s", ex.ScheduledFor, ex.Error)
+		}
+	}
+
+	return nil
+}
+
 // taskFindFlags define the Find Command
 type TaskFindFlags struct {
-	user  string
-	id    string
-	org   string
-	orgID string
-	limit int
+	user      string
+	id        string
+	org       string
+	orgID     string
+	dependsOn string
+	limit     int
 }
 
 var taskFindFlags TaskFindFlags
@@ -156,6 +261,7 @@ func init() {
 	taskFindCmd.Flags().StringVarP(&taskFindFlags.user, "user-id", "n", "", "task owner ID")
 	taskFindCmd.Flags().StringVarP(&taskFindFlags.org, "org", "", "", "task organization name")
 	taskFindCmd.Flags().StringVarP(&taskFindFlags.orgID, "org-id", "", "", "task organization ID")
+	taskFindCmd.Flags().StringVarP(&taskFindFlags.dependsOn, "depends-on", "", "", "find the tasks which depend on this task ID")
 	taskFindCmd.Flags().IntVarP(&taskFindFlags.limit, "limit", "", platform.TaskDefaultPageSize, "the number of tasks to find")
 
 	taskCmd.AddCommand(taskFindCmd)
@@ -186,6 +292,13 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 		}
 		filter.OrganizationID = id
 	}
+	if taskFindFlags.dependsOn != "" {
+		id, err := platform.IDFromString(taskFindFlags.dependsOn)
+		if err != nil {
+			return err
+		}
+		filter.DependsOn = id
+	}
 
 	if taskFindFlags.limit < 1 || taskFindFlags.limit > platform.TaskMaxPageSize {
 		return fmt.Errorf("limit must be between 1 and %d", platform.TaskMaxPageSize)
@@ -224,8 +337,13 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"DependsOn",
 	)
 	for _, t := range tasks {
+		dependsOn := make([]string, len(t.DependsOn))
+		for i, id := range t.DependsOn {
+			dependsOn[i] = id.String()
+		}
 		w.Write(map[string]interface{}{
 			"ID":              t.ID.String(),
 			"Name":            t.Name,
@@ -235,6 +353,7 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 			"Status":          t.Status,
 			"Every":           t.Every,
 			"Cron":            t.Cron,
+			"DependsOn":       strings.Join(dependsOn, ","),
 		})
 	}
 	w.Flush()
@@ -438,11 +557,25 @@ func taskLogFindF(cmd *cobra.Command, args []string) error {
 
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
-		"Log",
+		"RunID",
+		"Time",
+		"Level",
+		"Message",
+		"Fields",
 	)
 	for _, log := range logs {
+		fields := make([]string, 0, len(log.Fields))
+		for k, v := range log.Fields {
+			fields = append(fields, fmt.Sprintf("%s=%q", k, v))
+		}
+		sort.Strings(fields)
+
 		w.Write(map[string]interface{}{
-			"Log": *log,
+			"RunID":   log.RunID,
+			"Time":    log.Time,
+			"Level":   string(log.Level),
+			"Message": log.Message,
+			"Fields":  strings.Join(fields, " "),
 		})
 	}
 	w.Flush()
@@ -523,9 +656,14 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 		"StartedAt",
 		"FinishedAt",
 		"RequestedAt",
+		"TotalDuration",
+		"QueueDuration",
+		"ExecuteDuration",
+		"MaxAllocated",
+		"RowsWritten",
 	)
 	for _, r := range runs {
-		w.Write(map[string]interface{}{
+		row := map[string]interface{}{
 			"ID":           r.ID,
 			"TaskID":       r.TaskID,
 			"Status":       r.Status,
@@ -533,7 +671,22 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 			"StartedAt":    r.StartedAt,
 			"FinishedAt":   r.FinishedAt,
 			"RequestedAt":  r.RequestedAt,
-		})
+
+			// Statistics are only known once a run has finished executing.
+			"TotalDuration":   "",
+			"QueueDuration":   "",
+			"ExecuteDuration": "",
+			"MaxAllocated":    "",
+			"RowsWritten":     "",
+		}
+		if st := r.Statistics; st != nil {
+			row["TotalDuration"] = st.TotalDuration.String()
+			row["QueueDuration"] = st.QueueDuration.String()
+			row["ExecuteDuration"] = st.ExecuteDuration.String()
+			row["MaxAllocated"] = st.MaxAllocated
+			row["RowsWritten"] = st.RowsWritten
+		}
+		w.Write(row)
 	}
 	w.Flush()
 
@@ -585,3 +738,317 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// TaskBackfillFlags define the Backfill Command
+type TaskBackfillFlags struct {
+	taskID     string
+	backfillID string
+	start      string
+	stop       string
+}
+
+var taskBackfillFlags TaskBackfillFlags
+
+func init() {
+	backfillCmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Run a task for each of its schedules in a time range",
+		Long: `Run a task for each of its schedules from start to stop, as the concurrency
+of the task allows. Backfills which still have runs to be created can be
+listed, paused, resumed and canceled with the subcommands.`,
+		RunE: wrapCheckSetup(taskBackfillF),
+	}
+
+	backfillCmd.Flags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+	backfillCmd.Flags().StringVarP(&taskBackfillFlags.start, "start", "", "", "RFC3339 time of the earliest schedule to run (required)")
+	backfillCmd.Flags().StringVarP(&taskBackfillFlags.stop, "stop", "", "", "RFC3339 time of the latest schedule to run (required)")
+	backfillCmd.MarkFlagRequired("task-id")
+	backfillCmd.MarkFlagRequired("start")
+	backfillCmd.MarkFlagRequired("stop")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the backfills of a task",
+		RunE:  wrapCheckSetup(taskBackfillListF),
+	}
+	listCmd.Flags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+	listCmd.MarkFlagRequired("task-id")
+	backfillCmd.AddCommand(listCmd)
+
+	for _, c := range []struct {
+		use, short string
+		fn         func(*cobra.Command, []string) error
+	}{
+		{"pause", "Pause a backfill", taskBackfillStatusF(platform.BackfillStatusPaused)},
+		{"resume", "Resume a paused backfill", taskBackfillStatusF(platform.BackfillStatusActive)},
+		{"cancel", "Cancel a backfill and its runs in progress", taskBackfillCancelF},
+	} {
+		cmd := &cobra.Command{
+			Use:   c.use,
+			Short: c.short,
+			RunE:  wrapCheckSetup(c.fn),
+		}
+		cmd.Flags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+		cmd.Flags().StringVarP(&taskBackfillFlags.backfillID, "backfill-id", "b", "", "backfill id (required)")
+		cmd.MarkFlagRequired("task-id")
+		cmd.MarkFlagRequired("backfill-id")
+		backfillCmd.AddCommand(cmd)
+	}
+
+	taskCmd.AddCommand(backfillCmd)
+}
+
+func taskBackfillF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		return err
+	}
+	start, err := time.Parse(time.RFC3339, taskBackfillFlags.start)
+	if err != nil {
+		return fmt.Errorf("failed to parse start: %v", err)
+	}
+	stop, err := time.Parse(time.RFC3339, taskBackfillFlags.stop)
+	if err != nil {
+		return fmt.Errorf("failed to parse stop: %v", err)
+	}
+
+	b, err := s.CreateBackfill(context.Background(), taskID, start.Unix(), stop.Unix())
+	if err != nil {
+		return err
+	}
+
+	writeBackfills(b)
+	return nil
+}
+
+func taskBackfillListF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		return err
+	}
+
+	backfills, err := s.FindBackfills(context.Background(), taskID)
+	if err != nil {
+		return err
+	}
+
+	writeBackfills(backfills...)
+	return nil
+}
+
+// taskBackfillStatusF returns the function of a command which sets the status of a backfill.
+func taskBackfillStatusF(status string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		s := &http.TaskService{
+			Addr:  flags.host,
+			Token: flags.token,
+		}
+
+		var taskID, backfillID platform.ID
+		if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+			return err
+		}
+		if err := backfillID.DecodeFromString(taskBackfillFlags.backfillID); err != nil {
+			return err
+		}
+
+		b, err := s.UpdateBackfill(context.Background(), taskID, backfillID, platform.BackfillUpdate{Status: &status})
+		if err != nil {
+			return err
+		}
+
+		writeBackfills(b)
+		return nil
+	}
+}
+
+func taskBackfillCancelF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID, backfillID platform.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		return err
+	}
+	if err := backfillID.DecodeFromString(taskBackfillFlags.backfillID); err != nil {
+		return err
+	}
+
+	if err := s.CancelBackfill(context.Background(), taskID, backfillID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backfill %s of task %s canceled.\n", backfillID, taskID)
+	return nil
+}
+
+func writeBackfills(backfills ...*platform.Backfill) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"TaskID",
+		"Status",
+		"Start",
+		"Stop",
+		"LatestCompleted",
+		"Progress",
+	)
+	for _, b := range backfills {
+		w.Write(map[string]interface{}{
+			"ID":              b.ID,
+			"TaskID":          b.TaskID,
+			"Status":          b.Status,
+			"Start":           b.Start,
+			"Stop":            b.Stop,
+			"LatestCompleted": b.LatestCompleted,
+			"Progress":        fmt.Sprintf("%d/%d", b.RunsCompleted, b.RunsTotal),
+		})
+	}
+	w.Flush()
+}
+
+// TaskRevisionFlags define the Revision Command
+type TaskRevisionFlags struct {
+	taskID   string
+	revision int64
+	from     int64
+}
+
+var taskRevisionFlags TaskRevisionFlags
+
+func init() {
+	revisionCmd := &cobra.Command{
+		Use:   "revision",
+		Short: "List, compare and roll back to revisions of the script of a task",
+		RunE:  wrapCheckSetup(taskF),
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the revisions of the script of a task",
+		RunE:  wrapCheckSetup(taskRevisionListF),
+	}
+	listCmd.Flags().StringVarP(&taskRevisionFlags.taskID, "task-id", "i", "", "task id (required)")
+	listCmd.MarkFlagRequired("task-id")
+	revisionCmd.AddCommand(listCmd)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the script of a revision to the script of another revision",
+		RunE:  wrapCheckSetup(taskRevisionDiffF),
+	}
+	diffCmd.Flags().StringVarP(&taskRevisionFlags.taskID, "task-id", "i", "", "task id (required)")
+	diffCmd.Flags().Int64VarP(&taskRevisionFlags.revision, "revision", "r", 0, "revision to compare (required)")
+	diffCmd.Flags().Int64VarP(&taskRevisionFlags.from, "from", "", 0, "revision to compare to, defaults to the previous revision")
+	diffCmd.MarkFlagRequired("task-id")
+	diffCmd.MarkFlagRequired("revision")
+	revisionCmd.AddCommand(diffCmd)
+
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore the script of a revision, recording it as a new revision",
+		RunE:  wrapCheckSetup(taskRevisionRollbackF),
+	}
+	rollbackCmd.Flags().StringVarP(&taskRevisionFlags.taskID, "task-id", "i", "", "task id (required)")
+	rollbackCmd.Flags().Int64VarP(&taskRevisionFlags.revision, "revision", "r", 0, "revision to restore (required)")
+	rollbackCmd.MarkFlagRequired("task-id")
+	rollbackCmd.MarkFlagRequired("revision")
+	revisionCmd.AddCommand(rollbackCmd)
+
+	taskCmd.AddCommand(revisionCmd)
+}
+
+func taskRevisionListF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskRevisionFlags.taskID); err != nil {
+		return err
+	}
+
+	revs, err := s.FindTaskRevisions(context.Background(), taskID)
+	if err != nil {
+		return err
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Revision",
+		"Name",
+		"Every",
+		"Cron",
+		"UpdatedBy",
+		"UpdatedAt",
+	)
+	for _, r := range revs {
+		var updatedBy string
+		if r.UpdatedBy.Valid() {
+			updatedBy = r.UpdatedBy.String()
+		}
+		w.Write(map[string]interface{}{
+			"Revision":  r.Revision,
+			"Name":      r.Name,
+			"Every":     r.Every,
+			"Cron":      r.Cron,
+			"UpdatedBy": updatedBy,
+			"UpdatedAt": r.UpdatedAt,
+		})
+	}
+	w.Flush()
+	return nil
+}
+
+func taskRevisionDiffF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskRevisionFlags.taskID); err != nil {
+		return err
+	}
+
+	d, err := s.DiffTaskRevisions(context.Background(), taskID, taskRevisionFlags.from, taskRevisionFlags.revision)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("--- revision %d\n+++ revision %d\n%s\n", d.From, d.To, d.Diff)
+	return nil
+}
+
+func taskRevisionRollbackF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskRevisionFlags.taskID); err != nil {
+		return err
+	}
+
+	t, err := s.RollbackTask(context.Background(), taskID, taskRevisionFlags.revision)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Task %s rolled back to the script of revision %d, as revision %d.\n", taskID, taskRevisionFlags.revision, t.Revision)
+	return nil
+}
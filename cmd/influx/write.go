@@ -83,50 +83,11 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid precision")
 	}
 
-	bs := &http.BucketService{
-		Addr:  flags.host,
-		Token: flags.token,
-	}
-
-	var err error
-	filter := platform.BucketFilter{}
-
-	if writeFlags.BucketID != "" {
-		filter.ID, err = platform.IDFromString(writeFlags.BucketID)
-		if err != nil {
-			return fmt.Errorf("failed to decode bucket-id: %v", err)
-		}
-	}
-	if writeFlags.Bucket != "" {
-		filter.Name = &writeFlags.Bucket
-	}
-
-	if writeFlags.OrgID != "" {
-		filter.OrganizationID, err = platform.IDFromString(writeFlags.OrgID)
-		if err != nil {
-			return fmt.Errorf("failed to decode org-id id: %v", err)
-		}
-	}
-	if writeFlags.Org != "" {
-		filter.Organization = &writeFlags.Org
-	}
-
-	buckets, n, err := bs.FindBuckets(ctx, filter)
+	bucket, err := findWriteBucket(ctx, writeFlags.Org, writeFlags.OrgID, writeFlags.Bucket, writeFlags.BucketID)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve buckets: %v", err)
+		return err
 	}
-
-	if n == 0 {
-		if writeFlags.Bucket != "" {
-			return fmt.Errorf("bucket %q was not found", writeFlags.Bucket)
-		}
-
-		if writeFlags.BucketID != "" {
-			return fmt.Errorf("bucket with id %q does not exist", writeFlags.BucketID)
-		}
-	}
-
-	bucketID, orgID := buckets[0].ID, buckets[0].OrganizationID
+	bucketID, orgID := bucket.ID, bucket.OrganizationID
 
 	var r io.Reader
 	if args[0] == "-" {
@@ -152,8 +113,72 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 
 	ctx = signals.WithStandardSignals(ctx)
 	if err := s.Write(ctx, orgID, bucketID, r); err != nil && err != context.Canceled {
-		return fmt.Errorf("failed to write data: %v", err)
+		return writeError(err)
 	}
 
 	return nil
 }
+
+// writeError prints the lines rejected by a write which failed with err, and
+// returns the error reported by the command.
+func writeError(err error) error {
+	if lpErr := platform.ErrorLineProtocol(err); lpErr != nil {
+		for _, le := range lpErr.Errors {
+			fmt.Fprintf(os.Stderr, "line %d: %s: %s\n", le.Line, le.Reason, le.Text)
+		}
+		if platform.ErrorCode(err) == platform.EUnprocessableEntity {
+			return fmt.Errorf("partial write: %d lines rejected", len(lpErr.Errors))
+		}
+		return fmt.Errorf("failed to write data: all %d lines rejected", len(lpErr.Errors))
+	}
+	return fmt.Errorf("failed to write data: %v", err)
+}
+
+// findWriteBucket finds the bucket to write to by the name or ID of the bucket
+// and of its organization.
+func findWriteBucket(ctx context.Context, org, orgID, bucket, bucketID string) (*platform.Bucket, error) {
+	bs := &http.BucketService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var err error
+	filter := platform.BucketFilter{}
+
+	if bucketID != "" {
+		filter.ID, err = platform.IDFromString(bucketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+	}
+	if bucket != "" {
+		filter.Name = &bucket
+	}
+
+	if orgID != "" {
+		filter.OrganizationID, err = platform.IDFromString(orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode org-id id: %v", err)
+		}
+	}
+	if org != "" {
+		filter.Organization = &org
+	}
+
+	buckets, n, err := bs.FindBuckets(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve buckets: %v", err)
+	}
+
+	if n == 0 {
+		if bucket != "" {
+			return nil, fmt.Errorf("bucket %q was not found", bucket)
+		}
+
+		if bucketID != "" {
+			return nil, fmt.Errorf("bucket with id %q does not exist", bucketID)
+		}
+	}
+
+	return buckets[0], nil
+}
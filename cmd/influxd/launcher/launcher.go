@@ -15,17 +15,22 @@ import (
 	"github.com/influxdata/flux/control"
 	"github.com/influxdata/flux/execute"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	"github.com/influxdata/influxdb/backup"
 	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
 	"github.com/influxdata/influxdb/chronograf/server"
 	protofs "github.com/influxdata/influxdb/fs"
 	"github.com/influxdata/influxdb/gather"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/identity"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/influxdata/influxdb/kit/cli"
 	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/kv"
 	influxlogger "github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/nats"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/proto"
@@ -40,6 +45,7 @@ import (
 	taskbolt "github.com/influxdata/influxdb/task/backend/bolt"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
 	taskexecutor "github.com/influxdata/influxdb/task/backend/executor"
+	tasknotifier "github.com/influxdata/influxdb/task/backend/notifier"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
 	_ "github.com/influxdata/influxdb/tsdb/tsm1" // needed for tsm1
 	"github.com/influxdata/influxdb/vault"
@@ -83,6 +89,23 @@ type Launcher struct {
 	protosPath      string
 	secretStore     string
 
+	writeMaxBodySize  int
+	writeMaxBatchSize int
+
+	maxSeriesPerOrg    int
+	maxSeriesPerBucket int
+	maxValuesPerTag    int
+
+	taskMaxConcurrencyPerOrg int
+	taskLeaseOwner           string
+	taskLeaseDuration        time.Duration
+
+	auditRetention time.Duration
+	auditBucketID  string
+
+	oauth oauthConfig
+	ldap  ldapConfig
+
 	boltClient *bolt.Client
 	kvService  *kv.Service
 	engine     *storage.Engine
@@ -94,8 +117,9 @@ type Launcher struct {
 
 	natsServer *nats.Server
 
-	scheduler *taskbackend.TickScheduler
-	taskStore taskbackend.Store
+	scheduler       *taskbackend.TickScheduler
+	taskCoordinator *coordinator.Coordinator
+	taskStore       taskbackend.Store
 
 	jaegerTracerCloser io.Closer
 	logger             *zap.Logger
@@ -162,6 +186,7 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.httpServer.Shutdown(ctx)
 
 	m.logger.Info("Stopping", zap.String("service", "task"))
+	m.taskCoordinator.Stop()
 	m.scheduler.Stop()
 
 	m.logger.Info("Stopping", zap.String("service", "nats"))
@@ -272,6 +297,230 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: false,
 				Desc:    "disable sending telemetry data to https://telemetry.influxdata.com every 8 hours",
 			},
+			{
+				DestP:   &m.writeMaxBodySize,
+				Flag:    "write-max-body-size",
+				Default: 0,
+				Desc:    "maximum size in bytes of a decompressed write request body; 0 allows any size",
+			},
+			{
+				DestP:   &m.writeMaxBatchSize,
+				Flag:    "write-max-batch-size",
+				Default: models.DefaultPointsReaderBatchSize,
+				Desc:    "maximum number of lines of a write request parsed and written to the engine at a time",
+			},
+			{
+				DestP:   &m.maxSeriesPerOrg,
+				Flag:    "storage-max-series-per-org",
+				Default: storage.DefaultMaxSeriesPerOrg,
+				Desc:    "maximum number of series in the buckets of an organization; writes creating new series beyond it are rejected; 0 disables the limit",
+			},
+			{
+				DestP:   &m.maxSeriesPerBucket,
+				Flag:    "storage-max-series-per-bucket",
+				Default: storage.DefaultMaxSeriesPerBucket,
+				Desc:    "maximum number of series in a bucket; writes creating new series beyond it are rejected; 0 disables the limit",
+			},
+			{
+				DestP:   &m.maxValuesPerTag,
+				Flag:    "storage-max-values-per-tag",
+				Default: storage.DefaultMaxValuesPerTag,
+				Desc:    "maximum number of distinct values of a tag key in a bucket; writes creating new values beyond it are rejected; 0 disables the limit",
+			},
+			{
+				DestP:   &m.taskMaxConcurrencyPerOrg,
+				Flag:    "task-max-concurrency-per-org",
+				Default: 0,
+				Desc:    "maximum number of concurrent task runs of an organization; due runs beyond it wait for a free slot; 0 disables the limit",
+			},
+			{
+				DestP: &m.taskLeaseOwner,
+				Flag:  "task-lease-owner",
+				Desc:  "name the tasks run by this node are leased as, unique among the nodes sharing a task store; defaults to an ID generated once and kept next to the bolt database",
+			},
+			{
+				DestP:   &m.taskLeaseDuration,
+				Flag:    "task-lease-duration",
+				Default: coordinator.DefaultLeaseDuration,
+				Desc:    "how long the leases of this node on its tasks last unless renewed; the tasks of a node which stops are taken over by other nodes after it",
+			},
+			{
+				DestP:   &m.auditRetention,
+				Flag:    "audit-retention",
+				Default: platform.DefaultAuditRetention,
+				Desc:    "how long audit events are kept; 0 keeps them forever",
+			},
+			{
+				DestP: &m.auditBucketID,
+				Flag:  "audit-bucket-id",
+				Desc:  "ID of an existing bucket to which the audit events of every organization are also written as points; only operators should be members of its organization",
+			},
+			{
+				DestP: &m.oauth.provider,
+				Flag:  "oauth-provider",
+				Desc:  "OAuth2 provider users can sign in through (github, google or generic); signing in through a provider is disabled if unset",
+			},
+			{
+				DestP: &m.oauth.clientID,
+				Flag:  "oauth-client-id",
+				Desc:  "client ID of influxd at the OAuth2 provider",
+			},
+			{
+				DestP: &m.oauth.clientSecret,
+				Flag:  "oauth-client-secret",
+				Desc:  "client secret of influxd at the OAuth2 provider",
+			},
+			{
+				DestP: &m.oauth.redirectURL,
+				Flag:  "oauth-redirect-url",
+				Desc:  "URL of /api/v2/signin/oauth/callback the OAuth2 provider redirects to after authenticating a user",
+			},
+			{
+				DestP: &m.oauth.authURL,
+				Flag:  "oauth-auth-url",
+				Desc:  "authorization endpoint of the generic OAuth2 provider",
+			},
+			{
+				DestP: &m.oauth.tokenURL,
+				Flag:  "oauth-token-url",
+				Desc:  "token endpoint of the generic OAuth2 provider",
+			},
+			{
+				DestP: &m.oauth.apiURL,
+				Flag:  "oauth-api-url",
+				Desc:  "user info endpoint of the generic OAuth2 provider",
+			},
+			{
+				DestP:   &m.oauth.apiKey,
+				Flag:    "oauth-api-key",
+				Default: "email",
+				Desc:    "user info field or id_token claim identifying users of the generic OAuth2 provider",
+			},
+			{
+				DestP: &m.oauth.groupsKey,
+				Flag:  "oauth-groups-key",
+				Desc:  "user info field or id_token claim listing the groups of users of the generic OAuth2 provider; if unset, the group of a user is their email domain",
+			},
+			{
+				DestP: &m.oauth.scopes,
+				Flag:  "oauth-scopes",
+				Desc:  "scopes requested from the generic OAuth2 provider, such as openid,email,groups",
+			},
+			{
+				DestP: &m.oauth.domains,
+				Flag:  "oauth-domains",
+				Desc:  "email domains users of the google or generic OAuth2 provider must belong to; any domain if unset",
+			},
+			{
+				DestP: &m.oauth.orgs,
+				Flag:  "oauth-github-orgs",
+				Desc:  "GitHub organizations users must belong to; any organization if unset",
+			},
+			{
+				DestP:   &m.oauth.useIDToken,
+				Flag:    "oauth-use-id-token",
+				Default: false,
+				Desc:    "identify users by the OpenID Connect id_token of the generic OAuth2 provider instead of its user info endpoint",
+			},
+			{
+				DestP: &m.oauth.jwksURL,
+				Flag:  "oauth-jwks-url",
+				Desc:  "URL of the keys the id_token is verified with, required by --oauth-use-id-token",
+			},
+			{
+				DestP: &m.oauth.tokenSecret,
+				Flag:  "oauth-token-secret",
+				Desc:  "secret signing the state of OAuth2 sign ins; must be the same for all instances behind a load balancer; random if unset",
+			},
+			{
+				DestP:   &m.oauth.autoProvision,
+				Flag:    "oauth-auto-provision",
+				Default: true,
+				Desc:    "create a user for identities signing in through the OAuth2 provider for the first time",
+			},
+			{
+				DestP: &m.oauth.groupMappings,
+				Flag:  "oauth-group-mapping",
+				Desc:  "role granted in an organization to the members of a group of the OAuth2 provider, of the form group=org:owner or group=org:member; the memberships of users in mapped organizations are updated each time they sign in",
+			},
+			{
+				DestP: &m.ldap.URL,
+				Flag:  "ldap-url",
+				Desc:  "URL of the LDAP directory users sign in with, such as ldap://ldap.example.com or ldaps://ldap.example.com; users who are not in the directory sign in with their local password; signing in with the directory is disabled if unset",
+			},
+			{
+				DestP:   &m.ldap.StartTLS,
+				Flag:    "ldap-start-tls",
+				Default: false,
+				Desc:    "upgrade ldap:// connections to the LDAP directory to TLS",
+			},
+			{
+				DestP:   &m.ldap.InsecureSkipVerify,
+				Flag:    "ldap-insecure-skip-verify",
+				Default: false,
+				Desc:    "skip verifying the certificate of the LDAP directory",
+			},
+			{
+				DestP:   &m.ldap.Timeout,
+				Flag:    "ldap-timeout",
+				Default: 10 * time.Second,
+				Desc:    "timeout of connecting to the LDAP directory, and of each request made to it",
+			},
+			{
+				DestP: &m.ldap.BindDN,
+				Flag:  "ldap-bind-dn",
+				Desc:  "DN users and groups are searched as; searches are anonymous if unset",
+			},
+			{
+				DestP: &m.ldap.BindPassword,
+				Flag:  "ldap-bind-password",
+				Desc:  "password of the DN users and groups are searched as",
+			},
+			{
+				DestP: &m.ldap.UserBaseDN,
+				Flag:  "ldap-user-base-dn",
+				Desc:  "DN below which users are searched, such as ou=people,dc=example,dc=com",
+			},
+			{
+				DestP:   &m.ldap.UserFilter,
+				Flag:    "ldap-user-filter",
+				Default: "(uid=%s)",
+				Desc:    "filter users are searched with, where %s is the name of the user signing in",
+			},
+			{
+				DestP: &m.ldap.GroupBaseDN,
+				Flag:  "ldap-group-base-dn",
+				Desc:  "DN below which the groups of users are searched, such as ou=groups,dc=example,dc=com; memberships are not synced with groups if unset",
+			},
+			{
+				DestP:   &m.ldap.GroupFilter,
+				Flag:    "ldap-group-filter",
+				Default: "(member=%s)",
+				Desc:    "filter the groups of a user are searched with, where %s is the DN of the user",
+			},
+			{
+				DestP:   &m.ldap.GroupAttribute,
+				Flag:    "ldap-group-attribute",
+				Default: "cn",
+				Desc:    "attribute of groups holding the name group mappings refer to them by",
+			},
+			{
+				DestP:   &m.ldap.autoProvision,
+				Flag:    "ldap-auto-provision",
+				Default: true,
+				Desc:    "create a user for users of the LDAP directory signing in for the first time",
+			},
+			{
+				DestP:   &m.ldap.FallbackWhenUnavailable,
+				Flag:    "ldap-fallback-when-unavailable",
+				Default: false,
+				Desc:    "let users who never signed in with the LDAP directory sign in with their local password while the directory is unavailable; users of the directory who had a local password before it was configured can then sign in with it",
+			},
+			{
+				DestP: &m.ldap.groupMappings,
+				Flag:  "ldap-group-mapping",
+				Desc:  "role granted in an organization to the members of a group of the LDAP directory, of the form group=org:owner or group=org:member; the memberships of users in mapped organizations are updated each time they sign in",
+			},
 		},
 	}
 
@@ -342,11 +591,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	var flusher http.Flusher
+	var kvSnapshotter backup.KVSnapshotter
 	switch m.storeType {
 	case BoltStore:
 		store := bolt.NewKVStore(m.boltPath)
 		store.WithDB(m.boltClient.DB())
 		m.kvService = kv.NewService(store)
+		kvSnapshotter = store
 		if m.testing {
 			flusher = store
 		}
@@ -382,6 +633,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		userSvc          platform.UserService                     = m.kvService
 		variableSvc      platform.VariableService                 = m.kvService
 		bucketSvc        platform.BucketService                   = m.kvService
+		bucketSchemaSvc  platform.BucketSchemaService             = m.kvService
 		sourceSvc        platform.SourceService                   = m.kvService
 		sessionSvc       platform.SessionService                  = m.kvService
 		passwdsSvc       platform.PasswordsService                = m.kvService
@@ -439,9 +691,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
-	var pointsWriter storage.PointsWriter
+	var (
+		pointsWriter   storage.PointsWriter
+		observedWriter *taskbackend.ObservedPointsWriter
+	)
 	{
-		m.engine = storage.NewEngine(m.enginePath, storage.NewConfig(), storage.WithRetentionEnforcer(bucketSvc))
+		config := storage.NewConfig()
+		config.MaxSeriesPerOrg = m.maxSeriesPerOrg
+		config.MaxSeriesPerBucket = m.maxSeriesPerBucket
+		config.MaxValuesPerTag = m.maxValuesPerTag
+
+		m.engine = storage.NewEngine(m.enginePath, config, storage.WithRetentionEnforcer(bucketSvc))
 		m.engine.WithLogger(m.logger)
 
 		if err := m.engine.Open(ctx); err != nil {
@@ -451,7 +711,13 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		// The Engine's metrics must be registered after it opens.
 		m.reg.MustRegister(m.engine.PrometheusCollectors()...)
 
-		pointsWriter = m.engine
+		// Points which do not match the schema of a bucket with an explicit schema are
+		// dropped, whether they are written through the API or by queries.
+		pointsWriter = storage.NewSchemaPointsWriter(m.engine, bucketSvc, bucketSchemaSvc)
+
+		// Points written with observedWriter, including by the to() function of queries, trigger the runs
+		// of the tasks with a trigger on their bucket, once the task scheduler observes them.
+		observedWriter = taskbackend.NewObservedPointsWriter(pointsWriter, nil)
 
 		const (
 			concurrencyQuota = 10
@@ -466,7 +732,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		}
 
 		if err := readservice.AddControllerConfigDependencies(
-			&cc, m.engine, bucketSvc, orgSvc,
+			&cc, m.engine, observedWriter, bucketSvc, orgSvc,
 		); err != nil {
 			m.logger.Error("Failed to configure query controller dependencies", zap.Error(err))
 			return err
@@ -495,14 +761,36 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, authSvc, store)
 
+		queryService := query.QueryServiceBridge{AsyncQueryService: m.queryController}
+		lr := taskbackend.NewQueryLogReader(queryService)
+
 		lw := taskbackend.NewPointLogWriter(pointsWriter)
-		m.scheduler = taskbackend.NewScheduler(store, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
+		notifier := tasknotifier.NewWebhook(secretSvc, lr)
+		m.scheduler = taskbackend.NewScheduler(store, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger), taskbackend.WithRunNotifier(notifier), taskbackend.WithOrgConcurrencyLimit(m.taskMaxConcurrencyPerOrg))
 		m.scheduler.Start(ctx)
 		m.reg.MustRegister(m.scheduler.PrometheusCollectors()...)
 
-		queryService := query.QueryServiceBridge{AsyncQueryService: m.queryController}
-		lr := taskbackend.NewQueryLogReader(queryService)
-		taskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, store), lr, m.scheduler, authSvc, userResourceSvc, orgSvc)
+		// Points written from here on trigger the runs of the tasks with a trigger on their bucket.
+		observedWriter.SetObserver(m.scheduler)
+		pointsWriter = observedWriter
+
+		if m.taskLeaseDuration < minTaskLeaseDuration {
+			err := fmt.Errorf("--task-lease-duration must be at least %s", minTaskLeaseDuration)
+			m.logger.Error("failed to start task coordinator", zap.Error(err))
+			return err
+		}
+		leaseOwner := m.taskLeaseOwner
+		if leaseOwner == "" {
+			if leaseOwner, err = loadNodeID(filepath.Join(filepath.Dir(m.boltPath), "node-id")); err != nil {
+				m.logger.Error("failed to load node ID", zap.Error(err))
+				return err
+			}
+		}
+		m.taskCoordinator = coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, store,
+			coordinator.WithLeaseOwner(leaseOwner), coordinator.WithLeaseDuration(m.taskLeaseDuration))
+		taskSvc = task.PlatformAdapter(m.taskCoordinator, lr, m.scheduler, authSvc, userResourceSvc, orgSvc)
+		// The retention enforcer reads the runs of downsample tasks without an authorizer.
+		m.engine.WithDownsampleWatermarker(task.NewDownsampleWatermarker(taskSvc, authSvc))
 		taskSvc = task.NewValidator(taskSvc, bucketSvc)
 		m.taskStore = store
 	}
@@ -549,6 +837,75 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		logger.Info("Stopping")
 	}(m.logger)
 
+	var auditLogSvc platform.AuditLogService = m.kvService
+	if m.auditBucketID != "" {
+		id, err := platform.IDFromString(m.auditBucketID)
+		if err != nil {
+			m.logger.Error("invalid audit bucket ID", zap.Error(err))
+			return err
+		}
+		if _, err := bucketSvc.FindBucketByID(ctx, *id); err != nil {
+			m.logger.Error("failed to find audit bucket", zap.String("bucketID", id.String()), zap.Error(err))
+			return err
+		}
+		mirrorSvc := audit.NewMirrorService(m.kvService, bucketSvc, pointsWriter, *id)
+		mirrorSvc.Logger = m.logger.With(zap.String("service", "audit-mirror"))
+		auditLogSvc = mirrorSvc
+	}
+
+	if m.auditRetention > 0 {
+		m.wg.Add(1)
+		go func(logger *zap.Logger) {
+			defer m.wg.Done()
+			logger = logger.With(zap.String("service", "audit-retention"))
+			m.enforceAuditRetention(ctx, logger)
+			logger.Info("Stopping")
+		}(m.logger)
+	}
+
+	newProvisioner := func(autoCreate bool, groupMappings []string) (*identity.Provisioner, error) {
+		p := identity.NewProvisioner(userSvc, m.kvService, orgSvc, userResourceSvc)
+		p.Logger = m.logger.With(zap.String("service", "identity"))
+		p.AutoCreate = autoCreate
+		for _, s := range groupMappings {
+			gm, err := platform.ParseGroupMapping(s)
+			if err != nil {
+				return nil, err
+			}
+			p.GroupMappings = append(p.GroupMappings, gm)
+		}
+		return p, nil
+	}
+
+	var oauthMux *oauth2.AuthMux
+	if m.oauth.provider != "" {
+		provisioner, err := newProvisioner(m.oauth.autoProvision, m.oauth.groupMappings)
+		if err != nil {
+			m.logger.Error("invalid OAuth2 group mapping", zap.Error(err))
+			return err
+		}
+
+		oauthMux, err = m.oauth.newMux(sessionSvc, provisioner, m.logger.With(zap.String("service", "oauth")))
+		if err != nil {
+			m.logger.Error("failed to configure OAuth2 provider", zap.Error(err))
+			return err
+		}
+	}
+
+	if m.ldap.URL != "" {
+		provisioner, err := newProvisioner(m.ldap.autoProvision, m.ldap.groupMappings)
+		if err != nil {
+			m.logger.Error("invalid LDAP group mapping", zap.Error(err))
+			return err
+		}
+
+		passwdsSvc, err = m.ldap.newPasswordsService(provisioner, passwdsSvc, m.logger.With(zap.String("service", "ldap")))
+		if err != nil {
+			m.logger.Error("failed to configure LDAP directory", zap.Error(err))
+			return err
+		}
+	}
+
 	m.httpServer = &nethttp.Server{
 		Addr: m.httpBindAddress,
 	}
@@ -559,9 +916,18 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		Deleter:              m.engine,
+		WriteMaxBodySize:     int64(m.writeMaxBodySize),
+		WriteMaxBatchSize:    m.writeMaxBatchSize,
 		AuthorizationService: authSvc,
-		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
-		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		AuditLogService:      auditLogSvc,
+		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine,
+		// and in one that manages the tasks of the downsample rules of buckets.
+		BucketService:                   task.NewDownsampleBucketService(storage.NewBucketService(bucketSvc, m.engine), taskSvc),
+		BucketSchemaService:             bucketSchemaSvc,
+		CardinalityService:              storage.NewCardinalityService(bucketSvc, m.engine),
+		BackupService:                   backup.NewService(kvSnapshotter, m.engine),
+		ExportService:                   storage.NewExportService(bucketSvc, m.engine),
 		SessionService:                  sessionSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
@@ -578,6 +944,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		OnboardingService:               onboardingSvc,
 		InfluxQLService:                 nil, // No InfluxQL support
 		FluxService:                     storageQueryService,
+		QueryService:                    query.QueryServiceBridge{AsyncQueryService: m.queryController},
 		TaskService:                     taskSvc,
 		TelegrafService:                 telegrafSvc,
 		ScraperTargetStoreService:       scraperTargetSvc,
@@ -587,6 +954,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		ProtoService:                    protoSvc,
 		OrgLookupService:                m.kvService,
 	}
+	// Assigning a nil *oauth2.AuthMux would make the interface non-nil.
+	if oauthMux != nil {
+		m.apibackend.OAuthMux = oauthMux
+	}
 
 	// HTTP server
 	httpLogger := m.logger.With(zap.String("service", "http"))
@@ -629,6 +1000,34 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	return nil
 }
 
+// auditRetentionInterval is how often audit events older than the audit retention are deleted.
+const auditRetentionInterval = time.Hour
+
+// minTaskLeaseDuration is the shortest lease duration of the tasks of a node.
+// Leases expire on Unix timestamps, and are renewed three times per lease duration.
+const minTaskLeaseDuration = 3 * time.Second
+
+// enforceAuditRetention deletes the audit events older than the audit retention, until ctx is done.
+func (m *Launcher) enforceAuditRetention(ctx context.Context, logger *zap.Logger) {
+	ticker := time.NewTicker(auditRetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := m.kvService.DeleteAuditEventsBefore(ctx, time.Now().Add(-m.auditRetention))
+		if err != nil {
+			logger.Error("failed to delete expired audit events", zap.Error(err))
+		} else if n > 0 {
+			logger.Info("Deleted expired audit events", zap.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // OrganizationService returns the internal organization service.
 func (m *Launcher) OrganizationService() platform.OrganizationService {
 	return m.apibackend.OrganizationService
@@ -0,0 +1,37 @@
+package launcher
+
+import (
+	"fmt"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/identity"
+	"github.com/influxdata/influxdb/ldap"
+	"go.uber.org/zap"
+)
+
+// ldapConfig is the configuration of the LDAP directory users can sign in with.
+type ldapConfig struct {
+	ldap.Config
+
+	autoProvision bool
+	groupMappings []string
+}
+
+// newPasswordsService returns the passwords service authenticating users against the directory,
+// and the users who are not in the directory with local.
+func (c *ldapConfig) newPasswordsService(p *identity.Provisioner, local platform.PasswordsService, logger *zap.Logger) (*ldap.PasswordsService, error) {
+	if c.UserBaseDN == "" {
+		return nil, fmt.Errorf("an LDAP directory requires --ldap-user-base-dn")
+	}
+	if !strings.Contains(c.UserFilter, "%s") {
+		return nil, fmt.Errorf("--ldap-user-filter must contain %%s, which is replaced with the name of the user")
+	}
+	if c.GroupBaseDN != "" && !strings.Contains(c.GroupFilter, "%s") {
+		return nil, fmt.Errorf("--ldap-group-filter must contain %%s, which is replaced with the DN of the user")
+	}
+
+	s := ldap.NewPasswordsService(c.Config, p, local)
+	s.Logger = logger
+	return s, nil
+}
@@ -0,0 +1,33 @@
+package launcher
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/influxdb/snowflake"
+)
+
+// loadNodeID returns the ID of this node kept at path.
+// If there is no such file, a new ID is generated and kept at path,
+// so that the node keeps its ID, and the leases on its tasks, across restarts.
+func loadNodeID(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err == nil {
+		if id := strings.TrimSpace(string(b)); id != "" {
+			return id, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	id := snowflake.NewIDGenerator().ID().String()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path, []byte(id+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
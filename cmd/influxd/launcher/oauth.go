@@ -0,0 +1,110 @@
+package launcher
+
+import (
+	"fmt"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/identity"
+	"github.com/influxdata/influxdb/rand"
+	"go.uber.org/zap"
+)
+
+// oauthConfig is the configuration of the OAuth2 provider users can sign in through.
+type oauthConfig struct {
+	provider     string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+
+	// Endpoints and user info of the generic provider.
+	authURL   string
+	tokenURL  string
+	apiURL    string
+	apiKey    string
+	groupsKey string
+	scopes    []string
+
+	domains []string
+	orgs    []string
+
+	useIDToken bool
+	jwksURL    string
+
+	tokenSecret   string
+	autoProvision bool
+	groupMappings []string
+}
+
+// newProvider returns the configured provider.
+func (c *oauthConfig) newProvider(logger chronograf.Logger) (oauth2.Provider, error) {
+	// An id_token whose signature can't be verified could be forged by anyone.
+	if c.useIDToken && c.jwksURL == "" {
+		return nil, fmt.Errorf("--oauth-use-id-token requires --oauth-jwks-url to verify the id_token with")
+	}
+
+	switch c.provider {
+	case "github":
+		return &oauth2.Github{
+			ClientID:     c.clientID,
+			ClientSecret: c.clientSecret,
+			Orgs:         c.orgs,
+			Logger:       logger,
+		}, nil
+	case "google":
+		return &oauth2.Google{
+			ClientID:     c.clientID,
+			ClientSecret: c.clientSecret,
+			RedirectURL:  c.redirectURL,
+			Domains:      c.domains,
+			Logger:       logger,
+		}, nil
+	case "generic":
+		if c.authURL == "" || c.tokenURL == "" {
+			return nil, fmt.Errorf("the generic OAuth2 provider requires --oauth-auth-url and --oauth-token-url")
+		}
+		if c.apiURL == "" && !c.useIDToken {
+			return nil, fmt.Errorf("the generic OAuth2 provider requires --oauth-api-url, or --oauth-use-id-token")
+		}
+		return &oauth2.Generic{
+			ClientID:       c.clientID,
+			ClientSecret:   c.clientSecret,
+			RequiredScopes: c.scopes,
+			Domains:        c.domains,
+			RedirectURL:    c.redirectURL,
+			AuthURL:        c.authURL,
+			TokenURL:       c.tokenURL,
+			APIURL:         c.apiURL,
+			APIKey:         c.apiKey,
+			GroupsKey:      c.groupsKey,
+			Logger:         logger,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown OAuth2 provider %q; supported providers are github, google and generic", c.provider)
+}
+
+// newMux returns the mux serving the sign in through the configured provider,
+// which starts sessions of the users the provisioner provisions for the identities signing in.
+func (c *oauthConfig) newMux(s platform.SessionService, p *identity.Provisioner, logger *zap.Logger) (*oauth2.AuthMux, error) {
+	if c.clientID == "" || c.clientSecret == "" {
+		return nil, fmt.Errorf("an OAuth2 provider requires --oauth-client-id and --oauth-client-secret")
+	}
+
+	provider, err := c.newProvider(http.NewChronografLogger(logger))
+	if err != nil {
+		return nil, err
+	}
+
+	secret := c.tokenSecret
+	if secret == "" {
+		if secret, err = rand.NewTokenGenerator(64).Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	a := http.NewOAuthAuthenticator(s, p)
+	a.Logger = logger
+	return http.NewOAuthMux(provider, a, secret, c.jwksURL, c.useIDToken, logger), nil
+}
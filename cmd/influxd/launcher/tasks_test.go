@@ -223,7 +223,15 @@ from(bucket:"my_bucket_in") |> range(start:-5m) |> to(bucket:"%s", org:"%s")`, b
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(logs) != 1 {
-		t.Fatalf("expected 1 log for run, got %d", len(logs))
+	if len(logs) < 2 {
+		t.Fatalf("expected at least 2 log entries for run, got %d", len(logs))
+	}
+	for _, l := range logs {
+		if l.RunID != targetRun.ID {
+			t.Fatalf("expected log entries of run %s, got entry of run %s", targetRun.ID, l.RunID)
+		}
+	}
+	if last := logs[len(logs)-1]; last.Message != "Completed successfully" {
+		t.Fatalf("expected last log entry to be %q, got %q", "Completed successfully", last.Message)
 	}
 }
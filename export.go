@@ -0,0 +1,69 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ops for exports.
+const (
+	OpExportBucket = "ExportBucket"
+)
+
+// ExportFormat is the format of the data of an export.
+type ExportFormat string
+
+// Export formats.
+const (
+	// ExportFormatLineProtocol exports a line of line protocol per point and
+	// field.
+	ExportFormatLineProtocol ExportFormat = "lp"
+	// ExportFormatCSV exports a table of Flux annotated CSV per series.
+	ExportFormatCSV ExportFormat = "csv"
+)
+
+// Valid returns an error if the format is unknown.
+func (f ExportFormat) Valid() error {
+	switch f {
+	case ExportFormatLineProtocol, ExportFormatCSV:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid export format %q; valid formats are %s and %s", f, ExportFormatLineProtocol, ExportFormatCSV),
+		}
+	}
+}
+
+// ExportPosition is the position of a point and field in an export. Exports
+// are ordered by series, and by time within a series.
+type ExportPosition struct {
+	// Key is the series key of the point in line protocol, such as
+	// "cpu,host=a".
+	Key   string
+	Field string
+	Time  time.Time
+}
+
+// ExportFilter selects the data of a bucket to export.
+type ExportFilter struct {
+	BucketID ID
+	Format   ExportFormat
+
+	// Start and Stop bound the times of the exported points. Start is
+	// inclusive and Stop is exclusive. Zero values are unbounded.
+	Start time.Time
+	Stop  time.Time
+
+	// After resumes an interrupted export after the last point and field
+	// which were received.
+	After *ExportPosition
+}
+
+// ExportService exports the data of buckets.
+type ExportService interface {
+	// ExportBucket writes the data of a bucket selected by filter to w.
+	ExportBucket(ctx context.Context, filter ExportFilter, w io.Writer) error
+}
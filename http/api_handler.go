@@ -6,6 +6,7 @@ import (
 
 	influxdb "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
@@ -15,12 +16,15 @@ import (
 // APIHandler is a collection of all the service handlers.
 type APIHandler struct {
 	BucketHandler        *BucketHandler
+	BackupHandler        *BackupHandler
+	ExportHandler        *ExportHandler
 	UserHandler          *UserHandler
 	OrgHandler           *OrgHandler
 	AuthorizationHandler *AuthorizationHandler
 	DashboardHandler     *DashboardHandler
 	LabelHandler         *LabelHandler
 	AssetHandler         *AssetHandler
+	AuditHandler         *AuditHandler
 	ChronografHandler    *ChronografHandler
 	ScraperHandler       *ScraperHandler
 	SourceHandler        *SourceHandler
@@ -30,6 +34,7 @@ type APIHandler struct {
 	QueryHandler         *FluxHandler
 	ProtoHandler         *ProtoHandler
 	WriteHandler         *WriteHandler
+	DeleteHandler        *DeleteHandler
 	SetupHandler         *SetupHandler
 	SessionHandler       *SessionHandler
 	SwaggerHandler       http.HandlerFunc
@@ -45,8 +50,14 @@ type APIBackend struct {
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
 
 	PointsWriter                    storage.PointsWriter
+	Deleter                         storage.Deleter
+	AuditLogService                 influxdb.AuditLogService
 	AuthorizationService            influxdb.AuthorizationService
 	BucketService                   influxdb.BucketService
+	BucketSchemaService             influxdb.BucketSchemaService
+	BackupService                   influxdb.BackupService
+	ExportService                   influxdb.ExportService
+	CardinalityService              influxdb.CardinalityService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
@@ -63,6 +74,7 @@ type APIBackend struct {
 	OnboardingService               influxdb.OnboardingService
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
+	QueryService                    query.QueryService
 	TaskService                     influxdb.TaskService
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
@@ -72,6 +84,10 @@ type APIBackend struct {
 	ProtoService                    influxdb.ProtoService
 	OrgLookupService                authorizer.OrganizationService
 	ViewService                     influxdb.ViewService
+	OAuthMux                        oauth2.Mux
+
+	WriteMaxBodySize  int64
+	WriteMaxBatchSize int
 }
 
 // NewAPIHandler constructs all api handlers beneath it and returns an APIHandler
@@ -86,8 +102,18 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 
 	bucketBackend := NewBucketBackend(b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	bucketBackend.BucketSchemaService = authorizer.NewBucketSchemaService(b.OrgLookupService, b.BucketSchemaService)
+	bucketBackend.CardinalityService = authorizer.NewCardinalityService(b.OrgLookupService, b.CardinalityService)
 	h.BucketHandler = NewBucketHandler(bucketBackend)
 
+	backupBackend := NewBackupBackend(b)
+	backupBackend.BackupService = authorizer.NewBackupService(b.BackupService)
+	h.BackupHandler = NewBackupHandler(backupBackend)
+
+	exportBackend := NewExportBackend(b)
+	exportBackend.ExportService = authorizer.NewExportService(b.OrgLookupService, b.ExportService)
+	h.ExportHandler = NewExportHandler(exportBackend)
+
 	orgBackend := NewOrgBackend(b)
 	orgBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
 	h.OrgHandler = NewOrgHandler(orgBackend)
@@ -132,6 +158,9 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	writeBackend := NewWriteBackend(b)
 	h.WriteHandler = NewWriteHandler(writeBackend)
 
+	deleteBackend := NewDeleteBackend(b)
+	h.DeleteHandler = NewDeleteHandler(deleteBackend)
+
 	fluxBackend := NewFluxBackend(b)
 	h.QueryHandler = NewFluxHandler(fluxBackend)
 
@@ -140,18 +169,27 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.SwaggerHandler = SwaggerHandler()
 	h.LabelHandler = NewLabelHandler(b.LabelService)
 
+	auditBackend := NewAuditBackend(b)
+	auditBackend.AuditLogService = authorizer.NewAuditLogService(b.AuditLogService)
+	h.AuditHandler = NewAuditHandler(auditBackend)
+
 	return h
 }
 
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
+	"audit":          "/api/v2/audit",
 	"authorizations": "/api/v2/authorizations",
+	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
+	"delete":         "/api/v2/delete",
+	"export":         "/api/v2/export",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
+	"import":    "/api/v2/import",
 	"labels":    "/api/v2/labels",
 	"variables": "/api/v2/variables",
 	"me":        "/api/v2/me",
@@ -202,7 +240,7 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" {
+	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" || strings.HasPrefix(r.URL.Path, oauthSigninPath) {
 		h.SessionHandler.ServeHTTP(w, r)
 		return
 	}
@@ -212,11 +250,21 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.HasPrefix(r.URL.Path, "/api/v2/write") {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/write") || strings.HasPrefix(r.URL.Path, "/api/v2/import") {
 		h.WriteHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/export") {
+		h.ExportHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/delete") {
+		h.DeleteHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
 		h.QueryHandler.ServeHTTP(w, r)
 		return
@@ -227,6 +275,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/backup") {
+		h.BackupHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/labels") {
 		h.LabelHandler.ServeHTTP(w, r)
 		return
@@ -247,6 +300,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/audit") {
+		h.AuditHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/authorizations") {
 		h.AuthorizationHandler.ServeHTTP(w, r)
 		return
@@ -0,0 +1,319 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	auditPath = "/api/v2/audit"
+)
+
+// AuditBackend is all services and associated parameters required to construct
+// the AuditHandler.
+type AuditBackend struct {
+	Logger *zap.Logger
+
+	AuditLogService influxdb.AuditLogService
+}
+
+// NewAuditBackend returns a new instance of AuditBackend.
+func NewAuditBackend(b *APIBackend) *AuditBackend {
+	return &AuditBackend{
+		Logger: b.Logger.With(zap.String("handler", "audit")),
+
+		AuditLogService: b.AuditLogService,
+	}
+}
+
+// AuditHandler serves the audit log.
+type AuditHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	AuditLogService influxdb.AuditLogService
+}
+
+// NewAuditHandler returns a new instance of AuditHandler.
+func NewAuditHandler(b *AuditBackend) *AuditHandler {
+	h := &AuditHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		AuditLogService: b.AuditLogService,
+	}
+
+	h.HandlerFunc("GET", auditPath, h.handleGetAuditEvents)
+	return h
+}
+
+type auditEventsResponse struct {
+	Links  *influxdb.PagingLinks  `json:"links"`
+	Events []*influxdb.AuditEvent `json:"events"`
+	// Count is the number of events matching the filter, of which Events is a page.
+	Count int `json:"count"`
+}
+
+func newAuditEventsResponse(opts influxdb.FindOptions, f influxdb.AuditFilter, es []*influxdb.AuditEvent, n int) *auditEventsResponse {
+	if es == nil {
+		es = []*influxdb.AuditEvent{}
+	}
+	return &auditEventsResponse{
+		Links:  newPagingLinks(auditPath, opts, f, len(es)),
+		Events: es,
+		Count:  n,
+	}
+}
+
+type getAuditEventsRequest struct {
+	filter influxdb.AuditFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetAuditEventsRequest(ctx context.Context, r *http.Request) (*getAuditEventsRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getAuditEventsRequest{
+		opts: *opts,
+	}
+
+	qp := r.URL.Query()
+	if action := qp.Get("action"); action != "" {
+		a := influxdb.AuditAction(action)
+		if err := a.Valid(); err != nil {
+			return nil, err
+		}
+		req.filter.Action = &a
+	}
+
+	if resourceType := qp.Get("resourceType"); resourceType != "" {
+		rt := influxdb.ResourceType(resourceType)
+		req.filter.ResourceType = &rt
+	}
+
+	for k, id := range map[string]**influxdb.ID{
+		"resourceID": &req.filter.ResourceID,
+		"orgID":      &req.filter.OrgID,
+		"userID":     &req.filter.UserID,
+	} {
+		if v := qp.Get(k); v != "" {
+			i, err := influxdb.IDFromString(v)
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  k + " is invalid",
+					Err:  err,
+				}
+			}
+			*id = i
+		}
+	}
+
+	for k, t := range map[string]**time.Time{
+		"since": &req.filter.Since,
+		"until": &req.filter.Until,
+	} {
+		if v := qp.Get(k); v != "" {
+			tm, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  k + " must be an RFC3339 time",
+					Err:  err,
+				}
+			}
+			*t = &tm
+		}
+	}
+
+	return req, nil
+}
+
+// handleGetAuditEvents is the HTTP handler for the GET /api/v2/audit route.
+func (h *AuditHandler) handleGetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetAuditEventsRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	es, n, err := h.AuditLogService.FindAuditEvents(ctx, req.filter, req.opts)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAuditEventsResponse(req.opts, req.filter, es, n)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// AuditingHandler is a middleware recording the changes made by the requests it serves in the audit log.
+// It must be placed after the AuthenticationHandler, so that the changes are recorded as made by the request's authorizer.
+type AuditingHandler struct {
+	Logger *zap.Logger
+
+	AuditLogService influxdb.AuditLogService
+
+	Handler http.Handler
+}
+
+// NewAuditingHandler creates an auditing handler.
+func NewAuditingHandler() *AuditingHandler {
+	return &AuditingHandler{
+		Logger:  zap.NewNop(),
+		Handler: http.DefaultServeMux,
+	}
+}
+
+// unauditedPaths are the prefixes of the paths of requests which write data rather than change resources,
+// and are too frequent to record.
+var unauditedPaths = []string{
+	"/api/v2/write",
+	"/api/v2/import",
+	"/api/v2/query",
+}
+
+// ServeHTTP serves the request in a context in which the services record the changes they make.
+// Successful changing requests which did not record any change, because the services they use do not,
+// are recorded as a change to the resource named by their path.
+func (h *AuditingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	action, ok := auditAction(r)
+	if !ok {
+		h.Handler.ServeHTTP(w, r)
+		return
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	ctx := audit.NewContext(r.Context(), h.AuditLogService, h.Logger, audit.Source{
+		IP:      ip,
+		Request: r.Method + " " + r.URL.Path,
+	})
+	sw := newStatusResponseWriter(w)
+	h.Handler.ServeHTTP(sw, r.WithContext(ctx))
+
+	if sw.code() >= http.StatusBadRequest {
+		return
+	}
+
+	rt, id := auditResource(r.URL.Path)
+	audit.RecordRequest(ctx, action, rt, id)
+}
+
+// auditAction returns the action recorded for r, and false if r is not audited.
+func auditAction(r *http.Request) (influxdb.AuditAction, bool) {
+	for _, p := range unauditedPaths {
+		if strings.HasPrefix(r.URL.Path, p) {
+			return "", false
+		}
+	}
+
+	switch r.Method {
+	case "POST":
+		return influxdb.AuditCreate, true
+	case "PUT", "PATCH":
+		return influxdb.AuditUpdate, true
+	case "DELETE":
+		return influxdb.AuditDelete, true
+	}
+	return "", false
+}
+
+// auditResource returns the resource type and ID named by the path of a request, such as
+// "buckets" and the bucket ID for "/api/v2/buckets/:id/labels".
+func auditResource(p string) (influxdb.ResourceType, influxdb.ID) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(p, "/api/v2"), "/"), "/")
+
+	rt := influxdb.ResourceType(parts[0])
+	var id influxdb.ID
+	if len(parts) > 1 {
+		if err := id.DecodeFromString(parts[1]); err != nil {
+			id = 0
+		}
+	}
+	return rt, id
+}
+
+// AuditLogService connects to Influx via HTTP using tokens to read the audit log.
+type AuditLogService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.AuditLogService = (*AuditLogService)(nil)
+
+// CreateAuditEvent is not supported over HTTP, as events are only recorded by the server.
+func (s *AuditLogService) CreateAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	return &influxdb.Error{
+		Code: influxdb.EMethodNotAllowed,
+		Op:   influxdb.OpCreateAuditEvent,
+		Msg:  "audit events cannot be created over HTTP",
+	}
+}
+
+// FindAuditEvents returns the audit events that match filter.
+func (s *AuditLogService) FindAuditEvents(ctx context.Context, filter influxdb.AuditFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	u, err := newURL(s.Addr, auditPath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	qp := u.Query()
+	for k, vs := range filter.QueryParams() {
+		for _, v := range vs {
+			qp.Add(k, v)
+		}
+	}
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				qp.Add(k, v)
+			}
+		}
+	}
+	u.RawQuery = qp.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, 0, err
+	}
+
+	var ar auditEventsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		return nil, 0, err
+	}
+	return ar.Events, ar.Count, nil
+}
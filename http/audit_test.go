@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestAuditingHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		// record is whether the inner handler records the change itself.
+		record bool
+		want   *platform.AuditEvent
+	}{
+		{
+			name:   "request recorded by the services",
+			method: "PATCH",
+			path:   "/api/v2/buckets/020f755c3c082000",
+			status: http.StatusOK,
+			record: true,
+			want: &platform.AuditEvent{
+				Action:       platform.AuditUpdate,
+				ResourceType: platform.BucketsResourceType,
+				ResourceID:   platform.ID(1),
+				UserID:       platform.ID(2),
+				AuthorizerID: platform.ID(3),
+			},
+		},
+		{
+			name:   "request not recorded by the services",
+			method: "DELETE",
+			path:   "/api/v2/tasks/020f755c3c082000/runs/020f755c3c082001",
+			status: http.StatusNoContent,
+			want: &platform.AuditEvent{
+				Action:       platform.AuditDelete,
+				ResourceType: platform.TasksResourceType,
+				ResourceID:   platform.ID(0x020f755c3c082000),
+				UserID:       platform.ID(2),
+				AuthorizerID: platform.ID(3),
+			},
+		},
+		{
+			name:   "failed request",
+			method: "POST",
+			path:   "/api/v2/tasks",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "read request",
+			method: "GET",
+			path:   "/api/v2/tasks",
+			status: http.StatusOK,
+		},
+		{
+			name:   "write request",
+			method: "POST",
+			path:   "/api/v2/write",
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []*platform.AuditEvent
+			log := mock.NewAuditLogService()
+			log.CreateAuditEventFn = func(ctx context.Context, e *platform.AuditEvent) error {
+				events = append(events, e)
+				return nil
+			}
+
+			h := NewAuditingHandler()
+			h.AuditLogService = log
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.record {
+					audit.Record(r.Context(), platform.AuditUpdate, platform.BucketsResourceType, platform.ID(1), 0, nil, nil)
+				}
+				w.WriteHeader(tt.status)
+			})
+
+			r := httptest.NewRequest(tt.method, "http://any.url"+tt.path, nil)
+			r.RemoteAddr = "10.0.0.1:5678"
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{ID: 3, UserID: 2}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if tt.want == nil {
+				if len(events) != 0 {
+					t.Fatalf("expected no event, got %+v", events[0])
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events))
+			}
+
+			want := *tt.want
+			want.AuthorizerKind = platform.AuthorizationKind
+			want.SourceIP = "10.0.0.1"
+			want.Request = tt.method + " " + tt.path
+			if diff := cmp.Diff(events[0], &want); diff != "" {
+				t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestAuditLogService_FindAuditEvents(t *testing.T) {
+	timeComparer := cmp.Comparer(func(x, y time.Time) bool {
+		return x.Equal(y)
+	})
+	orgID := platform.ID(10)
+	action := platform.AuditDelete
+	since := time.Date(2019, 2, 1, 12, 0, 0, 0, time.UTC)
+	filter := platform.AuditFilter{
+		Action: &action,
+		OrgID:  &orgID,
+		Since:  &since,
+	}
+	opts := platform.FindOptions{Limit: 5, Descending: true}
+	events := []*platform.AuditEvent{
+		{
+			ID:           platform.ID(1),
+			Time:         since.Add(time.Minute),
+			Action:       platform.AuditDelete,
+			ResourceType: platform.BucketsResourceType,
+			ResourceID:   platform.ID(2),
+			OrgID:        orgID,
+			Before:       []byte(`{"name":"b1"}`),
+		},
+	}
+
+	svc := mock.NewAuditLogService()
+	svc.FindAuditEventsFn = func(ctx context.Context, f platform.AuditFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error) {
+		if diff := cmp.Diff(f, filter, timeComparer); diff != "" {
+			t.Errorf("audit filters are different -got/+want\ndiff %s", diff)
+		}
+		if len(opt) != 1 || opt[0] != opts {
+			t.Errorf("unexpected find options %+v", opt)
+		}
+		// More events match than the page returned.
+		return events, 7, nil
+	}
+
+	auditBackend := NewAuditBackend(&APIBackend{Logger: zap.NewNop()})
+	auditBackend.AuditLogService = svc
+	server := httptest.NewServer(NewAuditHandler(auditBackend))
+	defer server.Close()
+
+	client := AuditLogService{Addr: server.URL}
+	got, n, err := client.FindAuditEvents(context.Background(), filter, opts)
+	if err != nil {
+		t.Fatalf("FindAuditEvents() unexpected error: %v", err)
+	}
+	if n != 7 {
+		t.Errorf("FindAuditEvents() count = %d, want 7", n)
+	}
+	if diff := cmp.Diff(got, events, timeComparer); diff != "" {
+		t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"path"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -67,6 +69,7 @@ func NewAuthorizationHandler(b *AuthorizationBackend) *AuthorizationHandler {
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleSetAuthorizationStatus)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
+	h.HandlerFunc("POST", "/api/v2/authorizations/:id/rotate", h.handleRotateAuthorization)
 	return h
 }
 
@@ -80,7 +83,13 @@ type authResponse struct {
 	UserID      platform.ID          `json:"userID"`
 	User        string               `json:"user"`
 	Permissions []permissionResponse `json:"permissions"`
-	Links       map[string]string    `json:"links"`
+	CreatedAt   *time.Time           `json:"createdAt,omitempty"`
+	CreatedBy   platform.ID          `json:"createdBy,omitempty"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time           `json:"lastUsedAt,omitempty"`
+	// The previous token itself is not returned, only until when it is accepted.
+	PreviousTokenExpiresAt *time.Time        `json:"previousTokenExpiresAt,omitempty"`
+	Links                  map[string]string `json:"links"`
 }
 
 func newAuthResponse(a *platform.Authorization, org *platform.Organization, user *platform.User, ps []permissionResponse) *authResponse {
@@ -94,11 +103,21 @@ func newAuthResponse(a *platform.Authorization, org *platform.Organization, user
 		User:        user.Name,
 		Org:         org.Name,
 		Permissions: ps,
+		CreatedBy:   a.CreatedBy,
+		ExpiresAt:   a.ExpiresAt,
+		LastUsedAt:  a.LastUsedAt,
+
+		PreviousTokenExpiresAt: a.PreviousTokenExpiresAt,
 		Links: map[string]string{
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
 		},
 	}
+	// Authorizations created before the time was recorded don't have it.
+	if !a.CreatedAt.IsZero() {
+		createdAt := a.CreatedAt
+		res.CreatedAt = &createdAt
+	}
 	return res
 }
 
@@ -110,6 +129,14 @@ func (a *authResponse) toPlatform() *platform.Authorization {
 		Description: a.Description,
 		OrgID:       a.OrgID,
 		UserID:      a.UserID,
+		CreatedBy:   a.CreatedBy,
+		ExpiresAt:   a.ExpiresAt,
+		LastUsedAt:  a.LastUsedAt,
+
+		PreviousTokenExpiresAt: a.PreviousTokenExpiresAt,
+	}
+	if a.CreatedAt != nil {
+		res.CreatedAt = *a.CreatedAt
 	}
 	for _, p := range a.Permissions {
 		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource})
@@ -225,6 +252,7 @@ type postAuthorizationRequest struct {
 	UserID      *platform.ID          `json:"userID,omitempty"`
 	Description string                `json:"description"`
 	Permissions []platform.Permission `json:"permissions"`
+	ExpiresAt   *time.Time            `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
@@ -234,6 +262,8 @@ func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Auth
 		Description: p.Description,
 		Permissions: p.Permissions,
 		UserID:      userID,
+		CreatedBy:   userID,
+		ExpiresAt:   p.ExpiresAt,
 	}
 }
 
@@ -243,6 +273,7 @@ func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationR
 		Description: a.Description,
 		Permissions: a.Permissions,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	if a.UserID.Valid() {
@@ -293,6 +324,13 @@ func (p *postAuthorizationRequest) Validate() error {
 		return err
 	}
 
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "expiresAt must be in the future",
+		}
+	}
+
 	return nil
 }
 
@@ -390,6 +428,18 @@ func decodeGetAuthorizationsRequest(ctx context.Context, r *http.Request) (*getA
 		req.filter.ID = id
 	}
 
+	if v := qp.Get("unusedSince"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "unusedSince must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		req.filter.UnusedSince = &t
+	}
+
 	return req, nil
 }
 
@@ -583,6 +633,100 @@ func decodeDeleteAuthorizationRequest(ctx context.Context, r *http.Request) (*de
 	}, nil
 }
 
+// handleRotateAuthorization is the HTTP handler for the POST /api/v2/authorizations/:id/rotate route.
+func (h *AuthorizationHandler) handleRotateAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeRotateAuthorizationRequest(ctx, r)
+	if err != nil {
+		h.Logger.Info("failed to decode request", zap.String("handler", "rotateAuthorization"), zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AuthorizationService.RotateAuthorization(ctx, req.ID, req.GracePeriod)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, a.OrgID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, a.UserID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	ps, err := newPermissionsResponse(ctx, a.Permissions, h.LookupService)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAuthResponse(a, o, u, ps)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type rotateAuthorizationRequest struct {
+	ID          platform.ID
+	GracePeriod time.Duration
+}
+
+type rotateAuthorizationBody struct {
+	// GracePeriod is how long the current token is still accepted, such as "1h"; if empty, it is rejected right away.
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
+func decodeRotateAuthorizationRequest(ctx context.Context, r *http.Request) (*rotateAuthorizationRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	// The body is optional.
+	var body rotateAuthorizationBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	req := &rotateAuthorizationRequest{ID: i}
+	if body.GracePeriod != "" {
+		d, err := ParseDuration(body.GracePeriod)
+		if err != nil {
+			return nil, err
+		}
+		if d < 0 {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "gracePeriod must not be negative",
+			}
+		}
+		req.GracePeriod = d
+	}
+
+	return req, nil
+}
+
 func getAuthorizedUser(r *http.Request, svc platform.UserService) (*platform.User, error) {
 	ctx := r.Context()
 
@@ -667,6 +811,10 @@ func (s *AuthorizationService) FindAuthorizations(ctx context.Context, filter pl
 		query.Add("user", *filter.User)
 	}
 
+	if filter.UnusedSince != nil {
+		query.Add("unusedSince", filter.UnusedSince.UTC().Format(time.RFC3339))
+	}
+
 	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
@@ -809,6 +957,53 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 	return CheckError(resp)
 }
 
+// RotateAuthorization replaces the token of an authorization with a new one, and returns the updated authorization.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	u, err := newURL(s.Addr, path.Join(authorizationIDPath(id), "rotate"))
+	if err != nil {
+		return nil, err
+	}
+
+	var body rotateAuthorizationBody
+	if gracePeriod > 0 {
+		body.GracePeriod = FormatDuration(gracePeriod)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var a authResponse
+	if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return nil, err
+	}
+	return a.toPlatform(), nil
+}
+
+// SetAuthorizationLastUsed is only recorded by the server, when it authenticates a request.
+func (s *AuthorizationService) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) error {
+	return errors.New("not supported in HTTP authorization service")
+}
+
 func authorizationIDPath(id platform.ID) string {
 	return path.Join(authorizationPath, id.String())
 }
@@ -497,6 +497,7 @@ func TestService_handlePostAuthorization(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `
 {
+  "createdBy": "aaaaaaaaaaaaaaaa",
   "description": "only read dashboards sucka",
   "id": "020f755c3c082000",
   "links": {
@@ -109,6 +109,20 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return ctx, err
 	}
 
+	now := time.Now()
+	if a.IsExpired(now) {
+		return ctx, platform.ErrAuthorizationExpired
+	}
+
+	// Recording every use would write to the store for each request.
+	if a.LastUsedAt == nil || now.Sub(*a.LastUsedAt) >= platform.LastUsedResolution {
+		if err := h.AuthorizationService.SetAuthorizationLastUsed(ctx, a.ID, now); err != nil {
+			h.Logger.Info("failed to record authorization use", zap.String("authorizationID", a.ID.String()), zap.Error(err))
+		} else {
+			a.LastUsedAt = &now
+		}
+	}
+
 	return platcontext.SetAuthorizer(ctx, a), nil
 }
 
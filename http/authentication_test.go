@@ -76,6 +76,47 @@ func TestAuthenticationHandler(t *testing.T) {
 					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
 						return &platform.Authorization{}, nil
 					},
+					SetAuthorizationLastUsedFn: func(ctx context.Context, id platform.ID, t time.Time) error {
+						return nil
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name: "token expired",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						expired := time.Now().Add(-time.Minute)
+						return &platform.Authorization{ExpiresAt: &expired}, nil
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
+		{
+			name: "failing to record token use does not fail request",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						return &platform.Authorization{}, nil
+					},
+					SetAuthorizationLastUsedFn: func(ctx context.Context, id platform.ID, t time.Time) error {
+						return fmt.Errorf("store unavailable")
+					},
 				},
 				SessionService: mock.NewSessionService(),
 			},
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// BackupBackend is all services and associated parameters required to construct
+// the BackupHandler.
+type BackupBackend struct {
+	Logger *zap.Logger
+
+	BackupService influxdb.BackupService
+}
+
+// NewBackupBackend returns a new instance of BackupBackend.
+func NewBackupBackend(b *APIBackend) *BackupBackend {
+	return &BackupBackend{
+		Logger: b.Logger.With(zap.String("handler", "backup")),
+
+		BackupService: b.BackupService,
+	}
+}
+
+// BackupHandler streams backup archives of the server.
+type BackupHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	BackupService influxdb.BackupService
+}
+
+const (
+	backupPath            = "/api/v2/backup"
+	incrementalBackupPath = "/api/v2/backup/incremental"
+
+	backupContentType = "application/x-tar"
+)
+
+// NewBackupHandler creates a new handler at /api/v2/backup to stream backups.
+func NewBackupHandler(b *BackupBackend) *BackupHandler {
+	h := &BackupHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		BackupService: b.BackupService,
+	}
+
+	h.HandlerFunc("GET", backupPath, h.handleBackup)
+	h.HandlerFunc("POST", incrementalBackupPath, h.handleIncrementalBackup)
+	return h
+}
+
+// backupResponseWriter records if the response has been written to.
+type backupResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *backupResponseWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.written = true
+		w.ResponseWriter.Header().Set("Content-Type", backupContentType)
+		w.ResponseWriter.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// handleBackup is the HTTP handler for the GET /api/v2/backup route.
+func (h *BackupHandler) handleBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bw := &backupResponseWriter{ResponseWriter: w}
+	if err := h.BackupService.Backup(ctx, bw); err != nil {
+		if !bw.written {
+			EncodeError(ctx, err, w)
+			return
+		}
+		// The archive is truncated, which the client detects when reading it.
+		h.Logger.Error("Failed to write backup", zap.Error(err))
+		return
+	}
+}
+
+func decodeIncrementalBackupRequest(ctx context.Context, r *http.Request) (*influxdb.BackupManifest, error) {
+	var since influxdb.BackupManifest
+	if err := json.NewDecoder(r.Body).Decode(&since); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid request; error parsing the manifest of the previous backup",
+			Err:  err,
+		}
+	}
+	return &since, nil
+}
+
+// handleIncrementalBackup is the HTTP handler for the POST /api/v2/backup/incremental route.
+func (h *BackupHandler) handleIncrementalBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	since, err := decodeIncrementalBackupRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	bw := &backupResponseWriter{ResponseWriter: w}
+	if err := h.BackupService.IncrementalBackup(ctx, since, bw); err != nil {
+		if !bw.written {
+			EncodeError(ctx, err, w)
+			return
+		}
+		h.Logger.Error("Failed to write incremental backup", zap.Error(err))
+		return
+	}
+}
+
+// BackupService connects to Influx via HTTP using tokens to create backups of
+// the server.
+type BackupService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.BackupService = (*BackupService)(nil)
+
+// Backup writes a backup archive of the server to w.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) error {
+	u, err := newURL(s.Addr, backupPath)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(ctx, req, w)
+}
+
+// IncrementalBackup writes an archive of the files of the server created
+// since the backup described by since to w.
+func (s *BackupService) IncrementalBackup(ctx context.Context, since *influxdb.BackupManifest, w io.Writer) error {
+	u, err := newURL(s.Addr, incrementalBackupPath)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(since)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return s.do(ctx, req, w)
+}
+
+// do sends req, and copies the backup archive in the response to w.
+func (s *BackupService) do(ctx context.Context, req *http.Request, w io.Writer) error {
+	SetToken(s.Token, req)
+
+	hc := newClient(req.URL.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
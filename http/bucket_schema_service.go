@@ -0,0 +1,303 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+)
+
+type measurementSchemaResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.MeasurementSchema
+}
+
+func newMeasurementSchemaResponse(m *influxdb.MeasurementSchema) *measurementSchemaResponse {
+	return &measurementSchemaResponse{
+		Links: map[string]string{
+			"self":   measurementSchemaPath(m.BucketID, m.Name),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", m.BucketID),
+		},
+		MeasurementSchema: *m,
+	}
+}
+
+type measurementSchemasResponse struct {
+	Links              map[string]string            `json:"links"`
+	MeasurementSchemas []*measurementSchemaResponse `json:"measurementSchemas"`
+}
+
+func newMeasurementSchemasResponse(bucketID influxdb.ID, ms []*influxdb.MeasurementSchema) *measurementSchemasResponse {
+	res := &measurementSchemasResponse{
+		Links: map[string]string{
+			"self": measurementSchemasPath(bucketID),
+		},
+		MeasurementSchemas: make([]*measurementSchemaResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		res.MeasurementSchemas = append(res.MeasurementSchemas, newMeasurementSchemaResponse(m))
+	}
+	return res
+}
+
+// handleGetMeasurementSchemas is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handleGetMeasurementSchemas(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	ms, err := h.BucketSchemaService.FindMeasurementSchemas(ctx, req.BucketID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemasResponse(req.BucketID, ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostMeasurementSchema is the HTTP handler for the POST /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handlePostMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	m := &influxdb.MeasurementSchema{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		EncodeError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+	m.BucketID = req.BucketID
+
+	if err := h.BucketSchemaService.CreateMeasurementSchema(ctx, m); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newMeasurementSchemaResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type measurementSchemaRequest struct {
+	BucketID influxdb.ID
+	Name     string
+}
+
+func decodeMeasurementSchemaRequest(ctx context.Context, r *http.Request) (*measurementSchemaRequest, error) {
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	name := params.ByName("name")
+	if name == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing measurement name",
+		}
+	}
+
+	return &measurementSchemaRequest{
+		BucketID: req.BucketID,
+		Name:     name,
+	}, nil
+}
+
+// handleGetMeasurementSchema is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements/:name route.
+func (h *BucketHandler) handleGetMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeMeasurementSchemaRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	m, err := h.BucketSchemaService.FindMeasurementSchema(ctx, req.BucketID, req.Name)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemaResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchMeasurementSchema is the HTTP handler for the PATCH /api/v2/buckets/:id/schema/measurements/:name route.
+func (h *BucketHandler) handlePatchMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeMeasurementSchemaRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.MeasurementSchemaUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		EncodeError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  err.Error(),
+		}, w)
+		return
+	}
+
+	m, err := h.BucketSchemaService.UpdateMeasurementSchema(ctx, req.BucketID, req.Name, upd)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemaResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteMeasurementSchema is the HTTP handler for the DELETE /api/v2/buckets/:id/schema/measurements/:name route.
+func (h *BucketHandler) handleDeleteMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeMeasurementSchemaRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.BucketSchemaService.DeleteMeasurementSchema(ctx, req.BucketID, req.Name); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func measurementSchemasPath(bucketID influxdb.ID) string {
+	return path.Join(bucketPath, bucketID.String(), "schema", "measurements")
+}
+
+func measurementSchemaPath(bucketID influxdb.ID, name string) string {
+	return path.Join(measurementSchemasPath(bucketID), name)
+}
+
+// BucketSchemaService connects to Influx via HTTP using tokens to manage the
+// measurement schemas of buckets.
+type BucketSchemaService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// FindMeasurementSchema returns the schema of a measurement in a bucket.
+func (s *BucketSchemaService) FindMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string) (*influxdb.MeasurementSchema, error) {
+	var mr measurementSchemaResponse
+	if err := s.do(ctx, "GET", measurementSchemaPath(bucketID, name), nil, &mr); err != nil {
+		return nil, err
+	}
+	return &mr.MeasurementSchema, nil
+}
+
+// FindMeasurementSchemas returns the schemas of every measurement in a bucket.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.MeasurementSchema, error) {
+	var mr measurementSchemasResponse
+	if err := s.do(ctx, "GET", measurementSchemasPath(bucketID), nil, &mr); err != nil {
+		return nil, err
+	}
+
+	ms := make([]*influxdb.MeasurementSchema, 0, len(mr.MeasurementSchemas))
+	for _, m := range mr.MeasurementSchemas {
+		ms = append(ms, &m.MeasurementSchema)
+	}
+	return ms, nil
+}
+
+// CreateMeasurementSchema creates the schema of a measurement in a bucket.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	var mr measurementSchemaResponse
+	if err := s.do(ctx, "POST", measurementSchemasPath(m.BucketID), m, &mr); err != nil {
+		return err
+	}
+	*m = mr.MeasurementSchema
+	return nil
+}
+
+// UpdateMeasurementSchema updates the schema of a measurement in a bucket.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	var mr measurementSchemaResponse
+	if err := s.do(ctx, "PATCH", measurementSchemaPath(bucketID, name), upd, &mr); err != nil {
+		return nil, err
+	}
+	return &mr.MeasurementSchema, nil
+}
+
+// DeleteMeasurementSchema removes the schema of a measurement from a bucket.
+func (s *BucketSchemaService) DeleteMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string) error {
+	return s.do(ctx, "DELETE", measurementSchemaPath(bucketID, name), nil, nil)
+}
+
+// do sends a request with body encoded as JSON, and decodes the response into v
+// if it is not nil.
+func (s *BucketSchemaService) do(ctx context.Context, method, p string, body, v interface{}) error {
+	u, err := newURL(s.Addr, p)
+	if err != nil {
+		return err
+	}
+
+	var octets []byte
+	if body != nil {
+		if octets, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
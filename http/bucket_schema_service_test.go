@@ -0,0 +1,51 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func initBucketSchemaService(f platformtesting.BucketSchemaFields, t *testing.T) (platform.BucketSchemaService, func()) {
+	svc := kv.NewService(inmem.NewKVStore())
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing bucket schema service: %v", err)
+	}
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets")
+		}
+	}
+	for _, m := range f.MeasurementSchemas {
+		if err := svc.CreateMeasurementSchema(ctx, m); err != nil {
+			t.Fatalf("failed to populate measurement schemas: %v", err)
+		}
+	}
+
+	bucketBackend := NewMockBucketBackend()
+	bucketBackend.BucketService = svc
+	bucketBackend.BucketSchemaService = svc
+	handler := NewBucketHandler(bucketBackend)
+	server := httptest.NewServer(handler)
+	client := BucketSchemaService{
+		Addr: server.URL,
+	}
+
+	return &client, server.Close
+}
+
+func TestBucketSchemaService(t *testing.T) {
+	platformtesting.BucketSchemaService(initBucketSchemaService, t)
+}
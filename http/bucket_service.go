@@ -20,6 +20,8 @@ type BucketBackend struct {
 	Logger *zap.Logger
 
 	BucketService              influxdb.BucketService
+	BucketSchemaService        influxdb.BucketSchemaService
+	CardinalityService         influxdb.CardinalityService
 	BucketOperationLogService  influxdb.BucketOperationLogService
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
@@ -33,6 +35,8 @@ func NewBucketBackend(b *APIBackend) *BucketBackend {
 		Logger: b.Logger.With(zap.String("handler", "bucket")),
 
 		BucketService:              b.BucketService,
+		BucketSchemaService:        b.BucketSchemaService,
+		CardinalityService:         b.CardinalityService,
 		BucketOperationLogService:  b.BucketOperationLogService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
@@ -48,6 +52,8 @@ type BucketHandler struct {
 	Logger *zap.Logger
 
 	BucketService              influxdb.BucketService
+	BucketSchemaService        influxdb.BucketSchemaService
+	CardinalityService         influxdb.CardinalityService
 	BucketOperationLogService  influxdb.BucketOperationLogService
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
@@ -65,6 +71,11 @@ const (
 	bucketsIDOwnersIDPath  = "/api/v2/buckets/:id/owners/:userID"
 	bucketsIDLabelsPath    = "/api/v2/buckets/:id/labels"
 	bucketsIDLabelsIDPath  = "/api/v2/buckets/:id/labels/:lid"
+
+	bucketsIDCardinalityPath = "/api/v2/buckets/:id/cardinality"
+
+	bucketsIDSchemaMeasurementsPath     = "/api/v2/buckets/:id/schema/measurements"
+	bucketsIDSchemaMeasurementsNamePath = "/api/v2/buckets/:id/schema/measurements/:name"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -74,6 +85,8 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		Logger: b.Logger,
 
 		BucketService:              b.BucketService,
+		BucketSchemaService:        b.BucketSchemaService,
+		CardinalityService:         b.CardinalityService,
 		BucketOperationLogService:  b.BucketOperationLogService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
@@ -88,6 +101,14 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
 
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsPath, h.handleGetMeasurementSchemas)
+	h.HandlerFunc("POST", bucketsIDSchemaMeasurementsPath, h.handlePostMeasurementSchema)
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsNamePath, h.handleGetMeasurementSchema)
+	h.HandlerFunc("PATCH", bucketsIDSchemaMeasurementsNamePath, h.handlePatchMeasurementSchema)
+	h.HandlerFunc("DELETE", bucketsIDSchemaMeasurementsNamePath, h.handleDeleteMeasurementSchema)
+
+	h.HandlerFunc("GET", bucketsIDCardinalityPath, h.handleGetBucketCardinality)
+
 	memberBackend := MemberBackend{
 		Logger:                     b.Logger.With(zap.String("handler", "member")),
 		ResourceType:               influxdb.BucketsResourceType,
@@ -125,12 +146,14 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
-	ID                  influxdb.ID     `json:"id,omitempty"`
-	OrganizationID      influxdb.ID     `json:"organizationID,omitempty"`
-	Organization        string          `json:"organization,omitempty"`
-	Name                string          `json:"name"`
-	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
-	RetentionRules      []retentionRule `json:"retentionRules"`
+	ID                  influxdb.ID         `json:"id,omitempty"`
+	OrganizationID      influxdb.ID         `json:"organizationID,omitempty"`
+	Organization        string              `json:"organization,omitempty"`
+	Name                string              `json:"name"`
+	RetentionPolicyName string              `json:"rp,omitempty"` // This to support v1 sources
+	RetentionRules      []retentionRule     `json:"retentionRules"`
+	SchemaType          influxdb.SchemaType `json:"schemaType,omitempty"`
+	DownsampleRules     []downsampleRule    `json:"downsampleRules,omitempty"`
 }
 
 // retentionRule is the retention rule action for a bucket.
@@ -139,6 +162,49 @@ type retentionRule struct {
 	EverySeconds int64  `json:"everySeconds"`
 }
 
+// downsampleRule is a downsample rule of a bucket with durations in seconds.
+type downsampleRule struct {
+	SourceRetentionSeconds int64                       `json:"sourceRetentionSeconds,omitempty"`
+	EverySeconds           int64                       `json:"everySeconds"`
+	Function               influxdb.DownsampleFunction `json:"function"`
+	DestinationBucketID    influxdb.ID                 `json:"destinationBucketID"`
+	TaskID                 influxdb.ID                 `json:"taskID,omitempty"`
+}
+
+func toDownsampleRules(rs []downsampleRule) []influxdb.DownsampleRule {
+	if rs == nil {
+		return nil
+	}
+	rules := make([]influxdb.DownsampleRule, 0, len(rs))
+	for _, r := range rs {
+		rules = append(rules, influxdb.DownsampleRule{
+			SourceRetention:     time.Duration(r.SourceRetentionSeconds) * time.Second,
+			Every:               time.Duration(r.EverySeconds) * time.Second,
+			Function:            r.Function,
+			DestinationBucketID: r.DestinationBucketID,
+			TaskID:              r.TaskID,
+		})
+	}
+	return rules
+}
+
+func newDownsampleRules(rules []influxdb.DownsampleRule) []downsampleRule {
+	if rules == nil {
+		return nil
+	}
+	rs := make([]downsampleRule, 0, len(rules))
+	for _, r := range rules {
+		rs = append(rs, downsampleRule{
+			SourceRetentionSeconds: int64(r.SourceRetention.Round(time.Second) / time.Second),
+			EverySeconds:           int64(r.Every.Round(time.Second) / time.Second),
+			Function:               r.Function,
+			DestinationBucketID:    r.DestinationBucketID,
+			TaskID:                 r.TaskID,
+		})
+	}
+	return rs
+}
+
 func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 	if b == nil {
 		return nil, nil
@@ -157,6 +223,10 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		}
 	}
 
+	if err := b.SchemaType.Valid(); err != nil {
+		return nil, err
+	}
+
 	return &influxdb.Bucket{
 		ID:                  b.ID,
 		OrganizationID:      b.OrganizationID,
@@ -164,6 +234,8 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		SchemaType:          b.SchemaType,
+		DownsampleRules:     toDownsampleRules(b.DownsampleRules),
 	}, nil
 }
 
@@ -188,13 +260,17 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Name:                pb.Name,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		SchemaType:          pb.SchemaType,
+		DownsampleRules:     newDownsampleRules(pb.DownsampleRules),
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name            *string              `json:"name,omitempty"`
+	RetentionRules  []retentionRule      `json:"retentionRules,omitempty"`
+	DownsampleRules *[]downsampleRule    `json:"downsampleRules,omitempty"`
+	SchemaType      *influxdb.SchemaType `json:"schemaType,omitempty"`
 }
 
 func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
@@ -214,10 +290,25 @@ func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
 		}
 	}
 
-	return &influxdb.BucketUpdate{
+	if b.SchemaType != nil {
+		if err := b.SchemaType.Valid(); err != nil {
+			return nil, err
+		}
+	}
+
+	upd := &influxdb.BucketUpdate{
 		Name:            b.Name,
 		RetentionPeriod: &d,
-	}, nil
+		SchemaType:      b.SchemaType,
+	}
+	if b.DownsampleRules != nil {
+		rules := toDownsampleRules(*b.DownsampleRules)
+		if rules == nil {
+			rules = []influxdb.DownsampleRule{}
+		}
+		upd.DownsampleRules = &rules
+	}
+	return upd, nil
 }
 
 func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
@@ -228,6 +319,7 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	up := &bucketUpdate{
 		Name:           pb.Name,
 		RetentionRules: []retentionRule{},
+		SchemaType:     pb.SchemaType,
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -237,6 +329,14 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 			EverySeconds: d,
 		})
 	}
+
+	if pb.DownsampleRules != nil {
+		rules := newDownsampleRules(*pb.DownsampleRules)
+		if rules == nil {
+			rules = []downsampleRule{}
+		}
+		up.DownsampleRules = &rules
+	}
 	return up
 }
 
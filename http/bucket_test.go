@@ -25,6 +25,8 @@ func NewMockBucketBackend() *BucketBackend {
 		Logger: zap.NewNop().With(zap.String("handler", "bucket")),
 
 		BucketService:              mock.NewBucketService(),
+		BucketSchemaService:        mock.NewBucketSchemaService(),
+		CardinalityService:         mock.NewCardinalityService(),
 		BucketOperationLogService:  mock.NewBucketOperationLogService(),
 		UserResourceMappingService: mock.NewUserResourceMappingService(),
 		LabelService:               mock.NewLabelService(),
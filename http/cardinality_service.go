@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/influxdata/influxdb"
+)
+
+type bucketCardinalityResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.BucketCardinality
+}
+
+func newBucketCardinalityResponse(bc *influxdb.BucketCardinality) *bucketCardinalityResponse {
+	return &bucketCardinalityResponse{
+		Links: map[string]string{
+			"self":   bucketCardinalityPath(bc.BucketID),
+			"bucket": path.Join(bucketPath, bc.BucketID.String()),
+		},
+		BucketCardinality: *bc,
+	}
+}
+
+type getBucketCardinalityRequest struct {
+	BucketID influxdb.ID
+	Options  influxdb.CardinalityOptions
+}
+
+func decodeGetBucketCardinalityRequest(ctx context.Context, r *http.Request) (*getBucketCardinalityRequest, error) {
+	breq, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getBucketCardinalityRequest{
+		BucketID: breq.BucketID,
+		Options: influxdb.CardinalityOptions{
+			TopN: influxdb.DefaultCardinalityTopN,
+		},
+	}
+
+	qp := r.URL.Query()
+	if topN := qp.Get("topN"); topN != "" {
+		n, err := strconv.Atoi(topN)
+		if err != nil || n < 1 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "topN must be a positive integer",
+			}
+		}
+		req.Options.TopN = n
+	}
+
+	if estimate := qp.Get("estimate"); estimate != "" {
+		e, err := strconv.ParseBool(estimate)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "estimate must be true or false",
+			}
+		}
+		req.Options.Estimate = e
+	}
+
+	return req, nil
+}
+
+// handleGetBucketCardinality is the HTTP handler for the GET /api/v2/buckets/:id/cardinality route.
+func (h *BucketHandler) handleGetBucketCardinality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketCardinalityRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	bc, err := h.CardinalityService.FindBucketCardinality(ctx, req.BucketID, req.Options)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketCardinalityResponse(bc)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func bucketCardinalityPath(bucketID influxdb.ID) string {
+	return path.Join(bucketPath, bucketID.String(), "cardinality")
+}
+
+// CardinalityService connects to Influx via HTTP using tokens to report the
+// series cardinality of buckets.
+type CardinalityService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.CardinalityService = (*CardinalityService)(nil)
+
+// FindBucketCardinality returns the series cardinality of a bucket.
+func (s *CardinalityService) FindBucketCardinality(ctx context.Context, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	u, err := newURL(s.Addr, bucketCardinalityPath(bucketID))
+	if err != nil {
+		return nil, err
+	}
+
+	qp := u.Query()
+	if opts.TopN > 0 {
+		qp.Set("topN", strconv.Itoa(opts.TopN))
+	}
+	if opts.Estimate {
+		qp.Set("estimate", "true")
+	}
+	u.RawQuery = qp.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var br bucketCardinalityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		return nil, err
+	}
+	return &br.BucketCardinality, nil
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestCardinalityService_FindBucketCardinality(t *testing.T) {
+	bucketID := platform.ID(2)
+	want := &platform.BucketCardinality{
+		BucketID: bucketID,
+		Series:   3,
+		Measurements: []platform.MeasurementCardinality{
+			{
+				Name:   "cpu",
+				Series: 3,
+				TagKeys: []platform.TagKeyCardinality{
+					{
+						Key:    "host",
+						Values: 2,
+						TopValues: []platform.TagValueCardinality{
+							{Value: "a", Series: 2},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts platform.CardinalityOptions
+	}{
+		{
+			name: "exact cardinality",
+			opts: platform.CardinalityOptions{TopN: 1},
+		},
+		{
+			name: "estimated cardinality",
+			opts: platform.CardinalityOptions{TopN: 5, Estimate: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewCardinalityService()
+			svc.FindBucketCardinalityFn = func(ctx context.Context, id platform.ID, opts platform.CardinalityOptions) (*platform.BucketCardinality, error) {
+				if id != bucketID {
+					t.Errorf("FindBucketCardinality() bucket id = %v, want %v", id, bucketID)
+				}
+				if opts != tt.opts {
+					t.Errorf("FindBucketCardinality() options = %+v, want %+v", opts, tt.opts)
+				}
+				bc := *want
+				bc.Estimated = opts.Estimate
+				return &bc, nil
+			}
+
+			bucketBackend := NewMockBucketBackend()
+			bucketBackend.CardinalityService = svc
+			server := httptest.NewServer(NewBucketHandler(bucketBackend))
+			defer server.Close()
+
+			client := CardinalityService{Addr: server.URL}
+			got, err := client.FindBucketCardinality(context.Background(), bucketID, tt.opts)
+			if err != nil {
+				t.Fatalf("FindBucketCardinality() unexpected error: %v", err)
+			}
+
+			exp := *want
+			exp.Estimated = tt.opts.Estimate
+			if diff := cmp.Diff(got, &exp); diff != "" {
+				t.Errorf("bucket cardinalities are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestBucketHandler_handleGetBucketCardinality_InvalidOptions(t *testing.T) {
+	for _, query := range []string{"topN=0", "topN=x", "estimate=maybe"} {
+		t.Run(query, func(t *testing.T) {
+			h := NewBucketHandler(NewMockBucketBackend())
+
+			r := httptest.NewRequest("GET", "/api/v2/buckets/0000000000000002/cardinality?"+query, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got, want := w.Code, http.StatusBadRequest; got != want {
+				t.Errorf("handleGetBucketCardinality() status = %d, want %d: %s", got, want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxql"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// DeleteBackend is all services and associated parameters required to construct
+// the DeleteHandler.
+type DeleteBackend struct {
+	Logger *zap.Logger
+
+	Deleter             storage.Deleter
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+}
+
+// NewDeleteBackend returns a new instance of DeleteBackend.
+func NewDeleteBackend(b *APIBackend) *DeleteBackend {
+	return &DeleteBackend{
+		Logger: b.Logger.With(zap.String("handler", "delete")),
+
+		Deleter:             b.Deleter,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// DeleteHandler receives a delete request with a predicate and sends it to storage.
+type DeleteHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+
+	Deleter storage.Deleter
+}
+
+const (
+	deletePath = "/api/v2/delete"
+)
+
+// predicateRemap maps the user facing measurement and field tag keys to the
+// keys used to store them in the engine.
+var predicateRemap = map[string]string{
+	"_measurement": tsdb.MeasurementTagKey,
+	"_field":       tsdb.FieldKeyTagKey,
+}
+
+// NewDeleteHandler creates a new handler at /api/v2/delete to delete data matching a predicate.
+func NewDeleteHandler(b *DeleteBackend) *DeleteHandler {
+	h := &DeleteHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		Deleter:             b.Deleter,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", deletePath, h.handleDelete)
+	return h
+}
+
+func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeDeleteRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
+
+	org, err := findOrganization(ctx, h.OrganizationService, req.Org)
+	if err != nil {
+		logger.Info("Failed to find organization", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	bucket, err := findBucket(ctx, h.BucketService, org.ID, req.Bucket)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Op:  "http/handleDelete",
+			Err: err,
+		}, w)
+		return
+	}
+
+	p, err := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResourceType, org.ID)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleDelete",
+			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if !a.Allowed(*p) {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Op:   "http/handleDelete",
+			Msg:  "insufficient permissions to delete",
+		}, w)
+		return
+	}
+
+	if err := h.Deleter.DeleteBucketRangePredicate(ctx, org.ID, bucket.ID, req.Start, req.Stop, req.Predicate); err != nil {
+		logger.Error("Error deleting data", zap.Error(err))
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleDelete",
+			Msg:  fmt.Sprintf("unable to delete data: %v", err),
+			Err:  err,
+		}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type deleteRequest struct {
+	Org       string
+	Bucket    string
+	Start     int64
+	Stop      int64
+	Predicate influxql.Expr
+}
+
+type deleteRequestDecode struct {
+	Start     string          `json:"start"`
+	Stop      string          `json:"stop"`
+	Predicate json.RawMessage `json:"predicate"`
+}
+
+func decodeDeleteRequest(ctx context.Context, r *http.Request) (*deleteRequest, error) {
+	qp := r.URL.Query()
+	req := &deleteRequest{
+		Org:    qp.Get("org"),
+		Bucket: qp.Get("bucket"),
+	}
+
+	var drd deleteRequestDecode
+	if err := json.NewDecoder(r.Body).Decode(&drd); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "invalid request; error parsing request json",
+			Err:  err,
+		}
+	}
+
+	start, err := time.Parse(time.RFC3339Nano, drd.Start)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "invalid RFC3339Nano for field start, please format your time with RFC3339Nano format, example: 2009-01-02T23:00:00Z",
+		}
+	}
+	req.Start = start.UnixNano()
+
+	stop, err := time.Parse(time.RFC3339Nano, drd.Stop)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "invalid RFC3339Nano for field stop, please format your time with RFC3339Nano format, example: 2009-01-01T23:00:00Z",
+		}
+	}
+	req.Stop = stop.UnixNano()
+
+	if req.Start > req.Stop {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "invalid time range; start must not be after stop",
+		}
+	}
+
+	if len(drd.Predicate) > 0 && !bytes.Equal(drd.Predicate, []byte("null")) {
+		var pred datatypes.Predicate
+		if err := jsonpb.Unmarshal(bytes.NewReader(drd.Predicate), &pred); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeDeleteRequest",
+				Msg:  "invalid predicate",
+				Err:  err,
+			}
+		}
+
+		if root := pred.GetRoot(); root != nil {
+			if req.Predicate, err = reads.NodeToExpr(root, predicateRemap); err != nil {
+				return nil, &platform.Error{
+					Code: platform.EInvalid,
+					Op:   "http/decodeDeleteRequest",
+					Msg:  "invalid predicate",
+					Err:  err,
+				}
+			}
+		}
+	}
+
+	return req, nil
+}
+
+// DeleteService sends delete requests over HTTP to influxdb.
+type DeleteService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// DeleteBucketRangePredicate deletes the data in the bucket between min and max
+// matching the predicate.
+func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred *datatypes.Predicate) error {
+	u, err := newURL(s.Addr, deletePath)
+	if err != nil {
+		return err
+	}
+
+	body := struct {
+		Start     string          `json:"start"`
+		Stop      string          `json:"stop"`
+		Predicate json.RawMessage `json:"predicate,omitempty"`
+	}{
+		Start: time.Unix(0, min).UTC().Format(time.RFC3339Nano),
+		Stop:  time.Unix(0, max).UTC().Format(time.RFC3339Nano),
+	}
+
+	if pred != nil {
+		var m jsonpb.Marshaler
+		p, err := m.MarshalToString(pred)
+		if err != nil {
+			return err
+		}
+		body.Predicate = json.RawMessage(p)
+	}
+
+	octets, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	params := req.URL.Query()
+	params.Set("org", orgID.String())
+	params.Set("bucket", bucketID.String())
+	req.URL.RawQuery = params.Encode()
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
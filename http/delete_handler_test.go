@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+)
+
+func TestDecodeDeleteRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		start   int64
+		stop    int64
+		expr    string
+		wantErr bool
+	}{
+		{
+			name:  "time range only",
+			body:  `{"start":"1970-01-01T00:00:00Z","stop":"1970-01-01T00:00:01Z"}`,
+			start: 0,
+			stop:  1000000000,
+		},
+		{
+			name: "measurement and tag predicate",
+			body: `{"start":"1970-01-01T00:00:00Z","stop":"1970-01-01T00:00:01Z","predicate":{"root":{
+				"nodeType":"LOGICAL_EXPRESSION","logical":"AND","children":[
+					{"nodeType":"COMPARISON_EXPRESSION","comparison":"EQUAL","children":[
+						{"nodeType":"TAG_REF","tagRefValue":"_measurement"},
+						{"nodeType":"LITERAL","stringValue":"cpu"}]},
+					{"nodeType":"COMPARISON_EXPRESSION","comparison":"EQUAL","children":[
+						{"nodeType":"TAG_REF","tagRefValue":"host"},
+						{"nodeType":"LITERAL","stringValue":"a"}]}]}}}`,
+			start: 0,
+			stop:  1000000000,
+			expr:  `_m::tag = 'cpu' AND host::tag = 'a'`,
+		},
+		{
+			name:    "invalid start",
+			body:    `{"start":"yesterday","stop":"1970-01-01T00:00:01Z"}`,
+			wantErr: true,
+		},
+		{
+			name:    "start after stop",
+			body:    `{"start":"1970-01-01T00:00:02Z","stop":"1970-01-01T00:00:01Z"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid predicate",
+			body:    `{"start":"1970-01-01T00:00:00Z","stop":"1970-01-01T00:00:01Z","predicate":{"root":"cpu"}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v2/delete?org=o&bucket=b", strings.NewReader(tt.body))
+			got, err := decodeDeleteRequest(context.Background(), r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeDeleteRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Org != "o" || got.Bucket != "b" {
+				t.Errorf("decodeDeleteRequest() org, bucket = %q, %q, want %q, %q", got.Org, got.Bucket, "o", "b")
+			}
+			if got.Start != tt.start || got.Stop != tt.stop {
+				t.Errorf("decodeDeleteRequest() range = [%d, %d], want [%d, %d]", got.Start, got.Stop, tt.start, tt.stop)
+			}
+
+			var expr string
+			if got.Predicate != nil {
+				expr = got.Predicate.String()
+			}
+			if expr != tt.expr {
+				t.Errorf("decodeDeleteRequest() predicate = %q, want %q", expr, tt.expr)
+			}
+		})
+	}
+}
+
+func TestDeleteService_DeleteBucketRangePredicate(t *testing.T) {
+	var (
+		org, bucket *platform.ID
+		body        map[string]interface{}
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		org, _ = platform.IDFromString(r.URL.Query().Get("org"))
+		bucket, _ = platform.IDFromString(r.URL.Query().Get("bucket"))
+		defer r.Body.Close()
+		b, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	pred := &datatypes.Predicate{
+		Root: &datatypes.Node{
+			NodeType: datatypes.NodeTypeComparisonExpression,
+			Value:    &datatypes.Node_Comparison_{Comparison: datatypes.ComparisonEqual},
+			Children: []*datatypes.Node{
+				{NodeType: datatypes.NodeTypeTagRef, Value: &datatypes.Node_TagRefValue{TagRefValue: "host"}},
+				{NodeType: datatypes.NodeTypeLiteral, Value: &datatypes.Node_StringValue{StringValue: "a"}},
+			},
+		},
+	}
+
+	s := &DeleteService{Addr: ts.URL}
+	if err := s.DeleteBucketRangePredicate(context.Background(), 1, 2, 0, 1000000000, pred); err != nil {
+		t.Fatalf("DeleteService.DeleteBucketRangePredicate() error = %v", err)
+	}
+
+	if got, want := *org, platform.ID(1); got != want {
+		t.Errorf("DeleteService.DeleteBucketRangePredicate() org = %v, want %v", got, want)
+	}
+	if got, want := *bucket, platform.ID(2); got != want {
+		t.Errorf("DeleteService.DeleteBucketRangePredicate() bucket = %v, want %v", got, want)
+	}
+	if got, want := body["start"], "1970-01-01T00:00:00Z"; got != want {
+		t.Errorf("DeleteService.DeleteBucketRangePredicate() start = %v, want %v", got, want)
+	}
+	if got, want := body["stop"], "1970-01-01T00:00:01Z"; got != want {
+		t.Errorf("DeleteService.DeleteBucketRangePredicate() stop = %v, want %v", got, want)
+	}
+	if body["predicate"] == nil {
+		t.Errorf("DeleteService.DeleteBucketRangePredicate() expected a predicate")
+	}
+}
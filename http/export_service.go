@@ -0,0 +1,222 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// ExportBackend is all services and associated parameters required to construct
+// the ExportHandler.
+type ExportBackend struct {
+	Logger *zap.Logger
+
+	ExportService influxdb.ExportService
+}
+
+// NewExportBackend returns a new instance of ExportBackend.
+func NewExportBackend(b *APIBackend) *ExportBackend {
+	return &ExportBackend{
+		Logger: b.Logger.With(zap.String("handler", "export")),
+
+		ExportService: b.ExportService,
+	}
+}
+
+// ExportHandler streams the data of buckets.
+type ExportHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	ExportService influxdb.ExportService
+}
+
+const (
+	exportPath = "/api/v2/export"
+)
+
+// NewExportHandler creates a new handler at /api/v2/export to stream the data
+// of buckets.
+func NewExportHandler(b *ExportBackend) *ExportHandler {
+	h := &ExportHandler{
+		Router: NewRouter(),
+		Logger: b.Logger,
+
+		ExportService: b.ExportService,
+	}
+
+	h.HandlerFunc("GET", exportPath, h.handleExport)
+	return h
+}
+
+// exportContentType returns the content type of the data of an export in
+// format f.
+func exportContentType(f influxdb.ExportFormat) string {
+	if f == influxdb.ExportFormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// exportResponseWriter sets the content type of the response when it is first
+// written to.
+type exportResponseWriter struct {
+	http.ResponseWriter
+	contentType string
+	written     bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.written = true
+		w.ResponseWriter.Header().Set("Content-Type", w.contentType)
+		w.ResponseWriter.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func decodeExportRequest(ctx context.Context, r *http.Request) (*influxdb.ExportFilter, error) {
+	qp := r.URL.Query()
+
+	id, err := influxdb.IDFromString(qp.Get("bucketID"))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid request; bucketID is required",
+			Err:  err,
+		}
+	}
+
+	filter := &influxdb.ExportFilter{
+		BucketID: *id,
+		Format:   influxdb.ExportFormat(qp.Get("format")),
+	}
+	if filter.Format == "" {
+		filter.Format = influxdb.ExportFormatLineProtocol
+	}
+	if err := filter.Format.Valid(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{
+		{"start", &filter.Start},
+		{"stop", &filter.Stop},
+	} {
+		v := qp.Get(p.name)
+		if v == "" {
+			continue
+		}
+		if *p.t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid request; " + p.name + " must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+	}
+
+	if key := qp.Get("afterKey"); key != "" {
+		t, err := time.Parse(time.RFC3339Nano, qp.Get("afterTime"))
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid request; afterTime must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		filter.After = &influxdb.ExportPosition{
+			Key:   key,
+			Field: qp.Get("afterField"),
+			Time:  t,
+		}
+	}
+	return filter, nil
+}
+
+// handleExport is the HTTP handler for the GET /api/v2/export route.
+func (h *ExportHandler) handleExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeExportRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	ew := &exportResponseWriter{ResponseWriter: w, contentType: exportContentType(filter.Format)}
+	if err := h.ExportService.ExportBucket(ctx, *filter, ew); err != nil {
+		if !ew.written {
+			EncodeError(ctx, err, w)
+			return
+		}
+		// The export is truncated, and is resumed by the client after the last
+		// complete line.
+		h.Logger.Error("Failed to write export", zap.Error(err))
+		return
+	}
+}
+
+// ExportService connects to Influx via HTTP using tokens to export the data of
+// buckets.
+type ExportService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.ExportService = (*ExportService)(nil)
+
+// ExportBucket writes the data of a bucket selected by filter to w.
+func (s *ExportService) ExportBucket(ctx context.Context, filter influxdb.ExportFilter, w io.Writer) error {
+	u, err := newURL(s.Addr, exportPath)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	qp := req.URL.Query()
+	qp.Set("bucketID", filter.BucketID.String())
+	if filter.Format != "" {
+		qp.Set("format", string(filter.Format))
+	}
+	if !filter.Start.IsZero() {
+		qp.Set("start", filter.Start.Format(time.RFC3339Nano))
+	}
+	if !filter.Stop.IsZero() {
+		qp.Set("stop", filter.Stop.Format(time.RFC3339Nano))
+	}
+	if filter.After != nil {
+		qp.Set("afterKey", filter.After.Key)
+		qp.Set("afterField", filter.After.Field)
+		qp.Set("afterTime", filter.After.Time.Format(time.RFC3339Nano))
+	}
+	req.URL.RawQuery = qp.Encode()
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
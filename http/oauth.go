@@ -0,0 +1,138 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
+	"github.com/influxdata/influxdb/identity"
+	"go.uber.org/zap"
+)
+
+const (
+	oauthSigninPath   = "/api/v2/signin/oauth"
+	oauthCallbackPath = "/api/v2/signin/oauth/callback"
+
+	// oauthFailurePath is the page of the UI the browser is redirected to when signing in through the provider fails.
+	oauthFailurePath = "/signin"
+)
+
+// OAuthAuthenticator signs in the users authenticated by an OAuth2 provider,
+// by starting a session of the user provisioned for their identity.
+type OAuthAuthenticator struct {
+	Logger *zap.Logger
+
+	SessionService platform.SessionService
+	Provisioner    *identity.Provisioner
+}
+
+var _ oauth2.Authenticator = (*OAuthAuthenticator)(nil)
+
+// NewOAuthAuthenticator returns a new instance of OAuthAuthenticator.
+func NewOAuthAuthenticator(s platform.SessionService, p *identity.Provisioner) *OAuthAuthenticator {
+	return &OAuthAuthenticator{
+		Logger:         zap.NewNop(),
+		SessionService: s,
+		Provisioner:    p,
+	}
+}
+
+// Validate is not supported, as requests are authenticated with their session by the AuthenticationHandler.
+func (a *OAuthAuthenticator) Validate(ctx context.Context, r *http.Request) (oauth2.Principal, error) {
+	return oauth2.Principal{}, oauth2.ErrAuthentication
+}
+
+// Authorize provisions the user of the principal, whose groups are the comma delimited groups of the principal,
+// and sets the cookie of a new session of the user.
+func (a *OAuthAuthenticator) Authorize(ctx context.Context, w http.ResponseWriter, p oauth2.Principal) error {
+	u, err := a.Provisioner.Provision(ctx, p.Issuer, p.Subject, splitGroups(p.Group))
+	if err != nil {
+		a.Logger.Info("failed to provision user", zap.String("provider", p.Issuer), zap.String("subject", p.Subject), zap.Error(err))
+		return err
+	}
+
+	s, err := a.SessionService.CreateSession(ctx, u.Name)
+	if err != nil {
+		return err
+	}
+
+	// The callback is below /api/v2/signin, so the path of the cookie must be set for it to be sent to the rest of the API.
+	http.SetCookie(w, &http.Cookie{
+		Name:  cookieSessionName,
+		Value: s.Key,
+		Path:  "/",
+	})
+	return nil
+}
+
+// Extend returns p unchanged, as sessions are renewed by the AuthenticationHandler.
+func (a *OAuthAuthenticator) Extend(ctx context.Context, w http.ResponseWriter, p oauth2.Principal) (oauth2.Principal, error) {
+	return p, nil
+}
+
+// Expire clears the session cookie.
+func (a *OAuthAuthenticator) Expire(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   cookieSessionName,
+		Path:   "/",
+		MaxAge: -1,
+	})
+}
+
+func splitGroups(group string) []string {
+	var groups []string
+	for _, g := range strings.Split(group, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// NewOAuthMux returns the mux serving the sign in through an OAuth2 provider.
+// tokenSecret signs the state of the sign ins, and jwksURL is the address of the keys of the provider,
+// with which the id_token is verified if useIDToken is set.
+// The browser is redirected to the UI after signing in, and to its sign in page if signing in fails.
+func NewOAuthMux(p oauth2.Provider, a *OAuthAuthenticator, tokenSecret, jwksURL string, useIDToken bool, logger *zap.Logger) *oauth2.AuthMux {
+	m := oauth2.NewAuthMux(p, a, oauth2.NewJWT(tokenSecret, jwksURL), "", NewChronografLogger(logger), useIDToken)
+	m.FailureURL = oauthFailurePath
+	return m
+}
+
+// chronografLogger adapts a zap logger to the logger of the chronograf packages.
+type chronografLogger struct {
+	logger *zap.SugaredLogger
+}
+
+var _ chronograf.Logger = (*chronografLogger)(nil)
+
+// NewChronografLogger returns a chronograf logger logging to logger.
+func NewChronografLogger(logger *zap.Logger) chronograf.Logger {
+	return &chronografLogger{logger: logger.Sugar()}
+}
+
+func (l *chronografLogger) Debug(args ...interface{}) { l.logger.Debug(args...) }
+func (l *chronografLogger) Info(args ...interface{})  { l.logger.Info(args...) }
+func (l *chronografLogger) Error(args ...interface{}) { l.logger.Error(args...) }
+
+func (l *chronografLogger) WithField(key string, value interface{}) chronograf.Logger {
+	return &chronografLogger{logger: l.logger.With(key, value)}
+}
+
+// Writer returns a writer whose lines are logged at info level until it is closed.
+func (l *chronografLogger) Writer() *io.PipeWriter {
+	pr, pw := io.Pipe()
+	go func() {
+		s := bufio.NewScanner(pr)
+		for s.Scan() {
+			l.logger.Info(s.Text())
+		}
+		pr.CloseWithError(s.Err())
+	}()
+	return pw
+}
@@ -0,0 +1,197 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
+	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/identity"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap"
+)
+
+// newFakeIdentityProvider returns an OAuth2 provider which authenticates any authorization code
+// as the given user, with the given groups.
+func newFakeIdentityProvider(t *testing.T, email string, groups []string) *httptest.Server {
+	const (
+		code        = "the-code"
+		accessToken = "the-access-token"
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		// The user authenticates and is redirected back with an authorization code.
+		u, err := url.Parse(r.FormValue("redirect_uri"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := u.Query()
+		q.Set("code", code)
+		q.Set("state", r.FormValue("state"))
+		u.RawQuery = q.Encode()
+		http.Redirect(w, r, u.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != code {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": accessToken,
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"email":  email,
+			"groups": groups,
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func newOAuthSessionHandler(t *testing.T, idp *httptest.Server) (*platformhttp.SessionHandler, *kv.Service) {
+	t.Helper()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutOrganization(ctx, &platform.Organization{ID: platform.ID(1), Name: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := identity.NewProvisioner(svc, svc, svc, svc)
+	p.GroupMappings = []platform.GroupMapping{
+		{Group: "devs", Org: "acme", UserType: platform.Member},
+	}
+
+	provider := &oauth2.Generic{
+		ClientID:       "influxd",
+		ClientSecret:   "client-secret",
+		RequiredScopes: []string{"openid", "email", "groups"},
+		RedirectURL:    "http://localhost:9999/api/v2/signin/oauth/callback",
+		AuthURL:        idp.URL + "/authorize",
+		TokenURL:       idp.URL + "/token",
+		APIURL:         idp.URL + "/userinfo",
+		APIKey:         "email",
+		GroupsKey:      "groups",
+		Logger:         &chronograf.NoopLogger{},
+	}
+
+	b := NewMockSessionBackend()
+	b.SessionService = svc
+	b.OAuthMux = platformhttp.NewOAuthMux(provider, platformhttp.NewOAuthAuthenticator(svc, p), "token-secret", "", false, zap.NewNop())
+	return platformhttp.NewSessionHandler(b), svc
+}
+
+// serve serves a GET request of u with h, and returns the response.
+func serve(h http.Handler, u string) *http.Response {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", u, nil))
+	return w.Result()
+}
+
+func TestSessionHandler_OAuthSignin(t *testing.T) {
+	idp := newFakeIdentityProvider(t, "alice@example.com", []string{"devs", "testers"})
+	defer idp.Close()
+
+	h, svc := newOAuthSessionHandler(t, idp)
+
+	// Signing in redirects to the provider...
+	res := serve(h, "http://localhost:9999/api/v2/signin/oauth")
+	if res.StatusCode != http.StatusTemporaryRedirect {
+		t.Fatalf("expected redirect to the provider, got status %d", res.StatusCode)
+	}
+	authorize := res.Header.Get("Location")
+	if !strings.HasPrefix(authorize, idp.URL+"/authorize") {
+		t.Fatalf("expected redirect to the provider, got %q", authorize)
+	}
+
+	// ...which authenticates the user and redirects back to the callback.
+	hc := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	idpRes, err := hc.Get(authorize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idpRes.Body.Close()
+	callback := idpRes.Header.Get("Location")
+	if !strings.HasPrefix(callback, "http://localhost:9999/api/v2/signin/oauth/callback") {
+		t.Fatalf("expected redirect to the callback, got %q", callback)
+	}
+
+	res = serve(h, callback)
+	if res.StatusCode != http.StatusTemporaryRedirect || res.Header.Get("Location") != "/" {
+		t.Fatalf("expected redirect to the UI, got status %d to %q", res.StatusCode, res.Header.Get("Location"))
+	}
+
+	var key string
+	for _, c := range res.Cookies() {
+		if c.Name == "session" {
+			key = c.Value
+			if c.Path != "/" {
+				t.Errorf("expected the session cookie to be sent to the whole API, got path %q", c.Path)
+			}
+		}
+	}
+	if key == "" {
+		t.Fatal("expected a session cookie")
+	}
+
+	ctx := context.Background()
+	s, err := svc.FindSession(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := svc.FindUserByID(ctx, s.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "alice@example.com" {
+		t.Errorf("expected a session of the provisioned user, got user %q", u.Name)
+	}
+
+	ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: u.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].ResourceID != platform.ID(1) || ms[0].UserType != platform.Member {
+		t.Errorf("expected the user to be a member of the organization of their group, got %+v", ms)
+	}
+}
+
+func TestSessionHandler_OAuthSignin_InvalidState(t *testing.T) {
+	idp := newFakeIdentityProvider(t, "alice@example.com", nil)
+	defer idp.Close()
+
+	h, _ := newOAuthSessionHandler(t, idp)
+
+	res := serve(h, "http://localhost:9999/api/v2/signin/oauth/callback?code=the-code&state=forged")
+	if res.StatusCode != http.StatusTemporaryRedirect || res.Header.Get("Location") != "/signin" {
+		t.Fatalf("expected redirect to the sign in page, got status %d to %q", res.StatusCode, res.Header.Get("Location"))
+	}
+	for _, c := range res.Cookies() {
+		if c.Name == "session" {
+			t.Errorf("expected no session cookie, got %q", c.Value)
+		}
+	}
+}
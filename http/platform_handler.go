@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 // PlatformHandler is a collection of all the service handlers.
@@ -26,12 +27,21 @@ func setCORSResponseHeaders(w http.ResponseWriter, r *http.Request) {
 func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 	h := NewAuthenticationHandler()
 	h.Handler = NewAPIHandler(b)
+	if b.AuditLogService != nil {
+		ah := NewAuditingHandler()
+		ah.Logger = b.Logger.With(zap.String("handler", "auditing"))
+		ah.AuditLogService = b.AuditLogService
+		ah.Handler = h.Handler
+		h.Handler = ah
+	}
 	h.AuthorizationService = b.AuthorizationService
 	h.SessionService = b.SessionService
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signout")
+	h.RegisterNoAuthRoute("GET", oauthSigninPath)
+	h.RegisterNoAuthRoute("GET", oauthCallbackPath)
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
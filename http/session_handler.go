@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf/oauth2"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -16,6 +17,9 @@ type SessionBackend struct {
 
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
+
+	// OAuthMux serves the sign in through an OAuth2 provider; if nil, only passwords can be used to sign in.
+	OAuthMux oauth2.Mux
 }
 
 // NewSessionBackend creates a new SessionBackend with associated logger.
@@ -25,6 +29,7 @@ func NewSessionBackend(b *APIBackend) *SessionBackend {
 
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
+		OAuthMux:         b.OAuthMux,
 	}
 }
 
@@ -49,6 +54,12 @@ func NewSessionHandler(b *SessionBackend) *SessionHandler {
 
 	h.HandlerFunc("POST", "/api/v2/signin", h.handleSignin)
 	h.HandlerFunc("POST", "/api/v2/signout", h.handleSignout)
+
+	if b.OAuthMux != nil {
+		// Signing in redirects to the provider, which redirects back to the callback once the user is authenticated.
+		h.Handler("GET", oauthSigninPath, b.OAuthMux.Login())
+		h.Handler("GET", oauthCallbackPath, b.OAuthMux.Callback())
+	}
 	return h
 }
 
@@ -13,7 +13,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/andreyvit/diff"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/authorizer"
 	pcontext "github.com/influxdata/influxdb/context"
@@ -37,6 +39,7 @@ type TaskBackend struct {
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 	BucketService              platform.BucketService
+	QueryService               query.QueryService
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -50,13 +53,16 @@ func NewTaskBackend(b *APIBackend) *TaskBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		QueryService:               b.QueryService,
 	}
 }
 
 // TaskHandler represents an HTTP API handler for tasks.
 type TaskHandler struct {
 	*httprouter.Router
-	logger *zap.Logger
+	// validateRouter routes the validate path, which the Router can't match next to the task ID parameter.
+	validateRouter *httprouter.Router
+	logger         *zap.Logger
 
 	TaskService                platform.TaskService
 	AuthorizationService       platform.AuthorizationService
@@ -65,6 +71,7 @@ type TaskHandler struct {
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 	BucketService              platform.BucketService
+	QueryService               query.QueryService
 }
 
 const (
@@ -81,13 +88,24 @@ const (
 	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
 	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
 	tasksIDLabelsIDPath    = "/api/v2/tasks/:id/labels/:lid"
+	tasksIDBackfillPath    = "/api/v2/tasks/:id/backfill"
+	tasksIDBackfillIDPath  = "/api/v2/tasks/:id/backfill/:bid"
+	tasksIDRevisionsPath   = "/api/v2/tasks/:id/revisions"
+	tasksIDRevisionsIDPath = "/api/v2/tasks/:id/revisions/:rev"
+	tasksIDRevDiffPath     = "/api/v2/tasks/:id/revisions/:rev/diff"
+	tasksIDRollbackPath    = "/api/v2/tasks/:id/revisions/:rev/rollback"
+	tasksValidatePath      = "/api/v2/tasks/validate"
+
+	// taskExecutionTimeout bounds how long the script of a task runs when executed while validating it.
+	taskExecutionTimeout = 30 * time.Second
 )
 
 // NewTaskHandler returns a new instance of TaskHandler.
 func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h := &TaskHandler{
-		Router: NewRouter(),
-		logger: b.Logger,
+		Router:         NewRouter(),
+		validateRouter: NewRouter(),
+		logger:         b.Logger,
 
 		TaskService:                b.TaskService,
 		AuthorizationService:       b.AuthorizationService,
@@ -96,11 +114,14 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		QueryService:               b.QueryService,
 	}
 
 	h.HandlerFunc("GET", tasksPath, h.handleGetTasks)
 	h.HandlerFunc("POST", tasksPath, h.handlePostTask)
 
+	h.validateRouter.HandlerFunc("POST", tasksValidatePath, h.handlePostTaskValidate)
+
 	h.HandlerFunc("GET", tasksIDPath, h.handleGetTask)
 	h.HandlerFunc("PATCH", tasksIDPath, h.handleUpdateTask)
 	h.HandlerFunc("DELETE", tasksIDPath, h.handleDeleteTask)
@@ -136,6 +157,16 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
+	h.HandlerFunc("GET", tasksIDBackfillPath, h.handleGetBackfills)
+	h.HandlerFunc("POST", tasksIDBackfillPath, h.handlePostBackfill)
+	h.HandlerFunc("PATCH", tasksIDBackfillIDPath, h.handlePatchBackfill)
+	h.HandlerFunc("DELETE", tasksIDBackfillIDPath, h.handleDeleteBackfill)
+
+	h.HandlerFunc("GET", tasksIDRevisionsPath, h.handleGetTaskRevisions)
+	h.HandlerFunc("GET", tasksIDRevisionsIDPath, h.handleGetTaskRevision)
+	h.HandlerFunc("GET", tasksIDRevDiffPath, h.handleGetTaskRevisionDiff)
+	h.HandlerFunc("POST", tasksIDRollbackPath, h.handlePostTaskRollback)
+
 	labelBackend := &LabelBackend{
 		Logger:       b.Logger.With(zap.String("handler", "label")),
 		LabelService: b.LabelService,
@@ -257,6 +288,79 @@ func newRunsResponse(rs []*platform.Run, taskID platform.ID) runsResponse {
 	return r
 }
 
+type backfillResponse struct {
+	Links map[string]string `json:"links"`
+	platform.Backfill
+}
+
+func newBackfillResponse(b platform.Backfill) *backfillResponse {
+	return &backfillResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/tasks/%s/backfill/%s", b.TaskID, b.ID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", b.TaskID),
+			"runs": fmt.Sprintf("/api/v2/tasks/%s/runs", b.TaskID),
+		},
+		Backfill: b,
+	}
+}
+
+type backfillsResponse struct {
+	Links     map[string]string   `json:"links"`
+	Backfills []*backfillResponse `json:"backfills"`
+}
+
+func newBackfillsResponse(bs []*platform.Backfill, taskID platform.ID) backfillsResponse {
+	r := backfillsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/tasks/%s/backfill", taskID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", taskID),
+		},
+		Backfills: make([]*backfillResponse, len(bs)),
+	}
+
+	for i := range bs {
+		r.Backfills[i] = newBackfillResponse(*bs[i])
+	}
+	return r
+}
+
+type taskRevisionResponse struct {
+	Links map[string]string `json:"links"`
+	platform.TaskRevision
+}
+
+func newTaskRevisionResponse(rev platform.TaskRevision) *taskRevisionResponse {
+	return &taskRevisionResponse{
+		Links: map[string]string{
+			"self":     fmt.Sprintf("/api/v2/tasks/%s/revisions/%d", rev.TaskID, rev.Revision),
+			"task":     fmt.Sprintf("/api/v2/tasks/%s", rev.TaskID),
+			"diff":     fmt.Sprintf("/api/v2/tasks/%s/revisions/%d/diff", rev.TaskID, rev.Revision),
+			"rollback": fmt.Sprintf("/api/v2/tasks/%s/revisions/%d/rollback", rev.TaskID, rev.Revision),
+		},
+		TaskRevision: rev,
+	}
+}
+
+type taskRevisionsResponse struct {
+	Links     map[string]string       `json:"links"`
+	Revisions []*taskRevisionResponse `json:"revisions"`
+}
+
+func newTaskRevisionsResponse(revs []*platform.TaskRevision, taskID platform.ID) taskRevisionsResponse {
+	r := taskRevisionsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/tasks/%s/revisions", taskID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", taskID),
+		},
+		Revisions: make([]*taskRevisionResponse, len(revs)),
+	}
+
+	for i := range revs {
+		r.Revisions[i] = newTaskRevisionResponse(*revs[i])
+	}
+	return r
+}
+
 func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -335,6 +439,14 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs platform.O
 		req.filter.User = id
 	}
 
+	if dependsOn := qp.Get("dependsOn"); dependsOn != "" {
+		id, err := platform.IDFromString(dependsOn)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.DependsOn = id
+	}
+
 	if limit := qp.Get("limit"); limit != "" {
 		lim, err := strconv.Atoi(limit)
 		if err != nil {
@@ -550,6 +662,227 @@ func decodePostTaskRequest(ctx context.Context, r *http.Request) (*postTaskReque
 	}, nil
 }
 
+// ServeHTTP routes requests to the validate path with their own router, and all other requests with the Router.
+func (h *TaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == tasksValidatePath {
+		h.validateRouter.ServeHTTP(w, r)
+		return
+	}
+	h.Router.ServeHTTP(w, r)
+}
+
+// handlePostTaskValidate is the HTTP handler for the POST /api/v2/tasks/validate route.
+func (h *TaskHandler) handlePostTaskValidate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodePostTaskValidateRequest(ctx, r)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if req.Execute {
+		tc := platform.TaskCreate{OrganizationID: req.OrganizationID, Organization: req.Organization}
+		if err := h.populateTaskCreateOrg(ctx, &tc); err != nil {
+			err = &platform.Error{
+				Err: err,
+				Msg: "could not identify organization",
+			}
+			EncodeError(ctx, err, w)
+			return
+		}
+		req.OrganizationID = tc.OrganizationID
+	}
+
+	res, err := h.validateTask(ctx, auth, req)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func decodePostTaskValidateRequest(ctx context.Context, r *http.Request) (*platform.TaskValidation, error) {
+	var v platform.TaskValidation
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// validateTask parses the options of the script of v, compiles it,
+// and verifies that the authorizer a may access the buckets the script reads and writes.
+// It reports the options and next scheduled runs of the task the script would create,
+// and if requested, the outcome of running the script once.
+//
+// This method returns a *platform.Error, suitable for directly passing to EncodeError.
+func (h *TaskHandler) validateTask(ctx context.Context, a platform.Authorizer, v *platform.TaskValidation) (*platform.TaskValidationResult, error) {
+	opts, err := options.FromScript(v.Flux)
+	if err != nil {
+		return nil, &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "invalid task options",
+		}
+	}
+
+	now := time.Now()
+	if v.Now != "" {
+		// Already validated when decoding the request.
+		now, _ = time.Parse(time.RFC3339, v.Now)
+	}
+
+	spec, err := flux.Compile(ctx, v.Flux, now)
+	if err != nil {
+		return nil, &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to compile flux script",
+		}
+	}
+
+	preAuthorizer := query.NewPreAuthorizer(h.BucketService)
+	ps, err := preAuthorizer.RequiredPermissions(ctx, spec)
+	if err != nil {
+		return nil, &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to find buckets of flux script",
+		}
+	}
+	if err := preAuthorizer.PreAuthorize(ctx, spec, a); err != nil {
+		return nil, &platform.Error{
+			Err:  err,
+			Code: platform.EForbidden,
+			Msg:  "insufficient permissions for flux script",
+		}
+	}
+
+	res := &platform.TaskValidationResult{
+		Name:        opts.Name,
+		Cron:        opts.Cron,
+		Concurrency: opts.Concurrency,
+		Retry:       opts.Retry,
+		Permissions: ps,
+	}
+	if opts.Every != 0 {
+		res.Every = opts.Every.String()
+	}
+	if opts.Offset != 0 {
+		res.Offset = opts.Offset.String()
+	}
+	for _, s := range opts.DependsOn {
+		id, err := platform.IDFromString(s)
+		if err != nil {
+			continue
+		}
+		res.DependsOn = append(res.DependsOn, *id)
+	}
+
+	runs := v.Runs
+	if runs == 0 {
+		runs = platform.TaskValidationDefaultRuns
+	}
+	stm := backend.NewStoreTaskMeta(backend.CreateTaskRequest{ScheduleAfter: now.Unix()}, opts)
+	schedules, err := stm.NextSchedules(runs)
+	if err != nil {
+		return nil, &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to compute task schedule",
+		}
+	}
+	res.NextRuns = make([]string, 0, len(schedules))
+	for _, sch := range schedules {
+		res.NextRuns = append(res.NextRuns, time.Unix(sch, 0).UTC().Format(time.RFC3339))
+	}
+
+	if v.Execute {
+		if res.Execution, err = h.executeTask(ctx, v.OrganizationID, spec); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// executeTask runs spec once without writing the output of to(), and summarizes its results.
+// Errors of the query are reported in the execution, rather than failing the validation.
+func (h *TaskHandler) executeTask(ctx context.Context, orgID platform.ID, spec *flux.Spec) (*platform.TaskExecution, error) {
+	if h.QueryService == nil {
+		return nil, &platform.Error{
+			Code: platform.EUnavailable,
+			Msg:  "executing task scripts is not supported",
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, taskExecutionTimeout)
+	defer cancel()
+
+	ex := &platform.TaskExecution{
+		ScheduledFor: spec.Now.UTC().Format(time.RFC3339),
+		Results:      []platform.TaskExecutionResult{},
+	}
+
+	it, err := h.QueryService.Query(ctx, &query.Request{
+		OrganizationID: orgID,
+		Compiler: lang.SpecCompiler{
+			Spec: query.WithoutWrites(spec),
+		},
+	})
+	if err != nil {
+		ex.Error = err.Error()
+		return ex, nil
+	}
+	defer it.Release()
+
+	for it.More() {
+		res := it.Next()
+		r := platform.TaskExecutionResult{Name: res.Name()}
+		err := res.Tables().Do(func(tbl flux.Table) error {
+			r.Tables++
+			return tbl.Do(func(cr flux.ColReader) error {
+				r.Rows += cr.Len()
+				return nil
+			})
+		})
+		if err != nil && ex.Error == "" {
+			ex.Error = err.Error()
+		}
+		ex.Results = append(ex.Results, r)
+	}
+	if err := it.Err(); err != nil && ex.Error == "" {
+		ex.Error = err.Error()
+	}
+
+	return ex, nil
+}
+
 func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -797,12 +1130,16 @@ func (h *TaskHandler) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := encodeResponse(ctx, w, http.StatusOK, logs); err != nil {
+	if err := encodeResponse(ctx, w, http.StatusOK, &getLogsResponse{Events: logs}); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
 }
 
+type getLogsResponse struct {
+	Events []*platform.Log `json:"events"`
+}
+
 type getLogsRequest struct {
 	filter platform.LogFilter
 }
@@ -1199,102 +1536,452 @@ func (h *TaskHandler) handleCancelRun(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
+// backfillError wraps an error of the backfills of a task with msg,
+// and with the code of the error if it is an error of the task store.
+func backfillError(err error, msg string) *platform.Error {
+	e := &platform.Error{
+		Err: err,
+		Msg: msg,
+	}
+	switch err {
+	case backend.ErrTaskNotFound, backend.ErrBackfillNotFound:
+		e.Code = platform.ENotFound
+	case backend.ErrNoSchedulesInRange:
+		e.Code = platform.EInvalid
+	case backend.ErrManualQueueFull:
+		e.Code = platform.EConflict
+	}
+	if _, ok := err.(backend.RequestStillQueuedError); ok {
+		e.Code = platform.EConflict
+	}
+	return e
+}
+
+func (h *TaskHandler) handleGetBackfills(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	req, err := decodeRetryRunRequest(ctx, r)
+	taskID, _, err := decodeBackfillIDs(ctx, false)
 	if err != nil {
-		err = &platform.Error{
-			Err:  err,
-			Code: platform.EInvalid,
-			Msg:  "failed to decode request",
-		}
 		EncodeError(ctx, err, w)
 		return
 	}
 
-	auth, err := pcontext.GetAuthorizer(ctx)
+	backfills, err := h.TaskService.FindBackfills(ctx, taskID)
 	if err != nil {
-		err = &platform.Error{
-			Err:  err,
-			Code: platform.EUnauthorized,
-			Msg:  "failed to get authorizer",
-		}
-		EncodeError(ctx, err, w)
+		EncodeError(ctx, backfillError(err, "failed to find backfills"), w)
 		return
 	}
 
-	if k := auth.Kind(); k != platform.AuthorizationKind {
-		// Get the authorization for the task, if allowed.
-		authz, err := h.getAuthorizationForTask(ctx, req.TaskID)
-		if err != nil {
-			EncodeError(ctx, err, w)
-			return
-		}
-
-		// We were able to access the authorizer for the task, so reassign that on the context for the rest of this call.
-		ctx = pcontext.SetAuthorizer(ctx, authz)
-	}
-
-	run, err := h.TaskService.RetryRun(ctx, req.TaskID, req.RunID)
-	if err != nil {
-		err := &platform.Error{
-			Err: err,
-			Msg: "failed to retry run",
-		}
-		if err.Err == backend.ErrTaskNotFound || err.Err == backend.ErrRunNotFound {
-			err.Code = platform.ENotFound
-		}
-		EncodeError(ctx, err, w)
-		return
-	}
-	if err := encodeResponse(ctx, w, http.StatusOK, newRunResponse(*run)); err != nil {
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillsResponse(backfills, taskID)); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
 }
 
-type retryRunRequest struct {
-	RunID, TaskID platform.ID
+type postBackfillRequest struct {
+	TaskID      platform.ID
+	Start, Stop int64
 }
 
-func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunRequest, error) {
-	params := httprouter.ParamsFromContext(ctx)
-	tid := params.ByName("id")
-	if tid == "" {
+func decodePostBackfillRequest(ctx context.Context, r *http.Request) (*postBackfillRequest, error) {
+	taskID, _, err := decodeBackfillIDs(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Start string `json:"start"`
+		Stop  string `json:"stop"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		return nil, &platform.Error{
 			Code: platform.EInvalid,
-			Msg:  "you must provide a task ID",
+			Msg:  "failed to decode request",
+			Err:  err,
 		}
 	}
-	rid := params.ByName("rid")
-	if rid == "" {
+
+	start, err := time.Parse(time.RFC3339, body.Start)
+	if err != nil {
 		return nil, &platform.Error{
 			Code: platform.EInvalid,
-			Msg:  "you must provide a run ID",
+			Msg:  "start must be an RFC3339 time",
+			Err:  err,
 		}
 	}
-
-	var ti, ri platform.ID
-	if err := ti.DecodeFromString(tid); err != nil {
-		return nil, err
-	}
-	if err := ri.DecodeFromString(rid); err != nil {
-		return nil, err
+	stop, err := time.Parse(time.RFC3339, body.Stop)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "stop must be an RFC3339 time",
+			Err:  err,
+		}
 	}
 
-	return &retryRunRequest{
-		RunID:  ri,
-		TaskID: ti,
+	return &postBackfillRequest{
+		TaskID: taskID,
+		Start:  start.Unix(),
+		Stop:   stop.Unix(),
 	}, nil
 }
 
-func (h *TaskHandler) populateTaskCreateOrg(ctx context.Context, tc *platform.TaskCreate) error {
-	if tc.OrganizationID.Valid() && tc.Organization != "" {
-		return nil
-	}
+func (h *TaskHandler) handlePostBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-	if !tc.OrganizationID.Valid() && tc.Organization == "" {
+	req, err := decodePostBackfillRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	b, err := h.TaskService.CreateBackfill(ctx, req.TaskID, req.Start, req.Stop)
+	if err != nil {
+		EncodeError(ctx, backfillError(err, "failed to create backfill"), w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newBackfillResponse(*b)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handlePatchBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, backfillID, err := decodeBackfillIDs(ctx, true)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var upd platform.BackfillUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := upd.Validate(); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	b, err := h.TaskService.UpdateBackfill(ctx, taskID, backfillID, upd)
+	if err != nil {
+		EncodeError(ctx, backfillError(err, "failed to update backfill"), w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillResponse(*b)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleDeleteBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, backfillID, err := decodeBackfillIDs(ctx, true)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.TaskService.CancelBackfill(ctx, taskID, backfillID); err != nil {
+		EncodeError(ctx, backfillError(err, "failed to cancel backfill"), w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeBackfillIDs decodes the task ID of a backfill route, and the backfill ID if withBackfill is true.
+func decodeBackfillIDs(ctx context.Context, withBackfill bool) (taskID, backfillID platform.ID, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+	if err := taskID.DecodeFromString(params.ByName("id")); err != nil {
+		return 0, 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "you must provide a valid task ID",
+			Err:  err,
+		}
+	}
+
+	if withBackfill {
+		if err := backfillID.DecodeFromString(params.ByName("bid")); err != nil {
+			return 0, 0, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "you must provide a valid backfill ID",
+				Err:  err,
+			}
+		}
+	}
+	return taskID, backfillID, nil
+}
+
+// revisionError wraps an error of the revisions of a task with msg,
+// and with the code of the error if it is an error of the task store.
+func revisionError(err error, msg string) *platform.Error {
+	e := &platform.Error{
+		Err: err,
+		Msg: msg,
+	}
+	switch err {
+	case backend.ErrTaskNotFound, backend.ErrRevisionNotFound:
+		e.Code = platform.ENotFound
+	}
+	return e
+}
+
+func (h *TaskHandler) handleGetTaskRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, _, err := decodeRevisionIDs(ctx, false)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	revs, err := h.TaskService.FindTaskRevisions(ctx, taskID)
+	if err != nil {
+		EncodeError(ctx, revisionError(err, "failed to find task revisions"), w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskRevisionsResponse(revs, taskID)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleGetTaskRevision(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, revision, err := decodeRevisionIDs(ctx, true)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	rev, err := h.TaskService.FindTaskRevision(ctx, taskID, revision)
+	if err != nil {
+		EncodeError(ctx, revisionError(err, "failed to find task revision"), w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskRevisionResponse(*rev)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// handleGetTaskRevisionDiff is the HTTP handler for the GET /api/v2/tasks/:id/revisions/:rev/diff route.
+// It compares the revision to the one given by the from query parameter, or else to the previous revision.
+func (h *TaskHandler) handleGetTaskRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, to, err := decodeRevisionIDs(ctx, true)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	from := to - 1
+	if s := r.URL.Query().Get("from"); s != "" {
+		from, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || from < 1 {
+			EncodeError(ctx, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "from must be a positive revision number",
+			}, w)
+			return
+		}
+	}
+
+	toRev, err := h.TaskService.FindTaskRevision(ctx, taskID, to)
+	if err != nil {
+		EncodeError(ctx, revisionError(err, "failed to find task revision"), w)
+		return
+	}
+
+	// The first revision is compared to an empty script.
+	var fromFlux string
+	if from > 0 {
+		fromRev, err := h.TaskService.FindTaskRevision(ctx, taskID, from)
+		if err != nil {
+			EncodeError(ctx, revisionError(err, "failed to find task revision"), w)
+			return
+		}
+		fromFlux = fromRev.Flux
+	}
+
+	d := platform.TaskRevisionDiff{
+		TaskID: taskID,
+		From:   from,
+		To:     to,
+		Diff:   diff.LineDiff(fromFlux, toRev.Flux),
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, d); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handlePostTaskRollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, revision, err := decodeRevisionIDs(ctx, true)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.RollbackTask(ctx, taskID, revision)
+	if err != nil {
+		EncodeError(ctx, revisionError(err, "failed to roll back task"), w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{ResourceID: task.ID})
+	if err != nil {
+		err = &platform.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// decodeRevisionIDs decodes the task ID of a revision route, and the revision number if withRevision is true.
+func decodeRevisionIDs(ctx context.Context, withRevision bool) (taskID platform.ID, revision int64, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+	if err := taskID.DecodeFromString(params.ByName("id")); err != nil {
+		return 0, 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "you must provide a valid task ID",
+			Err:  err,
+		}
+	}
+
+	if withRevision {
+		revision, err = strconv.ParseInt(params.ByName("rev"), 10, 64)
+		if err != nil || revision < 1 {
+			return 0, 0, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "you must provide a positive revision number",
+			}
+		}
+	}
+	return taskID, revision, nil
+}
+
+func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeRetryRunRequest(ctx, r)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if k := auth.Kind(); k != platform.AuthorizationKind {
+		// Get the authorization for the task, if allowed.
+		authz, err := h.getAuthorizationForTask(ctx, req.TaskID)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+
+		// We were able to access the authorizer for the task, so reassign that on the context for the rest of this call.
+		ctx = pcontext.SetAuthorizer(ctx, authz)
+	}
+
+	run, err := h.TaskService.RetryRun(ctx, req.TaskID, req.RunID)
+	if err != nil {
+		err := &platform.Error{
+			Err: err,
+			Msg: "failed to retry run",
+		}
+		if err.Err == backend.ErrTaskNotFound || err.Err == backend.ErrRunNotFound {
+			err.Code = platform.ENotFound
+		}
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunResponse(*run)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+type retryRunRequest struct {
+	RunID, TaskID platform.ID
+}
+
+func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	tid := params.ByName("id")
+	if tid == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "you must provide a task ID",
+		}
+	}
+	rid := params.ByName("rid")
+	if rid == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "you must provide a run ID",
+		}
+	}
+
+	var ti, ri platform.ID
+	if err := ti.DecodeFromString(tid); err != nil {
+		return nil, err
+	}
+	if err := ri.DecodeFromString(rid); err != nil {
+		return nil, err
+	}
+
+	return &retryRunRequest{
+		RunID:  ri,
+		TaskID: ti,
+	}, nil
+}
+
+func (h *TaskHandler) populateTaskCreateOrg(ctx context.Context, tc *platform.TaskCreate) error {
+	if tc.OrganizationID.Valid() && tc.Organization != "" {
+		return nil
+	}
+
+	if !tc.OrganizationID.Valid() && tc.Organization == "" {
 		return errors.New("missing orgID and organization name")
 	}
 
@@ -1409,6 +2096,9 @@ func (t TaskService) FindTasks(ctx context.Context, filter platform.TaskFilter)
 	if filter.User != nil {
 		val.Add("user", filter.User.String())
 	}
+	if filter.DependsOn != nil {
+		val.Add("dependsOn", filter.DependsOn.String())
+	}
 	if filter.Limit != 0 {
 		val.Add("limit", strconv.Itoa(filter.Limit))
 	}
@@ -1429,34 +2119,73 @@ func (t TaskService) FindTasks(ctx context.Context, filter platform.TaskFilter)
 	defer resp.Body.Close()
 
 	if err := CheckError(resp); err != nil {
-		return nil, 0, err
+		return nil, 0, err
+	}
+
+	var tr tasksResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, 0, err
+	}
+
+	tasks := make([]*platform.Task, len(tr.Tasks))
+	for i := range tr.Tasks {
+		tasks[i] = &tr.Tasks[i].Task
+	}
+	return tasks, len(tasks), nil
+}
+
+// CreateTask creates a new task.
+func (t TaskService) CreateTask(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+	u, err := newURL(t.Addr, tasksPath)
+	if err != nil {
+		return nil, err
+	}
+
+	taskBytes, err := json.Marshal(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(taskBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
 	}
 
-	var tr tasksResponse
+	var tr taskResponse
 	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
-		return nil, 0, err
-	}
-
-	tasks := make([]*platform.Task, len(tr.Tasks))
-	for i := range tr.Tasks {
-		tasks[i] = &tr.Tasks[i].Task
+		return nil, err
 	}
-	return tasks, len(tasks), nil
+	return &tr.Task, nil
 }
 
-// CreateTask creates a new task.
-func (t TaskService) CreateTask(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
-	u, err := newURL(t.Addr, tasksPath)
+// ValidateTask checks the Flux script of a task without creating the task.
+func (t TaskService) ValidateTask(ctx context.Context, v platform.TaskValidation) (*platform.TaskValidationResult, error) {
+	u, err := newURL(t.Addr, tasksValidatePath)
 	if err != nil {
 		return nil, err
 	}
 
-	taskBytes, err := json.Marshal(tc)
+	validationBytes, err := json.Marshal(v)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(taskBytes))
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(validationBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -1476,11 +2205,11 @@ func (t TaskService) CreateTask(ctx context.Context, tc platform.TaskCreate) (*p
 		return nil, err
 	}
 
-	var tr taskResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+	var res platform.TaskValidationResult
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, err
 	}
-	return &tr.Task, nil
+	return &res, nil
 }
 
 // UpdateTask updates a single task with changeset.
@@ -1585,12 +2314,12 @@ func (t TaskService) FindLogs(ctx context.Context, filter platform.LogFilter) ([
 		return nil, 0, err
 	}
 
-	var logs []*platform.Log
-	if err := json.NewDecoder(resp.Body).Decode(&logs); err != nil {
+	var lr getLogsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&lr); err != nil {
 		return nil, 0, err
 	}
 
-	return logs, len(logs), nil
+	return lr.Events, len(lr.Events), nil
 }
 
 // FindRuns returns a list of runs that match a filter and the total count of returned runs.
@@ -1805,6 +2534,293 @@ func (t TaskService) CancelRun(ctx context.Context, taskID, runID platform.ID) e
 	return nil
 }
 
+// CreateBackfill requests runs of a task for each of its schedules from start to stop.
+func (t TaskService) CreateBackfill(ctx context.Context, taskID platform.ID, start, stop int64) (*platform.Backfill, error) {
+	u, err := newURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"start": time.Unix(start, 0).UTC().Format(time.RFC3339),
+		"stop":  time.Unix(stop, 0).UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var br backfillResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		return nil, err
+	}
+	return &br.Backfill, nil
+}
+
+// FindBackfills returns the backfills of a task which still have runs to be created.
+func (t TaskService) FindBackfills(ctx context.Context, taskID platform.ID) ([]*platform.Backfill, error) {
+	u, err := newURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var br backfillsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		return nil, err
+	}
+
+	backfills := make([]*platform.Backfill, len(br.Backfills))
+	for i := range br.Backfills {
+		backfills[i] = &br.Backfills[i].Backfill
+	}
+	return backfills, nil
+}
+
+// UpdateBackfill pauses or resumes a backfill.
+func (t TaskService) UpdateBackfill(ctx context.Context, taskID, backfillID platform.ID, upd platform.BackfillUpdate) (*platform.Backfill, error) {
+	u, err := newURL(t.Addr, taskIDBackfillIDPath(taskID, backfillID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(upd)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var br backfillResponse
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		return nil, err
+	}
+	return &br.Backfill, nil
+}
+
+// CancelBackfill removes a backfill and cancels its runs in progress.
+func (t TaskService) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) error {
+	u, err := newURL(t.Addr, taskIDBackfillIDPath(taskID, backfillID))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckErrorStatus(http.StatusNoContent, resp)
+}
+
+// FindTaskRevisions returns the revisions of the script of a task, oldest first.
+func (t TaskService) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*platform.TaskRevision, error) {
+	u, err := newURL(t.Addr, taskIDRevisionsPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var rr taskRevisionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return nil, err
+	}
+
+	revs := make([]*platform.TaskRevision, len(rr.Revisions))
+	for i := range rr.Revisions {
+		revs[i] = &rr.Revisions[i].TaskRevision
+	}
+	return revs, nil
+}
+
+// FindTaskRevision returns a single revision of the script of a task.
+func (t TaskService) FindTaskRevision(ctx context.Context, taskID platform.ID, revision int64) (*platform.TaskRevision, error) {
+	u, err := newURL(t.Addr, taskIDRevisionIDPath(taskID, revision))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var rr taskRevisionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rr); err != nil {
+		return nil, err
+	}
+	return &rr.TaskRevision, nil
+}
+
+// DiffTaskRevisions compares the scripts of two revisions of a task.
+// If from is zero, the revision before to is compared.
+func (t TaskService) DiffTaskRevisions(ctx context.Context, taskID platform.ID, from, to int64) (*platform.TaskRevisionDiff, error) {
+	u, err := newURL(t.Addr, path.Join(taskIDRevisionIDPath(taskID, to), "diff"))
+	if err != nil {
+		return nil, err
+	}
+	if from != 0 {
+		val := url.Values{}
+		val.Set("from", strconv.FormatInt(from, 10))
+		u.RawQuery = val.Encode()
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var d platform.TaskRevisionDiff
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RollbackTask restores the script of a previous revision of a task, which is recorded as a new revision.
+func (t TaskService) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*platform.Task, error) {
+	u, err := newURL(t.Addr, path.Join(taskIDRevisionIDPath(taskID, revision), "rollback"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var tr taskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, err
+	}
+	return &tr.Task, nil
+}
+
 func taskIDPath(id platform.ID) string {
 	return path.Join(tasksPath, id.String())
 }
@@ -1816,3 +2832,19 @@ func taskIDRunsPath(id platform.ID) string {
 func taskIDRunIDPath(taskID, runID platform.ID) string {
 	return path.Join(tasksPath, taskID.String(), "runs", runID.String())
 }
+
+func taskIDBackfillPath(id platform.ID) string {
+	return path.Join(tasksPath, id.String(), "backfill")
+}
+
+func taskIDBackfillIDPath(taskID, backfillID platform.ID) string {
+	return path.Join(tasksPath, taskID.String(), "backfill", backfillID.String())
+}
+
+func taskIDRevisionsPath(id platform.ID) string {
+	return path.Join(tasksPath, id.String(), "revisions")
+}
+
+func taskIDRevisionIDPath(taskID platform.ID, revision int64) string {
+	return path.Join(tasksPath, taskID.String(), "revisions", strconv.FormatInt(revision, 10))
+}
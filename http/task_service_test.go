@@ -442,6 +442,268 @@ func TestTaskHandler_handlePostTasks(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_handlePostTaskValidate(t *testing.T) {
+	const script = `option task = {name: "task1", every: 1h, concurrency: 1, retry: 1}
+from(bucket: "b1") |> range(start: -1h) |> to(bucket: "b2", org: "test")`
+
+	bucketService := mock.NewBucketService()
+	bucketService.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+		switch *filter.Name {
+		case "b1":
+			return &platform.Bucket{ID: 1, OrganizationID: 1, Name: "b1"}, nil
+		case "b2":
+			return &platform.Bucket{ID: 2, OrganizationID: 1, Name: "b2"}, nil
+		}
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+	}
+
+	type args struct {
+		validation platform.TaskValidation
+		auth       *platform.Authorization
+	}
+	type wants struct {
+		statusCode  int
+		contentType string
+		body        string
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "valid task",
+			args: args{
+				validation: platform.TaskValidation{
+					Flux: script,
+					Runs: 2,
+					Now:  "2019-01-01T00:00:00Z",
+				},
+				auth: &platform.Authorization{
+					Status: platform.Active,
+					Permissions: []platform.Permission{
+						{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, ID: platformtesting.IDPtr(1)}},
+						{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, ID: platformtesting.IDPtr(2)}},
+					},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "name": "task1",
+  "every": "1h0m0s",
+  "concurrency": 1,
+  "retry": 1,
+  "permissions": [
+    {"action": "read", "resource": {"type": "buckets", "id": "0000000000000001", "orgID": "0000000000000001"}},
+    {"action": "write", "resource": {"type": "buckets", "id": "0000000000000002", "orgID": "0000000000000001"}}
+  ],
+  "nextRuns": ["2019-01-01T01:00:00Z", "2019-01-01T02:00:00Z"]
+}
+`,
+			},
+		},
+		{
+			name: "missing write permission",
+			args: args{
+				validation: platform.TaskValidation{
+					Flux: script,
+				},
+				auth: &platform.Authorization{
+					Status: platform.Active,
+					Permissions: []platform.Permission{
+						{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, ID: platformtesting.IDPtr(1)}},
+					},
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusForbidden,
+			},
+		},
+		{
+			name: "invalid options",
+			args: args{
+				validation: platform.TaskValidation{
+					Flux: `from(bucket: "b1") |> range(start: -1h)`,
+				},
+				auth: &platform.Authorization{Status: platform.Active},
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.args.validation)
+			if err != nil {
+				t.Fatalf("failed to marshal validation: %v", err)
+			}
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/tasks/validate", bytes.NewReader(b))
+			r = r.WithContext(pcontext.SetAuthorizer(context.TODO(), tt.args.auth))
+
+			w := httptest.NewRecorder()
+
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.BucketService = bucketService
+			h := NewTaskHandler(taskBackend)
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			content := res.Header.Get("Content-Type")
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handlePostTaskValidate() = %v, want %v: %s", tt.name, res.StatusCode, tt.wants.statusCode, body)
+			}
+			if tt.wants.contentType != "" && content != tt.wants.contentType {
+				t.Errorf("%q. handlePostTaskValidate() = %v, want %v", tt.name, content, tt.wants.contentType)
+			}
+			if eq, diff, _ := jsonEqual(string(body), tt.wants.body); tt.wants.body != "" && !eq {
+				t.Errorf("%q. handlePostTaskValidate() = ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_ValidateRoute(t *testing.T) {
+	h := NewTaskHandler(NewMockTaskBackend(t))
+
+	tests := []struct {
+		method, path string
+		statusCode   int
+	}{
+		// The validate path only accepts POST requests.
+		{method: "GET", path: "/api/v2/tasks/validate", statusCode: http.StatusMethodNotAllowed},
+		// The path of a task isn't mistaken for the validate path.
+		{method: "POST", path: "/api/v2/tasks/020f755c3c082000", statusCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "http://any.url"+tt.path, nil)
+		r = r.WithContext(pcontext.SetAuthorizer(context.TODO(), &platform.Authorization{Status: platform.Active}))
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, r)
+		if res := w.Result(); res.StatusCode != tt.statusCode {
+			t.Errorf("%s %s = %v, want %v", tt.method, tt.path, res.StatusCode, tt.statusCode)
+		}
+	}
+}
+
+func TestTaskHandler_handleGetTaskRevisionDiff(t *testing.T) {
+	scripts := map[int64]string{
+		1: "option task = {name: \"t\", every: 1h}\nfrom(bucket: \"b\") |> range(start: -1h)",
+		2: "option task = {name: \"t\", every: 1h}\nfrom(bucket: \"b\") |> range(start: -2h)",
+		3: "option task = {name: \"t\", every: 1h}\nfrom(bucket: \"c\") |> range(start: -2h)",
+	}
+	taskService := &mock.TaskService{
+		FindTaskRevisionFn: func(ctx context.Context, taskID platform.ID, revision int64) (*platform.TaskRevision, error) {
+			flux, ok := scripts[revision]
+			if !ok {
+				return nil, backend.ErrRevisionNotFound
+			}
+			return &platform.TaskRevision{TaskID: taskID, Revision: revision, Flux: flux}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		rev        string
+		query      string
+		statusCode int
+		from       int64
+		lines      []string
+	}{
+		{
+			name:       "previous revision",
+			rev:        "2",
+			statusCode: http.StatusOK,
+			from:       1,
+			lines:      []string{" option task = {name: \"t\", every: 1h}", "-from(bucket: \"b\") |> range(start: -1h)", "+from(bucket: \"b\") |> range(start: -2h)"},
+		},
+		{
+			name:       "explicit revision",
+			rev:        "3",
+			query:      "?from=1",
+			statusCode: http.StatusOK,
+			from:       1,
+			lines:      []string{"-from(bucket: \"b\") |> range(start: -1h)", "+from(bucket: \"c\") |> range(start: -2h)"},
+		},
+		{
+			name:       "first revision",
+			rev:        "1",
+			statusCode: http.StatusOK,
+			from:       0,
+			lines:      []string{"+option task = {name: \"t\", every: 1h}", "+from(bucket: \"b\") |> range(start: -1h)"},
+		},
+		{
+			name:       "missing revision",
+			rev:        "4",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "invalid from",
+			rev:        "2",
+			query:      "?from=x",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/tasks/0000000000000001/revisions/"+tt.rev+"/diff"+tt.query, nil)
+			ctx := context.WithValue(context.Background(), httprouter.ParamsKey, httprouter.Params{
+				{Key: "id", Value: "0000000000000001"},
+				{Key: "rev", Value: tt.rev},
+			})
+			r = r.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.TaskService = taskService
+			h := NewTaskHandler(taskBackend)
+			h.handleGetTaskRevisionDiff(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.statusCode {
+				t.Fatalf("handleGetTaskRevisionDiff() = %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var d platform.TaskRevisionDiff
+			if err := json.Unmarshal(body, &d); err != nil {
+				t.Fatal(err)
+			}
+			if d.From != tt.from {
+				t.Fatalf("expected diff from revision %d, got %d", tt.from, d.From)
+			}
+			lines := strings.Split(d.Diff, "\n")
+			for _, l := range tt.lines {
+				found := false
+				for _, dl := range lines {
+					if dl == l {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected line %q in diff:\n%s", l, d.Diff)
+				}
+			}
+		})
+	}
+}
+
 func TestTaskHandler_handleGetRun(t *testing.T) {
 	type fields struct {
 		taskService platform.TaskService
@@ -1434,7 +1696,7 @@ func TestTaskHandler_Sessions(t *testing.T) {
 					t.Fatalf("expected run ID %v, got %v", runID, *f.Run)
 				}
 
-				line := platform.Log("a log line")
+				line := platform.Log{RunID: runID, Time: "2019-01-01T00:00:00Z", Level: platform.LogLevelInfo, Message: "a log line"}
 				return []*platform.Log{&line}, 1, nil
 			},
 
@@ -1470,6 +1732,10 @@ func TestTaskHandler_Sessions(t *testing.T) {
 			t.Logf("response body: %s", body)
 			t.Fatalf("expected status OK, got %v", res.StatusCode)
 		}
+		exp := `{"events":[{"runID":"` + runID.String() + `","time":"2019-01-01T00:00:00Z","level":"info","message":"a log line"}]}`
+		if eq, diff, err := jsonEqual(string(body), exp); err != nil || !eq {
+			t.Fatalf("unexpected response body -want/+got: %s, err: %v", diff, err)
+		}
 
 		// The context passed to TaskService.FindLogs must be a valid authorization (not a session).
 		authr, err := pcontext.GetAuthorizer(findLogsCtx)
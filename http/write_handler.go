@@ -1,12 +1,16 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
@@ -25,7 +29,15 @@ type WriteBackend struct {
 
 	PointsWriter        storage.PointsWriter
 	BucketService       platform.BucketService
+	BucketSchemaService platform.BucketSchemaService
 	OrganizationService platform.OrganizationService
+
+	// MaxBodySize is the maximum size in bytes of a decompressed write body.
+	// A value of zero means there is no limit.
+	MaxBodySize int64
+	// MaxBatchSize is the maximum number of lines parsed and written to the
+	// PointsWriter at a time.
+	MaxBatchSize int
 }
 
 // NewWriteBackend returns a new instance of WriteBackend.
@@ -35,7 +47,11 @@ func NewWriteBackend(b *APIBackend) *WriteBackend {
 
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
+		BucketSchemaService: b.BucketSchemaService,
 		OrganizationService: b.OrganizationService,
+
+		MaxBodySize:  b.WriteMaxBodySize,
+		MaxBatchSize: b.WriteMaxBatchSize,
 	}
 }
 
@@ -46,18 +62,24 @@ type WriteHandler struct {
 	Logger *zap.Logger
 
 	BucketService       platform.BucketService
+	BucketSchemaService platform.BucketSchemaService
 	OrganizationService platform.OrganizationService
 
 	PointsWriter storage.PointsWriter
+
+	MaxBodySize  int64
+	MaxBatchSize int
 }
 
 const (
 	writePath            = "/api/v2/write"
+	importPath           = "/api/v2/import"
 	errInvalidGzipHeader = "gzipped HTTP body contains an invalid header"
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
 )
 
-// NewWriteHandler creates a new handler at /api/v2/write to receive line protocol.
+// NewWriteHandler creates a new handler at /api/v2/write to receive line protocol,
+// and at /api/v2/import to receive the data of exports.
 func NewWriteHandler(b *WriteBackend) *WriteHandler {
 	h := &WriteHandler{
 		Router: NewRouter(),
@@ -65,25 +87,65 @@ func NewWriteHandler(b *WriteBackend) *WriteHandler {
 
 		PointsWriter:        b.PointsWriter,
 		BucketService:       b.BucketService,
+		BucketSchemaService: b.BucketSchemaService,
 		OrganizationService: b.OrganizationService,
+
+		MaxBodySize:  b.MaxBodySize,
+		MaxBatchSize: b.MaxBatchSize,
 	}
 
 	h.HandlerFunc("POST", writePath, h.handleWrite)
+	h.HandlerFunc("POST", importPath, h.handleImport)
 	return h
 }
 
+// handleWrite is the HTTP handler for the POST /api/v2/write route.
 func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	h.write(w, r, "http/handleWrite", nil)
+}
+
+// handleImport is the HTTP handler for the POST /api/v2/import route. It
+// writes line protocol or the Flux annotated CSV of an export, which is
+// converted to line protocol and parsed in the same way as a write.
+func (h *WriteHandler) handleImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format := platform.ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = platform.ExportFormatLineProtocol
+	}
+	if err := format.Valid(); err != nil {
+		r.Body.Close()
+		EncodeError(ctx, &platform.Error{
+			Op:  "http/handleImport",
+			Err: err,
+		}, w)
+		return
+	}
+
+	var convert func(io.Reader) io.Reader
+	if format == platform.ExportFormatCSV {
+		convert = storage.NewAnnotatedCSVReader
+	}
+	h.write(w, r, "http/handleImport", convert)
+}
+
+// write parses the line protocol in the body of r and writes it to the bucket
+// of the request. If convert is not nil, the body is converted to line protocol
+// with it first.
+func (h *WriteHandler) write(w http.ResponseWriter, r *http.Request, op string, convert func(io.Reader) io.Reader) {
 	ctx := r.Context()
 	defer r.Body.Close()
 
 	in := r.Body
-	if r.Header.Get("Content-Encoding") == "gzip" {
+	gzipped := r.Header.Get("Content-Encoding") == "gzip"
+	if gzipped {
 		var err error
 		in, err = gzip.NewReader(r.Body)
 		if err != nil {
 			EncodeError(ctx, &platform.Error{
 				Code: platform.EInvalid,
-				Op:   "http/handleWrite",
+				Op:   op,
 				Msg:  errInvalidGzipHeader,
 				Err:  err,
 			}, w)
@@ -92,6 +154,13 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		defer in.Close()
 	}
 
+	// The max body size is the size of the decompressed body, so the length of
+	// a gzipped body can't be compared with it.
+	if h.MaxBodySize > 0 && !gzipped && r.ContentLength > h.MaxBodySize {
+		encodeLineProtocolLengthError(ctx, h.MaxBodySize, w)
+		return
+	}
+
 	a, err := pcontext.GetAuthorizer(ctx)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -106,64 +175,27 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
 
-	var org *platform.Organization
-	if id, err := platform.IDFromString(req.Org); err == nil {
-		// Decoded ID successfully. Make sure it's a real org.
-		o, err := h.OrganizationService.FindOrganizationByID(ctx, *id)
-		if err == nil {
-			org = o
-		} else if platform.ErrorCode(err) != platform.ENotFound {
-			EncodeError(ctx, err, w)
-			return
-		}
-	}
-	if org == nil {
-		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &req.Org})
-		if err != nil {
-			logger.Info("Failed to find organization", zap.Error(err))
-			EncodeError(ctx, err, w)
-			return
-		}
-
-		org = o
-	}
-
-	var bucket *platform.Bucket
-	if id, err := platform.IDFromString(req.Bucket); err == nil {
-		// Decoded ID successfully. Make sure it's a real bucket.
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
-			OrganizationID: &org.ID,
-			ID:             id,
-		})
-		if err == nil {
-			bucket = b
-		} else if platform.ErrorCode(err) != platform.ENotFound {
-			EncodeError(ctx, err, w)
-			return
-		}
+	org, err := findOrganization(ctx, h.OrganizationService, req.Org)
+	if err != nil {
+		logger.Info("Failed to find organization", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
 	}
 
-	if bucket == nil {
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
-			OrganizationID: &org.ID,
-			Name:           &req.Bucket,
-		})
-		if err != nil {
-			EncodeError(ctx, &platform.Error{
-				Op:  "http/handleWrite",
-				Err: err,
-			}, w)
-			return
-		}
-
-		bucket = b
+	bucket, err := findBucket(ctx, h.BucketService, org.ID, req.Bucket)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{
+			Op:  op,
+			Err: err,
+		}, w)
+		return
 	}
 
 	p, err := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResourceType, org.ID)
 	if err != nil {
 		EncodeError(ctx, &platform.Error{
 			Code: platform.EInternal,
-			Op:   "http/handleWrite",
+			Op:   op,
 			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
 			Err:  err,
 		}, w)
@@ -173,7 +205,7 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	if !a.Allowed(*p) {
 		EncodeError(ctx, &platform.Error{
 			Code: platform.EForbidden,
-			Op:   "http/handleWrite",
+			Op:   op,
 			Msg:  "insufficient permissions for write",
 		}, w)
 		return
@@ -182,56 +214,292 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 	// TODO(jeff): we should be publishing with the org and bucket instead of
 	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
 	// be sure to remove this when it is there!
-	data, err := ioutil.ReadAll(in)
-	if err != nil {
-		logger.Error("Error reading body", zap.Error(err))
-		EncodeError(ctx, &platform.Error{
-			Code: platform.EInternal,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to read data: %v", err),
-			Err:  err,
-		}, w)
-		return
+	var body io.Reader = in
+
+	// The max body size is enforced on the decompressed body as it is streamed,
+	// so that no more than a batch of it is held in memory. As with lines which
+	// cannot be parsed, the batches read before a body exceeding it is rejected
+	// have already been written.
+	if h.MaxBodySize > 0 {
+		body = newLimitedReader(in, h.MaxBodySize)
+	}
+	if convert != nil {
+		body = convert(body)
 	}
 
-	points, err := models.ParsePointsWithPrecision(data, time.Now(), req.Precision)
-	if err != nil {
-		logger.Error("Error parsing points", zap.Error(err))
-		EncodeError(ctx, &platform.Error{
-			Code: platform.EInvalid,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to parse points: %v", err),
-			Err:  err,
-		}, w)
-		return
+	// The body is parsed and written in batches so that large writes do not
+	// need to be held in memory. Lines which cannot be parsed are rejected, but
+	// do not prevent the remaining points from being written.
+	var (
+		pr      = models.NewPointsReader(body, time.Now(), req.Precision, h.MaxBatchSize)
+		perrs   models.ParseErrors
+		written int
+	)
+
+	// Points which do not match the schema of a bucket with an explicit schema
+	// are rejected in the same way as lines which cannot be parsed. They are
+	// validated before they are written, so that the reason each line was
+	// rejected for is reported; the PointsWriter enforces the schema of writes
+	// which do not go through the handler.
+	if bucket.SchemaType == platform.SchemaTypeExplicit && h.BucketSchemaService != nil {
+		schemas, err := h.BucketSchemaService.FindMeasurementSchemas(ctx, bucket.ID)
+		if err != nil {
+			logger.Error("Error finding bucket schema", zap.Error(err))
+			EncodeError(ctx, &platform.Error{
+				Code: platform.EInternal,
+				Op:   op,
+				Msg:  fmt.Sprintf("unable to find bucket schema: %v", err),
+				Err:  err,
+			}, w)
+			return
+		}
+		pr.SetValidateFunc(storage.NewSchemaValidator(schemas).ValidatePoint)
 	}
 
-	exploded, err := tsdb.ExplodePoints(org.ID, bucket.ID, points)
-	if err != nil {
-		logger.Error("Error exploding points", zap.Error(err))
-		EncodeError(ctx, &platform.Error{
-			Code: platform.EInternal,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to convert points to internal structures: %v", err),
-			Err:  err,
-		}, w)
-		return
+	for {
+		points, err := pr.Next()
+		if err == io.EOF {
+			break
+		} else if pe, ok := err.(models.ParseErrors); ok {
+			perrs = append(perrs, pe...)
+		} else if err == errBodyTooLarge {
+			logger.Info("Write body too large", zap.Int64("max_body_size", h.MaxBodySize), zap.Int("written", written))
+			encodeLineProtocolLengthError(ctx, h.MaxBodySize, w)
+			return
+		} else if perr, ok := err.(*platform.Error); ok {
+			// The body could not be converted to line protocol.
+			EncodeError(ctx, &platform.Error{
+				Op:  op,
+				Err: perr,
+			}, w)
+			return
+		} else if err != nil {
+			logger.Error("Error reading body", zap.Error(err))
+			EncodeError(ctx, &platform.Error{
+				Code: platform.EInternal,
+				Op:   op,
+				Msg:  fmt.Sprintf("unable to read data: %v", err),
+				Err:  err,
+			}, w)
+			return
+		}
+
+		if len(points) == 0 {
+			continue
+		}
+
+		exploded, err := tsdb.ExplodePoints(org.ID, bucket.ID, points)
+		if err != nil {
+			logger.Error("Error exploding points", zap.Error(err))
+			EncodeError(ctx, &platform.Error{
+				Code: platform.EInternal,
+				Op:   op,
+				Msg:  fmt.Sprintf("unable to convert points to internal structures: %v", err),
+				Err:  err,
+			}, w)
+			return
+		}
+
+		n := len(points)
+		if err := h.PointsWriter.WritePoints(ctx, exploded); err != nil {
+			// Points dropped by the engine, such as those exceeding its series
+			// cardinality limits, are rejected in the same way as malformed lines.
+			pwe, ok := err.(tsdb.PartialWriteError)
+			if !ok {
+				logger.Error("Error writing points", zap.Error(err))
+				EncodeError(ctx, &platform.Error{
+					Code: platform.EInternal,
+					Op:   op,
+					Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+					Err:  err,
+				}, w)
+				return
+			}
+
+			dropped := droppedPoints(org.ID, bucket.ID, points, pwe)
+			for _, i := range dropped {
+				perrs = append(perrs, &models.ParseError{
+					Line: pr.Line(i),
+					Text: points[i].String(),
+					Err:  errors.New(pwe.Reason),
+				})
+			}
+			n -= len(dropped)
+		}
+		written += n
 	}
 
-	if err := h.PointsWriter.WritePoints(ctx, exploded); err != nil {
-		logger.Error("Error writing points", zap.Error(err))
-		EncodeError(ctx, &platform.Error{
-			Code: platform.EInternal,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
-			Err:  err,
-		}, w)
+	if len(perrs) > 0 {
+		sort.SliceStable(perrs, func(i, j int) bool { return perrs[i].Line < perrs[j].Line })
+		logger.Info("Rejected lines", zap.Int("rejected", len(perrs)), zap.Int("accepted", written))
+		encodeLineProtocolError(ctx, newLineProtocolError(op, perrs, written > 0), w)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// droppedPoints returns the indexes of the points with a series dropped by a write
+// which returned pwe. A point is dropped if the series of any of its fields was.
+func droppedPoints(orgID, bucketID platform.ID, points []models.Point, pwe tsdb.PartialWriteError) []int {
+	keys := make(map[string]struct{}, len(pwe.DroppedKeys))
+	for _, k := range pwe.DroppedKeys {
+		keys[string(k)] = struct{}{}
+	}
+
+	var dropped []int
+	for i, p := range points {
+		exploded, err := tsdb.ExplodePoints(orgID, bucketID, []models.Point{p})
+		if err != nil {
+			continue
+		}
+		for _, ep := range exploded {
+			if _, ok := keys[string(ep.Key())]; ok {
+				dropped = append(dropped, i)
+				break
+			}
+		}
+	}
+	return dropped
+}
+
+// errBodyTooLarge is returned by a limitedReader once more than its limit has
+// been read.
+var errBodyTooLarge = errors.New("request body too large")
+
+// limitedReader reads from r until more than n bytes have been read, after which
+// it returns errBodyTooLarge. Unlike io.LimitReader, reaching the limit is
+// distinguishable from the end of the input.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func newLimitedReader(r io.Reader, n int64) *limitedReader {
+	return &limitedReader{r: r, n: n}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errBodyTooLarge
+	}
+	// Read one byte more than the limit to detect bodies exceeding it.
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, errBodyTooLarge
+	}
+	return n, err
+}
+
+// encodeLineProtocolLengthError writes a 413 response reporting the maximum
+// body size accepted by the handler.
+func encodeLineProtocolLengthError(ctx context.Context, maxLength int64, w http.ResponseWriter) {
+	w.Header().Set(PlatformErrorCodeHeader, platform.EInvalid)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	b, _ := json.Marshal(struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		MaxLength int64  `json:"maxLength"`
+	}{
+		Code:      platform.EInvalid,
+		Message:   fmt.Sprintf("request body exceeds the maximum size of %d bytes", maxLength),
+		MaxLength: maxLength,
+	})
+	_, _ = w.Write(b)
+}
+
+// newLineProtocolError converts the errors from parsing line protocol into the
+// error returned to the client.
+func newLineProtocolError(op string, perrs models.ParseErrors, partial bool) *platform.Error {
+	errs := make([]platform.LineError, 0, len(perrs))
+	for _, pe := range perrs {
+		errs = append(errs, platform.LineError{
+			Line:   pe.Line,
+			Text:   pe.Text,
+			Reason: pe.Err.Error(),
+		})
+	}
+	return platform.NewLineProtocolError(op, errs, partial)
+}
+
+// lineProtocolErrorResponse is the body of the response to a write which
+// rejected lines of line protocol.
+type lineProtocolErrorResponse struct {
+	Code   string               `json:"code"`
+	Msg    string               `json:"message"`
+	Op     string               `json:"op"`
+	Err    string               `json:"err"`
+	Line   int                  `json:"line"`
+	Errors []platform.LineError `json:"errors,omitempty"`
+}
+
+// encodeLineProtocolError writes err, returned by platform.NewLineProtocolError,
+// to the response. The status code distinguishes a write where every line was
+// rejected (400) from a partial write (422).
+func encodeLineProtocolError(ctx context.Context, err *platform.Error, w http.ResponseWriter) {
+	res := lineProtocolErrorResponse{
+		Code: err.Code,
+		Msg:  err.Msg,
+		Op:   err.Op,
+	}
+	if lpErr := platform.ErrorLineProtocol(err); lpErr != nil {
+		res.Err = lpErr.Error()
+		res.Line = lpErr.Line
+		res.Errors = lpErr.Errors
+	}
+
+	httpCode, ok := statusCodePlatformError[err.Code]
+	if !ok {
+		httpCode = http.StatusBadRequest
+	}
+	w.Header().Set(PlatformErrorCodeHeader, err.Code)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(httpCode)
+	b, _ := json.Marshal(res)
+	_, _ = w.Write(b)
+}
+
+// findOrganization finds an organization by either its ID or its name.
+func findOrganization(ctx context.Context, svc platform.OrganizationService, org string) (*platform.Organization, error) {
+	if id, err := platform.IDFromString(org); err == nil {
+		// Decoded ID successfully. Make sure it's a real org.
+		o, err := svc.FindOrganizationByID(ctx, *id)
+		if err == nil {
+			return o, nil
+		} else if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+	}
+
+	return svc.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+}
+
+// findBucket finds a bucket within an organization by either its ID or its name.
+func findBucket(ctx context.Context, svc platform.BucketService, orgID platform.ID, bucket string) (*platform.Bucket, error) {
+	if id, err := platform.IDFromString(bucket); err == nil {
+		// Decoded ID successfully. Make sure it's a real bucket.
+		b, err := svc.FindBucket(ctx, platform.BucketFilter{
+			OrganizationID: &orgID,
+			ID:             id,
+		})
+		if err == nil {
+			return b, nil
+		} else if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+	}
+
+	return svc.FindBucket(ctx, platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &bucket,
+	})
+}
+
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
 	qp := r.URL.Query()
 	p := qp.Get("precision")
@@ -266,6 +534,10 @@ type WriteService struct {
 	Token              string
 	Precision          string
 	InsecureSkipVerify bool
+
+	// Format is the format of the data written. The data of an export can be
+	// imported in either format; the default is line protocol.
+	Format platform.ExportFormat
 }
 
 var _ platform.WriteService = (*WriteService)(nil)
@@ -284,7 +556,15 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 		}
 	}
 
-	u, err := newURL(s.Addr, writePath)
+	path, contentType := writePath, "text/plain; charset=utf-8"
+	if s.Format != "" && s.Format != platform.ExportFormatLineProtocol {
+		if err := s.Format.Valid(); err != nil {
+			return err
+		}
+		path, contentType = importPath, exportContentType(s.Format)
+	}
+
+	u, err := newURL(s.Addr, path)
 	if err != nil {
 		return err
 	}
@@ -299,7 +579,7 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 		return err
 	}
 
-	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Content-Encoding", "gzip")
 	SetToken(s.Token, req)
 
@@ -317,6 +597,9 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 	params.Set("org", string(org))
 	params.Set("bucket", string(bucket))
 	params.Set("precision", string(precision))
+	if path == importPath {
+		params.Set("format", string(s.Format))
+	}
 	req.URL.RawQuery = params.Encode()
 
 	hc := newClient(u.Scheme, s.InsecureSkipVerify)
@@ -327,6 +610,35 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 	}
 	defer resp.Body.Close()
 
+	return checkWriteError(resp)
+}
+
+// checkWriteError returns an error wrapping a *platform.LineProtocolError if lines
+// of the write were rejected, otherwise it behaves like CheckError.
+func checkWriteError(resp *http.Response) error {
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnprocessableEntity {
+		return CheckError(resp)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var res lineProtocolErrorResponse
+	if err := json.Unmarshal(body, &res); err == nil && len(res.Errors) > 0 {
+		return &platform.Error{
+			Code: res.Code,
+			Msg:  res.Msg,
+			Op:   res.Op,
+			Err: &platform.LineProtocolError{
+				Line:   res.Line,
+				Errors: res.Errors,
+			},
+		}
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
 	return CheckError(resp)
 }
 
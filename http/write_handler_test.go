@@ -1,16 +1,24 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
 )
 
 func TestWriteService_Write(t *testing.T) {
@@ -69,3 +77,236 @@ func TestWriteService_Write(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteHandler_handleWrite(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	// seriesKey returns the key of the series a line with a single field is
+	// written to by the engine.
+	seriesKey := func(line string) []byte {
+		points, err := models.ParsePointsString(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if points, err = tsdb.ExplodePoints(orgID, bucketID, points); err != nil {
+			t.Fatal(err)
+		}
+		return points[0].Key()
+	}
+
+	tests := []struct {
+		name          string
+		body          string
+		maxBodySize   int64
+		maxBatchSize  int
+		unknownLength bool
+		gzipped       bool
+		schemas       []*platform.MeasurementSchema
+		noSchemaSvc   bool
+		writeErr      error
+		wantStatus    int
+		wantPoints    int
+		wantLines     []int
+	}{
+		{
+			name:       "all points are written",
+			body:       "m,t1=v1 f1=1\nm,t1=v2 f1=2",
+			wantStatus: http.StatusNoContent,
+			wantPoints: 2,
+		},
+		{
+			name:       "valid points are written when some lines are malformed",
+			body:       "m,t1=v1 f1=1\nm,t1=v2\nm,t1=v3 f1=3\nm f1=",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantPoints: 2,
+			wantLines:  []int{2, 4},
+		},
+		{
+			name:       "nothing is written when every line is malformed",
+			body:       "m,t1=v1\nm f1=",
+			wantStatus: http.StatusBadRequest,
+			wantLines:  []int{1, 2},
+		},
+		{
+			name:         "rejected lines are numbered across batches",
+			body:         "m,t1=v1 f1=1\nm,t1=v2\nm,t1=v3 f1=3\nm f1=\nm,t1=v5 f1=5",
+			maxBatchSize: 1,
+			wantStatus:   http.StatusUnprocessableEntity,
+			wantPoints:   3,
+			wantLines:    []int{2, 4},
+		},
+		{
+			name:        "content length exceeds the max body size",
+			body:        "m,t1=v1 f1=1\nm,t1=v2 f1=2",
+			maxBodySize: 16,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "streamed body exceeds the max body size",
+			body:          "m,t1=v1 f1=1\nm,t1=v2 f1=2",
+			maxBodySize:   16,
+			unknownLength: true,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "batches read before a streamed body exceeds the max body size are written",
+			body:          "m,t1=v1 f1=1\nm,t1=v2 f1=2\nm,t1=v3 f1=3",
+			maxBodySize:   26,
+			maxBatchSize:  1,
+			unknownLength: true,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+			wantPoints:    2,
+		},
+		{
+			name:        "gzipped body exceeds the max body size once decompressed",
+			body:        "m,t1=v1 f1=1\nm,t1=v2 f1=2",
+			maxBodySize: 16,
+			gzipped:     true,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "gzipped body within the max body size once decompressed",
+			body:        "m,t1=v1 f1=1\nm,t1=v2 f1=2",
+			maxBodySize: 25,
+			gzipped:     true,
+			wantStatus:  http.StatusNoContent,
+			wantPoints:  2,
+		},
+		{
+			name:        "body within the max body size",
+			body:        "m,t1=v1 f1=1\nm,t1=v2 f1=2",
+			maxBodySize: 25,
+			wantStatus:  http.StatusNoContent,
+			wantPoints:  2,
+		},
+		{
+			name: "points which do not match an explicit schema are rejected",
+			body: "cpu,host=a usage=1\ncpu usage=1\ncpu,host=a usage=1i\nmem,host=a used=1\ncpu,host=a usage=1,idle=2",
+			schemas: []*platform.MeasurementSchema{
+				{
+					Name: "cpu",
+					Tags: []string{"host"},
+					Fields: []platform.MeasurementSchemaField{
+						{Name: "usage", Type: platform.SchemaFieldTypeFloat},
+					},
+				},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantPoints: 1,
+			wantLines:  []int{2, 3, 4, 5},
+		},
+		{
+			name: "points are left to the points writer to validate without a bucket schema service",
+			body: "cpu,host=a usage=1\nmem,host=a used=1",
+			schemas: []*platform.MeasurementSchema{
+				{Name: "cpu", Fields: []platform.MeasurementSchemaField{{Name: "usage", Type: platform.SchemaFieldTypeFloat}}},
+			},
+			noSchemaSvc: true,
+			wantStatus:  http.StatusNoContent,
+			wantPoints:  2,
+		},
+		{
+			name:         "points dropped by the engine are rejected",
+			body:         "m,t1=v1 f1=1\nm,t1=v2 f1=2\nm,t1=v3 f1=3",
+			maxBatchSize: 2,
+			writeErr: tsdb.PartialWriteError{
+				Reason:      "max-series-per-bucket limit exceeded: (1)",
+				Dropped:     1,
+				DroppedKeys: [][]byte{seriesKey("m,t1=v2 f1=2")},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantPoints: 3,
+			wantLines:  []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationByIDF = func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+				return &platform.Organization{ID: id}, nil
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				b := &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID}
+				if tt.schemas != nil {
+					b.SchemaType = platform.SchemaTypeExplicit
+				}
+				return b, nil
+			}
+			schemas := mock.NewBucketSchemaService()
+			schemas.FindMeasurementSchemasFn = func(ctx context.Context, bucketID platform.ID) ([]*platform.MeasurementSchema, error) {
+				return tt.schemas, nil
+			}
+			pw := &mock.PointsWriter{}
+			pw.ForceError(tt.writeErr)
+
+			b := &WriteBackend{
+				Logger:              zap.NewNop(),
+				PointsWriter:        pw,
+				BucketService:       buckets,
+				BucketSchemaService: schemas,
+				OrganizationService: orgs,
+				MaxBodySize:         tt.maxBodySize,
+				MaxBatchSize:        tt.maxBatchSize,
+			}
+			if tt.noSchemaSvc {
+				b.BucketSchemaService = nil
+			}
+			h := NewWriteHandler(b)
+
+			p, _ := platform.NewPermissionAtID(bucketID, platform.WriteAction, platform.BucketsResourceType, orgID)
+			auth := &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{*p}}
+
+			var body io.Reader = strings.NewReader(tt.body)
+			if tt.gzipped {
+				var buf bytes.Buffer
+				gw := gzip.NewWriter(&buf)
+				if _, err := gw.Write([]byte(tt.body)); err != nil {
+					t.Fatal(err)
+				}
+				if err := gw.Close(); err != nil {
+					t.Fatal(err)
+				}
+				body = &buf
+			}
+			r := httptest.NewRequest("POST", "/api/v2/write?org="+orgID.String()+"&bucket="+bucketID.String(), body)
+			if tt.gzipped {
+				r.Header.Set("Content-Encoding", "gzip")
+			}
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), auth))
+			if tt.unknownLength {
+				r.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+
+			h.handleWrite(w, r)
+
+			if got, want := w.Code, tt.wantStatus; got != want {
+				t.Fatalf("handleWrite() status = %d, want %d: %s", got, want, w.Body.String())
+			}
+
+			// Each point has a single field so is exploded into a single point.
+			// Points dropped by the engine are still received by the writer.
+			if got, want := len(pw.Points), tt.wantPoints; got != want {
+				t.Errorf("handleWrite() wrote %d points, want %d", got, want)
+			}
+
+			if len(tt.wantLines) == 0 {
+				return
+			}
+
+			var lpErr lineProtocolErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &lpErr); err != nil {
+				t.Fatalf("failed to decode line protocol error: %v", err)
+			}
+
+			var lines []int
+			for _, le := range lpErr.Errors {
+				lines = append(lines, le.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("handleWrite() rejected lines = %v, want %v", lines, tt.wantLines)
+			}
+		})
+	}
+}
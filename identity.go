@@ -0,0 +1,65 @@
+package influxdb
+
+import (
+	"context"
+	"strings"
+)
+
+// ErrUserIdentityNotFound is the error message for a missing user identity.
+const ErrUserIdentityNotFound = "user identity not found"
+
+// ops for user identities.
+const (
+	OpFindUserIdentity = "FindUserIdentity"
+	OpPutUserIdentity  = "PutUserIdentity"
+)
+
+// UserIdentity links a user to their identity at an external identity provider,
+// such as an OAuth2 provider or an LDAP directory.
+type UserIdentity struct {
+	// Provider is the name of the identity provider, and Subject the identifier of the identity at the provider.
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	UserID   ID     `json:"userID"`
+}
+
+// UserIdentityService stores the links between users and their external identities.
+type UserIdentityService interface {
+	// FindUserIdentity returns the identity with the given subject at provider.
+	FindUserIdentity(ctx context.Context, provider, subject string) (*UserIdentity, error)
+
+	// PutUserIdentity links a user to an identity, replacing any previous link of the identity.
+	PutUserIdentity(ctx context.Context, i *UserIdentity) error
+}
+
+// GroupMapping grants the members of a group of an identity provider a role in an organization.
+type GroupMapping struct {
+	Group    string
+	Org      string
+	UserType UserType
+}
+
+// ParseGroupMapping parses a group mapping of the form "group=org:owner" or "group=org:member".
+func ParseGroupMapping(s string) (GroupMapping, error) {
+	invalid := &Error{
+		Code: EInvalid,
+		Msg:  "group mapping " + s + " must be of the form group=org:owner or group=org:member",
+	}
+
+	// Group names may contain '=' and organization names ':', but roles do not.
+	eq := strings.LastIndex(s, "=")
+	colon := strings.LastIndex(s, ":")
+	if eq < 1 || colon < eq+2 {
+		return GroupMapping{}, invalid
+	}
+
+	m := GroupMapping{
+		Group:    s[:eq],
+		Org:      s[eq+1 : colon],
+		UserType: UserType(s[colon+1:]),
+	}
+	if err := m.UserType.Valid(); err != nil {
+		return GroupMapping{}, invalid
+	}
+	return m, nil
+}
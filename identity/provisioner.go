@@ -0,0 +1,176 @@
+// Package identity provisions the users who sign in through external identity providers,
+// such as OAuth2 providers and LDAP directories.
+package identity
+
+import (
+	"context"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// Provisioner finds or creates the users of external identities,
+// and grants them the memberships of the groups they belong to at their provider.
+type Provisioner struct {
+	Logger *zap.Logger
+
+	UserService                influxdb.UserService
+	UserIdentityService        influxdb.UserIdentityService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+
+	// GroupMappings are the roles granted to the members of groups.
+	// The memberships of users in the organizations of the mappings are managed by the provisioner:
+	// they are created, changed and removed to match the groups of the users each time they sign in.
+	GroupMappings []influxdb.GroupMapping
+
+	// AutoCreate is whether a user is created for an identity signing in for the first time.
+	// If not set, only identities already linked to a user can sign in.
+	AutoCreate bool
+}
+
+// NewProvisioner returns a Provisioner which creates users for new identities.
+func NewProvisioner(us influxdb.UserService, is influxdb.UserIdentityService, os influxdb.OrganizationService, urms influxdb.UserResourceMappingService) *Provisioner {
+	return &Provisioner{
+		Logger:                     zap.NewNop(),
+		UserService:                us,
+		UserIdentityService:        is,
+		OrganizationService:        os,
+		UserResourceMappingService: urms,
+		AutoCreate:                 true,
+	}
+}
+
+// Provision returns the user linked to the identity with the given subject at provider,
+// after syncing the user's memberships with groups.
+// If the identity is not linked to a user, a user named after the subject is created and linked to it,
+// unless AutoCreate is not set or the name is already taken by another user.
+func (p *Provisioner) Provision(ctx context.Context, provider, subject string, groups []string) (*influxdb.User, error) {
+	u, err := p.findUser(ctx, provider, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		if !p.AutoCreate {
+			return nil, &influxdb.Error{
+				Code: influxdb.EUnauthorized,
+				Msg:  "no user is linked to " + subject + " at " + provider,
+			}
+		}
+
+		u = &influxdb.User{Name: subject}
+		// Creating the user fails if a user with the same name exists, so that signing in
+		// through a provider never grants the access of a user the identity isn't linked to.
+		if err := p.UserService.CreateUser(ctx, u); err != nil {
+			return nil, err
+		}
+
+		if err := p.UserIdentityService.PutUserIdentity(ctx, &influxdb.UserIdentity{
+			Provider: provider,
+			Subject:  subject,
+			UserID:   u.ID,
+		}); err != nil {
+			return nil, err
+		}
+		p.Logger.Info("Created user for identity", zap.String("provider", provider), zap.String("subject", subject), zap.String("userID", u.ID.String()))
+	}
+
+	if err := p.SyncMemberships(ctx, u.ID, groups); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// findUser returns the user linked to an identity, or nil if the identity is not linked to an existing user.
+func (p *Provisioner) findUser(ctx context.Context, provider, subject string) (*influxdb.User, error) {
+	i, err := p.UserIdentityService.FindUserIdentity(ctx, provider, subject)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := p.UserService.FindUserByID(ctx, i.UserID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		// The user was deleted since it was linked.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// SyncMemberships makes the memberships of a user in the organizations of the group mappings
+// match the groups the user belongs to. A user in several groups mapped to the same organization
+// is an owner if any of the mappings grants ownership.
+func (p *Provisioner) SyncMemberships(ctx context.Context, userID influxdb.ID, groups []string) error {
+	inGroup := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		inGroup[g] = true
+	}
+
+	want := map[string]influxdb.UserType{}
+	var orgs []string
+	for _, m := range p.GroupMappings {
+		if _, ok := want[m.Org]; !ok {
+			want[m.Org] = ""
+			orgs = append(orgs, m.Org)
+		}
+		if inGroup[m.Group] && want[m.Org] != influxdb.Owner {
+			want[m.Org] = m.UserType
+		}
+	}
+	sort.Strings(orgs)
+
+	for _, name := range orgs {
+		name := name
+		o, err := p.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &name})
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			p.Logger.Info("Organization of group mapping not found", zap.String("org", name))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := p.syncMembership(ctx, userID, o.ID, want[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncMembership makes the user a member of type ut of the organization, or removes it from the organization if ut is empty.
+func (p *Provisioner) syncMembership(ctx context.Context, userID, orgID influxdb.ID, ut influxdb.UserType) error {
+	ms, _, err := p.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   orgID,
+		UserID:       userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(ms) > 0 {
+		if ms[0].UserType == ut {
+			return nil
+		}
+		if err := p.UserResourceMappingService.DeleteUserResourceMapping(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	if ut == "" {
+		return nil
+	}
+	return p.UserResourceMappingService.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   orgID,
+		UserID:       userID,
+		UserType:     ut,
+	})
+}
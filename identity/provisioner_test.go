@@ -0,0 +1,173 @@
+package identity_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/identity"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+)
+
+func newTestProvisioner(t *testing.T) (*identity.Provisioner, *kv.Service) {
+	t.Helper()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, name := range []string{"acme", "initech"} {
+		if err := svc.PutOrganization(ctx, &influxdb.Organization{ID: influxdb.ID(i + 1), Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := identity.NewProvisioner(svc, svc, svc, svc)
+	p.GroupMappings = []influxdb.GroupMapping{
+		{Group: "admins", Org: "acme", UserType: influxdb.Owner},
+		{Group: "devs", Org: "acme", UserType: influxdb.Member},
+		{Group: "devs", Org: "initech", UserType: influxdb.Member},
+	}
+	return p, svc
+}
+
+// memberships returns the roles of a user in the organizations, by organization name.
+func memberships(t *testing.T, svc *kv.Service, userID influxdb.ID) map[string]influxdb.UserType {
+	t.Helper()
+
+	ctx := context.Background()
+	ms, _, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceType: influxdb.OrgsResourceType,
+		UserID:       userID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]influxdb.UserType{}
+	for _, m := range ms {
+		o, err := svc.FindOrganizationByID(ctx, m.ResourceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[o.Name] = m.UserType
+	}
+	return got
+}
+
+func TestProvisioner_Provision(t *testing.T) {
+	p, svc := newTestProvisioner(t)
+	ctx := context.Background()
+
+	u, err := p.Provision(ctx, "generic", "alice@example.com", []string{"devs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "alice@example.com" {
+		t.Errorf("expected user to be named after the subject, got %q", u.Name)
+	}
+
+	got := memberships(t, svc, u.ID)
+	if len(got) != 2 || got["acme"] != influxdb.Member || got["initech"] != influxdb.Member {
+		t.Errorf("unexpected memberships after first sign in: %v", got)
+	}
+
+	// Signing in again returns the same user, with memberships following the groups.
+	again, err := p.Provision(ctx, "generic", "alice@example.com", []string{"admins", "devs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != u.ID {
+		t.Errorf("expected the linked user %v, got %v", u.ID, again.ID)
+	}
+	got = memberships(t, svc, u.ID)
+	if len(got) != 2 || got["acme"] != influxdb.Owner || got["initech"] != influxdb.Member {
+		t.Errorf("unexpected memberships after joining admins: %v", got)
+	}
+
+	if _, err := p.Provision(ctx, "generic", "alice@example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got = memberships(t, svc, u.ID); len(got) != 0 {
+		t.Errorf("expected memberships to be removed after leaving all groups, got %v", got)
+	}
+}
+
+func TestProvisioner_Provision_UnmappedMembershipsAreKept(t *testing.T) {
+	p, svc := newTestProvisioner(t)
+	ctx := context.Background()
+
+	other := &influxdb.Organization{ID: influxdb.ID(3), Name: "other"}
+	if err := svc.PutOrganization(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := p.Provision(ctx, "generic", "alice@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   other.ID,
+		UserID:       u.ID,
+		UserType:     influxdb.Member,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.Provision(ctx, "generic", "alice@example.com", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := memberships(t, svc, u.ID); len(got) != 1 || got["other"] != influxdb.Member {
+		t.Errorf("expected the membership of an unmapped organization to be kept, got %v", got)
+	}
+}
+
+func TestProvisioner_Provision_ExistingUserName(t *testing.T) {
+	p, svc := newTestProvisioner(t)
+	ctx := context.Background()
+
+	if err := svc.CreateUser(ctx, &influxdb.User{Name: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := p.Provision(ctx, "github", "admin", []string{"admins"})
+	if influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected a conflict signing in as an existing unlinked user, got %v", err)
+	}
+}
+
+func TestProvisioner_Provision_NoAutoCreate(t *testing.T) {
+	p, _ := newTestProvisioner(t)
+	p.AutoCreate = false
+	ctx := context.Background()
+
+	_, err := p.Provision(ctx, "generic", "alice@example.com", nil)
+	if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Fatalf("expected an unlinked identity to be unauthorized, got %v", err)
+	}
+}
+
+func TestProvisioner_Provision_DeletedUser(t *testing.T) {
+	p, svc := newTestProvisioner(t)
+	ctx := context.Background()
+
+	u, err := p.Provision(ctx, "generic", "alice@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteUser(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	recreated, err := p.Provision(ctx, "generic", "alice@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recreated.ID == u.ID {
+		t.Error("expected a new user for the identity of a deleted user")
+	}
+}
@@ -0,0 +1,64 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestParseGroupMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    platform.GroupMapping
+		wantErr bool
+	}{
+		{
+			name: "owner",
+			s:    "admins=acme:owner",
+			want: platform.GroupMapping{Group: "admins", Org: "acme", UserType: platform.Owner},
+		},
+		{
+			name: "member of an organization whose name has a colon",
+			s:    "devs=acme:eu:member",
+			want: platform.GroupMapping{Group: "devs", Org: "acme:eu", UserType: platform.Member},
+		},
+		{
+			name: "group whose name has an equal sign",
+			s:    "cn=devs,ou=groups=acme:member",
+			want: platform.GroupMapping{Group: "cn=devs,ou=groups", Org: "acme", UserType: platform.Member},
+		},
+		{
+			name:    "unknown role",
+			s:       "admins=acme:admin",
+			wantErr: true,
+		},
+		{
+			name:    "missing organization",
+			s:       "admins=:owner",
+			wantErr: true,
+		},
+		{
+			name:    "missing group",
+			s:       "=acme:owner",
+			wantErr: true,
+		},
+		{
+			name:    "missing role",
+			s:       "admins=acme",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := platform.ParseGroupMapping(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGroupMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseGroupMapping() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package inmem
 
 import (
 	"context"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 )
@@ -52,28 +53,37 @@ func (s *Service) FindAuthorizationByID(ctx context.Context, id platform.ID) (*p
 
 // FindAuthorizationByToken returns an authorization given a token.
 func (s *Service) FindAuthorizationByToken(ctx context.Context, t string) (*platform.Authorization, error) {
-	var err error
 	op := OpPrefix + platform.OpFindAuthorizationByToken
-	var n int
-	var as []*platform.Authorization
-	as, n, err = s.FindAuthorizations(ctx, platform.AuthorizationFilter{Token: &t})
-	if err != nil {
-		return nil, &platform.Error{
-			Err: err,
-			Op:  op,
+	now := s.time()
+	var found *platform.Authorization
+	s.authorizationKV.Range(func(k, v interface{}) bool {
+		a, ok := v.(platform.Authorization)
+		if ok && a.AcceptsToken(t, now) {
+			found = &a
+			return false
 		}
-	}
-	if n < 1 {
+		return true
+	})
+	if found == nil {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
 			Msg:  "authorization not found",
 			Op:   op,
 		}
 	}
-	return as[0], nil
+	return found, nil
 }
 
 func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platform.Authorization) bool {
+	if filter.UnusedSince != nil {
+		t := *filter.UnusedSince
+		filter.UnusedSince = nil
+		fn := filterAuthorizationsFn(filter)
+		return func(a *platform.Authorization) bool {
+			return a.UnusedSince(t) && fn(a)
+		}
+	}
+
 	if filter.ID != nil {
 		return func(a *platform.Authorization) bool {
 			return a.ID == *filter.ID
@@ -175,6 +185,10 @@ func (s *Service) CreateAuthorization(ctx context.Context, a *platform.Authoriza
 
 	a.ID = s.IDGenerator.ID()
 	a.Status = platform.Active
+	a.CreatedAt = s.time()
+	a.LastUsedAt = nil
+	a.PreviousToken = ""
+	a.PreviousTokenExpiresAt = nil
 
 	return s.PutAuthorization(ctx, a)
 }
@@ -220,3 +234,44 @@ func (s *Service) SetAuthorizationStatus(ctx context.Context, id platform.ID, st
 	a.Status = status
 	return s.PutAuthorization(ctx, a)
 }
+
+// RotateAuthorization replaces the token of the authorization associated with id with a new one,
+// and returns the updated authorization. The current token is still accepted for gracePeriod.
+func (s *Service) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	op := OpPrefix + platform.OpRotateAuthorization
+	a, err := s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
+	token, err := s.TokenGenerator.Token()
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
+	a.Rotate(token, s.time(), gracePeriod)
+	if err := s.PutAuthorization(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetAuthorizationLastUsed records the time the authorization associated with id was last used at.
+func (s *Service) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) error {
+	a, err := s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+			Op:  OpPrefix + platform.OpSetAuthorizationLastUsed,
+		}
+	}
+
+	a.LastUsedAt = &t
+	return s.PutAuthorization(ctx, a)
+}
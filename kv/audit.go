@@ -0,0 +1,232 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb"
+)
+
+var (
+	auditBucket = []byte("auditlogv1")
+)
+
+var _ influxdb.AuditLogService = (*Service)(nil)
+
+func (s *Service) initializeAuditLog(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(auditBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// auditEventKey returns the key of an audit event, which orders events by the time they were recorded.
+func auditEventKey(t time.Time, id influxdb.ID) ([]byte, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(auditEventTimeKey(t), encodedID...), nil
+}
+
+// auditEventTimeKey returns the prefix of the keys of the audit events recorded at t.
+// Seeking to it moves a cursor to the first event recorded at or after t.
+func auditEventTimeKey(t time.Time) []byte {
+	k := make([]byte, 8, 8+influxdb.IDLength)
+	// This needs to be big-endian so that the iteration order is preserved when scanning keys
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	return k
+}
+
+// auditEventKeyTime returns the time of the audit event with key k.
+func auditEventKeyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k[:8])))
+}
+
+// CreateAuditEvent records an audit event, and sets its ID, and its time if not set.
+func (s *Service) CreateAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	err := s.kv.Update(func(tx Tx) error {
+		return s.createAuditEvent(ctx, tx, e)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateAuditEvent,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) createAuditEvent(ctx context.Context, tx Tx, e *influxdb.AuditEvent) error {
+	if err := e.Action.Valid(); err != nil {
+		return err
+	}
+
+	e.ID = s.IDGenerator.ID()
+	if e.Time.IsZero() {
+		e.Time = s.time()
+	}
+	e.Time = e.Time.UTC()
+
+	k, err := auditEventKey(e.Time, e.ID)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(e)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(auditBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(k, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindAuditEvents returns the audit events that match filter, in the order they were recorded,
+// or the reverse order if opt is descending.
+// Only the events recorded between the time bounds of the filter are scanned,
+// starting from the bound the events are returned from.
+// The count returned is the total number of matching events, regardless of the offset and limit of opt.
+func (s *Service) FindAuditEvents(ctx context.Context, filter influxdb.AuditFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	var es []*influxdb.AuditEvent
+	var n int
+	err := s.kv.View(func(tx Tx) error {
+		events, total, err := s.findAuditEvents(ctx, tx, filter, opt...)
+		if err != nil {
+			return err
+		}
+		es, n = events, total
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindAuditEvents,
+			Err: err,
+		}
+	}
+	return es, n, nil
+}
+
+func (s *Service) findAuditEvents(ctx context.Context, tx Tx, filter influxdb.AuditFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	var opts influxdb.FindOptions
+	if len(opt) > 0 {
+		opts = opt[0]
+	}
+
+	b, err := tx.Bucket(auditBucket)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	first, next := cur.First, cur.Next
+	if filter.Since != nil {
+		first = func() ([]byte, []byte) {
+			return cur.Seek(auditEventTimeKey(*filter.Since))
+		}
+	}
+	// beyond returns true once the cursor has passed the time bounds of the filter.
+	beyond := func(t time.Time) bool {
+		return filter.Until != nil && !t.Before(*filter.Until)
+	}
+	if opts.Descending {
+		first, next = cur.Last, cur.Prev
+		if filter.Until != nil {
+			// Start just before the first event recorded at or after until, if any.
+			first = func() ([]byte, []byte) {
+				if k, _ := cur.Seek(auditEventTimeKey(*filter.Until)); k != nil {
+					return cur.Prev()
+				}
+				return cur.Last()
+			}
+		}
+		beyond = func(t time.Time) bool {
+			return filter.Since != nil && t.Before(*filter.Since)
+		}
+	}
+
+	es := []*influxdb.AuditEvent{}
+	total := 0
+	for k, v := first(); k != nil; k, v = next() {
+		if beyond(auditEventKeyTime(k)) {
+			break
+		}
+
+		e := &influxdb.AuditEvent{}
+		if err := json.Unmarshal(v, e); err != nil {
+			return nil, 0, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if !filter.Matches(e) {
+			continue
+		}
+
+		total++
+		if total <= opts.Offset || (opts.Limit > 0 && len(es) >= opts.Limit) {
+			continue
+		}
+		es = append(es, e)
+	}
+
+	return es, total, nil
+}
+
+// DeleteAuditEventsBefore deletes the audit events recorded before t, and returns how many were deleted.
+// It is used to enforce the retention of the audit log.
+func (s *Service) DeleteAuditEventsBefore(ctx context.Context, t time.Time) (int, error) {
+	var n int
+	err := s.kv.Update(func(tx Tx) error {
+		b, err := tx.Bucket(auditBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		var keys [][]byte
+		for k, _ := cur.First(); k != nil && auditEventKeyTime(k).Before(t); k, _ = cur.Next() {
+			keys = append(keys, k)
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		n = len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, &influxdb.Error{
+			Err: err,
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,111 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltAuditLogService(t *testing.T) {
+	influxdbtesting.AuditLogService(initBoltAuditLogService, t)
+}
+
+func TestInmemAuditLogService(t *testing.T) {
+	influxdbtesting.AuditLogService(initInmemAuditLogService, t)
+}
+
+func initBoltAuditLogService(f influxdbtesting.AuditLogFields, t *testing.T) (influxdb.AuditLogService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initAuditLogService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemAuditLogService(f influxdbtesting.AuditLogFields, t *testing.T) (influxdb.AuditLogService, func()) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initAuditLogService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeStore()
+	}
+}
+
+func initAuditLogService(s kv.Store, f influxdbtesting.AuditLogFields, t *testing.T) (influxdb.AuditLogService, func()) {
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing audit log service: %v", err)
+	}
+
+	for _, e := range f.Events {
+		id := e.ID
+		svc.IDGenerator = mock.IDGenerator{IDFn: func() influxdb.ID { return id }}
+		if err := svc.CreateAuditEvent(ctx, e); err != nil {
+			t.Fatalf("failed to populate audit events: %v", err)
+		}
+	}
+	svc.IDGenerator = f.IDGenerator
+
+	return svc, func() {}
+}
+
+func TestService_DeleteAuditEventsBefore(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2019, 2, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		e := &influxdb.AuditEvent{
+			Time:         start.Add(time.Duration(i) * time.Hour),
+			Action:       influxdb.AuditCreate,
+			ResourceType: influxdb.BucketsResourceType,
+		}
+		if err := svc.CreateAuditEvent(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := svc.DeleteAuditEventsBefore(ctx, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 deleted event, got %d", n)
+	}
+
+	es, _, err := svc.FindAuditEvents(ctx, influxdb.AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(es) != 2 {
+		t.Fatalf("expected 2 remaining events, got %d", len(es))
+	}
+	if want := start.Add(time.Hour); !es[0].Time.Equal(want) {
+		t.Errorf("expected the oldest remaining event at %v, got %v", want, es[0].Time)
+	}
+}
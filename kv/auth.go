@@ -1,16 +1,33 @@
 package kv
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb"
 )
 
 var (
 	authBucket = []byte("authorizationsv1")
-	authIndex  = []byte("authorizationindexv1")
+	// authIndex indexed the raw tokens of authorizations before they were hashed.
+	// It is only kept to be emptied by migrateAuthTokens.
+	authIndex            = []byte("authorizationindexv1")
+	authTokenPrefixIndex = []byte("authorizationtokenprefixindexv1")
+)
+
+const (
+	// authTokenPrefixLen is the length of the token prefixes by which authorizations are indexed.
+	// It is short enough to not give away a token, and long enough to rarely match more than one authorization.
+	authTokenPrefixLen = 8
+	authTokenSaltLen   = 16
 )
 
 var _ influxdb.AuthorizationService = (*Service)(nil)
@@ -22,9 +39,151 @@ func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := authIndexBucket(tx); err != nil {
 		return err
 	}
+	if _, err := authTokenPrefixIndexBucket(tx); err != nil {
+		return err
+	}
+	return s.migrateAuthTokens(ctx, tx)
+}
+
+// migrateAuthTokens replaces the raw tokens of authorizations stored before tokens were hashed with their hashes,
+// and removes the raw tokens from the index.
+func (s *Service) migrateAuthTokens(ctx context.Context, tx Tx) error {
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var legacy []*storedAuthorization
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		sa := &storedAuthorization{}
+		if err := decodeStoredAuthorization(v, sa); err != nil {
+			return err
+		}
+		if sa.Token != "" || sa.PreviousToken != "" {
+			legacy = append(legacy, sa)
+		}
+	}
+
+	for _, sa := range legacy {
+		if err := hashAuthTokens(sa); err != nil {
+			return err
+		}
+		if err := putStoredAuthorization(tx, sa); err != nil {
+			return err
+		}
+	}
+
+	// Delete every raw token, including those left behind by rotations and updates.
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	idxCur, err := idx.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for k, _ := idxCur.First(); k != nil; k, _ = idxCur.Next() {
+		keys = append(keys, k)
+	}
+	for _, k := range keys {
+		if err := idx.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storedAuthorization is an authorization as it is stored:
+// instead of its tokens, it has their salted hashes, and their prefixes by which it is indexed.
+// Token and PreviousToken of the embedded authorization are only set in records stored before tokens were hashed.
+type storedAuthorization struct {
+	influxdb.Authorization
+	TokenHash           string `json:"tokenHash,omitempty"`
+	TokenPrefix         string `json:"tokenPrefix,omitempty"`
+	PreviousTokenHash   string `json:"previousTokenHash,omitempty"`
+	PreviousTokenPrefix string `json:"previousTokenPrefix,omitempty"`
+}
+
+// hashAuthTokens replaces the raw tokens of sa with their hashes and prefixes.
+func hashAuthTokens(sa *storedAuthorization) error {
+	if sa.Token != "" {
+		h, err := hashAuthToken(sa.Token)
+		if err != nil {
+			return err
+		}
+		sa.TokenHash, sa.TokenPrefix = h, authTokenPrefix(sa.Token)
+		sa.Token = ""
+	}
+
+	if sa.PreviousToken != "" {
+		h, err := hashAuthToken(sa.PreviousToken)
+		if err != nil {
+			return err
+		}
+		sa.PreviousTokenHash, sa.PreviousTokenPrefix = h, authTokenPrefix(sa.PreviousToken)
+		sa.PreviousToken = ""
+	}
 	return nil
 }
 
+// authTokenPrefix returns the prefix of token by which its authorization is indexed.
+// It is at most half the token, so that short tokens given by users are not stored in full.
+func authTokenPrefix(token string) string {
+	n := authTokenPrefixLen
+	if l := len(token) / 2; l < n {
+		n = l
+	}
+	return token[:n]
+}
+
+// hashAuthToken returns the hex encoded random salt and SHA-256 hash of the salted token, separated by '$'.
+// Tokens are generated with enough entropy that a fast hash is enough to protect them,
+// and it keeps authenticating requests cheap.
+func hashAuthToken(token string) (string, error) {
+	salt := make([]byte, authTokenSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	sum := saltedAuthTokenHash(salt, token)
+	return hex.EncodeToString(salt) + "$" + hex.EncodeToString(sum[:]), nil
+}
+
+// authTokenMatches returns true if token is the token hashed by hashAuthToken into hash.
+func authTokenMatches(hash, token string) bool {
+	i := strings.IndexByte(hash, '$')
+	if i < 0 {
+		return false
+	}
+	salt, err := hex.DecodeString(hash[:i])
+	if err != nil {
+		return false
+	}
+	want, err := hex.DecodeString(hash[i+1:])
+	if err != nil {
+		return false
+	}
+	sum := saltedAuthTokenHash(salt, token)
+	return subtle.ConstantTimeCompare(sum[:], want) == 1
+}
+
+func saltedAuthTokenHash(salt []byte, token string) [sha256.Size]byte {
+	b := make([]byte, 0, len(salt)+len(token))
+	b = append(b, salt...)
+	b = append(b, token...)
+	return sha256.Sum256(b)
+}
+
 // FindAuthorizationByID retrieves a authorization by id.
 func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*influxdb.Authorization, error) {
 	var a *influxdb.Authorization
@@ -45,7 +204,16 @@ func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*i
 	return a, nil
 }
 
+// findAuthorizationByID returns the authorization with the given ID. Its tokens are not set, as only their hashes are stored.
 func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Authorization, error) {
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &sa.Authorization, nil
+}
+
+func (s *Service) findStoredAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*storedAuthorization, error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &influxdb.Error{
@@ -71,15 +239,15 @@ func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.
 		return nil, err
 	}
 
-	a := &influxdb.Authorization{}
-	if err := decodeAuthorization(v, a); err != nil {
+	sa := &storedAuthorization{}
+	if err := decodeStoredAuthorization(v, sa); err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
 
-	return a, nil
+	return sa, nil
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
@@ -103,31 +271,82 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*infl
 	return a, nil
 }
 
+// findAuthorizationByToken returns the authorization whose token or previous token is n, with that token set.
 func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string) (*influxdb.Authorization, error) {
-	idx, err := authIndexBucket(tx)
+	sa, err := s.findStoredAuthorizationByToken(ctx, tx, n)
 	if err != nil {
 		return nil, err
 	}
 
-	a, err := idx.Get(authIndexKey(n))
-	if IsNotFound(err) {
+	a := &sa.Authorization
+	if authTokenMatches(sa.TokenHash, n) {
+		a.Token = n
+	} else {
+		a.PreviousToken = n
+	}
+
+	// The previous token of a rotated authorization is still indexed after its grace period ended.
+	if !a.AcceptsToken(n, s.time()) {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
 			Msg:  "authorization not found",
 		}
 	}
+	return a, nil
+}
 
-	var id influxdb.ID
-	if err := id.Decode(a); err != nil {
-		return nil, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Err:  err,
+// findStoredAuthorizationByToken returns the authorization whose token or previous token hashes to n, whether or not it is still accepted.
+func (s *Service) findStoredAuthorizationByToken(ctx context.Context, tx Tx, n string) (*storedAuthorization, error) {
+	idx, err := authTokenPrefixIndexBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := []byte(authTokenPrefix(n))
+	for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		// Skip the longer prefixes of other tokens which start with this one.
+		if len(k) != len(prefix)+influxdb.IDLength {
+			continue
 		}
+
+		var id influxdb.ID
+		if err := id.Decode(v); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if authTokenMatches(sa.TokenHash, n) || authTokenMatches(sa.PreviousTokenHash, n) {
+			return sa, nil
+		}
+	}
+
+	return nil, &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "authorization not found",
 	}
-	return s.findAuthorizationByID(ctx, tx, id)
 }
 
 func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxdb.Authorization) bool {
+	if filter.UnusedSince != nil {
+		t := *filter.UnusedSince
+		filter.UnusedSince = nil
+		fn := filterAuthorizationsFn(filter)
+		return func(a *influxdb.Authorization) bool {
+			return a.UnusedSince(t) && fn(a)
+		}
+	}
+
 	if filter.ID != nil {
 		return func(a *influxdb.Authorization) bool {
 			return a.ID == *filter.ID
@@ -220,6 +439,7 @@ func (s *Service) findAuthorizations(ctx context.Context, tx Tx, f influxdb.Auth
 }
 
 // CreateAuthorization creates a influxdb authorization and sets b.ID, and b.UserID if not provided.
+// a.Token is the only place the token is returned, as only its hash is stored.
 func (s *Service) CreateAuthorization(ctx context.Context, a *influxdb.Authorization) error {
 	return s.kv.Update(func(tx Tx) error {
 		return s.createAuthorization(ctx, tx, a)
@@ -241,8 +461,10 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 		return influxdb.ErrUnableToCreateToken
 	}
 
-	if err := s.uniqueAuthToken(ctx, tx, a); err != nil {
-		return err
+	if a.Token != "" {
+		if err := s.uniqueAuthToken(ctx, tx, a.Token); err != nil {
+			return err
+		}
 	}
 
 	if a.Token == "" {
@@ -256,6 +478,10 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 	}
 
 	a.ID = s.IDGenerator.ID()
+	a.CreatedAt = s.time()
+	a.LastUsedAt = nil
+	a.PreviousToken = ""
+	a.PreviousTokenExpiresAt = nil
 
 	if err := s.putAuthorization(ctx, tx, a); err != nil {
 		return err
@@ -265,17 +491,18 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 }
 
 // PutAuthorization will put a authorization without setting an ID.
+// Its tokens are hashed before it is stored.
 func (s *Service) PutAuthorization(ctx context.Context, a *influxdb.Authorization) error {
 	return s.kv.Update(func(tx Tx) error {
 		return s.putAuthorization(ctx, tx, a)
 	})
 }
 
-func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
-	switch a.Status {
+func encodeStoredAuthorization(sa *storedAuthorization) ([]byte, error) {
+	switch sa.Status {
 	case influxdb.Active, influxdb.Inactive:
 	case "":
-		a.Status = influxdb.Active
+		sa.Status = influxdb.Active
 	default:
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -283,11 +510,34 @@ func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	return json.Marshal(sa)
 }
 
 func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	v, err := encodeAuthorization(a)
+	// The tokens of an authorization which is replaced are no longer indexed.
+	if old, err := s.findStoredAuthorizationByID(ctx, tx, a.ID); err == nil {
+		if err := deleteAuthTokenPrefixIndexKeys(tx, old); err != nil {
+			return err
+		}
+	} else if influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+
+	sa := &storedAuthorization{Authorization: *a}
+	if err := hashAuthTokens(sa); err != nil {
+		return err
+	}
+	if err := putStoredAuthorization(tx, sa); err != nil {
+		return err
+	}
+
+	a.Status = sa.Status
+	return nil
+}
+
+// putStoredAuthorization stores sa, and indexes it by the prefixes of its tokens.
+func putStoredAuthorization(tx Tx, sa *storedAuthorization) error {
+	v, err := encodeStoredAuthorization(sa)
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -295,7 +545,7 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		}
 	}
 
-	encodedID, err := a.ID.Encode()
+	encodedID, err := sa.ID.Encode()
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -303,15 +553,20 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		}
 	}
 
-	idx, err := authIndexBucket(tx)
+	idx, err := authTokenPrefixIndexBucket(tx)
 	if err != nil {
 		return err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
-		return &influxdb.Error{
-			Code: influxdb.EInternal,
-			Err:  err,
+	for _, prefix := range []string{sa.TokenPrefix, sa.PreviousTokenPrefix} {
+		if prefix == "" {
+			continue
+		}
+		if err := idx.Put(authTokenPrefixIndexKey(prefix, encodedID), encodedID); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
 		}
 	}
 
@@ -329,16 +584,20 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 	return nil
 }
 
-func authIndexKey(n string) []byte {
-	return []byte(n)
+// authTokenPrefixIndexKey returns the key indexing an authorization by a token prefix.
+// The encoded ID of the authorization is appended, as several authorizations may have tokens with the same prefix.
+func authTokenPrefixIndexKey(prefix string, encodedID []byte) []byte {
+	k := make([]byte, 0, len(prefix)+len(encodedID))
+	k = append(k, prefix...)
+	return append(k, encodedID...)
 }
 
-func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
-	if err := json.Unmarshal(b, a); err != nil {
+func decodeStoredAuthorization(b []byte, sa *storedAuthorization) error {
+	if err := json.Unmarshal(b, sa); err != nil {
 		return err
 	}
-	if a.Status == "" {
-		a.Status = influxdb.Active
+	if sa.Status == "" {
+		sa.Status = influxdb.Active
 	}
 	return nil
 }
@@ -356,12 +615,12 @@ func (s *Service) forEachAuthorization(ctx context.Context, tx Tx, fn func(*infl
 	}
 
 	for k, v := cur.First(); k != nil; k, v = cur.Next() {
-		a := &influxdb.Authorization{}
+		sa := &storedAuthorization{}
 
-		if err := decodeAuthorization(v, a); err != nil {
+		if err := decodeStoredAuthorization(v, sa); err != nil {
 			return err
 		}
-		if !fn(a) {
+		if !fn(&sa.Authorization) {
 			break
 		}
 	}
@@ -377,21 +636,14 @@ func (s *Service) DeleteAuthorization(ctx context.Context, id influxdb.ID) error
 }
 
 func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID) error {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
 	if err != nil {
 		return err
 	}
 
-	idx, err := authIndexBucket(tx)
-	if err != nil {
+	if err := deleteAuthTokenPrefixIndexKeys(tx, sa); err != nil {
 		return err
 	}
-
-	if err := idx.Delete(authIndexKey(a.Token)); err != nil {
-		return &influxdb.Error{
-			Err: err,
-		}
-	}
 	encodedID, err := id.Encode()
 	if err != nil {
 		return &influxdb.Error{
@@ -421,13 +673,13 @@ func (s *Service) SetAuthorizationStatus(ctx context.Context, id influxdb.ID, st
 }
 
 func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, status influxdb.Status) error {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
 	if err != nil {
 		return err
 	}
 
-	a.Status = status
-	v, err := encodeAuthorization(a)
+	sa.Status = status
+	v, err := encodeStoredAuthorization(sa)
 	if err != nil {
 		return &influxdb.Error{
 			Err: err,
@@ -454,6 +706,102 @@ func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 	return nil
 }
 
+// RotateAuthorization replaces the token of an authorization with a new one, and returns the updated authorization.
+// The current token is still accepted for gracePeriod.
+func (s *Service) RotateAuthorization(ctx context.Context, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	var a *influxdb.Authorization
+	err := s.kv.Update(func(tx Tx) error {
+		auth, err := s.rotateAuthorization(ctx, tx, id, gracePeriod)
+		if err != nil {
+			return err
+		}
+		a = auth
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *Service) rotateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.TokenGenerator.Token()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	if err := s.uniqueAuthToken(ctx, tx, token); err != nil {
+		return nil, err
+	}
+
+	// putStoredAuthorization indexes the tokens which are still accepted after the rotation.
+	if err := deleteAuthTokenPrefixIndexKeys(tx, sa); err != nil {
+		return nil, err
+	}
+
+	// Only the hash of the current token is known, and it becomes the hash of the previous token.
+	sa.PreviousTokenHash, sa.PreviousTokenPrefix = "", ""
+	if gracePeriod > 0 {
+		sa.PreviousTokenHash, sa.PreviousTokenPrefix = sa.TokenHash, sa.TokenPrefix
+	}
+	sa.Rotate(token, s.time(), gracePeriod)
+
+	a := sa.Authorization
+	if err := hashAuthTokens(sa); err != nil {
+		return nil, err
+	}
+	if err := putStoredAuthorization(tx, sa); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// SetAuthorizationLastUsed records the time the authorization's token was last used at.
+func (s *Service) SetAuthorizationLastUsed(ctx context.Context, id influxdb.ID, t time.Time) error {
+	return s.kv.Update(func(tx Tx) error {
+		sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		sa.LastUsedAt = &t
+		return putStoredAuthorization(tx, sa)
+	})
+}
+
+// deleteAuthTokenPrefixIndexKeys removes the prefixes of the current and previous tokens of sa from the index.
+func deleteAuthTokenPrefixIndexKeys(tx Tx, sa *storedAuthorization) error {
+	idx, err := authTokenPrefixIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	encodedID, err := sa.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	for _, prefix := range []string{sa.TokenPrefix, sa.PreviousTokenPrefix} {
+		if prefix == "" {
+			continue
+		}
+		if err := idx.Delete(authTokenPrefixIndexKey(prefix, encodedID)); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+	}
+	return nil
+}
+
 func authIndexBucket(tx Tx) (Bucket, error) {
 	b, err := tx.Bucket([]byte(authIndex))
 	if err != nil {
@@ -463,6 +811,15 @@ func authIndexBucket(tx Tx) (Bucket, error) {
 	return b, nil
 }
 
+func authTokenPrefixIndexBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(authTokenPrefixIndex)
+	if err != nil {
+		return nil, UnexpectedAuthIndexError(err)
+	}
+
+	return b, nil
+}
+
 // UnexpectedAuthIndexError is used when the error comes from an internal system.
 func UnexpectedAuthIndexError(err error) *influxdb.Error {
 	return &influxdb.Error{
@@ -472,13 +829,17 @@ func UnexpectedAuthIndexError(err error) *influxdb.Error {
 	}
 }
 
-func (s *Service) uniqueAuthToken(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	err := s.unique(ctx, tx, authIndex, authIndexKey(a.Token))
-	if err == NotUniqueError {
+// uniqueAuthToken returns an error if token is the current or previous token of an authorization.
+func (s *Service) uniqueAuthToken(ctx context.Context, tx Tx, token string) error {
+	_, err := s.findStoredAuthorizationByToken(ctx, tx, token)
+	if err == nil {
 		// by returning a generic error we are trying to hide when
 		// a token is non-unique.
 		return influxdb.ErrUnableToCreateToken
 	}
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil
+	}
 	// otherwise, this is some sort of internal server error and we
 	// should provide some debugging information.
 	return err
@@ -1,8 +1,10 @@
 package kv_test
 
 import (
+	"bytes"
 	"context"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
@@ -10,11 +12,11 @@ import (
 )
 
 func TestBoltAuthorizationService(t *testing.T) {
-	influxdbtesting.AuthorizationService(initBoltAuthorizationService, t)
+	influxdbtesting.AuthorizationService(initBoltAuthorizationService, t, influxdbtesting.WithHashedTokens())
 }
 
 func TestInmemAuthorizationService(t *testing.T) {
-	influxdbtesting.AuthorizationService(initInmemAuthorizationService, t)
+	influxdbtesting.AuthorizationService(initInmemAuthorizationService, t, influxdbtesting.WithHashedTokens())
 }
 
 func initBoltAuthorizationService(f influxdbtesting.AuthorizationFields, t *testing.T) (influxdb.AuthorizationService, string, func()) {
@@ -91,3 +93,177 @@ func initAuthorizationService(s kv.Store, f influxdbtesting.AuthorizationFields,
 		}
 	}
 }
+
+// authBuckets are the buckets in which authorizations and their index are stored.
+var authBuckets = [][]byte{
+	[]byte("authorizationsv1"),
+	[]byte("authorizationindexv1"),
+	[]byte("authorizationtokenprefixindexv1"),
+}
+
+// assertTokenNotStored fails t if token appears in any key or value of the authorization buckets.
+func assertTokenNotStored(t *testing.T, s kv.Store, token string) {
+	t.Helper()
+	err := s.View(func(tx kv.Tx) error {
+		for _, name := range authBuckets {
+			b, err := tx.Bucket(name)
+			if err != nil {
+				return err
+			}
+			cur, err := b.Cursor()
+			if err != nil {
+				return err
+			}
+			for k, v := cur.First(); k != nil; k, v = cur.Next() {
+				if bytes.Contains(k, []byte(token)) || bytes.Contains(v, []byte(token)) {
+					t.Errorf("expected token not to be stored, found it in bucket %s under key %q", name, k)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read authorization buckets: %v", err)
+	}
+}
+
+func TestService_AuthorizationTokenHashed(t *testing.T) {
+	s, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing authorization service: %v", err)
+	}
+
+	u := &influxdb.User{Name: "cooluser"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	o := &influxdb.Organization{Name: "o1"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatalf("failed to create org: %v", err)
+	}
+
+	a := &influxdb.Authorization{
+		OrgID:       o.ID,
+		UserID:      u.ID,
+		Permissions: influxdb.OperPermissions(),
+	}
+	if err := svc.CreateAuthorization(ctx, a); err != nil {
+		t.Fatalf("failed to create authorization: %v", err)
+	}
+	if a.Token == "" {
+		t.Fatal("expected the token of the created authorization to be returned")
+	}
+	assertTokenNotStored(t, s, a.Token)
+
+	found, err := svc.FindAuthorizationByID(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("failed to find authorization: %v", err)
+	}
+	if found.Token != "" {
+		t.Errorf("expected the token not to be returned after creation, got %q", found.Token)
+	}
+
+	found, err = svc.FindAuthorizationByToken(ctx, a.Token)
+	if err != nil {
+		t.Fatalf("failed to find authorization by token: %v", err)
+	}
+	if found.ID != a.ID || found.Token != a.Token {
+		t.Errorf("expected authorization %s with its token, got %s with token %q", a.ID, found.ID, found.Token)
+	}
+
+	// A token which shares the indexed prefix of another token is not accepted.
+	if _, err := svc.FindAuthorizationByToken(ctx, a.Token[:len(a.Token)-1]+"!"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a token with the same prefix not to be found, got error %v", err)
+	}
+
+	rotated, err := svc.RotateAuthorization(ctx, a.ID, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to rotate authorization: %v", err)
+	}
+	assertTokenNotStored(t, s, a.Token)
+	assertTokenNotStored(t, s, rotated.Token)
+	for _, token := range []string{a.Token, rotated.Token} {
+		if _, err := svc.FindAuthorizationByToken(ctx, token); err != nil {
+			t.Errorf("expected token %q to be accepted after rotation, got error %v", token, err)
+		}
+	}
+}
+
+func TestService_Initialize_MigratesAuthorizationTokens(t *testing.T) {
+	s, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	// An authorization as it was stored before tokens were hashed.
+	const (
+		token    = "legacy-token-value"
+		previous = "legacy-previous-token"
+	)
+	id := influxdbtesting.MustIDBase16("020f755c3c082000")
+	encodedID, err := id.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano)
+	legacy := `{"id":"020f755c3c082000","token":"` + token + `","status":"active","orgID":"020f755c3c082001","userID":"020f755c3c082002",` +
+		`"permissions":[],"previousToken":"` + previous + `","previousTokenExpiresAt":"` + expiresAt + `"}`
+
+	err = s.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(authBuckets[0])
+		if err != nil {
+			return err
+		}
+		if err := b.Put(encodedID, []byte(legacy)); err != nil {
+			return err
+		}
+
+		idx, err := tx.Bucket(authBuckets[1])
+		if err != nil {
+			return err
+		}
+		if err := idx.Put([]byte(token), encodedID); err != nil {
+			return err
+		}
+		return idx.Put([]byte(previous), encodedID)
+	})
+	if err != nil {
+		t.Fatalf("failed to store legacy authorization: %v", err)
+	}
+
+	svc := kv.NewService(s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing authorization service: %v", err)
+	}
+
+	assertTokenNotStored(t, s, token)
+	assertTokenNotStored(t, s, previous)
+
+	for _, tok := range []string{token, previous} {
+		a, err := svc.FindAuthorizationByToken(ctx, tok)
+		if err != nil {
+			t.Errorf("expected migrated token %q to be accepted, got error %v", tok, err)
+			continue
+		}
+		if a.ID != id {
+			t.Errorf("expected token %q to find authorization %s, got %s", tok, id, a.ID)
+		}
+	}
+
+	// Migrating again leaves the hashed authorization as it is.
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing authorization service again: %v", err)
+	}
+	if _, err := svc.FindAuthorizationByToken(ctx, token); err != nil {
+		t.Errorf("expected migrated token to be accepted after initializing again, got error %v", err)
+	}
+}
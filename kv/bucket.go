@@ -373,6 +373,10 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) e
 		b.OrganizationID = o.ID
 	}
 
+	if err := b.SchemaType.Valid(); err != nil {
+		return err
+	}
+
 	// if the bucket name is not unique for this organization, then, do not
 	// allow creation.
 	if err := s.uniqueBucketName(ctx, tx, b); err != nil {
@@ -381,6 +385,10 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) e
 
 	b.ID = s.IDGenerator.ID()
 
+	if err := influxdb.ValidDownsampleRules(b.ID, b.DownsampleRules); err != nil {
+		return err
+	}
+
 	if err := s.appendBucketEventToLog(ctx, tx, b.ID, bucketCreatedEvent); err != nil {
 		return &influxdb.Error{
 			Err: err,
@@ -572,6 +580,20 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.DownsampleRules != nil {
+		if err := influxdb.ValidDownsampleRules(b.ID, *upd.DownsampleRules); err != nil {
+			return nil, err
+		}
+		b.DownsampleRules = *upd.DownsampleRules
+	}
+
+	if upd.SchemaType != nil {
+		if err := upd.SchemaType.Valid(); err != nil {
+			return nil, err
+		}
+		b.SchemaType = *upd.SchemaType
+	}
+
 	if upd.Name != nil {
 		key, err := bucketIndexKey(b)
 		if err != nil {
@@ -662,6 +684,10 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
+	if err := s.deleteMeasurementSchemas(ctx, tx, id); err != nil {
+		return err
+	}
+
 	return nil
 }
 
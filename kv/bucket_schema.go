@@ -0,0 +1,321 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	bucketSchemaBucket = []byte("bucketschemasv1")
+)
+
+var _ influxdb.BucketSchemaService = (*Service)(nil)
+
+func (s *Service) initializeBucketSchemas(ctx context.Context, tx Tx) error {
+	if _, err := s.bucketSchemasBucket(tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Service) bucketSchemasBucket(tx Tx) (Bucket, error) {
+	b, err := tx.Bucket(bucketSchemaBucket)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unexpected error retrieving bucket schema bucket",
+			Err:  err,
+			Op:   "kv/bucketSchemasBucket",
+		}
+	}
+
+	return b, nil
+}
+
+// encodeBucketSchemaKey returns the key of a measurement schema, which is the
+// bucket ID followed by the measurement name, so that the schemas of a bucket
+// can be found by prefix.
+func encodeBucketSchemaKey(bucketID influxdb.ID, name string) ([]byte, error) {
+	prefix, err := bucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return append(prefix, name...), nil
+}
+
+// FindMeasurementSchema retrieves the schema of a measurement in a bucket.
+func (s *Service) FindMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string) (*influxdb.MeasurementSchema, error) {
+	var m *influxdb.MeasurementSchema
+	err := s.kv.View(func(tx Tx) error {
+		sch, err := s.findMeasurementSchema(ctx, tx, bucketID, name)
+		if err != nil {
+			return err
+		}
+		m = sch
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMeasurementSchema,
+			Err: err,
+		}
+	}
+
+	return m, nil
+}
+
+func (s *Service) findMeasurementSchema(ctx context.Context, tx Tx, bucketID influxdb.ID, name string) (*influxdb.MeasurementSchema, error) {
+	key, err := encodeBucketSchemaKey(bucketID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.bucketSchemasBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrMeasurementSchemaNotFound,
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m influxdb.MeasurementSchema
+	if err := json.Unmarshal(v, &m); err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return &m, nil
+}
+
+// FindMeasurementSchemas retrieves the schemas of every measurement in a bucket,
+// sorted by measurement name.
+func (s *Service) FindMeasurementSchemas(ctx context.Context, bucketID influxdb.ID) ([]*influxdb.MeasurementSchema, error) {
+	var ms []*influxdb.MeasurementSchema
+	err := s.kv.View(func(tx Tx) error {
+		schs, err := s.findMeasurementSchemas(ctx, tx, bucketID)
+		if err != nil {
+			return err
+		}
+		ms = schs
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMeasurementSchemas,
+			Err: err,
+		}
+	}
+
+	return ms, nil
+}
+
+func (s *Service) findMeasurementSchemas(ctx context.Context, tx Tx, bucketID influxdb.ID) ([]*influxdb.MeasurementSchema, error) {
+	prefix, err := encodeBucketSchemaKey(bucketID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.bucketSchemasBucket(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	ms := []*influxdb.MeasurementSchema{}
+	for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		var m influxdb.MeasurementSchema
+		if err := json.Unmarshal(v, &m); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		ms = append(ms, &m)
+	}
+
+	return ms, nil
+}
+
+// CreateMeasurementSchema creates the schema of a measurement in a bucket with
+// an explicit schema.
+func (s *Service) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	err := s.kv.Update(func(tx Tx) error {
+		return s.createMeasurementSchema(ctx, tx, m)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateMeasurementSchema,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) createMeasurementSchema(ctx context.Context, tx Tx, m *influxdb.MeasurementSchema) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	bkt, err := s.findBucketByID(ctx, tx, m.BucketID)
+	if err != nil {
+		return err
+	}
+
+	if bkt.SchemaType != influxdb.SchemaTypeExplicit {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("bucket %q does not have an explicit schema", bkt.Name),
+		}
+	}
+
+	if _, err := s.findMeasurementSchema(ctx, tx, m.BucketID, m.Name); err == nil {
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("measurement schema %q already exists", m.Name),
+		}
+	} else if influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+
+	return s.putMeasurementSchema(ctx, tx, m)
+}
+
+func (s *Service) putMeasurementSchema(ctx context.Context, tx Tx, m *influxdb.MeasurementSchema) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	key, err := encodeBucketSchemaKey(m.BucketID, m.Name)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.bucketSchemasBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// UpdateMeasurementSchema updates the schema of a measurement in a bucket.
+func (s *Service) UpdateMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	var m *influxdb.MeasurementSchema
+	err := s.kv.Update(func(tx Tx) error {
+		sch, err := s.findMeasurementSchema(ctx, tx, bucketID, name)
+		if err != nil {
+			return err
+		}
+
+		if err := upd.Apply(sch); err != nil {
+			return err
+		}
+
+		if err := s.putMeasurementSchema(ctx, tx, sch); err != nil {
+			return err
+		}
+
+		m = sch
+		return nil
+	})
+
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateMeasurementSchema,
+			Err: err,
+		}
+	}
+
+	return m, nil
+}
+
+// DeleteMeasurementSchema removes the schema of a measurement from a bucket.
+func (s *Service) DeleteMeasurementSchema(ctx context.Context, bucketID influxdb.ID, name string) error {
+	err := s.kv.Update(func(tx Tx) error {
+		if _, err := s.findMeasurementSchema(ctx, tx, bucketID, name); err != nil {
+			return err
+		}
+
+		key, err := encodeBucketSchemaKey(bucketID, name)
+		if err != nil {
+			return err
+		}
+
+		b, err := s.bucketSchemasBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return b.Delete(key)
+	})
+
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteMeasurementSchema,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// deleteMeasurementSchemas removes the schemas of every measurement in a bucket.
+func (s *Service) deleteMeasurementSchemas(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	ms, err := s.findMeasurementSchemas(ctx, tx, bucketID)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.bucketSchemasBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		key, err := encodeBucketSchemaKey(bucketID, m.Name)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Delete(key); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+	}
+
+	return nil
+}
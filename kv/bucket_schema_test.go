@@ -0,0 +1,113 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltBucketSchemaService(t *testing.T) {
+	influxdbtesting.BucketSchemaService(initBoltBucketSchemaService, t)
+}
+
+func TestInmemBucketSchemaService(t *testing.T) {
+	influxdbtesting.BucketSchemaService(initInmemBucketSchemaService, t)
+}
+
+func initBoltBucketSchemaService(f influxdbtesting.BucketSchemaFields, t *testing.T) (influxdb.BucketSchemaService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initBucketSchemaService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initInmemBucketSchemaService(f influxdbtesting.BucketSchemaFields, t *testing.T) (influxdb.BucketSchemaService, func()) {
+	s, closeBolt, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	svc, closeSvc := initBucketSchemaService(s, f, t)
+	return svc, func() {
+		closeSvc()
+		closeBolt()
+	}
+}
+
+func initBucketSchemaService(s kv.Store, f influxdbtesting.BucketSchemaFields, t *testing.T) (influxdb.BucketSchemaService, func()) {
+	svc := kv.NewService(s)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing bucket schema service: %v", err)
+	}
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets")
+		}
+	}
+	for _, m := range f.MeasurementSchemas {
+		if err := svc.CreateMeasurementSchema(ctx, m); err != nil {
+			t.Fatalf("failed to populate measurement schemas: %v", err)
+		}
+	}
+	return svc, func() {
+		for _, b := range f.Buckets {
+			if err := svc.DeleteBucket(ctx, b.ID); err != nil {
+				t.Logf("failed to remove bucket: %v", err)
+			}
+		}
+		for _, o := range f.Organizations {
+			if err := svc.DeleteOrganization(ctx, o.ID); err != nil {
+				t.Logf("failed to remove organization: %v", err)
+			}
+		}
+	}
+}
+
+func TestService_UpdateBucket_SchemaType(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+
+	svc := kv.NewService(s)
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	b := &influxdb.Bucket{ID: influxdb.ID(1), OrganizationID: influxdb.ID(2), Name: "b"}
+	if err := svc.PutBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	explicit := influxdb.SchemaTypeExplicit
+	b, err = svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{SchemaType: &explicit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.SchemaType != influxdb.SchemaTypeExplicit {
+		t.Errorf("expected the schema type to be updated to %q, got %q", influxdb.SchemaTypeExplicit, b.SchemaType)
+	}
+
+	invalid := influxdb.SchemaType("strict")
+	_, err = svc.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{SchemaType: &invalid})
+	if code := influxdb.ErrorCode(err); code != influxdb.EInvalid {
+		t.Errorf("expected an invalid schema type to be rejected, got %v", err)
+	}
+}
@@ -29,17 +29,19 @@ func NewStaticCursor(pairs []Pair) Cursor {
 	}
 }
 
-// Seek searches the slice for the first key with the provided prefix.
+// Seek moves the cursor to the first key greater than or equal to prefix,
+// which is the first key with the provided prefix if there is one.
 func (c *staticCursor) Seek(prefix []byte) ([]byte, []byte) {
-	// TODO: do binary search for prefix since pairs are ordered.
-	for i, pair := range c.pairs {
-		if bytes.HasPrefix(pair.Key, prefix) {
-			c.idx = i
-			return pair.Key, pair.Value
-		}
+	i := sort.Search(len(c.pairs), func(i int) bool {
+		return bytes.Compare(c.pairs[i].Key, prefix) >= 0
+	})
+	if i == len(c.pairs) {
+		return nil, nil
 	}
 
-	return nil, nil
+	c.idx = i
+	pair := c.pairs[c.idx]
+	return pair.Key, pair.Value
 }
 
 func (c *staticCursor) getValueAtIndex(delta int) ([]byte, []byte) {
@@ -224,6 +224,43 @@ func TestStaticCursor_Seek(t *testing.T) {
 				val: []byte("yoyo"),
 			},
 		},
+		{
+			name: "no key with prefix",
+			args: args{
+				prefix: []byte("bb"),
+				pairs: []kv.Pair{
+					{
+						Key:   []byte("abc"),
+						Value: []byte("oyoy"),
+					},
+					{
+						Key:   []byte("bcd"),
+						Value: []byte("yoyo"),
+					},
+				},
+			},
+			wants: wants{
+				key: []byte("bcd"),
+				val: []byte("yoyo"),
+			},
+		},
+		{
+			name: "past the last key",
+			args: args{
+				prefix: []byte("c"),
+				pairs: []kv.Pair{
+					{
+						Key:   []byte("abc"),
+						Value: []byte("oyoy"),
+					},
+					{
+						Key:   []byte("bcd"),
+						Value: []byte("yoyo"),
+					},
+				},
+			},
+			wants: wants{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,123 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	userIdentityBucket = []byte("useridentitiesv1")
+)
+
+var _ influxdb.UserIdentityService = (*Service)(nil)
+
+func (s *Service) initializeUserIdentities(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(userIdentityBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// userIdentityKey returns the key of the identity with the given subject at provider.
+// Provider names do not contain NUL bytes, so the keys of different providers never collide.
+func userIdentityKey(provider, subject string) []byte {
+	return []byte(provider + "\x00" + subject)
+}
+
+// FindUserIdentity returns the identity with the given subject at provider.
+func (s *Service) FindUserIdentity(ctx context.Context, provider, subject string) (*influxdb.UserIdentity, error) {
+	var i *influxdb.UserIdentity
+	err := s.kv.View(func(tx Tx) error {
+		ui, err := s.findUserIdentity(ctx, tx, provider, subject)
+		if err != nil {
+			return err
+		}
+		i = ui
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindUserIdentity,
+			Err: err,
+		}
+	}
+	return i, nil
+}
+
+func (s *Service) findUserIdentity(ctx context.Context, tx Tx, provider, subject string) (*influxdb.UserIdentity, error) {
+	b, err := tx.Bucket(userIdentityBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(userIdentityKey(provider, subject))
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrUserIdentityNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	i := &influxdb.UserIdentity{}
+	if err := json.Unmarshal(v, i); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return i, nil
+}
+
+// PutUserIdentity links a user to an identity, replacing any previous link of the identity.
+func (s *Service) PutUserIdentity(ctx context.Context, i *influxdb.UserIdentity) error {
+	err := s.kv.Update(func(tx Tx) error {
+		return s.putUserIdentity(ctx, tx, i)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPutUserIdentity,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) putUserIdentity(ctx context.Context, tx Tx, i *influxdb.UserIdentity) error {
+	if i.Provider == "" || i.Subject == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "user identity must have a provider and a subject",
+		}
+	}
+	if !i.UserID.Valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  influxdb.ErrUserIDRequired.Error(),
+		}
+	}
+
+	v, err := json.Marshal(i)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(userIdentityBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(userIdentityKey(i.Provider, i.Subject), v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
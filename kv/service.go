@@ -44,6 +44,10 @@ func NewService(kv Store) *Service {
 // Initialize creates Buckets needed.
 func (s *Service) Initialize(ctx context.Context) error {
 	return s.kv.Update(func(tx Tx) error {
+		if err := s.initializeAuditLog(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeAuths(ctx, tx); err != nil {
 			return err
 		}
@@ -52,6 +56,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeBucketSchemas(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeDashboards(ctx, tx); err != nil {
 			return err
 		}
@@ -104,6 +112,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeUserIdentities(ctx, tx); err != nil {
+			return err
+		}
+
 		return s.initializeUsers(ctx, tx)
 	})
 }
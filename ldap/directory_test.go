@@ -0,0 +1,249 @@
+package ldap_test
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "gopkg.in/asn1-ber.v1"
+	goldap "gopkg.in/ldap.v2"
+)
+
+// entry is an entry of a directory. The password of an entry is its userPassword attribute.
+type entry struct {
+	dn    string
+	attrs map[string][]string
+}
+
+// directory is an in-process stand-in for an LDAP server.
+// It serves simple binds, and searches with equality and and filters, of its entries.
+type directory struct {
+	t  *testing.T
+	ln net.Listener
+
+	// searchDN, if set, is the only entry allowed to search the directory.
+	searchDN string
+
+	mu      sync.Mutex
+	entries []entry
+}
+
+func newDirectory(t *testing.T, searchDN string, entries ...entry) *directory {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &directory{
+		t:        t,
+		ln:       ln,
+		searchDN: searchDN,
+		entries:  entries,
+	}
+	go d.serve()
+	return d
+}
+
+// URL returns the URL of the directory.
+func (d *directory) URL() string {
+	return "ldap://" + d.ln.Addr().String()
+}
+
+// Close stops serving the directory.
+func (d *directory) Close() error {
+	return d.ln.Close()
+}
+
+// SetAttr replaces the values of an attribute of the entry with the given DN.
+func (d *directory) SetAttr(dn, attr string, values ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, e := range d.entries {
+		if strings.EqualFold(e.dn, dn) {
+			e.attrs[attr] = values
+			return
+		}
+	}
+	d.t.Fatalf("no entry %q in the directory", dn)
+}
+
+func (d *directory) serve() {
+	for {
+		c, err := d.ln.Accept()
+		if err != nil {
+			return
+		}
+		go d.serveConn(c)
+	}
+}
+
+func (d *directory) serveConn(c net.Conn) {
+	defer c.Close()
+
+	var boundDN string
+	for {
+		p, err := ber.ReadPacket(c)
+		if err != nil || len(p.Children) < 2 {
+			return
+		}
+		id, op := p.Children[0].Value, p.Children[1]
+
+		var res []*ber.Packet
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			dn, password := op.Children[1].Value.(string), op.Children[2].Data.String()
+			code := d.bind(dn, password)
+			if code == goldap.LDAPResultSuccess {
+				boundDN = dn
+			}
+			res = append(res, response(id, goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationSearchRequest:
+			if d.searchDN != "" && !strings.EqualFold(boundDN, d.searchDN) {
+				res = append(res, response(id, goldap.ApplicationSearchResultDone, goldap.LDAPResultInsufficientAccessRights))
+				break
+			}
+
+			var attrs []string
+			for _, a := range op.Children[7].Children {
+				attrs = append(attrs, a.Value.(string))
+			}
+			for _, e := range d.search(op.Children[0].Value.(string), op.Children[6]) {
+				res = append(res, searchEntry(id, e, attrs))
+			}
+			res = append(res, response(id, goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess))
+		default:
+			// Unbind, or a request the stand-in doesn't serve.
+			return
+		}
+
+		for _, r := range res {
+			if _, err := c.Write(r.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// bind returns the result code of a simple bind as the entry with the given DN.
+func (d *directory) bind(dn, password string) uint8 {
+	if dn == "" && password == "" {
+		return goldap.LDAPResultSuccess
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, e := range d.entries {
+		if strings.EqualFold(e.dn, dn) {
+			for _, p := range e.attrs["userPassword"] {
+				if password != "" && p == password {
+					return goldap.LDAPResultSuccess
+				}
+			}
+		}
+	}
+	return goldap.LDAPResultInvalidCredentials
+}
+
+// search returns the copies of the entries below base matching filter.
+func (d *directory) search(base string, filter *ber.Packet) []entry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var found []entry
+	for _, e := range d.entries {
+		dn, base := strings.ToLower(e.dn), strings.ToLower(base)
+		if dn != base && !strings.HasSuffix(dn, ","+base) {
+			continue
+		}
+		if !matches(e, filter) {
+			continue
+		}
+
+		c := entry{dn: e.dn, attrs: make(map[string][]string, len(e.attrs))}
+		for k, v := range e.attrs {
+			c.attrs[k] = append([]string(nil), v...)
+		}
+		found = append(found, c)
+	}
+	return found
+}
+
+func matches(e entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case goldap.FilterAnd:
+		for _, f := range filter.Children {
+			if !matches(e, f) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterEqualityMatch:
+		attr, value := filter.Children[0].Value.(string), filter.Children[1].Value.(string)
+		for k, vs := range e.attrs {
+			if !strings.EqualFold(k, attr) {
+				continue
+			}
+			for _, v := range vs {
+				if strings.EqualFold(v, value) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func message(id interface{}, op *ber.Packet) *ber.Packet {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	p.AppendChild(op)
+	return p
+}
+
+func response(id interface{}, tag ber.Tag, code uint8) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	return message(id, op)
+}
+
+// searchEntry returns the search result of an entry, with the given attributes, or all of them but the password if none are given.
+func searchEntry(id interface{}, e entry, attrs []string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "objectName"))
+
+	want := func(k string) bool {
+		if len(attrs) == 0 {
+			return k != "userPassword"
+		}
+		for _, a := range attrs {
+			if strings.EqualFold(a, k) {
+				return true
+			}
+		}
+		return false
+	}
+
+	as := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for k, vs := range e.attrs {
+		if !want(k) {
+			continue
+		}
+		a := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		a.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, k, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, v := range vs {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		a.AppendChild(set)
+		as.AppendChild(a)
+	}
+	op.AppendChild(as)
+	return message(id, op)
+}
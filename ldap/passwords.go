@@ -0,0 +1,340 @@
+// Package ldap authenticates users against an LDAP directory.
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/identity"
+	"go.uber.org/zap"
+	goldap "gopkg.in/ldap.v2"
+)
+
+// Provider is the provider of the identities of the users of a directory.
+const Provider = "ldap"
+
+var (
+	// ErrIncorrectPassword is returned when a user of the directory cannot be authenticated.
+	// It does not tell unknown users from incorrect passwords, so as not to leak the users of the directory.
+	ErrIncorrectPassword = &platform.Error{
+		Code: platform.EForbidden,
+		Msg:  "your username or password is incorrect",
+	}
+
+	// ErrDirectoryPassword is returned when changing the password of a user of the directory,
+	// whose password is managed by the directory.
+	ErrDirectoryPassword = &platform.Error{
+		Code: platform.EMethodNotAllowed,
+		Msg:  "the password of a user of the LDAP directory must be changed in the directory",
+	}
+)
+
+// Config is the configuration of the directory users are authenticated against.
+type Config struct {
+	// URL is the address of the server, such as ldap://ldap.example.com or ldaps://ldap.example.com:636.
+	URL string
+	// StartTLS is whether to upgrade ldap:// connections to TLS.
+	StartTLS bool
+	// InsecureSkipVerify skips the verification of the certificate of the server.
+	InsecureSkipVerify bool
+	// Timeout bounds connecting to the server, and each request made to it.
+	Timeout time.Duration
+
+	// BindDN and BindPassword are the credentials the users and their groups are searched with.
+	// The searches are anonymous if BindDN is empty.
+	BindDN       string
+	BindPassword string
+
+	// UserBaseDN is the entry below which users are searched.
+	UserBaseDN string
+	// UserFilter is the filter the entry of a user is searched with, such as (uid=%s),
+	// where %s is replaced with the escaped name of the user.
+	UserFilter string
+
+	// GroupBaseDN is the entry below which the groups of users are searched.
+	// Groups are not searched, and memberships are not synced, if it is empty.
+	GroupBaseDN string
+	// GroupFilter is the filter the groups of a user are searched with, such as (member=%s),
+	// where %s is replaced with the escaped DN of the user.
+	GroupFilter string
+	// GroupAttribute is the attribute of the entries of groups holding their name, such as cn.
+	GroupAttribute string
+
+	// FallbackWhenUnavailable is whether the passwords of users who never signed in with the directory are
+	// compared by the Fallback service while the directory is unavailable. It is off by default, as the users
+	// of the directory who had a local password before it was configured could then sign in with that password.
+	FallbackWhenUnavailable bool
+}
+
+var _ platform.PasswordsService = (*PasswordsService)(nil)
+
+// PasswordsService authenticates users by binding to an LDAP directory with their credentials.
+// Users signing in are provisioned by the Provisioner, which syncs their memberships with their groups in the directory.
+// The passwords of users who are not in the directory are handled by the Fallback service, if any.
+type PasswordsService struct {
+	Config
+	Logger *zap.Logger
+
+	Provisioner         *identity.Provisioner
+	UserIdentityService platform.UserIdentityService
+
+	Fallback platform.PasswordsService
+}
+
+// NewPasswordsService returns a PasswordsService authenticating users against the directory of c.
+func NewPasswordsService(c Config, p *identity.Provisioner, fallback platform.PasswordsService) *PasswordsService {
+	return &PasswordsService{
+		Config:              c,
+		Logger:              zap.NewNop(),
+		Provisioner:         p,
+		UserIdentityService: p.UserIdentityService,
+		Fallback:            fallback,
+	}
+}
+
+// ComparePassword binds to the directory as the user named name with password,
+// and provisions the user with the groups it belongs to if it succeeds.
+// If there is no such user in the directory, the password is compared by the Fallback service.
+// While the directory is unavailable, every user is refused, unless FallbackWhenUnavailable is set,
+// in which case only the users who signed in with it before are refused.
+func (s *PasswordsService) ComparePassword(ctx context.Context, name string, password string) error {
+	op := "ldap/ComparePassword"
+
+	_, err := s.UserIdentityService.FindUserIdentity(ctx, Provider, name)
+	if err != nil && platform.ErrorCode(err) != platform.ENotFound {
+		return &platform.Error{Op: op, Err: err}
+	}
+	linked := err == nil
+
+	// fallback compares the password of a user who is not linked to the directory
+	// with the Fallback service if the directory is unavailable and FallbackWhenUnavailable is set,
+	// or returns err.
+	fallback := func(err error) error {
+		if linked || !s.FallbackWhenUnavailable || s.Fallback == nil || platform.ErrorCode(err) != platform.EUnavailable {
+			return &platform.Error{Op: op, Err: err}
+		}
+		s.Logger.Info("Comparing the password of a local user while the directory is unavailable", zap.String("user", name), zap.Error(err))
+		return s.Fallback.ComparePassword(ctx, name, password)
+	}
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fallback(err)
+	}
+	defer conn.Close()
+
+	dn, err := s.findUserDN(conn, name)
+	if err != nil {
+		return fallback(err)
+	}
+	if dn == "" {
+		if s.Fallback != nil {
+			return s.Fallback.ComparePassword(ctx, name, password)
+		}
+		return ErrIncorrectPassword
+	}
+
+	// Servers may accept a bind with an empty password as an unauthenticated bind.
+	if password == "" {
+		return ErrIncorrectPassword
+	}
+	if err := conn.Bind(dn, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return ErrIncorrectPassword
+		}
+		return &platform.Error{Op: op, Err: unavailableError(err)}
+	}
+
+	var groups []string
+	if s.GroupBaseDN != "" {
+		if groups, err = s.findGroups(conn, dn); err != nil {
+			return &platform.Error{Op: op, Err: err}
+		}
+	}
+
+	if _, err := s.Provisioner.Provision(ctx, Provider, name, groups); err != nil {
+		s.Logger.Info("Failed to provision user of the directory", zap.String("user", name), zap.Error(err))
+		return &platform.Error{Op: op, Err: err}
+	}
+	return nil
+}
+
+// SetPassword sets the password of a user who is not in the directory with the Fallback service.
+func (s *PasswordsService) SetPassword(ctx context.Context, name string, password string) error {
+	if err := s.checkFallback(ctx, name); err != nil {
+		return &platform.Error{Op: "ldap/SetPassword", Err: err}
+	}
+	return s.Fallback.SetPassword(ctx, name, password)
+}
+
+// CompareAndSetPassword changes the password of a user who is not in the directory with the Fallback service.
+func (s *PasswordsService) CompareAndSetPassword(ctx context.Context, name string, old string, new string) error {
+	if err := s.checkFallback(ctx, name); err != nil {
+		return &platform.Error{Op: "ldap/CompareAndSetPassword", Err: err}
+	}
+	return s.Fallback.CompareAndSetPassword(ctx, name, old, new)
+}
+
+// checkFallback returns an error unless the password of the user named name is handled by the Fallback service.
+func (s *PasswordsService) checkFallback(ctx context.Context, name string) error {
+	_, err := s.UserIdentityService.FindUserIdentity(ctx, Provider, name)
+	if err == nil {
+		return ErrDirectoryPassword
+	}
+	if platform.ErrorCode(err) != platform.ENotFound {
+		return err
+	}
+
+	if s.Fallback == nil {
+		return ErrDirectoryPassword
+	}
+	return nil
+}
+
+// dial connects to the server, and binds with the credentials of the searches.
+func (s *PasswordsService) dial(ctx context.Context) (*goldap.Conn, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  "invalid LDAP URL",
+			Err:  err,
+		}
+	}
+
+	host, port := u.Hostname(), u.Port()
+	var isTLS bool
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		if port == "" {
+			port = "636"
+		}
+		isTLS = true
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  fmt.Sprintf("unsupported LDAP URL scheme %q", u.Scheme),
+		}
+	}
+
+	d := net.Dialer{Timeout: s.Timeout}
+	c, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, unavailableError(err)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: s.InsecureSkipVerify,
+	}
+	if isTLS {
+		c = tls.Client(c, tlsConfig)
+	}
+
+	conn := goldap.NewConn(c, isTLS)
+	conn.Start()
+	if s.Timeout > 0 {
+		conn.SetTimeout(s.Timeout)
+	}
+
+	if s.StartTLS && !isTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, unavailableError(err)
+		}
+	}
+
+	if err := s.bindSearch(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// bindSearch binds with the credentials of the searches.
+func (s *PasswordsService) bindSearch(conn *goldap.Conn) error {
+	if s.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(s.BindDN, s.BindPassword); err != nil {
+		return unavailableError(err)
+	}
+	return nil
+}
+
+// findUserDN returns the DN of the user named name, or an empty DN if there is no such user.
+func (s *PasswordsService) findUserDN(conn *goldap.Conn, name string) (string, error) {
+	res, err := conn.Search(goldap.NewSearchRequest(
+		s.UserBaseDN,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		2, 0, false,
+		fmt.Sprintf(s.UserFilter, goldap.EscapeFilter(name)),
+		[]string{"dn"},
+		nil,
+	))
+	switch {
+	case goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject):
+		return "", nil
+	case goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded):
+		res = &goldap.SearchResult{}
+	case err != nil:
+		return "", unavailableError(err)
+	}
+
+	if len(res.Entries) != 1 {
+		if len(res.Entries) > 1 {
+			// Authenticating as either user would be a guess.
+			s.Logger.Info("Several users of the directory match name", zap.String("user", name))
+		}
+		return "", nil
+	}
+	return res.Entries[0].DN, nil
+}
+
+// findGroups returns the names of the groups of the user with the given DN.
+// If BindDN is set, the groups are searched with its credentials again, as the user may not be allowed to search them.
+func (s *PasswordsService) findGroups(conn *goldap.Conn, dn string) ([]string, error) {
+	if err := s.bindSearch(conn); err != nil {
+		return nil, err
+	}
+
+	res, err := conn.Search(goldap.NewSearchRequest(
+		s.GroupBaseDN,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		0, 0, false,
+		fmt.Sprintf(s.GroupFilter, goldap.EscapeFilter(dn)),
+		[]string{s.GroupAttribute},
+		nil,
+	))
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultNoSuchObject) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, unavailableError(err)
+	}
+
+	groups := make([]string, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		if g := e.GetAttributeValue(s.GroupAttribute); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return groups, nil
+}
+
+func unavailableError(err error) *platform.Error {
+	return &platform.Error{
+		Code: platform.EUnavailable,
+		Msg:  "unable to reach the LDAP directory",
+		Err:  err,
+	}
+}
@@ -0,0 +1,268 @@
+package ldap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/identity"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/ldap"
+)
+
+const (
+	serviceDN = "cn=influxd,ou=services,dc=example,dc=com"
+	aliceDN   = "uid=alice,ou=people,dc=example,dc=com"
+	bobDN     = "uid=bob,ou=people,dc=example,dc=com"
+	adminsDN  = "cn=admins,ou=groups,dc=example,dc=com"
+	devsDN    = "cn=devs,ou=groups,dc=example,dc=com"
+)
+
+func newTestDirectory(t *testing.T) *directory {
+	return newDirectory(t, serviceDN,
+		entry{dn: serviceDN, attrs: map[string][]string{"cn": {"influxd"}, "userPassword": {"service-password"}}},
+		entry{dn: aliceDN, attrs: map[string][]string{"uid": {"alice"}, "userPassword": {"alice-password"}}},
+		entry{dn: bobDN, attrs: map[string][]string{"uid": {"bob"}, "userPassword": {"bob-password"}}},
+		entry{dn: adminsDN, attrs: map[string][]string{"cn": {"admins"}, "member": {aliceDN}}},
+		entry{dn: devsDN, attrs: map[string][]string{"cn": {"devs"}, "member": {aliceDN, bobDN}}},
+	)
+}
+
+func newTestPasswordsService(t *testing.T, url string) (*ldap.PasswordsService, *kv.Service) {
+	t.Helper()
+
+	svc := kv.NewService(inmem.NewKVStore())
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"acme", "initech"} {
+		if err := svc.PutOrganization(ctx, &platform.Organization{ID: platform.ID(i + 1), Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := identity.NewProvisioner(svc, svc, svc, svc)
+	p.GroupMappings = []platform.GroupMapping{
+		{Group: "admins", Org: "acme", UserType: platform.Owner},
+		{Group: "devs", Org: "initech", UserType: platform.Member},
+	}
+
+	return ldap.NewPasswordsService(ldap.Config{
+		URL:            url,
+		Timeout:        5 * time.Second,
+		BindDN:         serviceDN,
+		BindPassword:   "service-password",
+		UserBaseDN:     "ou=people,dc=example,dc=com",
+		UserFilter:     "(uid=%s)",
+		GroupBaseDN:    "ou=groups,dc=example,dc=com",
+		GroupFilter:    "(member=%s)",
+		GroupAttribute: "cn",
+	}, p, svc), svc
+}
+
+// memberships returns the roles of the user named name in the organizations, by organization name.
+func memberships(t *testing.T, svc *kv.Service, name string) map[string]platform.UserType {
+	t.Helper()
+
+	ctx := context.Background()
+	u, err := svc.FindUser(ctx, platform.UserFilter{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		ResourceType: platform.OrgsResourceType,
+		UserID:       u.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]platform.UserType{}
+	for _, m := range ms {
+		o, err := svc.FindOrganizationByID(ctx, m.ResourceID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[o.Name] = m.UserType
+	}
+	return got
+}
+
+func TestPasswordsService_ComparePassword(t *testing.T) {
+	d := newTestDirectory(t)
+	defer d.Close()
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		wantCode string
+	}{
+		{
+			name:     "correct password",
+			user:     "alice",
+			password: "alice-password",
+		},
+		{
+			name:     "incorrect password",
+			user:     "alice",
+			password: "bob-password",
+			wantCode: platform.EForbidden,
+		},
+		{
+			name:     "empty password",
+			user:     "alice",
+			password: "",
+			wantCode: platform.EForbidden,
+		},
+		{
+			name:     "unknown user",
+			user:     "mallory",
+			password: "alice-password",
+			wantCode: platform.EForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestPasswordsService(t, d.URL())
+			s.Fallback = nil
+
+			err := s.ComparePassword(context.Background(), tt.user, tt.password)
+			if code := platform.ErrorCode(err); code != tt.wantCode {
+				t.Fatalf("expected error code %q, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestPasswordsService_ComparePassword_SyncsGroups(t *testing.T) {
+	d := newTestDirectory(t)
+	defer d.Close()
+
+	s, svc := newTestPasswordsService(t, d.URL())
+	ctx := context.Background()
+
+	if err := s.ComparePassword(ctx, "alice", "alice-password"); err != nil {
+		t.Fatal(err)
+	}
+	if got := memberships(t, svc, "alice"); len(got) != 2 || got["acme"] != platform.Owner || got["initech"] != platform.Member {
+		t.Errorf("unexpected memberships of alice: %v", got)
+	}
+
+	if err := s.ComparePassword(ctx, "bob", "bob-password"); err != nil {
+		t.Fatal(err)
+	}
+	if got := memberships(t, svc, "bob"); len(got) != 1 || got["initech"] != platform.Member {
+		t.Errorf("unexpected memberships of bob: %v", got)
+	}
+
+	// Bob moves from devs to admins.
+	d.SetAttr(devsDN, "member", aliceDN)
+	d.SetAttr(adminsDN, "member", aliceDN, bobDN)
+
+	if err := s.ComparePassword(ctx, "bob", "bob-password"); err != nil {
+		t.Fatal(err)
+	}
+	if got := memberships(t, svc, "bob"); len(got) != 1 || got["acme"] != platform.Owner {
+		t.Errorf("expected memberships of bob to follow his groups, got %v", got)
+	}
+}
+
+func TestPasswordsService_ComparePassword_ExistingUserName(t *testing.T) {
+	d := newTestDirectory(t)
+	defer d.Close()
+
+	s, svc := newTestPasswordsService(t, d.URL())
+	ctx := context.Background()
+
+	if err := svc.CreateUser(ctx, &platform.User{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.ComparePassword(ctx, "alice", "alice-password")
+	if code := platform.ErrorCode(err); code != platform.EConflict {
+		t.Fatalf("expected a conflict signing in as an existing local user, got %v", err)
+	}
+}
+
+func TestPasswordsService_Fallback(t *testing.T) {
+	d := newTestDirectory(t)
+	defer d.Close()
+
+	s, svc := newTestPasswordsService(t, d.URL())
+	ctx := context.Background()
+
+	if err := svc.CreateUser(ctx, &platform.User{Name: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPassword(ctx, "admin", "local-password"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ComparePassword(ctx, "admin", "local-password"); err != nil {
+		t.Errorf("expected the password of a local user to be compared by the fallback, got %v", err)
+	}
+	if err := s.CompareAndSetPassword(ctx, "admin", "local-password", "new-local-password"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ComparePassword(ctx, "admin", "local-password"); err == nil {
+		t.Error("expected the previous password of a local user to be incorrect")
+	}
+
+	// Users of the directory can't set a local password once they signed in.
+	if err := s.ComparePassword(ctx, "alice", "alice-password"); err != nil {
+		t.Fatal(err)
+	}
+	err := s.SetPassword(ctx, "alice", "local-password")
+	if code := platform.ErrorCode(err); code != platform.EMethodNotAllowed {
+		t.Errorf("expected the password of a user of the directory not to be set, got %v", err)
+	}
+	err = s.CompareAndSetPassword(ctx, "alice", "alice-password", "local-password")
+	if code := platform.ErrorCode(err); code != platform.EMethodNotAllowed {
+		t.Errorf("expected the password of a user of the directory not to be changed, got %v", err)
+	}
+}
+
+func TestPasswordsService_Unavailable(t *testing.T) {
+	d := newTestDirectory(t)
+	defer d.Close()
+
+	s, svc := newTestPasswordsService(t, d.URL())
+	ctx := context.Background()
+
+	if err := svc.CreateUser(ctx, &platform.User{Name: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetPassword(ctx, "admin", "local-password"); err != nil {
+		t.Fatal(err)
+	}
+	// Alice is linked to the directory once she signed in with it.
+	if err := s.ComparePassword(ctx, "alice", "alice-password"); err != nil {
+		t.Fatal(err)
+	}
+
+	d.Close()
+
+	// Whether admin is a user of the directory who never signed in with it can't be told.
+	err := s.ComparePassword(ctx, "admin", "local-password")
+	if code := platform.ErrorCode(err); code != platform.EUnavailable {
+		t.Fatalf("expected the directory to be unavailable to users who never signed in with it, got %v", err)
+	}
+
+	s.FallbackWhenUnavailable = true
+	if err := s.ComparePassword(ctx, "admin", "local-password"); err != nil {
+		t.Errorf("expected a local user to sign in while the directory is unavailable, got %v", err)
+	}
+	if err := s.ComparePassword(ctx, "admin", "wrong-password"); err == nil {
+		t.Error("expected the password of a local user to still be compared while the directory is unavailable")
+	}
+
+	err = s.ComparePassword(ctx, "alice", "alice-password")
+	if code := platform.ErrorCode(err); code != platform.EUnavailable {
+		t.Fatalf("expected the directory to be unavailable to its users, got %v", err)
+	}
+}
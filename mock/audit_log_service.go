@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.AuditLogService = (*AuditLogService)(nil)
+
+// AuditLogService is a mock implementation of platform.AuditLogService.
+type AuditLogService struct {
+	CreateAuditEventFn func(ctx context.Context, e *platform.AuditEvent) error
+	FindAuditEventsFn  func(ctx context.Context, filter platform.AuditFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error)
+}
+
+// NewAuditLogService returns a mock AuditLogService where its methods
+// will return zero values.
+func NewAuditLogService() *AuditLogService {
+	return &AuditLogService{
+		CreateAuditEventFn: func(ctx context.Context, e *platform.AuditEvent) error {
+			return nil
+		},
+		FindAuditEventsFn: func(ctx context.Context, filter platform.AuditFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error) {
+			return nil, 0, nil
+		},
+	}
+}
+
+// CreateAuditEvent records an audit event.
+func (s *AuditLogService) CreateAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	return s.CreateAuditEventFn(ctx, e)
+}
+
+// FindAuditEvents returns the audit events that match filter.
+func (s *AuditLogService) FindAuditEvents(ctx context.Context, filter platform.AuditFilter, opt ...platform.FindOptions) ([]*platform.AuditEvent, int, error) {
+	return s.FindAuditEventsFn(ctx, filter, opt...)
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"go.uber.org/zap"
@@ -22,6 +23,8 @@ type AuthorizationService struct {
 	CreateAuthorizationFn      func(context.Context, *platform.Authorization) error
 	DeleteAuthorizationFn      func(context.Context, platform.ID) error
 	SetAuthorizationStatusFn   func(context.Context, platform.ID, platform.Status) error
+	RotateAuthorizationFn      func(context.Context, platform.ID, time.Duration) (*platform.Authorization, error)
+	SetAuthorizationLastUsedFn func(context.Context, platform.ID, time.Time) error
 }
 
 // NewAuthorizationService returns a mock AuthorizationService where its methods will return
@@ -36,6 +39,10 @@ func NewAuthorizationService() *AuthorizationService {
 		CreateAuthorizationFn:    func(context.Context, *platform.Authorization) error { return nil },
 		DeleteAuthorizationFn:    func(context.Context, platform.ID) error { return nil },
 		SetAuthorizationStatusFn: func(context.Context, platform.ID, platform.Status) error { return nil },
+		RotateAuthorizationFn: func(context.Context, platform.ID, time.Duration) (*platform.Authorization, error) {
+			return nil, nil
+		},
+		SetAuthorizationLastUsedFn: func(context.Context, platform.ID, time.Time) error { return nil },
 	}
 }
 
@@ -66,3 +73,13 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 func (s *AuthorizationService) SetAuthorizationStatus(ctx context.Context, id platform.ID, status platform.Status) error {
 	return s.SetAuthorizationStatusFn(ctx, id, status)
 }
+
+// RotateAuthorization replaces the token of an authorization with a new one.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	return s.RotateAuthorizationFn(ctx, id, gracePeriod)
+}
+
+// SetAuthorizationLastUsed records the time the authorization's token was last used at.
+func (s *AuthorizationService) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) error {
+	return s.SetAuthorizationLastUsedFn(ctx, id, t)
+}
@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.BackupService = (*BackupService)(nil)
+
+// BackupService is a mock implementation of platform.BackupService.
+type BackupService struct {
+	BackupFn            func(ctx context.Context, w io.Writer) error
+	IncrementalBackupFn func(ctx context.Context, since *platform.BackupManifest, w io.Writer) error
+}
+
+// NewBackupService returns a mock BackupService where its methods will return
+// zero values.
+func NewBackupService() *BackupService {
+	return &BackupService{
+		BackupFn: func(ctx context.Context, w io.Writer) error {
+			return fmt.Errorf("not implemented")
+		},
+		IncrementalBackupFn: func(ctx context.Context, since *platform.BackupManifest, w io.Writer) error {
+			return fmt.Errorf("not implemented")
+		},
+	}
+}
+
+// Backup writes a backup archive to w.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer) error {
+	return s.BackupFn(ctx, w)
+}
+
+// IncrementalBackup writes an incremental backup archive to w.
+func (s *BackupService) IncrementalBackup(ctx context.Context, since *platform.BackupManifest, w io.Writer) error {
+	return s.IncrementalBackupFn(ctx, since, w)
+}
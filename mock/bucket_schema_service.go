@@ -0,0 +1,66 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.BucketSchemaService = (*BucketSchemaService)(nil)
+
+// BucketSchemaService is a mock implementation of platform.BucketSchemaService.
+type BucketSchemaService struct {
+	FindMeasurementSchemaFn   func(ctx context.Context, bucketID platform.ID, name string) (*platform.MeasurementSchema, error)
+	FindMeasurementSchemasFn  func(ctx context.Context, bucketID platform.ID) ([]*platform.MeasurementSchema, error)
+	CreateMeasurementSchemaFn func(ctx context.Context, m *platform.MeasurementSchema) error
+	UpdateMeasurementSchemaFn func(ctx context.Context, bucketID platform.ID, name string, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error)
+	DeleteMeasurementSchemaFn func(ctx context.Context, bucketID platform.ID, name string) error
+}
+
+// NewBucketSchemaService returns a mock BucketSchemaService where its methods
+// will return zero values.
+func NewBucketSchemaService() *BucketSchemaService {
+	return &BucketSchemaService{
+		FindMeasurementSchemaFn: func(ctx context.Context, bucketID platform.ID, name string) (*platform.MeasurementSchema, error) {
+			return nil, fmt.Errorf("not implemented")
+		},
+		FindMeasurementSchemasFn: func(ctx context.Context, bucketID platform.ID) ([]*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+		CreateMeasurementSchemaFn: func(ctx context.Context, m *platform.MeasurementSchema) error {
+			return nil
+		},
+		UpdateMeasurementSchemaFn: func(ctx context.Context, bucketID platform.ID, name string, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+			return nil, fmt.Errorf("not implemented")
+		},
+		DeleteMeasurementSchemaFn: func(ctx context.Context, bucketID platform.ID, name string) error {
+			return nil
+		},
+	}
+}
+
+// FindMeasurementSchema returns the schema of a measurement in a bucket.
+func (s *BucketSchemaService) FindMeasurementSchema(ctx context.Context, bucketID platform.ID, name string) (*platform.MeasurementSchema, error) {
+	return s.FindMeasurementSchemaFn(ctx, bucketID, name)
+}
+
+// FindMeasurementSchemas returns the schemas of every measurement in a bucket.
+func (s *BucketSchemaService) FindMeasurementSchemas(ctx context.Context, bucketID platform.ID) ([]*platform.MeasurementSchema, error) {
+	return s.FindMeasurementSchemasFn(ctx, bucketID)
+}
+
+// CreateMeasurementSchema creates the schema of a measurement in a bucket.
+func (s *BucketSchemaService) CreateMeasurementSchema(ctx context.Context, m *platform.MeasurementSchema) error {
+	return s.CreateMeasurementSchemaFn(ctx, m)
+}
+
+// UpdateMeasurementSchema updates the schema of a measurement in a bucket.
+func (s *BucketSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID platform.ID, name string, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+	return s.UpdateMeasurementSchemaFn(ctx, bucketID, name, upd)
+}
+
+// DeleteMeasurementSchema removes the schema of a measurement from a bucket.
+func (s *BucketSchemaService) DeleteMeasurementSchema(ctx context.Context, bucketID platform.ID, name string) error {
+	return s.DeleteMeasurementSchemaFn(ctx, bucketID, name)
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.CardinalityService = (*CardinalityService)(nil)
+
+// CardinalityService is a mock implementation of platform.CardinalityService.
+type CardinalityService struct {
+	FindBucketCardinalityFn func(ctx context.Context, bucketID platform.ID, opts platform.CardinalityOptions) (*platform.BucketCardinality, error)
+}
+
+// NewCardinalityService returns a mock CardinalityService where its methods
+// will return zero values.
+func NewCardinalityService() *CardinalityService {
+	return &CardinalityService{
+		FindBucketCardinalityFn: func(ctx context.Context, bucketID platform.ID, opts platform.CardinalityOptions) (*platform.BucketCardinality, error) {
+			return nil, fmt.Errorf("not implemented")
+		},
+	}
+}
+
+// FindBucketCardinality returns the series cardinality of a bucket.
+func (s *CardinalityService) FindBucketCardinality(ctx context.Context, bucketID platform.ID, opts platform.CardinalityOptions) (*platform.BucketCardinality, error) {
+	return s.FindBucketCardinalityFn(ctx, bucketID, opts)
+}
@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.ExportService = (*ExportService)(nil)
+
+// ExportService is a mock implementation of platform.ExportService.
+type ExportService struct {
+	ExportBucketFn func(ctx context.Context, filter platform.ExportFilter, w io.Writer) error
+}
+
+// NewExportService returns a mock ExportService where its methods will return
+// zero values.
+func NewExportService() *ExportService {
+	return &ExportService{
+		ExportBucketFn: func(ctx context.Context, filter platform.ExportFilter, w io.Writer) error {
+			return fmt.Errorf("not implemented")
+		},
+	}
+}
+
+// ExportBucket writes the data of a bucket to w.
+func (s *ExportService) ExportBucket(ctx context.Context, filter platform.ExportFilter, w io.Writer) error {
+	return s.ExportBucketFn(ctx, filter, w)
+}
@@ -20,6 +20,15 @@ type TaskService struct {
 	CancelRunFn    func(context.Context, platform.ID, platform.ID) error
 	RetryRunFn     func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
 	ForceRunFn     func(context.Context, platform.ID, int64) (*platform.Run, error)
+
+	CreateBackfillFn func(context.Context, platform.ID, int64, int64) (*platform.Backfill, error)
+	FindBackfillsFn  func(context.Context, platform.ID) ([]*platform.Backfill, error)
+	UpdateBackfillFn func(context.Context, platform.ID, platform.ID, platform.BackfillUpdate) (*platform.Backfill, error)
+	CancelBackfillFn func(context.Context, platform.ID, platform.ID) error
+
+	FindTaskRevisionsFn func(context.Context, platform.ID) ([]*platform.TaskRevision, error)
+	FindTaskRevisionFn  func(context.Context, platform.ID, int64) (*platform.TaskRevision, error)
+	RollbackTaskFn      func(context.Context, platform.ID, int64) (*platform.Task, error)
 }
 
 func (s *TaskService) FindTaskByID(ctx context.Context, id platform.ID) (*platform.Task, error) {
@@ -65,3 +74,31 @@ func (s *TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID) (
 func (s *TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
 	return s.ForceRunFn(ctx, taskID, scheduledFor)
 }
+
+func (s *TaskService) CreateBackfill(ctx context.Context, taskID platform.ID, start, stop int64) (*platform.Backfill, error) {
+	return s.CreateBackfillFn(ctx, taskID, start, stop)
+}
+
+func (s *TaskService) FindBackfills(ctx context.Context, taskID platform.ID) ([]*platform.Backfill, error) {
+	return s.FindBackfillsFn(ctx, taskID)
+}
+
+func (s *TaskService) UpdateBackfill(ctx context.Context, taskID, backfillID platform.ID, upd platform.BackfillUpdate) (*platform.Backfill, error) {
+	return s.UpdateBackfillFn(ctx, taskID, backfillID, upd)
+}
+
+func (s *TaskService) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) error {
+	return s.CancelBackfillFn(ctx, taskID, backfillID)
+}
+
+func (s *TaskService) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*platform.TaskRevision, error) {
+	return s.FindTaskRevisionsFn(ctx, taskID)
+}
+
+func (s *TaskService) FindTaskRevision(ctx context.Context, taskID platform.ID, revision int64) (*platform.TaskRevision, error) {
+	return s.FindTaskRevisionFn(ctx, taskID, revision)
+}
+
+func (s *TaskService) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*platform.Task, error) {
+	return s.RollbackTaskFn(ctx, taskID, revision)
+}
@@ -342,19 +342,64 @@ func ValidPrecision(precision string) bool {
 	}
 }
 
+// ParseError describes a line of line protocol which could not be parsed.
+type ParseError struct {
+	Line int    // Line is the 1-based line number within the parsed buffer.
+	Text string // Text is the content of the line.
+	Err  error  // Err is the reason the line could not be parsed.
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("unable to parse '%s': %v", e.Text, e.Err)
+}
+
+// ParseErrors is returned by ParsePointsWithPrecision when one or more lines could
+// not be parsed. The points of the lines which were successfully parsed are still
+// returned.
+type ParseErrors []*ParseError
+
+// Error implements the error interface.
+func (e ParseErrors) Error() string {
+	failed := make([]string, 0, len(e))
+	for _, pe := range e {
+		failed = append(failed, pe.Error())
+	}
+	return strings.Join(failed, "\n")
+}
+
 // ParsePointsWithPrecision is similar to ParsePoints, but allows the
 // caller to provide a precision for time.
 //
+// If any of the lines fail to parse, a ParseErrors error is returned along with
+// the points of the lines which were parsed successfully.
+//
 // NOTE: to minimize heap allocations, the returned Points will refer to subslices of buf.
 // This can have the unintended effect preventing buf from being garbage collected.
 func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision string) ([]Point, error) {
+	return parsePointsWithPrecision(buf, defaultTime, precision, nil, nil)
+}
+
+// parsePointsWithPrecision parses the points in buf. If validate is not nil, it is
+// called with each point parsed, and any error returned rejects the point's line
+// in the same way as an error parsing it. If lines is not nil, the line number of
+// each point returned is appended to it.
+func parsePointsWithPrecision(buf []byte, defaultTime time.Time, precision string, validate func(Point) error, lines *[]int) ([]Point, error) {
 	points := make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
 	var (
 		pos    int
 		block  []byte
-		failed []string
+		failed ParseErrors
+
+		// line is the line number of the current block, and counted is the
+		// position in buf up to which newlines have been counted.
+		line    = 1
+		counted int
 	)
 	for pos < len(buf) {
+		line += bytes.Count(buf[counted:pos], []byte{'\n'})
+		counted = pos
+
 		pos, block = scanLine(buf, pos)
 		pos++
 
@@ -380,15 +425,25 @@ func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision strin
 		}
 
 		pt, err := parsePoint(block[start:], defaultTime, precision)
+		if err == nil && validate != nil {
+			err = validate(pt)
+		}
 		if err != nil {
-			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block[start:]), err))
+			failed = append(failed, &ParseError{
+				Line: line,
+				Text: string(block[start:]),
+				Err:  err,
+			})
 		} else {
 			points = append(points, pt)
+			if lines != nil {
+				*lines = append(*lines, line)
+			}
 		}
 
 	}
 	if len(failed) > 0 {
-		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
+		return points, failed
 	}
 	return points, nil
 
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"time"
+)
+
+// DefaultPointsReaderBatchSize is the default maximum number of lines
+// parsed by a PointsReader for each call to Next.
+const DefaultPointsReaderBatchSize = 5000
+
+// PointsReader parses line protocol from an io.Reader in batches, so that the
+// whole of the input does not need to be held in memory at once.
+type PointsReader struct {
+	r           *bufio.Reader
+	defaultTime time.Time
+	precision   string
+	batchSize   int
+	validate    func(Point) error
+
+	// line is the number of newlines consumed from r so far, and is used to
+	// report line numbers relative to the start of the input. lines holds the
+	// line numbers of the points returned by the last call to Next.
+	line  int
+	lines []int
+	err   error
+}
+
+// NewPointsReader returns a PointsReader parsing line protocol from r. Each batch
+// contains at most batchSize lines; if batchSize is not positive,
+// DefaultPointsReaderBatchSize is used. Points without a timestamp are given
+// defaultTime, and timestamps are interpreted using precision.
+func NewPointsReader(r io.Reader, defaultTime time.Time, precision string, batchSize int) *PointsReader {
+	if batchSize <= 0 {
+		batchSize = DefaultPointsReaderBatchSize
+	}
+	return &PointsReader{
+		r:           bufio.NewReader(r),
+		defaultTime: defaultTime,
+		precision:   precision,
+		batchSize:   batchSize,
+	}
+}
+
+// SetValidateFunc sets a function called with each point parsed. If it returns an
+// error, the point is not returned by Next and its line is rejected in the same
+// way as a line which could not be parsed.
+func (r *PointsReader) SetValidateFunc(fn func(Point) error) {
+	r.validate = fn
+}
+
+// Next parses and returns the points of the next batch of lines. It returns
+// io.EOF once the input is exhausted.
+//
+// If any of the lines in the batch fail to parse, a ParseErrors error is returned
+// along with the points of the lines which were parsed successfully. The line
+// numbers of the errors are relative to the start of the input. Any other error
+// is returned from the underlying reader, and the batch is discarded.
+//
+// Unlike ParsePointsWithPrecision, the returned points never share memory with
+// the points returned by a previous call to Next.
+func (r *PointsReader) Next() ([]Point, error) {
+	r.lines = r.lines[:0]
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var (
+		buf   []byte
+		start int // start of the current line within buf
+		n     int
+		line  = r.line
+	)
+	for n < r.batchSize {
+		b, err := r.r.ReadSlice('\n')
+		buf = append(buf, b...)
+		if err == bufio.ErrBufferFull {
+			continue
+		} else if err != nil && err != io.EOF {
+			r.err = err
+			return nil, err
+		}
+
+		if err == nil {
+			// A newline within a quoted string field value does not end the
+			// line, so keep reading until an unquoted newline is found.
+			if end, _ := scanLine(buf, start); end == len(buf) {
+				continue
+			}
+		}
+
+		r.line += bytes.Count(buf[start:], []byte{'\n'})
+		start = len(buf)
+		n++
+
+		if err == io.EOF {
+			r.err = io.EOF
+			break
+		}
+	}
+
+	if len(buf) == 0 {
+		return nil, r.err
+	}
+
+	points, err := parsePointsWithPrecision(buf, r.defaultTime, r.precision, r.validate, &r.lines)
+	for i := range r.lines {
+		r.lines[i] += line
+	}
+	if perrs, ok := err.(ParseErrors); ok {
+		for _, pe := range perrs {
+			pe.Line += line
+		}
+	}
+	return points, err
+}
+
+// Line returns the line number of the ith point returned by the last call to
+// Next, relative to the start of the input.
+func (r *PointsReader) Line(i int) int {
+	return r.lines[i]
+}
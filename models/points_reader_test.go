@@ -0,0 +1,124 @@
+package models_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+func TestPointsReader_Next(t *testing.T) {
+	batch := `cpu,host=serverA value=1.0 1
+cpu,host=serverA value= 2
+# a comment
+
+cpu,host=serverA value="multi
+line" 3
+cpu,host=serverA 4
+cpu,host=serverA value=5.0 5`
+
+	r := models.NewPointsReader(iotest.OneByteReader(strings.NewReader(batch)), time.Now().UTC(), "", 2)
+
+	var (
+		pts     []models.Point
+		lines   []int
+		perrs   models.ParseErrors
+		batches int
+	)
+	for {
+		p, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if pe, ok := err.(models.ParseErrors); ok {
+			perrs = append(perrs, pe...)
+		} else if err != nil {
+			t.Fatalf("PointsReader.Next() unexpected error: %v", err)
+		}
+		pts = append(pts, p...)
+		for i := range p {
+			lines = append(lines, r.Line(i))
+		}
+		batches++
+	}
+
+	if got, exp := batches, 4; got != exp {
+		t.Errorf("PointsReader.Next() batch count mismatch: got %v, exp %v", got, exp)
+	}
+
+	if got, exp := len(pts), 3; got != exp {
+		t.Fatalf("PointsReader.Next() len mismatch: got %v, exp %v", got, exp)
+	}
+	for i, exp := range []string{
+		`cpu,host=serverA value=1.0 1`,
+		"cpu,host=serverA value=\"multi\nline\" 3",
+		`cpu,host=serverA value=5.0 5`,
+	} {
+		if got := pts[i].String(); got != exp {
+			t.Errorf("PointsReader.Next() point %d mismatch: got %q, exp %q", i, got, exp)
+		}
+	}
+	for i, exp := range []int{1, 5, 8} {
+		if got := lines[i]; got != exp {
+			t.Errorf("PointsReader.Line(%d) mismatch: got %v, exp %v", i, got, exp)
+		}
+	}
+
+	if got, exp := len(perrs), 2; got != exp {
+		t.Fatalf("PointsReader.Next() error count mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := perrs[0].Line, 2; got != exp {
+		t.Errorf("PointsReader.Next() line mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := perrs[1].Line, 7; got != exp {
+		t.Errorf("PointsReader.Next() line mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := perrs[1].Text, "cpu,host=serverA 4"; got != exp {
+		t.Errorf("PointsReader.Next() text mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+func TestPointsReader_NextReadError(t *testing.T) {
+	r := models.NewPointsReader(iotest.TimeoutReader(strings.NewReader("cpu value=1 1\n")), time.Now().UTC(), "", 0)
+	if _, err := r.Next(); err != iotest.ErrTimeout {
+		t.Fatalf("PointsReader.Next() error mismatch: got %v, exp %v", err, iotest.ErrTimeout)
+	}
+	if _, err := r.Next(); err != iotest.ErrTimeout {
+		t.Fatalf("PointsReader.Next() error mismatch: got %v, exp %v", err, iotest.ErrTimeout)
+	}
+}
+
+func TestPointsReader_SetValidateFunc(t *testing.T) {
+	batch := "cpu value=1 1\nmem value=2 2\ncpu value=3 3"
+
+	r := models.NewPointsReader(strings.NewReader(batch), time.Now().UTC(), "", 0)
+	r.SetValidateFunc(func(p models.Point) error {
+		if string(p.Name()) != "cpu" {
+			return fmt.Errorf("unexpected measurement %q", p.Name())
+		}
+		return nil
+	})
+
+	pts, err := r.Next()
+	if got, exp := len(pts), 2; got != exp {
+		t.Fatalf("PointsReader.Next() len mismatch: got %v, exp %v", got, exp)
+	}
+
+	perrs, ok := err.(models.ParseErrors)
+	if !ok || len(perrs) != 1 {
+		t.Fatalf("PointsReader.Next() expected a single ParseError, got %v", err)
+	}
+	if got, exp := perrs[0].Line, 2; got != exp {
+		t.Errorf("PointsReader.Next() line mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := perrs[0].Err.Error(), `unexpected measurement "mem"`; got != exp {
+		t.Errorf("PointsReader.Next() error mismatch: got %v, exp %v", got, exp)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("PointsReader.Next() error mismatch: got %v, exp %v", err, io.EOF)
+	}
+}
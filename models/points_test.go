@@ -1930,6 +1930,45 @@ cpu,host=serverA,region=us-east value=1.0 946730096789012345`,
 	}
 }
 
+func TestParsePointsWithPrecisionParseErrors(t *testing.T) {
+	batch := `cpu,host=serverA value=1.0 1
+cpu,host=serverA value= 2
+# a comment
+
+cpu,host=serverA value="multi
+line" 3
+cpu,host=serverA 4
+cpu,host=serverA value=5.0 5`
+
+	pts, err := models.ParsePointsWithPrecision([]byte(batch), time.Now().UTC(), "")
+	if got, exp := len(pts), 3; got != exp {
+		t.Fatalf("ParsePointsWithPrecision() len mismatch: got %v, exp %v", got, exp)
+	}
+
+	perrs, ok := err.(models.ParseErrors)
+	if !ok {
+		t.Fatalf("ParsePointsWithPrecision() expected ParseErrors, got %T: %v", err, err)
+	}
+
+	if got, exp := len(perrs), 2; got != exp {
+		t.Fatalf("ParsePointsWithPrecision() error count mismatch: got %v, exp %v", got, exp)
+	}
+
+	if got, exp := perrs[0].Line, 2; got != exp {
+		t.Errorf("ParsePointsWithPrecision() line mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := perrs[0].Text, "cpu,host=serverA value= 2"; got != exp {
+		t.Errorf("ParsePointsWithPrecision() text mismatch: got %v, exp %v", got, exp)
+	}
+
+	if got, exp := perrs[1].Line, 7; got != exp {
+		t.Errorf("ParsePointsWithPrecision() line mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := perrs[1].Text, "cpu,host=serverA 4"; got != exp {
+		t.Errorf("ParsePointsWithPrecision() text mismatch: got %v, exp %v", got, exp)
+	}
+}
+
 func TestNewPointEscaped(t *testing.T) {
 	// commas
 	pt := models.MustNewPoint("cpu,main", models.NewTags(map[string]string{"tag,bar": "value"}), models.Fields{"name,bar": 1.0}, time.Unix(0, 0))
@@ -124,6 +124,34 @@ func (s *AuthorizationService) SetAuthorizationStatus(ctx context.Context, id pl
 	return s.AuthorizationService.SetAuthorizationStatus(ctx, id, status)
 }
 
+// RotateAuthorization replaces the token of an authorization with a new one, records function call latency, and counts function calls.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (a *platform.Authorization, err error) {
+	defer func(start time.Time) {
+		labels := prometheus.Labels{
+			"method": "RotateAuthorization",
+			"error":  fmt.Sprint(err != nil),
+		}
+		s.requestCount.With(labels).Add(1)
+		s.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	return s.AuthorizationService.RotateAuthorization(ctx, id, gracePeriod)
+}
+
+// SetAuthorizationLastUsed records the time an authorization's token was last used at, records function call latency, and counts function calls.
+func (s *AuthorizationService) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) (err error) {
+	defer func(start time.Time) {
+		labels := prometheus.Labels{
+			"method": "SetAuthorizationLastUsed",
+			"error":  fmt.Sprint(err != nil),
+		}
+		s.requestCount.With(labels).Add(1)
+		s.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	return s.AuthorizationService.SetAuthorizationLastUsed(ctx, id, t)
+}
+
 // PrometheusCollectors returns all authorization service prometheus collectors.
 func (s *AuthorizationService) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
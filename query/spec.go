@@ -28,3 +28,58 @@ func BucketsAccessed(q *flux.Spec) (readBuckets, writeBuckets []platform.BucketF
 
 	return readBuckets, writeBuckets, nil
 }
+
+// WithoutWrites returns a copy of q without the operations which write to buckets, such as to().
+// Those operations pass their input through, so their children are connected to their parents instead.
+// If q has no such operations, q itself is returned.
+func WithoutWrites(q *flux.Spec) *flux.Spec {
+	removed := make(map[flux.OperationID]bool)
+	for _, o := range q.Operations {
+		if bucketAwareOpSpec, ok := o.Spec.(BucketAwareOperationSpec); ok {
+			if _, writeBuckets := bucketAwareOpSpec.BucketsAccessed(); len(writeBuckets) > 0 {
+				removed[o.ID] = true
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return q
+	}
+
+	parents := make(map[flux.OperationID][]flux.OperationID)
+	for _, e := range q.Edges {
+		if removed[e.Child] {
+			parents[e.Child] = append(parents[e.Child], e.Parent)
+		}
+	}
+	// ancestors returns id, or the nearest ancestors of id which are kept if id is removed.
+	var ancestors func(id flux.OperationID) []flux.OperationID
+	ancestors = func(id flux.OperationID) []flux.OperationID {
+		if !removed[id] {
+			return []flux.OperationID{id}
+		}
+		var ids []flux.OperationID
+		for _, p := range parents[id] {
+			ids = append(ids, ancestors(p)...)
+		}
+		return ids
+	}
+
+	out := &flux.Spec{
+		Resources: q.Resources,
+		Now:       q.Now,
+	}
+	for _, o := range q.Operations {
+		if !removed[o.ID] {
+			out.Operations = append(out.Operations, o)
+		}
+	}
+	for _, e := range q.Edges {
+		if removed[e.Child] {
+			continue
+		}
+		for _, p := range ancestors(e.Parent) {
+			out.Edges = append(out.Edges, flux.Edge{Parent: p, Child: e.Child})
+		}
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+func TestWithoutWrites(t *testing.T) {
+	q := `from(bucket:"a") |> range(start:-1h) |> to(bucket:"b", org:"o") |> yield(name:"r")`
+	spec, err := flux.Compile(context.Background(), q, time.Now())
+	if err != nil {
+		t.Fatalf("Error compiling query: %v", err)
+	}
+
+	out := query.WithoutWrites(spec)
+	readBuckets, writeBuckets, err := query.BucketsAccessed(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readBuckets) != 1 || len(writeBuckets) != 0 {
+		t.Fatalf("expected only one read bucket, got %d read and %d written", len(readBuckets), len(writeBuckets))
+	}
+	if len(out.Operations) != len(spec.Operations)-1 {
+		t.Fatalf("expected one operation to be removed, got %d of %d", len(out.Operations), len(spec.Operations))
+	}
+
+	// The output of range is yielded directly.
+	kinds := make(map[flux.OperationID]flux.OperationKind)
+	for _, o := range out.Operations {
+		kinds[o.ID] = o.Spec.Kind()
+	}
+	found := false
+	for _, e := range out.Edges {
+		if kinds[e.Child] == "yield" {
+			found = true
+			if kinds[e.Parent] != "range" {
+				t.Fatalf("expected yield to follow range, got %q", kinds[e.Parent])
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected yield to be kept")
+	}
+
+	// A query without writes is left alone.
+	spec, err = flux.Compile(context.Background(), `from(bucket:"a") |> range(start:-1h)`, time.Now())
+	if err != nil {
+		t.Fatalf("Error compiling query: %v", err)
+	}
+	if query.WithoutWrites(spec) != spec {
+		t.Fatal("expected query without writes to be returned as is")
+	}
+}
@@ -0,0 +1,382 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/escape"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// Columns of the annotated CSV of an export.
+const (
+	csvResultColumn      = "result"
+	csvTableColumn       = "table"
+	csvStartColumn       = "_start"
+	csvStopColumn        = "_stop"
+	csvTimeColumn        = "_time"
+	csvValueColumn       = "_value"
+	csvFieldColumn       = "_field"
+	csvMeasurementColumn = "_measurement"
+
+	csvDefaultResult = "_result"
+)
+
+// Data types of the annotated CSV of an export.
+const (
+	csvDatatypeString   = "string"
+	csvDatatypeLong     = "long"
+	csvDatatypeUnsigned = "unsignedLong"
+	csvDatatypeDouble   = "double"
+	csvDatatypeBoolean  = "boolean"
+	csvDatatypeTime     = "dateTime:RFC3339"
+)
+
+// annotatedCSVWriter writes a table of Flux annotated CSV per series. The
+// annotations and the header are written again whenever the tag keys or the
+// data type of the values of a series differ from those of the previous series.
+type annotatedCSVWriter struct {
+	w     *csv.Writer
+	table int
+
+	// tagKeys and datatype are the schema of the last annotations written.
+	tagKeys  []string
+	datatype string
+	written  bool
+
+	record []string
+}
+
+func newAnnotatedCSVWriter(w io.Writer) *annotatedCSVWriter {
+	return &annotatedCSVWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *annotatedCSVWriter) WriteSeries(tags models.Tags, cur cursors.Cursor) error {
+	measurement, userTags, field := splitSeriesTags(tags)
+	datatype := cursorDatatype(cur)
+
+	empty := true
+	err := forEachValue(cur, func(ts int64, v interface{}) error {
+		if empty {
+			empty = false
+			if err := cw.writeSchema(userTags, datatype); err != nil {
+				return err
+			}
+			cw.record = cw.record[:0]
+			cw.record = append(cw.record, "", "", strconv.Itoa(cw.table), "", "", string(field), string(measurement))
+			for _, t := range userTags {
+				cw.record = append(cw.record, string(t.Value))
+			}
+		}
+
+		cw.record[3] = time.Unix(0, ts).UTC().Format(time.RFC3339Nano)
+		switch v := v.(type) {
+		case float64:
+			cw.record[4] = strconv.FormatFloat(v, 'f', -1, 64)
+		case int64:
+			cw.record[4] = strconv.FormatInt(v, 10)
+		case uint64:
+			cw.record[4] = strconv.FormatUint(v, 10)
+		case string:
+			cw.record[4] = v
+		case bool:
+			cw.record[4] = strconv.FormatBool(v)
+		}
+		return cw.w.Write(cw.record)
+	})
+	if err != nil {
+		return err
+	}
+	if !empty {
+		cw.table++
+	}
+	return nil
+}
+
+// writeSchema writes the annotations and the header of a table with the
+// provided tags and data type, unless they are those of the previous table.
+func (cw *annotatedCSVWriter) writeSchema(tags models.Tags, datatype string) error {
+	if cw.written && cw.datatype == datatype && len(cw.tagKeys) == len(tags) {
+		same := true
+		for i, t := range tags {
+			if cw.tagKeys[i] != string(t.Key) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return nil
+		}
+	}
+
+	if cw.written {
+		// Tables with different schemas are separated by an empty line.
+		if err := cw.w.Write(nil); err != nil {
+			return err
+		}
+	}
+	cw.written = true
+	cw.datatype = datatype
+	cw.tagKeys = cw.tagKeys[:0]
+	for _, t := range tags {
+		cw.tagKeys = append(cw.tagKeys, string(t.Key))
+	}
+
+	n := len(tags) + 7
+	types := make([]string, 0, n)
+	group := make([]string, 0, n)
+	defaults := make([]string, 0, n)
+	header := make([]string, 0, n)
+
+	types = append(types, "#datatype", csvDatatypeString, csvDatatypeLong, csvDatatypeTime, datatype, csvDatatypeString, csvDatatypeString)
+	group = append(group, "#group", "false", "false", "false", "false", "true", "true")
+	defaults = append(defaults, "#default", csvDefaultResult, "", "", "", "", "")
+	header = append(header, "", csvResultColumn, csvTableColumn, csvTimeColumn, csvValueColumn, csvFieldColumn, csvMeasurementColumn)
+	for _, k := range cw.tagKeys {
+		types = append(types, csvDatatypeString)
+		group = append(group, "true")
+		defaults = append(defaults, "")
+		header = append(header, k)
+	}
+
+	for _, r := range [][]string{types, group, defaults, header} {
+		if err := cw.w.Write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cw *annotatedCSVWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// cursorDatatype returns the annotated CSV data type of the values of cur.
+func cursorDatatype(cur cursors.Cursor) string {
+	switch cur.(type) {
+	case cursors.FloatArrayCursor:
+		return csvDatatypeDouble
+	case cursors.IntegerArrayCursor:
+		return csvDatatypeLong
+	case cursors.UnsignedArrayCursor:
+		return csvDatatypeUnsigned
+	case cursors.BooleanArrayCursor:
+		return csvDatatypeBoolean
+	default:
+		return csvDatatypeString
+	}
+}
+
+// NewAnnotatedCSVReader returns a reader of the line protocol of the Flux
+// annotated CSV read from r, such as the annotated CSV of an export or the
+// result of a Flux query.
+//
+// Each table must have the _measurement, _field, _value and _time columns.
+// The result, table, _start and _stop columns are ignored, and the other
+// columns are the tags of the points. Rows with an empty value are skipped.
+// The values are typed by the #datatype annotation of their table, and are
+// floats if a table has none.
+func NewAnnotatedCSVReader(r io.Reader) io.Reader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return &annotatedCSVReader{r: cr}
+}
+
+type annotatedCSVReader struct {
+	r   *csv.Reader
+	err error
+
+	// buf is the line protocol which has not been read yet.
+	buf bytes.Buffer
+
+	// records is the number of records read.
+	records int
+
+	// datatypes are the data types of the #datatype annotation of the
+	// current table, annotations is true if the last record was an
+	// annotation, and header is true if the next record is a header.
+	datatypes   []string
+	annotations bool
+	header      bool
+	columns     *csvColumns
+	line        []byte
+}
+
+// csvColumns are the indexes of the columns of a table.
+type csvColumns struct {
+	time, value, field, measurement int
+	datatype                        string
+	tags                            []int
+	tagKeys                         [][]byte
+}
+
+func (ar *annotatedCSVReader) Read(p []byte) (int, error) {
+	for ar.buf.Len() == 0 {
+		if ar.err != nil {
+			return 0, ar.err
+		}
+		ar.err = ar.readRecord()
+	}
+	return ar.buf.Read(p)
+}
+
+// readRecord reads a record of annotated CSV, and appends the line protocol of
+// its row, if any, to buf.
+func (ar *annotatedCSVReader) readRecord() error {
+	record, err := ar.r.Read()
+	if err != nil {
+		if pe, ok := err.(*csv.ParseError); ok {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid annotated CSV",
+				Err:  pe,
+			}
+		}
+		return err
+	}
+
+	ar.records++
+	n := ar.records
+
+	// Empty lines between tables are skipped by the CSV reader, so a table
+	// starts with its annotations.
+	if strings.HasPrefix(record[0], "#") {
+		if !ar.annotations {
+			ar.datatypes = ar.datatypes[:0]
+		}
+		if record[0] == "#datatype" {
+			ar.datatypes = append(ar.datatypes[:0], record...)
+		}
+		ar.annotations = true
+		ar.header = true
+		return nil
+	}
+	ar.annotations = false
+
+	if ar.header || ar.columns == nil {
+		ar.header = false
+		cols, err := ar.parseHeader(record)
+		if err != nil {
+			return csvError(n, err)
+		}
+		ar.columns = cols
+		return nil
+	}
+
+	cols := ar.columns
+	if len(record) <= cols.maxIndex() {
+		return csvError(n, fmt.Errorf("expected %d columns, got %d", cols.maxIndex()+1, len(record)))
+	}
+	value := record[cols.value]
+	if value == "" {
+		return nil
+	}
+
+	b := ar.line[:0]
+	b = append(b, escape.Bytes([]byte(record[cols.measurement]))...)
+	for i, c := range cols.tags {
+		if record[c] == "" {
+			continue
+		}
+		b = append(b, ',')
+		b = append(b, escape.Bytes(cols.tagKeys[i])...)
+		b = append(b, '=')
+		b = append(b, escape.Bytes([]byte(record[c]))...)
+	}
+	b = append(b, ' ')
+	b = append(b, escape.Bytes([]byte(record[cols.field]))...)
+	b = append(b, '=')
+
+	switch cols.datatype {
+	case csvDatatypeLong:
+		b = append(b, value...)
+		b = append(b, 'i')
+	case csvDatatypeUnsigned:
+		b = append(b, value...)
+		b = append(b, 'u')
+	case csvDatatypeString:
+		b = append(b, '"')
+		b = append(b, models.EscapeStringField(value)...)
+		b = append(b, '"')
+	default:
+		b = append(b, value...)
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, record[cols.time])
+	if err != nil {
+		return csvError(n, fmt.Errorf("invalid time %q", record[cols.time]))
+	}
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, ts.UnixNano(), 10)
+	b = append(b, '\n')
+	ar.line = b
+
+	ar.buf.Write(b)
+	return nil
+}
+
+// parseHeader returns the columns of a table with the provided header.
+func (ar *annotatedCSVReader) parseHeader(header []string) (*csvColumns, error) {
+	cols := &csvColumns{time: -1, value: -1, field: -1, measurement: -1, datatype: csvDatatypeDouble}
+	for i, name := range header {
+		switch name {
+		case "", csvResultColumn, csvTableColumn, csvStartColumn, csvStopColumn:
+		case csvTimeColumn:
+			cols.time = i
+		case csvValueColumn:
+			cols.value = i
+			if i < len(ar.datatypes) && ar.datatypes[i] != "" {
+				cols.datatype = ar.datatypes[i]
+			}
+		case csvFieldColumn:
+			cols.field = i
+		case csvMeasurementColumn:
+			cols.measurement = i
+		default:
+			cols.tags = append(cols.tags, i)
+			cols.tagKeys = append(cols.tagKeys, []byte(name))
+		}
+	}
+
+	for _, c := range []struct {
+		name  string
+		index int
+	}{
+		{csvMeasurementColumn, cols.measurement},
+		{csvFieldColumn, cols.field},
+		{csvValueColumn, cols.value},
+		{csvTimeColumn, cols.time},
+	} {
+		if c.index < 0 {
+			return nil, fmt.Errorf("missing %s column", c.name)
+		}
+	}
+	return cols, nil
+}
+
+func (c *csvColumns) maxIndex() int {
+	max := c.time
+	for _, i := range append([]int{c.value, c.field, c.measurement}, c.tags...) {
+		if i > max {
+			max = i
+		}
+	}
+	return max
+}
+
+func csvError(record int, err error) error {
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("invalid annotated CSV in record %d", record),
+		Err:  err,
+	}
+}
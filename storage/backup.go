@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// CreateBackup creates hard links to the TSM and tombstone files and to the
+// closed WAL segments of the engine in a new directory, which must be removed
+// by the caller. The current WAL segment is closed first, so every write
+// acknowledged before the call is in the backup.
+//
+// If snapshotCache is true, the cache of the engine is written to a new TSM
+// file first, which leaves few WAL segments in the backup.
+func (e *Engine) CreateBackup(ctx context.Context, snapshotCache bool) (string, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Engine.CreateBackup")
+	defer span.Finish()
+
+	// The engine lock is not held, as writing the snapshot of the cache and
+	// acquiring the WAL segments acquire it.
+	e.mu.RLock()
+	closed := e.closing == nil
+	e.mu.RUnlock()
+	if closed {
+		return "", ErrEngineClosed
+	}
+
+	if snapshotCache {
+		if err := e.engine.WriteSnapshot(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	// The TSM files and the WAL segments are linked while the engine is
+	// locked, so that no WAL segment is written to a TSM file in between.
+	var dir string
+	if err := e.AcquireSegments(ctx, func(segs []string) error {
+		var err error
+		if dir, err = e.engine.FileStore.CreateSnapshot(ctx); err != nil {
+			return err
+		}
+		for _, seg := range segs {
+			if err := os.Link(seg, filepath.Join(dir, filepath.Base(seg))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+		return "", err
+	}
+	return dir, nil
+}
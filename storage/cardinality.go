@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/estimator"
+	"github.com/influxdata/influxdb/pkg/estimator/hll"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/opentracing/opentracing-go"
+)
+
+// fieldTagKey is the key used to report the field of a series as a tag key.
+const fieldTagKey = "_field"
+
+// BucketCardinality returns the series cardinality of a bucket broken down by
+// measurement, tag key and tag value.
+//
+// The number of series in the bucket is taken from the index statistics. The
+// breakdown is computed by reading the series of the bucket from the index, so
+// its cost grows with the number of series. If opts.Estimate is set, the memory
+// used for each tag key is bounded: the number of values is estimated using a
+// HyperLogLog sketch, and the values with the most series are approximated.
+func (e *Engine) BucketCardinality(ctx context.Context, orgID, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Engine.BucketCardinality")
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	if opts.TopN <= 0 {
+		opts.TopN = influxdb.DefaultCardinalityTopN
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := encoded[:]
+
+	bc := &influxdb.BucketCardinality{
+		BucketID:     bucketID,
+		Estimated:    opts.Estimate,
+		Series:       int64(e.index.MeasurementCardinalityStats()[string(name)]),
+		Measurements: []influxdb.MeasurementCardinality{},
+	}
+
+	itr, err := e.index.MeasurementSeriesIDIterator(name)
+	if err != nil {
+		return nil, err
+	} else if itr == nil {
+		return bc, nil
+	}
+	defer itr.Close()
+
+	measurements := make(map[string]*measurementCardinality)
+	for n := 0; ; n++ {
+		if n%1000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		elem, err := itr.Next()
+		if err != nil {
+			return nil, err
+		} else if elem.SeriesID.IsZero() {
+			break
+		}
+
+		key := e.sfile.SeriesKey(elem.SeriesID)
+		if len(key) == 0 {
+			continue
+		}
+		_, tags := tsdb.ParseSeriesKey(key)
+
+		m := string(tags.Get(tsdb.MeasurementTagKeyBytes))
+		mc := measurements[m]
+		if mc == nil {
+			mc = &measurementCardinality{tagKeys: make(map[string]tagValueCounter)}
+			measurements[m] = mc
+		}
+		mc.series++
+
+		for _, t := range tags {
+			if bytes.Equal(t.Key, tsdb.MeasurementTagKeyBytes) {
+				continue
+			}
+
+			k := string(t.Key)
+			if bytes.Equal(t.Key, tsdb.FieldKeyTagKeyBytes) {
+				k = fieldTagKey
+			}
+
+			c := mc.tagKeys[k]
+			if c == nil {
+				c = newTagValueCounter(opts)
+				mc.tagKeys[k] = c
+			}
+			c.Add(t.Value)
+		}
+	}
+
+	for m, mc := range measurements {
+		bc.Measurements = append(bc.Measurements, mc.cardinality(m, opts.TopN))
+	}
+	sort.Slice(bc.Measurements, func(i, j int) bool {
+		a, b := bc.Measurements[i], bc.Measurements[j]
+		if a.Series != b.Series {
+			return a.Series > b.Series
+		}
+		return a.Name < b.Name
+	})
+
+	return bc, nil
+}
+
+type measurementCardinality struct {
+	series  int64
+	tagKeys map[string]tagValueCounter
+}
+
+func (mc *measurementCardinality) cardinality(name string, topN int) influxdb.MeasurementCardinality {
+	m := influxdb.MeasurementCardinality{
+		Name:    name,
+		Series:  mc.series,
+		TagKeys: make([]influxdb.TagKeyCardinality, 0, len(mc.tagKeys)),
+	}
+	for k, c := range mc.tagKeys {
+		m.TagKeys = append(m.TagKeys, influxdb.TagKeyCardinality{
+			Key:       k,
+			Values:    c.Count(),
+			TopValues: c.Top(topN),
+		})
+	}
+	sort.Slice(m.TagKeys, func(i, j int) bool {
+		a, b := m.TagKeys[i], m.TagKeys[j]
+		if a.Values != b.Values {
+			return a.Values > b.Values
+		}
+		return a.Key < b.Key
+	})
+	return m
+}
+
+// tagValueCounter counts the values of a tag key, and the number of series with
+// each of them.
+type tagValueCounter interface {
+	// Add counts a series with the tag value v.
+	Add(v []byte)
+	// Count returns the number of distinct values.
+	Count() int64
+	// Top returns the n values with the most series.
+	Top(n int) []influxdb.TagValueCardinality
+}
+
+func newTagValueCounter(opts influxdb.CardinalityOptions) tagValueCounter {
+	if opts.Estimate {
+		return newEstimatedTagValueCounter(opts.TopN)
+	}
+	return exactTagValueCounter{}
+}
+
+// exactTagValueCounter counts the series of every tag value.
+type exactTagValueCounter map[string]int64
+
+func (c exactTagValueCounter) Add(v []byte) { c[string(v)]++ }
+func (c exactTagValueCounter) Count() int64 { return int64(len(c)) }
+
+func (c exactTagValueCounter) Top(n int) []influxdb.TagValueCardinality {
+	return topTagValues(c, n)
+}
+
+// estimatedTagValueCounter estimates the number of tag values using a HyperLogLog
+// sketch, and finds the values with the most series using the space-saving
+// algorithm, which tracks a fixed number of values. The series counts of the top
+// values are upper bounds.
+type estimatedTagValueCounter struct {
+	sketch   estimator.Sketch
+	counts   map[string]int64
+	capacity int
+}
+
+// estimatedTopNFactor is the number of values tracked by an estimatedTagValueCounter
+// for each top value reported, which improves the accuracy of the top values.
+const estimatedTopNFactor = 10
+
+func newEstimatedTagValueCounter(topN int) *estimatedTagValueCounter {
+	return &estimatedTagValueCounter{
+		sketch:   hll.NewDefaultPlus(),
+		counts:   make(map[string]int64),
+		capacity: topN * estimatedTopNFactor,
+	}
+}
+
+func (c *estimatedTagValueCounter) Add(v []byte) {
+	c.sketch.Add(v)
+
+	if _, ok := c.counts[string(v)]; ok || len(c.counts) < c.capacity {
+		c.counts[string(v)]++
+		return
+	}
+
+	// Replace the value with the fewest series, assuming the new value could
+	// have had as many series.
+	var (
+		minValue string
+		minCount int64 = -1
+	)
+	for k, n := range c.counts {
+		if minCount < 0 || n < minCount {
+			minValue, minCount = k, n
+		}
+	}
+	delete(c.counts, minValue)
+	c.counts[string(v)] = minCount + 1
+}
+
+func (c *estimatedTagValueCounter) Count() int64 { return int64(c.sketch.Count()) }
+
+func (c *estimatedTagValueCounter) Top(n int) []influxdb.TagValueCardinality {
+	return topTagValues(c.counts, n)
+}
+
+// topTagValues returns the n values with the most series in counts.
+func topTagValues(counts map[string]int64, n int) []influxdb.TagValueCardinality {
+	values := make([]influxdb.TagValueCardinality, 0, len(counts))
+	for v, n := range counts {
+		values = append(values, influxdb.TagValueCardinality{Value: v, Series: n})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Series != values[j].Series {
+			return values[i].Series > values[j].Series
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > n {
+		values = values[:n]
+	}
+	return values
+}
+
+// A BucketCardinalityFinder computes the series cardinality of a bucket.
+type BucketCardinalityFinder interface {
+	BucketCardinality(ctx context.Context, orgID, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error)
+}
+
+// CardinalityService implements influxdb.CardinalityService for a storage engine,
+// using a bucket service to find the organization of a bucket.
+type CardinalityService struct {
+	buckets influxdb.BucketService
+	engine  BucketCardinalityFinder
+}
+
+var _ influxdb.CardinalityService = (*CardinalityService)(nil)
+
+// NewCardinalityService returns a new CardinalityService for the provided
+// BucketCardinalityFinder, which typically will be an Engine.
+func NewCardinalityService(buckets influxdb.BucketService, engine BucketCardinalityFinder) *CardinalityService {
+	return &CardinalityService{
+		buckets: buckets,
+		engine:  engine,
+	}
+}
+
+// FindBucketCardinality returns the series cardinality of a bucket.
+func (s *CardinalityService) FindBucketCardinality(ctx context.Context, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	b, err := s.buckets.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindBucketCardinality,
+			Err: err,
+		}
+	}
+
+	bc, err := s.engine.BucketCardinality(ctx, b.OrganizationID, b.ID, opts)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpFindBucketCardinality,
+			Err:  err,
+		}
+	}
+	return bc, nil
+}
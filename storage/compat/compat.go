@@ -28,6 +28,8 @@ type Config struct {
 	CompactThroughputBurst         toml.Size     `toml:"compact-throughput-burst"`
 	MaxConcurrentCompactions       int           `toml:"max-concurrent-compactions"`
 	TSMWillNeed                    bool          `toml:"tsm-use-madv-willneed"`
+	MaxSeriesPerDatabase           int           `toml:"max-series-per-database"`
+	MaxValuesPerTag                int           `toml:"max-values-per-tag"`
 }
 
 // NewConfig constructs an old Config struct with appropriate defaults for a new Config.
@@ -43,6 +45,8 @@ func NewConfig() Config {
 		CompactThroughputBurst:         toml.Size(tsm1.DefaultCompactThroughputBurst),
 		MaxConcurrentCompactions:       tsm1.DefaultCompactMaxConcurrent,
 		TSMWillNeed:                    tsm1.DefaultMADVWillNeed,
+		MaxSeriesPerDatabase:           storage.DefaultMaxSeriesPerBucket,
+		MaxValuesPerTag:                storage.DefaultMaxValuesPerTag,
 	}
 }
 
@@ -61,5 +65,7 @@ func Convert(oldConfig Config) (string, storage.Config) {
 	newConfig.Engine.Compaction.MaxConcurrent = oldConfig.MaxConcurrentCompactions
 	newConfig.WALPath = oldConfig.WALDir
 	newConfig.WAL.FsyncDelay = oldConfig.WALFsyncDelay
+	newConfig.MaxSeriesPerBucket = oldConfig.MaxSeriesPerDatabase
+	newConfig.MaxValuesPerTag = oldConfig.MaxValuesPerTag
 	return oldConfig.Dir, newConfig
 }
@@ -13,6 +13,10 @@ const (
 	DefaultRetentionInterval = 1 * time.Hour
 	DefaultValidateKeys      = false
 
+	DefaultMaxSeriesPerOrg    = 0
+	DefaultMaxSeriesPerBucket = 0
+	DefaultMaxValuesPerTag    = 0
+
 	DefaultSeriesFileDirectoryName = "_series"
 	DefaultIndexDirectoryName      = "index"
 	DefaultWALDirectoryName        = "wal"
@@ -27,6 +31,16 @@ type Config struct {
 	// Enables unicode validation on series keys on write.
 	ValidateKeys bool `toml:"validate-keys"`
 
+	// Limits on the number of series which can be created. Writes of new series
+	// which would exceed a limit are dropped. A limit of 0 disables it.
+	MaxSeriesPerOrg    int `toml:"max-series-per-org"`
+	MaxSeriesPerBucket int `toml:"max-series-per-bucket"`
+
+	// Limit on the number of distinct values of each tag key within a bucket.
+	// Writes of new series which would exceed it are dropped. A limit of 0
+	// disables it.
+	MaxValuesPerTag int `toml:"max-values-per-tag"`
+
 	// Series file config.
 	SeriesFilePath string `toml:"series-file-path"` // Overrides the default path.
 
@@ -49,6 +63,10 @@ func NewConfig() Config {
 		RetentionInterval: toml.Duration(DefaultRetentionInterval),
 		ValidateKeys:      DefaultValidateKeys,
 
+		MaxSeriesPerOrg:    DefaultMaxSeriesPerOrg,
+		MaxSeriesPerBucket: DefaultMaxSeriesPerBucket,
+		MaxValuesPerTag:    DefaultMaxValuesPerTag,
+
 		WAL:    tsm1.NewWALConfig(),
 		Engine: tsm1.NewConfig(),
 		Index:  tsi1.NewConfig(),
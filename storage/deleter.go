@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxql"
+)
+
+// Deleter describes the ability to delete data matching a predicate from a
+// storage engine.
+type Deleter interface {
+	DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxql.Expr) error
+}
@@ -45,6 +45,7 @@ type Engine struct {
 	retentionEnforcer *retentionEnforcer
 
 	defaultMetricLabels prometheus.Labels
+	writerMetrics       *writerMetrics
 
 	// Tracks all goroutines started by the Engine.
 	wg sync.WaitGroup
@@ -136,6 +137,7 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	}
 
 	// Set default metrics labels.
+	e.writerMetrics = newWriterMetrics(e.defaultMetricLabels)
 	e.engine.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.sfile.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.index.SetDefaultMetricLabels(e.defaultMetricLabels)
@@ -166,6 +168,13 @@ func (e *Engine) WithLogger(log *zap.Logger) {
 	e.retentionEnforcer.WithLogger(e.logger)
 }
 
+// WithDownsampleWatermarker sets the watermarker used by the retention enforcer
+// to keep the raw data of buckets with downsample rules until it has been
+// rolled up. It may be called after the engine is opened.
+func (e *Engine) WithDownsampleWatermarker(w DownsampleWatermarker) {
+	e.retentionEnforcer.WithDownsampleWatermarker(w)
+}
+
 // PrometheusCollectors returns all the prometheus collectors associated with
 // the engine and its components.
 func (e *Engine) PrometheusCollectors() []prometheus.Collector {
@@ -175,6 +184,7 @@ func (e *Engine) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, tsm1.PrometheusCollectors()...)
 	metrics = append(metrics, wal.PrometheusCollectors()...)
 	metrics = append(metrics, e.retentionEnforcer.PrometheusCollectors()...)
+	metrics = append(metrics, e.writerMetrics.PrometheusCollectors()...)
 	return metrics
 }
 
@@ -250,6 +260,13 @@ func (e *Engine) replayWAL() error {
 
 		case *wal.DeleteBucketRangeWALEntry:
 			return e.deleteBucketRangeLocked(en.OrgID, en.BucketID, en.Min, en.Max)
+
+		case *wal.DeleteBucketRangePredicateWALEntry:
+			pred, err := influxql.ParseExpr(string(en.Predicate))
+			if err != nil {
+				return err
+			}
+			return e.deleteBucketRangePredicateLocked(en.OrgID, en.BucketID, en.Min, en.Max, pred)
 		}
 
 		return nil
@@ -356,7 +373,9 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 //
 // The Engine expects all points to have been correctly validated by the caller.
 // WritePoints will however determine if there are any field type conflicts, and
-// return an appropriate error in that case.
+// return an appropriate error in that case. Points which would create a series
+// exceeding the series cardinality limits of the engine are dropped, and reported
+// by returning a tsdb.PartialWriteError.
 func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 	collection, j := tsdb.NewSeriesCollection(points), 0
 	for iter := collection.Iterator(); iter.Next(); {
@@ -404,6 +423,12 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return ErrEngineClosed
 	}
 
+	// Drop any new series which would exceed the series cardinality limits.
+	// Concurrent writes may exceed the limits by a small number of series.
+	if err := e.limitSeries(collection); err != nil {
+		return err
+	}
+
 	// Convert the points to values for adding to the WAL/Cache.
 	values, err := tsm1.PointsToValues(collection.Points)
 	if err != nil {
@@ -513,6 +538,44 @@ func (e *Engine) deleteBucketRangeLocked(orgID, bucketID platform.ID, min, max i
 	return e.engine.DeleteBucketRange(name, min, max)
 }
 
+// DeleteBucketRangePredicate deletes data within a bucket from the storage engine
+// for the series matching the predicate and with timestamps between min and max.
+// Series that no longer contain any data are removed from the index and series file.
+//
+// The predicate is evaluated against the tsdb representation of series, so the
+// measurement and field are referenced by the tsdb.MeasurementTagKey and
+// tsdb.FieldKeyTagKey tag keys. A nil predicate deletes the entire range.
+func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, "Engine.DeleteBucketRangePredicate")
+	defer span.Finish()
+
+	if pred == nil {
+		return e.DeleteBucketRange(orgID, bucketID, min, max)
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
+	if _, err := e.wal.DeleteBucketRangePredicate(orgID, bucketID, min, max, []byte(pred.String())); err != nil {
+		return err
+	}
+
+	return e.deleteBucketRangePredicateLocked(orgID, bucketID, min, max, pred)
+}
+
+// deleteBucketRangePredicateLocked does the work of deleting a bucket range for the
+// series matching pred and must be called under some sort of lock.
+func (e *Engine) deleteBucketRangePredicateLocked(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error {
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := models.EscapeMeasurement(encoded[:])
+
+	return e.engine.DeleteBucketRangePredicate(name, min, max, pred)
+}
+
 // SeriesCardinality returns the number of series in the engine.
 func (e *Engine) SeriesCardinality() int64 {
 	e.mu.RLock()
@@ -1,11 +1,15 @@
 package storage_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxql"
 )
 
 func TestEngine_WriteAndIndex(t *testing.T) {
@@ -152,6 +157,334 @@ func TestEngine_WriteAddNewField(t *testing.T) {
 	}
 }
 
+func TestEngine_SeriesLimits(t *testing.T) {
+	const (
+		org     = "3131313131313131"
+		bucket1 = "3232323232323232"
+		bucket2 = "3333333333333333"
+	)
+
+	point := func(host, field string) models.Point {
+		return models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": host}),
+			map[string]interface{}{field: 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	type write struct {
+		bucket  string
+		points  []models.Point
+		dropped int // number of series expected to be dropped
+	}
+
+	tests := []struct {
+		name   string
+		config func(*storage.Config)
+		writes []write
+		series int64
+	}{
+		{
+			name:   "max series per bucket",
+			config: func(c *storage.Config) { c.MaxSeriesPerBucket = 2 },
+			writes: []write{
+				{bucket: bucket1, points: []models.Point{point("a", "v"), point("b", "v"), point("a", "v"), point("c", "v")}, dropped: 1},
+				{bucket: bucket1, points: []models.Point{point("a", "v"), point("d", "v")}, dropped: 1},
+				{bucket: bucket2, points: []models.Point{point("a", "v"), point("b", "v")}},
+			},
+			series: 4,
+		},
+		{
+			name:   "max series per org",
+			config: func(c *storage.Config) { c.MaxSeriesPerOrg = 3 },
+			writes: []write{
+				{bucket: bucket1, points: []models.Point{point("a", "v"), point("b", "v")}},
+				{bucket: bucket2, points: []models.Point{point("a", "v"), point("b", "v"), point("c", "v")}, dropped: 2},
+			},
+			series: 3,
+		},
+		{
+			name:   "max values per tag",
+			config: func(c *storage.Config) { c.MaxValuesPerTag = 2 },
+			writes: []write{
+				{bucket: bucket1, points: []models.Point{point("a", "v"), point("b", "v"), point("c", "v")}, dropped: 1},
+				{bucket: bucket1, points: []models.Point{point("a", "v2"), point("c", "v2")}, dropped: 1},
+				{bucket: bucket2, points: []models.Point{point("c", "v")}},
+			},
+			series: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := storage.NewConfig()
+			tt.config(&config)
+
+			engine := NewEngine(config)
+			defer engine.Close()
+			engine.MustOpen()
+
+			for i, w := range tt.writes {
+				err := engine.Write1xPointsWithOrgBucket(w.points, org, w.bucket)
+				if w.dropped == 0 {
+					if err != nil {
+						t.Fatalf("write %d: unexpected error: %v", i, err)
+					}
+					continue
+				}
+
+				pwe, ok := err.(tsdb.PartialWriteError)
+				if !ok {
+					t.Fatalf("write %d: got error %v, exp partial write error", i, err)
+				}
+				if got, exp := pwe.Dropped, w.dropped; got != exp {
+					t.Fatalf("write %d: got %d dropped series, exp %d", i, got, exp)
+				}
+			}
+
+			if got, exp := engine.SeriesCardinality(), tt.series; got != exp {
+				t.Fatalf("got %d series, exp %d series in index", got, exp)
+			}
+		})
+	}
+}
+
+func TestEngine_BucketCardinality(t *testing.T) {
+	const (
+		org    = "3131313131313131"
+		bucket = "3232323232323232"
+	)
+
+	point := func(name, host, field string) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{"host": host}),
+			map[string]interface{}{field: 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pts := []models.Point{
+		point("cpu", "a", "v"),
+		point("cpu", "b", "v"),
+		point("cpu", "b", "w"),
+		point("mem", "a", "v"),
+	}
+	if err := engine.Write1xPointsWithOrgBucket(pts, org, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	orgID, _ := influxdb.IDFromString(org)
+	bucketID, _ := influxdb.IDFromString(bucket)
+
+	for _, estimate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("estimate=%t", estimate), func(t *testing.T) {
+			opts := influxdb.CardinalityOptions{TopN: 1, Estimate: estimate}
+			got, err := engine.BucketCardinality(context.Background(), *orgID, *bucketID, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exp := &influxdb.BucketCardinality{
+				BucketID:  *bucketID,
+				Estimated: estimate,
+				Series:    4,
+				Measurements: []influxdb.MeasurementCardinality{
+					{
+						Name:   "cpu",
+						Series: 3,
+						TagKeys: []influxdb.TagKeyCardinality{
+							{Key: "_field", Values: 2, TopValues: []influxdb.TagValueCardinality{{Value: "v", Series: 2}}},
+							{Key: "host", Values: 2, TopValues: []influxdb.TagValueCardinality{{Value: "b", Series: 2}}},
+						},
+					},
+					{
+						Name:   "mem",
+						Series: 1,
+						TagKeys: []influxdb.TagKeyCardinality{
+							{Key: "_field", Values: 1, TopValues: []influxdb.TagValueCardinality{{Value: "v", Series: 1}}},
+							{Key: "host", Values: 1, TopValues: []influxdb.TagValueCardinality{{Value: "a", Series: 1}}},
+						},
+					},
+				},
+			}
+			if !reflect.DeepEqual(got, exp) {
+				t.Fatalf("got cardinality %+v, exp %+v", got, exp)
+			}
+		})
+	}
+}
+
+func TestEngine_CreateBackup(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pt := models.MustNewPoint("cpu", models.Tags{}, map[string]interface{}{"value": 1.0}, time.Unix(1, 2))
+	if err := engine.Write1xPoints([]models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	// exts returns the number of files in a backup by extension.
+	exts := func(snapshotCache bool) map[string]int {
+		t.Helper()
+		dir, err := engine.CreateBackup(context.Background(), snapshotCache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		fis, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]int)
+		for _, fi := range fis {
+			got[filepath.Ext(fi.Name())]++
+		}
+		return got
+	}
+
+	// Without a snapshot of the cache, the write is only in the WAL.
+	if got, exp := exts(false), map[string]int{".wal": 1}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got files %v, exp %v", got, exp)
+	}
+
+	// With a snapshot of the cache, the write is in a TSM file.
+	if got, exp := exts(true), map[string]int{".tsm": 1}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got files %v, exp %v", got, exp)
+	}
+}
+
+func TestEngine_ExportBucket(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pts, err := models.ParsePointsString(`cpu,host=a f=1 1000000000
+cpu,host=a f=2 2000000000
+cpu,host=b i=3i 1000000000
+mem s="x \"y\"" 1000000000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Write1xPoints(pts); err != nil {
+		t.Fatal(err)
+	}
+
+	export := func(filter influxdb.ExportFilter) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := engine.ExportBucket(context.Background(), engine.org, engine.bucket, filter, &buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	tests := []struct {
+		name   string
+		filter influxdb.ExportFilter
+		exp    string
+	}{
+		{
+			name: "all points",
+			exp: `cpu,host=a f=1 1000000000
+cpu,host=a f=2 2000000000
+cpu,host=b i=3i 1000000000
+mem s="x \"y\"" 1000000000
+`,
+		},
+		{
+			name: "stop is exclusive",
+			filter: influxdb.ExportFilter{
+				Stop: time.Unix(2, 0),
+			},
+			exp: `cpu,host=a f=1 1000000000
+cpu,host=b i=3i 1000000000
+mem s="x \"y\"" 1000000000
+`,
+		},
+		{
+			name: "resume after a point",
+			filter: influxdb.ExportFilter{
+				After: &influxdb.ExportPosition{Key: "cpu,host=a", Field: "f", Time: time.Unix(1, 0)},
+			},
+			exp: `cpu,host=a f=2 2000000000
+cpu,host=b i=3i 1000000000
+mem s="x \"y\"" 1000000000
+`,
+		},
+		{
+			name: "resume after a series",
+			filter: influxdb.ExportFilter{
+				After: &influxdb.ExportPosition{Key: "cpu,host=b", Field: "i", Time: time.Unix(1, 0)},
+			},
+			exp: `mem s="x \"y\"" 1000000000
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.Format = influxdb.ExportFormatLineProtocol
+			if got := export(tt.filter); got != tt.exp {
+				t.Fatalf("got line protocol:\n%s\nexp:\n%s", got, tt.exp)
+			}
+
+			// The annotated CSV of an export is converted back to the same
+			// line protocol.
+			tt.filter.Format = influxdb.ExportFormatCSV
+			csv := export(tt.filter)
+			if !strings.HasPrefix(csv, "#datatype,string,long,dateTime:RFC3339,") {
+				t.Fatalf("got annotated CSV without annotations:\n%s", csv)
+			}
+			got, err := ioutil.ReadAll(storage.NewAnnotatedCSVReader(strings.NewReader(csv)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.exp {
+				t.Fatalf("got line protocol from annotated CSV:\n%s\nexp:\n%s\nCSV:\n%s", got, tt.exp, csv)
+			}
+		})
+	}
+}
+
+func TestAnnotatedCSVReader_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{
+			name: "missing field column",
+			csv: `#datatype,string,long,dateTime:RFC3339,double,string
+,result,table,_time,_value,_measurement
+,,0,1970-01-01T00:00:01Z,1,cpu
+`,
+		},
+		{
+			name: "invalid time",
+			csv: `#datatype,string,long,dateTime:RFC3339,double,string,string
+,result,table,_time,_value,_field,_measurement
+,,0,yesterday,1,f,cpu
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ioutil.ReadAll(storage.NewAnnotatedCSVReader(strings.NewReader(tt.csv)))
+			if code := influxdb.ErrorCode(err); code != influxdb.EInvalid {
+				t.Fatalf("got error %v with code %q, exp code %q", err, code, influxdb.EInvalid)
+			}
+		})
+	}
+}
+
 func TestEngine_DeleteBucket(t *testing.T) {
 	engine := NewDefaultEngine()
 	defer engine.Close()
@@ -197,6 +530,60 @@ func TestEngine_DeleteBucket(t *testing.T) {
 	}
 }
 
+func TestEngine_DeleteBucketRangePredicate(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pts := []models.Point{
+		models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": "a"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": "b"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+		models.MustNewPoint(
+			"mem",
+			models.NewTags(map[string]string{"host": "a"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		),
+	}
+
+	if err := engine.Write1xPoints(pts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(3); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	pred := influxql.MustParseExpr(`_m = 'cpu' AND host = 'a'`)
+	if err := engine.DeleteBucketRangePredicate(context.Background(), engine.org, engine.bucket, math.MinInt64, math.MaxInt64, pred); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// Replaying the WAL should not resurrect the deleted series.
+	if err := engine.Engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	engine.MustOpen()
+
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index after reopening", got, exp)
+	}
+}
+
 func TestEngine_OpenClose(t *testing.T) {
 	engine := NewDefaultEngine()
 	engine.MustOpen()
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/escape"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/opentracing/opentracing-go"
+)
+
+// A seriesWriter writes the values of the series of an export.
+type seriesWriter interface {
+	// WriteSeries writes the values of cur, which is the cursor of the series
+	// with the provided tags. The tags include the measurement and field tags.
+	WriteSeries(tags models.Tags, cur cursors.Cursor) error
+	Flush() error
+}
+
+// ExportBucket writes the data of a bucket selected by filter to w, reading it
+// from the series of the bucket in the index and their cursors.
+//
+// The series are exported in the order of their series keys, and the values of
+// each series in time order, so that an interrupted export can be resumed with
+// filter.After. The engine lock is not held during the export, so writes to the
+// bucket while it is exported may or may not be exported.
+func (e *Engine) ExportBucket(ctx context.Context, orgID, bucketID influxdb.ID, filter influxdb.ExportFilter, w io.Writer) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Engine.ExportBucket")
+	defer span.Finish()
+
+	var sw seriesWriter
+	switch filter.Format {
+	case influxdb.ExportFormatLineProtocol:
+		sw = newLineProtocolWriter(w)
+	case influxdb.ExportFormatCSV:
+		sw = newAnnotatedCSVWriter(w)
+	default:
+		return filter.Format.Valid()
+	}
+
+	// The cursors of the engine take an inclusive end time.
+	start, end := models.MinNanoTime, models.MaxNanoTime
+	if !filter.Start.IsZero() {
+		start = filter.Start.UnixNano()
+	}
+	if !filter.Stop.IsZero() {
+		end = filter.Stop.UnixNano() - 1
+	}
+
+	name := tsdb.EncodeName(orgID, bucketID)
+
+	var after []byte
+	var afterTime int64
+	if filter.After != nil {
+		measurement, tags := models.ParseKeyBytes([]byte(filter.After.Key))
+		after = tsdb.AppendSeriesKey(nil, name[:], exportSeriesTags(measurement, tags, []byte(filter.After.Field)))
+		afterTime = filter.After.Time.UnixNano()
+	}
+
+	sc, err := e.CreateSeriesCursor(ctx, SeriesCursorRequest{Name: name}, nil)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+
+	ci, err := e.CreateCursorIterator(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := cursors.CursorRequest{Ascending: true, EndTime: end}
+	var key []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row, err := sc.Next()
+		if err != nil {
+			return err
+		} else if row == nil {
+			break
+		}
+
+		req.StartTime = start
+		if after != nil {
+			key = tsdb.AppendSeriesKey(key[:0], row.Name, row.Tags)
+			if cmp := tsdb.CompareSeriesKeys(key, after); cmp < 0 {
+				continue
+			} else if cmp == 0 && afterTime >= start {
+				req.StartTime = afterTime + 1
+			}
+		}
+
+		req.Name = row.Name
+		req.Tags = row.Tags
+		req.Field = string(row.Tags.Get(tsdb.FieldKeyTagKeyBytes))
+		cur, err := ci.Next(ctx, &req)
+		if err != nil {
+			return err
+		} else if cur == nil {
+			continue
+		}
+
+		err = sw.WriteSeries(row.Tags, cur)
+		cur.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return sw.Flush()
+}
+
+// exportSeriesTags returns the tags of the series of a measurement, its tags and
+// a field, in the order of the tags of a series key.
+func exportSeriesTags(measurement []byte, tags models.Tags, field []byte) models.Tags {
+	st := make(models.Tags, 0, len(tags)+2)
+	st = append(st, models.NewTag(tsdb.MeasurementTagKeyBytes, measurement))
+	st = append(st, tags...)
+	st = append(st, models.NewTag(tsdb.FieldKeyTagKeyBytes, field))
+	sort.Sort(st)
+	return st
+}
+
+// splitSeriesTags returns the measurement, the tags and the field of the tags of
+// a series.
+func splitSeriesTags(tags models.Tags) (measurement []byte, userTags models.Tags, field []byte) {
+	userTags = make(models.Tags, 0, len(tags))
+	for _, t := range tags {
+		switch string(t.Key) {
+		case tsdb.MeasurementTagKey:
+			measurement = t.Value
+		case tsdb.FieldKeyTagKey:
+			field = t.Value
+		default:
+			userTags = append(userTags, t)
+		}
+	}
+	return measurement, userTags, field
+}
+
+// forEachValue calls fn with the timestamp and value of each value of cur.
+func forEachValue(cur cursors.Cursor, fn func(ts int64, v interface{}) error) error {
+	switch c := cur.(type) {
+	case cursors.FloatArrayCursor:
+		for a := c.Next(); len(a.Timestamps) > 0; a = c.Next() {
+			for i, v := range a.Values {
+				if err := fn(a.Timestamps[i], v); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.IntegerArrayCursor:
+		for a := c.Next(); len(a.Timestamps) > 0; a = c.Next() {
+			for i, v := range a.Values {
+				if err := fn(a.Timestamps[i], v); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		for a := c.Next(); len(a.Timestamps) > 0; a = c.Next() {
+			for i, v := range a.Values {
+				if err := fn(a.Timestamps[i], v); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.StringArrayCursor:
+		for a := c.Next(); len(a.Timestamps) > 0; a = c.Next() {
+			for i, v := range a.Values {
+				if err := fn(a.Timestamps[i], v); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.BooleanArrayCursor:
+		for a := c.Next(); len(a.Timestamps) > 0; a = c.Next() {
+			for i, v := range a.Values {
+				if err := fn(a.Timestamps[i], v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return cur.Err()
+}
+
+// lineProtocolWriter writes a line of line protocol per value.
+type lineProtocolWriter struct {
+	w      *bufio.Writer
+	prefix []byte
+	buf    []byte
+}
+
+func newLineProtocolWriter(w io.Writer) *lineProtocolWriter {
+	return &lineProtocolWriter{w: bufio.NewWriter(w)}
+}
+
+func (lw *lineProtocolWriter) WriteSeries(tags models.Tags, cur cursors.Cursor) error {
+	measurement, userTags, field := splitSeriesTags(tags)
+	lw.prefix = models.AppendMakeKey(lw.prefix[:0], measurement, userTags)
+	lw.prefix = append(lw.prefix, ' ')
+	lw.prefix = append(lw.prefix, escape.Bytes(field)...)
+	lw.prefix = append(lw.prefix, '=')
+
+	return forEachValue(cur, func(ts int64, v interface{}) error {
+		b := append(lw.buf[:0], lw.prefix...)
+		switch v := v.(type) {
+		case float64:
+			b = strconv.AppendFloat(b, v, 'f', -1, 64)
+		case int64:
+			b = strconv.AppendInt(b, v, 10)
+			b = append(b, 'i')
+		case uint64:
+			b = strconv.AppendUint(b, v, 10)
+			b = append(b, 'u')
+		case string:
+			b = append(b, '"')
+			b = append(b, models.EscapeStringField(v)...)
+			b = append(b, '"')
+		case bool:
+			b = strconv.AppendBool(b, v)
+		}
+		b = append(b, ' ')
+		b = strconv.AppendInt(b, ts, 10)
+		b = append(b, '\n')
+		lw.buf = b
+
+		_, err := lw.w.Write(b)
+		return err
+	})
+}
+
+func (lw *lineProtocolWriter) Flush() error {
+	return lw.w.Flush()
+}
+
+// A BucketExporter exports the data of a bucket.
+type BucketExporter interface {
+	ExportBucket(ctx context.Context, orgID, bucketID influxdb.ID, filter influxdb.ExportFilter, w io.Writer) error
+}
+
+// ExportService implements influxdb.ExportService for a storage engine, using a
+// bucket service to find the organization of a bucket.
+type ExportService struct {
+	buckets influxdb.BucketService
+	engine  BucketExporter
+}
+
+var _ influxdb.ExportService = (*ExportService)(nil)
+
+// NewExportService returns a new ExportService for the provided BucketExporter,
+// which typically will be an Engine.
+func NewExportService(buckets influxdb.BucketService, engine BucketExporter) *ExportService {
+	return &ExportService{
+		buckets: buckets,
+		engine:  engine,
+	}
+}
+
+// ExportBucket writes the data of a bucket selected by filter to w.
+func (s *ExportService) ExportBucket(ctx context.Context, filter influxdb.ExportFilter, w io.Writer) error {
+	if err := filter.Format.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpExportBucket,
+			Err: err,
+		}
+	}
+
+	b, err := s.buckets.FindBucketByID(ctx, filter.BucketID)
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpExportBucket,
+			Err: err,
+		}
+	}
+
+	if err := s.engine.ExportBucket(ctx, b.OrganizationID, b.ID, filter, w); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpExportBucket,
+			Err:  err,
+		}
+	}
+	return nil
+}
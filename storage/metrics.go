@@ -64,3 +64,47 @@ func (rm *retentionMetrics) PrometheusCollectors() []prometheus.Collector {
 		rm.CheckDuration,
 	}
 }
+
+const writerSubsystem = "writer" // sub-system associated with metrics for writing points.
+
+// writerMetrics is a set of metrics concerned with tracking data about writes.
+type writerMetrics struct {
+	labels        prometheus.Labels
+	DroppedSeries *prometheus.CounterVec
+}
+
+func newWriterMetrics(labels prometheus.Labels) *writerMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+
+	droppedNames := append(names, "reason", "org_id", "bucket_id")
+	sort.Strings(droppedNames)
+
+	return &writerMetrics{
+		labels: labels,
+		DroppedSeries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: writerSubsystem,
+			Name:      "dropped_series_total",
+			Help:      "Number of series dropped from writes for exceeding a cardinality limit, by reason and org/bucket id.",
+		}, droppedNames),
+	}
+}
+
+// Labels returns a copy of labels for use with writer metrics.
+func (m *writerMetrics) Labels() prometheus.Labels {
+	l := make(map[string]string, len(m.labels))
+	for k, v := range m.labels {
+		l[k] = v
+	}
+	return l
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *writerMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.DroppedSeries,
+	}
+}
@@ -22,9 +22,11 @@ func NewProxyQueryService(queryController *pcontrol.Controller) query.ProxyQuery
 
 // AddControllerConfigDependencies sets up the dependencies on cc
 // such that "from" and "to" flux functions will work correctly.
+// The points of "to" are written with pointsWriter, which typically wraps engine.
 func AddControllerConfigDependencies(
 	cc *control.Config,
 	engine *storage.Engine,
+	pointsWriter storage.PointsWriter,
 	bucketSvc platform.BucketService,
 	orgSvc platform.OrganizationService,
 ) error {
@@ -46,6 +48,6 @@ func AddControllerConfigDependencies(
 	return influxdb.InjectToDependencies(cc.ExecutorDependencies, influxdb.ToDependencies{
 		BucketLookup:       bucketLookupSvc,
 		OrganizationLookup: orgLookupSvc,
-		PointsWriter:       engine,
+		PointsWriter:       pointsWriter,
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"sync"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
@@ -26,6 +27,15 @@ type BucketFinder interface {
 	FindBuckets(context.Context, platform.BucketFilter, ...platform.FindOptions) ([]*platform.Bucket, int, error)
 }
 
+// A DownsampleWatermarker reports how much of the raw data of a bucket has been
+// rolled up by its downsample rules.
+type DownsampleWatermarker interface {
+	// DownsampleWatermark returns the time before which the raw data of b has
+	// been rolled up successfully by all of its downsample rules, or the zero
+	// time if no data has been rolled up.
+	DownsampleWatermark(ctx context.Context, b *platform.Bucket) (time.Time, error)
+}
+
 // ErrServiceClosed is returned when the service is unavailable.
 var ErrServiceClosed = errors.New("service is currently closed")
 
@@ -39,6 +49,12 @@ type retentionEnforcer struct {
 	// organisations.
 	BucketService BucketFinder
 
+	mu sync.RWMutex
+	// watermarker reports how much of the raw data of buckets with downsample
+	// rules has been rolled up. The raw data of such buckets is kept until it has
+	// been rolled up.
+	watermarker DownsampleWatermarker
+
 	logger *zap.Logger
 
 	metrics *retentionMetrics
@@ -65,6 +81,17 @@ func (s *retentionEnforcer) WithLogger(l *zap.Logger) {
 	s.logger = l.With(zap.String("component", "retention_enforcer"))
 }
 
+// WithDownsampleWatermarker sets the watermarker used to determine how much of
+// the raw data of buckets with downsample rules has been rolled up.
+func (s *retentionEnforcer) WithDownsampleWatermarker(w DownsampleWatermarker) {
+	if s == nil {
+		return // Not initialised
+	}
+	s.mu.Lock()
+	s.watermarker = w
+	s.mu.Unlock()
+}
+
 // run periodically expires (deletes) all data that's fallen outside of the
 // retention period for the associated bucket.
 func (s *retentionEnforcer) run() {
@@ -86,13 +113,17 @@ func (s *retentionEnforcer) run() {
 //
 // Any series data that (1) belongs to a bucket in the provided list and
 // (2) falls outside the bucket's indicated retention period will be deleted.
+// The raw data of a bucket with downsample rules is deleted after the source
+// retention of the rules once it has been rolled up by all of them, and after
+// the retention period of the bucket regardless.
 func (s *retentionEnforcer) expireData(buckets []*platform.Bucket, now time.Time) {
 	logger, logEnd := logger.NewOperation(s.logger, "Data deletion", "data_deletion")
 	defer logEnd()
 
 	labels := s.metrics.Labels()
 	for _, b := range buckets {
-		if b.RetentionPeriod == 0 {
+		rp := b.RawRetentionPeriod()
+		if rp == 0 {
 			continue
 		}
 
@@ -100,7 +131,38 @@ func (s *retentionEnforcer) expireData(buckets []*platform.Bucket, now time.Time
 		labels["org_id"] = b.OrganizationID.String()
 		labels["bucket_id"] = b.ID.String()
 
-		max := now.Add(-b.RetentionPeriod).UnixNano()
+		max := int64(math.MinInt64)
+		if b.RetentionPeriod > 0 {
+			max = now.Add(-b.RetentionPeriod).UnixNano()
+		}
+
+		// Only the data kept for less than the retention period of the bucket
+		// by the source retention of its rules waits for them to be rolled up,
+		// so that a failing rule does not keep the raw data forever.
+		if rp != b.RetentionPeriod {
+			watermark, err := s.downsampleWatermark(b)
+			if err != nil {
+				labels["status"] = "error"
+				logger.Info("unable to determine rolled up range",
+					zap.String("bucket id", b.ID.String()),
+					zap.String("org id", b.OrganizationID.String()),
+					zap.Error(err))
+			} else if !watermark.IsZero() {
+				source := now.Add(-rp).UnixNano()
+				if wm := watermark.UnixNano(); wm < source {
+					source = wm
+				}
+				if source > max {
+					max = source
+				}
+			}
+		}
+
+		if max == math.MinInt64 {
+			s.metrics.Checks.With(labels).Inc()
+			continue
+		}
+
 		err := s.Engine.DeleteBucketRange(b.OrganizationID, b.ID, math.MinInt64, max)
 		if err != nil {
 			labels["status"] = "error"
@@ -114,6 +176,21 @@ func (s *retentionEnforcer) expireData(buckets []*platform.Bucket, now time.Time
 	}
 }
 
+// downsampleWatermark returns the time before which the raw data of b has been
+// rolled up by its downsample rules.
+func (s *retentionEnforcer) downsampleWatermark(b *platform.Bucket) (time.Time, error) {
+	s.mu.RLock()
+	w := s.watermarker
+	s.mu.RUnlock()
+	if w == nil {
+		return time.Time{}, errors.New("downsample rules are not supported")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bucketAPITimeout)
+	defer cancel()
+	return w.DownsampleWatermark(ctx, b)
+}
+
 // getBucketInformation returns a slice of buckets to run retention on.
 func (s *retentionEnforcer) getBucketInformation() ([]*platform.Bucket, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), bucketAPITimeout)
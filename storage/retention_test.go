@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"math"
 	"math/rand"
 	"reflect"
@@ -80,6 +81,89 @@ func TestRetentionService(t *testing.T) {
 	})
 }
 
+func TestRetentionService_DownsampleRules(t *testing.T) {
+	now := time.Date(2018, 4, 10, 23, 12, 33, 0, time.UTC)
+	rules := []platform.DownsampleRule{
+		{SourceRetention: time.Hour, Every: time.Minute, Function: platform.DownsampleFunctionMean, DestinationBucketID: 3, TaskID: 4},
+	}
+
+	tests := []struct {
+		name          string
+		noWatermarker bool
+		watermark     time.Time
+		watermarkErr  error
+		retention     time.Duration
+		expTo         int64 // zero if no data is expected to be deleted
+	}{
+		{
+			name:          "no watermarker",
+			noWatermarker: true,
+			retention:     3 * time.Hour,
+			expTo:         now.Add(-3 * time.Hour).UnixNano(),
+		},
+		{
+			name:         "failing watermarker",
+			watermarkErr: errors.New("rollup task failed"),
+			retention:    3 * time.Hour,
+			expTo:        now.Add(-3 * time.Hour).UnixNano(),
+		},
+		{
+			name:          "no watermarker and infinite retention",
+			noWatermarker: true,
+		},
+		{
+			name:      "rolled up",
+			watermark: now,
+			retention: 3 * time.Hour,
+			expTo:     now.Add(-time.Hour).UnixNano(),
+		},
+		{
+			name:      "rolled up partially",
+			watermark: now.Add(-2 * time.Hour),
+			expTo:     now.Add(-2 * time.Hour).UnixNano(),
+		},
+		{
+			name:      "rolled up partially before the retention period",
+			watermark: now.Add(-5 * time.Hour),
+			retention: 3 * time.Hour,
+			expTo:     now.Add(-3 * time.Hour).UnixNano(),
+		},
+		{
+			name: "not rolled up",
+		},
+		{
+			name:      "not rolled up with a retention period",
+			retention: 3 * time.Hour,
+			expTo:     now.Add(-3 * time.Hour).UnixNano(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewTestEngine()
+			service := newRetentionEnforcer(engine, NewTestBucketFinder())
+			if !tt.noWatermarker {
+				service.WithDownsampleWatermarker(TestDownsampleWatermarker(func(context.Context, *platform.Bucket) (time.Time, error) {
+					return tt.watermark, tt.watermarkErr
+				}))
+			}
+
+			var gotTo int64
+			engine.DeleteBucketRangeFn = func(orgID, bucketID platform.ID, from, to int64) error {
+				gotTo = to
+				return nil
+			}
+
+			service.expireData([]*platform.Bucket{
+				{OrganizationID: 1, ID: 2, RetentionPeriod: tt.retention, DownsampleRules: rules},
+			}, now)
+			if gotTo != tt.expTo {
+				t.Fatalf("got delete to %d, expected %d", gotTo, tt.expTo)
+			}
+		})
+	}
+}
+
 // genMeasurementName generates a random measurement name or panics.
 func genMeasurementName() []byte {
 	b := make([]byte, 16)
@@ -119,3 +203,9 @@ func NewTestBucketFinder() *TestBucketFinder {
 func (f *TestBucketFinder) FindBuckets(ctx context.Context, filter platform.BucketFilter, opts ...platform.FindOptions) ([]*platform.Bucket, int, error) {
 	return f.FindBucketsFn(ctx, filter, opts...)
 }
+
+type TestDownsampleWatermarker func(context.Context, *platform.Bucket) (time.Time, error)
+
+func (w TestDownsampleWatermarker) DownsampleWatermark(ctx context.Context, b *platform.Bucket) (time.Time, error) {
+	return w(ctx, b)
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// SchemaValidator validates points against the measurement schemas of a bucket
+// with an explicit schema.
+type SchemaValidator map[string]*platform.MeasurementSchema
+
+// NewSchemaValidator returns a SchemaValidator of schemas.
+func NewSchemaValidator(schemas []*platform.MeasurementSchema) SchemaValidator {
+	v := make(SchemaValidator, len(schemas))
+	for _, m := range schemas {
+		v[m.Name] = m
+	}
+	return v
+}
+
+// ValidatePoint returns an error if p does not match the schemas. A point must
+// belong to a measurement with a schema, have every tag required by it, and only
+// have the fields it defines with their types.
+func (v SchemaValidator) ValidatePoint(p models.Point) error {
+	name := p.Name()
+	m, err := v.measurement(name, p)
+	if err != nil {
+		return err
+	}
+
+	itr := p.FieldIterator()
+	for itr.Next() {
+		if err := validateField(m, itr.FieldKey(), itr.Type()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateExploded returns an error if p, a point exploded by tsdb.ExplodePoints,
+// does not match the schemas.
+func (v SchemaValidator) validateExploded(p models.Point) error {
+	name := p.Tags().Get(tsdb.MeasurementTagKeyBytes)
+	m, err := v.measurement(name, p)
+	if err != nil {
+		return err
+	}
+
+	itr := p.FieldIterator()
+	for itr.Next() {
+		if err := validateField(m, p.Tags().Get(tsdb.FieldKeyTagKeyBytes), itr.Type()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// measurement returns the schema of the measurement named name, if p has every
+// tag it requires.
+func (v SchemaValidator) measurement(name []byte, p models.Point) (*platform.MeasurementSchema, error) {
+	m, ok := v[string(name)]
+	if !ok {
+		return nil, fmt.Errorf("measurement %q is not defined by the bucket schema", name)
+	}
+
+	for _, t := range m.Tags {
+		if !p.HasTag([]byte(t)) {
+			return nil, fmt.Errorf("measurement %q requires tag %q", name, t)
+		}
+	}
+	return m, nil
+}
+
+func validateField(m *platform.MeasurementSchema, key []byte, typ models.FieldType) error {
+	f := m.Field(string(key))
+	if f == nil {
+		return fmt.Errorf("field %q is not defined by the schema of measurement %q", key, m.Name)
+	}
+	if t := schemaFieldType(typ); t != f.Type {
+		return fmt.Errorf("field %q of measurement %q must be of type %s, got %s", key, m.Name, f.Type, t)
+	}
+	return nil
+}
+
+// schemaFieldType returns the schema field type of the line protocol field type typ.
+func schemaFieldType(typ models.FieldType) platform.SchemaFieldType {
+	switch typ {
+	case models.Float:
+		return platform.SchemaFieldTypeFloat
+	case models.Integer:
+		return platform.SchemaFieldTypeInteger
+	case models.Unsigned:
+		return platform.SchemaFieldTypeUnsigned
+	case models.String:
+		return platform.SchemaFieldTypeString
+	case models.Boolean:
+		return platform.SchemaFieldTypeBoolean
+	default:
+		return platform.SchemaFieldType(typ.String())
+	}
+}
+
+// SchemaPointsWriter wraps a PointsWriter, and drops the points written to buckets
+// with an explicit schema which do not match it.
+//
+// It validates the points exploded by tsdb.ExplodePoints which are written to the
+// engine, so that every write is validated, whether it is made through the write
+// API, by a task, or by an import. The points which match are still written, and
+// the dropped points are reported by a tsdb.PartialWriteError.
+type SchemaPointsWriter struct {
+	PointsWriter
+	BucketService       platform.BucketService
+	BucketSchemaService platform.BucketSchemaService
+}
+
+// NewSchemaPointsWriter returns a SchemaPointsWriter writing to w the points which
+// match the schemas of their buckets.
+func NewSchemaPointsWriter(w PointsWriter, bs platform.BucketService, ss platform.BucketSchemaService) *SchemaPointsWriter {
+	return &SchemaPointsWriter{
+		PointsWriter:        w,
+		BucketService:       bs,
+		BucketSchemaService: ss,
+	}
+}
+
+// WritePoints writes the points which match the schemas of their buckets.
+func (w *SchemaPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	validators := make(map[[16]byte]SchemaValidator)
+
+	var (
+		valid   = make([]models.Point, 0, len(points))
+		dropped [][]byte
+		reason  string
+	)
+	for _, p := range points {
+		var name [16]byte
+		copy(name[:], p.Name())
+
+		v, ok := validators[name]
+		if !ok {
+			var err error
+			if v, err = w.validator(ctx, name); err != nil {
+				return err
+			}
+			validators[name] = v
+		}
+
+		if v != nil {
+			if err := v.validateExploded(p); err != nil {
+				if reason == "" {
+					reason = fmt.Sprintf("points do not match the bucket schema: %v", err)
+				}
+				dropped = append(dropped, p.Key())
+				continue
+			}
+		}
+		valid = append(valid, p)
+	}
+
+	if len(dropped) == 0 {
+		return w.PointsWriter.WritePoints(ctx, points)
+	}
+
+	pwe := tsdb.PartialWriteError{
+		Reason:      reason,
+		Dropped:     len(dropped),
+		DroppedKeys: dropped,
+	}
+	if len(valid) > 0 {
+		err := w.PointsWriter.WritePoints(ctx, valid)
+		if e, ok := err.(tsdb.PartialWriteError); ok {
+			pwe.Reason = fmt.Sprintf("%s; %s", pwe.Reason, e.Reason)
+			pwe.Dropped += e.Dropped
+			pwe.DroppedKeys = append(pwe.DroppedKeys, e.DroppedKeys...)
+		} else if err != nil {
+			return err
+		}
+	}
+	sort.Slice(pwe.DroppedKeys, func(i, j int) bool {
+		return bytes.Compare(pwe.DroppedKeys[i], pwe.DroppedKeys[j]) < 0
+	})
+	return pwe
+}
+
+// validator returns the SchemaValidator of the bucket of the points with the given
+// name, or nil if its schema is not explicit.
+func (w *SchemaPointsWriter) validator(ctx context.Context, name [16]byte) (SchemaValidator, error) {
+	_, bucketID := tsdb.DecodeName(name)
+	b, err := w.BucketService.FindBucketByID(ctx, bucketID)
+	if platform.ErrorCode(err) == platform.ENotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if b.SchemaType != platform.SchemaTypeExplicit {
+		return nil, nil
+	}
+
+	schemas, err := w.BucketSchemaService.FindMeasurementSchemas(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+	return NewSchemaValidator(schemas), nil
+}
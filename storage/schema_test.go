@@ -0,0 +1,89 @@
+package storage_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestSchemaPointsWriter_WritePoints(t *testing.T) {
+	orgID, explicitID, implicitID := platform.ID(1), platform.ID(2), platform.ID(3)
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		b := &platform.Bucket{ID: id, OrganizationID: orgID}
+		if id == explicitID {
+			b.SchemaType = platform.SchemaTypeExplicit
+		}
+		return b, nil
+	}
+	schemas := mock.NewBucketSchemaService()
+	schemas.FindMeasurementSchemasFn = func(ctx context.Context, bucketID platform.ID) ([]*platform.MeasurementSchema, error) {
+		return []*platform.MeasurementSchema{
+			{
+				Name: "cpu",
+				Tags: []string{"host"},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "usage", Type: platform.SchemaFieldTypeFloat},
+				},
+			},
+		}, nil
+	}
+
+	explode := func(bucketID platform.ID, lines string) []models.Point {
+		points, err := models.ParsePointsString(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if points, err = tsdb.ExplodePoints(orgID, bucketID, points); err != nil {
+			t.Fatal(err)
+		}
+		return points
+	}
+	keys := func(points []models.Point) []string {
+		var ks []string
+		for _, p := range points {
+			ks = append(ks, string(p.Key()))
+		}
+		sort.Strings(ks)
+		return ks
+	}
+
+	// Each field is validated once points are exploded, so that the fields of a
+	// point which match the schema are written even if its other fields do not.
+	valid := append(
+		explode(explicitID, "cpu,host=a usage=1 1\ncpu,host=b usage=1 1"),
+		explode(implicitID, "mem,host=a used=1i 1")...,
+	)
+	invalid := explode(explicitID, "cpu usage=1 1\ncpu,host=c usage=1i 1\nmem,host=a used=1 1\ncpu,host=b idle=2 1")
+
+	pw := &mock.PointsWriter{}
+	w := storage.NewSchemaPointsWriter(pw, buckets, schemas)
+
+	err := w.WritePoints(context.Background(), append(append([]models.Point{}, valid...), invalid...))
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatalf("expected a partial write error, got %v", err)
+	}
+	if pwe.Dropped != len(invalid) {
+		t.Errorf("expected %d points to be dropped, got %d", len(invalid), pwe.Dropped)
+	}
+
+	var dropped []string
+	for _, k := range pwe.DroppedKeys {
+		dropped = append(dropped, string(k))
+	}
+	if want := keys(invalid); !cmp.Equal(dropped, want) {
+		t.Errorf("unexpected dropped keys -got/+want\n%s", cmp.Diff(dropped, want))
+	}
+	if got, want := keys(pw.Points), keys(valid); !cmp.Equal(got, want) {
+		t.Errorf("unexpected written points -got/+want\n%s", cmp.Diff(got, want))
+	}
+}
@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+)
+
+// Reasons for dropping a series, used to label the dropped series metric.
+const (
+	dropReasonMaxSeriesPerOrg    = "max_series_per_org"
+	dropReasonMaxSeriesPerBucket = "max_series_per_bucket"
+	dropReasonMaxValuesPerTag    = "max_values_per_tag"
+)
+
+type seriesLimitTagKey struct {
+	name, key string
+}
+
+type seriesLimitTagValue struct {
+	name, key, value string
+}
+
+// seriesLimiter enforces the series cardinality limits of an engine on the new
+// series of a single write. The cardinality of the existing data is loaded
+// lazily and then kept up to date with the series admitted by the write.
+type seriesLimiter struct {
+	e *Engine
+
+	stats        tsi1.MeasurementCardinalityStats
+	orgSeries    map[string]int
+	bucketSeries map[string]int
+	tagValues    map[seriesLimitTagKey]int
+
+	// seen records whether each new series key of the write has been admitted
+	// (true) or dropped (false), and values the new tag values admitted.
+	seen   map[string]bool
+	values map[seriesLimitTagValue]struct{}
+}
+
+func newSeriesLimiter(e *Engine) *seriesLimiter {
+	return &seriesLimiter{
+		e:            e,
+		orgSeries:    make(map[string]int),
+		bucketSeries: make(map[string]int),
+		tagValues:    make(map[seriesLimitTagKey]int),
+		seen:         make(map[string]bool),
+		values:       make(map[seriesLimitTagValue]struct{}),
+	}
+}
+
+// limitSeries drops the points of the collection which would create a series
+// exceeding one of the series cardinality limits of the engine's config. Points
+// of existing series are never dropped. It must be called under the engine lock.
+func (e *Engine) limitSeries(collection *tsdb.SeriesCollection) error {
+	if e.config.MaxSeriesPerOrg <= 0 && e.config.MaxSeriesPerBucket <= 0 && e.config.MaxValuesPerTag <= 0 {
+		return nil
+	}
+
+	l, j := newSeriesLimiter(e), 0
+	for iter := collection.Iterator(); iter.Next(); {
+		reason, msg, err := l.check(iter.Key(), iter.Name(), iter.Tags())
+		if err != nil {
+			return err
+		}
+
+		if reason != "" {
+			if collection.Reason == "" {
+				collection.Reason = msg
+			}
+			collection.Dropped++
+			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
+			continue
+		}
+
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+	return nil
+}
+
+// check returns the reason and a description of why the series should be dropped,
+// or an empty reason if it can be written. If the series is new and is not
+// dropped, it is counted towards the limits for the remainder of the write.
+func (l *seriesLimiter) check(key, name []byte, tags models.Tags) (reason, msg string, err error) {
+	if admitted, ok := l.seen[string(key)]; ok {
+		if admitted {
+			return "", "", nil
+		}
+		return l.rejection(name, tags)
+	}
+
+	if l.e.sfile.HasSeries(name, tags, nil) {
+		return "", "", nil
+	}
+
+	orgKey := string(name[:8])
+	bucketKey := string(name)
+
+	if max := l.e.config.MaxSeriesPerBucket; max > 0 {
+		if l.bucketSeriesN(bucketKey) >= max {
+			return l.drop(key, name, tags)
+		}
+	}
+
+	if max := l.e.config.MaxSeriesPerOrg; max > 0 {
+		if l.orgSeriesN(orgKey) >= max {
+			return l.drop(key, name, tags)
+		}
+	}
+
+	var newValues []seriesLimitTagValue
+	if max := l.e.config.MaxValuesPerTag; max > 0 {
+		for _, t := range tags {
+			if isReservedTagKey(t.Key) {
+				continue
+			}
+
+			v := seriesLimitTagValue{name: bucketKey, key: string(t.Key), value: string(t.Value)}
+			if _, ok := l.values[v]; ok {
+				continue
+			}
+			if ok, err := l.e.index.HasTagValue(name, t.Key, t.Value); err != nil {
+				return "", "", err
+			} else if ok {
+				continue
+			}
+
+			n, err := l.tagValueN(name, t.Key)
+			if err != nil {
+				return "", "", err
+			}
+			if n >= max {
+				return l.drop(key, name, tags)
+			}
+			newValues = append(newValues, v)
+		}
+	}
+
+	// The series is admitted, so count it towards the limits.
+	l.seen[string(key)] = true
+	if l.e.config.MaxSeriesPerBucket > 0 || l.e.config.MaxSeriesPerOrg > 0 {
+		l.bucketSeries[bucketKey] = l.bucketSeriesN(bucketKey) + 1
+	}
+	if n, ok := l.orgSeries[orgKey]; ok {
+		l.orgSeries[orgKey] = n + 1
+	}
+	for _, v := range newValues {
+		l.values[v] = struct{}{}
+		l.tagValues[seriesLimitTagKey{name: v.name, key: v.key}]++
+	}
+	return "", "", nil
+}
+
+// drop records a new series as dropped, both for the remainder of the write and
+// in the engine's metrics.
+func (l *seriesLimiter) drop(key, name []byte, tags models.Tags) (reason, msg string, err error) {
+	l.seen[string(key)] = false
+
+	reason, msg, err = l.rejection(name, tags)
+	if err != nil {
+		return "", "", err
+	}
+
+	var encoded [16]byte
+	copy(encoded[:], name)
+	orgID, bucketID := tsdb.DecodeName(encoded)
+
+	labels := l.e.writerMetrics.Labels()
+	labels["reason"] = reason
+	labels["org_id"] = orgID.String()
+	labels["bucket_id"] = bucketID.String()
+	l.e.writerMetrics.DroppedSeries.With(labels).Inc()
+
+	return reason, msg, nil
+}
+
+// rejection determines which limit a dropped series exceeds.
+func (l *seriesLimiter) rejection(name []byte, tags models.Tags) (reason, msg string, err error) {
+	c := l.e.config
+	switch {
+	case c.MaxSeriesPerBucket > 0 && l.bucketSeriesN(string(name)) >= c.MaxSeriesPerBucket:
+		reason = dropReasonMaxSeriesPerBucket
+		msg = fmt.Sprintf("max-series-per-bucket limit exceeded: (%d)", c.MaxSeriesPerBucket)
+	case c.MaxSeriesPerOrg > 0 && l.orgSeriesN(string(name[:8])) >= c.MaxSeriesPerOrg:
+		reason = dropReasonMaxSeriesPerOrg
+		msg = fmt.Sprintf("max-series-per-org limit exceeded: (%d)", c.MaxSeriesPerOrg)
+	default:
+		reason = dropReasonMaxValuesPerTag
+		msg = fmt.Sprintf("max-values-per-tag limit exceeded: (%d)", c.MaxValuesPerTag)
+		for _, t := range tags {
+			if isReservedTagKey(t.Key) {
+				continue
+			}
+			if n, err := l.tagValueN(name, t.Key); err != nil {
+				return "", "", err
+			} else if n >= c.MaxValuesPerTag {
+				msg = fmt.Sprintf("max-values-per-tag limit exceeded (%d): measurement=%q tag=%q value=%q",
+					c.MaxValuesPerTag, tags.Get(tsdb.MeasurementTagKeyBytes), t.Key, t.Value)
+				break
+			}
+		}
+	}
+	return reason, msg, nil
+}
+
+// cardinalityStats returns the number of series of each bucket in the index.
+func (l *seriesLimiter) cardinalityStats() tsi1.MeasurementCardinalityStats {
+	if l.stats == nil {
+		l.stats = l.e.index.MeasurementCardinalityStats()
+	}
+	return l.stats
+}
+
+// bucketSeriesN returns the number of series in the bucket with the encoded name.
+func (l *seriesLimiter) bucketSeriesN(name string) int {
+	n, ok := l.bucketSeries[name]
+	if !ok {
+		n = l.cardinalityStats()[name]
+		l.bucketSeries[name] = n
+	}
+	return n
+}
+
+// orgSeriesN returns the number of series in the buckets of the org with the
+// encoded id.
+func (l *seriesLimiter) orgSeriesN(org string) int {
+	n, ok := l.orgSeries[org]
+	if !ok {
+		for name := range l.cardinalityStats() {
+			if len(name) >= 8 && name[:8] == org {
+				n += l.bucketSeriesN(name)
+			}
+		}
+		// Account for series already admitted to buckets with no series in the index.
+		for name, bn := range l.bucketSeries {
+			if name[:8] == org {
+				if _, ok := l.stats[name]; !ok {
+					n += bn
+				}
+			}
+		}
+		l.orgSeries[org] = n
+	}
+	return n
+}
+
+// tagValueN returns the number of values of the tag key in the bucket with the
+// encoded name. Values are only counted up to the max-values-per-tag limit.
+func (l *seriesLimiter) tagValueN(name, key []byte) (int, error) {
+	k := seriesLimitTagKey{name: string(name), key: string(key)}
+	if n, ok := l.tagValues[k]; ok {
+		return n, nil
+	}
+
+	itr, err := l.e.index.TagValueIterator(name, key)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	if itr != nil {
+		defer itr.Close()
+		for n < l.e.config.MaxValuesPerTag {
+			v, err := itr.Next()
+			if err != nil {
+				return 0, err
+			} else if v == nil {
+				break
+			}
+			n++
+		}
+	}
+
+	l.tagValues[k] = n
+	return n, nil
+}
+
+// isReservedTagKey returns true if key is one of the tag keys used to store the
+// measurement and field of a series.
+func isReservedTagKey(key []byte) bool {
+	return bytes.Equal(key, tsdb.MeasurementTagKeyBytes) || bytes.Equal(key, tsdb.FieldKeyTagKeyBytes)
+}
@@ -260,6 +260,53 @@ func TestWALWriter_DeleteBucketRange(t *testing.T) {
 	}
 }
 
+func TestWALWriter_DeleteBucketRangePredicate(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	f := MustTempFile(dir)
+	w := NewWALSegmentWriter(f)
+
+	entry := &DeleteBucketRangePredicateWALEntry{
+		OrgID:     influxdb.ID(1),
+		BucketID:  influxdb.ID(2),
+		Min:       3,
+		Max:       4,
+		Predicate: []byte(`_m = 'cpu' AND host = 'A'`),
+	}
+
+	if err := w.Write(mustMarshalEntry(entry)); err != nil {
+		fatal(t, "write points", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		fatal(t, "flush", err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		fatal(t, "seek", err)
+	}
+
+	r := NewWALSegmentReader(f)
+
+	if !r.Next() {
+		t.Fatalf("expected next, got false")
+	}
+
+	we, err := r.Read()
+	if err != nil {
+		fatal(t, "read entry", err)
+	}
+
+	e, ok := we.(*DeleteBucketRangePredicateWALEntry)
+	if !ok {
+		t.Fatalf("expected DeleteBucketRangePredicateWALEntry: got %#v", e)
+	}
+
+	if !reflect.DeepEqual(entry, e) {
+		t.Fatalf("expected %+v but got %+v", entry, e)
+	}
+}
+
 func TestWAL_ClosedSegments(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -495,6 +542,58 @@ func TestWriteWALSegment_UnmarshalBinary_DeleteBucketRangeWALCorrupt(t *testing.
 	}
 }
 
+func TestDeleteBucketRangePredicateWALEntry_UnmarshalBinary(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		in := &DeleteBucketRangePredicateWALEntry{
+			OrgID:     influxdb.ID(rand.Int63()) + 1,
+			BucketID:  influxdb.ID(rand.Int63()) + 1,
+			Min:       rand.Int63(),
+			Max:       rand.Int63(),
+			Predicate: []byte(fmt.Sprintf("host = 'server-%d'", rand.Int())),
+		}
+
+		b, err := in.MarshalBinary()
+		if err != nil {
+			t.Fatalf("unexpected error, got %v", err)
+		}
+
+		out := &DeleteBucketRangePredicateWALEntry{}
+		if err := out.UnmarshalBinary(b); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("got %+v, expected %+v", out, in)
+		}
+	}
+}
+
+func TestWriteWALSegment_UnmarshalBinary_DeleteBucketRangePredicateWALCorrupt(t *testing.T) {
+	w := &DeleteBucketRangePredicateWALEntry{
+		OrgID:     influxdb.ID(1),
+		BucketID:  influxdb.ID(2),
+		Min:       3,
+		Max:       4,
+		Predicate: []byte(`_m = 'cpu'`),
+	}
+
+	b, err := w.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	// Test every possible truncation of a write WAL entry
+	for i := 0; i < len(b); i++ {
+		// re-allocated to ensure capacity would be exceed if slicing
+		truncated := make([]byte, i)
+		copy(truncated, b[:i])
+		err := w.UnmarshalBinary(truncated)
+		if err != nil && err != ErrWALCorrupt {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
 func BenchmarkWALSegmentWriter(b *testing.B) {
 	points := map[string][]value.Value{}
 	for i := 0; i < 5000; i++ {
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/influxdata/flux"
@@ -20,6 +23,16 @@ const (
 
 	TaskStatusActive   = "active"
 	TaskStatusInactive = "inactive"
+
+	BackfillStatusActive = "active"
+	BackfillStatusPaused = "paused"
+
+	TaskValidationDefaultRuns = 5
+	TaskValidationMaxRuns     = 100
+
+	TaskNotifyOnFailure             = "failure"
+	TaskNotifyOnConsecutiveFailures = "consecutive_failures"
+	TaskNotifyOnRecovery            = "recovery"
 )
 
 // Task is a task. 🎊
@@ -37,6 +50,13 @@ type Task struct {
 	LatestCompleted string `json:"latestCompleted,omitempty"`
 	CreatedAt       string `json:"createdAt,omitempty"`
 	UpdatedAt       string `json:"updatedAt,omitempty"`
+	DependsOn       []ID   `json:"dependsOn,omitempty"`
+	Revision        int64  `json:"revision,omitempty"`
+
+	// Trigger is set if the task is run when points are written to a bucket, rather than on a schedule.
+	Trigger *TaskTrigger `json:"trigger,omitempty"`
+
+	NotificationRules []TaskNotificationRule `json:"notificationRules,omitempty"`
 }
 
 // Run is a record created when a run of a task is scheduled.
@@ -48,11 +68,267 @@ type Run struct {
 	StartedAt    string `json:"startedAt,omitempty"`
 	FinishedAt   string `json:"finishedAt,omitempty"`
 	RequestedAt  string `json:"requestedAt,omitempty"`
-	Log          Log    `json:"log"`
+	Revision     int64  `json:"revision,omitempty"`
+	Log          []Log  `json:"log,omitempty"`
+
+	// Statistics of the query executed by the run, set once the run has finished executing.
+	Statistics *RunStatistics `json:"statistics,omitempty"`
+}
+
+// Log is a structured entry of the log of a run.
+type Log struct {
+	RunID   ID                `json:"runID,omitempty"`
+	Time    string            `json:"time"`
+	Level   LogLevel          `json:"level"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// LogLevel is the severity of a log entry of a run.
+type LogLevel string
+
+// Levels of log entries of runs.
+const (
+	LogLevelDebug LogLevel = "debug"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
+// RunStatistics are the statistics of the query executed by a run.
+// Durations are in nanoseconds.
+type RunStatistics struct {
+	TotalDuration   time.Duration `json:"totalDuration"`
+	CompileDuration time.Duration `json:"compileDuration"`
+	QueueDuration   time.Duration `json:"queueDuration"`
+	PlanDuration    time.Duration `json:"planDuration"`
+	RequeueDuration time.Duration `json:"requeueDuration"`
+	ExecuteDuration time.Duration `json:"executeDuration"`
+
+	// Number of goroutines and maximum number of bytes allocated to execute the query.
+	Concurrency  int   `json:"concurrency"`
+	MaxAllocated int64 `json:"maxAllocated"`
+
+	// Number of values and bytes read from storage.
+	ScannedValues int64 `json:"scannedValues"`
+	ScannedBytes  int64 `json:"scannedBytes"`
+
+	// Number of rows in the results of the query, which are the rows the task writes with to().
+	RowsWritten int64 `json:"rowsWritten"`
+}
+
+// Backfill is a request to run a task for each of its schedules in a range of time.
+type Backfill struct {
+	ID          ID     `json:"id,omitempty"`
+	TaskID      ID     `json:"taskID"`
+	Status      string `json:"status"`
+	Start       string `json:"start"`
+	Stop        string `json:"stop"`
+	RequestedAt string `json:"requestedAt,omitempty"`
+
+	// LatestCompleted is the schedule of the latest completed run of the backfill.
+	LatestCompleted string `json:"latestCompleted,omitempty"`
+
+	// RunsCompleted is the number of schedules of the backfill no later than LatestCompleted,
+	// out of RunsTotal schedules from Start to Stop.
+	RunsCompleted int64 `json:"runsCompleted"`
+	RunsTotal     int64 `json:"runsTotal"`
+}
+
+// BackfillUpdate represents updates to a backfill.
+type BackfillUpdate struct {
+	Status *string `json:"status,omitempty"`
+}
+
+// Validate returns an error if the status of the update is not a backfill status.
+func (u BackfillUpdate) Validate() error {
+	if u.Status != nil && *u.Status != BackfillStatusActive && *u.Status != BackfillStatusPaused {
+		return fmt.Errorf("invalid backfill status: %q", *u.Status)
+	}
+	return nil
+}
+
+// TaskRevision is an immutable record of the script of a task,
+// created when the task is created and each time its script changes.
+type TaskRevision struct {
+	TaskID    ID     `json:"taskID"`
+	Revision  int64  `json:"revision"`
+	Flux      string `json:"flux"`
+	Name      string `json:"name"`
+	Every     string `json:"every,omitempty"`
+	Cron      string `json:"cron,omitempty"`
+	Offset    string `json:"offset,omitempty"`
+	UpdatedBy ID     `json:"updatedBy,omitempty"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// TaskRevisionDiff is the line-based difference between the scripts of two revisions of a task.
+type TaskRevisionDiff struct {
+	TaskID ID    `json:"taskID"`
+	From   int64 `json:"from"`
+	To     int64 `json:"to"`
+
+	// Diff lists the lines of both scripts, prefixed by "-" if only in From, "+" if only in To, or " " if in both.
+	Diff string `json:"diff"`
+}
+
+// TaskTrigger describes the writes which trigger runs of a task.
+type TaskTrigger struct {
+	BucketID    ID     `json:"bucketID"`
+	Measurement string `json:"measurement,omitempty"`
+	MinInterval string `json:"minInterval,omitempty"`
+}
+
+// TaskNotificationRule describes when to notify an HTTP endpoint about the outcome of the runs of a task.
+// The notification is a JSON payload posted to URL, describing the run and ending with its last log lines.
+type TaskNotificationRule struct {
+	// On is the condition for a notification: TaskNotifyOnFailure notifies about every failed run,
+	// TaskNotifyOnConsecutiveFailures about the failed run which makes Threshold failed runs in a row,
+	// and TaskNotifyOnRecovery about a successful run after failed runs.
+	On        string `json:"on"`
+	Threshold int    `json:"threshold,omitempty"`
+	URL       string `json:"url"`
+
+	// AuthHeader is the name of an HTTP header, such as Authorization, sent with the notification.
+	// Its value is the secret of the task's organization with the key AuthSecretKey.
+	AuthHeader    string `json:"authHeader,omitempty"`
+	AuthSecretKey string `json:"authSecretKey,omitempty"`
+}
+
+// Validate returns an error if the notification rule has an unknown condition or an invalid URL.
+func (r TaskNotificationRule) Validate() error {
+	switch r.On {
+	case TaskNotifyOnFailure, TaskNotifyOnRecovery:
+		if r.Threshold != 0 {
+			return fmt.Errorf("threshold is only allowed for %q notifications", TaskNotifyOnConsecutiveFailures)
+		}
+	case TaskNotifyOnConsecutiveFailures:
+		if r.Threshold < 1 {
+			return errors.New("threshold of consecutive failures must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid notification condition: %q", r.On)
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return fmt.Errorf("invalid notification url: %v", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("notification url must be an absolute http or https url: %q", r.URL)
+	}
+	if host := strings.ToLower(u.Hostname()); host == "localhost" || strings.HasSuffix(host, ".localhost") || IsInternalIP(net.ParseIP(host)) {
+		return fmt.Errorf("notification url must not be a loopback, link-local or private address: %q", r.URL)
+	}
+
+	if (r.AuthHeader == "") != (r.AuthSecretKey == "") {
+		return errors.New("authHeader and authSecretKey must be set together")
+	}
+	return nil
 }
 
-// Log represents a link to a log resource
-type Log string
+// privateNetworks are the IPv4 private (RFC 1918) and shared (RFC 6598) networks, and the IPv6 unique local
+// network (RFC 4193).
+var privateNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsInternalIP returns whether ip is a loopback, link-local, private or unspecified address,
+// which notifications must not be sent to.
+func IsInternalIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskValidation is a request to check the Flux script of a task without creating the task.
+type TaskValidation struct {
+	Flux           string `json:"flux"`
+	OrganizationID ID     `json:"orgID,omitempty"`
+	Organization   string `json:"org,omitempty"`
+
+	// Runs is the number of next scheduled runs to report.
+	// If zero, TaskValidationDefaultRuns are reported.
+	Runs int `json:"runs,omitempty"`
+
+	// Execute requests to run the script once, without writing the output of to().
+	Execute bool `json:"execute,omitempty"`
+
+	// Now is the RFC3339 time the executed run is scheduled for,
+	// which bounds the time range the script reads like it does for a run of the task.
+	// If empty, the current time is used.
+	Now string `json:"now,omitempty"`
+}
+
+// Validate returns an error if the validation request is incomplete.
+func (v TaskValidation) Validate() error {
+	switch {
+	case v.Flux == "":
+		return errors.New("missing flux")
+	case v.Runs < 0 || v.Runs > TaskValidationMaxRuns:
+		return fmt.Errorf("runs must be between 0 and %d", TaskValidationMaxRuns)
+	case v.Execute && !v.OrganizationID.Valid() && v.Organization == "":
+		return errors.New("missing orgID and org to execute the script")
+	}
+	if v.Now != "" {
+		if _, err := time.Parse(time.RFC3339, v.Now); err != nil {
+			return fmt.Errorf("invalid now: %v", err)
+		}
+	}
+	return nil
+}
+
+// TaskValidationResult describes the task a Flux script would create.
+type TaskValidationResult struct {
+	Name        string `json:"name"`
+	Every       string `json:"every,omitempty"`
+	Cron        string `json:"cron,omitempty"`
+	Offset      string `json:"offset,omitempty"`
+	Concurrency int64  `json:"concurrency,omitempty"`
+	Retry       int64  `json:"retry,omitempty"`
+	DependsOn   []ID   `json:"dependsOn,omitempty"`
+
+	// Permissions are the permissions on buckets needed by the script, which the caller was verified to have.
+	Permissions []Permission `json:"permissions"`
+
+	// NextRuns are the RFC3339 times of the next scheduled runs of the task, if it was created now.
+	// A task with dependencies has no scheduled runs of its own.
+	NextRuns []string `json:"nextRuns"`
+
+	// Execution is the outcome of running the script once, if requested.
+	Execution *TaskExecution `json:"execution,omitempty"`
+}
+
+// TaskExecution is the outcome of running the Flux script of a task once while validating it.
+type TaskExecution struct {
+	ScheduledFor string                `json:"scheduledFor"`
+	Results      []TaskExecutionResult `json:"results"`
+	Error        string                `json:"error,omitempty"`
+}
+
+// TaskExecutionResult summarizes a result of running the Flux script of a task.
+type TaskExecutionResult struct {
+	Name   string `json:"name"`
+	Tables int    `json:"tables"`
+	Rows   int    `json:"rows"`
+}
 
 // TaskService represents a service for managing one-off and recurring tasks.
 type TaskService interface {
@@ -91,6 +367,28 @@ type TaskService interface {
 	// ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 	// The value of scheduledFor may or may not align with the task's schedule.
 	ForceRun(ctx context.Context, taskID ID, scheduledFor int64) (*Run, error)
+
+	// CreateBackfill requests runs of a task for each of its schedules from the unix timestamps start to stop, inclusive.
+	// The runs are created as soon as the concurrency of the task allows.
+	CreateBackfill(ctx context.Context, taskID ID, start, stop int64) (*Backfill, error)
+
+	// FindBackfills returns the backfills of a task which still have runs to be created.
+	FindBackfills(ctx context.Context, taskID ID) ([]*Backfill, error)
+
+	// UpdateBackfill pauses or resumes a backfill.
+	UpdateBackfill(ctx context.Context, taskID, backfillID ID, upd BackfillUpdate) (*Backfill, error)
+
+	// CancelBackfill removes a backfill and cancels its runs in progress.
+	CancelBackfill(ctx context.Context, taskID, backfillID ID) error
+
+	// FindTaskRevisions returns the revisions of the script of a task, oldest first.
+	FindTaskRevisions(ctx context.Context, taskID ID) ([]*TaskRevision, error)
+
+	// FindTaskRevision returns a single revision of the script of a task.
+	FindTaskRevision(ctx context.Context, taskID ID, revision int64) (*TaskRevision, error)
+
+	// RollbackTask restores the script of a previous revision of a task, which is recorded as a new revision.
+	RollbackTask(ctx context.Context, taskID ID, revision int64) (*Task, error)
 }
 
 // TaskCreate is the set of values to create a task.
@@ -100,6 +398,8 @@ type TaskCreate struct {
 	OrganizationID ID     `json:"orgID,omitempty"`
 	Organization   string `json:"org,omitempty"`
 	Token          string `json:"token,omitempty"`
+
+	NotificationRules []TaskNotificationRule `json:"notificationRules,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
@@ -111,6 +411,15 @@ func (t TaskCreate) Validate() error {
 	case t.Status != "" && t.Status != TaskStatusActive && t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", t.Status)
 	}
+	return validateNotificationRules(t.NotificationRules)
+}
+
+func validateNotificationRules(rules []TaskNotificationRule) error {
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -123,6 +432,9 @@ type TaskUpdate struct {
 
 	// Optional token override.
 	Token string `json:"token,omitempty"`
+
+	// NotificationRules, if set, replace the notification rules of the task.
+	NotificationRules *[]TaskNotificationRule `json:"notificationRules,omitempty"`
 }
 
 func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
@@ -148,6 +460,8 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Retry int64 `json:"retry,omitempty"`
 
 		Token string `json:"token,omitempty"`
+
+		NotificationRules *[]TaskNotificationRule `json:"notificationRules,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &jo); err != nil {
@@ -162,6 +476,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	t.Flux = jo.Flux
 	t.Status = jo.Status
 	t.Token = jo.Token
+	t.NotificationRules = jo.NotificationRules
 
 	return nil
 }
@@ -186,6 +501,8 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 		Retry int64 `json:"retry,omitempty"`
 
 		Token string `json:"token,omitempty"`
+
+		NotificationRules *[]TaskNotificationRule `json:"notificationRules,omitempty"`
 	}{}
 	jo.Name = t.Options.Name
 	jo.Cron = t.Options.Cron
@@ -196,6 +513,7 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 	jo.Flux = t.Flux
 	jo.Status = t.Status
 	jo.Token = t.Token
+	jo.NotificationRules = t.NotificationRules
 	return json.Marshal(jo)
 }
 
@@ -203,10 +521,12 @@ func (t TaskUpdate) Validate() error {
 	switch {
 	case t.Options.Every != 0 && t.Options.Cron != "":
 		return errors.New("cannot specify both every and cron")
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero() && t.Token == "":
+	case t.Flux == nil && t.Status == nil && t.Options.IsZero() && t.Token == "" && t.NotificationRules == nil:
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
+	case t.NotificationRules != nil:
+		return validateNotificationRules(*t.NotificationRules)
 	}
 	return nil
 }
@@ -318,6 +638,9 @@ type TaskFilter struct {
 	Organization   string
 	User           *ID
 	Limit          int
+
+	// DependsOn restricts the results to the tasks which depend on the task with this ID.
+	DependsOn *ID
 }
 
 // RunFilter represents a set of filters that restrict the returned results
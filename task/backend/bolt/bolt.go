@@ -9,6 +9,9 @@
 //    bucket(/tasks/v1/name_by_task_id) key(:task_id) -> The user-supplied name of the script.
 //    bucket(/tasks/v1/run_ids) -> Counter for run IDs
 //    bucket(/tasks/v1/orgs).bucket(:org_id) key(:task_id) -> Empty content; presence of :task_id allows for lookup from org to tasks.
+//    bucket(/tasks/v1/lease_owners) key(:owner) -> Big-endian int64 Unix timestamp at which the owner's registration expires.
+//    bucket(/tasks/v1/task_revisions).bucket(:task_id) key(:revision) -> JSON encoded backend.StoreTaskRevision,
+//                                    keyed by the big-endian int64 revision number.
 // Note that task IDs are stored big-endian uint64s for sorting purposes,
 // but presented to the users with leading 0-bytes stripped.
 // Like other components of the system, IDs presented to users may be `0f12` rather than `f12`.
@@ -16,6 +19,8 @@ package bolt
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -54,12 +59,14 @@ type Store struct {
 const basePath = "/tasks/v1/"
 
 var (
-	tasksPath    = []byte(basePath + "tasks")
-	orgsPath     = []byte(basePath + "orgs")
-	taskMetaPath = []byte(basePath + "task_meta")
-	orgByTaskID  = []byte(basePath + "org_by_task_id")
-	nameByTaskID = []byte(basePath + "name_by_task_id")
-	runIDs       = []byte(basePath + "run_ids")
+	tasksPath     = []byte(basePath + "tasks")
+	orgsPath      = []byte(basePath + "orgs")
+	taskMetaPath  = []byte(basePath + "task_meta")
+	orgByTaskID   = []byte(basePath + "org_by_task_id")
+	nameByTaskID  = []byte(basePath + "name_by_task_id")
+	runIDs        = []byte(basePath + "run_ids")
+	leaseOwners   = []byte(basePath + "lease_owners")
+	revisionsPath = []byte(basePath + "task_revisions")
 )
 
 // Option is a optional configuration for the store.
@@ -84,7 +91,7 @@ func New(db *bolt.DB, rootBucket string, opts ...Option) (*Store, error) {
 		// create the buckets inside the root
 		for _, b := range [][]byte{
 			tasksPath, orgsPath, taskMetaPath,
-			orgByTaskID, nameByTaskID, runIDs,
+			orgByTaskID, nameByTaskID, runIDs, leaseOwners, revisionsPath,
 		} {
 			_, err := root.CreateBucketIfNotExists(b)
 			if err != nil {
@@ -109,6 +116,9 @@ func (s *Store) CreateTask(ctx context.Context, req backend.CreateTaskRequest) (
 	if err != nil {
 		return platform.InvalidID(), err
 	}
+	if err := backend.StoreValidator.Dependencies(ctx, s, platform.InvalidID(), req.Org, o); err != nil {
+		return platform.InvalidID(), err
+	}
 	// Get ID
 	id := s.idGen.ID()
 	err = s.db.Update(func(tx *bolt.Tx) error {
@@ -161,7 +171,16 @@ func (s *Store) CreateTask(ctx context.Context, req backend.CreateTaskRequest) (
 			return err
 		}
 		metaB := b.Bucket(taskMetaPath)
-		return metaB.Put(encodedID, stmBytes)
+		if err := metaB.Put(encodedID, stmBytes); err != nil {
+			return err
+		}
+
+		return putRevisions(b, encodedID, backend.StoreTaskRevision{
+			Revision:  stm.Revision,
+			Script:    req.Script,
+			UpdatedBy: req.UserID,
+			UpdatedAt: stm.CreatedAt,
+		})
 	})
 
 	if err != nil {
@@ -178,6 +197,17 @@ func (s *Store) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (
 		return res, err
 	}
 
+	if req.Script != "" {
+		// The new script may change the dependencies of the task.
+		t, err := s.FindTaskByID(ctx, req.ID)
+		if err != nil {
+			return res, err
+		}
+		if err := backend.StoreValidator.Dependencies(ctx, s, req.ID, t.Org, op); err != nil {
+			return res, err
+		}
+	}
+
 	encodedID, err := req.ID.Encode()
 	if err != nil {
 		return res, err
@@ -239,12 +269,25 @@ func (s *Store) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (
 		stm.UpdatedAt = time.Now().Unix()
 		res.OldStatus = backend.TaskStatus(stm.Status)
 
+		if newScript != res.OldScript {
+			stm.SetDependsOn(op)
+			stm.SetTrigger(op)
+
+			revs := stm.NextRevisions(res.OldScript, newScript, req.UserID, stm.UpdatedAt)
+			if err := putRevisions(b, encodedID, revs...); err != nil {
+				return err
+			}
+		}
+
 		if req.Status != "" {
 			stm.Status = string(req.Status)
 		}
 		if req.AuthorizationID.Valid() {
 			stm.AuthorizationID = uint64(req.AuthorizationID)
 		}
+		if req.NotificationRules != nil {
+			stm.NotificationRules = *req.NotificationRules
+		}
 		stmBytes, err = stm.Marshal()
 		if err != nil {
 			return err
@@ -529,6 +572,9 @@ func (s *Store) DeleteTask(ctx context.Context, id platform.ID) (deleted bool, e
 		if err := b.Bucket(nameByTaskID).Delete(encodedID); err != nil {
 			return err
 		}
+		if err := deleteRevisions(b, encodedID); err != nil {
+			return err
+		}
 
 		org := b.Bucket(orgByTaskID).Get(encodedID)
 		if len(org) > 0 {
@@ -620,6 +666,49 @@ func (s *Store) FinishRun(ctx context.Context, taskID, runID platform.ID) error
 	})
 }
 
+// RetryRun replaces runID in the list of running tasks with a new run for the same `now`.
+func (s *Store) RetryRun(ctx context.Context, taskID, runID platform.ID) (backend.QueuedRun, error) {
+	var qr backend.QueuedRun
+
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return qr, err
+	}
+
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		stmBytes := b.Bucket(taskMetaPath).Get(encodedID)
+		if stmBytes == nil {
+			return backend.ErrTaskNotFound
+		}
+
+		var stm backend.StoreTaskMeta
+		if err := stm.Unmarshal(stmBytes); err != nil {
+			return err
+		}
+
+		qr, err = stm.RetryRun(runID, func() (platform.ID, error) {
+			return s.idGen.ID(), nil
+		})
+		if err == backend.ErrRunNotFound {
+			return ErrRunNotFound
+		} else if err != nil {
+			return err
+		}
+		qr.TaskID = taskID
+
+		stmBytes, err = stm.Marshal()
+		if err != nil {
+			return err
+		}
+		return b.Bucket(taskMetaPath).Put(encodedID, stmBytes)
+	}); err != nil {
+		return backend.QueuedRun{}, err
+	}
+
+	return qr, nil
+}
+
 func (s *Store) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, start, end, requestedAt int64) (*backend.StoreTaskMetaManualRun, error) {
 	encodedID, err := taskID.Encode()
 	if err != nil {
@@ -652,6 +741,399 @@ func (s *Store) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, star
 	return mRun, nil
 }
 
+// Backfill enqueues runs for each of the task's schedules from start to end, as a backfill.
+func (s *Store) Backfill(_ context.Context, taskID platform.ID, start, end, requestedAt int64) (*backend.StoreTaskMetaManualRun, error) {
+	var mRun *backend.StoreTaskMetaManualRun
+	err := s.updateTaskMeta(taskID, func(stm *backend.StoreTaskMeta) (err error) {
+		mRun, err = stm.Backfill(start, end, requestedAt, func() (platform.ID, error) { return s.idGen.ID(), nil })
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mRun, nil
+}
+
+// SetBackfillPaused pauses or resumes a backfill of the task.
+func (s *Store) SetBackfillPaused(_ context.Context, taskID, backfillID platform.ID, paused bool) (*backend.StoreTaskMetaManualRun, error) {
+	var mRun *backend.StoreTaskMetaManualRun
+	err := s.updateTaskMeta(taskID, func(stm *backend.StoreTaskMeta) (err error) {
+		mRun, err = stm.SetBackfillPaused(backfillID, paused)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mRun, nil
+}
+
+// CancelBackfill removes a backfill of the task.
+func (s *Store) CancelBackfill(_ context.Context, taskID, backfillID platform.ID) (*backend.StoreTaskMetaManualRun, error) {
+	var mRun *backend.StoreTaskMetaManualRun
+	err := s.updateTaskMeta(taskID, func(stm *backend.StoreTaskMeta) (err error) {
+		mRun, err = stm.CancelBackfill(backfillID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mRun, nil
+}
+
+// QueueTriggeredRun requests a run for now of the given task, after points were written to the bucket of its trigger.
+func (s *Store) QueueTriggeredRun(_ context.Context, taskID platform.ID, now, start, stop int64) error {
+	requestedAt := time.Now().Unix()
+	return s.updateTaskMeta(taskID, func(stm *backend.StoreTaskMeta) error {
+		return stm.QueueTriggeredRun(now, start, stop, requestedAt)
+	})
+}
+
+// FindDependentTasks returns the IDs of the tasks which depend on the given task.
+func (s *Store) FindDependentTasks(_ context.Context, taskID platform.ID) ([]platform.ID, error) {
+	var ids []platform.ID
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Bucket(taskMetaPath).ForEach(func(k, v []byte) error {
+			var stm backend.StoreTaskMeta
+			if err := stm.Unmarshal(v); err != nil {
+				return err
+			}
+			if !stm.DependsOnTask(taskID) {
+				return nil
+			}
+
+			var id platform.ID
+			if err := id.Decode(k); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// QueueDependentRuns requests a run for now of each active task which depends on the given task.
+func (s *Store) QueueDependentRuns(_ context.Context, taskID platform.ID, now int64) ([]platform.ID, error) {
+	requestedAt := time.Now().Unix()
+
+	var ids []platform.ID
+	var firstErr error
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket).Bucket(taskMetaPath)
+
+		// Collect the updated metas first, as the bucket can't be modified while iterating it.
+		updated := make(map[string][]byte)
+		if err := b.ForEach(func(k, v []byte) error {
+			var stm backend.StoreTaskMeta
+			if err := stm.Unmarshal(v); err != nil {
+				return err
+			}
+			if stm.Status != string(backend.TaskActive) || !stm.DependsOnTask(taskID) {
+				return nil
+			}
+
+			queued, err := stm.QueueDependentRun(now, requestedAt)
+			if err != nil {
+				// Don't let one dependent task prevent the runs of the others.
+				if firstErr == nil {
+					firstErr = err
+				}
+				return nil
+			}
+			if !queued {
+				return nil
+			}
+
+			var id platform.ID
+			if err := id.Decode(k); err != nil {
+				return err
+			}
+			stmBytes, err := stm.Marshal()
+			if err != nil {
+				return err
+			}
+			updated[string(k)] = stmBytes
+			ids = append(ids, id)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for k, stmBytes := range updated {
+			if err := b.Put([]byte(k), stmBytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, firstErr
+}
+
+// LeaseTask acquires or renews the lease of owner on the task.
+func (s *Store) LeaseTask(ctx context.Context, taskID platform.ID, owner string, now, expiresAt int64) (*backend.StoreTask, *backend.StoreTaskMeta, error) {
+	err := s.updateTaskMeta(taskID, func(stm *backend.StoreTaskMeta) error {
+		return stm.Lease(owner, now, expiresAt)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.FindTaskByIDWithMeta(ctx, taskID)
+}
+
+// LeaseTasks acquires or renews the leases of owner on the tasks in a single transaction,
+// and returns the tasks which were leased.
+func (s *Store) LeaseTasks(_ context.Context, taskIDs []platform.ID, owner string, now, expiresAt int64) ([]backend.StoreTaskWithMeta, error) {
+	leased := make([]backend.StoreTaskWithMeta, 0, len(taskIDs))
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		mb := b.Bucket(taskMetaPath)
+
+		for _, id := range taskIDs {
+			encodedID, err := id.Encode()
+			if err != nil {
+				return err
+			}
+
+			scriptBytes := b.Bucket(tasksPath).Get(encodedID)
+			stmBytes := mb.Get(encodedID)
+			if scriptBytes == nil || stmBytes == nil {
+				continue
+			}
+
+			var stm backend.StoreTaskMeta
+			if err := stm.Unmarshal(stmBytes); err != nil {
+				return err
+			}
+			if err := stm.Lease(owner, now, expiresAt); err != nil {
+				if err == backend.ErrTaskLeased {
+					continue
+				}
+				return err
+			}
+			if stmBytes, err = stm.Marshal(); err != nil {
+				return err
+			}
+			if err := mb.Put(encodedID, stmBytes); err != nil {
+				return err
+			}
+
+			var orgID platform.ID
+			if err := orgID.Decode(b.Bucket(orgByTaskID).Get(encodedID)); err != nil {
+				return err
+			}
+			if stm.LatestCompleted < s.minLatestCompleted {
+				stm.LatestCompleted = s.minLatestCompleted
+				stm.AlignLatestCompleted()
+			}
+
+			// Copy everything so we don't hold a stale reference to a bolt-maintained byte slice.
+			leased = append(leased, backend.StoreTaskWithMeta{
+				Task: backend.StoreTask{
+					ID:     id,
+					Org:    orgID,
+					Name:   string(b.Bucket(nameByTaskID).Get(encodedID)),
+					Script: string(scriptBytes),
+				},
+				Meta: stm,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leased, nil
+}
+
+// ReleaseTaskLease releases the lease on the task, if it is held by owner.
+func (s *Store) ReleaseTaskLease(_ context.Context, taskID platform.ID, owner string) error {
+	err := s.updateTaskMeta(taskID, func(stm *backend.StoreTaskMeta) error {
+		if !stm.ReleaseLease(owner) {
+			return errLeaseNotHeld
+		}
+		return nil
+	})
+	if err == errLeaseNotHeld {
+		return nil
+	}
+	return err
+}
+
+// errLeaseNotHeld is used to skip saving the meta of a task whose lease isn't held by the releasing owner.
+var errLeaseNotHeld = errors.New("lease not held")
+
+// RegisterLeaseOwner records when owner's registration expires, and returns the owners which have not expired.
+func (s *Store) RegisterLeaseOwner(_ context.Context, owner string, now, expiresAt int64) ([]string, error) {
+	var owners []string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket).Bucket(leaseOwners)
+
+		exp := make([]byte, 8)
+		binary.BigEndian.PutUint64(exp, uint64(expiresAt))
+		if err := b.Put([]byte(owner), exp); err != nil {
+			return err
+		}
+
+		// Keys are iterated in sorted order.
+		var expired [][]byte
+		if err := b.ForEach(func(k, v []byte) error {
+			if len(v) != 8 || int64(binary.BigEndian.Uint64(v)) <= now {
+				expired = append(expired, append([]byte(nil), k...))
+				return nil
+			}
+			owners = append(owners, string(k))
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// ListTaskRevisions returns the revisions of the task's script, oldest first.
+func (s *Store) ListTaskRevisions(_ context.Context, taskID platform.ID) ([]backend.StoreTaskRevision, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var revs []backend.StoreTaskRevision
+	err = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b.Bucket(tasksPath).Get(encodedID) == nil {
+			return backend.ErrTaskNotFound
+		}
+
+		rb := b.Bucket(revisionsPath).Bucket(encodedID)
+		if rb == nil {
+			return nil
+		}
+		// Keys are big-endian revision numbers, so they are iterated in order.
+		return rb.ForEach(func(_, v []byte) error {
+			var rev backend.StoreTaskRevision
+			if err := json.Unmarshal(v, &rev); err != nil {
+				return err
+			}
+			revs = append(revs, rev)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revs, nil
+}
+
+// FindTaskRevision returns the given revision of the task's script.
+func (s *Store) FindTaskRevision(_ context.Context, taskID platform.ID, revision int64) (*backend.StoreTaskRevision, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var rev backend.StoreTaskRevision
+	err = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if b.Bucket(tasksPath).Get(encodedID) == nil {
+			return backend.ErrTaskNotFound
+		}
+
+		rb := b.Bucket(revisionsPath).Bucket(encodedID)
+		if rb == nil {
+			return backend.ErrRevisionNotFound
+		}
+		v := rb.Get(encodeRevision(revision))
+		if v == nil {
+			return backend.ErrRevisionNotFound
+		}
+		return json.Unmarshal(v, &rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// putRevisions stores revs in the revisions bucket of the task with the given encoded ID, within root bucket b.
+func putRevisions(b *bolt.Bucket, encodedID []byte, revs ...backend.StoreTaskRevision) error {
+	rb, err := b.Bucket(revisionsPath).CreateBucketIfNotExists(encodedID)
+	if err != nil {
+		return err
+	}
+
+	for _, rev := range revs {
+		v, err := json.Marshal(rev)
+		if err != nil {
+			return err
+		}
+		if err := rb.Put(encodeRevision(rev.Revision), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteRevisions removes the revisions bucket of the task with the given encoded ID, within root bucket b.
+func deleteRevisions(b *bolt.Bucket, encodedID []byte) error {
+	if err := b.Bucket(revisionsPath).DeleteBucket(encodedID); err != nil && err != bolt.ErrBucketNotFound {
+		return err
+	}
+	return nil
+}
+
+func encodeRevision(revision int64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(revision))
+	return k
+}
+
+// updateTaskMeta applies fn to the meta of the task in a single transaction.
+// The meta is only saved if fn returns no error.
+func (s *Store) updateTaskMeta(taskID platform.ID, fn func(*backend.StoreTaskMeta) error) error {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket).Bucket(taskMetaPath)
+		stmBytes := b.Get(encodedID)
+		if stmBytes == nil {
+			return backend.ErrTaskNotFound
+		}
+
+		var stm backend.StoreTaskMeta
+		if err := stm.Unmarshal(stmBytes); err != nil {
+			return err
+		}
+		if err := fn(&stm); err != nil {
+			return err
+		}
+
+		stmBytes, err := stm.Marshal()
+		if err != nil {
+			return err
+		}
+		return b.Put(encodedID, stmBytes)
+	})
+}
+
 // Close closes the store
 func (s *Store) Close() error {
 	return s.db.Close()
@@ -694,6 +1176,9 @@ func (s *Store) DeleteOrg(ctx context.Context, id platform.ID) error {
 			if err := b.Bucket(nameByTaskID).Delete(k); err != nil {
 				return err
 			}
+			if err := deleteRevisions(b, k); err != nil {
+				return err
+			}
 		}
 		// check for cancelation one last time before we return
 		select {
@@ -3,12 +3,25 @@ package coordinator
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/task/backend"
 	"go.uber.org/zap"
 )
 
+// DefaultLeaseDuration is how long a coordinator's leases on its tasks last, unless renewed.
+// Leases are renewed three times per lease duration,
+// so the tasks of a coordinator which stops renewing its leases are taken over within about a lease duration.
+const DefaultLeaseDuration = 30 * time.Second
+
+// Coordinator claims tasks for its scheduler as they are created, updated and deleted.
+//
+// Coordinators sharing a store split the active tasks among themselves by leasing them in the store.
+// Each coordinator registers itself as a lease owner, holds leases on about an equal share of the active tasks,
+// and takes over the tasks of coordinators whose leases expire.
 type Coordinator struct {
 	backend.Store
 
@@ -16,71 +29,345 @@ type Coordinator struct {
 	sch    backend.Scheduler
 
 	limit int
+
+	owner         string
+	leaseDuration time.Duration
+
+	mu sync.Mutex
+	// claimed maps the IDs of the tasks claimed for sch to the UpdatedAt of the meta they were last claimed or updated with.
+	claimed map[platform.ID]int64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 type Option func(*Coordinator)
 
+// WithLimit sets the maximum number of tasks the coordinator leases.
 func WithLimit(i int) Option {
 	return func(c *Coordinator) {
 		c.limit = i
 	}
 }
 
+// WithLeaseOwner sets the name the coordinator leases tasks as.
+// The name must be unique among the coordinators sharing a store,
+// and stable across restarts so that a restarted coordinator takes its unexpired leases back.
+// By default, a random name is used.
+func WithLeaseOwner(owner string) Option {
+	return func(c *Coordinator) {
+		c.owner = owner
+	}
+}
+
+// WithLeaseDuration sets how long the coordinator's leases last, unless renewed.
+// As leases expire on Unix timestamps, the duration should be at least a few seconds.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(c *Coordinator) {
+		c.leaseDuration = d
+	}
+}
+
 func New(logger *zap.Logger, scheduler backend.Scheduler, st backend.Store, opts ...Option) *Coordinator {
 	c := &Coordinator{
-		logger: logger,
-		sch:    scheduler,
-		Store:  st,
-		limit:  1000,
+		logger:        logger,
+		sch:           scheduler,
+		Store:         st,
+		limit:         1000,
+		leaseDuration: DefaultLeaseDuration,
+		claimed:       make(map[platform.ID]int64),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.owner == "" {
+		c.owner = snowflake.NewIDGenerator().ID().String()
+	}
 
-	go c.claimExistingTasks()
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.wg.Add(1)
+	go c.maintainLeases(ctx)
 
 	return c
 }
 
-// claimExistingTasks is called on startup to claim all tasks in the store.
-func (c *Coordinator) claimExistingTasks() {
-	tasks, err := c.Store.ListTasks(context.Background(), backend.TaskSearchParams{})
+// Stop stops leasing tasks, and releases the tasks leased by c, so that other coordinators can take them over.
+func (c *Coordinator) Stop() {
+	c.cancel()
+	c.wg.Wait()
+
+	c.mu.Lock()
+	ids := make([]platform.ID, 0, len(c.claimed))
+	for id := range c.claimed {
+		ids = append(ids, id)
+	}
+	c.mu.Unlock()
+
+	for _, id := range ids {
+		if err := c.release(context.Background(), id); err != nil {
+			c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+		}
+	}
+}
+
+// maintainLeases renews the leases of c on startup and then periodically, until ctx is canceled.
+func (c *Coordinator) maintainLeases(ctx context.Context) {
+	defer c.wg.Done()
+
+	c.renewLeases(ctx)
+
+	ticker := time.NewTicker(c.leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.renewLeases(ctx)
+		}
+	}
+}
+
+// renewLeases registers c as a lease owner, and leases its share of the active tasks in the store.
+// Leases held by c are renewed, leases above its share are released,
+// and tasks which are not leased or whose lease expired are leased until c holds its share.
+func (c *Coordinator) renewLeases(ctx context.Context) {
+	now := time.Now()
+	expiresAt := now.Add(c.leaseDuration).Unix()
+
+	owners, err := c.Store.RegisterLeaseOwner(ctx, c.owner, now.Unix(), expiresAt)
+	if err != nil {
+		c.logger.Error("failed to register lease owner", zap.Error(err))
+		return
+	}
+
+	active, err := c.listActiveTasks(ctx)
 	if err != nil {
 		c.logger.Error("failed to list tasks", zap.Error(err))
 		return
 	}
 
-	for len(tasks) > 0 {
-		for _, task := range tasks {
-			t := task // Copy to avoid mistaken closure around task value.
-			if err := c.sch.ClaimTask(&t.Task, &t.Meta); err != nil {
-				c.logger.Error("failed claim task", zap.Error(err))
-				continue
+	share := len(active)
+	if len(owners) > 1 {
+		share = (len(active) + len(owners) - 1) / len(owners)
+	}
+	if share > c.limit {
+		share = c.limit
+	}
+
+	// Renew the leases held by c first, so that it keeps the tasks it is already running.
+	var renew []platform.ID
+	isActive := make(map[platform.ID]bool, len(active))
+	for _, t := range active {
+		isActive[t.Task.ID] = true
+		if !c.isClaimed(t.Task.ID) {
+			continue
+		}
+
+		if len(renew) >= share {
+			if err := c.release(ctx, t.Task.ID); err != nil {
+				c.logger.Error("failed to release task", zap.String("task_id", t.Task.ID.String()), zap.Error(err))
+			}
+			continue
+		}
+		renew = append(renew, t.Task.ID)
+	}
+	held := c.renew(ctx, renew, now.Unix(), expiresAt)
+
+	// Release the tasks which were deleted or disabled through another coordinator.
+	c.mu.Lock()
+	var gone []platform.ID
+	for id := range c.claimed {
+		if !isActive[id] {
+			gone = append(gone, id)
+		}
+	}
+	c.mu.Unlock()
+	for _, id := range gone {
+		if err := c.release(ctx, id); err != nil {
+			c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+		}
+	}
+
+	// Take over unleased tasks and tasks whose lease expired, up to the share of c.
+	for _, t := range active {
+		if held >= share {
+			break
+		}
+		if c.isClaimed(t.Task.ID) || t.Meta.LeaseHeldByOther(c.owner, now.Unix()) {
+			continue
+		}
+
+		ok, err := c.claim(ctx, t.Task.ID, now.Unix(), expiresAt)
+		if err != nil {
+			c.logger.Error("failed to claim task", zap.String("task_id", t.Task.ID.String()), zap.Error(err))
+			continue
+		}
+		if ok {
+			held++
+		}
+	}
+}
+
+// listActiveTasks returns all the active tasks in the store, ordered by ID.
+func (c *Coordinator) listActiveTasks(ctx context.Context) ([]backend.StoreTaskWithMeta, error) {
+	var active []backend.StoreTaskWithMeta
+
+	tasks, err := c.Store.ListTasks(ctx, backend.TaskSearchParams{})
+	for err == nil && len(tasks) > 0 {
+		for _, t := range tasks {
+			if t.Meta.Status == string(backend.TaskActive) {
+				active = append(active, t)
 			}
 		}
-		tasks, err = c.Store.ListTasks(context.Background(), backend.TaskSearchParams{
+		tasks, err = c.Store.ListTasks(ctx, backend.TaskSearchParams{
 			After: tasks[len(tasks)-1].Task.ID,
 		})
-		if err != nil {
-			c.logger.Error("failed list additional tasks", zap.Error(err))
-			return
+	}
+	return active, err
+}
+
+func (c *Coordinator) isClaimed(id platform.ID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.claimed[id]
+	return ok
+}
+
+// claim leases the task with the given ID and claims it for the scheduler, unless it is already claimed,
+// and returns whether c holds the task afterwards.
+// A task leased by another coordinator is not claimed.
+// The scheduler resumes the runs in progress of a task taken over from another coordinator.
+func (c *Coordinator) claim(ctx context.Context, id platform.ID, now, expiresAt int64) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.claimed[id]; ok {
+		return true, nil
+	}
+
+	task, meta, err := c.Store.LeaseTask(ctx, id, c.owner, now, expiresAt)
+	if err != nil {
+		if err == backend.ErrTaskLeased {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if meta.Status != string(backend.TaskActive) {
+		return false, c.Store.ReleaseTaskLease(ctx, id, c.owner)
+	}
+
+	if err := c.sch.ClaimTask(task, meta); err != nil && err != backend.ErrTaskAlreadyClaimed {
+		if relErr := c.Store.ReleaseTaskLease(ctx, id, c.owner); relErr != nil {
+			c.logger.Error("failed to release task lease", zap.String("task_id", id.String()), zap.Error(relErr))
 		}
+		return false, err
 	}
+
+	c.claimed[id] = meta.UpdatedAt
+	return true, nil
 }
 
-func (c *Coordinator) CreateTask(ctx context.Context, req backend.CreateTaskRequest) (platform.ID, error) {
-	id, err := c.Store.CreateTask(ctx, req)
+// renew renews the leases on the tasks with the given IDs claimed by c in a single transaction,
+// and informs the scheduler of changes to the tasks made elsewhere.
+// Tasks whose lease can't be renewed, or which are no longer active, are released.
+// renew returns how many of the tasks c still holds.
+// If the store fails, the tasks are kept until the next renewal, as their leases have not expired yet.
+func (c *Coordinator) renew(ctx context.Context, ids []platform.ID, now, expiresAt int64) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	claimed := make([]platform.ID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := c.claimed[id]; ok {
+			claimed = append(claimed, id)
+		}
+	}
+	if len(claimed) == 0 {
+		return 0
+	}
+
+	leased, err := c.Store.LeaseTasks(ctx, claimed, c.owner, now, expiresAt)
 	if err != nil {
-		return id, err
+		c.logger.Error("failed to renew task leases", zap.Error(err))
+		return len(claimed)
+	}
+	byID := make(map[platform.ID]*backend.StoreTaskWithMeta, len(leased))
+	for i := range leased {
+		byID[leased[i].Task.ID] = &leased[i]
 	}
 
-	task, meta, err := c.Store.FindTaskByIDWithMeta(ctx, id)
+	held := 0
+	for _, id := range claimed {
+		if err := c.renewed(ctx, id, byID[id]); err != nil {
+			c.logger.Error("failed to renew task lease", zap.String("task_id", id.String()), zap.Error(err))
+		}
+		if _, ok := c.claimed[id]; ok {
+			held++
+		}
+	}
+	return held
+}
+
+// renewed informs the scheduler of the task with the given ID claimed by c, whose lease was renewed as t.
+// If t is nil because the lease wasn't renewed, or the task is no longer active, the task is released.
+// c.mu must be held.
+func (c *Coordinator) renewed(ctx context.Context, id platform.ID, t *backend.StoreTaskWithMeta) error {
+	if t == nil || t.Meta.Status != string(backend.TaskActive) {
+		// Another coordinator has taken over the task, it was deleted, or it must not run anymore.
+		delete(c.claimed, id)
+		if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+			return err
+		}
+		if t == nil {
+			return nil
+		}
+		return c.Store.ReleaseTaskLease(ctx, id, c.owner)
+	}
+
+	if t.Meta.UpdatedAt != c.claimed[id] {
+		// The task was updated through another coordinator.
+		if err := c.sch.UpdateTask(&t.Task, &t.Meta); err != nil {
+			return err
+		}
+		c.claimed[id] = t.Meta.UpdatedAt
+	}
+
+	// Pick up manual runs requested through other coordinators.
+	return c.sch.UpdateQueue(id, &t.Meta)
+}
+
+// release releases the task with the given ID from the scheduler if it is claimed by c, and releases the lease of c on it.
+// Releasing a task which no longer exists is not an error.
+func (c *Coordinator) release(ctx context.Context, id platform.ID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.claimed[id]; ok {
+		delete(c.claimed, id)
+		if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+			return err
+		}
+	}
+
+	if err := c.Store.ReleaseTaskLease(ctx, id, c.owner); err != nil && err != backend.ErrTaskNotFound {
+		return err
+	}
+	return nil
+}
+
+func (c *Coordinator) CreateTask(ctx context.Context, req backend.CreateTaskRequest) (platform.ID, error) {
+	id, err := c.Store.CreateTask(ctx, req)
 	if err != nil {
 		return id, err
 	}
 
-	if err := c.sch.ClaimTask(task, meta); err != nil {
+	now := time.Now()
+	if _, err := c.claim(ctx, id, now.Unix(), now.Add(c.leaseDuration).Unix()); err != nil {
 		_, delErr := c.Store.DeleteTask(ctx, id)
 		if delErr != nil {
 			return id, fmt.Errorf("schedule task failed: %s\n\tcleanup also failed: %s", err, delErr)
@@ -104,18 +391,26 @@ func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequ
 
 	// If disabling the task, do so before modifying the script.
 	if req.Status == backend.TaskInactive && res.OldStatus != backend.TaskInactive {
-		if err := c.sch.ReleaseTask(req.ID); err != nil && err != backend.ErrTaskNotClaimed {
+		if err := c.release(ctx, req.ID); err != nil {
 			return res, err
 		}
 	}
 
-	if err := c.sch.UpdateTask(task, meta); err != nil && err != backend.ErrTaskNotClaimed {
-		return res, err
+	// If another coordinator holds the task, it picks up the changes when renewing its lease.
+	c.mu.Lock()
+	if _, ok := c.claimed[req.ID]; ok {
+		if err := c.sch.UpdateTask(task, meta); err != nil && err != backend.ErrTaskNotClaimed {
+			c.mu.Unlock()
+			return res, err
+		}
+		c.claimed[req.ID] = meta.UpdatedAt
 	}
+	c.mu.Unlock()
 
 	// If enabling the task, claim it after modifying the script.
 	if req.Status == backend.TaskActive {
-		if err := c.sch.ClaimTask(task, meta); err != nil && err != backend.ErrTaskAlreadyClaimed {
+		now := time.Now()
+		if _, err := c.claim(ctx, req.ID, now.Unix(), now.Add(c.leaseDuration).Unix()); err != nil {
 			return res, err
 		}
 	}
@@ -124,7 +419,7 @@ func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequ
 }
 
 func (c *Coordinator) DeleteTask(ctx context.Context, id platform.ID) (deleted bool, err error) {
-	if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+	if err := c.release(ctx, id); err != nil {
 		return false, err
 	}
 
@@ -140,7 +435,7 @@ func (c *Coordinator) DeleteOrg(ctx context.Context, orgID platform.ID) error {
 	}
 
 	for _, orgTask := range orgTasks {
-		if err := c.sch.ReleaseTask(orgTask.Task.ID); err != nil {
+		if err := c.release(ctx, orgTask.Task.ID); err != nil {
 			return err
 		}
 	}
@@ -151,3 +446,45 @@ func (c *Coordinator) DeleteOrg(ctx context.Context, orgID platform.ID) error {
 func (c *Coordinator) CancelRun(ctx context.Context, taskID, runID platform.ID) error {
 	return c.sch.CancelRun(ctx, taskID, runID)
 }
+
+func (c *Coordinator) Backfill(ctx context.Context, taskID platform.ID, start, end, requestedAt int64) (*backend.StoreTaskMetaManualRun, error) {
+	mr, err := c.Store.Backfill(ctx, taskID, start, end, requestedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return mr, c.updateQueue(ctx, taskID)
+}
+
+func (c *Coordinator) SetBackfillPaused(ctx context.Context, taskID, backfillID platform.ID, paused bool) (*backend.StoreTaskMetaManualRun, error) {
+	mr, err := c.Store.SetBackfillPaused(ctx, taskID, backfillID, paused)
+	if err != nil {
+		return nil, err
+	}
+
+	return mr, c.updateQueue(ctx, taskID)
+}
+
+func (c *Coordinator) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) (*backend.StoreTaskMetaManualRun, error) {
+	mr, err := c.Store.CancelBackfill(ctx, taskID, backfillID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mr, c.updateQueue(ctx, taskID)
+}
+
+// updateQueue informs the scheduler of a change to the manual runs of a task,
+// unless the task is not claimed because it is inactive or held by another coordinator.
+// Another coordinator picks up the change when renewing its lease.
+func (c *Coordinator) updateQueue(ctx context.Context, taskID platform.ID) error {
+	meta, err := c.Store.FindTaskMetaByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sch.UpdateQueue(taskID, meta); err != nil && err != backend.ErrTaskNotClaimed {
+		return err
+	}
+	return nil
+}
@@ -192,3 +192,145 @@ func TestCoordinator_ClaimExistingTasks(t *testing.T) {
 		}
 	}
 }
+
+// waitFor fails the test if cond does not become true within a few lease renewals.
+func waitFor(t *testing.T, desc string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestCoordinator_SplitTasks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	st := backend.NewInMemStore()
+
+	const numTasks = 10
+	ids := make([]platform.ID, numTasks)
+	for i := range ids {
+		id, err := st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+
+	claimed := func(sched *mock.Scheduler) int {
+		n := 0
+		for _, id := range ids {
+			if sched.TaskFor(id) != nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	schedA := mock.NewScheduler()
+	coordA := coordinator.New(zaptest.NewLogger(t), schedA, st, coordinator.WithLeaseOwner("a"), coordinator.WithLeaseDuration(3*time.Second))
+	defer coordA.Stop()
+	waitFor(t, "a to claim all tasks", func() bool { return claimed(schedA) == numTasks })
+
+	// A second coordinator sharing the store takes over half the tasks.
+	schedB := mock.NewScheduler()
+	coordB := coordinator.New(zaptest.NewLogger(t), schedB, st, coordinator.WithLeaseOwner("b"), coordinator.WithLeaseDuration(3*time.Second))
+	waitFor(t, "a and b to split tasks", func() bool { return claimed(schedA) == numTasks/2 && claimed(schedB) == numTasks/2 })
+
+	for _, id := range ids {
+		if schedA.TaskFor(id) != nil && schedB.TaskFor(id) != nil {
+			t.Fatalf("task %s claimed by both coordinators", id)
+		}
+	}
+
+	// A task created through b is run by b.
+	id, err := coordB.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedB.TaskFor(id) == nil {
+		t.Fatal("expected task created through b to be claimed by b")
+	}
+	ids = append(ids, id)
+
+	// The tasks of a stopped coordinator are taken over.
+	coordB.Stop()
+	if n := claimed(schedB); n != 0 {
+		t.Fatalf("expected stopped coordinator to release its tasks, still has %d", n)
+	}
+	waitFor(t, "a to take over all tasks", func() bool { return claimed(schedA) == len(ids) })
+}
+
+func TestCoordinator_TakeOverExpiredLeases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	st := backend.NewInMemStore()
+
+	now := time.Now().Unix()
+	id, err := st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: now - 120})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a node which leased the task and started a run, and then died.
+	rc, err := st.CreateNextRun(context.Background(), id, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.RegisterLeaseOwner(context.Background(), "dead", now, now+2); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := st.LeaseTask(context.Background(), id, "dead", now, now+2); err != nil {
+		t.Fatal(err)
+	}
+
+	sched := mock.NewScheduler()
+	coord := coordinator.New(zaptest.NewLogger(t), sched, st, coordinator.WithLeaseOwner("a"), coordinator.WithLeaseDuration(3*time.Second))
+	defer coord.Stop()
+
+	if sched.TaskFor(id) != nil {
+		t.Fatal("expected task leased by another node not to be claimed")
+	}
+
+	waitFor(t, "expired lease to be taken over", func() bool { return sched.TaskFor(id) != nil })
+
+	// The run in progress on the dead node is handed to the scheduler to be picked up.
+	meta := sched.TaskMetaFor(id)
+	if meta.LeaseOwner != "a" {
+		t.Fatalf("expected task to be leased by a, got %q", meta.LeaseOwner)
+	}
+	if len(meta.CurrentlyRunning) != 1 || platform.ID(meta.CurrentlyRunning[0].RunID) != rc.Created.RunID {
+		t.Fatalf("expected run %s in progress, got %v", rc.Created.RunID, meta.CurrentlyRunning)
+	}
+}
+
+func TestCoordinator_ReclaimLeasesAfterRestart(t *testing.T) {
+	st := backend.NewInMemStore()
+
+	now := time.Now().Unix()
+	id, err := st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: now - 120})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a node which leased the task, and restarted before its lease expired.
+	if _, err := st.RegisterLeaseOwner(context.Background(), "a", now, now+60); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := st.LeaseTask(context.Background(), id, "a", now, now+60); err != nil {
+		t.Fatal(err)
+	}
+
+	sched := mock.NewScheduler()
+	coord := coordinator.New(zaptest.NewLogger(t), sched, st, coordinator.WithLeaseOwner("a"))
+	defer coord.Stop()
+
+	waitFor(t, "unexpired lease to be reclaimed", func() bool { return sched.TaskFor(id) != nil })
+}
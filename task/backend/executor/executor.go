@@ -4,7 +4,9 @@ package executor
 
 import (
 	"context"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/flux"
@@ -14,6 +16,7 @@ import (
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/options"
 	"go.uber.org/zap"
 )
 
@@ -136,7 +139,13 @@ func (p *syncRunPromise) finish(res *runResult, err error) {
 func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	spec, err := flux.Compile(p.ctx, p.t.Script, time.Unix(p.qr.Now, 0))
+	script, err := runScript(p.t.Script, p.qr)
+	if err != nil {
+		p.finish(nil, err)
+		return
+	}
+
+	spec, err := flux.Compile(p.ctx, script, time.Unix(p.qr.Now, 0))
 	if err != nil {
 		p.finish(nil, err)
 		return
@@ -157,16 +166,19 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	defer it.Release()
 
 	// Drain the result iterator.
+	var rows int64
 	for it.More() {
 		// Consume the full iterator so that we don't leak outstanding iterators.
 		res := it.Next()
-		if err := exhaustResultIterators(res); err != nil {
+		n, err := exhaustResultIterators(res)
+		if err != nil {
 			p.logger.Info("Error exhausting result iterator", zap.Error(err), zap.String("name", res.Name()))
 		}
+		rows += n
 	}
 
 	// Is it okay to assume it.Err will be set if the query context is canceled?
-	p.finish(&runResult{err: it.Err(), statistics: it.Statistics()}, nil)
+	p.finish(&runResult{err: it.Err(), retryable: isRetryable(it.Err()), statistics: withRowsWritten(it.Statistics(), rows)}, nil)
 }
 
 func (p *syncRunPromise) cancelOnContextDone(wg *sync.WaitGroup) {
@@ -210,7 +222,12 @@ func (e *asyncQueryServiceExecutor) Execute(ctx context.Context, run backend.Que
 		return nil, err
 	}
 
-	spec, err := flux.Compile(ctx, t.Script, time.Unix(run.Now, 0))
+	script, err := runScript(t.Script, run)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := flux.Compile(ctx, script, time.Unix(run.Now, 0))
 	if err != nil {
 		return nil, err
 	}
@@ -302,28 +319,32 @@ func (p *asyncRunPromise) followQuery(wg *sync.WaitGroup) {
 	case results, ok := <-p.q.Ready():
 		if !ok {
 			// Something went wrong with the flux. Set the error in the run result.
-			rr := &runResult{err: p.q.Err()}
+			rr := &runResult{err: p.q.Err(), retryable: isRetryable(p.q.Err())}
 			p.finish(rr, nil)
 			return
 		}
 
 		// Exhaust the results so we don't leave unfinished iterators around.
 		var wg sync.WaitGroup
+		var rows int64
 		wg.Add(len(results))
 		for _, res := range results {
 			r := res
 			go func() {
 				defer wg.Done()
-				if err := exhaustResultIterators(r); err != nil {
+				n, err := exhaustResultIterators(r)
+				if err != nil {
 					p.logger.Info("Error exhausting result iterator", zap.Error(err), zap.String("name", r.Name()))
 				}
+				atomic.AddInt64(&rows, n)
 			}()
 		}
 		wg.Wait()
 
 		// Otherwise, query was successful.
-		// TODO(mr): collect query statistics, once RunResult interface supports them?
-		p.finish(new(runResult), nil)
+		// The query statistics are only complete once the query is done; calling Done more than once is safe.
+		p.q.Done()
+		p.finish(&runResult{statistics: withRowsWritten(p.q.Statistics(), rows)}, nil)
 	}
 }
 
@@ -356,11 +377,63 @@ func (rr *runResult) Err() error                  { return rr.err }
 func (rr *runResult) IsRetryable() bool           { return rr.retryable }
 func (rr *runResult) Statistics() flux.Statistics { return rr.statistics }
 
-// exhaustResultIterators drains all the iterators from a flux query Result.
-func exhaustResultIterators(res flux.Result) error {
-	return res.Tables().Do(func(tbl flux.Table) error {
-		return tbl.Do(func(flux.ColReader) error {
+// runScript returns the script to execute for the run qr of the task with the given script.
+// For a run triggered by writes, the trigger option of the script is set to the time range of the written points.
+func runScript(script string, qr backend.QueuedRun) (string, error) {
+	if !qr.Triggered {
+		return script, nil
+	}
+
+	// The stop time of the range is exclusive, so the range ends just after the latest written point.
+	return options.SetTriggerRange(script, time.Unix(0, qr.TriggerStart), time.Unix(0, qr.TriggerStop+1))
+}
+
+// isRetryable reports whether a run which failed with the query error err may
+// succeed if it is retried. Only transient errors are retryable: unavailable
+// services and timeouts. Errors in the script, such as parse and compile errors,
+// or in its permissions are not.
+func isRetryable(err error) bool {
+	for err != nil {
+		if err == context.DeadlineExceeded {
+			return true
+		}
+		if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			return true
+		}
+
+		e, ok := err.(*influxdb.Error)
+		if !ok {
+			return false
+		}
+		if e.Code == influxdb.EUnavailable {
+			return true
+		}
+		err = e.Err
+	}
+	return false
+}
+
+// exhaustResultIterators drains all the iterators from a flux query Result,
+// and returns the number of rows drained.
+func exhaustResultIterators(res flux.Result) (int64, error) {
+	var rows int64
+	err := res.Tables().Do(func(tbl flux.Table) error {
+		return tbl.Do(func(cr flux.ColReader) error {
+			rows += int64(cr.Len())
 			return nil
 		})
 	})
+	return rows, err
+}
+
+// withRowsWritten adds the number of rows in the results of a run to the metadata of its query statistics.
+// A task writes the rows of its results with to(), which passes the rows it writes on as its own result.
+func withRowsWritten(stats flux.Statistics, rows int64) flux.Statistics {
+	md := make(flux.Metadata, len(stats.Metadata)+1)
+	for k, v := range stats.Metadata {
+		md[k] = v
+	}
+	md[backend.RowsWrittenMetadataKey] = []interface{}{rows}
+	stats.Metadata = md
+	return stats
 }
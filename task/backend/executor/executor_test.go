@@ -357,32 +357,46 @@ func testExecutorQuerySuccess(t *testing.T, fn createSysFn) {
 }
 
 func testExecutorQueryFailure(t *testing.T, fn createSysFn) {
-	sys := fn()
-	tc := createCreds(t, sys.i)
-	t.Run(sys.name+"/QueryFail", func(t *testing.T) {
-		t.Parallel()
-		script := fmt.Sprintf(fmtTestScript, t.Name())
-		tid, err := sys.st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: tc.OrgID, AuthorizationID: tc.AuthzID, Script: script})
-		if err != nil {
-			t.Fatal(err)
-		}
-		qr := backend.QueuedRun{TaskID: tid, RunID: platform.ID(1), Now: 123}
-		rp, err := sys.ex.Execute(context.Background(), qr)
-		if err != nil {
-			t.Fatal(err)
-		}
+	for _, tt := range []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "QueryFail", err: errors.New("forced error")},
+		{name: "QueryFailUnavailable", err: &platform.Error{Code: platform.EUnavailable, Msg: "forced error"}, retryable: true},
+		{name: "QueryFailDeadline", err: &platform.Error{Code: platform.EInternal, Err: context.DeadlineExceeded}, retryable: true},
+		{name: "QueryFailInvalid", err: &platform.Error{Code: platform.EInvalid, Msg: "forced error"}},
+	} {
+		tt := tt
+		sys := fn()
+		tc := createCreds(t, sys.i)
+		t.Run(sys.name+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+			script := fmt.Sprintf(fmtTestScript, t.Name())
+			tid, err := sys.st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: tc.OrgID, AuthorizationID: tc.AuthzID, Script: script})
+			if err != nil {
+				t.Fatal(err)
+			}
+			qr := backend.QueuedRun{TaskID: tid, RunID: platform.ID(1), Now: 123}
+			rp, err := sys.ex.Execute(context.Background(), qr)
+			if err != nil {
+				t.Fatal(err)
+			}
 
-		expErr := errors.New("forced error")
-		sys.svc.WaitForQueryLive(t, script)
-		sys.svc.FailQuery(script, expErr)
-		res, err := rp.Wait()
-		if err != nil {
-			t.Fatal(err)
-		}
-		if got := res.Err(); got != expErr {
-			t.Fatalf("expected error %v; got %v", expErr, got)
-		}
-	})
+			sys.svc.WaitForQueryLive(t, script)
+			sys.svc.FailQuery(script, tt.err)
+			res, err := rp.Wait()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.Err(); got != tt.err {
+				t.Fatalf("expected error %v; got %v", tt.err, got)
+			}
+			if res.IsRetryable() != tt.retryable {
+				t.Fatalf("expected query failure retryable to be %v", tt.retryable)
+			}
+		})
+	}
 }
 
 func testExecutorPromiseCancel(t *testing.T, fn createSysFn) {
@@ -3,7 +3,6 @@ package backend
 import (
 	"context"
 	"errors"
-	"fmt"
 	"sync"
 	"time"
 
@@ -48,6 +47,7 @@ func (r *runReaderWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, wh
 			TaskID:       rlb.Task.ID,
 			Status:       status.String(),
 			ScheduledFor: sf.Format(time.RFC3339),
+			Revision:     rlb.Revision,
 		}
 		if rlb.RequestedAt != 0 {
 			run.RequestedAt = time.Unix(rlb.RequestedAt, 0).UTC().Format(time.RFC3339)
@@ -64,20 +64,36 @@ func (r *runReaderWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, wh
 	return nil
 }
 
-func (r *runReaderWriter) AddRunLog(ctx context.Context, rlb RunLogBase, when time.Time, log string) error {
+func (r *runReaderWriter) AddRunLog(ctx context.Context, rlb RunLogBase, when time.Time, entry RunLogEntry) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	log = fmt.Sprintf("%s: %s", when.Format(time.RFC3339Nano), log)
 	ridStr := rlb.RunID.String()
 	existingRun, ok := r.byRunID[ridStr]
 	if !ok {
 		return ErrRunNotFound
 	}
-	sep := ""
-	if existingRun.Log != "" {
-		sep = "\n"
+	level := entry.Level
+	if level == "" {
+		level = platform.LogLevelInfo
 	}
-	existingRun.Log = platform.Log(string(existingRun.Log) + sep + log)
+	existingRun.Log = append(existingRun.Log, platform.Log{
+		RunID:   rlb.RunID,
+		Time:    when.Format(time.RFC3339Nano),
+		Level:   level,
+		Message: entry.Message,
+		Fields:  entry.Fields,
+	})
+	return nil
+}
+
+func (r *runReaderWriter) AddRunStatistics(ctx context.Context, rlb RunLogBase, when time.Time, stats platform.RunStatistics) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existingRun, ok := r.byRunID[rlb.RunID.String()]
+	if !ok {
+		return ErrRunNotFound
+	}
+	existingRun.Statistics = &stats
 	return nil
 }
 
@@ -149,17 +165,17 @@ func (r *runReaderWriter) ListLogs(ctx context.Context, orgID platform.ID, logFi
 			return nil, ErrRunNotFound
 		}
 		// TODO(mr): validate that task ID matches, if task is also set. Needs test.
-		return []platform.Log{run.Log}, nil
+		return append([]platform.Log(nil), run.Log...), nil
 	}
 
-	var logs []platform.Log
-	ot := orgtask{o: orgID, t: logFilter.Task}
-	for _, run := range r.byOrgTask[ot] {
-		logs = append(logs, run.Log)
+	runs := r.byOrgTask[orgtask{o: orgID, t: logFilter.Task}]
+	if len(runs) == 0 {
+		return nil, ErrNoRunsFound
 	}
 
-	if len(logs) == 0 {
-		return nil, ErrNoRunsFound
+	var logs []platform.Log
+	for _, run := range runs {
+		logs = append(logs, run.Log...)
 	}
 
 	return logs, nil
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,22 +26,33 @@ type inmem struct {
 	tasks []StoreTask
 
 	meta map[platform.ID]StoreTaskMeta
+
+	// revisions holds the revisions of each task's script, oldest first.
+	revisions map[platform.ID][]StoreTaskRevision
+
+	// leaseOwners maps the names of registered lease owners to when their registration expires.
+	leaseOwners map[string]int64
 }
 
 // NewInMemStore returns a new in-memory store.
 // This store is not designed to be efficient, it is here for testing purposes.
 func NewInMemStore() Store {
 	return &inmem{
-		idgen: snowflake.NewIDGenerator(),
-		meta:  map[platform.ID]StoreTaskMeta{},
+		idgen:       snowflake.NewIDGenerator(),
+		meta:        map[platform.ID]StoreTaskMeta{},
+		revisions:   map[platform.ID][]StoreTaskRevision{},
+		leaseOwners: map[string]int64{},
 	}
 }
 
-func (s *inmem) CreateTask(_ context.Context, req CreateTaskRequest) (platform.ID, error) {
+func (s *inmem) CreateTask(ctx context.Context, req CreateTaskRequest) (platform.ID, error) {
 	o, err := StoreValidator.CreateArgs(req)
 	if err != nil {
 		return platform.InvalidID(), err
 	}
+	if err := StoreValidator.Dependencies(ctx, s, platform.InvalidID(), req.Org, o); err != nil {
+		return platform.InvalidID(), err
+	}
 
 	id := s.idgen.ID()
 
@@ -57,13 +69,15 @@ func (s *inmem) CreateTask(_ context.Context, req CreateTaskRequest) (platform.I
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	stm := NewStoreTaskMeta(req, o)
 	s.tasks = append(s.tasks, task)
-	s.meta[id] = NewStoreTaskMeta(req, o)
+	s.meta[id] = stm
+	s.revisions[id] = []StoreTaskRevision{{Revision: stm.Revision, Script: req.Script, UpdatedBy: req.UserID, UpdatedAt: stm.CreatedAt}}
 
 	return id, nil
 }
 
-func (s *inmem) UpdateTask(_ context.Context, req UpdateTaskRequest) (UpdateTaskResult, error) {
+func (s *inmem) UpdateTask(ctx context.Context, req UpdateTaskRequest) (UpdateTaskResult, error) {
 	var res UpdateTaskResult
 	op, err := StoreValidator.UpdateArgs(req)
 	if err != nil {
@@ -71,6 +85,18 @@ func (s *inmem) UpdateTask(_ context.Context, req UpdateTaskRequest) (UpdateTask
 	}
 	idStr := req.ID.String()
 
+	newScript := req.Script != ""
+	if newScript {
+		// The new script may change the dependencies of the task.
+		t, err := s.FindTaskByID(ctx, req.ID)
+		if err != nil {
+			return res, err
+		}
+		if err := StoreValidator.Dependencies(ctx, s, req.ID, t.Org, op); err != nil {
+			return res, err
+		}
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -120,6 +146,20 @@ func (s *inmem) UpdateTask(_ context.Context, req UpdateTaskRequest) (UpdateTask
 		stm.AuthorizationID = uint64(req.AuthorizationID)
 	}
 
+	if req.NotificationRules != nil {
+		stm.NotificationRules = *req.NotificationRules
+	}
+
+	if newScript {
+		stm.SetDependsOn(op)
+		stm.SetTrigger(op)
+	}
+
+	if res.NewTask.Script != res.OldScript {
+		revs := stm.NextRevisions(res.OldScript, res.NewTask.Script, req.UserID, stm.UpdatedAt)
+		s.revisions[req.ID] = append(s.revisions[req.ID], revs...)
+	}
+
 	s.meta[req.ID] = stm
 
 	res.NewMeta = stm
@@ -247,6 +287,7 @@ func (s *inmem) DeleteTask(_ context.Context, id platform.ID) (deleted bool, err
 	// Delete entry from slice.
 	s.tasks = append(s.tasks[:idx], s.tasks[idx+1:]...)
 	delete(s.meta, id)
+	delete(s.revisions, id)
 	return true, nil
 }
 
@@ -297,6 +338,28 @@ func (s *inmem) FinishRun(ctx context.Context, taskID, runID platform.ID) error
 	return nil
 }
 
+// RetryRun replaces runID in the list of running tasks with a new run for the same `now`.
+func (s *inmem) RetryRun(ctx context.Context, taskID, runID platform.ID) (QueuedRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return QueuedRun{}, errors.New("taskRunner not found")
+	}
+
+	qr, err := stm.RetryRun(runID, func() (platform.ID, error) {
+		return s.idgen.ID(), nil
+	})
+	if err != nil {
+		return QueuedRun{}, err
+	}
+	qr.TaskID = taskID
+
+	s.meta[taskID] = stm
+	return qr, nil
+}
+
 func (s *inmem) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, start, end, requestedAt int64) (*StoreTaskMetaManualRun, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -315,6 +378,243 @@ func (s *inmem) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, star
 	return mr, nil
 }
 
+func (s *inmem) Backfill(_ context.Context, taskID platform.ID, start, end, requestedAt int64) (*StoreTaskMetaManualRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	mr, err := stm.Backfill(start, end, requestedAt, func() (platform.ID, error) { return s.idgen.ID(), nil })
+	if err != nil {
+		return nil, err
+	}
+
+	s.meta[taskID] = stm
+	return mr, nil
+}
+
+func (s *inmem) SetBackfillPaused(_ context.Context, taskID, backfillID platform.ID, paused bool) (*StoreTaskMetaManualRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	mr, err := stm.SetBackfillPaused(backfillID, paused)
+	if err != nil {
+		return nil, err
+	}
+
+	s.meta[taskID] = stm
+	return mr, nil
+}
+
+func (s *inmem) CancelBackfill(_ context.Context, taskID, backfillID platform.ID) (*StoreTaskMetaManualRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	mr, err := stm.CancelBackfill(backfillID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.meta[taskID] = stm
+	return mr, nil
+}
+
+func (s *inmem) FindDependentTasks(_ context.Context, taskID platform.ID) ([]platform.ID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []platform.ID
+	for _, t := range s.tasks {
+		if stm := s.meta[t.ID]; stm.DependsOnTask(taskID) {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *inmem) QueueDependentRuns(_ context.Context, taskID platform.ID, now int64) ([]platform.ID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requestedAt := time.Now().Unix()
+
+	var ids []platform.ID
+	var firstErr error
+	for _, t := range s.tasks {
+		stm := s.meta[t.ID]
+		if stm.Status != string(TaskActive) || !stm.DependsOnTask(taskID) {
+			continue
+		}
+
+		queued, err := stm.QueueDependentRun(now, requestedAt)
+		if err != nil {
+			// Don't let one dependent task prevent the runs of the others.
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if queued {
+			s.meta[t.ID] = stm
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids, firstErr
+}
+
+func (s *inmem) QueueTriggeredRun(_ context.Context, taskID platform.ID, now, start, stop int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return ErrTaskNotFound
+	}
+
+	if err := stm.QueueTriggeredRun(now, start, stop, time.Now().Unix()); err != nil {
+		return err
+	}
+
+	s.meta[taskID] = stm
+	return nil
+}
+
+func (s *inmem) LeaseTask(_ context.Context, taskID platform.ID, owner string, now, expiresAt int64) (*StoreTask, *StoreTaskMeta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var task *StoreTask
+	for _, t := range s.tasks {
+		if t.ID == taskID {
+			// Return a copy of the task.
+			task = new(StoreTask)
+			*task = t
+			break
+		}
+	}
+	if task == nil {
+		return nil, nil, ErrTaskNotFound
+	}
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return nil, nil, errors.New("task meta not found")
+	}
+
+	if err := stm.Lease(owner, now, expiresAt); err != nil {
+		return nil, nil, err
+	}
+
+	s.meta[taskID] = stm
+	return task, &stm, nil
+}
+
+func (s *inmem) LeaseTasks(_ context.Context, taskIDs []platform.ID, owner string, now, expiresAt int64) ([]StoreTaskWithMeta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := make(map[platform.ID]StoreTask, len(taskIDs))
+	for _, t := range s.tasks {
+		tasks[t.ID] = t
+	}
+
+	leased := make([]StoreTaskWithMeta, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		task, ok := tasks[id]
+		if !ok {
+			continue
+		}
+		stm, ok := s.meta[id]
+		if !ok {
+			return nil, errors.New("task meta not found")
+		}
+
+		if err := stm.Lease(owner, now, expiresAt); err != nil {
+			if err == ErrTaskLeased {
+				continue
+			}
+			return nil, err
+		}
+
+		s.meta[id] = stm
+		leased = append(leased, StoreTaskWithMeta{Task: task, Meta: stm})
+	}
+	return leased, nil
+}
+
+func (s *inmem) ReleaseTaskLease(_ context.Context, taskID platform.ID, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return ErrTaskNotFound
+	}
+
+	if stm.ReleaseLease(owner) {
+		s.meta[taskID] = stm
+	}
+	return nil
+}
+
+func (s *inmem) RegisterLeaseOwner(_ context.Context, owner string, now, expiresAt int64) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leaseOwners[owner] = expiresAt
+
+	owners := make([]string, 0, len(s.leaseOwners))
+	for o, exp := range s.leaseOwners {
+		if exp <= now {
+			delete(s.leaseOwners, o)
+			continue
+		}
+		owners = append(owners, o)
+	}
+	sort.Strings(owners)
+	return owners, nil
+}
+
+func (s *inmem) ListTaskRevisions(_ context.Context, taskID platform.ID) ([]StoreTaskRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.meta[taskID]; !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	return append([]StoreTaskRevision(nil), s.revisions[taskID]...), nil
+}
+
+func (s *inmem) FindTaskRevision(_ context.Context, taskID platform.ID, revision int64) (*StoreTaskRevision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.meta[taskID]; !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	for _, rev := range s.revisions[taskID] {
+		if rev.Revision == revision {
+			return &rev, nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
 func (s *inmem) delete(ctx context.Context, id platform.ID, f func(StoreTask) platform.ID) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -341,8 +641,9 @@ func (s *inmem) delete(ctx context.Context, id platform.ID, f func(StoreTask) pl
 		return ctx.Err()
 	default:
 	}
-	for i := range deletingTasks {
-		delete(s.meta, s.tasks[i].ID)
+	for _, id := range deletingTasks {
+		delete(s.meta, id)
+		delete(s.revisions, id)
 	}
 	s.tasks = newTasks
 	return nil
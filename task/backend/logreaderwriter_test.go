@@ -104,7 +104,7 @@ func newFullStackAwareLogReaderWriter(t *testing.T) *fullStackAwareLogReaderWrit
 	}
 
 	if err := readservice.AddControllerConfigDependencies(
-		&cc, engine, svc, svc,
+		&cc, engine, engine, svc, svc,
 	); err != nil {
 		t.Fatal(err)
 	}
@@ -23,6 +23,7 @@ func NewStoreTaskMeta(req CreateTaskRequest, o options.Options) StoreTaskMeta {
 		EffectiveCron:   o.EffectiveCronString(),
 		Offset:          int32(o.Offset / time.Second),
 		AuthorizationID: uint64(req.AuthorizationID),
+		Revision:        1,
 	}
 
 	if stm.Status == "" {
@@ -30,10 +31,72 @@ func NewStoreTaskMeta(req CreateTaskRequest, o options.Options) StoreTaskMeta {
 	}
 
 	stm.AlignLatestCompleted()
+	stm.SetDependsOn(o)
+	stm.SetTrigger(o)
+	stm.NotificationRules = req.NotificationRules
 
 	return stm
 }
 
+// SetDependsOn sets stm's DependsOn to the IDs of the upstream tasks in o's DependsOn.
+// The options are expected to have been validated, so IDs which cannot be decoded are skipped.
+func (stm *StoreTaskMeta) SetDependsOn(o options.Options) {
+	stm.DependsOn = nil
+	for _, s := range o.DependsOn {
+		id, err := platform.IDFromString(s)
+		if err != nil {
+			continue
+		}
+		stm.DependsOn = append(stm.DependsOn, uint64(*id))
+	}
+}
+
+// SetTrigger sets stm's trigger fields from o's Trigger, or clears them if o has no trigger.
+// The options are expected to have been validated, so a bucket ID which cannot be decoded is skipped.
+func (stm *StoreTaskMeta) SetTrigger(o options.Options) {
+	stm.TriggerBucketID = 0
+	stm.TriggerMeasurement = ""
+	stm.TriggerMinInterval = 0
+	if o.Trigger == nil {
+		if stm.EffectiveCron == "" {
+			// The task had a trigger, and is now run on the schedule of o.
+			stm.EffectiveCron = o.EffectiveCronString()
+		}
+		return
+	}
+
+	id, err := platform.IDFromString(o.Trigger.BucketID)
+	if err != nil {
+		return
+	}
+	stm.TriggerBucketID = uint64(*id)
+	stm.TriggerMeasurement = o.Trigger.Measurement
+	stm.TriggerMinInterval = int32(o.Trigger.MinInterval / time.Second)
+}
+
+// HasTrigger returns true if stm's task is run when points are written to a bucket, rather than on a schedule.
+func (stm *StoreTaskMeta) HasTrigger() bool {
+	return stm.TriggerBucketID != 0
+}
+
+// TriggeredBy returns true if writes of points of the given measurement to the given bucket trigger runs of stm's task.
+func (stm *StoreTaskMeta) TriggeredBy(bucketID platform.ID, measurement string) bool {
+	if !stm.HasTrigger() || platform.ID(stm.TriggerBucketID) != bucketID {
+		return false
+	}
+	return stm.TriggerMeasurement == "" || stm.TriggerMeasurement == measurement
+}
+
+// DependsOnTask returns true if the task with the given ID is one of stm's upstream tasks.
+func (stm *StoreTaskMeta) DependsOnTask(taskID platform.ID) bool {
+	for _, id := range stm.DependsOn {
+		if platform.ID(id) == taskID {
+			return true
+		}
+	}
+	return false
+}
+
 // AlignLatestCompleted alligns the latest completed to be on the min/hour/day
 func (stm *StoreTaskMeta) AlignLatestCompleted() {
 
@@ -71,7 +134,12 @@ func (stm *StoreTaskMeta) FinishRun(runID platform.ID) bool {
 		stm.CurrentlyRunning = append(stm.CurrentlyRunning[:i], stm.CurrentlyRunning[i+1:]...)
 
 		rs, re, ra := runner.RangeStart, runner.RangeEnd, runner.RequestedAt
-		if rs == 0 && re == 0 && ra == 0 {
+		if runner.Triggered {
+			// Its queue was dropped when the run was created.
+			if runner.Now > stm.LatestCompleted {
+				stm.LatestCompleted = runner.Now
+			}
+		} else if rs == 0 && re == 0 && ra == 0 {
 			// It must be a naturally scheduled run.
 			if runner.Now > stm.LatestCompleted {
 				stm.LatestCompleted = runner.Now
@@ -94,6 +162,39 @@ func (stm *StoreTaskMeta) FinishRun(runID platform.ID) bool {
 	return false
 }
 
+// RetryRun replaces the run matching runID in m's CurrentlyRunning slice with a new run,
+// which has the same now and time range, a new ID created by makeID, and a Try value one higher.
+// The new run is returned; because a StoreTaskMeta doesn't know the ID of the task it belongs to,
+// its TaskID is not set.
+//
+// If runID does not match a run, RetryRun returns ErrRunNotFound.
+func (stm *StoreTaskMeta) RetryRun(runID platform.ID, makeID func() (platform.ID, error)) (QueuedRun, error) {
+	for _, cr := range stm.CurrentlyRunning {
+		if platform.ID(cr.RunID) != runID {
+			continue
+		}
+
+		id, err := makeID()
+		if err != nil {
+			return QueuedRun{}, err
+		}
+		cr.RunID = uint64(id)
+		cr.Try++
+
+		return QueuedRun{
+			RunID:        id,
+			Now:          cr.Now,
+			RequestedAt:  cr.RequestedAt,
+			Try:          cr.Try,
+			Revision:     cr.Revision,
+			Triggered:    cr.Triggered,
+			TriggerStart: cr.TriggerStart,
+			TriggerStop:  cr.TriggerStop,
+		}, nil
+	}
+	return QueuedRun{}, ErrRunNotFound
+}
+
 // CreateNextRun attempts to update stm's CurrentlyRunning slice with a new run.
 // The new run's now is assigned the earliest possible time according to stm.EffectiveCron,
 // that is later than any in-progress run and stm's LatestCompleted timestamp.
@@ -106,6 +207,14 @@ func (stm *StoreTaskMeta) CreateNextRun(now int64, makeID func() (platform.ID, e
 		return RunCreation{}, errors.New("cannot create next run when max concurrency already reached")
 	}
 
+	if stm.HasTrigger() {
+		// A task with a trigger is only run when points are written, which queues its runs.
+		if stm.HasQueue() {
+			return stm.createNextRunFromQueue(now, math.MaxInt64, nil, makeID)
+		}
+		return RunCreation{}, RunNotYetDueError{DueAt: math.MaxInt64}
+	}
+
 	// Not calling stm.DueAt here because we reuse sch.
 	// We can definitely optimize (minimize) cron parsing at a later point in time.
 	sch, err := cron.Parse(stm.EffectiveCron)
@@ -113,6 +222,14 @@ func (stm *StoreTaskMeta) CreateNextRun(now int64, makeID func() (platform.ID, e
 		return RunCreation{}, err
 	}
 
+	if len(stm.DependsOn) > 0 {
+		// A task with dependencies is only run when its upstream tasks succeed, which queues its runs.
+		if stm.HasQueue() {
+			return stm.createNextRunFromQueue(now, math.MaxInt64, sch, makeID)
+		}
+		return RunCreation{}, RunNotYetDueError{DueAt: math.MaxInt64}
+	}
+
 	latest := stm.LatestCompleted
 	for _, cr := range stm.CurrentlyRunning {
 		if cr.Now > latest {
@@ -124,7 +241,7 @@ func (stm *StoreTaskMeta) CreateNextRun(now int64, makeID func() (platform.ID, e
 	nextScheduledUnix := nextScheduled.Unix()
 	if dueAt := nextScheduledUnix + int64(stm.Offset); dueAt > now {
 		// Can't schedule yet.
-		if len(stm.ManualRuns) > 0 {
+		if stm.HasQueue() {
 			return stm.createNextRunFromQueue(now, dueAt, sch, makeID)
 		}
 		return RunCreation{}, RunNotYetDueError{DueAt: dueAt}
@@ -136,41 +253,56 @@ func (stm *StoreTaskMeta) CreateNextRun(now int64, makeID func() (platform.ID, e
 	}
 
 	stm.CurrentlyRunning = append(stm.CurrentlyRunning, &StoreTaskMetaRun{
-		Now:   nextScheduledUnix,
-		Try:   1,
-		RunID: uint64(id),
+		Now:      nextScheduledUnix,
+		Try:      1,
+		RunID:    uint64(id),
+		Revision: stm.Revision,
 	})
 
 	return RunCreation{
 		Created: QueuedRun{
-			RunID: id,
-			Now:   nextScheduledUnix,
+			RunID:    id,
+			Now:      nextScheduledUnix,
+			Revision: stm.Revision,
 		},
 		NextDue:  sch.Next(nextScheduled).Unix() + int64(stm.Offset),
-		HasQueue: len(stm.ManualRuns) > 0,
+		HasQueue: stm.HasQueue(),
 	}, nil
 }
 
-// createNextRunFromQueue creates the next run from a queue.
-// This should only be called when the queue is not empty.
+// createNextRunFromQueue creates the next run from the first queue which is not paused.
+// This should only be called when HasQueue returns true.
 func (stm *StoreTaskMeta) createNextRunFromQueue(now, nextDue int64, sch cron.Schedule, makeID func() (platform.ID, error)) (RunCreation, error) {
-	if len(stm.ManualRuns) == 0 {
+	qi := -1
+	for i, q := range stm.ManualRuns {
+		if !q.Paused {
+			qi = i
+			break
+		}
+	}
+	if qi < 0 {
 		return RunCreation{}, errors.New("cannot create run from empty queue")
 	}
 
-	q := stm.ManualRuns[0]
-	latest := q.LatestCompleted
-	for _, r := range stm.CurrentlyRunning {
-		if r.RangeStart != q.Start || r.RangeEnd != q.End || r.RequestedAt != q.RequestedAt {
-			// Doesn't match our queue.
-			continue
-		}
-		if r.Now > latest {
-			latest = r.Now
+	q := stm.ManualRuns[qi]
+	var runNow int64
+	if sch == nil {
+		// The task has no schedule, so each request is for a single run, at the end of its time range.
+		runNow = q.End
+	} else {
+		latest := q.LatestCompleted
+		for _, r := range stm.CurrentlyRunning {
+			if r.RangeStart != q.Start || r.RangeEnd != q.End || r.RequestedAt != q.RequestedAt {
+				// Doesn't match our queue.
+				continue
+			}
+			if r.Now > latest {
+				latest = r.Now
+			}
 		}
-	}
 
-	runNow := sch.Next(time.Unix(latest, 0)).Unix()
+		runNow = sch.Next(time.Unix(latest, 0)).Unix()
+	}
 
 	// Already validated that we have room to create another run, in CreateNextRun.
 	id := platform.ID(q.RunID)
@@ -188,35 +320,63 @@ func (stm *StoreTaskMeta) createNextRunFromQueue(now, nextDue int64, sch cron.Sc
 		Try:   1,
 		RunID: uint64(id),
 
-		RangeStart:  q.Start,
-		RangeEnd:    q.End,
-		RequestedAt: q.RequestedAt,
+		RangeStart:   q.Start,
+		RangeEnd:     q.End,
+		RequestedAt:  q.RequestedAt,
+		Revision:     stm.Revision,
+		Triggered:    q.Triggered,
+		TriggerStart: q.TriggerStart,
+		TriggerStop:  q.TriggerStop,
 	})
 
 	if runNow >= q.End {
 		// Drop the queue.
-		stm.ManualRuns = append(stm.ManualRuns[:0], stm.ManualRuns[1:]...)
+		stm.ManualRuns = append(stm.ManualRuns[:qi], stm.ManualRuns[qi+1:]...)
 	}
 
 	return RunCreation{
 		Created: QueuedRun{
-			RunID:       id,
-			Now:         runNow,
-			RequestedAt: q.RequestedAt,
+			RunID:        id,
+			Now:          runNow,
+			RequestedAt:  q.RequestedAt,
+			Revision:     stm.Revision,
+			Triggered:    q.Triggered,
+			TriggerStart: q.TriggerStart,
+			TriggerStop:  q.TriggerStop,
 		},
 		NextDue:  nextDue,
-		HasQueue: len(stm.ManualRuns) > 0,
+		HasQueue: stm.HasQueue(),
 	}, nil
 }
 
+// HasQueue returns true if runs can be created from stm's ManualRuns, that is, if any manual run is not paused.
+func (stm *StoreTaskMeta) HasQueue() bool {
+	for _, q := range stm.ManualRuns {
+		if !q.Paused {
+			return true
+		}
+	}
+	return false
+}
+
 // NextDueRun returns the Unix timestamp of when the next call to CreateNextRun will be ready.
 // The returned timestamp reflects the task's delay, so it does not necessarily exactly match the schedule time.
 func (stm *StoreTaskMeta) NextDueRun() (int64, error) {
+	if stm.HasTrigger() {
+		// Never due on a schedule.
+		return math.MaxInt64, nil
+	}
+
 	sch, err := cron.Parse(stm.EffectiveCron)
 	if err != nil {
 		return 0, err
 	}
 
+	if len(stm.DependsOn) > 0 {
+		// Never due on its own schedule.
+		return math.MaxInt64, nil
+	}
+
 	latest := stm.LatestCompleted
 	currRun := make([]*StoreTaskMetaRun, len(stm.CurrentlyRunning))
 	copy(currRun, stm.CurrentlyRunning)
@@ -272,6 +432,271 @@ func (stm *StoreTaskMeta) ManuallyRunTimeRange(start, end, requestedAt int64, ma
 	return nil
 }
 
+// Backfill requests runs for each of the task's schedules no earlier than start and no later than end,
+// as a manual run which is identified by a backfill ID created by makeID.
+// Unlike ManuallyRunTimeRange, the requested range is aligned to the task's schedule:
+// the Start and End of the returned manual run are the first and last schedules in the range.
+// requestedAt is the Unix timestamp indicating when the backfill was requested.
+//
+// If there is no schedule in the range, Backfill returns ErrNoSchedulesInRange.
+// If adding the backfill would exceed the queue size, Backfill returns ErrManualQueueFull.
+func (stm *StoreTaskMeta) Backfill(start, end, requestedAt int64, makeID func() (platform.ID, error)) (*StoreTaskMetaManualRun, error) {
+	after, first, last, n, err := stm.scheduleRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrNoSchedulesInRange
+	}
+
+	id, err := makeID()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stm.ManuallyRunTimeRange(first, last, requestedAt, nil); err != nil {
+		return nil, err
+	}
+	mr := stm.ManualRuns[len(stm.ManualRuns)-1]
+	// The first run of the queue is the next schedule after its latest completed.
+	mr.LatestCompleted = after
+	mr.BackfillID = uint64(id)
+	return mr, nil
+}
+
+// QueueDependentRun requests a run for now, after a run of one of stm's upstream tasks succeeded for now.
+// requestedAt is the Unix timestamp indicating when the upstream run succeeded.
+//
+// The run is only requested if now is on the task's schedule, in which case QueueDependentRun returns true.
+// If a run for now is still queued, because another upstream task already succeeded for now,
+// no other run is requested and QueueDependentRun returns false.
+// If adding the run would exceed the queue size, QueueDependentRun returns ErrManualQueueFull.
+func (stm *StoreTaskMeta) QueueDependentRun(now, requestedAt int64) (bool, error) {
+	after, first, _, n, err := stm.scheduleRange(now, now)
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+
+	if err := stm.ManuallyRunTimeRange(first, first, requestedAt, nil); err != nil {
+		if _, ok := err.(RequestStillQueuedError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	// The run of the queue is the next schedule after its latest completed.
+	stm.ManualRuns[len(stm.ManualRuns)-1].LatestCompleted = after
+	return true, nil
+}
+
+// QueueTriggeredRun requests a run for now, after points were written to the bucket of stm's trigger.
+// start and stop are the Unix timestamps in nanoseconds of the earliest and latest written points.
+// requestedAt is the Unix timestamp indicating when the points were written.
+//
+// If a triggered run is still queued, no other run is requested.
+// Instead, the time range of the queued run is extended to cover the written points.
+// If adding the run would exceed the queue size, QueueTriggeredRun returns ErrManualQueueFull.
+func (stm *StoreTaskMeta) QueueTriggeredRun(now, start, stop, requestedAt int64) error {
+	for _, mr := range stm.ManualRuns {
+		if !mr.Triggered {
+			continue
+		}
+		if start < mr.TriggerStart {
+			mr.TriggerStart = start
+		}
+		if stop > mr.TriggerStop {
+			mr.TriggerStop = stop
+		}
+		return nil
+	}
+
+	err := stm.ManuallyRunTimeRange(now, now, requestedAt, nil)
+	if _, ok := err.(RequestStillQueuedError); err != nil && !ok {
+		return err
+	}
+	// If a run for now was already requested manually, it covers the written points instead.
+	for _, mr := range stm.ManualRuns {
+		if mr.Start == now && mr.End == now {
+			mr.Triggered = true
+			mr.TriggerStart = start
+			mr.TriggerStop = stop
+			break
+		}
+	}
+	return nil
+}
+
+// SetBackfillPaused pauses or resumes the backfill matching backfillID in stm's ManualRuns, and returns it.
+// No runs are created from a paused backfill, but its runs in progress are not affected.
+//
+// If backfillID does not match a backfill, SetBackfillPaused returns ErrBackfillNotFound.
+func (stm *StoreTaskMeta) SetBackfillPaused(backfillID platform.ID, paused bool) (*StoreTaskMetaManualRun, error) {
+	for _, mr := range stm.ManualRuns {
+		if mr.BackfillID != 0 && platform.ID(mr.BackfillID) == backfillID {
+			mr.Paused = paused
+			return mr, nil
+		}
+	}
+	return nil, ErrBackfillNotFound
+}
+
+// CancelBackfill removes the backfill matching backfillID from stm's ManualRuns, and returns it.
+// The runs in progress for the backfill are still in stm's CurrentlyRunning slice.
+//
+// If backfillID does not match a backfill, CancelBackfill returns ErrBackfillNotFound.
+func (stm *StoreTaskMeta) CancelBackfill(backfillID platform.ID) (*StoreTaskMetaManualRun, error) {
+	for i, mr := range stm.ManualRuns {
+		if mr.BackfillID != 0 && platform.ID(mr.BackfillID) == backfillID {
+			stm.ManualRuns = append(stm.ManualRuns[:i], stm.ManualRuns[i+1:]...)
+			return mr, nil
+		}
+	}
+	return nil, ErrBackfillNotFound
+}
+
+// BackfillProgress returns the number of schedules of the backfill mr which are no later than its latest completed run,
+// and the total number of schedules of the backfill.
+func (stm *StoreTaskMeta) BackfillProgress(mr *StoreTaskMetaManualRun) (completed, total int64, err error) {
+	_, _, _, total, err = stm.scheduleRange(mr.Start, mr.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	if mr.LatestCompleted < mr.Start {
+		return 0, total, nil
+	}
+	_, _, _, completed, err = stm.scheduleRange(mr.Start, mr.LatestCompleted)
+	if err != nil {
+		return 0, 0, err
+	}
+	return completed, total, nil
+}
+
+// scheduleRange returns the first and last schedules of stm no earlier than start and no later than end,
+// and the number of schedules in that range, which is 0 if there are none.
+// The next schedule after the returned time after is the first schedule.
+func (stm *StoreTaskMeta) scheduleRange(start, end int64) (after, first, last, n int64, err error) {
+	if stm.HasTrigger() {
+		// A task with a trigger has no schedules.
+		return start - 1, 0, 0, 0, nil
+	}
+
+	if strings.HasPrefix(stm.EffectiveCron, "@every ") {
+		// Like AlignLatestCompleted, align the schedules of the task to multiples of its period.
+		every, err := time.ParseDuration(strings.TrimPrefix(stm.EffectiveCron, "@every "))
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		d := int64(every / time.Second)
+		if d <= 0 {
+			return 0, 0, 0, 0, errors.New("task period must be at least one second")
+		}
+
+		first = time.Unix(start, 0).Truncate(every).Unix()
+		if first < start {
+			first += d
+		}
+		last = time.Unix(end, 0).Truncate(every).Unix()
+		if last < first {
+			return first - d, first, last, 0, nil
+		}
+		return first - d, first, last, (last-first)/d + 1, nil
+	}
+
+	sch, err := cron.Parse(stm.EffectiveCron)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	after = start - 1
+	for prev := after; ; n++ {
+		t := sch.Next(time.Unix(prev, 0)).Unix()
+		if t <= prev || t > end {
+			// Either no more schedules, or past the end of the range.
+			break
+		}
+		if n == 0 {
+			first = t
+		}
+		last, prev = t, t
+	}
+	return after, first, last, n, nil
+}
+
+// NextSchedules returns the Unix timestamps of the schedules of the next n runs after stm's latest completed run,
+// as CreateNextRun would create them. Each run is due at its schedule plus stm's offset.
+// A task with dependencies is only run when its upstream tasks succeed, so it has no schedules of its own;
+// neither has a task with a trigger, which is only run when points are written.
+func (stm *StoreTaskMeta) NextSchedules(n int) ([]int64, error) {
+	if stm.HasTrigger() {
+		return nil, nil
+	}
+
+	sch, err := cron.Parse(stm.EffectiveCron)
+	if err != nil {
+		return nil, err
+	}
+	if len(stm.DependsOn) > 0 {
+		return nil, nil
+	}
+
+	schedules := make([]int64, 0, n)
+	for next := time.Unix(stm.LatestCompleted, 0); len(schedules) < n; {
+		next = sch.Next(next)
+		if next.IsZero() {
+			// The schedule has no more times.
+			break
+		}
+		schedules = append(schedules, next.Unix())
+	}
+	return schedules, nil
+}
+
+// Lease acquires or renews the lease of owner on the task until the Unix timestamp expiresAt.
+// If another owner holds a lease which has not expired at the Unix timestamp now, Lease returns ErrTaskLeased.
+func (stm *StoreTaskMeta) Lease(owner string, now, expiresAt int64) error {
+	if stm.LeaseHeldByOther(owner, now) {
+		return ErrTaskLeased
+	}
+
+	stm.LeaseOwner = owner
+	stm.LeaseExpiresAt = expiresAt
+	return nil
+}
+
+// ReleaseLease releases the lease on the task if it is held by owner, and reports whether it was.
+func (stm *StoreTaskMeta) ReleaseLease(owner string) bool {
+	if stm.LeaseOwner != owner {
+		return false
+	}
+
+	stm.LeaseOwner = ""
+	stm.LeaseExpiresAt = 0
+	return true
+}
+
+// LeaseHeldByOther returns true if an owner other than owner holds a lease on the task
+// which has not expired at the Unix timestamp now.
+func (stm *StoreTaskMeta) LeaseHeldByOther(owner string, now int64) bool {
+	return stm.LeaseOwner != "" && stm.LeaseOwner != owner && stm.LeaseExpiresAt > now
+}
+
+// NextRevisions advances stm.Revision for a change of the task's script from oldScript to newScript
+// by the user with ID userID, at the Unix timestamp now, and returns the revisions to record.
+//
+// For a task created before revisions were recorded, oldScript is returned as the first revision,
+// so that the task can be rolled back to it.
+func (stm *StoreTaskMeta) NextRevisions(oldScript, newScript string, userID platform.ID, now int64) []StoreTaskRevision {
+	var revs []StoreTaskRevision
+	if stm.Revision == 0 {
+		stm.Revision = 1
+		revs = append(revs, StoreTaskRevision{Revision: 1, Script: oldScript, UpdatedAt: stm.CreatedAt})
+	}
+	stm.Revision++
+	return append(revs, StoreTaskRevision{Revision: stm.Revision, Script: newScript, UpdatedBy: userID, UpdatedAt: now})
+}
+
 // Equal returns true if all of stm's fields compare equal to other.
 // Note that this method operates on values, unlike the other methods which operate on pointers.
 //
@@ -283,7 +708,15 @@ func (stm StoreTaskMeta) Equal(other StoreTaskMeta) bool {
 		stm.EffectiveCron != other.EffectiveCron ||
 		stm.Offset != other.Offset ||
 		len(stm.CurrentlyRunning) != len(other.CurrentlyRunning) ||
-		len(stm.ManualRuns) != len(other.ManualRuns) {
+		len(stm.ManualRuns) != len(other.ManualRuns) ||
+		stm.LeaseOwner != other.LeaseOwner ||
+		stm.LeaseExpiresAt != other.LeaseExpiresAt ||
+		len(stm.DependsOn) != len(other.DependsOn) ||
+		len(stm.NotificationRules) != len(other.NotificationRules) ||
+		stm.Revision != other.Revision ||
+		stm.TriggerBucketID != other.TriggerBucketID ||
+		stm.TriggerMeasurement != other.TriggerMeasurement ||
+		stm.TriggerMinInterval != other.TriggerMinInterval {
 		return false
 	}
 
@@ -295,7 +728,11 @@ func (stm StoreTaskMeta) Equal(other StoreTaskMeta) bool {
 			s.RunID != o.RunID ||
 			s.RangeStart != o.RangeStart ||
 			s.RangeEnd != o.RangeEnd ||
-			s.RequestedAt != o.RequestedAt {
+			s.RequestedAt != o.RequestedAt ||
+			s.Revision != o.Revision ||
+			s.Triggered != o.Triggered ||
+			s.TriggerStart != o.TriggerStart ||
+			s.TriggerStop != o.TriggerStop {
 			return false
 		}
 	}
@@ -306,10 +743,42 @@ func (stm StoreTaskMeta) Equal(other StoreTaskMeta) bool {
 		if s.Start != o.Start ||
 			s.End != o.End ||
 			s.LatestCompleted != o.LatestCompleted ||
-			s.RequestedAt != o.RequestedAt {
+			s.RequestedAt != o.RequestedAt ||
+			s.Paused != o.Paused ||
+			s.BackfillID != o.BackfillID ||
+			s.Triggered != o.Triggered ||
+			s.TriggerStart != o.TriggerStart ||
+			s.TriggerStop != o.TriggerStop {
+			return false
+		}
+	}
+
+	for i, id := range other.DependsOn {
+		if stm.DependsOn[i] != id {
+			return false
+		}
+	}
+
+	for i, o := range other.NotificationRules {
+		if *stm.NotificationRules[i] != *o {
 			return false
 		}
 	}
 
 	return true
 }
+
+// Triggered reports whether a run finishing with status s triggers a notification by r.
+// consecutiveFailures is the number of failed runs of the task in a row,
+// ending with the run if it failed, or just before the run if it succeeded.
+func (r *StoreTaskNotificationRule) Triggered(s RunStatus, consecutiveFailures int) bool {
+	switch r.On {
+	case platform.TaskNotifyOnFailure:
+		return s == RunFail
+	case platform.TaskNotifyOnConsecutiveFailures:
+		return s == RunFail && consecutiveFailures == int(r.Threshold)
+	case platform.TaskNotifyOnRecovery:
+		return s == RunSuccess && consecutiveFailures > 0
+	}
+	return false
+}
@@ -41,6 +41,22 @@ type StoreTaskMeta struct {
 	// The Authorization ID associated with the task.
 	AuthorizationID uint64                    `protobuf:"varint,9,opt,name=authorization_id,json=authorizationId,proto3" json:"authorization_id,omitempty"`
 	ManualRuns      []*StoreTaskMetaManualRun `protobuf:"bytes,16,rep,name=manual_runs,json=manualRuns,proto3" json:"manual_runs,omitempty"`
+	// depends_on holds the IDs of the upstream tasks of a task, whose successful runs trigger runs of the task.
+	DependsOn []uint64 `protobuf:"varint,17,rep,packed,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// lease_owner identifies the scheduler which holds the lease on the task, and so is the only one to run it.
+	LeaseOwner string `protobuf:"bytes,18,opt,name=lease_owner,json=leaseOwner,proto3" json:"lease_owner,omitempty"`
+	// lease_expires_at is the unix timestamp at which the lease expires, after which another scheduler may take over the task.
+	LeaseExpiresAt int64 `protobuf:"varint,19,opt,name=lease_expires_at,json=leaseExpiresAt,proto3" json:"lease_expires_at,omitempty"`
+	// notification_rules describe when to notify an HTTP endpoint about the outcome of the task's runs.
+	NotificationRules []*StoreTaskNotificationRule `protobuf:"bytes,20,rep,name=notification_rules,json=notificationRules,proto3" json:"notification_rules,omitempty"`
+	// revision is the number of the task's current script revision, starting at 1.
+	Revision int64 `protobuf:"varint,21,opt,name=revision,proto3" json:"revision,omitempty"`
+	// trigger_bucket_id is the ID of the bucket whose writes trigger runs of the task, or 0 if the task runs on its schedule.
+	TriggerBucketID uint64 `protobuf:"varint,22,opt,name=trigger_bucket_id,json=triggerBucketId,proto3" json:"trigger_bucket_id,omitempty"`
+	// trigger_measurement restricts the writes which trigger runs of the task to those to a measurement, if set.
+	TriggerMeasurement string `protobuf:"bytes,23,opt,name=trigger_measurement,json=triggerMeasurement,proto3" json:"trigger_measurement,omitempty"`
+	// trigger_min_interval is the minimum time between two triggered runs of the task, in seconds.
+	TriggerMinInterval int32 `protobuf:"varint,24,opt,name=trigger_min_interval,json=triggerMinInterval,proto3" json:"trigger_min_interval,omitempty"`
 }
 
 func (m *StoreTaskMeta) Reset()         { *m = StoreTaskMeta{} }
@@ -146,6 +162,62 @@ func (m *StoreTaskMeta) GetManualRuns() []*StoreTaskMetaManualRun {
 	return nil
 }
 
+func (m *StoreTaskMeta) GetDependsOn() []uint64 {
+	if m != nil {
+		return m.DependsOn
+	}
+	return nil
+}
+
+func (m *StoreTaskMeta) GetLeaseOwner() string {
+	if m != nil {
+		return m.LeaseOwner
+	}
+	return ""
+}
+
+func (m *StoreTaskMeta) GetLeaseExpiresAt() int64 {
+	if m != nil {
+		return m.LeaseExpiresAt
+	}
+	return 0
+}
+
+func (m *StoreTaskMeta) GetNotificationRules() []*StoreTaskNotificationRule {
+	if m != nil {
+		return m.NotificationRules
+	}
+	return nil
+}
+
+func (m *StoreTaskMeta) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *StoreTaskMeta) GetTriggerBucketID() uint64 {
+	if m != nil {
+		return m.TriggerBucketID
+	}
+	return 0
+}
+
+func (m *StoreTaskMeta) GetTriggerMeasurement() string {
+	if m != nil {
+		return m.TriggerMeasurement
+	}
+	return ""
+}
+
+func (m *StoreTaskMeta) GetTriggerMinInterval() int32 {
+	if m != nil {
+		return m.TriggerMinInterval
+	}
+	return 0
+}
+
 type StoreTaskMetaRun struct {
 	// now is the unix timestamp of the "now" value for the run.
 	Now   int64  `protobuf:"varint,1,opt,name=now,proto3" json:"now,omitempty"`
@@ -158,6 +230,14 @@ type StoreTaskMetaRun struct {
 	// requested_at is the unix timestamp indicating when this run was requested.
 	// It is the same value as the "parent" StoreTaskMetaManualRun, if this run was the result of a manual request.
 	RequestedAt int64 `protobuf:"varint,6,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	// revision is the number of the task's script revision the run executes.
+	Revision int64 `protobuf:"varint,7,opt,name=revision,proto3" json:"revision,omitempty"`
+	// triggered is set if the run was triggered by writes to the bucket of the task's trigger.
+	Triggered bool `protobuf:"varint,8,opt,name=triggered,proto3" json:"triggered,omitempty"`
+	// trigger_start is the unix timestamp in nanoseconds of the earliest point written by the writes which triggered the run.
+	TriggerStart int64 `protobuf:"varint,9,opt,name=trigger_start,json=triggerStart,proto3" json:"trigger_start,omitempty"`
+	// trigger_stop is the unix timestamp in nanoseconds of the latest point written by the writes which triggered the run.
+	TriggerStop int64 `protobuf:"varint,10,opt,name=trigger_stop,json=triggerStop,proto3" json:"trigger_stop,omitempty"`
 }
 
 func (m *StoreTaskMetaRun) Reset()         { *m = StoreTaskMetaRun{} }
@@ -235,6 +315,34 @@ func (m *StoreTaskMetaRun) GetRequestedAt() int64 {
 	return 0
 }
 
+func (m *StoreTaskMetaRun) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *StoreTaskMetaRun) GetTriggered() bool {
+	if m != nil {
+		return m.Triggered
+	}
+	return false
+}
+
+func (m *StoreTaskMetaRun) GetTriggerStart() int64 {
+	if m != nil {
+		return m.TriggerStart
+	}
+	return 0
+}
+
+func (m *StoreTaskMetaRun) GetTriggerStop() int64 {
+	if m != nil {
+		return m.TriggerStop
+	}
+	return 0
+}
+
 // StoreTaskMetaManualRun indicates a manually requested run for a time range.
 // It has a start and end pair of unix timestamps indicating the time range covered by the request.
 type StoreTaskMetaManualRun struct {
@@ -248,6 +356,16 @@ type StoreTaskMetaManualRun struct {
 	RequestedAt int64 `protobuf:"varint,4,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
 	// run_id is set ahead of time for retries of individual runs. Manually run time ranges do not receive an ID.
 	RunID uint64 `protobuf:"varint,5,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// paused is set when no more runs are to be created from this queue, until it is resumed.
+	Paused bool `protobuf:"varint,6,opt,name=paused,proto3" json:"paused,omitempty"`
+	// backfill_id identifies a time range requested as a backfill, which can be paused, resumed or cancelled.
+	BackfillID uint64 `protobuf:"varint,7,opt,name=backfill_id,json=backfillId,proto3" json:"backfill_id,omitempty"`
+	// triggered is set if the run was requested by writes to the bucket of the task's trigger.
+	Triggered bool `protobuf:"varint,8,opt,name=triggered,proto3" json:"triggered,omitempty"`
+	// trigger_start is the unix timestamp in nanoseconds of the earliest point written by the writes which requested the run.
+	TriggerStart int64 `protobuf:"varint,9,opt,name=trigger_start,json=triggerStart,proto3" json:"trigger_start,omitempty"`
+	// trigger_stop is the unix timestamp in nanoseconds of the latest point written by the writes which requested the run.
+	TriggerStop int64 `protobuf:"varint,10,opt,name=trigger_stop,json=triggerStop,proto3" json:"trigger_stop,omitempty"`
 }
 
 func (m *StoreTaskMetaManualRun) Reset()         { *m = StoreTaskMetaManualRun{} }
@@ -318,10 +436,128 @@ func (m *StoreTaskMetaManualRun) GetRunID() uint64 {
 	return 0
 }
 
+func (m *StoreTaskMetaManualRun) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *StoreTaskMetaManualRun) GetBackfillID() uint64 {
+	if m != nil {
+		return m.BackfillID
+	}
+	return 0
+}
+
+func (m *StoreTaskMetaManualRun) GetTriggered() bool {
+	if m != nil {
+		return m.Triggered
+	}
+	return false
+}
+
+func (m *StoreTaskMetaManualRun) GetTriggerStart() int64 {
+	if m != nil {
+		return m.TriggerStart
+	}
+	return 0
+}
+
+func (m *StoreTaskMetaManualRun) GetTriggerStop() int64 {
+	if m != nil {
+		return m.TriggerStop
+	}
+	return 0
+}
+
+// StoreTaskNotificationRule describes when and where to send a notification about the outcome of a task's runs.
+type StoreTaskNotificationRule struct {
+	// on is the condition for a notification: "failure", "consecutive_failures" or "recovery".
+	On string `protobuf:"bytes,1,opt,name=on,proto3" json:"on,omitempty"`
+	// threshold is the number of consecutive failed runs which trigger a "consecutive_failures" notification.
+	Threshold int32 `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// url is the HTTP endpoint the notification is posted to.
+	URL string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// auth_header is the name of the HTTP header, such as Authorization, which carries the secret with the key auth_secret_key.
+	AuthHeader string `protobuf:"bytes,4,opt,name=auth_header,json=authHeader,proto3" json:"auth_header,omitempty"`
+	// auth_secret_key is the key of the secret of the task's organization, sent in auth_header.
+	AuthSecretKey string `protobuf:"bytes,5,opt,name=auth_secret_key,json=authSecretKey,proto3" json:"auth_secret_key,omitempty"`
+}
+
+func (m *StoreTaskNotificationRule) Reset()         { *m = StoreTaskNotificationRule{} }
+func (m *StoreTaskNotificationRule) String() string { return proto.CompactTextString(m) }
+func (*StoreTaskNotificationRule) ProtoMessage()    {}
+func (*StoreTaskNotificationRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meta_841ef32afee093f0, []int{3}
+}
+func (m *StoreTaskNotificationRule) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StoreTaskNotificationRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StoreTaskNotificationRule.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *StoreTaskNotificationRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreTaskNotificationRule.Merge(dst, src)
+}
+func (m *StoreTaskNotificationRule) XXX_Size() int {
+	return m.Size()
+}
+func (m *StoreTaskNotificationRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreTaskNotificationRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreTaskNotificationRule proto.InternalMessageInfo
+
+func (m *StoreTaskNotificationRule) GetOn() string {
+	if m != nil {
+		return m.On
+	}
+	return ""
+}
+
+func (m *StoreTaskNotificationRule) GetThreshold() int32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *StoreTaskNotificationRule) GetURL() string {
+	if m != nil {
+		return m.URL
+	}
+	return ""
+}
+
+func (m *StoreTaskNotificationRule) GetAuthHeader() string {
+	if m != nil {
+		return m.AuthHeader
+	}
+	return ""
+}
+
+func (m *StoreTaskNotificationRule) GetAuthSecretKey() string {
+	if m != nil {
+		return m.AuthSecretKey
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*StoreTaskMeta)(nil), "com.influxdata.platform.task.backend.StoreTaskMeta")
 	proto.RegisterType((*StoreTaskMetaRun)(nil), "com.influxdata.platform.task.backend.StoreTaskMetaRun")
 	proto.RegisterType((*StoreTaskMetaManualRun)(nil), "com.influxdata.platform.task.backend.StoreTaskMetaManualRun")
+	proto.RegisterType((*StoreTaskNotificationRule)(nil), "com.influxdata.platform.task.backend.StoreTaskNotificationRule")
 }
 func (m *StoreTaskMeta) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -406,6 +642,83 @@ func (m *StoreTaskMeta) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.DependsOn) > 0 {
+		dAtA2 := make([]byte, len(m.DependsOn)*10)
+		var j1 int
+		for _, num := range m.DependsOn {
+			for num >= 1<<7 {
+				dAtA2[j1] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j1++
+			}
+			dAtA2[j1] = uint8(num)
+			j1++
+		}
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(j1))
+		i += copy(dAtA[i:], dAtA2[:j1])
+	}
+	if len(m.LeaseOwner) > 0 {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(len(m.LeaseOwner)))
+		i += copy(dAtA[i:], m.LeaseOwner)
+	}
+	if m.LeaseExpiresAt != 0 {
+		dAtA[i] = 0x98
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.LeaseExpiresAt))
+	}
+	if len(m.NotificationRules) > 0 {
+		for _, msg := range m.NotificationRules {
+			dAtA[i] = 0xa2
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintMeta(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Revision != 0 {
+		dAtA[i] = 0xa8
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.Revision))
+	}
+	if m.TriggerBucketID != 0 {
+		dAtA[i] = 0xb0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.TriggerBucketID))
+	}
+	if len(m.TriggerMeasurement) > 0 {
+		dAtA[i] = 0xba
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(len(m.TriggerMeasurement)))
+		i += copy(dAtA[i:], m.TriggerMeasurement)
+	}
+	if m.TriggerMinInterval != 0 {
+		dAtA[i] = 0xc0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.TriggerMinInterval))
+	}
 	return i, nil
 }
 
@@ -454,6 +767,31 @@ func (m *StoreTaskMetaRun) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMeta(dAtA, i, uint64(m.RequestedAt))
 	}
+	if m.Revision != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.Revision))
+	}
+	if m.Triggered {
+		dAtA[i] = 0x40
+		i++
+		if m.Triggered {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.TriggerStart != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.TriggerStart))
+	}
+	if m.TriggerStop != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.TriggerStop))
+	}
 	return i, nil
 }
 
@@ -497,6 +835,88 @@ func (m *StoreTaskMetaManualRun) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMeta(dAtA, i, uint64(m.RunID))
 	}
+	if m.Paused {
+		dAtA[i] = 0x30
+		i++
+		if m.Paused {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.BackfillID != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.BackfillID))
+	}
+	if m.Triggered {
+		dAtA[i] = 0x40
+		i++
+		if m.Triggered {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.TriggerStart != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.TriggerStart))
+	}
+	if m.TriggerStop != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.TriggerStop))
+	}
+	return i, nil
+}
+
+func (m *StoreTaskNotificationRule) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoreTaskNotificationRule) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.On) > 0 {
+		dAtA[i] = 0x0a
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(len(m.On)))
+		i += copy(dAtA[i:], m.On)
+	}
+	if m.Threshold != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.Threshold))
+	}
+	if len(m.URL) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(len(m.URL)))
+		i += copy(dAtA[i:], m.URL)
+	}
+	if len(m.AuthHeader) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(len(m.AuthHeader)))
+		i += copy(dAtA[i:], m.AuthHeader)
+	}
+	if len(m.AuthSecretKey) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(len(m.AuthSecretKey)))
+		i += copy(dAtA[i:], m.AuthSecretKey)
+	}
 	return i, nil
 }
 
@@ -553,6 +973,39 @@ func (m *StoreTaskMeta) Size() (n int) {
 			n += 2 + l + sovMeta(uint64(l))
 		}
 	}
+	if len(m.DependsOn) > 0 {
+		l = 0
+		for _, e := range m.DependsOn {
+			l += sovMeta(uint64(e))
+		}
+		n += 2 + sovMeta(uint64(l)) + l
+	}
+	l = len(m.LeaseOwner)
+	if l > 0 {
+		n += 2 + l + sovMeta(uint64(l))
+	}
+	if m.LeaseExpiresAt != 0 {
+		n += 2 + sovMeta(uint64(m.LeaseExpiresAt))
+	}
+	if len(m.NotificationRules) > 0 {
+		for _, e := range m.NotificationRules {
+			l = e.Size()
+			n += 2 + l + sovMeta(uint64(l))
+		}
+	}
+	if m.Revision != 0 {
+		n += 2 + sovMeta(uint64(m.Revision))
+	}
+	if m.TriggerBucketID != 0 {
+		n += 2 + sovMeta(uint64(m.TriggerBucketID))
+	}
+	l = len(m.TriggerMeasurement)
+	if l > 0 {
+		n += 2 + l + sovMeta(uint64(l))
+	}
+	if m.TriggerMinInterval != 0 {
+		n += 2 + sovMeta(uint64(m.TriggerMinInterval))
+	}
 	return n
 }
 
@@ -580,6 +1033,18 @@ func (m *StoreTaskMetaRun) Size() (n int) {
 	if m.RequestedAt != 0 {
 		n += 1 + sovMeta(uint64(m.RequestedAt))
 	}
+	if m.Revision != 0 {
+		n += 1 + sovMeta(uint64(m.Revision))
+	}
+	if m.Triggered {
+		n += 2
+	}
+	if m.TriggerStart != 0 {
+		n += 1 + sovMeta(uint64(m.TriggerStart))
+	}
+	if m.TriggerStop != 0 {
+		n += 1 + sovMeta(uint64(m.TriggerStop))
+	}
 	return n
 }
 
@@ -604,20 +1069,63 @@ func (m *StoreTaskMetaManualRun) Size() (n int) {
 	if m.RunID != 0 {
 		n += 1 + sovMeta(uint64(m.RunID))
 	}
+	if m.Paused {
+		n += 2
+	}
+	if m.BackfillID != 0 {
+		n += 1 + sovMeta(uint64(m.BackfillID))
+	}
+	if m.Triggered {
+		n += 2
+	}
+	if m.TriggerStart != 0 {
+		n += 1 + sovMeta(uint64(m.TriggerStart))
+	}
+	if m.TriggerStop != 0 {
+		n += 1 + sovMeta(uint64(m.TriggerStop))
+	}
 	return n
 }
 
-func sovMeta(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+func (m *StoreTaskNotificationRule) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	return n
-}
-func sozMeta(x uint64) (n int) {
+	var l int
+	_ = l
+	l = len(m.On)
+	if l > 0 {
+		n += 1 + l + sovMeta(uint64(l))
+	}
+	if m.Threshold != 0 {
+		n += 1 + sovMeta(uint64(m.Threshold))
+	}
+	l = len(m.URL)
+	if l > 0 {
+		n += 1 + l + sovMeta(uint64(l))
+	}
+	l = len(m.AuthHeader)
+	if l > 0 {
+		n += 1 + l + sovMeta(uint64(l))
+	}
+	l = len(m.AuthSecretKey)
+	if l > 0 {
+		n += 1 + l + sovMeta(uint64(l))
+	}
+	return n
+}
+
+func sovMeta(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozMeta(x uint64) (n int) {
 	return sovMeta(uint64((x << 1) ^ uint64((int64(x) >> 63))))
 }
 func (m *StoreTaskMeta) Unmarshal(dAtA []byte) error {
@@ -883,6 +1391,244 @@ func (m *StoreTaskMeta) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 17:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMeta
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.DependsOn = append(m.DependsOn, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMeta
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMeta
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.DependsOn) == 0 {
+					m.DependsOn = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMeta
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.DependsOn = append(m.DependsOn, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field DependsOn", wireType)
+			}
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseOwner", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LeaseOwner = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 19:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LeaseExpiresAt", wireType)
+			}
+			m.LeaseExpiresAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LeaseExpiresAt |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotificationRules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NotificationRules = append(m.NotificationRules, &StoreTaskNotificationRule{})
+			if err := m.NotificationRules[len(m.NotificationRules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revision", wireType)
+			}
+			m.Revision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Revision |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 22:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TriggerBucketID", wireType)
+			}
+			m.TriggerBucketID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TriggerBucketID |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TriggerMeasurement", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TriggerMeasurement = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TriggerMinInterval", wireType)
+			}
+			m.TriggerMinInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TriggerMinInterval |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMeta(dAtA[iNdEx:])
@@ -1047,6 +1793,83 @@ func (m *StoreTaskMetaRun) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revision", wireType)
+			}
+			m.Revision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Revision |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Triggered", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Triggered = bool(v != 0)
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TriggerStart", wireType)
+			}
+			m.TriggerStart = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TriggerStart |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TriggerStop", wireType)
+			}
+			m.TriggerStop = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TriggerStop |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMeta(dAtA[iNdEx:])
@@ -1192,6 +2015,288 @@ func (m *StoreTaskMetaManualRun) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Paused", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Paused = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BackfillID", wireType)
+			}
+			m.BackfillID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BackfillID |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Triggered", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Triggered = bool(v != 0)
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TriggerStart", wireType)
+			}
+			m.TriggerStart = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TriggerStart |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TriggerStop", wireType)
+			}
+			m.TriggerStop = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TriggerStop |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMeta(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMeta
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StoreTaskNotificationRule) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMeta
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreTaskNotificationRule: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreTaskNotificationRule: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field On", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.On = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Threshold", wireType)
+			}
+			m.Threshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Threshold |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field URL", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.URL = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthHeader", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AuthHeader = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthSecretKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AuthSecretKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMeta(dAtA[iNdEx:])
@@ -1321,39 +2426,61 @@ var (
 func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_841ef32afee093f0) }
 
 var fileDescriptor_meta_841ef32afee093f0 = []byte{
-	// 543 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x41, 0x6f, 0xd3, 0x30,
-	0x14, 0xc7, 0x1b, 0xd2, 0x74, 0xab, 0x4b, 0xd7, 0x60, 0xa6, 0x29, 0x02, 0x91, 0x66, 0x15, 0x88,
-	0x72, 0x09, 0x12, 0x48, 0x9c, 0x10, 0x52, 0x57, 0x38, 0xec, 0xb0, 0x8b, 0xc7, 0x09, 0x09, 0x45,
-	0x5e, 0xe2, 0x94, 0xa8, 0x89, 0x5d, 0x9c, 0x67, 0x68, 0xf9, 0x14, 0x7c, 0x14, 0xae, 0x7c, 0x03,
-	0x8e, 0x3b, 0x72, 0x9a, 0x50, 0xfb, 0x35, 0x38, 0x20, 0x3b, 0x69, 0xd9, 0x46, 0x0f, 0x68, 0xb7,
-	0xe7, 0xdf, 0x8b, 0x9f, 0xdf, 0xff, 0xff, 0x5e, 0x10, 0x2a, 0x18, 0xd0, 0x70, 0x26, 0x05, 0x08,
-	0xfc, 0x30, 0x16, 0x45, 0x98, 0xf1, 0x34, 0x57, 0xf3, 0x84, 0x6a, 0x9a, 0x53, 0x48, 0x85, 0x2c,
-	0x42, 0xa0, 0xe5, 0x34, 0x3c, 0xa3, 0xf1, 0x94, 0xf1, 0xe4, 0xde, 0xfe, 0x44, 0x4c, 0x84, 0xb9,
-	0xf0, 0x54, 0x47, 0xd5, 0xdd, 0xc1, 0x6f, 0x1b, 0x75, 0x4f, 0x41, 0x48, 0xf6, 0x96, 0x96, 0xd3,
-	0x13, 0x06, 0x14, 0x3f, 0x46, 0xbd, 0x82, 0xce, 0xa3, 0x58, 0xf0, 0x58, 0x49, 0xc9, 0x78, 0xbc,
-	0xf0, 0xac, 0xc0, 0x1a, 0x3a, 0x64, 0xaf, 0xa0, 0xf3, 0xf1, 0x5f, 0x8a, 0x9f, 0x20, 0x37, 0xa7,
-	0xc0, 0x4a, 0x88, 0x62, 0x51, 0xcc, 0x72, 0x06, 0x2c, 0xf1, 0x6e, 0x05, 0xd6, 0xd0, 0x26, 0xbd,
-	0x8a, 0x8f, 0xd7, 0x18, 0x1f, 0xa0, 0x56, 0x09, 0x14, 0x54, 0xe9, 0xd9, 0x81, 0x35, 0x6c, 0x93,
-	0xfa, 0x84, 0x63, 0x74, 0xa7, 0x2a, 0x07, 0xf9, 0x22, 0x92, 0x8a, 0xf3, 0x8c, 0x4f, 0xbc, 0x66,
-	0x60, 0x0f, 0x3b, 0xcf, 0x5e, 0x84, 0xff, 0xa3, 0x2a, 0xbc, 0xd2, 0x3b, 0x51, 0x9c, 0xb8, 0x9b,
-	0x82, 0xa4, 0xaa, 0x87, 0x1f, 0xa1, 0x3d, 0x96, 0xa6, 0x2c, 0x86, 0xec, 0x13, 0x8b, 0x62, 0x29,
-	0xb8, 0xe7, 0x98, 0x26, 0xba, 0x1b, 0x3a, 0x96, 0x82, 0xeb, 0x1e, 0x45, 0x9a, 0x96, 0x0c, 0xbc,
-	0x96, 0x91, 0x5b, 0x9f, 0xf0, 0x03, 0x84, 0x62, 0xc9, 0x28, 0xb0, 0x24, 0xa2, 0xe0, 0xed, 0x18,
-	0x81, 0xed, 0x9a, 0x8c, 0x4c, 0x5a, 0xcd, 0x92, 0x75, 0x7a, 0xb7, 0x4a, 0xd7, 0x64, 0x04, 0xf8,
-	0x15, 0x72, 0xa9, 0x82, 0x0f, 0x42, 0x66, 0x5f, 0x28, 0x64, 0x82, 0x47, 0x59, 0xe2, 0xb5, 0x03,
-	0x6b, 0xd8, 0x3c, 0xba, 0xbb, 0xbc, 0xe8, 0xf7, 0x46, 0x97, 0x73, 0xc7, 0xaf, 0x49, 0xef, 0xca,
-	0xc7, 0xc7, 0x09, 0x7e, 0x8f, 0x3a, 0x05, 0xe5, 0x8a, 0xe6, 0xda, 0x9e, 0xd2, 0x73, 0x8d, 0x37,
-	0x2f, 0x6f, 0xe0, 0xcd, 0x89, 0xa9, 0xa2, 0x1d, 0x42, 0xc5, 0x3a, 0x2c, 0x07, 0xdf, 0x2d, 0xe4,
-	0x5e, 0xb7, 0x10, 0xbb, 0xc8, 0xe6, 0xe2, 0xb3, 0x99, 0xba, 0x4d, 0x74, 0xa8, 0x09, 0xc8, 0x85,
-	0x99, 0x6e, 0x97, 0xe8, 0x10, 0x07, 0xa8, 0x25, 0x95, 0x51, 0x63, 0x1b, 0x35, 0xed, 0xe5, 0x45,
-	0xdf, 0x21, 0x4a, 0x6b, 0x70, 0xa4, 0xd2, 0x9d, 0xf7, 0x51, 0x47, 0x52, 0x3e, 0x61, 0x51, 0x09,
-	0x54, 0x82, 0xd7, 0x34, 0xd5, 0x90, 0x41, 0xa7, 0x9a, 0xe0, 0xfb, 0xa8, 0x5d, 0x7d, 0xc0, 0x78,
-	0x62, 0x46, 0x62, 0x93, 0x5d, 0x03, 0xde, 0xf0, 0x04, 0x1f, 0xa2, 0xdb, 0x92, 0x7d, 0x54, 0xac,
-	0xac, 0x8d, 0x6d, 0x99, 0x7c, 0x67, 0xc3, 0x46, 0x30, 0xf8, 0x66, 0xa1, 0x83, 0xed, 0x12, 0xf1,
-	0x3e, 0x72, 0xaa, 0x57, 0x2b, 0x0d, 0xd5, 0x41, 0xab, 0xd0, 0x4f, 0x55, 0x3b, 0xaa, 0xc3, 0xad,
-	0x2b, 0x6c, 0x6f, 0x5f, 0xe1, 0xeb, 0x0d, 0x35, 0xff, 0x69, 0xe8, 0x92, 0x27, 0xce, 0x76, 0x4f,
-	0x8e, 0x0e, 0x7f, 0x2c, 0x7d, 0xeb, 0x7c, 0xe9, 0x5b, 0xbf, 0x96, 0xbe, 0xf5, 0x75, 0xe5, 0x37,
-	0xce, 0x57, 0x7e, 0xe3, 0xe7, 0xca, 0x6f, 0xbc, 0xdb, 0xa9, 0x87, 0x76, 0xd6, 0x32, 0xff, 0xe5,
-	0xf3, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x74, 0xb0, 0xab, 0x79, 0xe1, 0x03, 0x00, 0x00,
+	// 882 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbd, 0x55, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0x25, 0x71, 0x1e, 0xcd, 0x0d, 0x4d, 0xd2, 0x69, 0x29, 0xa6, 0x3c, 0xfa, 0xe0, 0x55, 0x36,
+	0x01, 0x81, 0xc4, 0x0a, 0x81, 0x9a, 0x52, 0x89, 0x0a, 0x0a, 0x92, 0x5b, 0x36, 0x48, 0xc8, 0x9a,
+	0xda, 0x93, 0xd4, 0xaa, 0x3d, 0x13, 0xc6, 0xe3, 0xd2, 0xf0, 0x15, 0xac, 0xf8, 0x0b, 0xbe, 0x80,
+	0x1f, 0x40, 0xac, 0x58, 0xb2, 0x02, 0x54, 0x7e, 0x84, 0x3b, 0x33, 0x4e, 0xd2, 0x96, 0x22, 0x01,
+	0x0b, 0x16, 0x23, 0xcd, 0x9c, 0xfb, 0xf0, 0xbd, 0xe7, 0x3e, 0x0c, 0x90, 0x30, 0x45, 0xdb, 0x7d,
+	0x29, 0x94, 0x20, 0x57, 0x02, 0x91, 0xb4, 0x23, 0xde, 0x8d, 0xb3, 0xfd, 0x90, 0x6a, 0x34, 0xa6,
+	0xaa, 0x2b, 0x64, 0xd2, 0x56, 0x34, 0xdd, 0x6d, 0x6f, 0xd3, 0x60, 0x97, 0xf1, 0x70, 0x6e, 0xa6,
+	0x27, 0x7a, 0xc2, 0x18, 0xdc, 0xd4, 0x37, 0x6b, 0xbb, 0xf4, 0xae, 0x0a, 0x93, 0x9b, 0x4a, 0x48,
+	0xb6, 0x85, 0xba, 0x1b, 0xe8, 0x93, 0x5c, 0x87, 0x66, 0x42, 0xf7, 0xfd, 0x40, 0xf0, 0x20, 0x93,
+	0x92, 0xf1, 0x60, 0xe0, 0x16, 0x16, 0x0a, 0xcb, 0x65, 0xaf, 0x81, 0xf0, 0xea, 0x18, 0x25, 0x37,
+	0xa0, 0x85, 0x1f, 0x62, 0xa9, 0x42, 0xdd, 0xa4, 0x1f, 0x33, 0xc5, 0x42, 0xb7, 0x88, 0x9a, 0x8e,
+	0xd7, 0xb4, 0xf8, 0xea, 0x10, 0x26, 0xb3, 0x50, 0x49, 0x15, 0x55, 0x59, 0xea, 0x3a, 0xa8, 0x50,
+	0xf3, 0xf2, 0x17, 0x09, 0x60, 0xca, 0xba, 0x53, 0xf1, 0xc0, 0x97, 0x19, 0xe7, 0x11, 0xef, 0xb9,
+	0xa5, 0x05, 0x67, 0xb9, 0x7e, 0xfb, 0x6e, 0xfb, 0x4f, 0xb2, 0x6a, 0x1f, 0x89, 0xdd, 0xcb, 0xb8,
+	0xd7, 0x1a, 0x39, 0xf4, 0xac, 0x3f, 0x72, 0x15, 0x1a, 0xac, 0xdb, 0x65, 0x81, 0x8a, 0xf6, 0x98,
+	0x1f, 0x48, 0xc1, 0xdd, 0xb2, 0x09, 0x62, 0x72, 0x84, 0xae, 0x22, 0xa8, 0x63, 0x14, 0xdd, 0x6e,
+	0xca, 0x94, 0x5b, 0x31, 0xe9, 0xe6, 0x2f, 0x72, 0x11, 0x20, 0x90, 0x0c, 0x13, 0x0a, 0x7d, 0xaa,
+	0xdc, 0xaa, 0x49, 0xb0, 0x96, 0x23, 0x2b, 0x46, 0x9c, 0xf5, 0xc3, 0xa1, 0x78, 0xc2, 0x8a, 0x73,
+	0x04, 0xc5, 0xf7, 0xa1, 0x45, 0x33, 0xb5, 0x23, 0x64, 0xf4, 0x86, 0xaa, 0x48, 0x70, 0x3f, 0x0a,
+	0xdd, 0x1a, 0x2a, 0x95, 0x3a, 0xd3, 0x07, 0x5f, 0xe7, 0x9b, 0x2b, 0x87, 0x65, 0xeb, 0x0f, 0xbd,
+	0xe6, 0x11, 0xe5, 0xf5, 0x90, 0xbc, 0x84, 0x7a, 0x42, 0x79, 0x46, 0x63, 0x4d, 0x4f, 0xea, 0xb6,
+	0x0c, 0x37, 0xf7, 0xfe, 0x81, 0x9b, 0x0d, 0xe3, 0x45, 0x33, 0x04, 0xc9, 0xf0, 0x9a, 0xea, 0xe8,
+	0x43, 0xd6, 0x47, 0xe5, 0xd4, 0x47, 0x5e, 0xa6, 0xd0, 0x7b, 0xc9, 0xab, 0xe5, 0xc8, 0x33, 0x4e,
+	0xe6, 0xa1, 0x1e, 0x33, 0x9a, 0x32, 0x5f, 0xbc, 0xe6, 0x4c, 0xba, 0xc4, 0xf0, 0x06, 0x06, 0x7a,
+	0xa6, 0x11, 0xb2, 0x8c, 0x3d, 0x60, 0x14, 0xd8, 0x7e, 0x3f, 0x92, 0x2c, 0xd5, 0x1c, 0x4c, 0x1b,
+	0x0e, 0x1a, 0x06, 0x5f, 0xb3, 0x30, 0x12, 0xc1, 0x81, 0x70, 0xa1, 0xa2, 0x6e, 0x14, 0x58, 0x1e,
+	0x64, 0x16, 0xb3, 0xd4, 0x9d, 0x31, 0xf9, 0x3c, 0xf8, 0xcb, 0x7c, 0x9e, 0x1e, 0x72, 0xe4, 0xa1,
+	0x1f, 0x6f, 0x8a, 0x1f, 0x43, 0x52, 0x32, 0x07, 0x13, 0x92, 0xed, 0x45, 0x29, 0x02, 0xee, 0x19,
+	0x13, 0xd1, 0xe8, 0x4d, 0x1e, 0xc0, 0x94, 0x92, 0x51, 0xaf, 0xc7, 0xa4, 0xbf, 0x9d, 0xa1, 0x6f,
+	0xa5, 0xab, 0x32, 0x6b, 0xab, 0xf2, 0x09, 0xab, 0xb2, 0x65, 0x85, 0x1d, 0x23, 0xd3, 0x55, 0x51,
+	0x47, 0x80, 0x90, 0xdc, 0x84, 0xe9, 0xa1, 0x83, 0x04, 0xd3, 0xcc, 0x24, 0x4b, 0xb0, 0xe5, 0xdc,
+	0xb3, 0x86, 0x1f, 0x92, 0x8b, 0x36, 0xc6, 0x12, 0x72, 0x0b, 0x66, 0x46, 0x06, 0x11, 0x36, 0x01,
+	0x57, 0x4c, 0xee, 0xd1, 0xd8, 0x75, 0x4d, 0xab, 0x8d, 0x2c, 0x22, 0xbe, 0x9e, 0x4b, 0x96, 0x3e,
+	0x14, 0xa1, 0x75, 0xbc, 0xb9, 0x49, 0x0b, 0x1c, 0x2e, 0x5e, 0x9b, 0x79, 0x74, 0x3c, 0x7d, 0xd5,
+	0x88, 0x92, 0x03, 0x33, 0x77, 0x93, 0x9e, 0xbe, 0x92, 0x05, 0xa8, 0x60, 0xab, 0xe8, 0x8c, 0x1c,
+	0x93, 0x51, 0x0d, 0xfb, 0xac, 0x8c, 0xc6, 0x98, 0x47, 0x19, 0x05, 0x18, 0x3d, 0x56, 0x55, 0x52,
+	0xde, 0x63, 0x3e, 0x4e, 0xa1, 0x54, 0x38, 0x6f, 0xda, 0x1b, 0x18, 0x68, 0x53, 0x23, 0xe4, 0x3c,
+	0xd4, 0xac, 0x02, 0xb2, 0x6e, 0x86, 0x45, 0x93, 0xa7, 0x81, 0x35, 0x1e, 0x92, 0x45, 0x38, 0x2d,
+	0xd9, 0xab, 0x0c, 0xe7, 0xdb, 0xb6, 0x7c, 0xc5, 0xc8, 0xeb, 0x23, 0x0c, 0x6b, 0x7d, 0x98, 0xfb,
+	0xea, 0x31, 0xee, 0x2f, 0x40, 0x2d, 0xcf, 0x16, 0xd7, 0x85, 0x1e, 0x97, 0x09, 0x6f, 0x0c, 0x90,
+	0xcb, 0x30, 0x39, 0xe4, 0xc9, 0x06, 0x57, 0x33, 0xe6, 0xa7, 0x73, 0xd0, 0x86, 0x87, 0x11, 0x8c,
+	0x95, 0x44, 0xdf, 0x05, 0x1b, 0xc1, 0x48, 0x47, 0xf4, 0x97, 0xbe, 0x15, 0x61, 0xf6, 0xe4, 0xf6,
+	0x27, 0x33, 0x50, 0xb6, 0xae, 0x2d, 0x8b, 0xf6, 0xa1, 0x79, 0xd4, 0xc9, 0xda, 0xfd, 0xa5, 0xaf,
+	0x27, 0xae, 0x37, 0xe7, 0xe4, 0xf5, 0x76, 0x9c, 0x92, 0xd2, 0xaf, 0x94, 0x8c, 0xab, 0x52, 0xfe,
+	0x4d, 0x55, 0x70, 0xff, 0xf4, 0x69, 0x96, 0xe2, 0x57, 0x2a, 0x86, 0x95, 0xfc, 0x85, 0xbd, 0x56,
+	0xd7, 0x03, 0xd0, 0x8d, 0xe2, 0x58, 0x9b, 0x57, 0x8d, 0x79, 0x03, 0xcd, 0xa1, 0x93, 0xc3, 0xe8,
+	0x03, 0x86, 0x2a, 0xe8, 0xe8, 0xff, 0x30, 0xfc, 0xbe, 0x00, 0xe7, 0x7e, 0x3b, 0x90, 0xa4, 0x01,
+	0x45, 0xac, 0x7d, 0xc1, 0xcc, 0x43, 0x31, 0xaf, 0xfa, 0x0e, 0x2e, 0x82, 0x1d, 0x11, 0x5b, 0x92,
+	0xcb, 0xde, 0x18, 0x20, 0xe7, 0xc0, 0xc9, 0x64, 0x6c, 0xff, 0x0d, 0x9d, 0x2a, 0xa6, 0xe6, 0x3c,
+	0xf7, 0x9e, 0x78, 0x1a, 0xd3, 0xbd, 0xaa, 0x57, 0xa2, 0xbf, 0xc3, 0x68, 0x88, 0x1b, 0xa8, 0x64,
+	0x37, 0x90, 0x86, 0x1e, 0x19, 0x84, 0x5c, 0x03, 0xb3, 0x33, 0xfd, 0x94, 0xe1, 0x4e, 0x56, 0xfe,
+	0x2e, 0x1b, 0x0c, 0xd7, 0xbb, 0x86, 0x37, 0x0d, 0xfa, 0x98, 0x0d, 0x3a, 0x8b, 0x1f, 0x0f, 0x2e,
+	0x15, 0x3e, 0xe3, 0xf9, 0x8e, 0xe7, 0xed, 0x8f, 0x4b, 0xa7, 0x3e, 0xe3, 0xf9, 0x82, 0xe7, 0x45,
+	0x35, 0xdf, 0x2f, 0xdb, 0x15, 0xf3, 0x4b, 0xbc, 0xf3, 0x13, 0xa6, 0xe4, 0xc1, 0x0b, 0x5c, 0x07,
+	0x00, 0x00,
 }
@@ -2,6 +2,8 @@ package backend_test
 
 import (
 	"errors"
+	"math"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -9,6 +11,7 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/options"
 )
 
 var idGen = snowflake.NewIDGenerator()
@@ -279,3 +282,397 @@ func TestMeta_ManuallyRunTimeRange(t *testing.T) {
 
 	// Not currently enforcing one way or another when a newly requested time range overlaps with an existing one.
 }
+
+func TestMeta_RetryRun(t *testing.T) {
+	stm := backend.StoreTaskMeta{
+		MaxConcurrency:  2,
+		Status:          "enabled",
+		EffectiveCron:   "* * * * *", // Every minute.
+		LatestCompleted: 60,
+	}
+
+	rc, err := stm.CreateNextRun(300, makeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldID := rc.Created.RunID
+
+	qr, err := stm.RetryRun(oldID, makeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qr.RunID == oldID {
+		t.Fatalf("expected retry to have a new run ID, got %s", qr.RunID)
+	}
+	if qr.Now != rc.Created.Now {
+		t.Fatalf("expected retry now %d, got %d", rc.Created.Now, qr.Now)
+	}
+	if qr.Try != 2 {
+		t.Fatalf("expected retry try 2, got %d", qr.Try)
+	}
+
+	if len(stm.CurrentlyRunning) != 1 {
+		t.Fatalf("expected 1 run in progress, got %d", len(stm.CurrentlyRunning))
+	}
+	if cr := stm.CurrentlyRunning[0]; platform.ID(cr.RunID) != qr.RunID || cr.Try != 2 {
+		t.Fatalf("expected retry to replace the run in progress, got %#v", cr)
+	}
+
+	if _, err := stm.RetryRun(oldID, makeID); err != backend.ErrRunNotFound {
+		t.Fatalf("expected ErrRunNotFound, got %v", err)
+	}
+}
+
+func TestMeta_Backfill(t *testing.T) {
+	t.Run("every", func(t *testing.T) {
+		stm := backend.StoreTaskMeta{
+			MaxConcurrency:  2,
+			Status:          "enabled",
+			EffectiveCron:   "@every 10s",
+			LatestCompleted: 1000,
+		}
+
+		// The range is aligned to the schedules of the task, at multiples of 10s.
+		mr, err := stm.Backfill(5, 41, 2000, makeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mr.Start != 10 || mr.End != 40 {
+			t.Fatalf("expected backfill from 10 to 40, got %d to %d", mr.Start, mr.End)
+		}
+		if mr.BackfillID == 0 || mr.RunID != 0 {
+			t.Fatalf("expected backfill to have a backfill ID but no run ID, got %#v", mr)
+		}
+		if completed, total, err := stm.BackfillProgress(mr); err != nil || completed != 0 || total != 4 {
+			t.Fatalf("expected 0 of 4 runs completed, got %d of %d (%v)", completed, total, err)
+		}
+
+		// The runs of the backfill are created in order, up to the task's concurrency.
+		for _, exp := range []int64{10, 20} {
+			rc, err := stm.CreateNextRun(1001, makeID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rc.Created.Now != exp {
+				t.Fatalf("expected run for %d, got %d", exp, rc.Created.Now)
+			}
+		}
+		if _, err := stm.CreateNextRun(1001, makeID); err == nil {
+			t.Fatal("expected failure when max concurrency reached")
+		}
+
+		if !stm.FinishRun(platform.ID(stm.CurrentlyRunning[0].RunID)) {
+			t.Fatal("expected to finish run")
+		}
+		if completed, total, err := stm.BackfillProgress(mr); err != nil || completed != 1 || total != 4 {
+			t.Fatalf("expected 1 of 4 runs completed, got %d of %d (%v)", completed, total, err)
+		}
+
+		// A paused backfill creates no runs.
+		if _, err := stm.SetBackfillPaused(platform.ID(mr.BackfillID), true); err != nil {
+			t.Fatal(err)
+		}
+		if stm.HasQueue() {
+			t.Fatal("expected no queue while backfill is paused")
+		}
+		if _, err := stm.CreateNextRun(1001, makeID); err == nil {
+			t.Fatal("expected no run to be created while backfill is paused")
+		}
+
+		if _, err := stm.SetBackfillPaused(platform.ID(mr.BackfillID), false); err != nil {
+			t.Fatal(err)
+		}
+		for _, exp := range []int64{30, 40} {
+			if exp == 40 {
+				stm.FinishRun(platform.ID(stm.CurrentlyRunning[0].RunID))
+			}
+			rc, err := stm.CreateNextRun(1001, makeID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rc.Created.Now != exp {
+				t.Fatalf("expected run for %d, got %d", exp, rc.Created.Now)
+			}
+		}
+		if len(stm.ManualRuns) != 0 {
+			t.Fatalf("expected backfill to be dropped after its last run, got %v", stm.ManualRuns)
+		}
+	})
+
+	t.Run("cron", func(t *testing.T) {
+		stm := backend.StoreTaskMeta{
+			MaxConcurrency:  1,
+			Status:          "enabled",
+			EffectiveCron:   "*/5 * * * *", // Every 5 minutes.
+			LatestCompleted: 3600,
+		}
+
+		mr, err := stm.Backfill(1, 3599, 4000, makeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mr.Start != 300 || mr.End != 3300 {
+			t.Fatalf("expected backfill from 300 to 3300, got %d to %d", mr.Start, mr.End)
+		}
+		if _, total, err := stm.BackfillProgress(mr); err != nil || total != 11 {
+			t.Fatalf("expected 11 runs, got %d (%v)", total, err)
+		}
+
+		rc, err := stm.CreateNextRun(3601, makeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rc.Created.Now != 300 {
+			t.Fatalf("expected run for 300, got %d", rc.Created.Now)
+		}
+
+		if _, err := stm.Backfill(10, 20, 4000, makeID); err != backend.ErrNoSchedulesInRange {
+			t.Fatalf("expected ErrNoSchedulesInRange, got %v", err)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		stm := backend.StoreTaskMeta{
+			MaxConcurrency:  1,
+			Status:          "enabled",
+			EffectiveCron:   "@every 1m",
+			LatestCompleted: 3600,
+		}
+
+		mr, err := stm.Backfill(0, 600, 4000, makeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := stm.ManuallyRunTimeRange(120, 120, 4001, makeID); err != nil {
+			t.Fatal(err)
+		}
+
+		canceled, err := stm.CancelBackfill(platform.ID(mr.BackfillID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if canceled.Start != 0 || canceled.End != 600 {
+			t.Fatalf("expected canceled backfill from 0 to 600, got %d to %d", canceled.Start, canceled.End)
+		}
+		if len(stm.ManualRuns) != 1 || stm.ManualRuns[0].RequestedAt != 4001 {
+			t.Fatalf("expected only forced run to remain queued, got %v", stm.ManualRuns)
+		}
+
+		if _, err := stm.CancelBackfill(platform.ID(mr.BackfillID)); err != backend.ErrBackfillNotFound {
+			t.Fatalf("expected ErrBackfillNotFound, got %v", err)
+		}
+		if _, err := stm.SetBackfillPaused(platform.ID(mr.BackfillID), true); err != backend.ErrBackfillNotFound {
+			t.Fatalf("expected ErrBackfillNotFound, got %v", err)
+		}
+	})
+}
+
+func TestMeta_Dependencies(t *testing.T) {
+	stm := backend.StoreTaskMeta{
+		MaxConcurrency:  2,
+		Status:          "active",
+		EffectiveCron:   "@every 1m",
+		LatestCompleted: 60,
+		DependsOn:       []uint64{1, 2},
+	}
+
+	if !stm.DependsOnTask(2) || stm.DependsOnTask(3) {
+		t.Fatalf("unexpected upstream tasks %v", stm.DependsOn)
+	}
+
+	// A task with dependencies is never due on its own schedule.
+	if due, err := stm.NextDueRun(); err != nil || due != math.MaxInt64 {
+		t.Fatalf("expected task never to be due, got %d (%v)", due, err)
+	}
+	if _, err := stm.CreateNextRun(1000, makeID); err == nil {
+		t.Fatal("expected no run to be created without a successful upstream run")
+	}
+
+	// Only upstream runs for a time on the task's schedule request a run.
+	if queued, err := stm.QueueDependentRun(150, 1000); err != nil || queued {
+		t.Fatalf("expected no run to be requested for time not on schedule, got %v (%v)", queued, err)
+	}
+	if queued, err := stm.QueueDependentRun(180, 1000); err != nil || !queued {
+		t.Fatalf("expected run to be requested, got %v (%v)", queued, err)
+	}
+	// Another upstream task succeeding for the same time doesn't request another run.
+	if queued, err := stm.QueueDependentRun(180, 1001); err != nil || queued {
+		t.Fatalf("expected no other run to be requested while run is queued, got %v (%v)", queued, err)
+	}
+
+	rc, err := stm.CreateNextRun(1000, makeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Created.Now != 180 || rc.HasQueue || rc.NextDue != math.MaxInt64 {
+		t.Fatalf("expected run at 180 without further queue, got %#v", rc)
+	}
+
+	var o backend.StoreTaskMeta
+	o.SetDependsOn(options.Options{DependsOn: []string{"0000000000000003", "not an ID"}})
+	if len(o.DependsOn) != 1 || o.DependsOn[0] != 3 {
+		t.Fatalf("expected dependency on task 3, got %v", o.DependsOn)
+	}
+}
+
+func TestMeta_Trigger(t *testing.T) {
+	var stm backend.StoreTaskMeta
+	stm.SetTrigger(options.Options{Trigger: &options.Trigger{BucketID: "0000000000000007", Measurement: "cpu", MinInterval: time.Minute}})
+	stm.MaxConcurrency = 1
+	stm.Status = "active"
+	if !stm.HasTrigger() || stm.TriggerMinInterval != 60 {
+		t.Fatalf("unexpected trigger %d %q %d", stm.TriggerBucketID, stm.TriggerMeasurement, stm.TriggerMinInterval)
+	}
+	if !stm.TriggeredBy(7, "cpu") || stm.TriggeredBy(7, "mem") || stm.TriggeredBy(8, "cpu") {
+		t.Fatal("unexpected writes triggering task")
+	}
+
+	// A task with a trigger is never due on its own schedule.
+	if due, err := stm.NextDueRun(); err != nil || due != math.MaxInt64 {
+		t.Fatalf("expected task never to be due, got %d (%v)", due, err)
+	}
+	if _, err := stm.CreateNextRun(1000, makeID); err == nil {
+		t.Fatal("expected no run to be created without writes")
+	}
+
+	if err := stm.QueueTriggeredRun(1000, 50e9, 60e9, 1000); err != nil {
+		t.Fatal(err)
+	}
+	// Further writes before the run starts widen the range of the queued run.
+	if err := stm.QueueTriggeredRun(1001, 40e9, 55e9, 1001); err != nil {
+		t.Fatal(err)
+	}
+	if len(stm.ManualRuns) != 1 {
+		t.Fatalf("expected one queued run, got %d", len(stm.ManualRuns))
+	}
+
+	rc, err := stm.CreateNextRun(1002, makeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Created.Now != 1000 || !rc.Created.Triggered || rc.Created.TriggerStart != 40e9 || rc.Created.TriggerStop != 60e9 {
+		t.Fatalf("unexpected triggered run %#v", rc.Created)
+	}
+	if rc.HasQueue || rc.NextDue != math.MaxInt64 {
+		t.Fatalf("expected no further queue, got %#v", rc)
+	}
+
+	var o backend.StoreTaskMeta
+	o.SetTrigger(options.Options{Trigger: &options.Trigger{BucketID: "not an ID"}})
+	if o.HasTrigger() {
+		t.Fatalf("expected no trigger, got bucket %d", o.TriggerBucketID)
+	}
+}
+
+func TestMeta_Lease(t *testing.T) {
+	var stm backend.StoreTaskMeta
+
+	if err := stm.Lease("a", 100, 130); err != nil {
+		t.Fatal(err)
+	}
+	if stm.LeaseHeldByOther("a", 110) || !stm.LeaseHeldByOther("b", 110) {
+		t.Fatalf("expected lease held by a, got %q until %d", stm.LeaseOwner, stm.LeaseExpiresAt)
+	}
+	if err := stm.Lease("b", 110, 140); err != backend.ErrTaskLeased {
+		t.Fatalf("expected ErrTaskLeased, got %v", err)
+	}
+
+	// An expired lease can be taken over.
+	if stm.LeaseHeldByOther("b", 130) {
+		t.Fatal("expected lease to have expired")
+	}
+	if err := stm.Lease("b", 130, 160); err != nil {
+		t.Fatal(err)
+	}
+	if stm.LeaseOwner != "b" || stm.LeaseExpiresAt != 160 {
+		t.Fatalf("expected lease of b until 160, got %q until %d", stm.LeaseOwner, stm.LeaseExpiresAt)
+	}
+
+	if stm.ReleaseLease("a") {
+		t.Fatal("expected release by a non-owner to fail")
+	}
+	if !stm.ReleaseLease("b") || stm.LeaseOwner != "" || stm.LeaseExpiresAt != 0 {
+		t.Fatalf("expected lease to be released, got %q until %d", stm.LeaseOwner, stm.LeaseExpiresAt)
+	}
+}
+
+func TestMeta_NextSchedules(t *testing.T) {
+	stm := backend.StoreTaskMeta{
+		EffectiveCron:   "@every 1m",
+		LatestCompleted: 60,
+	}
+	schedules, err := stm.NextSchedules(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(schedules, []int64{120, 180, 240}) {
+		t.Fatalf("unexpected schedules %v", schedules)
+	}
+
+	stm.EffectiveCron = "* * * * *"
+	if schedules, err = stm.NextSchedules(2); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(schedules, []int64{120, 180}) {
+		t.Fatalf("unexpected schedules %v", schedules)
+	}
+
+	stm.DependsOn = []uint64{1}
+	if schedules, err = stm.NextSchedules(2); err != nil || len(schedules) != 0 {
+		t.Fatalf("expected no schedules for task with dependencies, got %v (%v)", schedules, err)
+	}
+
+	stm.EffectiveCron = "not a cron"
+	if _, err := stm.NextSchedules(2); err == nil {
+		t.Fatal("expected error for invalid cron")
+	}
+}
+
+func TestStoreTaskNotificationRule_Triggered(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		rule     backend.StoreTaskNotificationRule
+		status   backend.RunStatus
+		failures int
+		want     bool
+	}{
+		{name: "failure on failed run", rule: backend.StoreTaskNotificationRule{On: platform.TaskNotifyOnFailure}, status: backend.RunFail, failures: 2, want: true},
+		{name: "failure on successful run", rule: backend.StoreTaskNotificationRule{On: platform.TaskNotifyOnFailure}, status: backend.RunSuccess, failures: 1, want: false},
+		{name: "consecutive failures below threshold", rule: backend.StoreTaskNotificationRule{On: platform.TaskNotifyOnConsecutiveFailures, Threshold: 3}, status: backend.RunFail, failures: 2, want: false},
+		{name: "consecutive failures at threshold", rule: backend.StoreTaskNotificationRule{On: platform.TaskNotifyOnConsecutiveFailures, Threshold: 3}, status: backend.RunFail, failures: 3, want: true},
+		{name: "consecutive failures past threshold", rule: backend.StoreTaskNotificationRule{On: platform.TaskNotifyOnConsecutiveFailures, Threshold: 3}, status: backend.RunFail, failures: 4, want: false},
+		{name: "recovery after failure", rule: backend.StoreTaskNotificationRule{On: platform.TaskNotifyOnRecovery}, status: backend.RunSuccess, failures: 1, want: true},
+		{name: "recovery without failure", rule: backend.StoreTaskNotificationRule{On: platform.TaskNotifyOnRecovery}, status: backend.RunSuccess, failures: 0, want: false},
+		{name: "unknown condition", rule: backend.StoreTaskNotificationRule{On: "sometimes"}, status: backend.RunFail, failures: 1, want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Triggered(tt.status, tt.failures); got != tt.want {
+				t.Fatalf("Triggered(%s, %d) = %v, want %v", tt.status, tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeta_NextRevisions(t *testing.T) {
+	stm := backend.StoreTaskMeta{CreatedAt: 10, Revision: 1}
+	revs := stm.NextRevisions("old", "new", 3, 20)
+	if stm.Revision != 2 {
+		t.Fatalf("expected revision 2, got %d", stm.Revision)
+	}
+	if !reflect.DeepEqual(revs, []backend.StoreTaskRevision{{Revision: 2, Script: "new", UpdatedBy: 3, UpdatedAt: 20}}) {
+		t.Fatalf("unexpected revisions %+v", revs)
+	}
+
+	// A task without revisions keeps its old script as the first revision.
+	stm = backend.StoreTaskMeta{CreatedAt: 10}
+	revs = stm.NextRevisions("old", "new", 3, 20)
+	if stm.Revision != 2 {
+		t.Fatalf("expected revision 2, got %d", stm.Revision)
+	}
+	if !reflect.DeepEqual(revs, []backend.StoreTaskRevision{
+		{Revision: 1, Script: "old", UpdatedAt: 10},
+		{Revision: 2, Script: "new", UpdatedBy: 3, UpdatedAt: 20},
+	}) {
+		t.Fatalf("unexpected revisions %+v", revs)
+	}
+}
@@ -0,0 +1,18 @@
+package notifier
+
+import (
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+// NewWebhookWithClient returns a Webhook sending notifications with c, which may reach loopback addresses.
+// Redirects are followed as they are by a Webhook.
+func NewWebhookWithClient(ss influxdb.SecretService, lr backend.LogReader, c *http.Client) *Webhook {
+	w := NewWebhook(ss, lr)
+	client := *c
+	client.CheckRedirect = w.client.CheckRedirect
+	w.client = &client
+	return w
+}
@@ -0,0 +1,182 @@
+// Package notifier contains implementations of backend.RunNotifier.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+// MaxLogLines is the maximum number of lines of the log of a run included in a notification.
+const MaxLogLines = 20
+
+// Payload is the JSON body of a notification about a run.
+type Payload struct {
+	TaskID       influxdb.ID `json:"taskID"`
+	TaskName     string      `json:"taskName"`
+	RunID        influxdb.ID `json:"runID"`
+	ScheduledFor string      `json:"scheduledFor"`
+	Status       string      `json:"status"`
+	Error        string      `json:"error,omitempty"`
+
+	// On is the condition of the notification rule which was triggered by the run.
+	On string `json:"on"`
+
+	// ConsecutiveFailures is the number of failed runs of the task in a row,
+	// ending with this run if it failed, or just before this run if it succeeded.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// Logs are the last lines of the log of the run, oldest first.
+	Logs []string `json:"logs"`
+}
+
+// Webhook is a backend.RunNotifier which posts a Payload to the URL of a notification rule.
+type Webhook struct {
+	secrets   influxdb.SecretService
+	logReader backend.LogReader
+	client    *http.Client
+}
+
+var _ backend.RunNotifier = (*Webhook)(nil)
+
+// NewWebhook returns a Webhook which resolves the auth headers of notification rules from the secrets of ss,
+// and reads the logs of runs from lr.
+// Notifications are not sent to loopback, link-local or private addresses, whichever address the URL of a rule
+// resolves or redirects to, so that rules can't reach the services of the host or of its network.
+// The auth header of a rule is not sent when a notification is redirected to another host.
+func NewWebhook(ss influxdb.SecretService, lr backend.LogReader) *Webhook {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: checkAddress,
+	}
+	return &Webhook{
+		secrets:   ss,
+		logReader: lr,
+		client: &http.Client{
+			// No proxy, so that the address of the notification URL itself is checked.
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			CheckRedirect: checkRedirect,
+		},
+	}
+}
+
+// checkAddress returns an error if address is a loopback, link-local, private or unspecified address.
+// It is called with the resolved address of each connection made by a Webhook.
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || influxdb.IsInternalIP(ip) {
+		return fmt.Errorf("notifications to %s are not allowed", host)
+	}
+	return nil
+}
+
+// authHeaderKey is the context key of the name of the auth header of the notification rule of a request.
+type authHeaderKey struct{}
+
+// checkRedirect removes the auth header of the notification rule from a request redirected to another host,
+// as the http.Client does for the Authorization header, since the header of a rule may have any name.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if h, _ := req.Context().Value(authHeaderKey{}).(string); h != "" && req.URL.Host != via[0].URL.Host {
+		req.Header.Del(h)
+	}
+	return nil
+}
+
+// Notify posts a Payload describing n to the URL of rule.
+// A response with a status other than 2xx is an error.
+func (w *Webhook) Notify(ctx context.Context, n backend.RunNotification, rule *backend.StoreTaskNotificationRule) error {
+	p := Payload{
+		TaskID:              n.Task.ID,
+		TaskName:            n.Task.Name,
+		RunID:               n.RunID,
+		ScheduledFor:        time.Unix(n.RunScheduledFor, 0).UTC().Format(time.RFC3339),
+		Status:              n.Status.String(),
+		On:                  rule.On,
+		ConsecutiveFailures: n.ConsecutiveFailures,
+		Logs:                w.lastLogLines(ctx, n),
+	}
+	if n.Err != nil {
+		p.Error = n.Err.Error()
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", rule.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	if rule.AuthHeader != "" {
+		secret, err := w.secrets.LoadSecret(ctx, n.Task.Org, rule.AuthSecretKey)
+		if err != nil {
+			return fmt.Errorf("failed to load secret %q: %v", rule.AuthSecretKey, err)
+		}
+		req.Header.Set(rule.AuthHeader, secret)
+		req = req.WithContext(context.WithValue(ctx, authHeaderKey{}, rule.AuthHeader))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// lastLogLines returns up to MaxLogLines of the last lines of the log of the run of n.
+// A notification is still sent if the log can't be read, so errors result in no lines.
+func (w *Webhook) lastLogLines(ctx context.Context, n backend.RunNotification) []string {
+	runID := n.RunID
+	logs, err := w.logReader.ListLogs(ctx, n.Task.Org, influxdb.LogFilter{Task: n.Task.ID, Run: &runID})
+	if err != nil {
+		return []string{}
+	}
+
+	var lines []string
+	for _, l := range logs {
+		for _, line := range strings.Split(string(l), "\n") {
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	if len(lines) > MaxLogLines {
+		lines = lines[len(lines)-MaxLogLines:]
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+	return lines
+}
@@ -0,0 +1,145 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/notifier"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	var (
+		gotPayload   notifier.Payload
+		gotAuth      string
+		gotOtherAuth *string
+	)
+	// other is another host notifications may be redirected to.
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("X-Hook-Token")
+		gotOtherAuth = &auth
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/hook", http.StatusTemporaryRedirect)
+			return
+		case "/elsewhere":
+			http.Redirect(w, r, other.URL+"/hook", http.StatusTemporaryRedirect)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization") + r.Header.Get("X-Hook-Token")
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Error(err)
+		}
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	ss := mock.NewSecretService()
+	ss.LoadSecretFn = func(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+		if orgID != 2 || k != "hook-token" {
+			return "", errors.New("secret not found")
+		}
+		return "Bearer s3cr3t", nil
+	}
+
+	lrw := backend.NewInMemRunReaderWriter()
+	task := &backend.StoreTask{ID: 1, Org: 2, Name: "a task"}
+	rlb := backend.RunLogBase{Task: task, RunID: 3, RunScheduledFor: 60}
+	if err := lrw.UpdateRunState(context.Background(), rlb, time.Now(), backend.RunStarted); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < notifier.MaxLogLines+5; i++ {
+		if err := lrw.AddRunLog(context.Background(), rlb, time.Now(), fmt.Sprintf("line %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := notifier.NewWebhookWithClient(ss, lrw, srv.Client())
+	n := backend.RunNotification{
+		RunLogBase:          rlb,
+		Status:              backend.RunFail,
+		Err:                 errors.New("bad query"),
+		ConsecutiveFailures: 3,
+	}
+	rule := &backend.StoreTaskNotificationRule{
+		On:            influxdb.TaskNotifyOnConsecutiveFailures,
+		Threshold:     3,
+		URL:           srv.URL + "/hook",
+		AuthHeader:    "Authorization",
+		AuthSecretKey: "hook-token",
+	}
+	if err := w.Notify(context.Background(), n, rule); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotAuth != "Bearer s3cr3t" {
+		t.Fatalf("expected auth header from secret, got %q", gotAuth)
+	}
+	if gotPayload.TaskID != task.ID || gotPayload.TaskName != task.Name || gotPayload.RunID != rlb.RunID ||
+		gotPayload.ScheduledFor != "1970-01-01T00:01:00Z" || gotPayload.Status != "failed" || gotPayload.Error != "bad query" ||
+		gotPayload.On != influxdb.TaskNotifyOnConsecutiveFailures || gotPayload.ConsecutiveFailures != 3 {
+		t.Fatalf("unexpected payload %+v", gotPayload)
+	}
+	if len(gotPayload.Logs) != notifier.MaxLogLines || !strings.HasSuffix(gotPayload.Logs[len(gotPayload.Logs)-1], fmt.Sprintf("line %d", notifier.MaxLogLines+4)) {
+		t.Fatalf("expected last %d log lines, got %v", notifier.MaxLogLines, gotPayload.Logs)
+	}
+
+	// The auth header is kept when a notification is redirected to the same host,
+	// but not when it is redirected to another host.
+	gotAuth = ""
+	rule.URL = srv.URL + "/moved"
+	rule.AuthHeader = "X-Hook-Token"
+	if err := w.Notify(context.Background(), n, rule); err != nil {
+		t.Fatal(err)
+	}
+	if gotAuth != "Bearer s3cr3t" {
+		t.Fatalf("expected auth header after redirect to the same host, got %q", gotAuth)
+	}
+	rule.URL = srv.URL + "/elsewhere"
+	if err := w.Notify(context.Background(), n, rule); err != nil {
+		t.Fatal(err)
+	}
+	if gotOtherAuth == nil {
+		t.Fatal("expected notification to be redirected to another host")
+	} else if *gotOtherAuth != "" {
+		t.Fatalf("expected no auth header after redirect to another host, got %q", *gotOtherAuth)
+	}
+	rule.AuthHeader = "Authorization"
+
+	// Unsuccessful responses are errors.
+	rule.URL = srv.URL + "/down"
+	if err := w.Notify(context.Background(), n, rule); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected error for unavailable endpoint, got %v", err)
+	}
+
+	// So are secrets which can't be loaded.
+	rule.URL = srv.URL + "/hook"
+	rule.AuthSecretKey = "missing"
+	if err := w.Notify(context.Background(), n, rule); err == nil {
+		t.Fatal("expected error for missing secret")
+	}
+
+	// Notifications aren't sent to loopback addresses.
+	gotAuth = ""
+	rule.AuthSecretKey = "hook-token"
+	if err := notifier.NewWebhook(ss, lrw).Notify(context.Background(), n, rule); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("expected error for loopback address, got %v", err)
+	}
+	if gotAuth != "" {
+		t.Fatalf("expected no notification to be sent to a loopback address, got auth header %q", gotAuth)
+	}
+}
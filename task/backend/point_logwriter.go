@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
@@ -11,9 +12,12 @@ import (
 
 const (
 	lineField         = "line"
+	levelField        = "level"
+	logFieldsField    = "fields"
 	runIDField        = "runID"
 	scheduledForField = "scheduledFor"
 	requestedAtField  = "requestedAt"
+	revisionField     = "revision"
 	statusField       = "status"
 
 	taskIDTag = "taskID"
@@ -42,13 +46,16 @@ func (p *PointLogWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, whe
 	tags := models.Tags{
 		models.NewTag([]byte(taskIDTag), []byte(rlb.Task.ID.String())),
 	}
-	fields := make(map[string]interface{}, 4)
+	fields := make(map[string]interface{}, 5)
 	fields[statusField] = status.String()
 	fields[runIDField] = rlb.RunID.String()
 	fields[scheduledForField] = time.Unix(rlb.RunScheduledFor, 0).UTC().Format(time.RFC3339)
 	if rlb.RequestedAt != 0 {
 		fields[requestedAtField] = time.Unix(rlb.RequestedAt, 0).UTC().Format(time.RFC3339)
 	}
+	if rlb.Revision != 0 {
+		fields[revisionField] = rlb.Revision
+	}
 
 	pt, err := models.NewPoint("records", tags, fields, when)
 	if err != nil {
@@ -64,13 +71,27 @@ func (p *PointLogWriter) UpdateRunState(ctx context.Context, rlb RunLogBase, whe
 	return p.pointsWriter.WritePoints(ctx, exploded)
 }
 
-func (p *PointLogWriter) AddRunLog(ctx context.Context, rlb RunLogBase, when time.Time, log string) error {
+// AddRunLog writes the log entry as a point of the logs measurement.
+// The fields of the entry are written as a single JSON-encoded field.
+func (p *PointLogWriter) AddRunLog(ctx context.Context, rlb RunLogBase, when time.Time, entry RunLogEntry) error {
 	tags := models.Tags{
 		models.NewTag([]byte(taskIDTag), []byte(rlb.Task.ID.String())),
 	}
+	level := entry.Level
+	if level == "" {
+		level = platform.LogLevelInfo
+	}
 	fields := map[string]interface{}{
 		runIDField: rlb.RunID.String(),
-		lineField:  log,
+		lineField:  entry.Message,
+		levelField: string(level),
+	}
+	if len(entry.Fields) > 0 {
+		b, err := json.Marshal(entry.Fields)
+		if err != nil {
+			return err
+		}
+		fields[logFieldsField] = string(b)
 	}
 	pt, err := models.NewPoint("logs", tags, fields, when)
 	if err != nil {
@@ -85,3 +106,73 @@ func (p *PointLogWriter) AddRunLog(ctx context.Context, rlb RunLogBase, when tim
 
 	return p.pointsWriter.WritePoints(ctx, exploded)
 }
+
+// AddRunStatistics writes the statistics as a point of the statistics measurement.
+func (p *PointLogWriter) AddRunStatistics(ctx context.Context, rlb RunLogBase, when time.Time, stats platform.RunStatistics) error {
+	tags := models.Tags{
+		models.NewTag([]byte(taskIDTag), []byte(rlb.Task.ID.String())),
+	}
+	fields := map[string]interface{}{
+		runIDField: rlb.RunID.String(),
+	}
+	for k, v := range statisticsFields(stats) {
+		fields[k] = v
+	}
+	pt, err := models.NewPoint("statistics", tags, fields, when)
+	if err != nil {
+		return err
+	}
+
+	exploded, err := tsdb.ExplodePoints(rlb.Task.Org, taskSystemBucketID, []models.Point{pt})
+	if err != nil {
+		return err
+	}
+
+	return p.pointsWriter.WritePoints(ctx, exploded)
+}
+
+// statisticsFields returns the fields of a point of the statistics measurement, which are named after the JSON of stats.
+func statisticsFields(stats platform.RunStatistics) map[string]int64 {
+	return map[string]int64{
+		"totalDuration":   int64(stats.TotalDuration),
+		"compileDuration": int64(stats.CompileDuration),
+		"queueDuration":   int64(stats.QueueDuration),
+		"planDuration":    int64(stats.PlanDuration),
+		"requeueDuration": int64(stats.RequeueDuration),
+		"executeDuration": int64(stats.ExecuteDuration),
+		"concurrency":     int64(stats.Concurrency),
+		"maxAllocated":    stats.MaxAllocated,
+		"scannedValues":   stats.ScannedValues,
+		"scannedBytes":    stats.ScannedBytes,
+		"rowsWritten":     stats.RowsWritten,
+	}
+}
+
+// setStatisticsField sets the field of stats with the given name of a point of the statistics measurement.
+// Unknown names are ignored.
+func setStatisticsField(stats *platform.RunStatistics, name string, v int64) {
+	switch name {
+	case "totalDuration":
+		stats.TotalDuration = time.Duration(v)
+	case "compileDuration":
+		stats.CompileDuration = time.Duration(v)
+	case "queueDuration":
+		stats.QueueDuration = time.Duration(v)
+	case "planDuration":
+		stats.PlanDuration = time.Duration(v)
+	case "requeueDuration":
+		stats.RequeueDuration = time.Duration(v)
+	case "executeDuration":
+		stats.ExecuteDuration = time.Duration(v)
+	case "concurrency":
+		stats.Concurrency = int(v)
+	case "maxAllocated":
+		stats.MaxAllocated = v
+	case "scannedValues":
+		stats.ScannedValues = v
+	case "scannedBytes":
+		stats.ScannedBytes = v
+	case "rowsWritten":
+		stats.RowsWritten = v
+	}
+}
@@ -2,10 +2,10 @@ package backend
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/influxdata/flux/values"
@@ -81,9 +81,9 @@ func (qlr *QueryLogReader) ListLogs(ctx context.Context, orgID platform.ID, logF
 		return nil, ErrNoRunsFound
 	}
 
-	logs := make([]platform.Log, len(runs))
-	for i, r := range runs {
-		logs[i] = r.Log
+	var logs []platform.Log
+	for _, r := range runs {
+		logs = append(logs, r.Log...)
 	}
 	return logs, nil
 }
@@ -111,6 +111,14 @@ func (qlr *QueryLogReader) ListRuns(ctx context.Context, orgID platform.ID, runF
 	listScript := fmt.Sprintf(`
 import "influxdata/influxdb/v1"
 
+from(bucketID: "000000000000000a")
+	|> range(start: -24h)
+	|> filter(fn: (r) => r._measurement == "statistics" and r.taskID == %q)
+	|> drop(columns: ["_start", "_stop"])
+	|> group(columns: ["_measurement", "taskID"])
+	|> v1.fieldsAsCols()
+	|> yield(name: "statistics")
+
 from(bucketID: "000000000000000a")
   |> range(start: -24h)
 	|> filter(fn: (r) => r._measurement == "records" and r.taskID == %q)
@@ -120,7 +128,7 @@ from(bucketID: "000000000000000a")
 	|> filter(fn: (r) => r.scheduledFor < %q and r.scheduledFor > %q and r.runID > %q)
 	|> pivot(rowKey:["runID", "scheduledFor"], columnKey: ["status"], valueColumn: "_time")
 	%s
-	`, runFilter.Task.String(), scheduledBefore, scheduledAfter, afterID, limit)
+	`, runFilter.Task.String(), runFilter.Task.String(), scheduledBefore, scheduledAfter, afterID, limit)
 
 	auth, err := pctx.GetAuthorizer(ctx)
 	if err != nil {
@@ -160,6 +168,14 @@ logs = from(bucketID: "000000000000000a")
 	|> filter(fn: (r) => r.runID == %q)
 	|> yield(name: "logs")
 
+from(bucketID: "000000000000000a")
+	|> range(start: -24h)
+	|> filter(fn: (r) => r._measurement == "statistics")
+	|> drop(columns: ["_start", "_stop"])
+	|> v1.fieldsAsCols()
+	|> filter(fn: (r) => r.runID == %q)
+	|> yield(name: "statistics")
+
 from(bucketID: "000000000000000a")
   |> range(start: -24h)
 	|> filter(fn: (r) => r._measurement == "records")
@@ -169,7 +185,7 @@ from(bucketID: "000000000000000a")
 	|> filter(fn: (r) => r.runID == %q)
 	|> pivot(rowKey:["runID", "scheduledFor"], columnKey: ["status"], valueColumn: "_time")
 	|> yield(name: "result")
-  `, runID.String(), runID.String())
+  `, runID.String(), runID.String(), runID.String())
 
 	auth, err := pctx.GetAuthorizer(ctx)
 	if err != nil {
@@ -218,18 +234,42 @@ func queryIttrToRuns(results flux.ResultIterator) ([]*platform.Run, error) {
 
 // runExtractor is used to decode query results to runs.
 type runExtractor struct {
-	runs map[platform.ID]platform.Run
+	runs  map[platform.ID]platform.Run
+	logs  map[platform.ID][]timedLog
+	stats map[platform.ID]platform.RunStatistics
+}
+
+// timedLog is a log entry with its parsed time, to order the entries of a run.
+type timedLog struct {
+	when time.Time
+	log  platform.Log
 }
 
 func newRunExtractor() *runExtractor {
-	return &runExtractor{runs: make(map[platform.ID]platform.Run)}
+	return &runExtractor{
+		runs:  make(map[platform.ID]platform.Run),
+		logs:  make(map[platform.ID][]timedLog),
+		stats: make(map[platform.ID]platform.RunStatistics),
+	}
 }
 
-// Runs returns the runExtractor's stored runs as a slice.
+// Runs returns the runExtractor's stored runs as a slice, with their logs in order of time and their statistics.
+// Statistics without a stored run are ignored.
 func (re *runExtractor) Runs() []*platform.Run {
 	runs := make([]*platform.Run, 0, len(re.runs))
-	for _, r := range re.runs {
+	for id, r := range re.runs {
 		r := r
+
+		logs := re.logs[id]
+		sort.SliceStable(logs, func(i, j int) bool { return logs[i].when.Before(logs[j].when) })
+		for _, l := range logs {
+			r.Log = append(r.Log, l.log)
+		}
+
+		if stats, ok := re.stats[id]; ok {
+			r.Statistics = &stats
+		}
+
 		runs = append(runs, &r)
 	}
 
@@ -253,6 +293,8 @@ func (re *runExtractor) Extract(tbl flux.Table) error {
 		return tbl.Do(re.extractRecord)
 	case "logs":
 		return tbl.Do(re.extractLog)
+	case "statistics":
+		return tbl.Do(re.extractStatistics)
 	default:
 		return fmt.Errorf("unknown measurement: %q", mv.Str())
 	}
@@ -267,6 +309,8 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 				r.RequestedAt = cr.Strings(j).ValueString(i)
 			case scheduledForField:
 				r.ScheduledFor = cr.Strings(j).ValueString(i)
+			case revisionField:
+				r.Revision = cr.Ints(j).Value(i)
 			case "runID":
 				id, err := platform.IDFromString(cr.Strings(j).ValueString(i))
 				if err != nil {
@@ -297,10 +341,6 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 			return errors.New("extractRecord: did not find valid run ID in table")
 		}
 
-		if ex, ok := re.runs[r.ID]; ok {
-			r.Log = ex.Log
-		}
-
 		re.runs[r.ID] = r
 	}
 
@@ -308,22 +348,33 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 }
 
 func (re *runExtractor) extractLog(cr flux.ColReader) error {
-	entries := make(map[platform.ID][]string)
 	for i := 0; i < cr.Len(); i++ {
 		var runID platform.ID
-		var when, line string
+		var when time.Time
+		log := platform.Log{Level: platform.LogLevelInfo}
 		for j, col := range cr.Cols() {
 			switch col.Label {
-			case "runID":
+			case runIDField:
 				id, err := platform.IDFromString(cr.Strings(j).ValueString(i))
 				if err != nil {
 					return err
 				}
 				runID = *id
 			case "_time":
-				when = values.Time(cr.Times(j).Value(i)).Time().Format(time.RFC3339Nano)
-			case "line":
-				line = cr.Strings(j).ValueString(i)
+				when = values.Time(cr.Times(j).Value(i)).Time()
+			case lineField:
+				log.Message = cr.Strings(j).ValueString(i)
+			case levelField:
+				// Entries written before levels were introduced have no level.
+				if l := cr.Strings(j).ValueString(i); l != "" {
+					log.Level = platform.LogLevel(l)
+				}
+			case logFieldsField:
+				if f := cr.Strings(j).ValueString(i); f != "" {
+					if err := json.Unmarshal([]byte(f), &log.Fields); err != nil {
+						return err
+					}
+				}
 			}
 		}
 
@@ -331,13 +382,41 @@ func (re *runExtractor) extractLog(cr flux.ColReader) error {
 			return errors.New("extractLog: did not find valid run ID in table")
 		}
 
-		entries[runID] = append(entries[runID], when+": "+line)
+		log.RunID = runID
+		log.Time = when.Format(time.RFC3339Nano)
+		re.logs[runID] = append(re.logs[runID], timedLog{when: when, log: log})
+		if _, ok := re.runs[runID]; !ok {
+			re.runs[runID] = platform.Run{ID: runID}
+		}
 	}
 
-	for id, lines := range entries {
-		run := re.runs[id]
-		run.Log = platform.Log(strings.Join(lines, "\n"))
-		re.runs[id] = run
+	return nil
+}
+
+func (re *runExtractor) extractStatistics(cr flux.ColReader) error {
+	for i := 0; i < cr.Len(); i++ {
+		var runID platform.ID
+		var stats platform.RunStatistics
+		for j, col := range cr.Cols() {
+			switch col.Label {
+			case runIDField:
+				id, err := platform.IDFromString(cr.Strings(j).ValueString(i))
+				if err != nil {
+					return err
+				}
+				runID = *id
+			default:
+				if col.Type == flux.TInt {
+					setStatisticsField(&stats, col.Label, cr.Ints(j).Value(i))
+				}
+			}
+		}
+
+		if !runID.Valid() {
+			return errors.New("extractStatistics: did not find valid run ID in table")
+		}
+
+		re.stats[runID] = stats
 	}
 
 	return nil
@@ -2,7 +2,6 @@ package backend
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/influxdata/flux"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/options"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -41,6 +41,18 @@ type DesiredState interface {
 	// FinishRun indicates that the given run is no longer intended to be executed.
 	// This may be called after a successful or failed execution, or upon cancellation.
 	FinishRun(ctx context.Context, taskID, runID platform.ID) error
+
+	// RetryRun replaces the given run, whose execution failed, with a new run for the same now value,
+	// delegating to (*StoreTaskMeta).RetryRun. The new run is returned with its Try set.
+	RetryRun(ctx context.Context, taskID, runID platform.ID) (QueuedRun, error)
+
+	// QueueDependentRuns requests a run for now of each task which depends on the given task,
+	// after a run of the given task succeeded for now. The IDs of the tasks for which a run was requested are returned.
+	QueueDependentRuns(ctx context.Context, taskID platform.ID, now int64) ([]platform.ID, error)
+
+	// QueueTriggeredRun requests a run for now of the given task, after points were written to the bucket of its trigger.
+	// start and stop are the Unix timestamps in nanoseconds of the earliest and latest written points.
+	QueueTriggeredRun(ctx context.Context, taskID platform.ID, now, start, stop int64) error
 }
 
 // Executor handles execution of a run.
@@ -68,6 +80,39 @@ type QueuedRun struct {
 	// The Unix timestamp (seconds since January 1, 1970 UTC) that will be set
 	// as the "now" option when executing the task.
 	Now int64
+
+	// The attempt of the run, starting at 1. A run which failed is retried as a new run,
+	// with the same Now and a higher Try. Zero is the same as 1.
+	Try uint32
+
+	// The revision of the task's script which the run executes. Zero if the task predates revisions.
+	Revision int64
+
+	// Whether the run was triggered by writes to the bucket of the task's trigger,
+	// and the Unix timestamps in nanoseconds of the earliest and latest points written by those writes.
+	Triggered                 bool
+	TriggerStart, TriggerStop int64
+}
+
+// RunNotifier delivers notifications about finished runs, according to the notification rules of their task.
+type RunNotifier interface {
+	// Notify delivers a notification about the finished run n, to the endpoint of rule.
+	Notify(ctx context.Context, n RunNotification, rule *StoreTaskNotificationRule) error
+}
+
+// RunNotification describes a finished run, whose outcome triggered a notification rule of its task.
+type RunNotification struct {
+	RunLogBase
+
+	// Status is RunSuccess or RunFail.
+	Status RunStatus
+
+	// Err is the error of a failed run.
+	Err error
+
+	// ConsecutiveFailures is the number of failed runs of the task in a row,
+	// ending with this run if it failed, or just before this run if it succeeded.
+	ConsecutiveFailures int
 }
 
 // RunPromise represents an in-progress run whose result is not yet known.
@@ -94,10 +139,52 @@ type RunResult interface {
 	// IsRetryable returns true if the error was non-terminal and the run is eligible for retry.
 	IsRetryable() bool
 
-	// TODO(mr): add more detail here like number of points written, execution time, etc.
+	// Statistics returns the statistics of the query executed by the run.
+	// The number of rows written is in the metadata under RowsWrittenMetadataKey.
 	Statistics() flux.Statistics
 }
 
+const (
+	// RowsWrittenMetadataKey is the key of the flux.Statistics metadata of a run which counts the rows in its results.
+	RowsWrittenMetadataKey = "task/rows-written"
+
+	// Keys of the flux.Statistics metadata which count the values and bytes read from storage.
+	scannedValuesMetadataKey = "influxdb/scanned-values"
+	scannedBytesMetadataKey  = "influxdb/scanned-bytes"
+)
+
+// NewRunStatistics returns the statistics of a run from the given query statistics.
+func NewRunStatistics(s flux.Statistics) platform.RunStatistics {
+	return platform.RunStatistics{
+		TotalDuration:   s.TotalDuration,
+		CompileDuration: s.CompileDuration,
+		QueueDuration:   s.QueueDuration,
+		PlanDuration:    s.PlanDuration,
+		RequeueDuration: s.RequeueDuration,
+		ExecuteDuration: s.ExecuteDuration,
+		Concurrency:     s.Concurrency,
+		MaxAllocated:    s.MaxAllocated,
+		ScannedValues:   sumMetadata(s.Metadata, scannedValuesMetadataKey),
+		ScannedBytes:    sumMetadata(s.Metadata, scannedBytesMetadataKey),
+		RowsWritten:     sumMetadata(s.Metadata, RowsWrittenMetadataKey),
+	}
+}
+
+// sumMetadata returns the sum of the integer values of the given key in md.
+// Each source of a query adds its own value for the key.
+func sumMetadata(md flux.Metadata, key string) int64 {
+	var sum int64
+	for _, v := range md[key] {
+		switch n := v.(type) {
+		case int64:
+			sum += n
+		case int:
+			sum += int64(n)
+		}
+	}
+	return sum
+}
+
 // Scheduler accepts tasks and handles their scheduling.
 //
 // TODO(mr): right now the methods on Scheduler are synchronous.
@@ -117,6 +204,10 @@ type Scheduler interface {
 	// UpdateTask will update the concurrency and the runners for a task
 	UpdateTask(task *StoreTask, meta *StoreTaskMeta) error
 
+	// UpdateQueue informs the scheduler that the manual runs of a claimed task have changed,
+	// so that runs are created from the queue without waiting for the next scheduled run of the task.
+	UpdateQueue(taskID platform.ID, meta *StoreTaskMeta) error
+
 	// ReleaseTask immediately cancels any in-progress runs for the given task ID,
 	// and releases any resources related to management of that task.
 	ReleaseTask(taskID platform.ID) error
@@ -163,17 +254,70 @@ func WithLogger(logger *zap.Logger) TickSchedulerOption {
 	}
 }
 
+// WithRetryBackoff sets the delay before the first retry of a failed run, and the maximum delay before a retry.
+// The delay doubles with each retry of a run.
+// If not set, the scheduler will use DefaultRetryBackoff and DefaultMaxRetryBackoff.
+func WithRetryBackoff(backoff, max time.Duration) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.retryBackoff = backoff
+		s.maxRetryBackoff = max
+	}
+}
+
+// WithRunNotifier sets the RunNotifier which delivers the notifications triggered by finished runs.
+// If not set, the scheduler does not send notifications.
+func WithRunNotifier(n RunNotifier) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.notifier = n
+	}
+}
+
+// WithOrgConcurrencyLimit sets the maximum number of concurrent runs of the tasks of each organization.
+// Due runs beyond the limit wait for a run slot of their organization, which the organization's tasks take in turn.
+// If not set, or set to 0, the number of concurrent runs of an organization is not limited.
+func WithOrgConcurrencyLimit(limit int) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.orgs.defaultLimit = limit
+	}
+}
+
+// WithOrgConcurrencyLimits overrides the limit set by WithOrgConcurrencyLimit for the given organizations.
+func WithOrgConcurrencyLimits(limits map[platform.ID]int) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		for org, limit := range limits {
+			s.orgs.limits[org] = limit
+		}
+	}
+}
+
+const (
+	// DefaultRetryBackoff is the default delay before the first retry of a failed run.
+	DefaultRetryBackoff = time.Second
+
+	// DefaultMaxRetryBackoff is the default maximum delay before a retry of a failed run.
+	DefaultMaxRetryBackoff = time.Minute
+
+	// notificationTimeout limits the time to deliver a single notification about a run.
+	notificationTimeout = 10 * time.Second
+)
+
 // NewScheduler returns a new scheduler with the given desired state and the given now UTC timestamp.
 func NewScheduler(desiredState DesiredState, executor Executor, lw LogWriter, now int64, opts ...TickSchedulerOption) *TickScheduler {
+	metrics := newSchedulerMetrics()
 	o := &TickScheduler{
 		desiredState:   desiredState,
 		executor:       executor,
 		logWriter:      lw,
 		now:            now,
 		taskSchedulers: make(map[platform.ID]*taskScheduler),
+		triggered:      make(map[platform.ID][]*taskScheduler),
 		logger:         zap.NewNop(),
 		wg:             &sync.WaitGroup{},
-		metrics:        newSchedulerMetrics(),
+		metrics:        metrics,
+		orgs:           newOrgSlots(metrics),
+
+		retryBackoff:    DefaultRetryBackoff,
+		maxRetryBackoff: DefaultMaxRetryBackoff,
 	}
 
 	for _, opt := range opts {
@@ -193,12 +337,24 @@ type TickScheduler struct {
 
 	metrics *schedulerMetrics
 
+	// Delay before the first retry of a failed run, and maximum delay before a retry.
+	retryBackoff, maxRetryBackoff time.Duration
+
+	notifier RunNotifier
+
+	// Run slots of each organization.
+	orgs *orgSlots
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     *sync.WaitGroup
 
 	schedulerMu    sync.Mutex                     // Protects access and modification of taskSchedulers map.
 	taskSchedulers map[platform.ID]*taskScheduler // task ID -> task scheduler.
+
+	// Separate from schedulerMu, so that writes aren't blocked while the scheduler ticks.
+	triggeredMu sync.RWMutex                     // Protects access and modification of triggered map.
+	triggered   map[platform.ID][]*taskScheduler // bucket ID -> schedulers of tasks triggered by writes to the bucket.
 }
 
 // CancelRun cancels a run, it has the unused Context argument so that it can implement a task.RunController
@@ -225,16 +381,18 @@ func (s *TickScheduler) CancelRun(_ context.Context, taskID, runID platform.ID)
 // Tick updates the time of the scheduler.
 // Any owned tasks who are due to execute and who have a free concurrency slot,
 // will begin a new execution.
+// The new executions are started in turn across organizations, one run at a time.
 func (s *TickScheduler) Tick(now int64) {
 	s.schedulerMu.Lock()
-	defer s.schedulerMu.Unlock()
+	ctx := s.ctx
+	s.schedulerMu.Unlock()
 
-	if s.ctx == nil {
+	if ctx == nil {
 		return
 	}
 
 	select {
-	case <-s.ctx.Done():
+	case <-ctx.Done():
 		return
 	default:
 		// do nothing and allow ticks
@@ -242,13 +400,23 @@ func (s *TickScheduler) Tick(now int64) {
 
 	atomic.StoreInt64(&s.now, now)
 
+	// Queueing a triggered run writes to the store, so it is done without holding s.schedulerMu.
+	for _, ts := range s.triggeredSchedulers() {
+		ts.queueTriggeredRun(ctx, now)
+	}
+
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
 	affected := 0
+	q := newFairQueue(s.metrics)
 	for _, ts := range s.taskSchedulers {
 		if nextDue, hasQueue := ts.NextDue(); now >= nextDue || hasQueue {
-			ts.Work()
+			q.Push(ts)
 			affected++
 		}
 	}
+	q.Work()
 	// TODO(mr): find a way to emit a more useful / less annoying tick message, maybe aggregated over the past 10s or 30s?
 	s.logger.Debug("Ticked", zap.Int64("now", now), zap.Int("tasks_affected", affected))
 }
@@ -273,7 +441,9 @@ func (s *TickScheduler) Stop() {
 	s.cancel()
 
 	// release tasks
-	for id := range s.taskSchedulers {
+	for id, ts := range s.taskSchedulers {
+		s.orgs.withdraw(ts)
+		s.untrack(ts)
 		delete(s.taskSchedulers, id)
 		s.metrics.ReleaseTask(id.String())
 	}
@@ -312,6 +482,7 @@ func (s *TickScheduler) ClaimTask(task *StoreTask, meta *StoreTaskMeta) (err err
 	}
 
 	s.taskSchedulers[task.ID] = ts
+	s.track(ts)
 
 	if len(meta.CurrentlyRunning) > 0 {
 		if err := ts.WorkCurrentlyRunning(meta); err != nil {
@@ -335,13 +506,18 @@ func (s *TickScheduler) UpdateTask(task *StoreTask, meta *StoreTaskMeta) error {
 		return ErrTaskNotClaimed
 	}
 	ts.Cancel()
+	s.wake(s.orgs.withdraw(ts))
 
 	nts, err := newTaskScheduler(s.ctx, s.wg, s, task, meta, s.metrics)
 	if err != nil {
 		return err
 	}
+	nts.consecutiveFailures = atomic.LoadInt64(&ts.consecutiveFailures)
+	nts.takeTrigger(ts)
 
+	s.untrack(ts)
 	s.taskSchedulers[task.ID] = nts
+	s.track(nts)
 
 	next, hasQueue := ts.NextDue()
 	if now := atomic.LoadInt64(&s.now); now >= next || hasQueue {
@@ -351,6 +527,40 @@ func (s *TickScheduler) UpdateTask(task *StoreTask, meta *StoreTaskMeta) error {
 	return nil
 }
 
+func (s *TickScheduler) UpdateQueue(taskID platform.ID, meta *StoreTaskMeta) error {
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
+	ts, ok := s.taskSchedulers[taskID]
+	if !ok {
+		return ErrTaskNotClaimed
+	}
+
+	hasQueue := meta.HasQueue()
+	ts.SetHasQueue(hasQueue)
+	if hasQueue {
+		ts.Work()
+	}
+
+	return nil
+}
+
+// notifyQueued informs the schedulers of the given tasks, if they are claimed, that runs were queued for them.
+func (s *TickScheduler) notifyQueued(taskIDs []platform.ID) {
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
+	for _, id := range taskIDs {
+		ts, ok := s.taskSchedulers[id]
+		if !ok {
+			// The task will pick up its queue once it is claimed.
+			continue
+		}
+		ts.SetHasQueue(true)
+		ts.Work()
+	}
+}
+
 func (s *TickScheduler) ReleaseTask(taskID platform.ID) error {
 	s.schedulerMu.Lock()
 	defer s.schedulerMu.Unlock()
@@ -361,6 +571,8 @@ func (s *TickScheduler) ReleaseTask(taskID platform.ID) error {
 	}
 
 	t.Cancel()
+	s.wake(s.orgs.withdraw(t))
+	s.untrack(t)
 	delete(s.taskSchedulers, taskID)
 
 	s.metrics.ReleaseTask(taskID.String())
@@ -368,6 +580,79 @@ func (s *TickScheduler) ReleaseTask(taskID platform.ID) error {
 	return nil
 }
 
+// PointsWritten records the points written to buckets for the claimed tasks triggered by the writes,
+// whose runs are queued on the next tick, once the minimum interval of their trigger passed.
+func (s *TickScheduler) PointsWritten(_ context.Context, ws []WrittenPoints) {
+	s.triggeredMu.RLock()
+	defer s.triggeredMu.RUnlock()
+
+	for _, w := range ws {
+		for _, ts := range s.triggered[w.BucketID] {
+			if ts.task.Org == w.OrgID && ts.triggeredBy(w.Measurement) {
+				ts.addTrigger(w.Start, w.Stop)
+			}
+		}
+	}
+}
+
+// triggeredSchedulers returns the schedulers of the tasks triggered by writes.
+func (s *TickScheduler) triggeredSchedulers() []*taskScheduler {
+	s.triggeredMu.RLock()
+	defer s.triggeredMu.RUnlock()
+
+	var tss []*taskScheduler
+	for _, q := range s.triggered {
+		tss = append(tss, q...)
+	}
+	return tss
+}
+
+// track adds ts to the schedulers of tasks triggered by writes, if its task has a trigger.
+// s.schedulerMu must be held.
+func (s *TickScheduler) track(ts *taskScheduler) {
+	if ts.triggerBucketID == 0 {
+		return
+	}
+
+	s.triggeredMu.Lock()
+	defer s.triggeredMu.Unlock()
+	s.triggered[ts.triggerBucketID] = append(s.triggered[ts.triggerBucketID], ts)
+}
+
+// untrack removes ts from the schedulers of tasks triggered by writes.
+// s.schedulerMu must be held.
+func (s *TickScheduler) untrack(ts *taskScheduler) {
+	if ts.triggerBucketID == 0 {
+		return
+	}
+
+	s.triggeredMu.Lock()
+	defer s.triggeredMu.Unlock()
+	q := s.triggered[ts.triggerBucketID]
+	i := indexOfTaskScheduler(q, ts)
+	if i < 0 {
+		return
+	}
+	if len(q) == 1 {
+		delete(s.triggered, ts.triggerBucketID)
+		return
+	}
+	nq := make([]*taskScheduler, 0, len(q)-1)
+	nq = append(nq, q[:i]...)
+	nq = append(nq, q[i+1:]...)
+	s.triggered[ts.triggerBucketID] = nq
+}
+
+// wake lets ts start the due run for which a slot of its organization is free, if ts is not nil.
+func (s *TickScheduler) wake(ts *taskScheduler) {
+	if ts == nil {
+		return
+	}
+
+	// Don't block the caller, which may be holding s.schedulerMu or be a runner the scheduler waits for.
+	go ts.Work()
+}
+
 func (s *TickScheduler) PrometheusCollectors() []prometheus.Collector {
 	return s.metrics.PrometheusCollectors()
 }
@@ -382,6 +667,9 @@ type taskScheduler struct {
 	// Reference to outerScheduler.now. Must be accessed atomically.
 	now *int64
 
+	// The scheduler which owns this taskScheduler.
+	scheduler *TickScheduler
+
 	// Task we are scheduling for.
 	task *StoreTask
 
@@ -398,6 +686,29 @@ type taskScheduler struct {
 
 	metrics *schedulerMetrics
 
+	// Maximum number of attempts of a run, from the task's retry option.
+	maxAttempts uint32
+
+	// Delay before the first retry of a failed run, and maximum delay before a retry.
+	retryBackoff, maxRetryBackoff time.Duration
+
+	// Rules for notifications about finished runs, and the number of failed runs in a row,
+	// which is only tracked while the task is claimed by this scheduler. Must be accessed atomically.
+	notificationRules   []*StoreTaskNotificationRule
+	consecutiveFailures int64
+
+	// Trigger of the task, if it is run when points are written rather than on a schedule.
+	// The bucket ID is 0 if the task has no trigger.
+	triggerBucketID    platform.ID
+	triggerMeasurement string
+	triggerMinInterval int64 // Seconds.
+
+	triggerMu      sync.Mutex // Protects following fields.
+	triggerPending bool       // Whether points were written since the last triggered run was queued.
+	triggerStart   int64      // Unix timestamp in nanoseconds of the earliest point written since then.
+	triggerStop    int64      // Unix timestamp in nanoseconds of the latest point written since then.
+	lastTriggered  int64      // Unix timestamp of when the last triggered run was queued.
+
 	nextDueMu     sync.RWMutex // Protects following fields.
 	nextDue       int64        // Unix timestamp of next due.
 	nextDueSource int64        // Run time that produced nextDue.
@@ -417,9 +728,16 @@ func newTaskScheduler(
 		return nil, err
 	}
 
+	// Failed runs are retried up to the task's retry option, if the script's options can be parsed.
+	maxAttempts := uint32(1)
+	if o, err := options.FromScript(task.Script); err == nil && o.Retry > 1 {
+		maxAttempts = uint32(o.Retry)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	ts := &taskScheduler{
 		now:           &s.now,
+		scheduler:     s,
 		task:          task,
 		cancel:        cancel,
 		wg:            wg,
@@ -429,7 +747,17 @@ func newTaskScheduler(
 		metrics:       s.metrics,
 		nextDue:       firstDue,
 		nextDueSource: math.MinInt64,
-		hasQueue:      len(meta.ManualRuns) > 0,
+		hasQueue:      meta.HasQueue(),
+
+		maxAttempts:     maxAttempts,
+		retryBackoff:    s.retryBackoff,
+		maxRetryBackoff: s.maxRetryBackoff,
+
+		notificationRules: meta.NotificationRules,
+
+		triggerBucketID:    platform.ID(meta.TriggerBucketID),
+		triggerMeasurement: meta.TriggerMeasurement,
+		triggerMinInterval: int64(meta.TriggerMinInterval),
 	}
 
 	for i := range ts.runners {
@@ -452,11 +780,32 @@ func (ts *taskScheduler) Work() {
 	}
 }
 
+// startOne starts a single run on an idle runner, and reports whether a run started.
+func (ts *taskScheduler) startOne() bool {
+	for _, r := range ts.runners {
+		if !r.IsIdle() {
+			continue
+		}
+		r.Start()
+		return !r.IsIdle()
+	}
+	return false
+}
+
 func (ts *taskScheduler) WorkCurrentlyRunning(meta *StoreTaskMeta) error {
 	for _, cr := range meta.CurrentlyRunning {
 		foundWorker := false
 		for _, r := range ts.runners {
-			qr := QueuedRun{TaskID: ts.task.ID, RunID: platform.ID(cr.RunID), Now: cr.Now}
+			qr := QueuedRun{
+				TaskID:       ts.task.ID,
+				RunID:        platform.ID(cr.RunID),
+				Now:          cr.Now,
+				Try:          cr.Try,
+				Revision:     cr.Revision,
+				Triggered:    cr.Triggered,
+				TriggerStart: cr.TriggerStart,
+				TriggerStop:  cr.TriggerStop,
+			}
 			if r.RestartRun(qr) {
 				foundWorker = true
 				break
@@ -471,6 +820,61 @@ func (ts *taskScheduler) WorkCurrentlyRunning(meta *StoreTaskMeta) error {
 	return nil
 }
 
+// triggeredBy returns true if writes of points of the given measurement to the bucket of ts's trigger trigger runs.
+func (ts *taskScheduler) triggeredBy(measurement string) bool {
+	return ts.triggerMeasurement == "" || ts.triggerMeasurement == measurement
+}
+
+// addTrigger records that points whose times range from start to stop, as Unix timestamps in nanoseconds,
+// were written to the bucket of ts's trigger.
+func (ts *taskScheduler) addTrigger(start, stop int64) {
+	ts.triggerMu.Lock()
+	defer ts.triggerMu.Unlock()
+
+	if !ts.triggerPending {
+		ts.triggerPending = true
+		ts.triggerStart, ts.triggerStop = start, stop
+		return
+	}
+	if start < ts.triggerStart {
+		ts.triggerStart = start
+	}
+	if stop > ts.triggerStop {
+		ts.triggerStop = stop
+	}
+}
+
+// takeTrigger takes over the points written for the task of old, which ts replaces, and not yet covered by a run.
+func (ts *taskScheduler) takeTrigger(old *taskScheduler) {
+	old.triggerMu.Lock()
+	defer old.triggerMu.Unlock()
+
+	ts.triggerPending = old.triggerPending
+	ts.triggerStart, ts.triggerStop = old.triggerStart, old.triggerStop
+	ts.lastTriggered = old.lastTriggered
+}
+
+// queueTriggeredRun requests a run for now, which covers the points written since the last triggered run was queued,
+// if any points were written and the minimum interval of the trigger has passed since then.
+// If requesting the run fails, it is requested again on the next call.
+func (ts *taskScheduler) queueTriggeredRun(ctx context.Context, now int64) {
+	ts.triggerMu.Lock()
+	defer ts.triggerMu.Unlock()
+
+	if !ts.triggerPending || now < ts.lastTriggered+ts.triggerMinInterval {
+		return
+	}
+
+	if err := ts.scheduler.desiredState.QueueTriggeredRun(ctx, ts.task.ID, now, ts.triggerStart, ts.triggerStop); err != nil {
+		ts.logger.Info("Failed to queue triggered run", zap.Error(err))
+		return
+	}
+	ts.triggerPending = false
+	ts.lastTriggered = now
+	ts.SetHasQueue(true)
+	ts.metrics.TriggerRun(ts.task.ID.String())
+}
+
 // Cancel interrupts this taskScheduler and its runners.
 func (ts *taskScheduler) Cancel() {
 	ts.cancel()
@@ -494,6 +898,26 @@ func (ts *taskScheduler) SetNextDue(nextDue int64, hasQueue bool, source int64)
 	ts.hasQueue = hasQueue
 }
 
+// SetHasQueue sets whether the task has a queue, without changing the next due timestamp.
+func (ts *taskScheduler) SetHasQueue(hasQueue bool) {
+	ts.nextDueMu.Lock()
+	defer ts.nextDueMu.Unlock()
+	ts.hasQueue = hasQueue
+}
+
+// RetryBackoff returns the delay before executing the given try of a run.
+// The delay of the first retry is ts.retryBackoff, and doubles with each further retry up to ts.maxRetryBackoff.
+func (ts *taskScheduler) RetryBackoff(try uint32) time.Duration {
+	d := ts.retryBackoff
+	for i := uint32(2); i < try && d < ts.maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > ts.maxRetryBackoff {
+		d = ts.maxRetryBackoff
+	}
+	return d
+}
+
 // A runner is one eligible "concurrency slot" for a given task.
 type runner struct {
 	state *uint32
@@ -572,6 +996,9 @@ func (r *runner) RestartRun(qr QueuedRun) bool {
 		// already working
 		return false
 	}
+	// The run was already in progress, so it holds a slot of the organization even beyond the limit.
+	r.ts.scheduler.orgs.take(r.ts)
+
 	// create a QueuedRun because we cant stm.CreateNextRun
 	runLogger := r.logger.With(zap.String("run_id", qr.RunID.String()), zap.Int64("now", qr.Now))
 	r.wg.Add(1)
@@ -593,7 +1020,14 @@ func (r *runner) RestartRun(qr QueuedRun) bool {
 // r.state must be runnerWorking when this is called.
 func (r *runner) startFromWorking(now int64) {
 	if nextDue, hasQueue := r.ts.NextDue(); now < nextDue && !hasQueue {
-		// Not ready for a new run. Go idle again.
+		// Not ready for a new run. Go idle again, and stop waiting for a slot of the organization.
+		atomic.StoreUint32(r.state, runnerIdle)
+		r.ts.scheduler.wake(r.ts.scheduler.orgs.withdraw(r.ts))
+		return
+	}
+	if !r.ts.scheduler.orgs.acquire(r.ctx, r.ts) {
+		// The task was canceled, or its organization is at its concurrency limit.
+		// Go idle until the task is woken up for a free slot.
 		atomic.StoreUint32(r.state, runnerIdle)
 		return
 	}
@@ -602,6 +1036,7 @@ func (r *runner) startFromWorking(now int64) {
 	if err != nil {
 		r.logger.Info("Failed to create run", zap.Error(err))
 		atomic.StoreUint32(r.state, runnerIdle)
+		r.releaseSlot()
 		cancel() // cancel to prevent context leak
 		return
 	}
@@ -623,6 +1058,12 @@ func (r *runner) startFromWorking(now int64) {
 	r.updateRunState(qr, RunStarted, runLogger)
 }
 
+// releaseSlot frees the slot of the organization held by the finished run of r,
+// and wakes up the task which is next in line for a slot of the organization.
+func (r *runner) releaseSlot() {
+	r.ts.scheduler.wake(r.ts.scheduler.orgs.release(r.task.Org))
+}
+
 func (r *runner) clearRunning(id platform.ID) {
 	r.ts.runningMu.Lock()
 	r.ts.running[id].CancelFunc() // cleanup
@@ -644,9 +1085,10 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 			runLogger.Error("Beginning run execution failed, and desired state update failed", zap.Error(err))
 		}
 
-		// TODO(mr): retry?
 		atomic.StoreUint32(r.state, runnerIdle)
+		r.releaseSlot()
 		r.updateRunState(qr, RunFail, runLogger)
+		r.notify(qr, RunFail, err)
 		return
 	}
 
@@ -667,13 +1109,13 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 		}
 	}()
 
-	// TODO(mr): handle rr.IsRetryable().
 	rr, err := rp.Wait()
 	close(ready)
 	if err != nil {
 		if err == ErrRunCanceled {
 			_ = r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID)
 			r.updateRunState(qr, RunCanceled, runLogger)
+			r.releaseSlot()
 
 			// Move on to the next execution, for a canceled run.
 			r.startFromWorking(atomic.LoadInt64(r.ts.now))
@@ -686,71 +1128,224 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 			runLogger.Error("Waiting for execution result failed, and desired state update failed", zap.Error(err))
 		}
 
-		// TODO(mr): retry?
 		r.updateRunState(qr, RunFail, runLogger)
+		r.notify(qr, RunFail, err)
 		atomic.StoreUint32(r.state, runnerIdle)
+		r.releaseSlot()
 		return
 	}
-	if err := rr.Err(); err != nil {
-		runLogger.Info("Run failed to execute", zap.Error(err))
+	if runErr := rr.Err(); runErr != nil {
+		runLogger.Info("Run failed to execute", zap.Error(runErr))
+		r.addStatistics(qr, rr, runLogger)
+		if rr.IsRetryable() && r.retry(qr, runErr, runLogger) {
+			return
+		}
 		if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
 			// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
 			runLogger.Error("Run failed to execute, and desired state update failed", zap.Error(err))
 		}
-		// TODO(mr): retry?
 		r.updateRunState(qr, RunFail, runLogger)
+		r.notify(qr, RunFail, runErr)
 		atomic.StoreUint32(r.state, runnerIdle)
+		r.releaseSlot()
 		return
 	}
 
 	if err := r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
 		runLogger.Info("Failed to finish run", zap.Error(err))
-		// TODO(mr): retry?
 		// Need to think about what it means if there was an error finishing a run.
 		atomic.StoreUint32(r.state, runnerIdle)
+		r.releaseSlot()
 		r.updateRunState(qr, RunFail, runLogger)
+		r.notify(qr, RunFail, err)
 		return
 	}
-	rlb := RunLogBase{
-		Task:            r.task,
-		RunID:           qr.RunID,
-		RunScheduledFor: qr.Now,
-		RequestedAt:     qr.RequestedAt,
-	}
-	stats := rr.Statistics()
-
-	b, err := json.Marshal(stats)
-	if err == nil {
-		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), string(b))
-	}
+	r.addStatistics(qr, rr, runLogger)
 	r.updateRunState(qr, RunSuccess, runLogger)
+	r.notify(qr, RunSuccess, nil)
 	runLogger.Info("Execution succeeded")
 
+	r.queueDependentRuns(qr, runLogger)
+	r.releaseSlot()
+
 	// Check again if there is a new run available, without returning to idle state.
 	r.startFromWorking(atomic.LoadInt64(r.ts.now))
 }
 
-func (r *runner) updateRunState(qr QueuedRun, s RunStatus, runLogger *zap.Logger) {
-	rlb := RunLogBase{
+// queueDependentRuns requests runs for the same time as the successful run qr, of the tasks which depend on r's task,
+// and notifies the schedulers of those tasks which are claimed by this scheduler.
+func (r *runner) queueDependentRuns(qr QueuedRun, runLogger *zap.Logger) {
+	ids, err := r.desiredState.QueueDependentRuns(r.ctx, qr.TaskID, qr.Now)
+	if err != nil {
+		runLogger.Info("Failed to request runs of dependent tasks", zap.Error(err))
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	// Don't block the runner on the scheduler, which waits for its runners while stopping.
+	go r.ts.scheduler.notifyQueued(ids)
+}
+
+// notify counts the failed runs in a row of r's task, after the run qr finished with status s,
+// and delivers the notifications triggered by the run in the background.
+// Each delivery attempt is recorded in the log of the run.
+func (r *runner) notify(qr QueuedRun, s RunStatus, runErr error) {
+	var failures int64
+	if s == RunFail {
+		failures = atomic.AddInt64(&r.ts.consecutiveFailures, 1)
+	} else {
+		failures = atomic.SwapInt64(&r.ts.consecutiveFailures, 0)
+	}
+
+	notifier := r.ts.scheduler.notifier
+	if notifier == nil {
+		return
+	}
+
+	var rules []*StoreTaskNotificationRule
+	for _, rule := range r.ts.notificationRules {
+		if rule.Triggered(s, int(failures)) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	n := RunNotification{
+		RunLogBase:          r.runLogBase(qr),
+		Status:              s,
+		Err:                 runErr,
+		ConsecutiveFailures: int(failures),
+	}
+	go func() {
+		for _, rule := range rules {
+			ctx, cancel := context.WithTimeout(r.ctx, notificationTimeout)
+			err := notifier.Notify(ctx, n, rule)
+			cancel()
+
+			entry := RunLogEntry{
+				Message: fmt.Sprintf("Sent %s notification to %s", rule.On, rule.URL),
+				Fields:  map[string]string{"url": rule.URL},
+			}
+			if err != nil {
+				r.logger.Info("Failed to send run notification", zap.String("run_id", qr.RunID.String()), zap.String("url", rule.URL), zap.Error(err))
+				entry.Level = platform.LogLevelWarn
+				entry.Message = fmt.Sprintf("Failed to send %s notification to %s", rule.On, rule.URL)
+				entry.Fields["error"] = err.Error()
+			}
+			r.logWriter.AddRunLog(r.ctx, n.RunLogBase, time.Now(), entry)
+		}
+	}()
+}
+
+// retry replaces the failed run qr with a new run for the same time, and executes the new run after a backoff,
+// if the task's retry option allows another attempt.
+// The runner stays busy until the new run finishes. retry returns false if the run is not retried.
+func (r *runner) retry(qr QueuedRun, runErr error, runLogger *zap.Logger) bool {
+	try := qr.Try
+	if try == 0 {
+		try = 1
+	}
+	if try >= r.ts.maxAttempts {
+		return false
+	}
+
+	nqr, err := r.desiredState.RetryRun(r.ctx, qr.TaskID, qr.RunID)
+	if err != nil {
+		runLogger.Info("Failed to create retry of run", zap.Error(err))
+		return false
+	}
+	backoff := r.ts.RetryBackoff(nqr.Try)
+
+	// The failed attempt and its retry are linked by their logs.
+	r.logWriter.AddRunLog(r.ctx, r.runLogBase(qr), time.Now(), RunLogEntry{
+		Level:   platform.LogLevelWarn,
+		Message: fmt.Sprintf("Retrying as run %s in %s (attempt %d of %d)", nqr.RunID, backoff, nqr.Try, r.ts.maxAttempts),
+		Fields:  map[string]string{"retryRunID": nqr.RunID.String(), "error": runErr.Error()},
+	})
+	r.updateRunState(qr, RunFail, runLogger)
+	r.ts.metrics.RetryRun(r.task.ID.String())
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.ts.runningMu.Lock()
+	r.ts.running[nqr.RunID] = runCtx{Context: ctx, CancelFunc: cancel}
+	r.ts.runningMu.Unlock()
+
+	retryLogger := r.logger.With(zap.String("run_id", nqr.RunID.String()), zap.Int64("now", nqr.Now), zap.Uint32("try", nqr.Try))
+	retryLogger.Info("Created retry of failed run", zap.String("retry_of", qr.RunID.String()), zap.Duration("backoff", backoff))
+
+	r.updateRunState(nqr, RunStarted, retryLogger)
+	r.logWriter.AddRunLog(r.ctx, r.runLogBase(nqr), time.Now(), RunLogEntry{
+		Message: fmt.Sprintf("Retry of run %s (attempt %d of %d)", qr.RunID, nqr.Try, r.ts.maxAttempts),
+		Fields:  map[string]string{"retryOfRunID": qr.RunID.String()},
+	})
+
+	r.wg.Add(1)
+	go r.executeAfter(ctx, nqr, backoff, retryLogger)
+	return true
+}
+
+// executeAfter waits for d before executing qr, unless ctx is canceled first.
+func (r *runner) executeAfter(ctx context.Context, qr QueuedRun, d time.Duration, runLogger *zap.Logger) {
+	t := time.NewTimer(d)
+	select {
+	case <-t.C:
+		r.executeAndWait(ctx, qr, runLogger)
+		return
+	case <-ctx.Done():
+		t.Stop()
+	}
+
+	defer r.wg.Done()
+	r.clearRunning(qr.RunID)
+	_ = r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID)
+	r.updateRunState(qr, RunCanceled, runLogger)
+
+	if r.ctx.Err() != nil {
+		atomic.StoreUint32(r.state, runnerIdle)
+		r.releaseSlot()
+		return
+	}
+	r.releaseSlot()
+	// Move on to the next execution, for a canceled run.
+	r.startFromWorking(atomic.LoadInt64(r.ts.now))
+}
+
+// addStatistics records the statistics of the query executed by the run qr, which finished with the result rr.
+func (r *runner) addStatistics(qr QueuedRun, rr RunResult, runLogger *zap.Logger) {
+	if err := r.logWriter.AddRunStatistics(r.ctx, r.runLogBase(qr), time.Now(), NewRunStatistics(rr.Statistics())); err != nil {
+		runLogger.Info("Error adding run statistics", zap.Error(err))
+	}
+}
+
+func (r *runner) runLogBase(qr QueuedRun) RunLogBase {
+	return RunLogBase{
 		Task:            r.task,
 		RunID:           qr.RunID,
 		RunScheduledFor: qr.Now,
 		RequestedAt:     qr.RequestedAt,
+		Revision:        qr.Revision,
 	}
+}
+
+func (r *runner) updateRunState(qr QueuedRun, s RunStatus, runLogger *zap.Logger) {
+	rlb := r.runLogBase(qr)
 
 	switch s {
 	case RunStarted:
 		r.ts.metrics.StartRun(r.task.ID.String())
-		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), fmt.Sprintf("Started task from script: %q", r.task.Script))
+		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), RunLogEntry{Message: fmt.Sprintf("Started task from script: %q", r.task.Script)})
 	case RunSuccess:
 		r.ts.metrics.FinishRun(r.task.ID.String(), true)
-		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), "Completed successfully")
+		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), RunLogEntry{Message: "Completed successfully"})
 	case RunFail:
 		r.ts.metrics.FinishRun(r.task.ID.String(), false)
-		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), "Failed")
+		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), RunLogEntry{Level: platform.LogLevelError, Message: "Failed"})
 	case RunCanceled:
 		r.ts.metrics.FinishRun(r.task.ID.String(), false)
-		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), "Canceled")
+		r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), RunLogEntry{Level: platform.LogLevelWarn, Message: "Canceled"})
 	default: // We are deliberately not handling RunQueued yet.
 		// There is not really a notion of being queued in this runner architecture.
 		runLogger.Warn("Unhandled run state", zap.Stringer("state", s))
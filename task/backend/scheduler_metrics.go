@@ -12,8 +12,18 @@ type schedulerMetrics struct {
 	runsComplete *prometheus.CounterVec
 	runsActive   *prometheus.GaugeVec
 
+	totalRunsRetried prometheus.Counter
+	runsRetried      *prometheus.CounterVec
+
+	totalRunsTriggered prometheus.Counter
+	runsTriggered      *prometheus.CounterVec
+
 	claimsComplete *prometheus.CounterVec
 	claimsActive   prometheus.Gauge
+
+	orgRunsActive   *prometheus.GaugeVec
+	orgRunsQueued   *prometheus.CounterVec
+	orgTasksBlocked *prometheus.GaugeVec
 }
 
 func newSchedulerMetrics() *schedulerMetrics {
@@ -47,6 +57,32 @@ func newSchedulerMetrics() *schedulerMetrics {
 			Help:      "Total number of runs that have started but not yet completed, split out by task ID.",
 		}, []string{"task_id"}),
 
+		totalRunsRetried: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "total_runs_retried",
+			Help:      "Total number of failed runs retried across all tasks.",
+		}),
+		runsRetried: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "runs_retried",
+			Help:      "Number of failed runs retried, split out by task ID.",
+		}, []string{"task_id"}),
+
+		totalRunsTriggered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "total_runs_triggered",
+			Help:      "Total number of runs queued across all tasks because points were written to the bucket of their trigger.",
+		}),
+		runsTriggered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "runs_triggered",
+			Help:      "Number of runs queued because points were written to the bucket of their task's trigger, split out by task ID.",
+		}, []string{"task_id"}),
+
 		claimsComplete: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
@@ -59,6 +95,25 @@ func newSchedulerMetrics() *schedulerMetrics {
 			Name:      "claims_active",
 			Help:      "Total number of claims currently held.",
 		}),
+
+		orgRunsActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_runs_active",
+			Help:      "Number of runs holding a run slot of their organization, split out by organization ID.",
+		}, []string{"org_id"}),
+		orgRunsQueued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_runs_queued",
+			Help:      "Total number of times a task with a due run was queued for its turn in the scheduler, split out by organization ID.",
+		}, []string{"org_id"}),
+		orgTasksBlocked: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "org_tasks_blocked",
+			Help:      "Number of tasks with a due run blocked by the concurrency limit of their organization, split out by organization ID.",
+		}, []string{"org_id"}),
 	}
 }
 
@@ -69,8 +124,15 @@ func (sm *schedulerMetrics) PrometheusCollectors() []prometheus.Collector {
 		sm.totalRunsActive,
 		sm.runsComplete,
 		sm.runsActive,
+		sm.totalRunsRetried,
+		sm.runsRetried,
+		sm.totalRunsTriggered,
+		sm.runsTriggered,
 		sm.claimsComplete,
 		sm.claimsActive,
+		sm.orgRunsActive,
+		sm.orgRunsQueued,
+		sm.orgTasksBlocked,
 	}
 }
 
@@ -91,6 +153,18 @@ func (sm *schedulerMetrics) FinishRun(tid string, succeeded bool) {
 	sm.runsComplete.WithLabelValues(tid, status).Inc()
 }
 
+// RetryRun adjusts the metrics to indicate a failed run is retried for the given task ID.
+func (sm *schedulerMetrics) RetryRun(tid string) {
+	sm.totalRunsRetried.Inc()
+	sm.runsRetried.WithLabelValues(tid).Inc()
+}
+
+// TriggerRun adjusts the metrics to indicate a run was queued for the given task ID, because points were written.
+func (sm *schedulerMetrics) TriggerRun(tid string) {
+	sm.totalRunsTriggered.Inc()
+	sm.runsTriggered.WithLabelValues(tid).Inc()
+}
+
 // ClaimTask adjusts the metrics to indicate the result of an attempted claim.
 func (sm *schedulerMetrics) ClaimTask(succeeded bool) {
 	status := statusString(succeeded)
@@ -108,6 +182,28 @@ func (sm *schedulerMetrics) ReleaseTask(tid string) {
 	sm.runsActive.DeleteLabelValues(tid)
 	sm.runsComplete.DeleteLabelValues(tid, statusString(true))
 	sm.runsComplete.DeleteLabelValues(tid, statusString(false))
+	sm.runsRetried.DeleteLabelValues(tid)
+	sm.runsTriggered.DeleteLabelValues(tid)
+}
+
+// StartOrgRun adjusts the metrics to indicate a run holds a run slot of the given organization ID.
+func (sm *schedulerMetrics) StartOrgRun(oid string) {
+	sm.orgRunsActive.WithLabelValues(oid).Inc()
+}
+
+// FinishOrgRun adjusts the metrics to indicate a run no longer holds a run slot of the given organization ID.
+func (sm *schedulerMetrics) FinishOrgRun(oid string) {
+	sm.orgRunsActive.WithLabelValues(oid).Dec()
+}
+
+// QueueOrgRun adjusts the metrics to indicate a task of the given organization ID was queued for its turn to start a due run.
+func (sm *schedulerMetrics) QueueOrgRun(oid string) {
+	sm.orgRunsQueued.WithLabelValues(oid).Inc()
+}
+
+// SetOrgTasksBlocked sets the number of tasks of the given organization ID which are blocked by its concurrency limit.
+func (sm *schedulerMetrics) SetOrgTasksBlocked(oid string, n int) {
+	sm.orgTasksBlocked.WithLabelValues(oid).Set(float64(n))
 }
 
 func statusString(succeeded bool) string {
@@ -0,0 +1,209 @@
+package backend
+
+import (
+	"context"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// orgSlots limits the number of concurrent runs of the tasks of each organization.
+// Tasks whose due runs are blocked by the limit wait in a FIFO queue per organization,
+// so that the slots of an organization are shared fairly among its tasks.
+type orgSlots struct {
+	// Default limit for every organization, and per-organization overrides. A limit of 0 means no limit.
+	defaultLimit int
+	limits       map[platform.ID]int
+
+	metrics *schedulerMetrics
+
+	mu      sync.Mutex                       // Protects following fields.
+	running map[platform.ID]int              // org ID -> number of runs holding a slot.
+	blocked map[platform.ID][]*taskScheduler // org ID -> tasks waiting for a slot, in order of arrival.
+}
+
+func newOrgSlots(metrics *schedulerMetrics) *orgSlots {
+	return &orgSlots{
+		limits:  make(map[platform.ID]int),
+		metrics: metrics,
+		running: make(map[platform.ID]int),
+		blocked: make(map[platform.ID][]*taskScheduler),
+	}
+}
+
+func (o *orgSlots) limit(org platform.ID) int {
+	if l, ok := o.limits[org]; ok {
+		return l
+	}
+	return o.defaultLimit
+}
+
+// acquire takes a run slot of the organization of ts, and reports whether it succeeded.
+// A slot is only taken if it is not reserved for a task blocked ahead of ts;
+// otherwise ts is blocked until a slot frees up.
+// ctx is the context of ts's runners: once it is done, ts neither takes a slot nor waits for one.
+func (o *orgSlots) acquire(ctx context.Context, ts *taskScheduler) bool {
+	org := ts.task.Org
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// Checked under o.mu, so that a canceled ts can't be blocked after it was withdrawn.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	q := o.blocked[org]
+	pos := indexOfTaskScheduler(q, ts)
+	if limit := o.limit(org); limit > 0 {
+		ahead := pos
+		if ahead < 0 {
+			ahead = len(q)
+		}
+		if o.running[org]+ahead >= limit {
+			if pos < 0 {
+				o.blocked[org] = append(q, ts)
+				o.metrics.SetOrgTasksBlocked(org.String(), len(q)+1)
+			}
+			return false
+		}
+	}
+	if pos >= 0 {
+		o.unblock(org, pos)
+	}
+
+	o.running[org]++
+	o.metrics.StartOrgRun(org.String())
+	return true
+}
+
+// take takes a run slot of the organization of ts regardless of the limit.
+// It is used for runs which were already in progress when ts's task was claimed.
+func (o *orgSlots) take(ts *taskScheduler) {
+	org := ts.task.Org
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.running[org]++
+	o.metrics.StartOrgRun(org.String())
+}
+
+// release frees a run slot of the given organization,
+// and returns the blocked task for which the slot is reserved, which should be woken up, if any.
+func (o *orgSlots) release(org platform.ID) *taskScheduler {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.running[org] <= 1 {
+		delete(o.running, org)
+	} else {
+		o.running[org]--
+	}
+	o.metrics.FinishOrgRun(org.String())
+
+	// The tasks before this one already had a free slot reserved.
+	q := o.blocked[org]
+	if i := o.limit(org) - o.running[org] - 1; i >= 0 && i < len(q) {
+		return q[i]
+	}
+	return nil
+}
+
+// withdraw removes ts from the tasks blocked on its organization's limit, when ts has no more due runs or was released.
+// If that frees a slot for the next blocked task, withdraw returns that task, which should be woken up.
+func (o *orgSlots) withdraw(ts *taskScheduler) *taskScheduler {
+	org := ts.task.Org
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	q := o.blocked[org]
+	pos := indexOfTaskScheduler(q, ts)
+	if pos < 0 {
+		return nil
+	}
+	o.unblock(org, pos)
+
+	// Only the tasks after ts move up, and the first of them may now fit in a free slot.
+	if limit := o.limit(org); pos < len(q)-1 && o.running[org]+pos < limit {
+		return q[pos+1]
+	}
+	return nil
+}
+
+// unblock removes the task at pos from the blocked queue of org. o.mu must be held.
+func (o *orgSlots) unblock(org platform.ID, pos int) {
+	q := o.blocked[org]
+	if len(q) == 1 {
+		delete(o.blocked, org)
+		o.metrics.SetOrgTasksBlocked(org.String(), 0)
+		return
+	}
+
+	nq := make([]*taskScheduler, 0, len(q)-1)
+	nq = append(nq, q[:pos]...)
+	nq = append(nq, q[pos+1:]...)
+	o.blocked[org] = nq
+	o.metrics.SetOrgTasksBlocked(org.String(), len(nq))
+}
+
+func indexOfTaskScheduler(q []*taskScheduler, ts *taskScheduler) int {
+	for i := range q {
+		if q[i] == ts {
+			return i
+		}
+	}
+	return -1
+}
+
+// fairQueue round-robins the start of due runs across organizations,
+// so that an organization with many due tasks does not delay the runs of every other organization.
+type fairQueue struct {
+	orgs  []platform.ID                    // Organizations with queued tasks, in order of their next turn.
+	tasks map[platform.ID][]*taskScheduler // org ID -> queued tasks, in order of their next turn.
+
+	metrics *schedulerMetrics
+}
+
+func newFairQueue(metrics *schedulerMetrics) *fairQueue {
+	return &fairQueue{
+		tasks:   make(map[platform.ID][]*taskScheduler),
+		metrics: metrics,
+	}
+}
+
+// Push queues ts, which has a due run.
+func (q *fairQueue) Push(ts *taskScheduler) {
+	org := ts.task.Org
+	if _, ok := q.tasks[org]; !ok {
+		q.orgs = append(q.orgs, org)
+	}
+	q.tasks[org] = append(q.tasks[org], ts)
+	q.metrics.QueueOrgRun(org.String())
+}
+
+// Work starts one run of the next task of each organization in turn,
+// until none of the queued tasks can start another run.
+// A task stays in the queue as long as it starts runs, so it has its turn again after the other tasks of its organization.
+func (q *fairQueue) Work() {
+	for len(q.orgs) > 0 {
+		orgs := make([]platform.ID, 0, len(q.orgs))
+		for _, org := range q.orgs {
+			tasks := q.tasks[org]
+			ts := tasks[0]
+			tasks = tasks[1:]
+			if ts.startOne() {
+				tasks = append(tasks, ts)
+			}
+
+			if len(tasks) == 0 {
+				delete(q.tasks, org)
+				continue
+			}
+			q.tasks[org] = tasks
+			orgs = append(orgs, org)
+		}
+		q.orgs = orgs
+	}
+}
@@ -616,6 +616,288 @@ func TestScheduler_RunFailureCleanup(t *testing.T) {
 	}
 }
 
+func TestScheduler_Retry(t *testing.T) {
+	t.Parallel()
+
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	rl := backend.NewInMemRunReaderWriter()
+	s := backend.NewScheduler(d, e, rl, 5, backend.WithLogger(zaptest.NewLogger(t)), backend.WithRetryBackoff(time.Millisecond, 2*time.Millisecond))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	reg := prom.NewRegistry()
+	reg.MustRegister(s.PrometheusCollectors()...)
+
+	// Task whose runs are attempted twice.
+	task := &backend.StoreTask{
+		ID:  platform.ID(1),
+		Org: 2,
+		Script: `option task = {name: "retry", every: 1s, retry: 2}
+from(bucket: "b") |> range(start: -1h)`,
+	}
+	meta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "@every 1s",
+		LatestCompleted: 5,
+	}
+
+	d.SetTaskMeta(task.ID, *meta)
+	if err := s.ClaimTask(task, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Tick(6)
+	promises, err := e.PollForNumberRunning(task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := promises[0].Run()
+
+	// A retryable failure is retried as a new run for the same time.
+	promises[0].Finish(mock.NewRunResult(errors.New("transient failure"), true), nil)
+	var retry backend.QueuedRun
+	for i := 0; i < 50; i++ {
+		time.Sleep(2 * time.Millisecond)
+		if promises = e.RunningFor(task.ID); len(promises) == 1 && promises[0].Run().RunID != first.RunID {
+			retry = promises[0].Run()
+			break
+		}
+	}
+	if !retry.RunID.Valid() {
+		t.Fatal("failed run was not retried")
+	}
+	if retry.Now != first.Now || retry.Try != 2 {
+		t.Fatalf("expected retry of run at %d with try 2, got run at %d with try %d", first.Now, retry.Now, retry.Try)
+	}
+	pollForRunStatus(t, rl, task.ID, task.Org, 2, 0, backend.RunFail.String())
+	pollForRunStatus(t, rl, task.ID, task.Org, 2, 1, backend.RunStarted.String())
+
+	mfs := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mfs, "task_scheduler_runs_retried", map[string]string{"task_id": task.ID.String()})
+	if got := *m.Counter.Value; got != 1 {
+		t.Fatalf("expected 1 run retried for task ID %s, got %v", task.ID.String(), got)
+	}
+
+	// The last attempt is not retried.
+	promises[0].Finish(mock.NewRunResult(errors.New("transient failure"), true), nil)
+	if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	pollForRunStatus(t, rl, task.ID, task.Org, 2, 1, backend.RunFail.String())
+	if n := d.TotalRunsCreatedForTask(task.ID); n != 2 {
+		t.Fatalf("expected 2 runs created, got %d", n)
+	}
+
+	// Failures which are not retryable are not retried.
+	s.Tick(7)
+	promises, err = e.PollForNumberRunning(task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	promises[0].Finish(mock.NewRunResult(errors.New("invalid query"), false), nil)
+	if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	pollForRunStatus(t, rl, task.ID, task.Org, 3, 2, backend.RunFail.String())
+	if n := d.TotalRunsCreatedForTask(task.ID); n != 3 {
+		t.Fatalf("expected 3 runs created, got %d", n)
+	}
+}
+
+func TestScheduler_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	s := backend.NewScheduler(d, e, backend.NopLogWriter{}, 3059, backend.WithLogger(zaptest.NewLogger(t)))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	upstream := &backend.StoreTask{ID: platform.ID(1)}
+	upstreamMeta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "* * * * *", // Every minute.
+		LatestCompleted: 3000,
+	}
+	downstream := &backend.StoreTask{ID: platform.ID(2)}
+	downstreamMeta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "* * * * *",
+		LatestCompleted: 3000,
+		DependsOn:       []uint64{uint64(upstream.ID)},
+	}
+
+	for _, tm := range []struct {
+		task *backend.StoreTask
+		meta *backend.StoreTaskMeta
+	}{{upstream, upstreamMeta}, {downstream, downstreamMeta}} {
+		d.SetTaskMeta(tm.task.ID, *tm.meta)
+		if err := s.ClaimTask(tm.task, tm.meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the upstream task runs on its schedule.
+	s.Tick(3060)
+	promises, err := e.PollForNumberRunning(upstream.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.PollForNumberRunning(downstream.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A successful upstream run triggers a downstream run for the same time.
+	promises[0].Finish(mock.NewRunResult(nil, false), nil)
+	promises, err = e.PollForNumberRunning(downstream.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if now := promises[0].Run().Now; now != 3060 {
+		t.Fatalf("expected downstream run at 3060, got %d", now)
+	}
+	promises[0].Finish(mock.NewRunResult(nil, false), nil)
+	if _, err := e.PollForNumberRunning(downstream.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed upstream run does not.
+	s.Tick(3120)
+	promises, err = e.PollForNumberRunning(upstream.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	promises[0].Finish(mock.NewRunResult(errors.New("invalid query"), false), nil)
+	if _, err := e.PollForNumberRunning(upstream.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.PollForNumberRunning(downstream.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := d.TotalRunsCreatedForTask(downstream.ID); n != 1 {
+		t.Fatalf("expected 1 downstream run created, got %d", n)
+	}
+}
+
+// notificationRecorder is a backend.RunNotifier which records notifications, and fails to deliver those to failURL.
+type notificationRecorder struct {
+	failURL string
+	ch      chan string
+}
+
+func (n *notificationRecorder) Notify(ctx context.Context, rn backend.RunNotification, rule *backend.StoreTaskNotificationRule) error {
+	n.ch <- fmt.Sprintf("%s %s %d", rule.On, rn.Status, rn.ConsecutiveFailures)
+	if rule.URL == n.failURL {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestScheduler_Notifications(t *testing.T) {
+	t.Parallel()
+
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	rl := backend.NewInMemRunReaderWriter()
+	n := &notificationRecorder{failURL: "http://example.com/recovery", ch: make(chan string, 10)}
+	s := backend.NewScheduler(d, e, rl, 5, backend.WithLogger(zaptest.NewLogger(t)), backend.WithRunNotifier(n))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	task := &backend.StoreTask{
+		ID:  platform.ID(1),
+		Org: 2,
+	}
+	meta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "@every 1s",
+		LatestCompleted: 5,
+		NotificationRules: []*backend.StoreTaskNotificationRule{
+			{On: platform.TaskNotifyOnFailure, URL: "http://example.com/failure"},
+			{On: platform.TaskNotifyOnConsecutiveFailures, Threshold: 2, URL: "http://example.com/failures"},
+			{On: platform.TaskNotifyOnRecovery, URL: "http://example.com/recovery"},
+		},
+	}
+
+	d.SetTaskMeta(task.ID, *meta)
+	if err := s.ClaimTask(task, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNotifications := func(want ...string) {
+		t.Helper()
+		for _, w := range want {
+			select {
+			case got := <-n.ch:
+				if got != w {
+					t.Fatalf("expected notification %q, got %q", w, got)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("timed out waiting for notification %q", w)
+			}
+		}
+		select {
+		case got := <-n.ch:
+			t.Fatalf("unexpected notification %q", got)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+
+	finish := func(now int64, runErr error) backend.QueuedRun {
+		t.Helper()
+		s.Tick(now)
+		promises, err := e.PollForNumberRunning(task.ID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		qr := promises[0].Run()
+		promises[0].Finish(mock.NewRunResult(runErr, false), nil)
+		if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+			t.Fatal(err)
+		}
+		return qr
+	}
+
+	finish(6, errors.New("bad query"))
+	expectNotifications("failure failed 1")
+
+	finish(7, errors.New("bad query"))
+	expectNotifications("failure failed 2", "consecutive_failures failed 2")
+
+	// The notification of a single failure is sent for each failed run, but the threshold is only reached once.
+	finish(8, errors.New("bad query"))
+	expectNotifications("failure failed 3")
+
+	qr := finish(9, nil)
+	expectNotifications("recovery success 3")
+
+	// A successful run after a successful run is not notified.
+	finish(10, nil)
+	expectNotifications()
+
+	// The failed delivery of the recovery notification is recorded in the run's log.
+	for i := 0; ; i++ {
+		logs, err := rl.ListLogs(context.Background(), task.Org, platform.LogFilter{Task: task.ID, Run: &qr.RunID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, l := range logs {
+			if l.Level == platform.LogLevelWarn && l.Message == "Failed to send recovery notification to http://example.com/recovery" && strings.Contains(l.Fields["error"], "connection refused") {
+				found = true
+			}
+		}
+		if found {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("expected failed delivery in run log, got %v", logs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 func TestScheduler_Metrics(t *testing.T) {
 	t.Parallel()
 
@@ -745,6 +1027,93 @@ func TestScheduler_Metrics(t *testing.T) {
 	}
 }
 
+func TestScheduler_OrgConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	s := backend.NewScheduler(d, e, backend.NopLogWriter{}, 5, backend.WithLogger(zaptest.NewLogger(t)), backend.WithOrgConcurrencyLimit(1))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	reg := prom.NewRegistry()
+	reg.MustRegister(s.PrometheusCollectors()...)
+
+	// Two tasks of a busy organization, and one task of another organization.
+	busyOrg, otherOrg := platform.ID(10), platform.ID(20)
+	tasks := []*backend.StoreTask{
+		{ID: platform.ID(1), Org: busyOrg},
+		{ID: platform.ID(2), Org: busyOrg},
+		{ID: platform.ID(3), Org: otherOrg},
+	}
+	for _, task := range tasks {
+		meta := &backend.StoreTaskMeta{
+			MaxConcurrency:  1,
+			EffectiveCron:   "@every 1s",
+			LatestCompleted: 5,
+		}
+		d.SetTaskMeta(task.ID, *meta)
+		if err := s.ClaimTask(task, meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.Tick(6)
+
+	// The other organization is not held back by the busy one.
+	if _, err := e.PollForNumberRunning(tasks[2].ID, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only one task of the busy organization runs, the other one is blocked.
+	var running, blocked *backend.StoreTask
+	for i := 0; i < 50 && running == nil; i++ {
+		for j, task := range tasks[:2] {
+			if len(e.RunningFor(task.ID)) > 0 {
+				running, blocked = task, tasks[1-j]
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	if running == nil {
+		t.Fatal("expected a run of the busy organization to start")
+	}
+	if n := len(e.RunningFor(blocked.ID)); n != 0 {
+		t.Fatalf("expected task %s to be blocked by the organization's limit, got %d running", blocked.ID, n)
+	}
+
+	mfs := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mfs, "task_scheduler_org_tasks_blocked", map[string]string{"org_id": busyOrg.String()})
+	if got := *m.Gauge.Value; got != 1 {
+		t.Fatalf("expected 1 task blocked for org ID %s, got %v", busyOrg, got)
+	}
+	for org, queued := range map[platform.ID]float64{busyOrg: 2, otherOrg: 1} {
+		m = promtest.MustFindMetric(t, mfs, "task_scheduler_org_runs_active", map[string]string{"org_id": org.String()})
+		if got := *m.Gauge.Value; got != 1 {
+			t.Fatalf("expected 1 run active for org ID %s, got %v", org, got)
+		}
+		m = promtest.MustFindMetric(t, mfs, "task_scheduler_org_runs_queued", map[string]string{"org_id": org.String()})
+		if got := *m.Counter.Value; got != queued {
+			t.Fatalf("expected %v runs queued for org ID %s, got %v", queued, org, got)
+		}
+	}
+
+	// Finishing the run frees the slot for the blocked task.
+	e.RunningFor(running.ID)[0].Finish(mock.NewRunResult(nil, false), nil)
+	if _, err := e.PollForNumberRunning(blocked.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.PollForNumberRunning(running.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	mfs = promtest.MustGather(t, reg)
+	m = promtest.MustFindMetric(t, mfs, "task_scheduler_org_tasks_blocked", map[string]string{"org_id": busyOrg.String()})
+	if got := *m.Gauge.Value; got != 0 {
+		t.Fatalf("expected 0 tasks blocked for org ID %s, got %v", busyOrg, got)
+	}
+}
+
 type fakeWaitExecutor struct {
 	wait chan struct{}
 }
@@ -33,6 +33,21 @@ var (
 
 	// ErrRunNotFinished is returned when a retry is invalid due to the run not being finished yet.
 	ErrRunNotFinished = errors.New("run is still in progress")
+
+	// ErrBackfillNotFound is returned when a backfill does not exist, or all its runs have already been created.
+	ErrBackfillNotFound = errors.New("backfill not found")
+
+	// ErrNoSchedulesInRange is returned when a backfill is requested for a time range without any schedule of the task.
+	ErrNoSchedulesInRange = errors.New("no schedules of the task in time range")
+
+	// ErrDependencyCycle is returned when a task would depend directly or indirectly on itself.
+	ErrDependencyCycle = errors.New("task dependencies must not form a cycle")
+
+	// ErrTaskLeased is returned when leasing a task whose lease is held by another owner and has not expired.
+	ErrTaskLeased = errors.New("task leased by another owner")
+
+	// ErrRevisionNotFound is returned when searching for a revision of a task's script that doesn't exist.
+	ErrRevisionNotFound = errors.New("task revision not found")
 )
 
 type TaskStatus string
@@ -163,6 +178,12 @@ type CreateTaskRequest struct {
 	// The initial task status.
 	// If empty, will be treated as DefaultTaskStatus.
 	Status TaskStatus
+
+	// Rules for notifications about the outcome of the task's runs.
+	NotificationRules []*StoreTaskNotificationRule
+
+	// The user creating the task, recorded in the task's first revision.
+	UserID platform.ID
 }
 
 // UpdateTaskRequest encapsulates requested changes to a task.
@@ -182,6 +203,13 @@ type UpdateTaskRequest struct {
 	// If zero, do not modify the existing authorization ID.
 	AuthorizationID platform.ID
 
+	// The new notification rules.
+	// If nil, do not modify the existing notification rules.
+	NotificationRules *[]*StoreTaskNotificationRule
+
+	// The user updating the task, recorded in the new revision if the script changes.
+	UserID platform.ID
+
 	// These options are for editing options via request.  Zeroed options will be ignored.
 	options.Options
 }
@@ -252,11 +280,73 @@ type Store interface {
 	// FinishRun removes runID from the list of running tasks and if its `now` is later then last completed update it.
 	FinishRun(ctx context.Context, taskID, runID platform.ID) error
 
+	// RetryRun replaces runID in the list of running tasks with a new run for the same `now`, whose try is one higher.
+	// RetryRun must delegate to an underlying StoreTaskMeta's RetryRun method.
+	RetryRun(ctx context.Context, taskID, runID platform.ID) (QueuedRun, error)
+
 	// ManuallyRunTimeRange enqueues a request to run the task with the given ID for all schedules no earlier than start and no later than end (Unix timestamps).
 	// requestedAt is the Unix timestamp when the request was initiated.
 	// ManuallyRunTimeRange must delegate to an underlying StoreTaskMeta's ManuallyRunTimeRange method.
 	ManuallyRunTimeRange(ctx context.Context, taskID platform.ID, start, end, requestedAt int64) (*StoreTaskMetaManualRun, error)
 
+	// Backfill enqueues a request to run the task with the given ID for all schedules no earlier than start and no later than end (Unix timestamps),
+	// which can be paused, resumed or cancelled by the backfill ID of the returned manual run.
+	// requestedAt is the Unix timestamp when the request was initiated.
+	// Backfill must delegate to an underlying StoreTaskMeta's Backfill method.
+	Backfill(ctx context.Context, taskID platform.ID, start, end, requestedAt int64) (*StoreTaskMetaManualRun, error)
+
+	// SetBackfillPaused pauses or resumes creating runs for a backfill of the task with the given ID.
+	// SetBackfillPaused must delegate to an underlying StoreTaskMeta's SetBackfillPaused method.
+	SetBackfillPaused(ctx context.Context, taskID, backfillID platform.ID, paused bool) (*StoreTaskMetaManualRun, error)
+
+	// CancelBackfill removes a backfill of the task with the given ID, and returns the removed backfill.
+	// CancelBackfill must delegate to an underlying StoreTaskMeta's CancelBackfill method.
+	CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) (*StoreTaskMetaManualRun, error)
+
+	// FindDependentTasks returns the IDs of the tasks which depend on the task with the given ID.
+	FindDependentTasks(ctx context.Context, taskID platform.ID) ([]platform.ID, error)
+
+	// QueueDependentRuns requests a run for now of each active task which depends on the task with the given ID,
+	// after a run of that task succeeded for now, and returns the IDs of the tasks for which a run was requested.
+	// If requesting a run fails for a dependent task, the runs of the other dependent tasks are still requested,
+	// and the first error is returned.
+	// QueueDependentRuns must delegate to the underlying StoreTaskMeta's QueueDependentRun method of each dependent task.
+	QueueDependentRuns(ctx context.Context, taskID platform.ID, now int64) ([]platform.ID, error)
+
+	// QueueTriggeredRun requests a run for now of the task with the given ID, after points were written to the bucket of its trigger.
+	// start and stop are the Unix timestamps in nanoseconds of the earliest and latest written points.
+	// QueueTriggeredRun must delegate to an underlying StoreTaskMeta's QueueTriggeredRun method.
+	QueueTriggeredRun(ctx context.Context, taskID platform.ID, now, start, stop int64) error
+
+	// LeaseTask acquires or renews the lease of owner on the task with the given ID until the Unix timestamp expiresAt,
+	// and returns the task and its meta.
+	// If another owner holds a lease on the task which has not expired at the Unix timestamp now, ErrTaskLeased is returned.
+	// LeaseTask must delegate to an underlying StoreTaskMeta's Lease method.
+	LeaseTask(ctx context.Context, taskID platform.ID, owner string, now, expiresAt int64) (*StoreTask, *StoreTaskMeta, error)
+
+	// LeaseTasks acquires or renews the leases of owner on the tasks with the given IDs until the Unix timestamp expiresAt,
+	// in a single transaction, and returns the leased tasks and their meta, in the order of taskIDs.
+	// Tasks which don't exist, or on which another owner holds a lease which has not expired at the Unix timestamp now,
+	// are left out of the result.
+	// LeaseTasks must delegate to an underlying StoreTaskMeta's Lease method.
+	LeaseTasks(ctx context.Context, taskIDs []platform.ID, owner string, now, expiresAt int64) ([]StoreTaskWithMeta, error)
+
+	// ReleaseTaskLease releases the lease on the task with the given ID, if it is held by owner.
+	ReleaseTaskLease(ctx context.Context, taskID platform.ID, owner string) error
+
+	// RegisterLeaseOwner records that owner is alive until the Unix timestamp expiresAt,
+	// and returns the sorted names of all owners which are alive at the Unix timestamp now, including owner.
+	// Owners which have not registered again before they expire are forgotten.
+	RegisterLeaseOwner(ctx context.Context, owner string, now, expiresAt int64) ([]string, error)
+
+	// ListTaskRevisions returns the revisions of the script of the task with the given ID, oldest first.
+	// A revision is recorded when the task is created, and each time its script is changed.
+	ListTaskRevisions(ctx context.Context, taskID platform.ID) ([]StoreTaskRevision, error)
+
+	// FindTaskRevision returns the given revision of the script of the task with the given ID.
+	// If the task has no such revision, ErrRevisionNotFound is returned.
+	FindTaskRevision(ctx context.Context, taskID platform.ID, revision int64) (*StoreTaskRevision, error)
+
 	// DeleteOrg deletes the org.
 	DeleteOrg(ctx context.Context, orgID platform.ID) error
 
@@ -277,6 +367,20 @@ type RunLogBase struct {
 
 	// When the log is requested, should be ignored when it is zero.
 	RequestedAt int64
+
+	// The revision of the task's script executed by the run, should be ignored when it is zero.
+	Revision int64
+}
+
+// RunLogEntry is a structured entry of the log of a run.
+type RunLogEntry struct {
+	// The severity of the entry. If empty, platform.LogLevelInfo is used.
+	Level platform.LogLevel
+
+	Message string
+
+	// Additional context of the entry, such as an error or the ID of a related run. May be nil.
+	Fields map[string]string
 }
 
 // LogWriter writes task logs and task state changes to a store.
@@ -284,8 +388,11 @@ type LogWriter interface {
 	// UpdateRunState sets the run state and the respective time.
 	UpdateRunState(ctx context.Context, base RunLogBase, when time.Time, state RunStatus) error
 
-	// AddRunLog adds a log line to the run.
-	AddRunLog(ctx context.Context, base RunLogBase, when time.Time, log string) error
+	// AddRunLog adds a log entry to the run.
+	AddRunLog(ctx context.Context, base RunLogBase, when time.Time, entry RunLogEntry) error
+
+	// AddRunStatistics records the statistics of the query executed by the run, once it has finished executing.
+	AddRunStatistics(ctx context.Context, base RunLogBase, when time.Time, stats platform.RunStatistics) error
 }
 
 // NopLogWriter is a LogWriter that doesn't do anything when its methods are called.
@@ -298,7 +405,11 @@ func (NopLogWriter) UpdateRunState(context.Context, RunLogBase, time.Time, RunSt
 	return nil
 }
 
-func (NopLogWriter) AddRunLog(context.Context, RunLogBase, time.Time, string) error {
+func (NopLogWriter) AddRunLog(context.Context, RunLogBase, time.Time, RunLogEntry) error {
+	return nil
+}
+
+func (NopLogWriter) AddRunStatistics(context.Context, RunLogBase, time.Time, platform.RunStatistics) error {
 	return nil
 }
 
@@ -312,7 +423,8 @@ type LogReader interface {
 	// orgID is necessary to look in the correct system bucket.
 	FindRunByID(ctx context.Context, orgID, runID platform.ID) (*platform.Run, error)
 
-	// ListLogs lists logs for a task or a specified run of a task.
+	// ListLogs lists the log entries of a task or a specified run of a task,
+	// ordered by run and then by time.
 	// orgID is necessary to look in the correct system bucket.
 	ListLogs(ctx context.Context, orgID platform.ID, logFilter platform.LogFilter) ([]platform.Log, error)
 }
@@ -363,6 +475,21 @@ type StoreTask struct {
 	Script string
 }
 
+// StoreTaskRevision is an immutable record of a task's script, created when the script changes.
+type StoreTaskRevision struct {
+	// The revision number, starting at 1 and matching StoreTaskMeta.Revision while it is current.
+	Revision int64
+
+	// The script content of the task as of the revision.
+	Script string
+
+	// The user who created or updated the task. Zero if unknown.
+	UpdatedBy platform.ID
+
+	// Unix timestamp of when the revision was created.
+	UpdatedAt int64
+}
+
 // StoreTaskWithMeta is a single struct with a StoreTask and a StoreTaskMeta.
 type StoreTaskWithMeta struct {
 	Task StoreTask
@@ -410,14 +537,78 @@ func (StoreValidation) CreateArgs(req CreateTaskRequest) (options.Options, error
 	return o, nil
 }
 
+// Dependencies returns an error if the task with the given ID, in the given organization,
+// cannot depend on the upstream tasks in the options o:
+// if an upstream task does not exist or belongs to another organization,
+// or if an upstream task depends directly or indirectly on the task, which would create a cycle.
+// When validating a task which is not yet created, taskID is the invalid ID.
+func (StoreValidation) Dependencies(ctx context.Context, s Store, taskID, org platform.ID, o options.Options) error {
+	upstream := make([]platform.ID, 0, len(o.DependsOn))
+	for _, str := range o.DependsOn {
+		id, err := platform.IDFromString(str)
+		if err != nil {
+			return fmt.Errorf("invalid upstream task ID %q: %v", str, err)
+		}
+		if *id == taskID {
+			return ErrDependencyCycle
+		}
+
+		t, err := s.FindTaskByID(ctx, *id)
+		if err != nil {
+			if err == ErrTaskNotFound {
+				return fmt.Errorf("upstream task %s not found", id)
+			}
+			return err
+		}
+		if t.Org != org {
+			return fmt.Errorf("upstream task %s belongs to another organization", id)
+		}
+
+		upstream = append(upstream, *id)
+	}
+
+	if !taskID.Valid() {
+		// Nothing can depend on a task which does not exist yet.
+		return nil
+	}
+
+	// Walk the upstream tasks until there are none left, or until the task itself is reached.
+	seen := make(map[platform.ID]bool)
+	for len(upstream) > 0 {
+		id := upstream[len(upstream)-1]
+		upstream = upstream[:len(upstream)-1]
+		if id == taskID {
+			return ErrDependencyCycle
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		meta, err := s.FindTaskMetaByID(ctx, id)
+		if err != nil {
+			if err == ErrTaskNotFound {
+				// A deleted task is not part of a cycle.
+				continue
+			}
+			return err
+		}
+		for _, u := range meta.DependsOn {
+			upstream = append(upstream, platform.ID(u))
+		}
+	}
+
+	return nil
+}
+
 // UpdateArgs validates the UpdateTaskRequest.
 // If the update only includes a new status (i.e. req.Script is empty), the returned options are zero.
 // If the update contains neither a new script nor a new status, or if the script is invalid, an error is returned.
 func (StoreValidation) UpdateArgs(req UpdateTaskRequest) (options.Options, error) {
 	var missing []string
 	o := req.Options
-	if req.Script == "" && req.Status == "" && req.Options.IsZero() && !req.AuthorizationID.Valid() {
-		missing = append(missing, "script or status or options or authorizationID")
+	if req.Script == "" && req.Status == "" && req.Options.IsZero() && !req.AuthorizationID.Valid() && req.NotificationRules == nil {
+		missing = append(missing, "script or status or options or authorizationID or notification rules")
 	}
 
 	if req.Script != "" {
@@ -136,22 +136,35 @@ func runLogTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc) {
 		t.Fatal(err)
 	}
 
-	if err := writer.AddRunLog(ctx, rlb, sa.Add(time.Second), "first"); err != nil {
+	if err := writer.AddRunLog(ctx, rlb, sa.Add(time.Second), backend.RunLogEntry{Message: "first"}); err != nil {
 		t.Fatal(err)
 	}
-	if err := writer.AddRunLog(ctx, rlb, sa.Add(2*time.Second), "second"); err != nil {
+	if err := writer.AddRunLog(ctx, rlb, sa.Add(2*time.Second), backend.RunLogEntry{Level: platform.LogLevelWarn, Message: "second"}); err != nil {
 		t.Fatal(err)
 	}
-	if err := writer.AddRunLog(ctx, rlb, sa.Add(3*time.Second), "third"); err != nil {
+	if err := writer.AddRunLog(ctx, rlb, sa.Add(3*time.Second), backend.RunLogEntry{Level: platform.LogLevelError, Message: "third", Fields: map[string]string{"error": "oops"}}); err != nil {
 		t.Fatal(err)
 	}
 
-	run.Log = platform.Log(fmt.Sprintf(
-		"%s: first\n%s: second\n%s: third",
-		sa.Add(time.Second).Format(time.RFC3339Nano),
-		sa.Add(2*time.Second).Format(time.RFC3339Nano),
-		sa.Add(3*time.Second).Format(time.RFC3339Nano),
-	))
+	stats := platform.RunStatistics{
+		TotalDuration:   3 * time.Second,
+		CompileDuration: time.Millisecond,
+		ExecuteDuration: 2 * time.Second,
+		Concurrency:     2,
+		MaxAllocated:    1024,
+		ScannedValues:   100,
+		RowsWritten:     10,
+	}
+	if err := writer.AddRunStatistics(ctx, rlb, sa.Add(3*time.Second), stats); err != nil {
+		t.Fatal(err)
+	}
+
+	run.Log = []platform.Log{
+		{RunID: run.ID, Time: sa.Add(time.Second).Format(time.RFC3339Nano), Level: platform.LogLevelInfo, Message: "first"},
+		{RunID: run.ID, Time: sa.Add(2 * time.Second).Format(time.RFC3339Nano), Level: platform.LogLevelWarn, Message: "second"},
+		{RunID: run.ID, Time: sa.Add(3 * time.Second).Format(time.RFC3339Nano), Level: platform.LogLevelError, Message: "third", Fields: map[string]string{"error": "oops"}},
+	}
+	run.Statistics = &stats
 	returnedRun, err := reader.FindRunByID(ctx, task.Org, run.ID)
 	if err != nil {
 		t.Fatal(err)
@@ -321,7 +334,7 @@ func findRunByIDTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFu
 		t.Fatalf("expected:\n%#v, got: \n%#v", run, *returnedRun)
 	}
 
-	returnedRun.Log = "cows"
+	returnedRun.Log = []platform.Log{{Message: "cows"}}
 
 	rr2, err := reader.FindRunByID(ctx, task.Org, run.ID)
 	if err != nil {
@@ -378,7 +391,7 @@ func listLogsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 			t.Fatal(err)
 		}
 
-		writer.AddRunLog(ctx, rlb, sf.Add(2*time.Millisecond), fmt.Sprintf("log%d", i))
+		writer.AddRunLog(ctx, rlb, sf.Add(2*time.Millisecond), backend.RunLogEntry{Message: fmt.Sprintf("log%d", i)})
 	}
 
 	const targetRun = 4
@@ -391,8 +404,9 @@ func listLogsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 	}
 
 	fmtTimelog := now.Add(time.Duration(targetRun-nRuns)*time.Second + 2*time.Millisecond).Format(time.RFC3339Nano)
-	if fmtTimelog+": log4" != string(logs[0]) {
-		t.Fatalf("expected: %q, got: %q", fmtTimelog+": log4", string(logs[0]))
+	expLog := platform.Log{RunID: runs[targetRun].ID, Time: fmtTimelog, Level: platform.LogLevelInfo, Message: "log4"}
+	if diff := cmp.Diff(expLog, logs[0]); diff != "" {
+		t.Fatalf("unexpected log: -want/+got: %s", diff)
 	}
 
 	logs, err = reader.ListLogs(ctx, task.Org, platform.LogFilter{Task: task.ID})
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/options"
 )
 
 var idGen = snowflake.NewIDGenerator()
@@ -33,7 +35,13 @@ func NewStoreTest(name string, cf CreateStoreFunc, df DestroyStoreFunc, funcName
 			"DeleteTask",
 			"CreateNextRun",
 			"FinishRun",
+			"RetryRun",
 			"ManuallyRunTimeRange",
+			"Backfill",
+			"Dependencies",
+			"LeaseTask",
+			"NotificationRules",
+			"Revisions",
 		}
 	}
 	availableFuncs := map[string]TestFunc{
@@ -46,7 +54,13 @@ func NewStoreTest(name string, cf CreateStoreFunc, df DestroyStoreFunc, funcName
 		"DeleteTask":           testStoreDelete,
 		"CreateNextRun":        testStoreCreateNextRun,
 		"FinishRun":            testStoreFinishRun,
+		"RetryRun":             testStoreRetryRun,
 		"ManuallyRunTimeRange": testStoreManuallyRunTimeRange,
+		"Backfill":             testStoreBackfill,
+		"Dependencies":         testStoreDependencies,
+		"LeaseTask":            testStoreLeaseTask,
+		"NotificationRules":    testStoreNotificationRules,
+		"Revisions":            testStoreRevisions,
 		"DeleteOrg":            testStoreDeleteOrg,
 	}
 
@@ -936,6 +950,58 @@ from(bucket:"test") |> range(start:-1h)`
 	}
 }
 
+func testStoreRetryRun(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+		retry: 3,
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+	s := create(t)
+	defer destroy(t, s)
+
+	task, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := s.CreateNextRun(context.Background(), task, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	qr, err := s.RetryRun(context.Background(), task, rc.Created.RunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qr.TaskID != task {
+		t.Fatalf("expected retry of task %s, got %s", task, qr.TaskID)
+	}
+	if qr.RunID == rc.Created.RunID {
+		t.Fatal("expected retry to have a new run ID")
+	}
+	if qr.Now != rc.Created.Now || qr.Try != 2 {
+		t.Fatalf("expected retry with now %d and try 2, got now %d and try %d", rc.Created.Now, qr.Now, qr.Try)
+	}
+
+	meta, err := s.FindTaskMetaByID(context.Background(), task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.CurrentlyRunning) != 1 || platform.ID(meta.CurrentlyRunning[0].RunID) != qr.RunID {
+		t.Fatalf("expected retry to replace the run in progress, got %v", meta.CurrentlyRunning)
+	}
+
+	if _, err := s.RetryRun(context.Background(), task, rc.Created.RunID); err == nil {
+		t.Fatal("expected failure when retrying run that doesnt exist")
+	}
+
+	if err := s.FinishRun(context.Background(), task, qr.RunID); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func testStoreManuallyRunTimeRange(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
 	const script = `option task = {
 		name: "a task",
@@ -973,6 +1039,173 @@ from(bucket:"test") |> range(start:-1h)`
 	}
 }
 
+func testStoreBackfill(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+	s := create(t)
+	defer destroy(t, s)
+
+	taskID, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: 6000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr, err := s.Backfill(context.Background(), taskID, 1, 599, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.Start != 60 || mr.End != 540 || mr.BackfillID == 0 {
+		t.Fatalf("expected backfill from 60 to 540 with an ID, got %#v", mr)
+	}
+	backfillID := platform.ID(mr.BackfillID)
+
+	if _, err := s.Backfill(context.Background(), taskID, 1, 2, 5); err != backend.ErrNoSchedulesInRange {
+		t.Fatalf("expected ErrNoSchedulesInRange, got %v", err)
+	}
+
+	if _, err := s.SetBackfillPaused(context.Background(), taskID, backfillID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := s.FindTaskMetaByID(context.Background(), taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.ManualRuns) != 1 || !meta.ManualRuns[0].Paused || platform.ID(meta.ManualRuns[0].BackfillID) != backfillID {
+		t.Fatalf("expected 1 paused backfill, got %v", meta.ManualRuns)
+	}
+
+	// Nothing is due, and the paused backfill must not create a run.
+	if _, err := s.CreateNextRun(context.Background(), taskID, 6001); err == nil {
+		t.Fatal("expected no run to be created from a paused backfill")
+	}
+
+	if _, err := s.SetBackfillPaused(context.Background(), taskID, backfillID, false); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := s.CreateNextRun(context.Background(), taskID, 6001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Created.Now != 60 || !rc.HasQueue {
+		t.Fatalf("expected run for 60 with the backfill still queued, got %#v", rc)
+	}
+
+	if _, err := s.CancelBackfill(context.Background(), taskID, backfillID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CancelBackfill(context.Background(), taskID, backfillID); err != backend.ErrBackfillNotFound {
+		t.Fatalf("expected ErrBackfillNotFound, got %v", err)
+	}
+
+	if _, err := s.Backfill(context.Background(), platform.ID(9999), 1, 599, 5); err == nil {
+		t.Fatal("expected failure when backfilling a task that does not exist")
+	}
+}
+
+func testStoreDependencies(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const scriptFmt = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+		dependsOn: [%s],
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+	script := func(upstream ...platform.ID) string {
+		ids := make([]string, len(upstream))
+		for i, id := range upstream {
+			ids[i] = fmt.Sprintf("%q", id.String())
+		}
+		return fmt.Sprintf(scriptFmt, strings.Join(ids, ", "))
+	}
+	const noDepsScript = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+
+	s := create(t)
+	defer destroy(t, s)
+
+	a, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: noDepsScript, ScheduleAfter: 6000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script(a), ScheduleAfter: 6000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script(b), ScheduleAfter: 6000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := s.FindTaskMetaByID(context.Background(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.DependsOn) != 1 || platform.ID(meta.DependsOn[0]) != a {
+		t.Fatalf("expected task to depend on %s, got %v", a, meta.DependsOn)
+	}
+
+	// Upstream tasks must exist, in the same organization.
+	if _, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script(platform.ID(9999))}); err == nil {
+		t.Fatal("expected failure when depending on a task that does not exist")
+	}
+	if _, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 2, AuthorizationID: 3, Script: script(a)}); err == nil {
+		t.Fatal("expected failure when depending on a task in another organization")
+	}
+
+	// Dependencies must not form a cycle.
+	if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: a, Script: script(c)}); err != backend.ErrDependencyCycle {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: a, Script: script(a)}); err != backend.ErrDependencyCycle {
+		t.Fatalf("expected ErrDependencyCycle for task depending on itself, got %v", err)
+	}
+
+	ids, err := s.FindDependentTasks(context.Background(), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != b {
+		t.Fatalf("expected %s to depend on %s, got %v", b, a, ids)
+	}
+
+	// A successful run of a for 6060 requests a run of b for the same time.
+	ids, err = s.QueueDependentRuns(context.Background(), a, 6060)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != b {
+		t.Fatalf("expected run of %s to be requested, got %v", b, ids)
+	}
+	rc, err := s.CreateNextRun(context.Background(), b, 6001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Created.Now != 6060 {
+		t.Fatalf("expected run for 6060, got %d", rc.Created.Now)
+	}
+
+	// Removing the dependency by updating the script.
+	if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: b, Script: noDepsScript}); err != nil {
+		t.Fatal(err)
+	}
+	ids, err = s.FindDependentTasks(context.Background(), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no tasks to depend on %s, got %v", a, ids)
+	}
+}
+
 func testStoreDeleteOrg(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
 	s := create(t)
 	defer destroy(t, s)
@@ -1020,3 +1253,285 @@ from(bucket:"test") |> range(start:-1h)`
 	}
 	return ids
 }
+
+func testStoreLeaseTask(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+
+	s := create(t)
+	defer destroy(t, s)
+
+	id, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: 6000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, meta, err := s.LeaseTask(context.Background(), id, "a", 100, 130)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != id || task.Script != script {
+		t.Fatalf("unexpected leased task %#v", task)
+	}
+	if meta.LeaseOwner != "a" || meta.LeaseExpiresAt != 130 {
+		t.Fatalf("expected lease of a until 130, got %q until %d", meta.LeaseOwner, meta.LeaseExpiresAt)
+	}
+
+	// Another owner can't lease the task until the lease expires.
+	if _, _, err := s.LeaseTask(context.Background(), id, "b", 110, 140); err != backend.ErrTaskLeased {
+		t.Fatalf("expected ErrTaskLeased, got %v", err)
+	}
+	// Releasing the lease of another owner has no effect.
+	if err := s.ReleaseTaskLease(context.Background(), id, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, meta, err = s.LeaseTask(context.Background(), id, "a", 120, 150); err != nil {
+		t.Fatal(err)
+	}
+	if meta.LeaseExpiresAt != 150 {
+		t.Fatalf("expected renewed lease until 150, got %d", meta.LeaseExpiresAt)
+	}
+
+	// Runs in progress are kept for the new owner of an expired lease.
+	rc, err := s.CreateNextRun(context.Background(), id, 6060)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, meta, err = s.LeaseTask(context.Background(), id, "b", 150, 180); err != nil {
+		t.Fatal(err)
+	}
+	if meta.LeaseOwner != "b" || len(meta.CurrentlyRunning) != 1 || platform.ID(meta.CurrentlyRunning[0].RunID) != rc.Created.RunID {
+		t.Fatalf("expected lease of b with run %s in progress, got %q with %v", rc.Created.RunID, meta.LeaseOwner, meta.CurrentlyRunning)
+	}
+
+	// After release, any owner can lease the task.
+	if err := s.ReleaseTaskLease(context.Background(), id, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if meta, err = s.FindTaskMetaByID(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if meta.LeaseOwner != "" {
+		t.Fatalf("expected released lease, got lease of %q", meta.LeaseOwner)
+	}
+	if _, _, err := s.LeaseTask(context.Background(), id, "a", 160, 190); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := s.LeaseTask(context.Background(), platform.ID(9999), "a", 160, 190); err != backend.ErrTaskNotFound {
+		t.Fatalf("expected ErrTaskNotFound leasing missing task, got %v", err)
+	}
+
+	// Leasing several tasks at once leaves out the tasks leased by others and the missing tasks.
+	id2, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: 6000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leased, err := s.LeaseTasks(context.Background(), []platform.ID{id, id2, platform.ID(9999)}, "b", 170, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leased) != 1 || leased[0].Task.ID != id2 || leased[0].Task.Script != script || leased[0].Meta.LeaseOwner != "b" || leased[0].Meta.LeaseExpiresAt != 200 {
+		t.Fatalf("expected only %s to be leased by b until 200, got %#v", id2, leased)
+	}
+	if leased, err = s.LeaseTasks(context.Background(), []platform.ID{id2, id}, "a", 180, 210); err != nil {
+		t.Fatal(err)
+	}
+	if len(leased) != 1 || leased[0].Task.ID != id || leased[0].Meta.LeaseOwner != "a" || leased[0].Meta.LeaseExpiresAt != 210 {
+		t.Fatalf("expected only %s to be renewed by a until 210, got %#v", id, leased)
+	}
+	if meta, err = s.FindTaskMetaByID(context.Background(), id2); err != nil {
+		t.Fatal(err)
+	}
+	if meta.LeaseOwner != "b" || meta.LeaseExpiresAt != 200 {
+		t.Fatalf("expected lease of b until 200 to be kept, got %q until %d", meta.LeaseOwner, meta.LeaseExpiresAt)
+	}
+
+	// Owners are forgotten once their registration expires.
+	if owners, err := s.RegisterLeaseOwner(context.Background(), "b", 100, 130); err != nil || !cmp.Equal(owners, []string{"b"}) {
+		t.Fatalf("expected owners [b], got %v (%v)", owners, err)
+	}
+	if owners, err := s.RegisterLeaseOwner(context.Background(), "a", 110, 140); err != nil || !cmp.Equal(owners, []string{"a", "b"}) {
+		t.Fatalf("expected owners [a b], got %v (%v)", owners, err)
+	}
+	if owners, err := s.RegisterLeaseOwner(context.Background(), "a", 135, 165); err != nil || !cmp.Equal(owners, []string{"a"}) {
+		t.Fatalf("expected owners [a], got %v (%v)", owners, err)
+	}
+}
+
+func testStoreNotificationRules(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+
+	s := create(t)
+	defer destroy(t, s)
+
+	rules := []*backend.StoreTaskNotificationRule{
+		{On: platform.TaskNotifyOnFailure, URL: "http://example.com/failure"},
+		{On: platform.TaskNotifyOnConsecutiveFailures, Threshold: 3, URL: "http://example.com/failures", AuthHeader: "Authorization", AuthSecretKey: "token"},
+	}
+	id, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, NotificationRules: rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := s.FindTaskMetaByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(meta.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules on created task: %s", cmp.Diff(rules, meta.NotificationRules))
+	}
+
+	// Updating other fields keeps the rules.
+	if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: id, Status: backend.TaskInactive}); err != nil {
+		t.Fatal(err)
+	}
+	if meta, err = s.FindTaskMetaByID(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(meta.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules after status update: %s", cmp.Diff(rules, meta.NotificationRules))
+	}
+
+	rules = []*backend.StoreTaskNotificationRule{{On: platform.TaskNotifyOnRecovery, URL: "http://example.com/recovery"}}
+	res, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: id, NotificationRules: &rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(res.NewMeta.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules in update result: %s", cmp.Diff(rules, res.NewMeta.NotificationRules))
+	}
+	if meta, err = s.FindTaskMetaByID(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(meta.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules after update: %s", cmp.Diff(rules, meta.NotificationRules))
+	}
+
+	// An empty list of rules removes all rules.
+	rules = []*backend.StoreTaskNotificationRule{}
+	if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: id, NotificationRules: &rules}); err != nil {
+		t.Fatal(err)
+	}
+	if meta, err = s.FindTaskMetaByID(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.NotificationRules) != 0 {
+		t.Fatalf("expected notification rules to be removed, got %v", meta.NotificationRules)
+	}
+}
+
+func testStoreRevisions(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+	const script2 = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-2h)`
+
+	s := create(t)
+	defer destroy(t, s)
+
+	id, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, AuthorizationID: 3, Script: script, ScheduleAfter: 60, UserID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := s.ListTaskRevisions(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 1 || revs[0].Revision != 1 || revs[0].Script != script || revs[0].UpdatedBy != 7 || revs[0].UpdatedAt == 0 {
+		t.Fatalf("unexpected revisions of created task: %+v", revs)
+	}
+
+	// Updating the task without changing its script doesn't create a revision.
+	if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: id, Status: backend.TaskInactive, UserID: 8}); err != nil {
+		t.Fatal(err)
+	}
+	if revs, err = s.ListTaskRevisions(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 1 {
+		t.Fatalf("expected 1 revision after status update, got %d", len(revs))
+	}
+
+	res, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: id, Script: script2, Status: backend.TaskActive, UserID: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NewMeta.Revision != 2 {
+		t.Fatalf("expected revision 2 after script update, got %d", res.NewMeta.Revision)
+	}
+	rev, err := s.FindTaskRevision(context.Background(), id, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev.Script != script2 || rev.UpdatedBy != 8 {
+		t.Fatalf("unexpected revision 2: %+v", rev)
+	}
+
+	// Runs record the revision they execute.
+	rc, err := s.CreateNextRun(context.Background(), id, 120)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Created.Revision != 2 {
+		t.Fatalf("expected run of revision 2, got %d", rc.Created.Revision)
+	}
+	meta, err := s.FindTaskMetaByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Revision != 2 || len(meta.CurrentlyRunning) != 1 || meta.CurrentlyRunning[0].Revision != 2 {
+		t.Fatalf("unexpected revisions in meta: %+v", meta)
+	}
+
+	// Updating the options changes the script too.
+	if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: id, Options: options.Options{Cron: "0 * * * *"}}); err != nil {
+		t.Fatal(err)
+	}
+	if revs, err = s.ListTaskRevisions(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 3 {
+		t.Fatalf("expected 3 revisions after options update, got %d", len(revs))
+	}
+	for i, rev := range revs {
+		if rev.Revision != int64(i+1) {
+			t.Fatalf("expected revisions in order, got revision %d at index %d", rev.Revision, i)
+		}
+	}
+
+	if rev, err = s.FindTaskRevision(context.Background(), id, 1); err != nil {
+		t.Fatal(err)
+	}
+	if rev.Script != script {
+		t.Fatalf("unexpected script of revision 1: %s", rev.Script)
+	}
+	if _, err := s.FindTaskRevision(context.Background(), id, 4); err != backend.ErrRevisionNotFound {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+
+	if _, err := s.DeleteTask(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ListTaskRevisions(context.Background(), id); err != backend.ErrTaskNotFound {
+		t.Fatalf("expected ErrTaskNotFound after deleting the task, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"context"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// WrittenPoints describes the points of a measurement written to a bucket by a single write.
+type WrittenPoints struct {
+	OrgID, BucketID platform.ID
+	Measurement     string
+
+	// Unix timestamps in nanoseconds of the earliest and latest written points.
+	Start, Stop int64
+}
+
+// WriteObserver is notified about the points written to buckets, such as to trigger runs of tasks.
+type WriteObserver interface {
+	// PointsWritten is called after points were successfully written.
+	// It must not block, as it is called on the write path.
+	PointsWritten(ctx context.Context, ws []WrittenPoints)
+}
+
+// ObservedPointsWriter is a PointsWriter which notifies a WriteObserver about the points written by its underlying PointsWriter.
+type ObservedPointsWriter struct {
+	pointsWriter PointsWriter
+
+	mu       sync.RWMutex
+	observer WriteObserver
+}
+
+// NewObservedPointsWriter returns an ObservedPointsWriter which writes points with pw, and notifies o about them.
+// The points are expected to have been exploded by tsdb.ExplodePoints, as the storage engine expects them.
+// o may be nil until it is set with SetObserver, such as when the observer depends on a query controller
+// which writes with the ObservedPointsWriter.
+func NewObservedPointsWriter(pw PointsWriter, o WriteObserver) *ObservedPointsWriter {
+	return &ObservedPointsWriter{pointsWriter: pw, observer: o}
+}
+
+// SetObserver sets the observer notified about the points written from now on.
+func (w *ObservedPointsWriter) SetObserver(o WriteObserver) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.observer = o
+}
+
+// WritePoints writes points with the underlying PointsWriter, and notifies the observer about the points written.
+// If only some of the points were written, as reported by a tsdb.PartialWriteError, the observer is notified
+// about those, and the error is returned.
+func (w *ObservedPointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	err := w.pointsWriter.WritePoints(ctx, points)
+	if pwe, ok := err.(tsdb.PartialWriteError); ok {
+		points = withoutDroppedPoints(points, pwe.DroppedKeys)
+	} else if err != nil {
+		return err
+	}
+
+	w.mu.RLock()
+	o := w.observer
+	w.mu.RUnlock()
+
+	if o != nil {
+		if ws := summarizeWrittenPoints(points); len(ws) > 0 {
+			o.PointsWritten(ctx, ws)
+		}
+	}
+	return err
+}
+
+// withoutDroppedPoints returns the points whose keys are not among the dropped keys.
+func withoutDroppedPoints(points []models.Point, droppedKeys [][]byte) []models.Point {
+	if len(droppedKeys) == 0 {
+		return points
+	}
+	dropped := make(map[string]struct{}, len(droppedKeys))
+	for _, k := range droppedKeys {
+		dropped[string(k)] = struct{}{}
+	}
+
+	written := make([]models.Point, 0, len(points))
+	for _, p := range points {
+		if _, ok := dropped[string(p.Key())]; !ok {
+			written = append(written, p)
+		}
+	}
+	return written
+}
+
+// summarizeWrittenPoints returns the time range of the written points of each measurement of each bucket.
+// Points whose name doesn't identify an organization and a bucket are skipped.
+func summarizeWrittenPoints(points []models.Point) []WrittenPoints {
+	type key struct {
+		name        [16]byte
+		measurement string
+	}
+	index := make(map[key]int)
+
+	var ws []WrittenPoints
+	for _, p := range points {
+		name := p.Name()
+		var k key
+		if len(name) != len(k.name) {
+			continue
+		}
+		copy(k.name[:], name)
+		k.measurement = string(p.Tags().Get(tsdb.MeasurementTagKeyBytes))
+
+		t := p.UnixNano()
+		i, ok := index[k]
+		if !ok {
+			org, bucket := tsdb.DecodeName(k.name)
+			index[k] = len(ws)
+			ws = append(ws, WrittenPoints{OrgID: org, BucketID: bucket, Measurement: k.measurement, Start: t, Stop: t})
+			continue
+		}
+		if t < ws[i].Start {
+			ws[i].Start = t
+		}
+		if t > ws[i].Stop {
+			ws[i].Stop = t
+		}
+	}
+	return ws
+}
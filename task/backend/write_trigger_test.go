@@ -0,0 +1,65 @@
+package backend_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+type writeObserverFunc func(ctx context.Context, ws []backend.WrittenPoints)
+
+func (f writeObserverFunc) PointsWritten(ctx context.Context, ws []backend.WrittenPoints) {
+	f(ctx, ws)
+}
+
+func TestObservedPointsWriter_WritePoints(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	points, err := models.ParsePointsString("cpu value=1 10\ncpu value=2 30\nmem value=1 20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points, err = tsdb.ExplodePoints(orgID, bucketID, points); err != nil {
+		t.Fatal(err)
+	}
+
+	pw := &mock.PointsWriter{}
+	w := backend.NewObservedPointsWriter(pw, nil)
+
+	// Points written before the observer is set are not observed.
+	if err := w.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []backend.WrittenPoints
+	w.SetObserver(writeObserverFunc(func(_ context.Context, ws []backend.WrittenPoints) {
+		got = append(got, ws...)
+	}))
+
+	// Only the points which were written are observed when some are dropped.
+	pwe := tsdb.PartialWriteError{Reason: "dropped", Dropped: 1, DroppedKeys: [][]byte{points[2].Key()}}
+	pw.ForceError(pwe)
+	if err := w.WritePoints(context.Background(), points); !reflect.DeepEqual(err, pwe) {
+		t.Fatalf("expected partial write error, got %v", err)
+	}
+
+	want := []backend.WrittenPoints{{OrgID: orgID, BucketID: bucketID, Measurement: "cpu", Start: 10, Stop: 30}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected written points: got %+v, want %+v", got, want)
+	}
+
+	// Nothing is observed when the write fails.
+	got = nil
+	pw.ForceError(&platform.Error{Code: platform.EInternal, Msg: "engine closed"})
+	if err := w.WritePoints(context.Background(), points); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no written points to be observed, got %+v", got)
+	}
+}
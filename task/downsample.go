@@ -0,0 +1,344 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/task/backend"
+)
+
+// DownsampleBucketService wraps a platform.BucketService, and materializes the
+// downsample rules of buckets as tasks owned by the buckets.
+//
+// A task is created with the authorization of the caller for each new rule,
+// and deleted when its rule is removed or its bucket is deleted. The TaskID of
+// the rules provided by callers is ignored.
+type DownsampleBucketService struct {
+	platform.BucketService
+	tasks platform.TaskService
+}
+
+// NewDownsampleBucketService returns a new DownsampleBucketService which
+// manages the tasks of downsample rules with ts.
+func NewDownsampleBucketService(bs platform.BucketService, ts platform.TaskService) *DownsampleBucketService {
+	return &DownsampleBucketService{
+		BucketService: bs,
+		tasks:         ts,
+	}
+}
+
+// CreateBucket creates a new bucket and the tasks of its downsample rules.
+func (s *DownsampleBucketService) CreateBucket(ctx context.Context, b *platform.Bucket) error {
+	rules := b.DownsampleRules
+	if len(rules) == 0 {
+		return s.BucketService.CreateBucket(ctx, b)
+	}
+
+	// The bucket is created first, so that its ID can be used by the tasks.
+	b.DownsampleRules = clearDownsampleTasks(rules)
+	if err := s.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+
+	rules, err := s.createDownsampleTasks(ctx, b, b.DownsampleRules)
+	if err == nil {
+		var nb *platform.Bucket
+		if nb, err = s.BucketService.UpdateBucket(ctx, b.ID, platform.BucketUpdate{DownsampleRules: &rules}); err == nil {
+			*b = *nb
+			return nil
+		}
+		s.deleteDownsampleTasks(ctx, rules)
+	}
+
+	if derr := s.BucketService.DeleteBucket(ctx, b.ID); derr != nil {
+		err = fmt.Errorf("%s: failed to clean up bucket: %s", err.Error(), derr.Error())
+	}
+	return err
+}
+
+// UpdateBucket updates a single bucket with changeset. If the changeset
+// replaces the downsample rules of the bucket, the tasks of the rules which
+// roll up data the same way as an existing rule are kept, the tasks of new
+// rules are created and the tasks of removed rules are deleted.
+func (s *DownsampleBucketService) UpdateBucket(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+	if upd.DownsampleRules == nil {
+		return s.BucketService.UpdateBucket(ctx, id, upd)
+	}
+
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := clearDownsampleTasks(*upd.DownsampleRules)
+	if err := platform.ValidDownsampleRules(id, rules); err != nil {
+		return nil, err
+	}
+
+	var created, removed []platform.DownsampleRule
+	for _, old := range b.DownsampleRules {
+		kept := false
+		for i := range rules {
+			if rules[i].SameRollup(old) && !rules[i].TaskID.Valid() {
+				rules[i].TaskID = old.TaskID
+				kept = true
+				break
+			}
+		}
+		if !kept {
+			removed = append(removed, old)
+		}
+	}
+	for i, r := range rules {
+		if r.TaskID.Valid() {
+			continue
+		}
+		nr, err := s.createDownsampleTasks(ctx, b, []platform.DownsampleRule{r})
+		if err != nil {
+			s.deleteDownsampleTasks(ctx, created)
+			return nil, err
+		}
+		rules[i] = nr[0]
+		created = append(created, nr[0])
+	}
+
+	upd.DownsampleRules = &rules
+	nb, err := s.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		s.deleteDownsampleTasks(ctx, created)
+		return nil, err
+	}
+
+	if err := s.deleteDownsampleTasks(ctx, removed); err != nil {
+		return nil, err
+	}
+	return nb, nil
+}
+
+// DeleteBucket removes a bucket by ID and the tasks of its downsample rules.
+func (s *DownsampleBucketService) DeleteBucket(ctx context.Context, id platform.ID) error {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.BucketService.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+	return s.deleteDownsampleTasks(ctx, b.DownsampleRules)
+}
+
+// createDownsampleTasks creates the tasks of the rules of b, and returns the
+// rules with their TaskID set. If any task cannot be created, the tasks which
+// were created are deleted.
+func (s *DownsampleBucketService) createDownsampleTasks(ctx context.Context, b *platform.Bucket, rules []platform.DownsampleRule) ([]platform.DownsampleRule, error) {
+	created := make([]platform.DownsampleRule, 0, len(rules))
+	for _, r := range rules {
+		dest, err := s.findDestination(ctx, b, r)
+		if err != nil {
+			s.deleteDownsampleTasks(ctx, created)
+			return nil, err
+		}
+
+		t, err := s.tasks.CreateTask(ctx, platform.TaskCreate{
+			Flux:           downsampleScript(b, r, dest),
+			OrganizationID: b.OrganizationID,
+		})
+		if err != nil {
+			s.deleteDownsampleTasks(ctx, created)
+			return nil, &platform.Error{
+				Msg: fmt.Sprintf("failed to create the task of downsample rule: %s of %s windows to bucket %s", r.Function, r.Every, r.DestinationBucketID),
+				Err: err,
+			}
+		}
+		r.TaskID = t.ID
+		created = append(created, r)
+	}
+	return created, nil
+}
+
+// findDestination returns the destination bucket of the rule r of b. It must be
+// in the organization of b, and the authorizer of ctx must be allowed to write to
+// it, as the task of the rule is created with the authorization of the caller.
+func (s *DownsampleBucketService) findDestination(ctx context.Context, b *platform.Bucket, r platform.DownsampleRule) (*platform.Bucket, error) {
+	dest, err := s.BucketService.FindBucketByID(ctx, r.DestinationBucketID)
+	if platform.ErrorCode(err) == platform.ENotFound {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("destination bucket %s of downsample rule not found", r.DestinationBucketID),
+			Err:  err,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if dest.OrganizationID != b.OrganizationID {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("destination bucket %s of downsample rule must belong to the organization of the bucket", dest.ID),
+		}
+	}
+
+	p, err := platform.NewPermissionAtID(dest.ID, platform.WriteAction, platform.BucketsResourceType, dest.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	a, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !a.Allowed(*p) {
+		return nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions to write to destination bucket %s of downsample rule", dest.ID),
+		}
+	}
+	return dest, nil
+}
+
+// deleteDownsampleTasks deletes the tasks of rules. It attempts to delete every
+// task, and returns the first error encountered.
+func (s *DownsampleBucketService) deleteDownsampleTasks(ctx context.Context, rules []platform.DownsampleRule) error {
+	var err error
+	for _, r := range rules {
+		if !r.TaskID.Valid() {
+			continue
+		}
+		if derr := s.tasks.DeleteTask(ctx, r.TaskID); derr != nil && err == nil {
+			err = derr
+		}
+	}
+	return err
+}
+
+// clearDownsampleTasks returns a copy of rules without their TaskID.
+func clearDownsampleTasks(rules []platform.DownsampleRule) []platform.DownsampleRule {
+	cleared := make([]platform.DownsampleRule, len(rules))
+	for i, r := range rules {
+		r.TaskID = 0
+		cleared[i] = r
+	}
+	return cleared
+}
+
+// downsampleScript returns the Flux script of the task of the rule r of b, which
+// writes to dest. Each run aggregates the window which ends at the time the run
+// is scheduled for.
+func downsampleScript(b *platform.Bucket, r platform.DownsampleRule, dest *platform.Bucket) string {
+	every := fluxDuration(r.Every)
+	name := fmt.Sprintf("downsample %s: %s of %s windows to %s", b.ID, r.Function, every, r.DestinationBucketID)
+	return fmt.Sprintf(`option task = {name: %q, every: %s}
+
+from(bucketID: %q)
+	|> range(start: -%s)
+	|> aggregateWindow(every: %s, fn: %s)
+	|> to(bucketID: %q, orgID: %q)
+`, name, every, b.ID, every, every, r.Function, dest.ID, dest.OrganizationID)
+}
+
+// fluxDuration formats d as a Flux duration literal.
+func fluxDuration(d time.Duration) string {
+	s := d.String()
+	if strings.Contains(s, ".") {
+		return fmt.Sprintf("%dns", d)
+	}
+	return s
+}
+
+// DownsampleWatermarker reports how much of the raw data of buckets has been
+// rolled up by the tasks of their downsample rules.
+type DownsampleWatermarker struct {
+	tasks platform.TaskService
+	auths platform.AuthorizationService
+}
+
+// NewDownsampleWatermarker returns a new DownsampleWatermarker. The runs of a
+// task are read with the authorization of the task, so ts must not require an
+// authorizer on the context.
+func NewDownsampleWatermarker(ts platform.TaskService, as platform.AuthorizationService) *DownsampleWatermarker {
+	return &DownsampleWatermarker{
+		tasks: ts,
+		auths: as,
+	}
+}
+
+// DownsampleWatermark returns the time before which the raw data of b has
+// been rolled up successfully by the tasks of all of its downsample rules.
+// It returns the zero time if the tasks have not rolled up any data.
+func (w *DownsampleWatermarker) DownsampleWatermark(ctx context.Context, b *platform.Bucket) (time.Time, error) {
+	var watermark time.Time
+	for i, r := range b.DownsampleRules {
+		if !r.TaskID.Valid() {
+			return time.Time{}, nil
+		}
+
+		wm, err := w.taskWatermark(ctx, r)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if i == 0 || wm.Before(watermark) {
+			watermark = wm
+		}
+	}
+	return watermark, nil
+}
+
+// taskWatermark returns the time before which the task of r has rolled up all
+// windows successfully.
+//
+// The latest completed run of the task is its latest scheduled run which
+// finished, successfully or not, and each run rolls up the window before the
+// time it is scheduled for. A window is not rolled up if its latest run
+// failed, or was canceled, and has not been retried successfully. Only the
+// runs reported by the task service are considered, which are its recent runs.
+func (w *DownsampleWatermarker) taskWatermark(ctx context.Context, r platform.DownsampleRule) (time.Time, error) {
+	t, err := w.tasks.FindTaskByID(ctx, r.TaskID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t.LatestCompleted == "" {
+		return time.Time{}, nil
+	}
+	watermark, err := time.Parse(time.RFC3339, t.LatestCompleted)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	auth, err := w.auths.FindAuthorizationByID(ctx, t.AuthorizationID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+
+	runs, _, err := w.tasks.FindRuns(ctx, platform.RunFilter{Task: t.ID})
+	if err != nil && err != backend.ErrNoRunsFound {
+		return time.Time{}, err
+	}
+
+	succeeded := make(map[string]bool, len(runs))
+	for _, run := range runs {
+		if run.Status == backend.RunSuccess.String() {
+			succeeded[run.ScheduledFor] = true
+		}
+	}
+	for _, run := range runs {
+		if run.Status != backend.RunFail.String() && run.Status != backend.RunCanceled.String() {
+			continue
+		}
+		if succeeded[run.ScheduledFor] {
+			continue
+		}
+		scheduledFor, err := time.Parse(time.RFC3339, run.ScheduledFor)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if start := scheduledFor.Add(-r.Every); start.Before(watermark) {
+			watermark = start
+		}
+	}
+	return watermark, nil
+}
@@ -0,0 +1,311 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task"
+)
+
+// downsampleServices returns a bucket service and a task service which keep
+// their buckets and tasks in the provided maps.
+func downsampleServices(buckets map[influxdb.ID]*influxdb.Bucket, tasks map[influxdb.ID]*influxdb.Task) (*mock.BucketService, *mock.TaskService) {
+	var nextID influxdb.ID = 100
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		b, ok := buckets[id]
+		if !ok {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+		}
+		cp := *b
+		return &cp, nil
+	}
+	bs.CreateBucketFn = func(ctx context.Context, b *influxdb.Bucket) error {
+		nextID++
+		b.ID = nextID
+		cp := *b
+		buckets[b.ID] = &cp
+		return nil
+	}
+	bs.UpdateBucketFn = func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+		b, ok := buckets[id]
+		if !ok {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+		}
+		if upd.DownsampleRules != nil {
+			b.DownsampleRules = *upd.DownsampleRules
+		}
+		cp := *b
+		return &cp, nil
+	}
+	bs.DeleteBucketFn = func(ctx context.Context, id influxdb.ID) error {
+		delete(buckets, id)
+		return nil
+	}
+
+	ts := &mock.TaskService{
+		CreateTaskFn: func(ctx context.Context, tc influxdb.TaskCreate) (*influxdb.Task, error) {
+			nextID++
+			t := &influxdb.Task{ID: nextID, OrganizationID: tc.OrganizationID, Flux: tc.Flux}
+			tasks[t.ID] = t
+			return t, nil
+		},
+		DeleteTaskFn: func(ctx context.Context, id influxdb.ID) error {
+			if _, ok := tasks[id]; !ok {
+				return fmt.Errorf("task %s not found", id)
+			}
+			delete(tasks, id)
+			return nil
+		},
+	}
+	return bs, ts
+}
+
+func taskIDs(tasks map[influxdb.ID]*influxdb.Task) []influxdb.ID {
+	ids := make([]influxdb.ID, 0, len(tasks))
+	for id := range tasks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// writeBucketsAuthorizer returns a context with an authorizer allowed to write to
+// the buckets of the organization with the given ID.
+func writeBucketsAuthorizer(t *testing.T, orgID influxdb.ID) context.Context {
+	t.Helper()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.BucketsResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{
+		Status:      influxdb.Active,
+		Permissions: []influxdb.Permission{*p},
+	})
+}
+
+func TestDownsampleBucketService(t *testing.T) {
+	const orgID, dest1, dest2 = influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	ctx := writeBucketsAuthorizer(t, orgID)
+	buckets := map[influxdb.ID]*influxdb.Bucket{
+		dest1: {ID: dest1, OrganizationID: orgID, Name: "5m"},
+		dest2: {ID: dest2, OrganizationID: orgID, Name: "1h"},
+	}
+	tasks := map[influxdb.ID]*influxdb.Task{}
+	bs, ts := downsampleServices(buckets, tasks)
+	s := task.NewDownsampleBucketService(bs, ts)
+
+	b := &influxdb.Bucket{
+		OrganizationID: orgID,
+		Name:           "raw",
+		DownsampleRules: []influxdb.DownsampleRule{
+			{Every: 5 * time.Minute, Function: influxdb.DownsampleFunctionMean, DestinationBucketID: dest1, TaskID: 42},
+			{Every: time.Hour, Function: influxdb.DownsampleFunctionMax, DestinationBucketID: dest2},
+		},
+	}
+	if err := s.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := taskIDs(tasks), []influxdb.ID{102, 103}; !cmp.Equal(got, exp) {
+		t.Fatalf("got tasks %v, exp %v", got, exp)
+	}
+	if got, exp := []influxdb.ID{b.DownsampleRules[0].TaskID, b.DownsampleRules[1].TaskID}, []influxdb.ID{102, 103}; !cmp.Equal(got, exp) {
+		t.Fatalf("got rule tasks %v, exp %v", got, exp)
+	}
+	if got := buckets[b.ID].DownsampleRules; !cmp.Equal(got, b.DownsampleRules) {
+		t.Fatalf("got stored rules %v, exp %v", got, b.DownsampleRules)
+	}
+
+	script := tasks[102].Flux
+	for _, exp := range []string{
+		fmt.Sprintf(`from(bucketID: %q)`, b.ID),
+		`every: 5m0s`,
+		`aggregateWindow(every: 5m0s, fn: mean)`,
+		fmt.Sprintf(`to(bucketID: %q, orgID: %q)`, dest1, orgID),
+	} {
+		if !strings.Contains(script, exp) {
+			t.Fatalf("script %q does not contain %q", script, exp)
+		}
+	}
+
+	// Keep the first rollup with another source retention, replace the second.
+	rules := []influxdb.DownsampleRule{
+		{SourceRetention: 24 * time.Hour, Every: 5 * time.Minute, Function: influxdb.DownsampleFunctionMean, DestinationBucketID: dest1},
+		{Every: time.Hour, Function: influxdb.DownsampleFunctionMin, DestinationBucketID: dest2},
+	}
+	nb, err := s.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{DownsampleRules: &rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := taskIDs(tasks), []influxdb.ID{102, 104}; !cmp.Equal(got, exp) {
+		t.Fatalf("got tasks %v, exp %v", got, exp)
+	}
+	if got, exp := nb.DownsampleRules[0].SourceRetention, 24*time.Hour; got != exp {
+		t.Fatalf("got source retention %v, exp %v", got, exp)
+	}
+	if got, exp := []influxdb.ID{nb.DownsampleRules[0].TaskID, nb.DownsampleRules[1].TaskID}, []influxdb.ID{102, 104}; !cmp.Equal(got, exp) {
+		t.Fatalf("got rule tasks %v, exp %v", got, exp)
+	}
+
+	// Invalid rules do not change the bucket or its tasks.
+	invalid := append(rules, rules[0])
+	if _, err := s.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{DownsampleRules: &invalid}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("got error %v, exp conflict", err)
+	}
+	if got, exp := taskIDs(tasks), []influxdb.ID{102, 104}; !cmp.Equal(got, exp) {
+		t.Fatalf("got tasks %v, exp %v", got, exp)
+	}
+
+	if err := s.DeleteBucket(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("got tasks %v after deleting bucket, exp none", taskIDs(tasks))
+	}
+}
+
+func TestDownsampleBucketService_Destination(t *testing.T) {
+	const orgID, otherOrgID, dest, otherDest = influxdb.ID(1), influxdb.ID(2), influxdb.ID(3), influxdb.ID(4)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		dest     influxdb.ID
+		wantCode string
+	}{
+		{
+			name: "destination in the organization of the bucket",
+			ctx:  writeBucketsAuthorizer(t, orgID),
+			dest: dest,
+		},
+		{
+			name:     "destination not found",
+			ctx:      writeBucketsAuthorizer(t, orgID),
+			dest:     influxdb.ID(5),
+			wantCode: influxdb.EInvalid,
+		},
+		{
+			name:     "destination in another organization",
+			ctx:      writeBucketsAuthorizer(t, otherOrgID),
+			dest:     otherDest,
+			wantCode: influxdb.EInvalid,
+		},
+		{
+			name:     "destination not writable",
+			ctx:      writeBucketsAuthorizer(t, otherOrgID),
+			dest:     dest,
+			wantCode: influxdb.EForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := map[influxdb.ID]*influxdb.Bucket{
+				dest:      {ID: dest, OrganizationID: orgID, Name: "dest"},
+				otherDest: {ID: otherDest, OrganizationID: otherOrgID, Name: "dest"},
+			}
+			tasks := map[influxdb.ID]*influxdb.Task{}
+			bs, ts := downsampleServices(buckets, tasks)
+			s := task.NewDownsampleBucketService(bs, ts)
+
+			b := &influxdb.Bucket{
+				OrganizationID: orgID,
+				Name:           "raw",
+				DownsampleRules: []influxdb.DownsampleRule{
+					{Every: time.Hour, Function: influxdb.DownsampleFunctionMean, DestinationBucketID: tt.dest},
+				},
+			}
+			err := s.CreateBucket(tt.ctx, b)
+			if code := influxdb.ErrorCode(err); code != tt.wantCode {
+				t.Fatalf("got error %v, exp code %q", err, tt.wantCode)
+			}
+			if tt.wantCode != "" {
+				if len(tasks) != 0 || len(buckets) != 2 {
+					t.Fatalf("got tasks %v and %d buckets after a failed create, exp none", taskIDs(tasks), len(buckets))
+				}
+				return
+			}
+
+			for _, tk := range tasks {
+				if exp := fmt.Sprintf(`to(bucketID: %q, orgID: %q)`, dest, orgID); !strings.Contains(tk.Flux, exp) {
+					t.Fatalf("script %q does not contain %q", tk.Flux, exp)
+				}
+			}
+		})
+	}
+}
+
+func TestDownsampleWatermarker(t *testing.T) {
+	const every = time.Hour
+	latest := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	run := func(hour int, status string) *influxdb.Run {
+		return &influxdb.Run{
+			ScheduledFor: time.Date(2019, 3, 1, hour, 0, 0, 0, time.UTC).Format(time.RFC3339),
+			Status:       status,
+		}
+	}
+
+	tests := []struct {
+		name string
+		runs []*influxdb.Run
+		exp  time.Time
+	}{
+		{
+			name: "all runs succeeded",
+			runs: []*influxdb.Run{run(11, "success"), run(12, "success")},
+			exp:  latest,
+		},
+		{
+			name: "failed run",
+			runs: []*influxdb.Run{run(10, "success"), run(11, "failed"), run(12, "success")},
+			exp:  latest.Add(-2 * every),
+		},
+		{
+			name: "failed run retried",
+			runs: []*influxdb.Run{run(11, "failed"), run(11, "success"), run(12, "canceled")},
+			exp:  latest.Add(-every),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := &mock.TaskService{
+				FindTaskByIDFn: func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+					return &influxdb.Task{ID: id, AuthorizationID: 7, LatestCompleted: latest.Format(time.RFC3339)}, nil
+				},
+				FindRunsFn: func(ctx context.Context, f influxdb.RunFilter) ([]*influxdb.Run, int, error) {
+					return tt.runs, len(tt.runs), nil
+				},
+			}
+			as := mock.NewAuthorizationService()
+			as.FindAuthorizationByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Authorization, error) {
+				return &influxdb.Authorization{ID: id}, nil
+			}
+
+			b := &influxdb.Bucket{
+				DownsampleRules: []influxdb.DownsampleRule{
+					{Every: every, Function: influxdb.DownsampleFunctionMean, DestinationBucketID: 2, TaskID: 10},
+				},
+			}
+			got, err := task.NewDownsampleWatermarker(ts, as).DownsampleWatermark(context.Background(), b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.exp) {
+				t.Fatalf("got watermark %v, exp %v", got, tt.exp)
+			}
+		})
+	}
+}
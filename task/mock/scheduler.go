@@ -107,6 +107,18 @@ func (s *Scheduler) UpdateTask(task *backend.StoreTask, meta *backend.StoreTaskM
 	return nil
 }
 
+func (s *Scheduler) UpdateQueue(taskID platform.ID, meta *backend.StoreTaskMeta) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.claims[taskID.String()]; !ok {
+		return backend.ErrTaskNotClaimed
+	}
+
+	s.meta[taskID.String()] = *meta
+	return nil
+}
+
 func (s *Scheduler) ReleaseTask(taskID platform.ID) error {
 	if s.releaseError != nil {
 		return s.releaseError
@@ -135,6 +147,18 @@ func (s *Scheduler) TaskFor(id platform.ID) *Task {
 	return s.claims[id.String()]
 }
 
+// TaskMetaFor returns a copy of the meta the task with the given ID was last claimed or updated with,
+// or nil if the task is not claimed.
+func (s *Scheduler) TaskMetaFor(id platform.ID) *backend.StoreTaskMeta {
+	s.Lock()
+	defer s.Unlock()
+	meta, ok := s.meta[id.String()]
+	if !ok {
+		return nil
+	}
+	return &meta
+}
+
 func (s *Scheduler) TaskCreateChan() <-chan *Task {
 	s.createChan = make(chan *Task, 10)
 	return s.createChan
@@ -249,6 +273,76 @@ func (d *DesiredState) FinishRun(_ context.Context, taskID, runID platform.ID) e
 	return nil
 }
 
+// RetryRun replaces the given run with a new run for the same time.
+func (d *DesiredState) RetryRun(_ context.Context, taskID, runID platform.ID) (backend.QueuedRun, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tid := taskID.String()
+	m := d.meta[tid]
+	qr, err := m.RetryRun(runID, func() (platform.ID, error) {
+		d.runIDs[tid]++
+		return platform.ID(d.runIDs[tid]), nil
+	})
+	if err != nil {
+		return backend.QueuedRun{}, err
+	}
+	qr.TaskID = taskID
+
+	d.meta[tid] = m
+	delete(d.created, tid+runID.String())
+	d.created[tid+qr.RunID.String()] = qr
+	d.totalRunsCreated[taskID]++
+	return qr, nil
+}
+
+// QueueDependentRuns requests a run for now of each task whose meta depends on the given task, regardless of its status.
+func (d *DesiredState) QueueDependentRuns(_ context.Context, taskID platform.ID, now int64) ([]platform.ID, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var ids []platform.ID
+	for tid, m := range d.meta {
+		if !m.DependsOnTask(taskID) {
+			continue
+		}
+
+		queued, err := m.QueueDependentRun(now, time.Now().Unix())
+		if err != nil {
+			return ids, err
+		}
+		if !queued {
+			continue
+		}
+		d.meta[tid] = m
+
+		id, err := platform.IDFromString(tid)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, *id)
+	}
+	return ids, nil
+}
+
+// QueueTriggeredRun requests a run for now of the given task, after points were written to the bucket of its trigger.
+func (d *DesiredState) QueueTriggeredRun(_ context.Context, taskID platform.ID, now, start, stop int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tid := taskID.String()
+	m, ok := d.meta[tid]
+	if !ok {
+		return errors.New("task not found")
+	}
+
+	if err := m.QueueTriggeredRun(now, start, stop, time.Now().Unix()); err != nil {
+		return err
+	}
+	d.meta[tid] = m
+	return nil
+}
+
 func (d *DesiredState) CreatedFor(taskID platform.ID) []backend.QueuedRun {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -2,6 +2,7 @@
 package options
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -9,7 +10,11 @@ import (
 	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/ast/edit"
+	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	cron "gopkg.in/robfig/cron.v2"
 )
 
@@ -28,6 +33,22 @@ func EnableScriptCacheForTest() {
 const maxConcurrency = 100
 const maxRetry = 10
 
+// idLen is the length in bytes of a decoded task or bucket ID.
+const idLen = 8
+
+// DefaultTriggerMinInterval is the minimum time between two triggered runs of a task, if its trigger doesn't set one.
+const DefaultTriggerMinInterval = time.Second
+
+// TriggerOption is the name of the option which a script of a task with a trigger may declare,
+// to receive the time range of the points written by the writes which triggered a run.
+// The option is an object with start and stop times, e.g.:
+//
+//	option trigger = {start: -1h, stop: now()}
+//
+// Like the arguments of range, start is inclusive and stop is exclusive, so the range covers all the written points.
+// The declared values are used when the script is not run because of writes, such as when it is run manually.
+const TriggerOption = "trigger"
+
 // Options are the task-related options that can be specified in a Flux script.
 type Options struct {
 	// Name is a non optional name designator for each task.
@@ -47,6 +68,28 @@ type Options struct {
 	Concurrency int64 `json:"concurrency,omitempty"`
 
 	Retry int64 `json:"retry,omitempty"`
+
+	// DependsOn holds the IDs of upstream tasks.
+	// A task with dependencies is not run on its own schedule,
+	// but when a run of one of its upstream tasks succeeds for a time on the task's schedule.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Trigger describes the writes which trigger runs of the task.
+	// A task with a trigger is not run on a schedule, but soon after points are written to the trigger's bucket.
+	Trigger *Trigger `json:"trigger,omitempty"`
+}
+
+// Trigger describes the writes which trigger runs of a task.
+type Trigger struct {
+	// BucketID is the ID of the bucket whose writes trigger runs.
+	BucketID string `json:"bucketID"`
+
+	// Measurement restricts the writes which trigger runs to those to a measurement, if set.
+	Measurement string `json:"measurement,omitempty"`
+
+	// MinInterval is the minimum time between two triggered runs.
+	// The writes during that time trigger a single run, which covers the time range of all of their points.
+	MinInterval time.Duration `json:"minInterval,omitempty"`
 }
 
 // Clear clears out all options in the options struct, it us useful if you wish to reuse it.
@@ -57,6 +100,8 @@ func (o *Options) Clear() {
 	o.Offset = 0
 	o.Concurrency = 0
 	o.Retry = 0
+	o.DependsOn = nil
+	o.Trigger = nil
 }
 
 func (o *Options) IsZero() bool {
@@ -65,7 +110,9 @@ func (o *Options) IsZero() bool {
 		o.Every == 0 &&
 		o.Offset == 0 &&
 		o.Concurrency == 0 &&
-		o.Retry == 0
+		o.Retry == 0 &&
+		len(o.DependsOn) == 0 &&
+		o.Trigger == nil
 }
 
 // FromScript extracts Options from a Flux script.
@@ -108,8 +155,9 @@ func FromScript(script string) (Options, error) {
 		return opt, errors.New("cannot use both cron and every in task options")
 	}
 
-	if !cronOK && !everyOK {
-		return opt, errors.New("cron or every is required")
+	triggerVal, triggerOK := optObject.Get("trigger")
+	if !cronOK && !everyOK && !triggerOK {
+		return opt, errors.New("cron, every or trigger is required")
 	}
 
 	if cronOK {
@@ -147,6 +195,36 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = retryVal.Int()
 	}
 
+	if dependsOnVal, ok := optObject.Get("dependsOn"); ok {
+		if err := checkNature(dependsOnVal.PolyType().Nature(), semantic.Array); err != nil {
+			return opt, err
+		}
+		var err error
+		dependsOnVal.Array().Range(func(_ int, v values.Value) {
+			if err != nil {
+				return
+			}
+			if err = checkNature(v.PolyType().Nature(), semantic.String); err != nil {
+				return
+			}
+			opt.DependsOn = append(opt.DependsOn, v.Str())
+		})
+		if err != nil {
+			return opt, err
+		}
+	}
+
+	if triggerOK {
+		if err := checkNature(triggerVal.PolyType().Nature(), semantic.Object); err != nil {
+			return opt, err
+		}
+		trigger, err := triggerFromObject(triggerVal.Object())
+		if err != nil {
+			return opt, err
+		}
+		opt.Trigger = trigger
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -160,6 +238,36 @@ func FromScript(script string) (Options, error) {
 	return opt, nil
 }
 
+// triggerFromObject extracts a Trigger from the trigger object of the task options.
+func triggerFromObject(obj values.Object) (*Trigger, error) {
+	t := &Trigger{MinInterval: DefaultTriggerMinInterval}
+
+	bucketIDVal, ok := obj.Get("bucketID")
+	if !ok {
+		return nil, errors.New("missing bucketID in task trigger")
+	}
+	if err := checkNature(bucketIDVal.PolyType().Nature(), semantic.String); err != nil {
+		return nil, err
+	}
+	t.BucketID = bucketIDVal.Str()
+
+	if measurementVal, ok := obj.Get("measurement"); ok {
+		if err := checkNature(measurementVal.PolyType().Nature(), semantic.String); err != nil {
+			return nil, err
+		}
+		t.Measurement = measurementVal.Str()
+	}
+
+	if minIntervalVal, ok := obj.Get("minInterval"); ok {
+		if err := checkNature(minIntervalVal.PolyType().Nature(), semantic.Duration); err != nil {
+			return nil, err
+		}
+		t.MinInterval = minIntervalVal.Duration().Duration()
+	}
+
+	return t, nil
+}
+
 // Validate returns an error if the options aren't valid.
 func (o *Options) Validate() error {
 	var errs []string
@@ -169,7 +277,16 @@ func (o *Options) Validate() error {
 
 	cronPresent := o.Cron != ""
 	everyPresent := o.Every != 0
-	if cronPresent == everyPresent {
+	if o.Trigger != nil {
+		// A triggered task has no schedule.
+		if cronPresent || everyPresent {
+			errs = append(errs, "cannot use cron or every with trigger")
+		}
+		if len(o.DependsOn) > 0 {
+			errs = append(errs, "cannot use dependsOn with trigger")
+		}
+		errs = append(errs, o.Trigger.validate()...)
+	} else if cronPresent == everyPresent {
 		// They're both present or both missing.
 		errs = append(errs, "must specify exactly one of either cron or every")
 	} else if cronPresent {
@@ -202,6 +319,16 @@ func (o *Options) Validate() error {
 		errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
 	}
 
+	seen := make(map[string]bool, len(o.DependsOn))
+	for _, id := range o.DependsOn {
+		if b, err := hex.DecodeString(id); err != nil || len(b) != idLen {
+			errs = append(errs, fmt.Sprintf("dependsOn contains invalid task ID %q", id))
+		} else if seen[id] {
+			errs = append(errs, fmt.Sprintf("dependsOn contains task ID %q more than once", id))
+		}
+		seen[id] = true
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -209,10 +336,24 @@ func (o *Options) Validate() error {
 	return fmt.Errorf("invalid options: %s", strings.Join(errs, ", "))
 }
 
+// validate returns the reasons why t isn't valid.
+func (t *Trigger) validate() []string {
+	var errs []string
+	if b, err := hex.DecodeString(t.BucketID); err != nil || len(b) != idLen {
+		errs = append(errs, fmt.Sprintf("trigger contains invalid bucket ID %q", t.BucketID))
+	}
+	if t.MinInterval < time.Second {
+		errs = append(errs, "trigger minInterval must be at least 1 second")
+	} else if t.MinInterval.Truncate(time.Second) != t.MinInterval {
+		errs = append(errs, "trigger minInterval must be expressible as whole seconds")
+	}
+	return errs
+}
+
 // EffectiveCronString returns the effective cron string of the options.
 // If the cron option was specified, it is returned.
 // If the every option was specified, it is converted into a cron string using "@every".
-// Otherwise, the empty string is returned.
+// Otherwise, as for a task with a trigger, the empty string is returned.
 // The value of the offset option is not considered.
 func (o *Options) EffectiveCronString() string {
 	if o.Cron != "" {
@@ -224,6 +365,37 @@ func (o *Options) EffectiveCronString() string {
 	return ""
 }
 
+// SetTriggerRange returns script, with the value of its trigger option replaced by the time range from start to stop.
+// If script doesn't declare the trigger option, it is returned unchanged.
+func SetTriggerRange(script string, start, stop time.Time) (string, error) {
+	pkg := parser.ParseSource(script)
+	if ast.Check(pkg) > 0 {
+		return "", ast.GetError(pkg)
+	}
+	file := pkg.Files[0]
+
+	ok, err := edit.Option(file, TriggerOption, func(opt *ast.OptionStatement) (ast.Expression, error) {
+		a, ok := opt.Assignment.(*ast.VariableAssignment)
+		if !ok {
+			return nil, errors.New("option assignment must be variable assignment")
+		}
+		a.Init = &ast.ObjectExpression{
+			Properties: []*ast.Property{
+				{Key: &ast.Identifier{Name: "start"}, Value: &ast.DateTimeLiteral{Value: start.UTC()}},
+				{Key: &ast.Identifier{Name: "stop"}, Value: &ast.DateTimeLiteral{Value: stop.UTC()}},
+			},
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return script, nil
+	}
+	return ast.Format(file), nil
+}
+
 // checkNature returns a clean error of got and expected dont match.
 func checkNature(got, exp semantic.Nature) error {
 	if got != exp {
@@ -3,6 +3,7 @@ package options_test
 import (
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +32,23 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, opt.Retry)
 	}
+	if len(opt.DependsOn) > 0 {
+		deps := make([]string, len(opt.DependsOn))
+		for i, id := range opt.DependsOn {
+			deps[i] = fmt.Sprintf("%q", id)
+		}
+		taskData = fmt.Sprintf("%s  dependsOn: [%s],\n", taskData, strings.Join(deps, ", "))
+	}
+	if opt.Trigger != nil {
+		trigger := fmt.Sprintf("bucketID: %q", opt.Trigger.BucketID)
+		if opt.Trigger.Measurement != "" {
+			trigger = fmt.Sprintf("%s, measurement: %q", trigger, opt.Trigger.Measurement)
+		}
+		if opt.Trigger.MinInterval != 0 {
+			trigger = fmt.Sprintf("%s, minInterval: %s", trigger, opt.Trigger.MinInterval.String())
+		}
+		taskData = fmt.Sprintf("%s  trigger: {%s},\n", taskData, trigger)
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -52,7 +70,15 @@ func TestFromScript(t *testing.T) {
 		{script: scriptGenerator(options.Options{Name: "name", Cron: "* * * * *", Concurrency: 2, Retry: 3, Offset: -time.Minute}, ""), exp: options.Options{Name: "name", Cron: "* * * * *", Concurrency: 2, Retry: 3, Offset: -time.Minute}},
 		{script: scriptGenerator(options.Options{Name: "name", Every: 5 * time.Second}, ""), exp: options.Options{Name: "name", Every: 5 * time.Second, Concurrency: 1, Retry: 1}},
 		{script: scriptGenerator(options.Options{Name: "name", Cron: "* * * * *"}, ""), exp: options.Options{Name: "name", Cron: "* * * * *", Concurrency: 1, Retry: 1}},
+		{script: scriptGenerator(options.Options{Name: "name", Every: time.Hour, DependsOn: []string{"020f755c3c082000", "020f755c3c082001"}}, ""), exp: options.Options{Name: "name", Every: time.Hour, Concurrency: 1, Retry: 1, DependsOn: []string{"020f755c3c082000", "020f755c3c082001"}}},
+		{script: scriptGenerator(options.Options{Name: "name", Trigger: &options.Trigger{BucketID: "020f755c3c082000", Measurement: "cpu", MinInterval: 30 * time.Second}}, ""), exp: options.Options{Name: "name", Concurrency: 1, Retry: 1, Trigger: &options.Trigger{BucketID: "020f755c3c082000", Measurement: "cpu", MinInterval: 30 * time.Second}}},
+		{script: scriptGenerator(options.Options{Name: "name", Trigger: &options.Trigger{BucketID: "020f755c3c082000"}}, ""), exp: options.Options{Name: "name", Concurrency: 1, Retry: 1, Trigger: &options.Trigger{BucketID: "020f755c3c082000", MinInterval: options.DefaultTriggerMinInterval}}},
 		{script: scriptGenerator(options.Options{Name: "name", Every: time.Hour, Cron: "* * * * *"}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name", Every: time.Hour, Trigger: &options.Trigger{BucketID: "020f755c3c082000"}}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name", Trigger: &options.Trigger{BucketID: "not an ID"}}, ""), shouldErr: true},
+		{script: "option task = {\n  name: \"name\",\n  trigger: {measurement: \"cpu\"},\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name", Every: time.Hour, DependsOn: []string{"not an ID"}}, ""), shouldErr: true},
+		{script: "option task = {\n  name: \"name\",\n  every: 1m0s,\n  dependsOn: \"020f755c3c082000\",\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name", Concurrency: 1000, Every: time.Hour}, ""), shouldErr: true},
 		{script: "option task = {\n  name: \"name\",\n  concurrency: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: "option task = {\n  name: \"name\",\n  concurrency: 1,\n  every: 1,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
@@ -144,6 +170,84 @@ func TestValidate(t *testing.T) {
 	if err := bad.Validate(); err == nil {
 		t.Error("expected error for retry too large")
 	}
+
+	*bad = good
+	bad.DependsOn = []string{"020f755c3c08200"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for invalid upstream task ID")
+	}
+
+	*bad = good
+	bad.DependsOn = []string{"020f755c3c082000", "020f755c3c082000"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for duplicate upstream task ID")
+	}
+
+	triggered := good
+	triggered.Cron = ""
+	triggered.Trigger = &options.Trigger{BucketID: "020f755c3c082000", MinInterval: time.Minute}
+	if err := triggered.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	*bad = triggered
+	bad.Cron = "* * * * *"
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for options with both cron and trigger")
+	}
+
+	*bad = triggered
+	bad.DependsOn = []string{"020f755c3c082000"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for options with both dependsOn and trigger")
+	}
+
+	*bad = triggered
+	bad.Trigger = &options.Trigger{BucketID: "020f755c3c08200", MinInterval: time.Minute}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for invalid trigger bucket ID")
+	}
+
+	*bad = triggered
+	bad.Trigger = &options.Trigger{BucketID: "020f755c3c082000", MinInterval: 1500 * time.Millisecond}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for sub-second trigger min interval resolution")
+	}
+
+	*bad = triggered
+	bad.Trigger = &options.Trigger{BucketID: "020f755c3c082000"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for zero trigger min interval")
+	}
+}
+
+func TestSetTriggerRange(t *testing.T) {
+	start := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	stop := start.Add(time.Minute)
+
+	script := scriptGenerator(options.Options{Name: "name", Trigger: &options.Trigger{BucketID: "020f755c3c082000"}}, `option trigger = {start: -1h, stop: now()}
+
+from(bucket: "test")
+    |> range(start: trigger.start, stop: trigger.stop)`)
+	got, err := options.SetTriggerRange(script, start, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "option trigger = {start: 2019-01-02T03:04:05Z, stop: 2019-01-02T03:05:05Z}") {
+		t.Fatalf("expected trigger range to be set, got %q", got)
+	}
+	if _, err := options.FromScript(got); err != nil {
+		t.Fatalf("script with trigger range should have been valid, got %v", err)
+	}
+
+	script = scriptGenerator(options.Options{Name: "name", Every: time.Hour}, "")
+	got, err = options.SetTriggerRange(script, start, stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != script {
+		t.Fatalf("expected script without trigger option to be unchanged, got %q", got)
+	}
 }
 
 func TestEffectiveCronString(t *testing.T) {
@@ -63,6 +63,34 @@ func (p pAdapter) FindTasks(ctx context.Context, filter platform.TaskFilter) ([]
 		}
 	}
 	params.Org = org.ID
+	if filter.DependsOn != nil {
+		ids, err := p.s.FindDependentTasks(ctx, *filter.DependsOn)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		tasks := make([]*platform.Task, 0, len(ids))
+		for _, id := range ids {
+			storeTask, meta, err := p.s.FindTaskByIDWithMeta(ctx, id)
+			if err != nil {
+				if err == backend.ErrTaskNotFound {
+					continue
+				}
+				return nil, 0, err
+			}
+			if params.Org.Valid() && storeTask.Org != params.Org {
+				continue
+			}
+			task, err := p.toPlatformTask(ctx, *storeTask, meta)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			tasks = append(tasks, task)
+		}
+
+		return tasks, len(tasks), nil
+	}
 	if filter.User != nil {
 		ownedTasks, _, err := p.urm.FindUserResourceMappings(
 			ctx,
@@ -145,6 +173,9 @@ func (p pAdapter) CreateTask(ctx context.Context, t platform.TaskCreate) (*platf
 		ScheduleAfter: scheduleAfter,
 		Status:        backend.TaskStatus(t.Status),
 		Script:        t.Flux,
+		UserID:        auth.GetUserID(),
+
+		NotificationRules: toStoreNotificationRules(t.NotificationRules),
 	}
 	req.AuthorizationID, err = p.authorizationIDFromToken(ctx, t.Token)
 	if err != nil {
@@ -164,6 +195,9 @@ func (p pAdapter) CreateTask(ctx context.Context, t platform.TaskCreate) (*platf
 		Organization:    org.Name,
 		Status:          t.Status,
 		AuthorizationID: req.AuthorizationID,
+		Revision:        1,
+
+		NotificationRules: t.NotificationRules,
 	}
 
 	if opts.Every != 0 {
@@ -172,6 +206,13 @@ func (p pAdapter) CreateTask(ctx context.Context, t platform.TaskCreate) (*platf
 	if opts.Offset != 0 {
 		task.Offset = opts.Offset.String()
 	}
+	for _, s := range opts.DependsOn {
+		// The store already validated the IDs of the upstream tasks.
+		if upstream, err := platform.IDFromString(s); err == nil {
+			task.DependsOn = append(task.DependsOn, *upstream)
+		}
+	}
+	task.Trigger = toPlatformTrigger(opts.Trigger)
 
 	mapping := &platform.UserResourceMapping{
 		UserID:       auth.GetUserID(),
@@ -198,7 +239,11 @@ func (p pAdapter) UpdateTask(ctx context.Context, id platform.ID, upd platform.T
 	if err != nil {
 		return nil, err
 	}
-	req := backend.UpdateTaskRequest{ID: id}
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req := backend.UpdateTaskRequest{ID: id, UserID: auth.GetUserID()}
 	if upd.Flux != nil {
 		req.Script = *upd.Flux
 	}
@@ -206,6 +251,10 @@ func (p pAdapter) UpdateTask(ctx context.Context, id platform.ID, upd platform.T
 		req.Status = backend.TaskStatus(*upd.Status)
 	}
 	req.Options = upd.Options
+	if upd.NotificationRules != nil {
+		rules := toStoreNotificationRules(*upd.NotificationRules)
+		req.NotificationRules = &rules
+	}
 
 	req.AuthorizationID, err = p.authorizationIDFromToken(ctx, upd.Token)
 	if err != nil {
@@ -330,6 +379,191 @@ func (p pAdapter) CancelRun(ctx context.Context, taskID, runID platform.ID) erro
 	return p.rc.CancelRun(ctx, taskID, runID)
 }
 
+func (p pAdapter) CreateBackfill(ctx context.Context, taskID platform.ID, start, stop int64) (*platform.Backfill, error) {
+	if stop < start {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "backfill stop must not be earlier than start",
+		}
+	}
+
+	mr, err := p.s.Backfill(ctx, taskID, start, stop, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := p.s.FindTaskMetaByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return toPlatformBackfill(taskID, m, mr)
+}
+
+func (p pAdapter) FindBackfills(ctx context.Context, taskID platform.ID) ([]*platform.Backfill, error) {
+	m, err := p.s.FindTaskMetaByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	backfills := make([]*platform.Backfill, 0, len(m.ManualRuns))
+	for _, mr := range m.ManualRuns {
+		if mr.BackfillID == 0 {
+			// Not a backfill, but a forced run or a retry.
+			continue
+		}
+		b, err := toPlatformBackfill(taskID, m, mr)
+		if err != nil {
+			return nil, err
+		}
+		backfills = append(backfills, b)
+	}
+	return backfills, nil
+}
+
+func (p pAdapter) UpdateBackfill(ctx context.Context, taskID, backfillID platform.ID, upd platform.BackfillUpdate) (*platform.Backfill, error) {
+	if err := upd.Validate(); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	var mr *backend.StoreTaskMetaManualRun
+	var err error
+	if upd.Status != nil {
+		mr, err = p.s.SetBackfillPaused(ctx, taskID, backfillID, *upd.Status == platform.BackfillStatusPaused)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m, err := p.s.FindTaskMetaByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if mr == nil {
+		for _, r := range m.ManualRuns {
+			if r.BackfillID != 0 && platform.ID(r.BackfillID) == backfillID {
+				mr = r
+				break
+			}
+		}
+		if mr == nil {
+			return nil, backend.ErrBackfillNotFound
+		}
+	}
+	return toPlatformBackfill(taskID, m, mr)
+}
+
+func (p pAdapter) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) error {
+	mr, err := p.s.CancelBackfill(ctx, taskID, backfillID)
+	if err != nil {
+		return err
+	}
+
+	m, err := p.s.FindTaskMetaByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	// Runs of the backfill in progress have the range and request time of the backfill.
+	for _, cr := range m.CurrentlyRunning {
+		if cr.RangeStart != mr.Start || cr.RangeEnd != mr.End || cr.RequestedAt != mr.RequestedAt {
+			continue
+		}
+		// The run may have finished, or the task may not be claimed by a scheduler.
+		if err := p.rc.CancelRun(ctx, taskID, platform.ID(cr.RunID)); err != nil && err != backend.ErrRunNotFound && err != backend.ErrTaskNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// toPlatformBackfill converts the backfill mr of the task with meta m to a platform.Backfill.
+func toPlatformBackfill(taskID platform.ID, m *backend.StoreTaskMeta, mr *backend.StoreTaskMetaManualRun) (*platform.Backfill, error) {
+	completed, total, err := m.BackfillProgress(mr)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &platform.Backfill{
+		ID:            platform.ID(mr.BackfillID),
+		TaskID:        taskID,
+		Status:        platform.BackfillStatusActive,
+		Start:         time.Unix(mr.Start, 0).UTC().Format(time.RFC3339),
+		Stop:          time.Unix(mr.End, 0).UTC().Format(time.RFC3339),
+		RequestedAt:   time.Unix(mr.RequestedAt, 0).UTC().Format(time.RFC3339),
+		RunsCompleted: completed,
+		RunsTotal:     total,
+	}
+	if mr.Paused {
+		b.Status = platform.BackfillStatusPaused
+	}
+	if mr.LatestCompleted >= mr.Start {
+		b.LatestCompleted = time.Unix(mr.LatestCompleted, 0).UTC().Format(time.RFC3339)
+	}
+	return b, nil
+}
+
+func (p pAdapter) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*platform.TaskRevision, error) {
+	revs, err := p.s.ListTaskRevisions(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]*platform.TaskRevision, 0, len(revs))
+	for _, rev := range revs {
+		pr, err := toPlatformTaskRevision(taskID, rev)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+func (p pAdapter) FindTaskRevision(ctx context.Context, taskID platform.ID, revision int64) (*platform.TaskRevision, error) {
+	rev, err := p.s.FindTaskRevision(ctx, taskID, revision)
+	if err != nil {
+		return nil, err
+	}
+	return toPlatformTaskRevision(taskID, *rev)
+}
+
+func (p pAdapter) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*platform.Task, error) {
+	rev, err := p.s.FindTaskRevision(ctx, taskID, revision)
+	if err != nil {
+		return nil, err
+	}
+	// Updating the script records it as a new revision, so the rollback can be rolled back in turn.
+	return p.UpdateTask(ctx, taskID, platform.TaskUpdate{Flux: &rev.Script})
+}
+
+// toPlatformTaskRevision converts the revision rev of the task with the given ID to a platform.TaskRevision.
+func toPlatformTaskRevision(taskID platform.ID, rev backend.StoreTaskRevision) (*platform.TaskRevision, error) {
+	opts, err := options.FromScript(rev.Script)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &platform.TaskRevision{
+		TaskID:    taskID,
+		Revision:  rev.Revision,
+		Flux:      rev.Script,
+		Name:      opts.Name,
+		Cron:      opts.Cron,
+		UpdatedBy: rev.UpdatedBy,
+		UpdatedAt: time.Unix(rev.UpdatedAt, 0).UTC().Format(time.RFC3339),
+	}
+	if opts.Every != 0 {
+		pr.Every = opts.Every.String()
+	}
+	if opts.Offset != 0 {
+		pr.Offset = opts.Offset.String()
+	}
+	return pr, nil
+}
+
 var errTokenUnreadable = errors.New("token invalid or unreadable by the current user")
 
 // authorizationIDFromToken looks up the authorization ID from the given token,
@@ -398,6 +632,7 @@ func (p *pAdapter) toPlatformTask(ctx context.Context, t backend.StoreTask, m *b
 	if opts.Offset != 0 {
 		pt.Offset = opts.Offset.String()
 	}
+	pt.Trigger = toPlatformTrigger(opts.Trigger)
 	if m != nil {
 		pt.Status = string(m.Status)
 		pt.LatestCompleted = time.Unix(m.LatestCompleted, 0).Format(time.RFC3339)
@@ -408,10 +643,60 @@ func (p *pAdapter) toPlatformTask(ctx context.Context, t backend.StoreTask, m *b
 			pt.UpdatedAt = time.Unix(m.UpdatedAt, 0).Format(time.RFC3339)
 		}
 		pt.AuthorizationID = platform.ID(m.AuthorizationID)
+		for _, id := range m.DependsOn {
+			pt.DependsOn = append(pt.DependsOn, platform.ID(id))
+		}
+		pt.NotificationRules = toPlatformNotificationRules(m.NotificationRules)
+		pt.Revision = m.Revision
 	}
 	return pt, nil
 }
 
+func toStoreNotificationRules(rules []platform.TaskNotificationRule) []*backend.StoreTaskNotificationRule {
+	srs := make([]*backend.StoreTaskNotificationRule, 0, len(rules))
+	for _, r := range rules {
+		srs = append(srs, &backend.StoreTaskNotificationRule{
+			On:            r.On,
+			Threshold:     int32(r.Threshold),
+			URL:           r.URL,
+			AuthHeader:    r.AuthHeader,
+			AuthSecretKey: r.AuthSecretKey,
+		})
+	}
+	return srs
+}
+
+// toPlatformTrigger returns the platform representation of the trigger t, which may be nil.
+func toPlatformTrigger(t *options.Trigger) *platform.TaskTrigger {
+	if t == nil {
+		return nil
+	}
+
+	pt := &platform.TaskTrigger{
+		Measurement: t.Measurement,
+		MinInterval: t.MinInterval.String(),
+	}
+	// The options were already validated.
+	if id, err := platform.IDFromString(t.BucketID); err == nil {
+		pt.BucketID = *id
+	}
+	return pt
+}
+
+func toPlatformNotificationRules(srs []*backend.StoreTaskNotificationRule) []platform.TaskNotificationRule {
+	var rules []platform.TaskNotificationRule
+	for _, r := range srs {
+		rules = append(rules, platform.TaskNotificationRule{
+			On:            r.On,
+			Threshold:     int(r.Threshold),
+			URL:           r.URL,
+			AuthHeader:    r.AuthHeader,
+			AuthSecretKey: r.AuthSecretKey,
+		})
+	}
+	return rules
+}
+
 func (p *pAdapter) populateOrg(ctx context.Context, org *platform.Organization) error {
 	if org.ID.Valid() && org.Name != "" {
 		return nil
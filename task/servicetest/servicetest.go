@@ -77,6 +77,21 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory) {
 			t.Parallel()
 			testMetaUpdate(t, sys)
 		})
+
+		t.Run("Task Dependencies", func(t *testing.T) {
+			t.Parallel()
+			testTaskDependencies(t, sys)
+		})
+
+		t.Run("Task Notification Rules", func(t *testing.T) {
+			t.Parallel()
+			testTaskNotificationRules(t, sys)
+		})
+
+		t.Run("Task Revisions", func(t *testing.T) {
+			t.Parallel()
+			testTaskRevisions(t, sys)
+		})
 	})
 }
 
@@ -424,6 +439,188 @@ func testMetaUpdate(t *testing.T, sys *System) {
 	}
 }
 
+func testTaskDependencies(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+
+	upstream, err := sys.ts.CreateTask(authorizedCtx, platform.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptFmt, 0),
+		Token:          cr.Token,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	downstream, err := sys.ts.CreateTask(authorizedCtx, platform.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptDependsOnFmt, upstream.ID.String()),
+		Token:          cr.Token,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(downstream.DependsOn) != 1 || downstream.DependsOn[0] != upstream.ID {
+		t.Fatalf("expected created task to depend on %s, got %v", upstream.ID, downstream.DependsOn)
+	}
+
+	found, err := sys.ts.FindTaskByID(sys.Ctx, downstream.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found.DependsOn) != 1 || found.DependsOn[0] != upstream.ID {
+		t.Fatalf("expected found task to depend on %s, got %v", upstream.ID, found.DependsOn)
+	}
+
+	tasks, _, err := sys.ts.FindTasks(sys.Ctx, platform.TaskFilter{DependsOn: &upstream.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != downstream.ID {
+		t.Fatalf("expected only task %s to depend on %s, got %v", downstream.ID, upstream.ID, tasks)
+	}
+
+	// The upstream task can't depend on its downstream task.
+	flux := fmt.Sprintf(scriptDependsOnFmt, downstream.ID.String())
+	if _, err := sys.ts.UpdateTask(authorizedCtx, upstream.ID, platform.TaskUpdate{Flux: &flux}); err == nil {
+		t.Fatal("expected failure when creating a cycle of dependencies")
+	}
+
+	// Nor on a task which doesn't exist.
+	if _, err := sys.ts.CreateTask(authorizedCtx, platform.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           fmt.Sprintf(scriptDependsOnFmt, "000000000000270f"),
+		Token:          cr.Token,
+	}); err == nil {
+		t.Fatal("expected failure when depending on a task which doesn't exist")
+	}
+}
+
+func testTaskNotificationRules(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+
+	rules := []platform.TaskNotificationRule{
+		{On: platform.TaskNotifyOnFailure, URL: "http://example.com/failure"},
+		{On: platform.TaskNotifyOnConsecutiveFailures, Threshold: 3, URL: "http://example.com/failures", AuthHeader: "Authorization", AuthSecretKey: "hook-token"},
+	}
+	task, err := sys.ts.CreateTask(authorizedCtx, platform.TaskCreate{
+		OrganizationID:    cr.OrgID,
+		Flux:              fmt.Sprintf(scriptFmt, 0),
+		Token:             cr.Token,
+		NotificationRules: rules,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(task.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules on created task: %v", task.NotificationRules)
+	}
+
+	found, err := sys.ts.FindTaskByID(sys.Ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(found.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules on found task: %v", found.NotificationRules)
+	}
+
+	rules = []platform.TaskNotificationRule{{On: platform.TaskNotifyOnRecovery, URL: "http://example.com/recovery"}}
+	updated, err := sys.ts.UpdateTask(authorizedCtx, task.ID, platform.TaskUpdate{NotificationRules: &rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(updated.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules on updated task: %v", updated.NotificationRules)
+	}
+
+	// Updating the script keeps the rules.
+	flux := fmt.Sprintf(scriptFmt, 1)
+	if updated, err = sys.ts.UpdateTask(authorizedCtx, task.ID, platform.TaskUpdate{Flux: &flux}); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(updated.NotificationRules, rules) {
+		t.Fatalf("unexpected notification rules after script update: %v", updated.NotificationRules)
+	}
+
+	// An empty list removes all rules.
+	rules = []platform.TaskNotificationRule{}
+	if updated, err = sys.ts.UpdateTask(authorizedCtx, task.ID, platform.TaskUpdate{NotificationRules: &rules}); err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.NotificationRules) != 0 {
+		t.Fatalf("expected notification rules to be removed, got %v", updated.NotificationRules)
+	}
+}
+
+func testTaskRevisions(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+
+	flux0 := fmt.Sprintf(scriptFmt, 0)
+	task, err := sys.ts.CreateTask(authorizedCtx, platform.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux:           flux0,
+		Token:          cr.Token,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Revision != 1 {
+		t.Fatalf("expected created task at revision 1, got %d", task.Revision)
+	}
+
+	flux1 := fmt.Sprintf(scriptFmt, 1)
+	updated, err := sys.ts.UpdateTask(authorizedCtx, task.ID, platform.TaskUpdate{Flux: &flux1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Revision != 2 {
+		t.Fatalf("expected updated task at revision 2, got %d", updated.Revision)
+	}
+
+	revs, err := sys.ts.FindTaskRevisions(authorizedCtx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revs))
+	}
+	if revs[0].Revision != 1 || revs[0].Flux != flux0 || revs[0].Name != task.Name || revs[0].UpdatedBy != cr.UserID {
+		t.Fatalf("unexpected first revision: %+v", revs[0])
+	}
+	if revs[1].Revision != 2 || revs[1].Flux != flux1 || revs[1].UpdatedBy != cr.UserID {
+		t.Fatalf("unexpected second revision: %+v", revs[1])
+	}
+
+	rev, err := sys.ts.FindTaskRevision(authorizedCtx, task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(rev, revs[0]) {
+		t.Fatalf("unexpected revision: %s", cmp.Diff(revs[0], rev))
+	}
+
+	// Rolling back records the restored script as a new revision.
+	rolledBack, err := sys.ts.RollbackTask(authorizedCtx, task.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolledBack.Flux != flux0 || rolledBack.Revision != 3 {
+		t.Fatalf("unexpected task after rollback: revision %d, flux %s", rolledBack.Revision, rolledBack.Flux)
+	}
+	if revs, err = sys.ts.FindTaskRevisions(authorizedCtx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 3 || revs[2].Flux != flux0 {
+		t.Fatalf("unexpected revisions after rollback: %+v", revs)
+	}
+
+	if _, err := sys.ts.FindTaskRevision(authorizedCtx, task.ID, 4); err != backend.ErrRevisionNotFound && platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected not found error for missing revision, got %v", err)
+	}
+}
+
 func testTaskRuns(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
@@ -724,6 +921,81 @@ func testTaskRuns(t *testing.T, sys *System) {
 		}
 	})
 
+	t.Run("Backfill", func(t *testing.T) {
+		t.Parallel()
+
+		ct := platform.TaskCreate{
+			OrganizationID: cr.OrgID,
+			Flux:           fmt.Sprintf(scriptFmt, 0),
+			Token:          cr.Token,
+		}
+		task, err := sys.ts.CreateTask(icontext.SetAuthorizer(sys.Ctx, cr.Authorizer()), ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The task runs every minute, so there are 61 schedules in an hour, inclusive.
+		const start, stop = 3600, 7200
+		b, err := sys.ts.CreateBackfill(sys.Ctx, task.ID, start+1, stop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.TaskID != task.ID || b.Status != platform.BackfillStatusActive {
+			t.Fatalf("unexpected backfill %#v", b)
+		}
+		if exp := time.Unix(start+60, 0).UTC().Format(time.RFC3339); b.Start != exp {
+			t.Fatalf("expected backfill to start at the first schedule %s, got %s", exp, b.Start)
+		}
+		if b.RunsTotal != 60 || b.RunsCompleted != 0 {
+			t.Fatalf("expected 0 of 60 runs completed, got %d of %d", b.RunsCompleted, b.RunsTotal)
+		}
+
+		if _, err := sys.ts.CreateBackfill(sys.Ctx, task.ID, start+1, start+2); err == nil {
+			t.Fatal("expected failure when backfilling a range without schedules")
+		}
+
+		backfills, err := sys.ts.FindBackfills(sys.Ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(backfills) != 1 || backfills[0].ID != b.ID {
+			t.Fatalf("expected to find backfill %s, got %#v", b.ID, backfills)
+		}
+
+		paused := platform.BackfillStatusPaused
+		b, err = sys.ts.UpdateBackfill(sys.Ctx, task.ID, b.ID, platform.BackfillUpdate{Status: &paused})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Status != platform.BackfillStatusPaused {
+			t.Fatalf("expected backfill to be paused, got %s", b.Status)
+		}
+
+		m, err := sys.S.FindTaskMetaByID(sys.Ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m.ManualRuns) != 1 || !m.ManualRuns[0].Paused || platform.ID(m.ManualRuns[0].BackfillID) != b.ID {
+			t.Fatalf("expected paused backfill in manual runs, got %v", m.ManualRuns)
+		}
+
+		if err := sys.ts.CancelBackfill(sys.Ctx, task.ID, b.ID); err != nil {
+			t.Fatal(err)
+		}
+		backfills, err = sys.ts.FindBackfills(sys.Ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(backfills) != 0 {
+			t.Fatalf("expected no backfills after cancel, got %#v", backfills)
+		}
+
+		// The HTTP client reports a not found error instead of the backend error.
+		if err := sys.ts.CancelBackfill(sys.Ctx, task.ID, b.ID); err != backend.ErrBackfillNotFound && platform.ErrorCode(err) != platform.ENotFound {
+			t.Fatalf("expected not found error when canceling a canceled backfill, got %v", err)
+		}
+	})
+
 	t.Run("FindLogs", func(t *testing.T) {
 		t.Parallel()
 
@@ -774,7 +1046,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 
 		// Add a log for the first run.
 		log1Time := time.Now().UTC()
-		if err := sys.LW.AddRunLog(sys.Ctx, rlb1, log1Time, "entry 1"); err != nil {
+		if err := sys.LW.AddRunLog(sys.Ctx, rlb1, log1Time, backend.RunLogEntry{Message: "entry 1"}); err != nil {
 			t.Fatal(err)
 		}
 
@@ -787,7 +1059,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 			t.Fatal(err)
 		}
 
-		expLine1 := platform.Log(log1Time.Format(time.RFC3339Nano) + ": entry 1")
+		expLine1 := platform.Log{RunID: rc1.Created.RunID, Time: log1Time.Format(time.RFC3339Nano), Level: platform.LogLevelInfo, Message: "entry 1"}
 		exp := []platform.Log{expLine1}
 		if diff := cmp.Diff(logs, exp); diff != "" {
 			t.Fatalf("unexpected log: -got/+want: %s", diff)
@@ -795,7 +1067,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 
 		// Add a log for the second run.
 		log2Time := time.Now().UTC()
-		if err := sys.LW.AddRunLog(sys.Ctx, rlb2, log2Time, "entry 2"); err != nil {
+		if err := sys.LW.AddRunLog(sys.Ctx, rlb2, log2Time, backend.RunLogEntry{Level: platform.LogLevelError, Message: "entry 2", Fields: map[string]string{"error": "oops"}}); err != nil {
 			t.Fatal(err)
 		}
 
@@ -807,7 +1079,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 			t.Fatal(err)
 		}
 
-		expLine2 := platform.Log(log2Time.Format(time.RFC3339Nano) + ": entry 2")
+		expLine2 := platform.Log{RunID: rc2.Created.RunID, Time: log2Time.Format(time.RFC3339Nano), Level: platform.LogLevelError, Message: "entry 2", Fields: map[string]string{"error": "oops"}}
 		exp = []platform.Log{expLine1, expLine2}
 		if diff := cmp.Diff(logs, exp); diff != "" {
 			t.Fatalf("unexpected log: -got/+want: %s", diff)
@@ -1050,6 +1322,17 @@ option task = {
 	concurrency: 100,
 }
 
+from(bucket: "b")
+	|> http.to(url: "http://example.com")`
+
+	scriptDependsOnFmt = `import "http"
+
+option task = {
+	name: "downstream task",
+	cron: "* * * * *",
+	dependsOn: ["%s"],
+}
+
 from(bucket: "b")
 	|> http.to(url: "http://example.com")`
 )
@@ -8,8 +8,10 @@ import (
 
 	"github.com/influxdata/flux"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 	platcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/task/options"
 )
 
 type authError struct {
@@ -95,7 +97,21 @@ func (ts *taskServiceValidator) CreateTask(ctx context.Context, t platform.TaskC
 		return nil, err
 	}
 
-	return ts.TaskService.CreateTask(ctx, t)
+	if err := validateTrigger(ctx, t.Flux, t.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	if err := validateNotificationRules(ctx, t.NotificationRules, t.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	created, err := ts.TaskService.CreateTask(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, platform.AuditCreate, platform.TasksResourceType, created.ID, created.OrganizationID, nil, created)
+	return created, nil
 }
 
 func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
@@ -118,7 +134,25 @@ func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id platform.ID,
 		return nil, err
 	}
 
-	return ts.TaskService.UpdateTask(ctx, id, upd)
+	if upd.Flux != nil {
+		if err := validateTrigger(ctx, *upd.Flux, task.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	if upd.NotificationRules != nil {
+		if err := validateNotificationRules(ctx, *upd.NotificationRules, task.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := ts.TaskService.UpdateTask(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, platform.AuditUpdate, platform.TasksResourceType, id, task.OrganizationID, task, updated)
+	return updated, nil
 }
 
 func (ts *taskServiceValidator) DeleteTask(ctx context.Context, id platform.ID) error {
@@ -137,7 +171,12 @@ func (ts *taskServiceValidator) DeleteTask(ctx context.Context, id platform.ID)
 		return err
 	}
 
-	return ts.TaskService.DeleteTask(ctx, id)
+	if err := ts.TaskService.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, platform.AuditDelete, platform.TasksResourceType, id, task.OrganizationID, task, nil)
+	return nil
 }
 
 func (ts *taskServiceValidator) FindLogs(ctx context.Context, filter platform.LogFilter) ([]*platform.Log, int, error) {
@@ -246,6 +285,102 @@ func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID platform.ID
 	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor)
 }
 
+func (ts *taskServiceValidator) CreateBackfill(ctx context.Context, taskID platform.ID, start, stop int64) (*platform.Backfill, error) {
+	if err := ts.validateTaskPermission(ctx, taskID, platform.WriteAction); err != nil {
+		return nil, err
+	}
+
+	return ts.TaskService.CreateBackfill(ctx, taskID, start, stop)
+}
+
+func (ts *taskServiceValidator) FindBackfills(ctx context.Context, taskID platform.ID) ([]*platform.Backfill, error) {
+	if err := ts.validateTaskPermission(ctx, taskID, platform.ReadAction); err != nil {
+		return nil, err
+	}
+
+	return ts.TaskService.FindBackfills(ctx, taskID)
+}
+
+func (ts *taskServiceValidator) UpdateBackfill(ctx context.Context, taskID, backfillID platform.ID, upd platform.BackfillUpdate) (*platform.Backfill, error) {
+	if err := ts.validateTaskPermission(ctx, taskID, platform.WriteAction); err != nil {
+		return nil, err
+	}
+
+	return ts.TaskService.UpdateBackfill(ctx, taskID, backfillID, upd)
+}
+
+func (ts *taskServiceValidator) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) error {
+	if err := ts.validateTaskPermission(ctx, taskID, platform.WriteAction); err != nil {
+		return err
+	}
+
+	return ts.TaskService.CancelBackfill(ctx, taskID, backfillID)
+}
+
+func (ts *taskServiceValidator) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*platform.TaskRevision, error) {
+	if err := ts.validateTaskPermission(ctx, taskID, platform.ReadAction); err != nil {
+		return nil, err
+	}
+
+	return ts.TaskService.FindTaskRevisions(ctx, taskID)
+}
+
+func (ts *taskServiceValidator) FindTaskRevision(ctx context.Context, taskID platform.ID, revision int64) (*platform.TaskRevision, error) {
+	if err := ts.validateTaskPermission(ctx, taskID, platform.ReadAction); err != nil {
+		return nil, err
+	}
+
+	return ts.TaskService.FindTaskRevision(ctx, taskID, revision)
+}
+
+func (ts *taskServiceValidator) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*platform.Task, error) {
+	if err := ts.validateTaskPermission(ctx, taskID, platform.WriteAction); err != nil {
+		return nil, err
+	}
+
+	// The restored script must only access buckets the authorizer may access.
+	rev, err := ts.TaskService.FindTaskRevision(ctx, taskID, revision)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBucket(ctx, rev.Flux, ts.preAuth); err != nil {
+		return nil, err
+	}
+
+	var before *platform.Task
+	if audit.Enabled(ctx) {
+		if before, err = ts.TaskService.FindTaskByID(ctx, taskID); err != nil {
+			return nil, err
+		}
+	}
+
+	rolledBack, err := ts.TaskService.RollbackTask(ctx, taskID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if before != nil {
+		audit.Record(ctx, platform.AuditUpdate, platform.TasksResourceType, taskID, before.OrganizationID, before, rolledBack)
+	}
+	return rolledBack, nil
+}
+
+// validateTaskPermission checks that the authorizer of ctx may perform action on the task with the given ID.
+func (ts *taskServiceValidator) validateTaskPermission(ctx context.Context, taskID platform.ID, action platform.Action) error {
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := ts.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	p, err := platform.NewPermissionAtID(taskID, action, platform.TasksResourceType, task.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	return validatePermission(ctx, *p)
+}
+
 func validatePermission(ctx context.Context, perm platform.Permission) error {
 	auth, err := platcontext.GetAuthorizer(ctx)
 	if err != nil {
@@ -259,6 +394,43 @@ func validatePermission(ctx context.Context, perm platform.Permission) error {
 	return nil
 }
 
+// validateTrigger checks that the authorizer may read the bucket of the trigger of the task with the given script, if it has one.
+// Invalid options are left to be reported by the task service.
+func validateTrigger(ctx context.Context, script string, orgID platform.ID) error {
+	opts, err := options.FromScript(script)
+	if err != nil || opts.Trigger == nil {
+		return nil
+	}
+
+	bucketID, err := platform.IDFromString(opts.Trigger.BucketID)
+	if err != nil {
+		return nil
+	}
+
+	p, err := platform.NewPermissionAtID(*bucketID, platform.ReadAction, platform.BucketsResourceType, orgID)
+	if err != nil {
+		return err
+	}
+	return validatePermission(ctx, *p)
+}
+
+// validateNotificationRules checks that the authorizer may read the secrets of the organization,
+// if one of the notification rules sends a secret to its URL.
+func validateNotificationRules(ctx context.Context, rules []platform.TaskNotificationRule, orgID platform.ID) error {
+	for _, r := range rules {
+		if r.AuthSecretKey == "" {
+			continue
+		}
+
+		p, err := platform.NewPermission(platform.ReadAction, platform.SecretsResourceType, orgID)
+		if err != nil {
+			return err
+		}
+		return validatePermission(ctx, *p)
+	}
+	return nil
+}
+
 func validateBucket(ctx context.Context, script string, preAuth query.PreAuthorizer) error {
 	auth, err := platcontext.GetAuthorizer(ctx)
 	if err != nil {
@@ -3,14 +3,17 @@ package task_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 	pctx "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/http"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/task"
+	"go.uber.org/zap"
 )
 
 func TestOnboardingValidation(t *testing.T) {
@@ -44,6 +47,67 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 	}
 }
 
+func TestValidator_Audit(t *testing.T) {
+	svc := inmem.NewService()
+	r, err := svc.Generate(context.Background(), &influxdb.OnboardingRequest{
+		User:            "Setec Astronomy",
+		Password:        "too many secrets",
+		Org:             "thing",
+		Bucket:          "holder",
+		RetentionPeriod: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var taskID influxdb.ID = 2
+	ts := mockTaskService(r.Org.ID, taskID, 1).(*mock.TaskService)
+	ts.UpdateTaskFn = func(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
+		task, err := ts.FindTaskByIDFn(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		updated := *task
+		updated.Status = *upd.Status
+		return &updated, nil
+	}
+	validator := task.NewValidator(ts, svc)
+
+	var events []*influxdb.AuditEvent
+	log := mock.NewAuditLogService()
+	log.CreateAuditEventFn = func(ctx context.Context, e *influxdb.AuditEvent) error {
+		events = append(events, e)
+		return nil
+	}
+	ctx := pctx.SetAuthorizer(context.Background(), r.Auth)
+	ctx = audit.NewContext(ctx, log, zap.NewNop(), audit.Source{Request: "PATCH /api/v2/tasks/0000000000000002"})
+
+	status := influxdb.TaskStatusInactive
+	if _, err := validator.UpdateTask(ctx, taskID, influxdb.TaskUpdate{Status: &status}); err != nil {
+		t.Fatal(err)
+	}
+	if err := validator.DeleteTask(ctx, taskID); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	update, del := events[0], events[1]
+	if update.Action != influxdb.AuditUpdate || update.ResourceType != influxdb.TasksResourceType || update.ResourceID != taskID || update.OrgID != r.Org.ID {
+		t.Errorf("unexpected update event %+v", update)
+	}
+	if got, want := string(update.Before), `{"status":""}`; got != want {
+		t.Errorf("unexpected before; got %s, want %s", got, want)
+	}
+	if got, want := string(update.After), `{"status":"inactive"}`; got != want {
+		t.Errorf("unexpected after; got %s, want %s", got, want)
+	}
+	if del.Action != influxdb.AuditDelete || del.ResourceID != taskID || del.OrgID != r.Org.ID || del.Before == nil || del.After != nil {
+		t.Errorf("unexpected delete event %+v", del)
+	}
+}
+
 func mockTaskService(orgID, taskID, runID influxdb.ID) influxdb.TaskService {
 	task := influxdb.Task{
 		ID:             taskID,
@@ -57,7 +121,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 		Every: "1s",
 	}
 
-	log := influxdb.Log("howdy partner")
+	log := influxdb.Log{Message: "howdy partner"}
 
 	run := influxdb.Run{
 		ID:           runID,
@@ -66,7 +130,7 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 		ScheduledFor: "a while ago",
 		StartedAt:    "not so long ago",
 		FinishedAt:   "more recently",
-		Log:          log,
+		Log:          []influxdb.Log{log},
 	}
 
 	return &mock.TaskService{
@@ -167,6 +231,17 @@ func TestValidations(t *testing.T) {
 		orgReadTaskPermissions = []influxdb.Permission{
 			{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &taskID}},
 		}
+
+		// Write all tasks in org, and read the secrets of the org.
+		orgWriteAllTaskReadSecretsPermissions = []influxdb.Permission{
+			{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID}},
+			{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.SecretsResourceType, OrgID: &orgID}},
+		}
+
+		// Notification rules sending a secret of the org.
+		secretNotificationRules = []influxdb.TaskNotificationRule{
+			{On: influxdb.TaskNotifyOnFailure, URL: "https://example.com/hook", AuthHeader: "Authorization", AuthSecretKey: "hook-token"},
+		}
 	)
 
 	tests := []struct {
@@ -225,6 +300,47 @@ from(bucket:"bad") |> range(start:-5m) |> to(bucket:"bad", org:"thing")`,
 				return nil
 			},
 		},
+		{
+			name: "create trigger success",
+			auth: &influxdb.Authorization{Status: "active", Permissions: []influxdb.Permission{
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &r.Org.ID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &r.Org.ID, ID: &r.Bucket.ID}},
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &r.Org.ID, ID: &r.Bucket.ID}},
+			}},
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				_, err := svc.CreateTask(ctx, influxdb.TaskCreate{
+					OrganizationID: r.Org.ID,
+					Flux: fmt.Sprintf(`option task = {
+ name: "my_task",
+ trigger: {bucketID: %q},
+}
+from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`, r.Bucket.ID),
+				})
+				return err
+			},
+		},
+		{
+			name: "create trigger badbucket",
+			auth: &influxdb.Authorization{Status: "active", Permissions: []influxdb.Permission{
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &r.Org.ID}},
+				{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &r.Org.ID, ID: &r.Bucket.ID}},
+				{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &r.Org.ID, ID: &r.Bucket.ID}},
+			}},
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				_, err := svc.CreateTask(ctx, influxdb.TaskCreate{
+					OrganizationID: r.Org.ID,
+					Flux: `option task = {
+ name: "my_task",
+ trigger: {bucketID: "020f755c3c082000"},
+}
+from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
+				})
+				if err == nil {
+					return errors.New("created task without permission to read the bucket of its trigger")
+				}
+				return nil
+			},
+		},
 		{
 			name: "FindTaskByID missing auth",
 			auth: &influxdb.Authorization{Permissions: []influxdb.Permission{}},
@@ -359,6 +475,29 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"cows", org:"thing")`
 				return nil
 			},
 		},
+		{
+			name: "UpdateTask notification secret without secrets auth",
+			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteAllTaskBucketPermissions},
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				_, err := svc.UpdateTask(ctx, taskID, influxdb.TaskUpdate{
+					NotificationRules: &secretNotificationRules,
+				})
+				if err == nil {
+					return errors.New("returned no error with unauthorized secret")
+				}
+				return nil
+			},
+		},
+		{
+			name: "UpdateTask notification secret with secrets auth",
+			auth: &influxdb.Authorization{Status: "active", Permissions: append(orgWriteAllTaskReadSecretsPermissions, orgWriteAllTaskBucketPermissions[1:]...)},
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				_, err := svc.UpdateTask(ctx, taskID, influxdb.TaskUpdate{
+					NotificationRules: &secretNotificationRules,
+				})
+				return err
+			},
+		},
 		{
 			name: "DeleteTask missing auth",
 			auth: &influxdb.Authorization{Permissions: []influxdb.Permission{}},
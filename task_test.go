@@ -113,3 +113,55 @@ from(bucket:"x")
 		}
 	})
 }
+
+func TestTaskNotificationRule_Validate(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		rule    platform.TaskNotificationRule
+		wantErr bool
+	}{
+		{name: "failure", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "https://example.com/hook"}},
+		{name: "consecutive failures", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnConsecutiveFailures, Threshold: 3, URL: "http://example.com"}},
+		{name: "recovery with auth", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnRecovery, URL: "http://example.com", AuthHeader: "Authorization", AuthSecretKey: "hook-token"}},
+		{name: "unknown condition", rule: platform.TaskNotificationRule{On: "success", URL: "http://example.com"}, wantErr: true},
+		{name: "missing threshold", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnConsecutiveFailures, URL: "http://example.com"}, wantErr: true},
+		{name: "threshold without consecutive failures", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, Threshold: 2, URL: "http://example.com"}, wantErr: true},
+		{name: "relative url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "/hook"}, wantErr: true},
+		{name: "non-http url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "ftp://example.com"}, wantErr: true},
+		{name: "loopback url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "http://127.0.0.1:8086/hook"}, wantErr: true},
+		{name: "localhost url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "http://localhost/hook"}, wantErr: true},
+		{name: "link-local url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "http://169.254.169.254/latest/meta-data"}, wantErr: true},
+		{name: "ipv6 loopback url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "http://[::1]/hook"}, wantErr: true},
+		{name: "private url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "http://10.0.0.1/hook"}, wantErr: true},
+		{name: "ipv6 unique local url", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "http://[fd00::1]/hook"}, wantErr: true},
+		{name: "auth header without secret", rule: platform.TaskNotificationRule{On: platform.TaskNotifyOnFailure, URL: "http://example.com", AuthHeader: "Authorization"}, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaskUpdate_NotificationRules(t *testing.T) {
+	tu := &platform.TaskUpdate{}
+	if err := json.Unmarshal([]byte(`{"notificationRules":[{"on":"failure","url":"http://example.com"}]}`), tu); err != nil {
+		t.Fatal(err)
+	}
+	if tu.NotificationRules == nil || len(*tu.NotificationRules) != 1 || (*tu.NotificationRules)[0].URL != "http://example.com" {
+		t.Fatalf("notification rules not properly unmarshaled: %v", tu.NotificationRules)
+	}
+	if err := tu.Validate(); err != nil {
+		t.Fatalf("expected update of only notification rules to be valid, got %v", err)
+	}
+
+	// An empty list removes all notification rules.
+	tu = &platform.TaskUpdate{}
+	if err := json.Unmarshal([]byte(`{"notificationRules":[]}`), tu); err != nil {
+		t.Fatal(err)
+	}
+	if tu.NotificationRules == nil || len(*tu.NotificationRules) != 0 {
+		t.Fatalf("expected empty notification rules, got %v", tu.NotificationRules)
+	}
+}
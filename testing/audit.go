@@ -0,0 +1,359 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	auditOneID   = "020f755c3c082000"
+	auditTwoID   = "020f755c3c082001"
+	auditThreeID = "020f755c3c082002"
+	auditFourID  = "020f755c3c082003"
+)
+
+var auditEventCmpOptions = cmp.Options{
+	cmp.Comparer(func(x, y json.RawMessage) bool {
+		return string(x) == string(y)
+	}),
+	cmp.Comparer(func(x, y time.Time) bool {
+		return x.Equal(y)
+	}),
+}
+
+// AuditLogFields will include the IDGenerator, and audit events
+type AuditLogFields struct {
+	IDGenerator platform.IDGenerator
+	Events      []*platform.AuditEvent
+}
+
+// AuditLogService tests all the service functions.
+func AuditLogService(
+	init func(AuditLogFields, *testing.T) (platform.AuditLogService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(AuditLogFields, *testing.T) (platform.AuditLogService, func()),
+			t *testing.T)
+	}{
+		{
+			name: "CreateAuditEvent",
+			fn:   CreateAuditEvent,
+		},
+		{
+			name: "FindAuditEvents",
+			fn:   FindAuditEvents,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+func auditTime(minutes int) time.Time {
+	return time.Date(2019, 2, 1, 12, minutes, 0, 0, time.UTC)
+}
+
+func auditEventsFixture() []*platform.AuditEvent {
+	return []*platform.AuditEvent{
+		{
+			ID:           MustIDBase16(auditOneID),
+			Time:         auditTime(0),
+			Action:       platform.AuditCreate,
+			ResourceType: platform.BucketsResourceType,
+			ResourceID:   MustIDBase16(bucketOneID),
+			OrgID:        MustIDBase16(orgOneID),
+			UserID:       MustIDBase16(oneID),
+			After:        json.RawMessage(`{"name":"b1"}`),
+		},
+		{
+			ID:           MustIDBase16(auditTwoID),
+			Time:         auditTime(1),
+			Action:       platform.AuditUpdate,
+			ResourceType: platform.BucketsResourceType,
+			ResourceID:   MustIDBase16(bucketOneID),
+			OrgID:        MustIDBase16(orgOneID),
+			UserID:       MustIDBase16(twoID),
+			Before:       json.RawMessage(`{"name":"b1"}`),
+			After:        json.RawMessage(`{"name":"b2"}`),
+		},
+		{
+			ID:           MustIDBase16(auditThreeID),
+			Time:         auditTime(2),
+			Action:       platform.AuditCreate,
+			ResourceType: platform.DashboardsResourceType,
+			ResourceID:   MustIDBase16(dashOneID),
+			OrgID:        MustIDBase16(orgTwoID),
+			UserID:       MustIDBase16(oneID),
+		},
+		{
+			ID:           MustIDBase16(auditFourID),
+			Time:         auditTime(3),
+			Action:       platform.AuditDelete,
+			ResourceType: platform.BucketsResourceType,
+			ResourceID:   MustIDBase16(bucketOneID),
+			OrgID:        MustIDBase16(orgOneID),
+			UserID:       MustIDBase16(oneID),
+			Before:       json.RawMessage(`{"name":"b2"}`),
+		},
+	}
+}
+
+// CreateAuditEvent testing
+func CreateAuditEvent(
+	init func(AuditLogFields, *testing.T) (platform.AuditLogService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		event *platform.AuditEvent
+	}
+	type wants struct {
+		err    bool
+		events []*platform.AuditEvent
+	}
+
+	tests := []struct {
+		name   string
+		fields AuditLogFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "records an event with a new ID",
+			fields: AuditLogFields{
+				IDGenerator: mock.NewIDGenerator(auditOneID, t),
+			},
+			args: args{
+				event: &platform.AuditEvent{
+					Time:         auditTime(0),
+					Action:       platform.AuditCreate,
+					ResourceType: platform.OrgsResourceType,
+					ResourceID:   MustIDBase16(orgOneID),
+					OrgID:        MustIDBase16(orgOneID),
+					SourceIP:     "127.0.0.1",
+					Request:      "POST /api/v2/orgs",
+					After:        json.RawMessage(`{"name":"o1"}`),
+				},
+			},
+			wants: wants{
+				events: []*platform.AuditEvent{
+					{
+						ID:           MustIDBase16(auditOneID),
+						Time:         auditTime(0),
+						Action:       platform.AuditCreate,
+						ResourceType: platform.OrgsResourceType,
+						ResourceID:   MustIDBase16(orgOneID),
+						OrgID:        MustIDBase16(orgOneID),
+						SourceIP:     "127.0.0.1",
+						Request:      "POST /api/v2/orgs",
+						After:        json.RawMessage(`{"name":"o1"}`),
+					},
+				},
+			},
+		},
+		{
+			name: "rejects an unknown action",
+			fields: AuditLogFields{
+				IDGenerator: mock.NewIDGenerator(auditOneID, t),
+			},
+			args: args{
+				event: &platform.AuditEvent{
+					Time:         auditTime(0),
+					Action:       platform.AuditAction("read"),
+					ResourceType: platform.OrgsResourceType,
+				},
+			},
+			wants: wants{
+				err:    true,
+				events: []*platform.AuditEvent{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateAuditEvent(ctx, tt.args.event)
+			if (err != nil) != tt.wants.err {
+				t.Fatalf("expected error %v, got %v", tt.wants.err, err)
+			}
+
+			events, _, err := s.FindAuditEvents(ctx, platform.AuditFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve audit events: %v", err)
+			}
+			if diff := cmp.Diff(events, tt.wants.events, auditEventCmpOptions...); diff != "" {
+				t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindAuditEvents testing
+func FindAuditEvents(
+	init func(AuditLogFields, *testing.T) (platform.AuditLogService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter platform.AuditFilter
+		opts   platform.FindOptions
+	}
+	type wants struct {
+		ids   []string
+		total int
+	}
+
+	action := platform.AuditCreate
+	resourceType := platform.BucketsResourceType
+	resourceID := MustIDBase16(bucketOneID)
+	orgID := MustIDBase16(orgTwoID)
+	userID := MustIDBase16(oneID)
+	since := auditTime(1)
+	until := auditTime(3)
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "find all events in the order they were recorded",
+			wants: wants{
+				ids:   []string{auditOneID, auditTwoID, auditThreeID, auditFourID},
+				total: 4,
+			},
+		},
+		{
+			name: "find events in descending order",
+			args: args{
+				opts: platform.FindOptions{Descending: true},
+			},
+			wants: wants{
+				ids:   []string{auditFourID, auditThreeID, auditTwoID, auditOneID},
+				total: 4,
+			},
+		},
+		{
+			name: "find events with offset and limit",
+			args: args{
+				opts: platform.FindOptions{Offset: 1, Limit: 2},
+			},
+			wants: wants{
+				ids:   []string{auditTwoID, auditThreeID},
+				total: 4,
+			},
+		},
+		{
+			name: "find events by action",
+			args: args{
+				filter: platform.AuditFilter{Action: &action},
+			},
+			wants: wants{
+				ids:   []string{auditOneID, auditThreeID},
+				total: 2,
+			},
+		},
+		{
+			name: "find events of a resource",
+			args: args{
+				filter: platform.AuditFilter{ResourceType: &resourceType, ResourceID: &resourceID},
+			},
+			wants: wants{
+				ids:   []string{auditOneID, auditTwoID, auditFourID},
+				total: 3,
+			},
+		},
+		{
+			name: "find events by organization",
+			args: args{
+				filter: platform.AuditFilter{OrgID: &orgID},
+			},
+			wants: wants{
+				ids:   []string{auditThreeID},
+				total: 1,
+			},
+		},
+		{
+			name: "find events by user",
+			args: args{
+				filter: platform.AuditFilter{UserID: &userID},
+			},
+			wants: wants{
+				ids:   []string{auditOneID, auditThreeID, auditFourID},
+				total: 3,
+			},
+		},
+		{
+			name: "find events in a time range",
+			args: args{
+				filter: platform.AuditFilter{Since: &since, Until: &until},
+			},
+			wants: wants{
+				ids:   []string{auditTwoID, auditThreeID},
+				total: 2,
+			},
+		},
+		{
+			name: "find events in a time range in descending order",
+			args: args{
+				filter: platform.AuditFilter{Since: &since, Until: &until},
+				opts:   platform.FindOptions{Descending: true},
+			},
+			wants: wants{
+				ids:   []string{auditThreeID, auditTwoID},
+				total: 2,
+			},
+		},
+		{
+			name: "find events in a time range in descending order with a limit",
+			args: args{
+				filter: platform.AuditFilter{Since: &since, Until: &until},
+				opts:   platform.FindOptions{Descending: true, Limit: 1},
+			},
+			wants: wants{
+				ids:   []string{auditThreeID},
+				total: 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := auditEventsFixture()
+			s, done := init(AuditLogFields{Events: fixture}, t)
+			defer done()
+			ctx := context.Background()
+
+			events, n, err := s.FindAuditEvents(ctx, tt.args.filter, tt.args.opts)
+			if err != nil {
+				t.Fatalf("failed to retrieve audit events: %v", err)
+			}
+			if n != tt.wants.total {
+				t.Errorf("expected count %d to be the total number of matching events %d", n, tt.wants.total)
+			}
+
+			byID := map[string]*platform.AuditEvent{}
+			for _, e := range auditEventsFixture() {
+				byID[e.ID.String()] = e
+			}
+			want := []*platform.AuditEvent{}
+			for _, id := range tt.wants.ids {
+				want = append(want, byID[id])
+			}
+			if diff := cmp.Diff(events, want, auditEventCmpOptions...); diff != "" {
+				t.Errorf("audit events are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/mock"
 )
@@ -29,6 +31,54 @@ var authorizationCmpOptions = cmp.Options{
 		})
 		return out
 	}),
+	// Set by the service when an authorization is created or its token is used.
+	cmpopts.IgnoreFields(platform.Authorization{}, "CreatedAt", "LastUsedAt"),
+}
+
+// AuthorizationServiceOption changes what the AuthorizationService tests expect of a service.
+type AuthorizationServiceOption func(*authorizationServiceOptions)
+
+type authorizationServiceOptions struct {
+	hashedTokens bool
+}
+
+// WithHashedTokens expects the service to only store hashes of tokens.
+// Such a service returns the token of an authorization when creating or rotating it, or finding it by token,
+// but not when finding it by ID or listing authorizations.
+func WithHashedTokens() AuthorizationServiceOption {
+	return func(o *authorizationServiceOptions) {
+		o.hashedTokens = true
+	}
+}
+
+func newAuthorizationServiceOptions(opts []AuthorizationServiceOption) authorizationServiceOptions {
+	var o authorizationServiceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// stored returns the authorization the service is expected to return when finding a by ID or listing it.
+func (o authorizationServiceOptions) stored(a *platform.Authorization) *platform.Authorization {
+	if a == nil || !o.hashedTokens {
+		return a
+	}
+	c := *a
+	c.Token, c.PreviousToken = "", ""
+	return &c
+}
+
+// storedAll returns the authorizations the service is expected to return when listing as.
+func (o authorizationServiceOptions) storedAll(as []*platform.Authorization) []*platform.Authorization {
+	if as == nil || !o.hashedTokens {
+		return as
+	}
+	stored := make([]*platform.Authorization, 0, len(as))
+	for _, a := range as {
+		stored = append(stored, o.stored(a))
+	}
+	return stored
 }
 
 // AuthorizationFields will include the IDGenerator, and authorizations
@@ -43,11 +93,12 @@ type AuthorizationFields struct {
 // AuthorizationService tests all the service functions.
 func AuthorizationService(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()), t *testing.T,
+	opts ...AuthorizationServiceOption,
 ) {
 	tests := []struct {
 		name string
 		fn   func(init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
-			t *testing.T)
+			t *testing.T, opts ...AuthorizationServiceOption)
 	}{
 		{
 			name: "CreateAuthorization",
@@ -73,10 +124,14 @@ func AuthorizationService(
 			name: "DeleteAuthorization",
 			fn:   DeleteAuthorization,
 		},
+		{
+			name: "RotateAuthorization",
+			fn:   RotateAuthorization,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.fn(init, t)
+			tt.fn(init, t, opts...)
 		})
 	}
 }
@@ -85,6 +140,7 @@ func AuthorizationService(
 func CreateAuthorization(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
 	t *testing.T,
+	opts ...AuthorizationServiceOption,
 ) {
 	type args struct {
 		authorization *platform.Authorization
@@ -346,6 +402,9 @@ func CreateAuthorization(
 			}
 
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+			if err == nil && tt.args.authorization.Token == "" {
+				t.Errorf("expected the token of the created authorization to be returned")
+			}
 
 			defer s.DeleteAuthorization(ctx, tt.args.authorization.ID)
 
@@ -353,7 +412,8 @@ func CreateAuthorization(
 			if err != nil {
 				t.Fatalf("failed to retrieve authorizations: %v", err)
 			}
-			if diff := cmp.Diff(authorizations, tt.wants.authorizations, authorizationCmpOptions...); diff != "" {
+			want := newAuthorizationServiceOptions(opts).storedAll(tt.wants.authorizations)
+			if diff := cmp.Diff(authorizations, want, authorizationCmpOptions...); diff != "" {
 				t.Errorf("authorizations are different -got/+want\ndiff %s", diff)
 			}
 		})
@@ -364,6 +424,7 @@ func CreateAuthorization(
 func FindAuthorizationByID(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
 	t *testing.T,
+	opts ...AuthorizationServiceOption,
 ) {
 	type args struct {
 		id platform.ID
@@ -440,7 +501,8 @@ func FindAuthorizationByID(
 			authorization, err := s.FindAuthorizationByID(ctx, tt.args.id)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
 
-			if diff := cmp.Diff(authorization, tt.wants.authorization, authorizationCmpOptions...); diff != "" {
+			want := newAuthorizationServiceOptions(opts).stored(tt.wants.authorization)
+			if diff := cmp.Diff(authorization, want, authorizationCmpOptions...); diff != "" {
 				t.Errorf("authorization is different -got/+want\ndiff %s", diff)
 			}
 		})
@@ -451,6 +513,7 @@ func FindAuthorizationByID(
 func UpdateAuthorizationStatus(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
 	t *testing.T,
+	opts ...AuthorizationServiceOption,
 ) {
 	type args struct {
 		id     platform.ID
@@ -678,7 +741,8 @@ func UpdateAuthorizationStatus(
 				if err != nil {
 					t.Errorf("%s failed, got error %s", tt.name, err.Error())
 				}
-				if diff := cmp.Diff(authorization, tt.wants.authorization, authorizationCmpOptions...); diff != "" {
+				want := newAuthorizationServiceOptions(opts).stored(tt.wants.authorization)
+				if diff := cmp.Diff(authorization, want, authorizationCmpOptions...); diff != "" {
 					t.Errorf("authorization is different -got/+want\ndiff %s", diff)
 				}
 			}
@@ -690,6 +754,7 @@ func UpdateAuthorizationStatus(
 func FindAuthorizationByToken(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
 	t *testing.T,
+	opts ...AuthorizationServiceOption,
 ) {
 	type args struct {
 		token string
@@ -796,11 +861,13 @@ func FindAuthorizationByToken(
 func FindAuthorizations(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
 	t *testing.T,
+	opts ...AuthorizationServiceOption,
 ) {
 	type args struct {
-		ID     platform.ID
-		UserID platform.ID
-		token  string
+		ID          platform.ID
+		UserID      platform.ID
+		token       string
+		unusedSince time.Time
 	}
 
 	type wants struct {
@@ -1005,6 +1072,56 @@ func FindAuthorizations(
 				},
 			},
 		},
+		{
+			name: "find authorizations unused since time",
+			fields: AuthorizationFields{
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Orgs: []*platform.Organization{
+					{
+						Name: "o1",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand1",
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+						LastUsedAt:  timePtr(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+					},
+					{
+						ID:          MustIDBase16(authTwoID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand2",
+						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
+						LastUsedAt:  timePtr(time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+			args: args{
+				unusedSince: time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wants: wants{
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand1",
+						Status:      platform.Active,
+						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1023,10 +1140,19 @@ func FindAuthorizations(
 			if tt.args.token != "" {
 				filter.Token = &tt.args.token
 			}
+			if !tt.args.unusedSince.IsZero() {
+				filter.UnusedSince = &tt.args.unusedSince
+			}
 
 			authorizations, _, err := s.FindAuthorizations(ctx, filter)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
-			if diff := cmp.Diff(authorizations, tt.wants.authorizations, authorizationCmpOptions...); diff != "" {
+
+			// The authorization found by token is returned with its token.
+			want := tt.wants.authorizations
+			if filter.Token == nil {
+				want = newAuthorizationServiceOptions(opts).storedAll(want)
+			}
+			if diff := cmp.Diff(authorizations, want, authorizationCmpOptions...); diff != "" {
 				t.Errorf("authorizations are different -got/+want\ndiff %s", diff)
 			}
 		})
@@ -1037,6 +1163,7 @@ func FindAuthorizations(
 func DeleteAuthorization(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
 	t *testing.T,
+	opts ...AuthorizationServiceOption,
 ) {
 	type args struct {
 		ID platform.ID
@@ -1184,13 +1311,189 @@ func DeleteAuthorization(
 			if err != nil {
 				t.Fatalf("failed to retrieve authorizations: %v", err)
 			}
-			if diff := cmp.Diff(authorizations, tt.wants.authorizations, authorizationCmpOptions...); diff != "" {
+			want := newAuthorizationServiceOptions(opts).storedAll(tt.wants.authorizations)
+			if diff := cmp.Diff(authorizations, want, authorizationCmpOptions...); diff != "" {
 				t.Errorf("authorizations are different -got/+want\ndiff %s", diff)
 			}
 		})
 	}
 }
 
+// RotateAuthorization testing
+func RotateAuthorization(
+	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
+	t *testing.T,
+	opts ...AuthorizationServiceOption,
+) {
+	type args struct {
+		id          platform.ID
+		gracePeriod time.Duration
+	}
+	type wants struct {
+		err            error
+		token          string
+		previousToken  string
+		acceptedTokens []string
+		rejectedTokens []string
+	}
+
+	fields := func(a *platform.Authorization) AuthorizationFields {
+		return AuthorizationFields{
+			TokenGenerator: &mock.TokenGenerator{
+				TokenFn: func() (string, error) {
+					return "rotated", nil
+				},
+			},
+			Users: []*platform.User{
+				{
+					Name: "cooluser",
+					ID:   MustIDBase16(userOneID),
+				},
+			},
+			Orgs: []*platform.Organization{
+				{
+					Name: "o1",
+					ID:   MustIDBase16(orgOneID),
+				},
+			},
+			Authorizations: []*platform.Authorization{a},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		fields AuthorizationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "rotate with grace period",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "rand1",
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+			}),
+			args: args{
+				id:          MustIDBase16(authOneID),
+				gracePeriod: time.Hour,
+			},
+			wants: wants{
+				token:          "rotated",
+				previousToken:  "rand1",
+				acceptedTokens: []string{"rotated", "rand1"},
+			},
+		},
+		{
+			name: "rotate without grace period",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "rand1",
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+			}),
+			args: args{
+				id: MustIDBase16(authOneID),
+			},
+			wants: wants{
+				token:          "rotated",
+				acceptedTokens: []string{"rotated"},
+				rejectedTokens: []string{"rand1"},
+			},
+		},
+		{
+			name: "rotating again rejects the token of the previous rotation",
+			fields: fields(&platform.Authorization{
+				ID:                     MustIDBase16(authOneID),
+				UserID:                 MustIDBase16(userOneID),
+				OrgID:                  MustIDBase16(orgOneID),
+				Token:                  "rand1",
+				PreviousToken:          "rand0",
+				PreviousTokenExpiresAt: timePtr(time.Now().Add(time.Hour)),
+				Permissions:            allUsersPermission(MustIDBase16(orgOneID)),
+			}),
+			args: args{
+				id:          MustIDBase16(authOneID),
+				gracePeriod: time.Hour,
+			},
+			wants: wants{
+				token:          "rotated",
+				previousToken:  "rand1",
+				acceptedTokens: []string{"rotated", "rand1"},
+				rejectedTokens: []string{"rand0"},
+			},
+		},
+		{
+			name: "rotate with id not found",
+			fields: fields(&platform.Authorization{
+				ID:          MustIDBase16(authOneID),
+				UserID:      MustIDBase16(userOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Token:       "rand1",
+				Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+			}),
+			args: args{
+				id: MustIDBase16(authTwoID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpRotateAuthorization,
+					Msg:  "authorization not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			a, err := s.RotateAuthorization(ctx, tt.args.id, tt.args.gracePeriod)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+			if tt.wants.err != nil {
+				return
+			}
+
+			// Services which store hashes of tokens don't know the previous token.
+			previousToken := tt.wants.previousToken
+			if newAuthorizationServiceOptions(opts).hashedTokens {
+				previousToken = ""
+			}
+			if a.Token != tt.wants.token || a.PreviousToken != previousToken {
+				t.Errorf("expected token %q and previous token %q, got %q and %q", tt.wants.token, previousToken, a.Token, a.PreviousToken)
+			}
+			if tt.wants.previousToken != "" && (a.PreviousTokenExpiresAt == nil || !a.PreviousTokenExpiresAt.After(time.Now())) {
+				t.Errorf("expected previous token to be accepted for grace period, got expiry %v", a.PreviousTokenExpiresAt)
+			}
+
+			for _, token := range tt.wants.acceptedTokens {
+				found, err := s.FindAuthorizationByToken(ctx, token)
+				if err != nil {
+					t.Errorf("expected token %q to be accepted, got error %v", token, err)
+					continue
+				}
+				if found.ID != tt.args.id {
+					t.Errorf("expected token %q to find authorization %s, got %s", token, tt.args.id, found.ID)
+				}
+			}
+			for _, token := range tt.wants.rejectedTokens {
+				if _, err := s.FindAuthorizationByToken(ctx, token); platform.ErrorCode(err) != platform.ENotFound {
+					t.Errorf("expected token %q to be rejected, got error %v", token, err)
+				}
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 func allUsersPermission(orgID platform.ID) []platform.Permission {
 	return []platform.Permission{
 		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.UsersResourceType, OrgID: &orgID}},
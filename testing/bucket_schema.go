@@ -0,0 +1,358 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+)
+
+const (
+	schemaOrgOneID      = "020f755c3c083000"
+	schemaBucketOneID   = "020f755c3c083001"
+	schemaBucketTwoID   = "020f755c3c083002"
+	schemaBucketThreeID = "020f755c3c083003"
+)
+
+// BucketSchemaFields will include the organizations, buckets and measurement
+// schemas to populate the backing store.
+type BucketSchemaFields struct {
+	Organizations      []*platform.Organization
+	Buckets            []*platform.Bucket
+	MeasurementSchemas []*platform.MeasurementSchema
+}
+
+// bucketSchemaFields returns fields with an explicit and an implicit bucket,
+// and a schema for the cpu measurement in the explicit bucket.
+func bucketSchemaFields() BucketSchemaFields {
+	return BucketSchemaFields{
+		Organizations: []*platform.Organization{
+			{
+				ID:   MustIDBase16(schemaOrgOneID),
+				Name: "theorg",
+			},
+		},
+		Buckets: []*platform.Bucket{
+			{
+				ID:             MustIDBase16(schemaBucketOneID),
+				OrganizationID: MustIDBase16(schemaOrgOneID),
+				Name:           "explicit",
+				SchemaType:     platform.SchemaTypeExplicit,
+			},
+			{
+				ID:             MustIDBase16(schemaBucketTwoID),
+				OrganizationID: MustIDBase16(schemaOrgOneID),
+				Name:           "implicit",
+			},
+			{
+				ID:             MustIDBase16(schemaBucketThreeID),
+				OrganizationID: MustIDBase16(schemaOrgOneID),
+				Name:           "explicit2",
+				SchemaType:     platform.SchemaTypeExplicit,
+			},
+		},
+		MeasurementSchemas: []*platform.MeasurementSchema{
+			{
+				BucketID: MustIDBase16(schemaBucketOneID),
+				Name:     "cpu",
+				Tags:     []string{"host"},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "usage_user", Type: platform.SchemaFieldTypeFloat},
+				},
+			},
+		},
+	}
+}
+
+// BucketSchemaService tests all the service functions.
+func BucketSchemaService(
+	init func(BucketSchemaFields, *testing.T) (platform.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   func(init func(BucketSchemaFields, *testing.T) (platform.BucketSchemaService, func()),
+			t *testing.T)
+	}{
+		{
+			name: "CreateMeasurementSchema",
+			fn:   CreateMeasurementSchema,
+		},
+		{
+			name: "FindMeasurementSchemas",
+			fn:   FindMeasurementSchemas,
+		},
+		{
+			name: "UpdateMeasurementSchema",
+			fn:   UpdateMeasurementSchema,
+		},
+		{
+			name: "DeleteMeasurementSchema",
+			fn:   DeleteMeasurementSchema,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// CreateMeasurementSchema testing
+func CreateMeasurementSchema(
+	init func(BucketSchemaFields, *testing.T) (platform.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name   string
+		schema *platform.MeasurementSchema
+		err    error
+	}{
+		{
+			name: "create measurement schema in explicit bucket",
+			schema: &platform.MeasurementSchema{
+				BucketID: MustIDBase16(schemaBucketOneID),
+				Name:     "mem",
+				Tags:     []string{},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "used", Type: platform.SchemaFieldTypeInteger},
+				},
+			},
+		},
+		{
+			name: "measurement schema already exists",
+			schema: &platform.MeasurementSchema{
+				BucketID: MustIDBase16(schemaBucketOneID),
+				Name:     "cpu",
+				Tags:     []string{},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "usage_user", Type: platform.SchemaFieldTypeFloat},
+				},
+			},
+			err: &platform.Error{
+				Code: platform.EConflict,
+				Op:   platform.OpCreateMeasurementSchema,
+				Msg:  `measurement schema "cpu" already exists`,
+			},
+		},
+		{
+			name: "bucket does not have an explicit schema",
+			schema: &platform.MeasurementSchema{
+				BucketID: MustIDBase16(schemaBucketTwoID),
+				Name:     "mem",
+				Tags:     []string{},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "used", Type: platform.SchemaFieldTypeInteger},
+				},
+			},
+			err: &platform.Error{
+				Code: platform.EConflict,
+				Op:   platform.OpCreateMeasurementSchema,
+				Msg:  `bucket "implicit" does not have an explicit schema`,
+			},
+		},
+		{
+			name: "invalid field type",
+			schema: &platform.MeasurementSchema{
+				BucketID: MustIDBase16(schemaBucketOneID),
+				Name:     "mem",
+				Tags:     []string{},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "used", Type: "int"},
+				},
+			},
+			err: &platform.Error{
+				Code: platform.EInvalid,
+				Op:   platform.OpCreateMeasurementSchema,
+				Msg:  `invalid field type "int"; valid field types are float, integer, unsigned, string and boolean`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(bucketSchemaFields(), t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateMeasurementSchema(ctx, tt.schema)
+			ErrorsEqual(t, err, tt.err)
+			if tt.err != nil {
+				return
+			}
+
+			m, err := s.FindMeasurementSchema(ctx, tt.schema.BucketID, tt.schema.Name)
+			if err != nil {
+				t.Fatalf("failed to find measurement schema: %v", err)
+			}
+			if diff := cmp.Diff(m, tt.schema); diff != "" {
+				t.Errorf("measurement schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindMeasurementSchemas testing
+func FindMeasurementSchemas(
+	init func(BucketSchemaFields, *testing.T) (platform.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	fields := bucketSchemaFields()
+
+	tests := []struct {
+		name     string
+		bucketID platform.ID
+		schemas  []*platform.MeasurementSchema
+	}{
+		{
+			name:     "find the schemas of a bucket",
+			bucketID: MustIDBase16(schemaBucketOneID),
+			schemas:  fields.MeasurementSchemas,
+		},
+		{
+			name:     "bucket without schemas",
+			bucketID: MustIDBase16(schemaBucketThreeID),
+			schemas:  []*platform.MeasurementSchema{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(bucketSchemaFields(), t)
+			defer done()
+			ctx := context.Background()
+
+			ms, err := s.FindMeasurementSchemas(ctx, tt.bucketID)
+			if err != nil {
+				t.Fatalf("failed to find measurement schemas: %v", err)
+			}
+			if diff := cmp.Diff(ms, tt.schemas); diff != "" {
+				t.Errorf("measurement schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateMeasurementSchema testing
+func UpdateMeasurementSchema(
+	init func(BucketSchemaFields, *testing.T) (platform.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name   string
+		upd    platform.MeasurementSchemaUpdate
+		schema *platform.MeasurementSchema
+		err    error
+	}{
+		{
+			name: "add a field and a tag",
+			upd: platform.MeasurementSchemaUpdate{
+				Tags: []string{"host", "region"},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "usage_user", Type: platform.SchemaFieldTypeFloat},
+					{Name: "usage_system", Type: platform.SchemaFieldTypeFloat},
+				},
+			},
+			schema: &platform.MeasurementSchema{
+				BucketID: MustIDBase16(schemaBucketOneID),
+				Name:     "cpu",
+				Tags:     []string{"host", "region"},
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "usage_user", Type: platform.SchemaFieldTypeFloat},
+					{Name: "usage_system", Type: platform.SchemaFieldTypeFloat},
+				},
+			},
+		},
+		{
+			name: "change the type of a field",
+			upd: platform.MeasurementSchemaUpdate{
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "usage_user", Type: platform.SchemaFieldTypeInteger},
+				},
+			},
+			err: &platform.Error{
+				Code: platform.EConflict,
+				Op:   platform.OpUpdateMeasurementSchema,
+				Msg:  `field "usage_user" of measurement schema "cpu" cannot change type from float to integer`,
+			},
+		},
+		{
+			name: "remove a field",
+			upd: platform.MeasurementSchemaUpdate{
+				Fields: []platform.MeasurementSchemaField{
+					{Name: "usage_system", Type: platform.SchemaFieldTypeFloat},
+				},
+			},
+			err: &platform.Error{
+				Code: platform.EConflict,
+				Op:   platform.OpUpdateMeasurementSchema,
+				Msg:  `field "usage_user" cannot be removed from measurement schema "cpu"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(bucketSchemaFields(), t)
+			defer done()
+			ctx := context.Background()
+
+			m, err := s.UpdateMeasurementSchema(ctx, MustIDBase16(schemaBucketOneID), "cpu", tt.upd)
+			ErrorsEqual(t, err, tt.err)
+			if tt.err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(m, tt.schema); diff != "" {
+				t.Errorf("measurement schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteMeasurementSchema testing
+func DeleteMeasurementSchema(
+	init func(BucketSchemaFields, *testing.T) (platform.BucketSchemaService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name        string
+		measurement string
+		err         error
+	}{
+		{
+			name:        "delete measurement schema",
+			measurement: "cpu",
+		},
+		{
+			name:        "measurement schema does not exist",
+			measurement: "mem",
+			err: &platform.Error{
+				Code: platform.ENotFound,
+				Op:   platform.OpDeleteMeasurementSchema,
+				Msg:  platform.ErrMeasurementSchemaNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(bucketSchemaFields(), t)
+			defer done()
+			ctx := context.Background()
+
+			bucketID := MustIDBase16(schemaBucketOneID)
+			err := s.DeleteMeasurementSchema(ctx, bucketID, tt.measurement)
+			ErrorsEqual(t, err, tt.err)
+			if tt.err != nil {
+				return
+			}
+
+			_, err = s.FindMeasurementSchema(ctx, bucketID, tt.measurement)
+			if platform.ErrorCode(err) != platform.ENotFound {
+				t.Errorf("expected measurement schema to be deleted, got error %v", err)
+			}
+		})
+	}
+}
@@ -593,6 +593,36 @@ func (c *Cache) DeleteBucketRange(name []byte, min, max int64) {
 	c.tracker.SetMemBytes(uint64(c.Size()))
 }
 
+// DeleteRange removes values for the provided keys with timestamps between
+// min and max from the cache. Keys left with no values are removed entirely.
+func (c *Cache) DeleteRange(keys [][]byte, min, max int64) {
+	c.init()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var total uint64
+	for _, k := range keys {
+		e := c.store.entry(k)
+		if e == nil {
+			continue
+		}
+
+		sz := uint64(e.size())
+		e.filter(min, max)
+		if e.count() == 0 {
+			c.store.remove(k)
+			total += sz + uint64(len(k))
+			continue
+		}
+
+		total += sz - uint64(e.size())
+	}
+
+	c.tracker.DecCacheSize(total)
+	c.tracker.SetMemBytes(uint64(c.Size()))
+}
+
 // SetMaxSize updates the memory limit of the cache.
 func (c *Cache) SetMaxSize(size uint64) {
 	c.mu.Lock()
@@ -0,0 +1,148 @@
+package tsm1
+
+import (
+	"math"
+	"sync"
+
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/bytesutil"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxql"
+)
+
+// DeleteBucketRangePredicate removes TSM data belonging to a bucket for the series
+// matching expr and with timestamps between min and max. Tombstones are written to
+// the TSM files, and any series that no longer contain data are removed from the
+// index and series file.
+//
+// A nil expr matches every series in the bucket.
+func (e *Engine) DeleteBucketRangePredicate(name []byte, min, max int64, expr influxql.Expr) error {
+	// TODO(jeff): the same caveats regarding concurrent writes that apply to
+	// DeleteBucketRange apply here.
+
+	// Ensure that the index does not compact away the measurement or series we're
+	// going to delete before we're done with them.
+	e.index.DisableCompactions()
+	defer e.index.EnableCompactions()
+	e.index.Wait()
+
+	// Disable and abort running level compactions so that tombstones added to
+	// existing tsm files don't get removed.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	e.sfile.DisableCompactions()
+	defer e.sfile.EnableCompactions()
+
+	if min == influxql.MinTime {
+		min = math.MinInt64
+	}
+	if max == influxql.MaxTime {
+		max = math.MaxInt64
+	}
+
+	// The TSI index and Series File do not store series data in escaped form.
+	ids, keys, err := e.matchingSeriesKeys(models.UnescapeMeasurement(name), expr)
+	if err != nil {
+		return err
+	} else if len(keys) == 0 {
+		return nil
+	}
+
+	// Write tombstones to every TSM file and purge the cache.
+	if err := e.FileStore.DeleteRange(keys, min, max); err != nil {
+		return err
+	}
+	e.Cache.DeleteRange(keys, min, max)
+
+	// Find the keys which no longer have any data in the cache or on disk.
+	dead := make(map[string]tsdb.SeriesID, len(keys))
+	for i, key := range keys {
+		if len(e.Cache.Values(key)) == 0 {
+			dead[string(key)] = ids[i]
+		}
+	}
+
+	var alive struct {
+		sync.Mutex
+		keys map[string]struct{}
+	}
+	alive.keys = make(map[string]struct{})
+
+	// Apply runs concurrently over the files, so dead is only read from here.
+	if err := e.FileStore.Apply(func(r TSMFile) error {
+		for key := range dead {
+			if r.Contains([]byte(key)) {
+				alive.Lock()
+				alive.keys[key] = struct{}{}
+				alive.Unlock()
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for key := range alive.keys {
+		delete(dead, key)
+	}
+
+	// Remove the dead series from the index before the series file.
+	for key, sid := range dead {
+		seriesKey, _ := SeriesAndFieldFromCompositeKey([]byte(key))
+		if err := e.index.DropSeries(sid, seriesKey, true); err != nil {
+			return err
+		}
+
+		if err := e.sfile.DeleteSeriesID(sid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchingSeriesKeys returns the series IDs and composite TSM keys of all the
+// series in the measurement name that match expr. The keys are returned sorted.
+func (e *Engine) matchingSeriesKeys(name []byte, expr influxql.Expr) ([]tsdb.SeriesID, [][]byte, error) {
+	itr, err := e.index.MeasurementSeriesByExprIterator(name, expr)
+	if err != nil {
+		return nil, nil, err
+	} else if itr == nil {
+		return nil, nil, nil
+	}
+	defer itr.Close()
+
+	byKey := make(map[string]tsdb.SeriesID)
+	var keys [][]byte
+	for {
+		elem, err := itr.Next()
+		if err != nil {
+			return nil, nil, err
+		} else if elem.SeriesID.IsZero() {
+			break
+		}
+
+		sname, tags := e.sfile.Series(elem.SeriesID)
+		if len(sname) == 0 {
+			continue
+		}
+
+		// Each series in the storage engine has exactly one field, which is
+		// stored in the field key tag.
+		key := models.MakeKey(sname, tags)
+		key = append(key, keyFieldSeparatorBytes...)
+		key = append(key, tags.Get(tsdb.FieldKeyTagKeyBytes)...)
+
+		byKey[string(key)] = elem.SeriesID
+		keys = append(keys, key)
+	}
+
+	bytesutil.Sort(keys)
+
+	ids := make([]tsdb.SeriesID, len(keys))
+	for i, key := range keys {
+		ids[i] = byKey[string(key)]
+	}
+	return ids, keys, nil
+}
@@ -0,0 +1,83 @@
+package tsm1_test
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxql"
+)
+
+func TestEngine_DeleteBucketRangePredicate(t *testing.T) {
+	// Create a few points.
+	p1 := MustParsePointString("cpu,_f=value,host=A value=1.1 1")
+	p2 := MustParsePointString("cpu,_f=value,host=A value=1.2 2")
+	p3 := MustParsePointString("cpu,_f=value,host=A value=1.3 5")
+	p4 := MustParsePointString("cpu,_f=value,host=B value=1.3 1")
+	p5 := MustParsePointString("cpu,_f=value,host=B value=1.3 2")
+	p6 := MustParsePointString("cpu,_f=value,host=C value=1.3 1")
+	p7 := MustParsePointString("mem,_f=value,host=A value=1.3 1")
+
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	if err := e.writePoints(p1, p2, p3, p4, p5, p6, p7); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	if err := e.WriteSnapshot(context.Background()); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	keys := e.FileStore.Keys()
+	if exp, got := 4, len(keys); exp != got {
+		t.Fatalf("series count mismatch: exp %v, got %v", exp, got)
+	}
+
+	// Delete data for host A and B in a range only covering all of the data for host B.
+	expr := influxql.MustParseExpr(`host = 'A' OR host = 'B'`)
+	if err := e.DeleteBucketRangePredicate([]byte("cpu"), 0, 3, expr); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	keys = e.FileStore.Keys()
+	exp := map[string]byte{
+		"cpu,_f=value,host=A#!~#value": 0,
+		"cpu,_f=value,host=C#!~#value": 0,
+		"mem,_f=value,host=A#!~#value": 0,
+	}
+	if !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("unexpected series in file store: %v != %v", keys, exp)
+	}
+
+	// Host B should have been removed from the index, but host A should not.
+	if got, exp := e.index.SeriesN(), int64(3); got != exp {
+		t.Fatalf("series cardinality mismatch: got %d, exp %d", got, exp)
+	}
+
+	// Deleting the remaining data for host A should drop it from the index.
+	expr = influxql.MustParseExpr(`host = 'A'`)
+	if err := e.DeleteBucketRangePredicate([]byte("cpu"), math.MinInt64, math.MaxInt64, expr); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	keys = e.FileStore.Keys()
+	exp = map[string]byte{
+		"cpu,_f=value,host=C#!~#value": 0,
+		"mem,_f=value,host=A#!~#value": 0,
+	}
+	if !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("unexpected series in file store: %v != %v", keys, exp)
+	}
+
+	if got, exp := e.index.SeriesN(), int64(2); got != exp {
+		t.Fatalf("series cardinality mismatch: got %d, exp %d", got, exp)
+	}
+}
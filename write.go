@@ -2,10 +2,71 @@ package influxdb
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"strings"
 )
 
 // WriteService writes data read from the reader.
 type WriteService interface {
 	Write(ctx context.Context, org, bucket ID, r io.Reader) error
 }
+
+// LineError describes a single line of line protocol that was rejected by a write.
+type LineError struct {
+	Line   int    `json:"line"`   // Line is the 1-based line number of the rejected line.
+	Text   string `json:"text"`   // Text is the content of the rejected line.
+	Reason string `json:"reason"` // Reason describes why the line was rejected.
+}
+
+// LineProtocolError describes the lines of line protocol rejected by a write.
+// It is returned wrapped by an *Error, whose code is EInvalid when every line was
+// rejected and nothing was written, or EUnprocessableEntity when the remaining
+// valid lines were still written.
+type LineProtocolError struct {
+	Line   int         `json:"line"` // Line is the first rejected line.
+	Errors []LineError `json:"errors,omitempty"`
+}
+
+// NewLineProtocolError returns the error of a write which rejected the lines of
+// errs. partial indicates whether some of the lines were written.
+func NewLineProtocolError(op string, errs []LineError, partial bool) *Error {
+	lpErr := &LineProtocolError{Errors: errs}
+	if len(errs) > 0 {
+		lpErr.Line = errs[0].Line
+	}
+
+	e := &Error{
+		Code: EInvalid,
+		Op:   op,
+		Msg:  fmt.Sprintf("no points written: %d lines rejected", len(errs)),
+		Err:  lpErr,
+	}
+	if partial {
+		e.Code = EUnprocessableEntity
+		e.Msg = fmt.Sprintf("partial write: %d lines rejected", len(errs))
+	}
+	return e
+}
+
+// Error implements the error interface by listing the rejected lines.
+func (e *LineProtocolError) Error() string {
+	reasons := make([]string, 0, len(e.Errors))
+	for _, le := range e.Errors {
+		reasons = append(reasons, fmt.Sprintf("line %d: %s", le.Line, le.Reason))
+	}
+	return strings.Join(reasons, "\n")
+}
+
+// ErrorLineProtocol returns the lines rejected by the write which failed with err, if any.
+func ErrorLineProtocol(err error) *LineProtocolError {
+	switch e := err.(type) {
+	case *LineProtocolError:
+		return e
+	case *Error:
+		if e != nil && e.Err != nil {
+			return ErrorLineProtocol(e.Err)
+		}
+	}
+	return nil
+}
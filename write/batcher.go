@@ -98,6 +98,32 @@ func (b *Batcher) write(ctx context.Context, org, bucket platform.ID, lines <-ch
 	buf := make([]byte, 0, maxBytes)
 	r := bytes.NewReader(buf)
 
+	// rejected collects the lines rejected by the service across all batches so that
+	// the valid lines of the remaining batches are still written.
+	var (
+		rejected []platform.LineError
+		written  bool
+		lines0   int // lines0 is the number of lines sent in previous batches.
+	)
+	flush := func() error {
+		r.Reset(buf)
+		timer.Reset(flushInterval)
+		err := b.Service.Write(ctx, org, bucket, r)
+		if lpErr := platform.ErrorLineProtocol(err); lpErr != nil {
+			for _, le := range lpErr.Errors {
+				le.Line += lines0
+				rejected = append(rejected, le)
+			}
+			written = written || platform.ErrorCode(err) == platform.EUnprocessableEntity
+			err = nil
+		} else if err == nil {
+			written = true
+		}
+		lines0 += countLines(buf)
+		buf = buf[:0]
+		return err
+	}
+
 	var line []byte
 	var more = true
 	// if read closes the channel normally, exit the loop
@@ -109,23 +135,17 @@ func (b *Batcher) write(ctx context.Context, org, bucket platform.ID, lines <-ch
 			}
 			// write if we exceed the max lines OR read routine has finished
 			if len(buf) >= maxBytes || (!more && len(buf) > 0) {
-				r.Reset(buf)
-				timer.Reset(flushInterval)
-				if err := b.Service.Write(ctx, org, bucket, r); err != nil {
+				if err := flush(); err != nil {
 					errC <- err
 					return
 				}
-				buf = buf[:0]
 			}
 		case <-timer.C:
 			if len(buf) > 0 {
-				r.Reset(buf)
-				timer.Reset(flushInterval)
-				if err := b.Service.Write(ctx, org, bucket, r); err != nil {
+				if err := flush(); err != nil {
 					errC <- err
 					return
 				}
-				buf = buf[:0]
 			}
 		case <-ctx.Done():
 			errC <- ctx.Err()
@@ -133,9 +153,23 @@ func (b *Batcher) write(ctx context.Context, org, bucket platform.ID, lines <-ch
 		}
 	}
 
+	if len(rejected) > 0 {
+		errC <- platform.NewLineProtocolError("write/Batcher", rejected, written)
+		return
+	}
+
 	errC <- nil
 }
 
+// countLines returns the number of lines of line protocol in buf.
+func countLines(buf []byte) int {
+	n := bytes.Count(buf, []byte{'\n'})
+	if len(buf) > 0 && buf[len(buf)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
 // ScanLines is used in bufio.Scanner.Split to split lines of line protocol.
 func ScanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	if atEOF && len(data) == 0 {
@@ -397,6 +397,57 @@ func TestBatcher_Write(t *testing.T) {
 	}
 }
 
+func TestBatcher_WriteLineProtocolError(t *testing.T) {
+	// The service rejects any line containing "bad", reporting line numbers
+	// relative to the batch it received.
+	svc := &mock.WriteService{
+		WriteF: func(ctx context.Context, org, bucket platform.ID, r io.Reader) error {
+			b, err := ioutil.ReadAll(r)
+			if err != nil {
+				return err
+			}
+
+			var errs []platform.LineError
+			lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+			for i, line := range lines {
+				if strings.Contains(line, "bad") {
+					errs = append(errs, platform.LineError{Line: i + 1, Text: line, Reason: "bad line"})
+				}
+			}
+			if len(errs) > 0 {
+				return platform.NewLineProtocolError("mock", errs, len(errs) < len(lines))
+			}
+			return nil
+		},
+	}
+
+	b := &Batcher{
+		MaxFlushBytes: len([]byte("m1,t1=v1 f1=1\nm2,t2=v2 f2=2\n")),
+		Service:       svc,
+	}
+
+	r := strings.NewReader("m1,t1=v1 f1=1\nm2,t2=v2 f2=2\nbad\nm4,t4=v4 f4=4\nbad\nbad\n")
+	err := b.Write(context.Background(), platform.ID(1), platform.ID(2), r)
+
+	lpErr := platform.ErrorLineProtocol(err)
+	if lpErr == nil {
+		t.Fatalf("Batcher.Write() expected a *LineProtocolError, got %T: %v", err, err)
+	}
+
+	if platform.ErrorCode(err) != platform.EUnprocessableEntity {
+		t.Errorf("Batcher.Write() expected a partial write")
+	}
+
+	want := []platform.LineError{
+		{Line: 3, Text: "bad", Reason: "bad line"},
+		{Line: 5, Text: "bad", Reason: "bad line"},
+		{Line: 6, Text: "bad", Reason: "bad line"},
+	}
+	if !cmp.Equal(lpErr.Errors, want) {
+		t.Errorf("Batcher.Write() rejected lines -got/+want %s", cmp.Diff(lpErr.Errors, want))
+	}
+}
+
 func TestBatcher_WriteTimeout(t *testing.T) {
 	// mocking the write service here to either return an error
 	// or get back all the bytes from the reader.
@@ -0,0 +1,62 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestNewLineProtocolError(t *testing.T) {
+	errs := []platform.LineError{
+		{Line: 2, Text: "m,t1=v2", Reason: "missing fields"},
+		{Line: 4, Text: "m f1=", Reason: "missing field value"},
+	}
+
+	tests := []struct {
+		name    string
+		partial bool
+		code    string
+		msg     string
+	}{
+		{
+			name: "every line rejected",
+			code: platform.EInvalid,
+			msg:  "no points written: 2 lines rejected",
+		},
+		{
+			name:    "partial write",
+			partial: true,
+			code:    platform.EUnprocessableEntity,
+			msg:     "partial write: 2 lines rejected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The error is wrapped as it is by the services returning it.
+			err := error(&platform.Error{
+				Op:  "wrapper",
+				Err: platform.NewLineProtocolError("write", errs, tt.partial),
+			})
+
+			if got := platform.ErrorCode(err); got != tt.code {
+				t.Errorf("ErrorCode() = %q, want %q", got, tt.code)
+			}
+			if got := platform.ErrorMessage(err); got != tt.msg {
+				t.Errorf("ErrorMessage() = %q, want %q", got, tt.msg)
+			}
+
+			lpErr := platform.ErrorLineProtocol(err)
+			if lpErr == nil {
+				t.Fatal("ErrorLineProtocol() returned no rejected lines")
+			}
+			if lpErr.Line != 2 {
+				t.Errorf("first rejected line = %d, want 2", lpErr.Line)
+			}
+			if !cmp.Equal(lpErr.Errors, errs) {
+				t.Errorf("rejected lines -got/+want %s", cmp.Diff(lpErr.Errors, errs))
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package zap
 
 import (
 	"context"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"go.uber.org/zap"
@@ -80,3 +81,25 @@ func (s *AuthorizationService) SetAuthorizationStatus(ctx context.Context, id pl
 
 	return s.AuthorizationService.SetAuthorizationStatus(ctx, id, status)
 }
+
+// RotateAuthorization replaces the token of an authorization with a new one, and logs any errors.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (a *platform.Authorization, err error) {
+	defer func() {
+		if err != nil {
+			s.Logger.Info("error rotating authorization", zap.Error(err))
+		}
+	}()
+
+	return s.AuthorizationService.RotateAuthorization(ctx, id, gracePeriod)
+}
+
+// SetAuthorizationLastUsed records the time an authorization's token was last used at, and logs any errors.
+func (s *AuthorizationService) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) (err error) {
+	defer func() {
+		if err != nil {
+			s.Logger.Info("error updating authorization last used time", zap.Error(err))
+		}
+	}()
+
+	return s.AuthorizationService.SetAuthorizationLastUsed(ctx, id, t)
+}